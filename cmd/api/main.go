@@ -2,57 +2,113 @@ package main
 
 import (
 	"context"
-	"log"
+	"flag"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	commentDelivery "github.com/martinmanurung/cinestream/internal/domain/comments/delivery"
+	commentRepository "github.com/martinmanurung/cinestream/internal/domain/comments/repository"
+	commentUsecase "github.com/martinmanurung/cinestream/internal/domain/comments/usecase"
+	editorialDelivery "github.com/martinmanurung/cinestream/internal/domain/editorial/delivery"
+	editorialRepository "github.com/martinmanurung/cinestream/internal/domain/editorial/repository"
+	editorialUsecase "github.com/martinmanurung/cinestream/internal/domain/editorial/usecase"
+	grantDelivery "github.com/martinmanurung/cinestream/internal/domain/grants/delivery"
+	grantRepository "github.com/martinmanurung/cinestream/internal/domain/grants/repository"
+	grantUsecase "github.com/martinmanurung/cinestream/internal/domain/grants/usecase"
+	invoiceDelivery "github.com/martinmanurung/cinestream/internal/domain/invoices/delivery"
+	invoiceRepository "github.com/martinmanurung/cinestream/internal/domain/invoices/repository"
+	invoiceUsecase "github.com/martinmanurung/cinestream/internal/domain/invoices/usecase"
 	movieDelivery "github.com/martinmanurung/cinestream/internal/domain/movies/delivery"
 	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
 	movieUsecase "github.com/martinmanurung/cinestream/internal/domain/movies/usecase"
+	opsDelivery "github.com/martinmanurung/cinestream/internal/domain/ops/delivery"
+	opsUsecase "github.com/martinmanurung/cinestream/internal/domain/ops/usecase"
 	orderDelivery "github.com/martinmanurung/cinestream/internal/domain/orders/delivery"
 	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
 	orderUsecase "github.com/martinmanurung/cinestream/internal/domain/orders/usecase"
+	partnerDelivery "github.com/martinmanurung/cinestream/internal/domain/partners/delivery"
+	partnerRepository "github.com/martinmanurung/cinestream/internal/domain/partners/repository"
+	partnerUsecase "github.com/martinmanurung/cinestream/internal/domain/partners/usecase"
+	reportDelivery "github.com/martinmanurung/cinestream/internal/domain/reports/delivery"
+	reportRepository "github.com/martinmanurung/cinestream/internal/domain/reports/repository"
+	reportUsecase "github.com/martinmanurung/cinestream/internal/domain/reports/usecase"
+	rightsholders "github.com/martinmanurung/cinestream/internal/domain/rightsholders"
+	rightsHolderDelivery "github.com/martinmanurung/cinestream/internal/domain/rightsholders/delivery"
+	rightsHolderRepository "github.com/martinmanurung/cinestream/internal/domain/rightsholders/repository"
+	rightsHolderUsecase "github.com/martinmanurung/cinestream/internal/domain/rightsholders/usecase"
+	supportDelivery "github.com/martinmanurung/cinestream/internal/domain/support/delivery"
+	supportRepository "github.com/martinmanurung/cinestream/internal/domain/support/repository"
+	supportUsecase "github.com/martinmanurung/cinestream/internal/domain/support/usecase"
 	"github.com/martinmanurung/cinestream/internal/domain/users/delivery"
 	"github.com/martinmanurung/cinestream/internal/domain/users/repository"
 	"github.com/martinmanurung/cinestream/internal/domain/users/usecase"
+	watchlistDelivery "github.com/martinmanurung/cinestream/internal/domain/watchlist/delivery"
+	watchlistRepository "github.com/martinmanurung/cinestream/internal/domain/watchlist/repository"
+	watchlistUsecase "github.com/martinmanurung/cinestream/internal/domain/watchlist/usecase"
+	watchpartyDelivery "github.com/martinmanurung/cinestream/internal/domain/watchparty/delivery"
+	watchpartyRepository "github.com/martinmanurung/cinestream/internal/domain/watchparty/repository"
+	watchpartyUsecase "github.com/martinmanurung/cinestream/internal/domain/watchparty/usecase"
+	"github.com/martinmanurung/cinestream/internal/platform/apidocs"
+	"github.com/martinmanurung/cinestream/internal/platform/backup"
+	"github.com/martinmanurung/cinestream/internal/platform/cache"
+	"github.com/martinmanurung/cinestream/internal/platform/captcha"
 	"github.com/martinmanurung/cinestream/internal/platform/config"
 	"github.com/martinmanurung/cinestream/internal/platform/database"
+	"github.com/martinmanurung/cinestream/internal/platform/health"
+	"github.com/martinmanurung/cinestream/internal/platform/logging"
+	"github.com/martinmanurung/cinestream/internal/platform/loginguard"
+	"github.com/martinmanurung/cinestream/internal/platform/mailer"
+	"github.com/martinmanurung/cinestream/internal/platform/metrics"
+	"github.com/martinmanurung/cinestream/internal/platform/moderation"
+	notification "github.com/martinmanurung/cinestream/internal/platform/notification"
 	"github.com/martinmanurung/cinestream/internal/platform/payment"
+	"github.com/martinmanurung/cinestream/internal/platform/pwned"
 	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/internal/platform/retention"
 	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/internal/platform/throttle"
+	"github.com/martinmanurung/cinestream/internal/platform/tmdb"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/martinmanurung/cinestream/internal/platform/usage"
+	"github.com/martinmanurung/cinestream/internal/platform/views"
 	"github.com/martinmanurung/cinestream/pkg/jwt"
 	"github.com/martinmanurung/cinestream/pkg/middleware"
+	"github.com/martinmanurung/cinestream/pkg/signedurl"
 	customValidator "github.com/martinmanurung/cinestream/pkg/validator"
 	"github.com/redis/go-redis/v9"
-	"github.com/rs/zerolog"
 	zlog "github.com/rs/zerolog/log"
 )
 
 func main() {
-	// Setup zerolog
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	zlog.Logger = zlog.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
-
-	zlog.Info().Msg("Starting CineStream API Server...")
-
-	// Load configuration
-	cfg, err := config.LoadConfig()
+	// Load configuration, from the file and CINESTREAM_* environment variables
+	configPath := flag.String("config", "", "path of the YAML config file, defaults to $"+config.PathEnv+" or ./app-config.yaml")
+	check := flag.Bool("check", false, "check the config and every dependency, print a pass/fail report and exit")
+	flag.Parse()
+	if *check {
+		os.Exit(runSelfTest(*configPath))
+	}
+	cfg, err := config.LoadConfig(*configPath, "api")
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to load config")
 	}
 
+	// Log level and format are shared with the worker
+	logging.Setup(cfg.Log, "api")
+	zlog.Info().Str("config_file", cfg.File).Msg("Starting CineStream API Server...")
+
 	// Initialize database
 	db, err := database.InitMySQL(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to initialize database")
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Failed to get database instance: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to get database instance")
 	}
 	defer sqlDB.Close()
 
@@ -61,7 +117,7 @@ func main() {
 	// Initialize MinIO
 	minioClient, err := storage.InitMinIO(cfg.MinIO)
 	if err != nil {
-		log.Fatalf("Failed to initialize MinIO: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to initialize MinIO")
 	}
 	zlog.Info().Msg("MinIO initialized successfully")
 
@@ -75,19 +131,39 @@ func main() {
 
 	// Ping Redis to verify connection
 	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
 	defer redisClient.Close()
 	zlog.Info().Msg("Redis initialized successfully")
 
+	// Profile sets from the config are accepted by the admin API
+	if err := transcoding.ConfigureProfileSets(cfg.Transcoding); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to load transcoding profiles")
+	}
+
 	// Initialize services
-	storageService := storage.NewStorageService(minioClient, cfg.MinIO.BucketRaw, cfg.MinIO.BucketProcessed)
+	storageService := storage.NewStorageService(minioClient, cfg.MinIO.BucketRaw, cfg.MinIO.BucketProcessed, cfg.MinIO.BucketMedia)
 	queueService := queue.NewRedisQueue(redisClient)
+	if err := queueService.SetRoutes(cfg.Queue.Routes); err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid queue routes")
+	}
+	opsMetrics := metrics.NewRecorder(redisClient)
+	partnerUsage := usage.NewMeter(redisClient)
 
 	// Initialize Echo
 	e := echo.New()
+	// Records the routes with their required roles for the route listing, before any route is added
+	routeCatalog := apidocs.NewCatalog(e)
 	e.Use(middleware.RequestID())
+	trustedProxies := trustedProxyRanges(cfg.Server.TrustedProxies)
+	e.Use(middleware.Region(cfg.Licensing.RegionHeader, cfg.Licensing.DefaultRegion, trustedProxies))
+	// Maintenance mode leaves staff sign-in, the admin API, webhooks and the probes working
+	maintenance := middleware.NewMaintenance()
+	applyMaintenance(maintenance, cfg.Maintenance)
+	e.Use(maintenance.Middleware("/api/v1/admin", "/api/v1/users/login", "/api/v1/users/refresh", "/api/v1/webhooks", "/health", "/ready"))
 	e.HideBanner = false
+	// Login throttling and screener view logs rely on the client address, clients must not be able to pick it
+	e.IPExtractor = clientIPExtractor(trustedProxies)
 
 	// Register validator
 	e.Validator = customValidator.New()
@@ -98,34 +174,250 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUser(db)
 	movieRepo := movieRepository.NewMovieRepository(db)
+	if cfg.Cache.Enabled {
+		catalogTTL, err := time.ParseDuration(cfg.Cache.CatalogTTL)
+		if err != nil || catalogTTL <= 0 {
+			catalogTTL = 5 * time.Minute
+		}
+		movieRepo.WithCatalogCache(cache.New(redisClient, "catalog", catalogTTL))
+	}
 	orderRepo := orderRepository.NewOrderRepository(db)
+	watchlistRepo := watchlistRepository.NewWatchlistRepository(db)
+	watchPartyRepo := watchpartyRepository.NewWatchPartyRepository(db)
+	commentRepo := commentRepository.NewCommentRepository(db)
+	editorialRepo := editorialRepository.NewEditorialRepository(db)
+	reportRepo := reportRepository.NewReportRepository(db)
+	supportRepo := supportRepository.NewSupportRepository(db)
+	grantRepo := grantRepository.NewGrantRepository(db)
+	invoiceRepo := invoiceRepository.NewInvoiceRepository(db)
+	partnerRepo := partnerRepository.NewPartnerRepository(db)
+	rightsHolderRepo := rightsHolderRepository.NewRightsHolderRepository(db)
 
 	// Create adapters for order usecase
 	movieRepoAdapter := orderRepository.NewMovieRepositoryAdapter(movieRepo)
 	userRepoAdapter := orderRepository.NewUserRepositoryAdapter(userRepo)
 
-	// Initialize payment service
-	paymentService := payment.NewMidtransService(
-		cfg.PaymentGW.ServerKey,
-		cfg.PaymentGW.ClientKey,
-		cfg.PaymentGW.IsProduction,
-	)
+	// Initialize payment providers
+	payments, err := payment.NewRegistryFromConfig(cfg.PaymentGW)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to initialize payment gateway")
+	}
+
+	// Initialize notification mailer (logs only when SMTP is not configured)
+	mailService := mailer.NewMailer(cfg.Mail.Host, cfg.Mail.Port, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.From)
+	// Templated notifications go through the notifications queue, the worker renders and sends them
+	notifier := notification.NewNotifier(queueService)
+
+	// Initialize media (poster/trailer) serving options
+	mediaPresignExpiry, err := time.ParseDuration(cfg.Media.PresignExpiry)
+	if err != nil {
+		mediaPresignExpiry = 15 * time.Minute
+	}
+	mediaSigningKey := cfg.Media.SigningKey
+	if mediaSigningKey == "" {
+		mediaSigningKey = cfg.JWT.SecretKey
+	}
+	mediaOptions := movieUsecase.MediaOptions{
+		PublicMode:    cfg.Media.PublicMode,
+		PrivateMode:   cfg.Media.PrivateMode,
+		PresignExpiry: mediaPresignExpiry,
+		ProxyBaseURL:  cfg.Media.ProxyBaseURL,
+		Signer:        signedurl.NewSigner(mediaSigningKey),
+	}
+
+	// Initialize password reset links
+	passwordResetExpiry, err := time.ParseDuration(cfg.Mail.PasswordResetExpiry)
+	if err != nil {
+		passwordResetExpiry = time.Hour
+	}
+	passwordResetOptions := usecase.PasswordResetOptions{
+		URL:         cfg.Mail.PasswordResetURL,
+		TokenExpiry: passwordResetExpiry,
+	}
+
+	// Initialize email change confirmation links
+	emailChangeExpiry, err := time.ParseDuration(cfg.Mail.EmailChangeExpiry)
+	if err != nil {
+		emailChangeExpiry = 24 * time.Hour
+	}
+	emailChangeOptions := usecase.EmailChangeOptions{
+		URL:         cfg.Mail.EmailChangeURL,
+		TokenExpiry: emailChangeExpiry,
+	}
+
+	// Initialize login lockout
+	loginGuard := loginguard.NewGuard(redisClient, loginGuardOptions(cfg.Login))
+
+	// Initialize the login CAPTCHA, disabled without a provider
+	var captchaVerifier usecase.CaptchaVerifier
+	captchaClient, err := captcha.New(cfg.Login.Captcha)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to initialize captcha")
+	}
+	if captchaClient != nil {
+		captchaVerifier = captchaClient
+	}
+
+	// Initialize concurrent stream limit
+	sessionIdleTimeout, err := time.ParseDuration(cfg.Streaming.SessionIdleTimeout)
+	if err != nil {
+		sessionIdleTimeout = 5 * time.Minute
+	}
+	streamSessionOptions := orderUsecase.StreamSessionOptions{
+		MaxConcurrent:     cfg.Streaming.MaxConcurrentStreams,
+		IdleTimeout:       sessionIdleTimeout,
+		RentalDevices:     cfg.Streaming.RentalDevices,
+		PlanRentalDevices: cfg.Streaming.PlanRentalDevices, // viper lowercases the keys
+	}
+
+	// Initialize checkout link expiry, movies can override it
+	paymentExpiry, err := time.ParseDuration(cfg.Orders.PaymentExpiry)
+	if err != nil {
+		paymentExpiry = 24 * time.Hour
+	}
+
+	// Initialize licensing, orders close this long before a window ends
+	licenseOrderCutoff, err := time.ParseDuration(cfg.Licensing.OrderCutoff)
+	if err != nil || licenseOrderCutoff < 0 {
+		licenseOrderCutoff = 72 * time.Hour
+	}
+
+	// Initialize TMDB metadata enrichment, disabled without an API token
+	var metadataProvider movieUsecase.MetadataProvider
+	if tmdbClient := tmdb.New(cfg.TMDB); tmdbClient != nil {
+		metadataProvider = tmdbClient
+	}
+
+	// Initialize content moderation of posters and comments, disabled unless configured
+	moderator, err := moderation.New(cfg.Moderation)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to initialize content moderation")
+	}
+
+	// Initialize the breached password check, disabled unless configured
+	var breachChecker usecase.BreachChecker
+	if pwnedClient := pwned.New(cfg.Passwords); pwnedClient != nil {
+		breachChecker = pwnedClient
+	}
+
+	// Initialize playback counting for the popular movies, the worker flushes the counters to MySQL
+	viewCounter := views.NewCounter(redisClient)
+
+	// Initialize refresh token lifetimes, absolute from login and sliding from the last use
+	refreshTokenOptions := usecase.RefreshTokenOptions{}
+	if refreshTokenOptions.Expiry, err = time.ParseDuration(cfg.JWT.RefreshTokenExpiry); err != nil || refreshTokenOptions.Expiry <= 0 {
+		refreshTokenOptions.Expiry = 90 * 24 * time.Hour
+	}
+	if refreshTokenOptions.IdleExpiry, err = time.ParseDuration(cfg.JWT.RefreshTokenIdleExpiry); err != nil || refreshTokenOptions.IdleExpiry <= 0 {
+		refreshTokenOptions.IdleExpiry = 30 * 24 * time.Hour
+	}
 
 	// Initialize use cases
-	userUsecase := usecase.NewUsecase(userRepo, jwtService)
-	movieUsecaseInstance := movieUsecase.NewMovieUsecase(movieRepo, storageService, queueService)
-	orderUsecaseInstance := orderUsecase.NewOrderUsecase(orderRepo, movieRepoAdapter, userRepoAdapter, paymentService)
+	userUsecase := usecase.NewUsecase(userRepo, jwtService, mailService, notifier, passwordResetOptions, emailChangeOptions, loginGuard, breachChecker, refreshTokenOptions, captchaVerifier)
+	movieUsecaseInstance := movieUsecase.NewMovieUsecase(movieRepo, storageService, queueService, mediaOptions, metadataProvider, moderator, cfg.SEO.SiteURL)
+	orderUsecaseInstance := orderUsecase.NewOrderUsecase(orderRepo, movieRepoAdapter, userRepoAdapter, payments, streamSessionOptions, paymentExpiry, licenseOrderCutoff, viewCounter)
+	watchlistUsecaseInstance := watchlistUsecase.NewWatchlistUsecase(watchlistRepo)
+	watchPartyUsecaseInstance := watchpartyUsecase.NewWatchPartyUsecase(watchPartyRepo)
+	commentUsecaseInstance := commentUsecase.NewCommentUsecase(commentRepo, moderator)
+	editorialUsecaseInstance := editorialUsecase.NewEditorialUsecase(editorialRepo)
+	reportUsecaseInstance := reportUsecase.NewReportUsecase(reportRepo)
+	supportUsecaseInstance := supportUsecase.NewSupportUsecase(supportRepo, mailService, cfg.Mail.SupportInbox)
+	invoiceUsecaseInstance := invoiceUsecase.NewInvoiceUsecase(invoiceRepo, storageService, notifier, invoiceUsecase.InvoiceOptions{
+		SellerName:    cfg.Invoices.SellerName,
+		SellerAddress: cfg.Invoices.SellerAddress,
+		Currency:      cfg.Invoices.Currency,
+		Email:         cfg.Invoices.Email,
+	})
+	// Invited accounts choose their password with the token returned on redemption, as with a reset link
+	grantUsecaseInstance := grantUsecase.NewGrantUsecase(grantRepo, mailService, grantUsecase.GrantOptions{
+		RedeemURL:           cfg.Mail.AccessGrantURL,
+		PasswordSetupExpiry: passwordResetExpiry,
+	})
+	opsUsecaseInstance := opsUsecase.NewOpsUsecase(opsMetrics, retention.NewStore(db), queueService, backup.NewStore(db))
+	partnerUsecaseInstance := partnerUsecase.NewPartnerUsecase(partnerRepo, partnerUsage)
+	// Royalty statements deduct what the payment provider of each order kept before sharing the revenue
+	paymentFees := make(map[string]rightsholders.PaymentFee, len(cfg.Royalties.Fees))
+	for provider, fee := range cfg.Royalties.Fees {
+		paymentFees[provider] = rightsholders.PaymentFee{Percent: fee.Percent, Fixed: fee.Fixed}
+	}
+	rightsHolderUsecaseInstance := rightsHolderUsecase.NewRightsHolderUsecase(rightsHolderRepo, rightsHolderUsecase.RoyaltyOptions{
+		Currency: cfg.Invoices.Currency,
+		Fees:     paymentFees,
+	})
 
 	// Initialize handlers
 	userHandler := delivery.NewHandler(ctx, userUsecase)
 	movieHandler := movieDelivery.NewMovieHandler(ctx, movieUsecaseInstance)
 	genreHandler := movieDelivery.NewGenreHandler(ctx, movieUsecaseInstance)
+	personHandler := movieDelivery.NewPersonHandler(ctx, movieUsecaseInstance)
+	uploadHandler := movieDelivery.NewUploadHandler(ctx, movieUsecaseInstance)
+	mediaHandler := movieDelivery.NewMediaHandler(ctx, movieUsecaseInstance)
+	subtitleHandler := movieDelivery.NewSubtitleHandler(ctx, movieUsecaseInstance)
+	audioHandler := movieDelivery.NewAudioHandler(ctx, movieUsecaseInstance)
+	licensingHandler := movieDelivery.NewLicensingHandler(ctx, movieUsecaseInstance)
+	metadataHandler := movieDelivery.NewMetadataHandler(ctx, movieUsecaseInstance)
+	publishingHandler := movieDelivery.NewPublishingHandler(ctx, movieUsecaseInstance)
+	queueHandler := movieDelivery.NewQueueHandler(ctx, movieUsecaseInstance)
+	popularHandler := movieDelivery.NewPopularHandler(ctx, movieUsecaseInstance)
+	recommendationHandler := movieDelivery.NewRecommendationHandler(ctx, movieUsecaseInstance)
+	seoHandler := movieDelivery.NewSEOHandler(ctx, movieUsecaseInstance)
 	orderHandler := orderDelivery.NewOrderHandler(ctx, orderUsecaseInstance)
-	webhookHandler := orderDelivery.NewWebhookHandler(ctx, orderRepo, paymentService, cfg.PaymentGW.ServerKey)
-	streamingHandler := orderDelivery.NewStreamingHandler(ctx, orderUsecaseInstance)
+	webhookHandler := orderDelivery.NewWebhookHandler(ctx, orderRepo, payments, opsMetrics).WithInvoices(invoiceUsecaseInstance)
+	opsHandler := opsDelivery.NewOpsHandler(ctx, opsUsecaseInstance)
+	partnerHandler := partnerDelivery.NewPartnerHandler(ctx, partnerUsecaseInstance)
+	rightsHolderHandler := rightsHolderDelivery.NewRightsHolderHandler(ctx, rightsHolderUsecaseInstance)
+	var streamLimiter *throttle.Limiter
+	if cfg.Streaming.ThrottleEnabled {
+		streamLimiter = throttle.NewLimiter(cfg.Streaming.PlanRatesKBps, cfg.Streaming.DefaultRateKBps)
+	}
+	streamingHandler := orderDelivery.NewStreamingHandler(ctx, orderUsecaseInstance, storageService, streamLimiter)
+	offlineHandler := orderDelivery.NewOfflineHandler(ctx, orderUsecaseInstance)
+	watchlistHandler := watchlistDelivery.NewWatchlistHandler(ctx, watchlistUsecaseInstance)
+	watchPartyHandler := watchpartyDelivery.NewWatchPartyHandler(ctx, watchPartyUsecaseInstance)
+	commentHandler := commentDelivery.NewCommentHandler(ctx, commentUsecaseInstance)
+	editorialHandler := editorialDelivery.NewEditorialHandler(ctx, editorialUsecaseInstance)
+	reportHandler := reportDelivery.NewReportHandler(ctx, reportUsecaseInstance)
+	supportHandler := supportDelivery.NewSupportHandler(ctx, supportUsecaseInstance)
+	grantHandler := grantDelivery.NewGrantHandler(ctx, grantUsecaseInstance)
+	invoiceHandler := invoiceDelivery.NewInvoiceHandler(ctx, invoiceUsecaseInstance)
+
+	// Public catalog responses carry ETag/Cache-Control headers for browsers and the CDN
+	publicMaxAge, err := time.ParseDuration(cfg.Cache.PublicMaxAge)
+	if err != nil || publicMaxAge < 0 {
+		publicMaxAge = time.Minute
+	}
+	httpCache := middleware.HTTPCache(publicMaxAge)
+
+	// Shutdown fails the readiness probe first, then waits for in-flight requests such as large uploads
+	drain := middleware.NewDrain()
+	drainDelay, err := time.ParseDuration(cfg.Server.DrainDelay)
+	if err != nil || drainDelay < 0 {
+		drainDelay = 5 * time.Second
+	}
+	shutdownTimeout, err := time.ParseDuration(cfg.Server.ShutdownTimeout)
+	if err != nil || shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Minute
+	}
+
+	// Readiness pings every dependency the API needs to serve requests
+	healthTimeout, err := time.ParseDuration(cfg.Health.Timeout)
+	if err != nil || healthTimeout <= 0 {
+		healthTimeout = 2 * time.Second
+	}
+	healthChecker := health.NewChecker(healthTimeout)
+	healthChecker.Add("mysql", health.MySQL(sqlDB))
+	healthChecker.Add("redis", health.Redis(redisClient))
+	healthChecker.Add("minio", health.MinIO(minioClient, cfg.MinIO.BucketProcessed))
+
+	if cfg.Server.Development() {
+		zlog.Warn().Msg("Development environment, admins can simulate payments")
+	}
 
 	// Setup routes
-	setupRoutes(e, userHandler, movieHandler, genreHandler, orderHandler, webhookHandler, streamingHandler, jwtService)
+	setupRoutes(e, userHandler, movieHandler, genreHandler, personHandler, uploadHandler, mediaHandler, subtitleHandler, audioHandler, licensingHandler, metadataHandler, publishingHandler, queueHandler, popularHandler, recommendationHandler, seoHandler, orderHandler, webhookHandler, streamingHandler, offlineHandler, watchlistHandler, watchPartyHandler, commentHandler, editorialHandler, reportHandler, supportHandler, grantHandler, invoiceHandler, opsHandler, partnerHandler, rightsHolderHandler, partnerUsecaseInstance, opsMetrics, jwtService, drain, httpCache, routeCatalog, healthChecker, cfg.Server.Development())
+
+	// Operational settings apply without a restart when the config file changes
+	watchConfig(cfg, loginGuard, maintenance)
 
 	// Start server in goroutine
 	go func() {
@@ -147,13 +439,45 @@ func main() {
 
 	zlog.Info().Msg("Shutting down server...")
 
-	// Gracefully shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Keep accepting requests until the load balancer noticed the failing readiness probe
+	drain.Start()
+	time.Sleep(drainDelay)
+
+	// Gracefully shutdown with timeout, in-flight uploads are allowed to complete
+	zlog.Info().Int64("in_flight", drain.InFlight()).Dur("timeout", shutdownTimeout).Msg("Waiting for in-flight requests")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := e.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		zlog.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
 
 	zlog.Info().Msg("Server exited successfully")
 }
+
+// clientIPExtractor takes the client address from X-Forwarded-For only when the request came through
+// one of the trusted proxies, otherwise it is the address of the connection
+func clientIPExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, ipRange := range trustedProxies {
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// trustedProxyRanges parses the CIDR ranges of the load balancers in front of the API
+func trustedProxyRanges(cidrs []string) []*net.IPNet {
+	ranges := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			zlog.Fatal().Err(err).Str("cidr", cidr).Msg("Invalid trusted proxy range")
+		}
+		ranges = append(ranges, ipRange)
+	}
+	return ranges
+}
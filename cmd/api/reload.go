@@ -0,0 +1,46 @@
+package main
+
+import (
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+	"github.com/martinmanurung/cinestream/internal/platform/logging"
+	"github.com/martinmanurung/cinestream/internal/platform/loginguard"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/martinmanurung/cinestream/pkg/middleware"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// watchConfig applies the operational settings of a changed config file without a restart:
+// the log level, the login limits, maintenance mode and the transcoding ladders the admin API accepts
+func watchConfig(cfg *config.Config, loginGuard *loginguard.Guard, maintenance *middleware.Maintenance) {
+	config.Watch(cfg, "api", func(previous, current *config.Config) {
+		logging.SetLevel(current.Log)
+		loginGuard.SetOptions(loginGuardOptions(current.Login))
+		if current.Maintenance.Enabled != previous.Maintenance.Enabled {
+			zlog.Warn().Bool("enabled", current.Maintenance.Enabled).Msg("Maintenance mode switched")
+		}
+		applyMaintenance(maintenance, current.Maintenance)
+		if err := transcoding.ConfigureProfileSets(current.Transcoding); err != nil {
+			zlog.Error().Err(err).Msg("Invalid transcoding profiles, keeping the previous ones")
+		}
+	})
+}
+
+// loginGuardOptions returns the login lockout settings, durations fall back to the guard defaults
+func loginGuardOptions(cfg config.LoginConfig) loginguard.Options {
+	options := loginguard.Options{
+		MaxAttempts:   cfg.MaxAttempts,
+		IPMaxAttempts: cfg.IPMaxAttempts,
+		CaptchaAfter:  cfg.CaptchaAfter,
+	}
+	options.Window, _ = time.ParseDuration(cfg.Window)
+	options.Lockout, _ = time.ParseDuration(cfg.Lockout)
+	options.MaxLockout, _ = time.ParseDuration(cfg.MaxLockout)
+	return options
+}
+
+func applyMaintenance(maintenance *middleware.Maintenance, cfg config.MaintenanceConfig) {
+	retryAfter, _ := time.ParseDuration(cfg.RetryAfter)
+	maintenance.Set(cfg.Enabled, cfg.Message, retryAfter)
+}
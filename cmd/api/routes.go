@@ -1,37 +1,82 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	commentDelivery "github.com/martinmanurung/cinestream/internal/domain/comments/delivery"
+	editorialDelivery "github.com/martinmanurung/cinestream/internal/domain/editorial/delivery"
+	grantDelivery "github.com/martinmanurung/cinestream/internal/domain/grants/delivery"
+	invoiceDelivery "github.com/martinmanurung/cinestream/internal/domain/invoices/delivery"
 	movieDelivery "github.com/martinmanurung/cinestream/internal/domain/movies/delivery"
+	opsDelivery "github.com/martinmanurung/cinestream/internal/domain/ops/delivery"
 	orderDelivery "github.com/martinmanurung/cinestream/internal/domain/orders/delivery"
+	partnerDelivery "github.com/martinmanurung/cinestream/internal/domain/partners/delivery"
+	reportDelivery "github.com/martinmanurung/cinestream/internal/domain/reports/delivery"
+	rightsHolderDelivery "github.com/martinmanurung/cinestream/internal/domain/rightsholders/delivery"
+	supportDelivery "github.com/martinmanurung/cinestream/internal/domain/support/delivery"
 	userDelivery "github.com/martinmanurung/cinestream/internal/domain/users/delivery"
+	watchlistDelivery "github.com/martinmanurung/cinestream/internal/domain/watchlist/delivery"
+	watchpartyDelivery "github.com/martinmanurung/cinestream/internal/domain/watchparty/delivery"
+	"github.com/martinmanurung/cinestream/internal/platform/apidocs"
+	"github.com/martinmanurung/cinestream/internal/platform/health"
+	"github.com/martinmanurung/cinestream/internal/platform/metrics"
+	"github.com/martinmanurung/cinestream/pkg/constant"
 	"github.com/martinmanurung/cinestream/pkg/jwt"
 	appMiddleware "github.com/martinmanurung/cinestream/pkg/middleware"
 	"github.com/martinmanurung/cinestream/pkg/response"
 )
 
-func setupRoutes(e *echo.Echo, userHandler *userDelivery.Handler, movieHandler *movieDelivery.MovieHandler, genreHandler *movieDelivery.GenreHandler, orderHandler *orderDelivery.OrderHandler, webhookHandler *orderDelivery.WebhookHandler, streamingHandler *orderDelivery.StreamingHandler, jwtService *jwt.JWTService) {
+func setupRoutes(e *echo.Echo, userHandler *userDelivery.Handler, movieHandler *movieDelivery.MovieHandler, genreHandler *movieDelivery.GenreHandler, personHandler *movieDelivery.PersonHandler, uploadHandler *movieDelivery.UploadHandler, mediaHandler *movieDelivery.MediaHandler, subtitleHandler *movieDelivery.SubtitleHandler, audioHandler *movieDelivery.AudioHandler, licensingHandler *movieDelivery.LicensingHandler, metadataHandler *movieDelivery.MetadataHandler, publishingHandler *movieDelivery.PublishingHandler, queueHandler *movieDelivery.QueueHandler, popularHandler *movieDelivery.PopularHandler, recommendationHandler *movieDelivery.RecommendationHandler, seoHandler *movieDelivery.SEOHandler, orderHandler *orderDelivery.OrderHandler, webhookHandler *orderDelivery.WebhookHandler, streamingHandler *orderDelivery.StreamingHandler, offlineHandler *orderDelivery.OfflineHandler, watchlistHandler *watchlistDelivery.WatchlistHandler, watchPartyHandler *watchpartyDelivery.WatchPartyHandler, commentHandler *commentDelivery.CommentHandler, editorialHandler *editorialDelivery.EditorialHandler, reportHandler *reportDelivery.ReportHandler, supportHandler *supportDelivery.SupportHandler, grantHandler *grantDelivery.GrantHandler, invoiceHandler *invoiceDelivery.InvoiceHandler, opsHandler *opsDelivery.OpsHandler, partnerHandler *partnerDelivery.PartnerHandler, rightsHolderHandler *rightsHolderDelivery.RightsHolderHandler, partnerMeter appMiddleware.PartnerMeter, opsMetrics *metrics.Recorder, jwtService *jwt.JWTService, drain *appMiddleware.Drain, httpCache echo.MiddlewareFunc, routeCatalog *apidocs.Catalog, healthChecker *health.Checker, simulatePayments bool) {
 	// Middleware
 	e.Pre(middleware.RemoveTrailingSlash())
+	e.Use(drain.Middleware())
 	e.Use(middleware.Gzip())
 	e.Use(middleware.CORS())
 	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
+	// Error metrics wrap Recover so recovered panics are counted as 500s
+	e.Use(appMiddleware.ErrorMetrics(opsMetrics, "/api/v1/webhooks"))
+	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			c.Logger().Errorf("[PANIC RECOVER] %v %s", err, stack)
+			opsMetrics.RecordPanic(context.Background(), metrics.PanicEvent{
+				OccurredAt: time.Now().UTC(),
+				Method:     c.Request().Method,
+				Route:      c.Path(),
+				RequestID:  c.Request().Header.Get(echo.HeaderXRequestID),
+				Error:      err.Error(),
+			})
+			return err
+		},
+	}))
 	e.Use(middleware.RequestID())
 
 	// Custom error handler
 	e.HTTPErrorHandler = response.CustomErrorHandler
 
-	// Health check
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{
-			"status": "ok",
-		})
-	})
+	// Liveness probe, only tells that the process serves requests (/health is kept for existing probes)
+	liveness := echo.WrapHandler(http.HandlerFunc(health.Liveness))
+	e.GET("/healthz", liveness)
+	e.GET("/health", liveness)
+
+	// Readiness probe, fails while the instance drains during a rolling deploy or a dependency is down
+	readiness := echo.WrapHandler(http.HandlerFunc(healthChecker.Readiness))
+	e.GET("/readyz", readiness, drain.ReadyGate)
+	e.GET("/ready", readiness, drain.ReadyGate)
 
 	// API v1 routes
 	v1 := e.Group("/api/v1")
+	// Requests with an X-API-Key are partner traffic, metered per key with monthly quotas
+	v1.Use(appMiddleware.PartnerMetering(partnerMeter))
+
+	// OpenAPI document of the routes the caller's credentials can call (Public, optional JWT)
+	v1.GET("/openapi.json", routeCatalog.GetOwnOpenAPI, jwtService.OptionalJWTMiddleware()) // GET /api/v1/openapi.json
+
+	// Partner self-service (Protected with X-API-Key)
+	v1.GET("/partner/usage", partnerHandler.GetOwnUsage) // GET /api/v1/partner/usage?month=2025-12
 
 	// User routes
 	users := v1.Group("/users")
@@ -40,67 +85,384 @@ func setupRoutes(e *echo.Echo, userHandler *userDelivery.Handler, movieHandler *
 		users.POST("/login", userHandler.LoginUser)
 		users.POST("/logout", userHandler.Logout)
 		users.POST("/refresh", userHandler.RefreshToken)
+		users.POST("/forgot-password", userHandler.ForgotPassword)   // POST /api/v1/users/forgot-password
+		users.POST("/reset-password", userHandler.ResetPassword)     // POST /api/v1/users/reset-password
+		users.POST("/confirm-email", userHandler.ConfirmEmailChange) // POST /api/v1/users/confirm-email
 
 		// Protected routes (require JWT)
 		users.GET("/me", userHandler.GetMe, jwtService.JWTMiddleware())
+		users.POST("/me/email", userHandler.ChangeEmail, jwtService.JWTMiddleware())                           // POST /api/v1/users/me/email
+		users.GET("/me/preferences", userHandler.GetPreferences, jwtService.JWTMiddleware())                   // GET /api/v1/users/me/preferences
+		users.PUT("/me/preferences", userHandler.UpdatePreferences, jwtService.JWTMiddleware())                // PUT /api/v1/users/me/preferences
+		users.GET("/me/recommendations", recommendationHandler.GetRecommendations, jwtService.JWTMiddleware()) // GET /api/v1/users/me/recommendations?limit=12
+		users.GET("/me/sessions", streamingHandler.GetSessions, jwtService.JWTMiddleware())                    // GET /api/v1/users/me/sessions
+		users.DELETE("/me/sessions", streamingHandler.EndAllSessions, jwtService.JWTMiddleware())              // DELETE /api/v1/users/me/sessions
+		users.DELETE("/me/sessions/:sessionID", streamingHandler.EndSession, jwtService.JWTMiddleware())       // DELETE /api/v1/users/me/sessions/:sessionID
+	}
+
+	// Complimentary access from an emailed campaign link, the token identifies the recipient
+	v1.POST("/access-grants/redeem", grantHandler.RedeemGrant) // POST /api/v1/access-grants/redeem
+
+	// Screener links, the token in the path stands in for an account
+	screeners := v1.Group("/screeners")
+	{
+		screeners.GET("/:token", streamingHandler.GetScreener)                             // GET /api/v1/screeners/:token
+		screeners.POST("/:token/views", streamingHandler.StartScreenerView)                // POST /api/v1/screeners/:token/views
+		screeners.GET("/:token/views/:viewID/hls/*", streamingHandler.ProxyScreenerHLS)    // GET /api/v1/screeners/:token/views/:viewID/hls/*
+		screeners.GET("/:token/views/:viewID/key", streamingHandler.GetScreenerContentKey) // GET /api/v1/screeners/:token/views/:viewID/key
 	}
 
-	// Movie routes (Public)
-	movies := v1.Group("/movies")
+	// Movie routes (Public), revalidated by ETag
+	movies := v1.Group("/movies", httpCache)
 	{
-		movies.GET("", movieHandler.GetMovieList)       // GET /api/v1/movies?page=1&limit=12&genre=action
-		movies.GET("/:id", movieHandler.GetMovieDetail) // GET /api/v1/movies/:id
+		// Optional JWT adds the in_watchlist flag for signed-in users
+		movies.GET("", movieHandler.GetMovieList, jwtService.OptionalJWTMiddleware())                 // GET /api/v1/movies?page=1&limit=12&genre=action&sort=popular
+		movies.GET("/popular", popularHandler.GetPopularMovies, jwtService.OptionalJWTMiddleware())   // GET /api/v1/movies/popular?days=7&limit=10
+		movies.GET("/trending", popularHandler.GetTrendingMovies, jwtService.OptionalJWTMiddleware()) // GET /api/v1/movies/trending?limit=20
+		movies.GET("/:id", movieHandler.GetMovieDetail, jwtService.OptionalJWTMiddleware())           // GET /api/v1/movies/:id
+		movies.GET("/:id/meta", seoHandler.GetMovieMeta, jwtService.OptionalJWTMiddleware())          // GET /api/v1/movies/:id/meta
 	}
 
+	// Sitemap of the watchable movie pages, proxied as /sitemap.xml by the site
+	v1.GET("/sitemap.xml", seoHandler.GetSitemap) // GET /api/v1/sitemap.xml
+
+	// Media proxy for posters/trailers (Public, signed URLs for private movies)
+	v1.GET("/media/movies/:id/:kind", mediaHandler.GetMovieMedia) // GET /api/v1/media/movies/:id/poster
+
 	// Genre routes (Public)
-	genres := v1.Group("/genres")
+	// Public catalog stats for the marketing site
+	v1.GET("/catalog/stats", movieHandler.GetCatalogStats, httpCache) // GET /api/v1/catalog/stats
+
+	genres := v1.Group("/genres", httpCache)
 	{
-		genres.GET("", genreHandler.GetAllGenres) // GET /api/v1/genres
+		genres.GET("", genreHandler.GetAllGenres)                                                  // GET /api/v1/genres
+		genres.GET("/:id/movies", genreHandler.GetGenreMovies, jwtService.OptionalJWTMiddleware()) // GET /api/v1/genres/:id/movies?page=1&limit=12&sort=popular
+	}
+
+	// People (director) browse routes (Public)
+	v1.GET("/people/:id/movies", personHandler.GetPersonMovies, httpCache, jwtService.OptionalJWTMiddleware()) // GET /api/v1/people/:id/movies?page=1&limit=12&sort=popular
+
+	// Watchlist routes (Protected with JWT)
+	watchlist := v1.Group("/watchlist", jwtService.JWTMiddleware())
+	{
+		watchlist.GET("", watchlistHandler.GetWatchlist)                    // GET /api/v1/watchlist?page=1&limit=12
+		watchlist.POST("/:movieID", watchlistHandler.AddToWatchlist)        // POST /api/v1/watchlist/:movieID
+		watchlist.DELETE("/:movieID", watchlistHandler.RemoveFromWatchlist) // DELETE /api/v1/watchlist/:movieID
+	}
+
+	// Timestamped comments (Protected with JWT, movie access required)
+	v1.POST("/movies/:id/comments", commentHandler.PostComment, jwtService.JWTMiddleware()) // POST /api/v1/movies/:id/comments
+	v1.GET("/movies/:id/comments", commentHandler.GetComments, jwtService.JWTMiddleware())  // GET /api/v1/movies/:id/comments?from=0&to=300
+	v1.DELETE("/comments/:id", commentHandler.DeleteOwnComment, jwtService.JWTMiddleware()) // DELETE /api/v1/comments/:id
+
+	// Watch party routes (Protected with JWT)
+	watchParties := v1.Group("/watch-parties")
+	{
+		watchParties.POST("", watchPartyHandler.CreateParty, jwtService.JWTMiddleware())            // POST /api/v1/watch-parties
+		watchParties.GET("/:id", watchPartyHandler.GetParty, jwtService.JWTMiddleware())            // GET /api/v1/watch-parties/:id
+		watchParties.DELETE("/:id", watchPartyHandler.EndParty, jwtService.JWTMiddleware())         // DELETE /api/v1/watch-parties/:id (host ends party)
+		watchParties.POST("/:id/invites", watchPartyHandler.InviteUser, jwtService.JWTMiddleware()) // POST /api/v1/watch-parties/:id/invites
+		watchParties.POST("/:id/join", watchPartyHandler.JoinParty, jwtService.JWTMiddleware())     // POST /api/v1/watch-parties/:id/join
+		watchParties.GET("/:id/ws", watchPartyHandler.Sync, jwtService.WebSocketJWTMiddleware())    // GET /api/v1/watch-parties/:id/ws?token=<jwt> (WebSocket)
 	}
 
 	// Order routes
 	orders := v1.Group("/orders")
 	{
 		// Protected user routes (require JWT)
-		orders.POST("", orderHandler.CreateOrder, jwtService.JWTMiddleware())                                 // POST /api/v1/orders (create rental order)
-		orders.GET("/me", orderHandler.GetUserOrders, jwtService.JWTMiddleware())                             // GET /api/v1/orders/me (user's order history)
-		orders.GET("/:id", orderHandler.GetOrderDetail, jwtService.JWTMiddleware())                           // GET /api/v1/orders/:id (order detail)
-		orders.POST("/:id/simulate-payment", orderHandler.SimulatePaymentSuccess, jwtService.JWTMiddleware()) // POST /api/v1/orders/:id/simulate-payment (dev only)
+		orders.POST("", orderHandler.CreateOrder, jwtService.JWTMiddleware())             // POST /api/v1/orders (create rental order)
+		orders.GET("/me", orderHandler.GetUserOrders, jwtService.JWTMiddleware())         // GET /api/v1/orders/me (user's order history)
+		orders.GET("/:id", orderHandler.GetOrderDetail, jwtService.JWTMiddleware())       // GET /api/v1/orders/:id (order detail)
+		orders.POST("/:id/cancel", orderHandler.CancelOrder, jwtService.JWTMiddleware())  // POST /api/v1/orders/:id/cancel (cancel pending order)
+		orders.GET("/:id/invoice", invoiceHandler.GetInvoice, jwtService.JWTMiddleware()) // GET /api/v1/orders/:id/invoice (receipt of a paid order)
+
+		// Marks an order paid without the gateway, only in development
+		if simulatePayments {
+			orders.POST("/:id/simulate-payment", orderHandler.SimulatePaymentSuccess, jwtService.JWTMiddleware(), appMiddleware.RequireRoles(constant.RoleAdmin)) // POST /api/v1/orders/:id/simulate-payment
+		}
 	}
 
 	// Streaming endpoint (Protected with JWT)
-	v1.GET("/movies/:id/stream", streamingHandler.GetStreamURL, jwtService.JWTMiddleware()) // GET /api/v1/movies/:id/stream
+	v1.GET("/movies/:id/stream", streamingHandler.GetStreamURL, jwtService.JWTMiddleware())        // GET /api/v1/movies/:id/stream
+	v1.GET("/movies/:id/hls/*", streamingHandler.ProxyHLS, jwtService.JWTMiddleware())             // GET /api/v1/movies/:id/hls/*
+	v1.GET("/movies/:id/entitlement", streamingHandler.GetEntitlement, jwtService.JWTMiddleware()) // GET /api/v1/movies/:id/entitlement
+	v1.PUT("/movies/:id/progress", streamingHandler.SaveProgress, jwtService.JWTMiddleware())      // PUT /api/v1/movies/:id/progress
+	v1.GET("/keys/:movieID", streamingHandler.GetContentKey, jwtService.JWTMiddleware())           // GET /api/v1/keys/:movieID (AES-128 key of encrypted HLS)
+
+	// Offline download licenses (Protected with JWT)
+	v1.POST("/movies/:id/offline-license", offlineHandler.IssueLicense, jwtService.JWTMiddleware()) // POST /api/v1/movies/:id/offline-license
+	offline := v1.Group("/offline-licenses", jwtService.JWTMiddleware())
+	{
+		offline.GET("/revocations", offlineHandler.GetRevocationList)  // GET /api/v1/offline-licenses/revocations?since=RFC3339
+		offline.POST("/:licenseID/renew", offlineHandler.RenewLicense) // POST /api/v1/offline-licenses/:licenseID/renew
+	}
+
+	// Editorial reviews (Protected with JWT + editorial permission)
+	editorialReviews := v1.Group("/editorial/reviews")
+	editorialReviews.Use(jwtService.JWTMiddleware(), appMiddleware.RequirePermission(constant.PermManageEditorial))
+	{
+		editorialReviews.POST("", editorialHandler.CreateReview)                  // POST /api/v1/editorial/reviews (saved as DRAFT)
+		editorialReviews.GET("", editorialHandler.GetAllReviews)                  // GET /api/v1/editorial/reviews?page=1&status=DRAFT&movie_id=1
+		editorialReviews.GET("/:id", editorialHandler.GetReview)                  // GET /api/v1/editorial/reviews/:id
+		editorialReviews.PUT("/:id", editorialHandler.UpdateReview)               // PUT /api/v1/editorial/reviews/:id
+		editorialReviews.POST("/:id/publish", editorialHandler.PublishReview)     // POST /api/v1/editorial/reviews/:id/publish
+		editorialReviews.POST("/:id/unpublish", editorialHandler.UnpublishReview) // POST /api/v1/editorial/reviews/:id/unpublish
+		editorialReviews.DELETE("/:id", editorialHandler.DeleteReview)            // DELETE /api/v1/editorial/reviews/:id
+	}
+
+	// Copyright/abuse reports (Public, reporter is linked when signed in)
+	v1.POST("/reports", reportHandler.CreateReport, jwtService.OptionalJWTMiddleware()) // POST /api/v1/reports
+
+	// Help tickets (Protected with JWT)
+	supportTickets := v1.Group("/support/tickets", jwtService.JWTMiddleware())
+	{
+		supportTickets.POST("", supportHandler.CreateTicket)   // POST /api/v1/support/tickets
+		supportTickets.GET("", supportHandler.GetMyTickets)    // GET /api/v1/support/tickets?page=1
+		supportTickets.GET("/:id", supportHandler.GetMyTicket) // GET /api/v1/support/tickets/:id
+	}
+
+	// Rights holder analytics (Protected with JWT, scoped to the titles of the account's rights holder)
+	rightsHolder := v1.Group("/rights-holder", jwtService.JWTMiddleware(), appMiddleware.RequireRoles(constant.RoleRightsHolder))
+	{
+		rightsHolder.GET("/titles", rightsHolderHandler.GetOwnTitles)                // GET /api/v1/rights-holder/titles
+		rightsHolder.GET("/analytics", rightsHolderHandler.GetOwnAnalytics)          // GET /api/v1/rights-holder/analytics?from=2026-01-01&to=2026-01-31&format=csv
+		rightsHolder.GET("/statements/:period", rightsHolderHandler.GetOwnStatement) // GET /api/v1/rights-holder/statements/2026-01?format=csv
+	}
 
 	// Webhook routes (Public but validated via signature)
 	webhooks := v1.Group("/webhooks")
 	{
-		webhooks.POST("/payment", webhookHandler.HandlePaymentWebhook) // POST /api/v1/webhooks/payment (Midtrans notification)
+		webhooks.POST("/payment", webhookHandler.HandlePaymentWebhook)    // POST /api/v1/webhooks/payment (Midtrans notification, legacy URL)
+		webhooks.POST("/:provider", webhookHandler.HandleProviderWebhook) // POST /api/v1/webhooks/stripe
 	}
 
-	// Admin routes (Protected with JWT + AdminOnly middleware)
+	// Admin routes (Protected with JWT, each group checks the permission of the staff role)
 	admin := v1.Group("/admin")
-	admin.Use(jwtService.JWTMiddleware(), appMiddleware.AdminOnly())
+	admin.Use(jwtService.JWTMiddleware())
 	{
 		// Admin movie management
-		adminMovies := admin.Group("/movies")
+		adminMovies := admin.Group("/movies", appMiddleware.RequirePermission(constant.PermManageCatalog))
 		{
 			adminMovies.POST("", movieHandler.UploadMovie)       // POST /api/v1/admin/movies
 			adminMovies.GET("", movieHandler.GetAllMoviesAdmin)  // GET /api/v1/admin/movies?page=1&status=PENDING
+			adminMovies.GET("/:id", movieHandler.GetMovieAdmin)  // GET /api/v1/admin/movies/:id
 			adminMovies.PUT("/:id", movieHandler.UpdateMovie)    // PUT /api/v1/admin/movies/:id
 			adminMovies.DELETE("/:id", movieHandler.DeleteMovie) // DELETE /api/v1/admin/movies/:id
+
+			// Pull a title from the catalog or list it again, paid access keeps working
+			adminMovies.POST("/:id/publish", publishingHandler.PublishMovie)     // POST /api/v1/admin/movies/:id/publish
+			adminMovies.POST("/:id/unpublish", publishingHandler.UnpublishMovie) // POST /api/v1/admin/movies/:id/unpublish
+
+			// Soft launch, only testers and the allowlist see the movie until it is released
+			adminMovies.POST("/:id/soft-launch", publishingHandler.SoftLaunchMovie)                // POST /api/v1/admin/movies/:id/soft-launch
+			adminMovies.POST("/:id/release", publishingHandler.ReleaseMovie)                       // POST /api/v1/admin/movies/:id/release
+			adminMovies.GET("/:id/beta-viewers", publishingHandler.GetBetaViewers)                 // GET /api/v1/admin/movies/:id/beta-viewers
+			adminMovies.POST("/:id/beta-viewers", publishingHandler.AddBetaViewers)                // POST /api/v1/admin/movies/:id/beta-viewers
+			adminMovies.DELETE("/:id/beta-viewers/:userExtID", publishingHandler.RemoveBetaViewer) // DELETE /api/v1/admin/movies/:id/beta-viewers/:userExtID
+
+			// Chunked (resumable) upload
+			adminMovies.POST("/uploads", uploadHandler.InitUpload)                  // POST /api/v1/admin/movies/uploads
+			adminMovies.GET("/uploads/:id", uploadHandler.GetUploadSession)         // GET /api/v1/admin/movies/uploads/:id
+			adminMovies.PUT("/uploads/:id/parts/:part", uploadHandler.UploadPart)   // PUT /api/v1/admin/movies/uploads/:id/parts/:part
+			adminMovies.POST("/uploads/:id/complete", uploadHandler.CompleteUpload) // POST /api/v1/admin/movies/uploads/:id/complete
+			adminMovies.DELETE("/uploads/:id", uploadHandler.AbortUpload)           // DELETE /api/v1/admin/movies/uploads/:id
+
+			// Direct-to-MinIO (presigned) upload
+			adminMovies.POST("/uploads/presign", uploadHandler.PresignUpload)    // POST /api/v1/admin/movies/uploads/presign
+			adminMovies.POST("/:id/upload/confirm", uploadHandler.ConfirmUpload) // POST /api/v1/admin/movies/:id/upload/confirm
+			adminMovies.POST("/:id/retranscode", uploadHandler.RetranscodeMovie) // POST /api/v1/admin/movies/:id/retranscode
+
+			// Metadata from TMDB, fills in empty fields unless overwrite is set
+			adminMovies.POST("/:id/enrich", metadataHandler.EnrichMovie) // POST /api/v1/admin/movies/:id/enrich
+
+			// Search appearance, empty fields fall back to the title, description and movie page
+			adminMovies.GET("/:id/seo", seoHandler.GetMovieSEO)      // GET /api/v1/admin/movies/:id/seo
+			adminMovies.PUT("/:id/seo", seoHandler.UpdateMovieSEO)   // PUT /api/v1/admin/movies/:id/seo
+			adminMovies.DELETE("/:id/seo", seoHandler.ClearMovieSEO) // DELETE /api/v1/admin/movies/:id/seo
+
+			// Emergency takedown pending investigation
+			adminMovies.POST("/:id/takedown", reportHandler.TakedownMovie, appMiddleware.RequirePermission(constant.PermModerateContent))  // POST /api/v1/admin/movies/:id/takedown
+			adminMovies.DELETE("/:id/takedown", reportHandler.RestoreMovie, appMiddleware.RequirePermission(constant.PermModerateContent)) // DELETE /api/v1/admin/movies/:id/takedown
+
+			// Complimentary access campaigns, e.g. press screeners or giveaway winners, imported from a CSV of emails
+			adminMovies.POST("/:id/access-grants", grantHandler.ImportGrant, appMiddleware.RequirePermission(constant.PermGrantAccess)) // POST /api/v1/admin/movies/:id/access-grants
+			adminMovies.GET("/:id/access-grants", grantHandler.GetGrants, appMiddleware.RequirePermission(constant.PermGrantAccess))    // GET /api/v1/admin/movies/:id/access-grants
+
+			// Screener links for distributors and festivals, watched without an account
+			adminMovies.POST("/:id/screeners", orderHandler.CreateScreener, appMiddleware.RequirePermission(constant.PermGrantAccess)) // POST /api/v1/admin/movies/:id/screeners
+			adminMovies.GET("/:id/screeners", orderHandler.GetScreeners, appMiddleware.RequirePermission(constant.PermGrantAccess))    // GET /api/v1/admin/movies/:id/screeners
+
+			// Poster/trailer upload to the private media bucket
+			adminMovies.POST("/:id/media/:kind", mediaHandler.UploadMovieMedia) // POST /api/v1/admin/movies/:id/media/poster
+
+			// Subtitle tracks, converted to WebVTT and served through the HLS proxy
+			adminMovies.POST("/:id/subtitles", subtitleHandler.UploadSubtitle)             // POST /api/v1/admin/movies/:id/subtitles
+			adminMovies.GET("/:id/subtitles", subtitleHandler.GetSubtitles)                // GET /api/v1/admin/movies/:id/subtitles
+			adminMovies.DELETE("/:id/subtitles/:language", subtitleHandler.DeleteSubtitle) // DELETE /api/v1/admin/movies/:id/subtitles/en
+
+			// Speech-to-text drafts from the worker, players only see them once approved
+			adminMovies.GET("/:id/subtitle-drafts", subtitleHandler.GetSubtitleDrafts)                      // GET /api/v1/admin/movies/:id/subtitle-drafts
+			adminMovies.GET("/:id/subtitle-drafts/:draftID/file", subtitleHandler.GetSubtitleDraftFile)     // GET /api/v1/admin/movies/:id/subtitle-drafts/:draftID/file
+			adminMovies.POST("/:id/subtitle-drafts/:draftID/approve", subtitleHandler.ApproveSubtitleDraft) // POST /api/v1/admin/movies/:id/subtitle-drafts/:draftID/approve
+			adminMovies.DELETE("/:id/subtitle-drafts/:draftID", subtitleHandler.RejectSubtitleDraft)        // DELETE /api/v1/admin/movies/:id/subtitle-drafts/:draftID
+
+			// Dubs and audio descriptions, added to the master playlist next to the source audio
+			adminMovies.POST("/:id/audio-tracks", audioHandler.UploadAlternateAudio)            // POST /api/v1/admin/movies/:id/audio-tracks
+			adminMovies.GET("/:id/audio-tracks", audioHandler.GetAlternateAudio)                // GET /api/v1/admin/movies/:id/audio-tracks
+			adminMovies.DELETE("/:id/audio-tracks/:trackID", audioHandler.DeleteAlternateAudio) // DELETE /api/v1/admin/movies/:id/audio-tracks/:trackID
+
+			// Per-region licensing windows, orders outside a window are refused
+			adminMovies.POST("/:id/license-windows", licensingHandler.CreateLicenseWindow)             // POST /api/v1/admin/movies/:id/license-windows
+			adminMovies.GET("/:id/license-windows", licensingHandler.GetLicenseWindows)                // GET /api/v1/admin/movies/:id/license-windows
+			adminMovies.DELETE("/:id/license-windows/:windowID", licensingHandler.DeleteLicenseWindow) // DELETE /api/v1/admin/movies/:id/license-windows/:windowID
+		}
+
+		// Pending transcoding jobs, and jobs queued by hand e.g. to encode one rendition again
+		adminJobs := admin.Group("/transcoding/jobs", appMiddleware.RequirePermission(constant.PermManageCatalog))
+		{
+			adminJobs.GET("", queueHandler.GetTranscodingQueue)            // GET /api/v1/admin/transcoding/jobs?limit=50
+			adminJobs.POST("", queueHandler.EnqueueTranscodingJob)         // POST /api/v1/admin/transcoding/jobs
+			adminJobs.DELETE("/:jobID", queueHandler.RemoveTranscodingJob) // DELETE /api/v1/admin/transcoding/jobs/:jobID
 		}
 
+		// Licenses expiring soon, so they can be renewed in time
+		admin.GET("/licensing/expiring", licensingHandler.GetExpiringLicenses, appMiddleware.RequirePermission(constant.PermManageCatalog)) // GET /api/v1/admin/licensing/expiring?days=30
+
 		// Admin genre management
-		adminGenres := admin.Group("/genres")
+		adminGenres := admin.Group("/genres", appMiddleware.RequirePermission(constant.PermManageCatalog))
 		{
 			adminGenres.POST("", genreHandler.CreateGenre)       // POST /api/v1/admin/genres
+			adminGenres.PUT("/:id", genreHandler.UpdateGenre)    // PUT /api/v1/admin/genres/:id
 			adminGenres.DELETE("/:id", genreHandler.DeleteGenre) // DELETE /api/v1/admin/genres/:id
 		}
 
+		// Admin people management
+		admin.PUT("/people/:id", personHandler.UpdatePerson, appMiddleware.RequirePermission(constant.PermManageCatalog)) // PUT /api/v1/admin/people/:id
+
 		// Admin order management
-		adminOrders := admin.Group("/orders")
+		adminOrders := admin.Group("/orders", appMiddleware.RequirePermission(constant.PermManageOrders))
+		{
+			adminOrders.GET("", orderHandler.GetAllOrders)                      // GET /api/v1/admin/orders?page=1&status=PAID&invoice=INV-2026-
+			adminOrders.POST("/:id/refund", orderHandler.RefundOrder)           // POST /api/v1/admin/orders/:id/refund
+			adminOrders.DELETE("/:id/devices", orderHandler.ResetRentalDevices) // DELETE /api/v1/admin/orders/:id/devices
+			adminOrders.GET("/:id/events", orderHandler.GetOrderPaymentEvents)  // GET /api/v1/admin/orders/:id/events
+		}
+
+		// Screener link view logs and revocation
+		adminScreeners := admin.Group("/screeners", appMiddleware.RequirePermission(constant.PermGrantAccess))
+		{
+			adminScreeners.GET("/:id/views", orderHandler.GetScreenerViews) // GET /api/v1/admin/screeners/:id/views
+			adminScreeners.DELETE("/:id", orderHandler.RevokeScreener)      // DELETE /api/v1/admin/screeners/:id
+		}
+
+		// Complimentary access granted by hand and revocation of any access, e.g. after a chargeback
+		adminAccess := admin.Group("/access", appMiddleware.RequirePermission(constant.PermGrantAccess))
+		{
+			adminAccess.POST("", orderHandler.GrantAccess)        // POST /api/v1/admin/access
+			adminAccess.GET("/:id", orderHandler.GetAccess)       // GET /api/v1/admin/access/:id
+			adminAccess.DELETE("/:id", orderHandler.RevokeAccess) // DELETE /api/v1/admin/access/:id
+		}
+
+		// End-of-day settlement reports for finance, reconciled by the worker
+		adminSettlements := admin.Group("/settlements", appMiddleware.RequirePermission(constant.PermManageOrders))
+		{
+			adminSettlements.GET("", orderHandler.GetSettlementReports)      // GET /api/v1/admin/settlements?from=2025-12-01&to=2025-12-31
+			adminSettlements.GET("/:date", orderHandler.GetSettlementReport) // GET /api/v1/admin/settlements/2025-12-30
+		}
+
+		// Admin comment moderation
+		adminComments := admin.Group("/comments", appMiddleware.RequirePermission(constant.PermModerateContent))
+		{
+			adminComments.GET("", commentHandler.GetAllCommentsAdmin)   // GET /api/v1/admin/comments?page=1&status=VISIBLE&movie_id=1
+			adminComments.PATCH("/:id", commentHandler.ModerateComment) // PATCH /api/v1/admin/comments/:id
+			adminComments.DELETE("/:id", commentHandler.DeleteComment)  // DELETE /api/v1/admin/comments/:id
+		}
+
+		// Uploads held back by content moderation, approving overrides the verdict
+		adminMediaReviews := admin.Group("/media-reviews", appMiddleware.RequirePermission(constant.PermModerateContent))
+		{
+			adminMediaReviews.GET("", mediaHandler.GetMediaReviews)                       // GET /api/v1/admin/media-reviews?status=PENDING
+			adminMediaReviews.POST("/:reviewID/approve", mediaHandler.ApproveMediaReview) // POST /api/v1/admin/media-reviews/:reviewID/approve
+			adminMediaReviews.POST("/:reviewID/reject", mediaHandler.RejectMediaReview)   // POST /api/v1/admin/media-reviews/:reviewID/reject
+		}
+
+		// Admin content report queue
+		adminReports := admin.Group("/reports", appMiddleware.RequirePermission(constant.PermModerateContent))
+		{
+			adminReports.GET("", reportHandler.GetAllReportsAdmin) // GET /api/v1/admin/reports?page=1&status=OPEN&target_type=MOVIE
+			adminReports.PATCH("/:id", reportHandler.UpdateReport) // PATCH /api/v1/admin/reports/:id
+		}
+
+		// Admin help ticket handling
+		adminTickets := admin.Group("/support/tickets", appMiddleware.RequirePermission(constant.PermManageSupport))
+		{
+			adminTickets.GET("", supportHandler.GetAllTicketsAdmin)              // GET /api/v1/admin/support/tickets?page=1&status=OPEN&assigned_to=me
+			adminTickets.GET("/:id", supportHandler.GetTicketAdmin)              // GET /api/v1/admin/support/tickets/:id
+			adminTickets.PATCH("/:id/assign", supportHandler.AssignTicket)       // PATCH /api/v1/admin/support/tickets/:id/assign
+			adminTickets.PATCH("/:id/status", supportHandler.UpdateTicketStatus) // PATCH /api/v1/admin/support/tickets/:id/status
+		}
+
+		// Admin security/ops summary
+		admin.GET("/ops/summary", opsHandler.GetSummary, appMiddleware.RequirePermission(constant.PermViewOps)) // GET /api/v1/admin/ops/summary?window=24h&top=10
+
+		// Data retention runs of the worker, dry runs show what a rule would delete or anonymize
+		admin.GET("/ops/retention", opsHandler.GetRetentionReports, appMiddleware.RequirePermission(constant.PermViewOps)) // GET /api/v1/admin/ops/retention?target=orders&limit=50
+
+		// Backlog of the named job queues, jobs out of attempts stay in a dead letter list
+		admin.GET("/ops/queues", opsHandler.GetQueues, appMiddleware.RequirePermission(constant.PermViewOps)) // GET /api/v1/admin/ops/queues
+
+		// Nightly backups of the worker, the last successful one and the latest runs
+		admin.GET("/ops/backups", opsHandler.GetBackups, appMiddleware.RequirePermission(constant.PermViewOps)) // GET /api/v1/admin/ops/backups?limit=20
+
+		// Registered routes with their required roles and permissions, and role-filtered OpenAPI documents
+		adminRoutes := admin.Group("/routes", appMiddleware.RequirePermission(constant.PermViewOps))
+		{
+			adminRoutes.GET("", routeCatalog.ListRoutes)                  // GET /api/v1/admin/routes?role=CONTENT_MANAGER
+			adminRoutes.GET("/openapi.json", routeCatalog.GetRoleOpenAPI) // GET /api/v1/admin/routes/openapi.json?role=USER
+		}
+
+		// Admin offline license management
+		admin.DELETE("/offline-licenses/:licenseID", offlineHandler.RevokeLicense, appMiddleware.RequirePermission(constant.PermManageLicenses)) // DELETE /api/v1/admin/offline-licenses/:licenseID?reason=...
+
+		// Admin user and role management
+		adminUsers := admin.Group("/users", appMiddleware.RequirePermission(constant.PermManageUsers))
+		{
+			adminUsers.GET("", userHandler.GetUsersAdmin)                                   // GET /api/v1/admin/users?page=1&role=CONTENT_MANAGER&status=disabled&q=john
+			adminUsers.PATCH("/:extID/role", userHandler.UpdateUserRole)                    // PATCH /api/v1/admin/users/:extID/role
+			adminUsers.POST("/:extID/disable", userHandler.DisableUser)                     // POST /api/v1/admin/users/:extID/disable
+			adminUsers.POST("/:extID/enable", userHandler.EnableUser)                       // POST /api/v1/admin/users/:extID/enable
+			adminUsers.POST("/:extID/unlock", userHandler.UnlockUser)                       // POST /api/v1/admin/users/:extID/unlock
+			adminUsers.POST("/:extID/force-password-reset", userHandler.ForcePasswordReset) // POST /api/v1/admin/users/:extID/force-password-reset
+			adminUsers.GET("/:extID/audit-logs", userHandler.GetUserAuditLogs)              // GET /api/v1/admin/users/:extID/audit-logs?limit=50
+		}
+
+		// Marketing audience, only users who opted in
+		admin.GET("/marketing/audience", userHandler.ExportMarketingAudience, appMiddleware.RequirePermission(constant.PermExportAudience)) // GET /api/v1/admin/marketing/audience?genre_id=3&format=csv
+
+		// Partner API keys, quotas and usage reports
+		adminPartners := admin.Group("/partners/keys", appMiddleware.RequirePermission(constant.PermManagePartners))
+		{
+			adminPartners.POST("", partnerHandler.CreateAPIKey)           // POST /api/v1/admin/partners/keys
+			adminPartners.GET("", partnerHandler.GetAPIKeys)              // GET /api/v1/admin/partners/keys
+			adminPartners.PUT("/:id/quotas", partnerHandler.UpdateQuotas) // PUT /api/v1/admin/partners/keys/:id/quotas
+			adminPartners.DELETE("/:id", partnerHandler.RevokeAPIKey)     // DELETE /api/v1/admin/partners/keys/:id
+			adminPartners.GET("/:id/usage", partnerHandler.GetKeyUsage)   // GET /api/v1/admin/partners/keys/:id/usage?month=2025-12
+		}
+
+		// Rights holders, their accounts and titles
+		adminRightsHolders := admin.Group("/rights-holders", appMiddleware.RequirePermission(constant.PermManagePartners))
 		{
-			adminOrders.GET("", orderHandler.GetAllOrders) // GET /api/v1/admin/orders?page=1&status=PAID
+			adminRightsHolders.POST("", rightsHolderHandler.CreateRightsHolder)                         // POST /api/v1/admin/rights-holders
+			adminRightsHolders.GET("", rightsHolderHandler.GetRightsHolders)                            // GET /api/v1/admin/rights-holders
+			adminRightsHolders.GET("/:id", rightsHolderHandler.GetRightsHolder)                         // GET /api/v1/admin/rights-holders/:id
+			adminRightsHolders.POST("/:id/members", rightsHolderHandler.AddMember)                      // POST /api/v1/admin/rights-holders/:id/members
+			adminRightsHolders.DELETE("/:id/members/:user_id", rightsHolderHandler.RemoveMember)        // DELETE /api/v1/admin/rights-holders/:id/members/:user_id
+			adminRightsHolders.POST("/:id/titles", rightsHolderHandler.AssignTitle)                     // POST /api/v1/admin/rights-holders/:id/titles
+			adminRightsHolders.DELETE("/:id/titles/:movie_id", rightsHolderHandler.RemoveTitle)         // DELETE /api/v1/admin/rights-holders/:id/titles/:movie_id
+			adminRightsHolders.GET("/:id/analytics", rightsHolderHandler.GetAnalytics)                  // GET /api/v1/admin/rights-holders/:id/analytics?from=2026-01-01&to=2026-01-31&format=csv
+			adminRightsHolders.PUT("/:id/share", rightsHolderHandler.UpdateShare)                       // PUT /api/v1/admin/rights-holders/:id/share
+			adminRightsHolders.PUT("/:id/titles/:movie_id/share", rightsHolderHandler.UpdateTitleShare) // PUT /api/v1/admin/rights-holders/:id/titles/:movie_id/share
+			adminRightsHolders.GET("/:id/statements/:period", rightsHolderHandler.GetStatement)         // GET /api/v1/admin/rights-holders/:id/statements/2026-01?format=csv
 		}
 	}
 
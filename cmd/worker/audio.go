@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/rs/zerolog"
+)
+
+// processAudioJob encodes a dub or audio description into an HLS audio rendition.
+// Only the audio is encoded, the API lists READY tracks in the master playlist it serves.
+func (p *JobProcessor) processAudioJob(ctx context.Context, job *queue.AudioJob) error {
+	ctx, logger := jobContext(ctx, "audio", job.ID, job.MovieID, job.Trace, "track_id", job.TrackID)
+
+	track, err := p.movieRepo.FindAlternateAudio(ctx, job.TrackID)
+	if err != nil {
+		return fmt.Errorf("failed to load audio track: %w", err)
+	}
+	if track == nil {
+		logger.Info().Msg("Skipping, the track was deleted")
+		return nil
+	}
+
+	if err := p.movieRepo.UpdateAlternateAudio(ctx, track.ID, map[string]interface{}{
+		"status": movies.AlternateAudioProcessing,
+	}); err != nil {
+		return fmt.Errorf("failed to update status to PROCESSING: %w", err)
+	}
+
+	// Same key as the video segments, players fetch it once per movie
+	key, err := p.contentKey(ctx, track.MovieID)
+	if err != nil {
+		p.markAudioFailed(ctx, track.ID, err)
+		return fmt.Errorf("failed to load content key: %w", err)
+	}
+
+	logger.Info().Str("kind", track.Kind).Str("language", track.Language).Msg("Packaging audio track")
+	playlistObject, err := p.transcodingService.PackageAlternateAudio(ctx, track.MovieID, track.ID, track.SourceObject, key)
+	if err != nil {
+		if ctx.Err() != nil {
+			// handleJob puts the job back for another worker, this worker is shutting down
+			return ctx.Err()
+		}
+		logger.Error().Err(err).Msg("Packaging FAILED")
+		p.markAudioFailed(ctx, track.ID, err)
+		return fmt.Errorf("audio packaging failed: %w", err)
+	}
+
+	if err := p.movieRepo.UpdateAlternateAudio(ctx, track.ID, map[string]interface{}{
+		"status":          movies.AlternateAudioReady,
+		"playlist_object": playlistObject,
+		"error_message":   nil,
+	}); err != nil {
+		return fmt.Errorf("failed to update status to READY: %w", err)
+	}
+
+	// The movie detail lists the audio languages
+	if err := p.movieRepo.RecordCatalogChanges(ctx, track.MovieID, movies.CatalogChangeUpdated); err != nil {
+		logger.Error().Err(err).Msg("Failed to record catalog change")
+	}
+
+	logger.Info().Str("playlist", playlistObject).Msg("Audio track ready")
+	return nil
+}
+
+func (p *JobProcessor) markAudioFailed(ctx context.Context, trackID int64, cause error) {
+	if err := p.movieRepo.UpdateAlternateAudio(ctx, trackID, map[string]interface{}{
+		"status":        movies.AlternateAudioFailed,
+		"error_message": cause.Error(),
+	}); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to update error status")
+	}
+}
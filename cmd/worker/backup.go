@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/backup"
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+	"github.com/martinmanurung/cinestream/internal/platform/database"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/minio/minio-go/v7"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+const (
+	// The scheduler checks once a minute whether the day's backup is due
+	backupCheckInterval = time.Minute
+	// Held for the day once a worker took the day's backup, so one worker writes it
+	backupLockPrefix = "backup:scheduled:"
+	backupLockTTL    = 48 * time.Hour
+)
+
+// BackupScheduler writes a backup every day at a time of day, UTC.
+// A worker started after the time writes the day's backup right away, unless another worker already took it.
+type BackupScheduler struct {
+	engine *backup.Engine
+	redis  *redis.Client
+	at     time.Duration // since midnight
+}
+
+// NewBackupScheduler creates a new backup scheduler
+func NewBackupScheduler(engine *backup.Engine, redisClient *redis.Client, at time.Duration) *BackupScheduler {
+	return &BackupScheduler{
+		engine: engine,
+		redis:  redisClient,
+		at:     at,
+	}
+}
+
+// Start runs the scheduler until the context is cancelled
+func (s *BackupScheduler) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "backup")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Dur("at", s.at).Msg("Backup scheduler started")
+
+	ticker := time.NewTicker(backupCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Backup scheduler received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check writes the day's backup once it is due and no worker took it yet
+func (s *BackupScheduler) check(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	if now.Before(day.Add(s.at)) {
+		return
+	}
+
+	// A failed backup is not retried until the next day, it shows on the ops dashboard
+	taken, err := s.redis.SetNX(ctx, backupLockPrefix+day.Format(time.DateOnly), 1, backupLockTTL).Result()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to lock the daily backup")
+		return
+	}
+	if !taken {
+		return
+	}
+
+	logBackupRun(ctx, s.engine, backup.TriggerScheduled)
+}
+
+// logBackupRun writes a backup and logs its outcome
+func logBackupRun(ctx context.Context, engine *backup.Engine, trigger string) backup.Run {
+	logger := zerolog.Ctx(ctx)
+	run, err := engine.Run(ctx, trigger)
+	event := logger.Info()
+	if run.Status != backup.StatusOK {
+		event = logger.Error().Str("error", *run.Error)
+	}
+	if run.Object != nil {
+		event = event.Str("object", *run.Object)
+	}
+	event.
+		Str("trigger", run.Trigger).
+		Int("tables", run.Tables).
+		Int64("rows", run.Rows).
+		Int64("objects", run.Objects).
+		Int64("size_bytes", run.SizeBytes).
+		Int("pruned", run.Pruned).
+		Dur("took", run.FinishedAt.Sub(run.StartedAt)).
+		Msg("Backup finished")
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to store backup run")
+	}
+	return run
+}
+
+// newBackupEngine creates the backup engine of the config, the backup bucket is created when the worker manages buckets.
+// The originals of the raw and media buckets are snapshotted, the processed renditions can be encoded again.
+func newBackupEngine(cfg *config.Config, db *gorm.DB, minioClient *minio.Client) (*backup.Engine, error) {
+	key, err := backup.ParseKey(cfg.Backup.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MinIO.ManageBuckets {
+		if err := storage.EnsurePrivateBucket(minioClient, cfg.Backup.Bucket); err != nil {
+			return nil, err
+		}
+	}
+
+	objectBuckets := []string{cfg.MinIO.BucketRaw}
+	if cfg.MinIO.BucketMedia != "" {
+		objectBuckets = append(objectBuckets, cfg.MinIO.BucketMedia)
+	}
+	return backup.NewEngine(db, minioClient, backup.Options{
+		Bucket:        cfg.Backup.Bucket,
+		Key:           key,
+		Tables:        cfg.Backup.Tables,
+		ObjectBuckets: objectBuckets,
+		Retention:     time.Duration(cfg.Backup.RetentionDays) * 24 * time.Hour,
+	})
+}
+
+// runBackupCommand writes a backup right away, or restores the archive when restore is set, and returns the exit code.
+// Without confirm a restore only checks that the archive decrypts and prints what it holds.
+func runBackupCommand(configPath, restore string, confirm bool) int {
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	ctx := logger.WithContext(context.Background())
+
+	cfg, err := config.LoadConfig(configPath, "worker")
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to load config")
+		return 1
+	}
+	db, err := database.InitMySQL(cfg.Database)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to initialize database")
+		return 1
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	minioClient, err := storage.NewMinIOClient(cfg.MinIO)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to initialize MinIO")
+		return 1
+	}
+	engine, err := newBackupEngine(cfg, db, minioClient)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to configure backups")
+		return 1
+	}
+
+	if restore == "" {
+		if run := logBackupRun(ctx, engine, backup.TriggerManual); run.Status != backup.StatusOK {
+			return 1
+		}
+		return 0
+	}
+
+	manifest, err := engine.Inspect(ctx, restore)
+	if err != nil {
+		logger.Error().Err(err).Str("object", restore).Msg("Failed to read backup")
+		return 1
+	}
+	fmt.Printf("%s, written %s\n", restore, manifest.CreatedAt.Format(time.RFC3339))
+	for _, table := range manifest.Tables {
+		fmt.Printf("  %-30s %d rows\n", table.Name, table.Rows)
+	}
+	if !confirm {
+		fmt.Println("The rows of these tables are replaced by the archived rows, run again with --yes to restore.")
+		return 1
+	}
+
+	restored, err := engine.Restore(ctx, restore)
+	if err != nil {
+		logger.Error().Err(err).Str("object", restore).Msg("Restore failed, nothing was changed")
+		return 1
+	}
+	logger.Info().Str("object", restore).Int("tables", len(restored)).Msg("Backup restored")
+	return 0
+}
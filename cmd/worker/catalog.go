@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	"github.com/martinmanurung/cinestream/internal/platform/cdn"
+	"github.com/rs/zerolog"
+)
+
+// CatalogChangeDispatcher drains the catalog change log into cache/CDN purges,
+// so admin edits such as a new price are visible before the cached responses expire
+type CatalogChangeDispatcher struct {
+	movieRepo   *movieRepository.MovieRepository
+	purger      cdn.Purger
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewCatalogChangeDispatcher creates a new catalog change dispatcher
+func NewCatalogChangeDispatcher(
+	movieRepo *movieRepository.MovieRepository,
+	purger cdn.Purger,
+	interval time.Duration,
+	batchSize int,
+	maxAttempts int,
+) *CatalogChangeDispatcher {
+	return &CatalogChangeDispatcher{
+		movieRepo:   movieRepo,
+		purger:      purger,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start runs the dispatcher until the context is cancelled
+func (d *CatalogChangeDispatcher) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "catalog_change_dispatcher")
+	zerolog.Ctx(ctx).Info().Dur("interval", d.interval).Msg("Catalog change dispatcher started")
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("Catalog change dispatcher received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch purges pending changes in batches, one purge call per batch
+func (d *CatalogChangeDispatcher) dispatch(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	purged := 0
+	invalidated := false
+
+	for ctx.Err() == nil {
+		changes, err := d.movieRepo.FindPendingCatalogChanges(ctx, d.maxAttempts, d.batchSize)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to find pending catalog changes")
+			return
+		}
+
+		if len(changes) == 0 {
+			break
+		}
+
+		ids := make([]int64, 0, len(changes))
+		for _, change := range changes {
+			ids = append(ids, change.ID)
+		}
+
+		// Changes recorded outside the repository, e.g. takedowns, reach the API's catalog cache here.
+		// It is dropped before the purge so the CDN fetches fresh responses.
+		if !invalidated {
+			d.movieRepo.InvalidateCatalogCache(ctx)
+			invalidated = true
+		}
+
+		if err := d.purger.Purge(ctx, purgePaths(changes)); err != nil {
+			logger.Warn().Err(err).Int("changes", len(changes)).Msg("CDN purge failed")
+			if markErr := d.movieRepo.MarkCatalogChangesFailed(ctx, ids, err.Error()); markErr != nil {
+				logger.Error().Err(markErr).Msg("Failed to record purge attempt")
+			}
+			// Retry on the next tick instead of looping
+			break
+		}
+
+		if err := d.movieRepo.MarkCatalogChangesProcessed(ctx, ids, time.Now()); err != nil {
+			logger.Error().Err(err).Msg("Failed to mark catalog changes processed")
+			return
+		}
+		purged += len(changes)
+
+		if len(changes) < d.batchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		logger.Info().Int("purged", purged).Msg("Purged catalog changes")
+	}
+}
+
+// purgePaths merges the paths of all changes in a batch without duplicates
+func purgePaths(changes []movies.CatalogChange) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, change := range changes {
+		for _, path := range change.PurgePaths() {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+	"github.com/martinmanurung/cinestream/internal/platform/database"
+	"github.com/martinmanurung/cinestream/internal/platform/health"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/redis/go-redis/v9"
+)
+
+// selfTestTimeout is what each check of the --check mode may take, the encoder self-test encodes a few frames
+const selfTestTimeout = 30 * time.Second
+
+// runSelfTest checks the config, every dependency of the worker and the ffmpeg encoder once,
+// prints a pass/fail report and returns the exit code. Unlike a normal start it creates no buckets, only a probe object is written and removed.
+func runSelfTest(configPath string) int {
+	ctx := context.Background()
+	checker := health.NewChecker(selfTestTimeout)
+
+	cfg, err := config.LoadConfig(configPath, "worker")
+	if err == nil {
+		err = transcoding.ConfigureProfileSets(cfg.Transcoding)
+	}
+	if err == nil {
+		err = queue.NewRedisQueue(nil).SetRoutes(cfg.Queue.Routes)
+	}
+	checker.Add("config", func(context.Context) error { return err })
+	if err != nil {
+		checker.SelfTest(ctx, os.Stdout)
+		return 1
+	}
+
+	checker.Add("mysql", health.Detached(func(context.Context) error {
+		db, err := database.InitMySQL(cfg.Database)
+		if err != nil {
+			return err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	}))
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	defer redisClient.Close()
+	checker.Add("redis", health.Redis(redisClient))
+
+	minioClient, err := storage.NewMinIOClient(cfg.MinIO)
+	if err != nil {
+		checker.Add("minio", func(context.Context) error { return err })
+	} else {
+		checker.Add("bucket_access", func(ctx context.Context) error {
+			return storage.ProbeBuckets(ctx, minioClient, cfg.MinIO.BucketRaw, cfg.MinIO.BucketProcessed)
+		})
+		// Scoped keys may not read bucket policies, the policies are then managed elsewhere
+		if cfg.MinIO.ManageBuckets {
+			checker.Add("bucket_policy", health.BucketPolicy(minioClient, cfg.MinIO.BucketProcessed, cfg.MinIO.BucketRaw, cfg.MinIO.BucketMedia))
+		}
+	}
+
+	// Lists the encoders built into ffmpeg and encodes a few frames with the configured one
+	checker.Add("ffmpeg", func(ctx context.Context) error {
+		_, err := transcoding.ConfigureEncoder(ctx, cfg.Transcoding.Encoder, cfg.Transcoding.VAAPIDevice)
+		return err
+	})
+
+	if !checker.SelfTest(ctx, os.Stdout) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxChunkAttempts is how often a chunk is encoded before the whole title fails
+	maxChunkAttempts  = 2
+	chunkPollInterval = 5 * time.Second
+)
+
+// ChunkingOptions controls when a title is split across workers
+type ChunkingOptions struct {
+	Enabled     bool
+	MinDuration time.Duration
+	ChunkLength time.Duration
+	WaitTimeout time.Duration
+}
+
+// transcode encodes the title on this worker, or splits it across workers when the source is long enough.
+// Chunk workers look the profile set up by name, a narrowed set is not chunkable.
+func (p *JobProcessor) transcode(ctx context.Context, movieID int64, version int, rawFilePath string, profileSet transcoding.ProfileSet, key *transcoding.ContentKey, chunkable bool, keep *transcoding.KeptOutput) (*transcoding.TranscodeResult, error) {
+	if p.chunking.Enabled && chunkable {
+		duration, err := p.transcodingService.SourceDuration(ctx, rawFilePath)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to probe source duration, encoding on this worker")
+		} else if duration >= p.chunking.MinDuration.Seconds() {
+			result, err := p.transcodeChunked(ctx, movieID, version, rawFilePath, profileSet, key)
+			if !errors.Is(err, transcoding.ErrChunkingUnsupported) {
+				return result, err
+			}
+			zerolog.Ctx(ctx).Info().Msg("Source cannot be chunked, encoding on this worker")
+		}
+	}
+
+	return p.transcodingService.TranscodeToHLS(ctx, movieID, version, rawFilePath, profileSet, key, keep)
+}
+
+// transcodeChunked splits the source, fans the chunks out to all workers and stitches the result.
+// This worker encodes chunks too while it waits, so a single worker still finishes the title.
+func (p *JobProcessor) transcodeChunked(ctx context.Context, movieID int64, version int, rawFilePath string, profileSet transcoding.ProfileSet, key *transcoding.ContentKey) (*transcoding.TranscodeResult, error) {
+	logger := zerolog.Ctx(ctx)
+	plan, err := p.transcodingService.PrepareChunks(ctx, movieID, version, rawFilePath, profileSet, p.chunking.ChunkLength.Seconds(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Cleanup also runs after a shutdown, so it must not use the cancelled context
+		cleanupCtx := context.WithoutCancel(ctx)
+		p.transcodingService.CleanupChunks(cleanupCtx, plan)
+		if err := p.queueService.ClearChunkState(cleanupCtx, movieID, version); err != nil {
+			logger.Error().Err(err).Msg("Failed to clear chunk state")
+		}
+	}()
+
+	logger.Info().Int("version", version).Int("chunks", len(plan.Chunks)).Msg("Split source into chunks")
+	for index := range plan.Chunks {
+		task := plan.Task(index)
+		if err := p.queueService.PublishChunkJob(ctx, queue.ChunkJob{
+			MovieID:          task.MovieID,
+			Version:          task.Version,
+			Index:            task.Index,
+			Total:            len(plan.Chunks),
+			SourceObject:     task.SourceObject,
+			ProfileSet:       task.ProfileSet,
+			ComplexityFactor: task.ComplexityFactor,
+			ToneMap:          task.ToneMap,
+			SourceHeight:     task.SourceHeight,
+			SourceKbps:       task.SourceKbps,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to publish chunk %d: %w", index, err)
+		}
+	}
+
+	deadline := time.Now().Add(p.chunking.WaitTimeout)
+	for {
+		progress, err := p.queueService.GetChunkProgress(ctx, movieID, version)
+		if err != nil {
+			return nil, err
+		}
+		if progress.Failure != "" {
+			return nil, fmt.Errorf("chunked encode failed: %s", progress.Failure)
+		}
+		if progress.Done >= len(plan.Chunks) {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("chunked encode timed out with %d of %d chunks done", progress.Done, len(plan.Chunks))
+		}
+
+		handled, err := p.processNextChunkJob(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Error().Err(err).Msg("Error processing chunk job")
+		}
+		if !handled {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(chunkPollInterval):
+			}
+		}
+	}
+
+	logger.Info().Int("chunks", len(plan.Chunks)).Msg("All chunks encoded, stitching renditions")
+	return p.transcodingService.StitchChunks(ctx, plan)
+}
+
+// processNextChunkJob runs the next job of the chunk queue, usually a chunk of any title,
+// it reports whether there was one
+func (p *JobProcessor) processNextChunkJob(ctx context.Context) (bool, error) {
+	job, err := p.queueService.PopJob(ctx, p.queueService.QueueOf(queue.JobTypeChunk), -1)
+	if err != nil || job == nil {
+		return false, err
+	}
+	return true, p.handleJob(ctx, job)
+}
+
+// processChunkJob encodes one chunk and records the outcome for the coordinating worker
+func (p *JobProcessor) processChunkJob(ctx context.Context, job *queue.ChunkJob) error {
+	ctx, logger := jobContext(ctx, "chunk", job.ID, job.MovieID, job.Trace,
+		"version", job.Version, "chunk", job.Index+1, "chunks", job.Total)
+
+	// Chunks of an encode that already failed are dropped
+	progress, err := p.queueService.GetChunkProgress(ctx, job.MovieID, job.Version)
+	if err != nil {
+		return err
+	}
+	if progress.Failure != "" {
+		logger.Info().Msg("Skipping chunk, the version already failed")
+		return nil
+	}
+
+	logger.Info().Msg("Encoding chunk")
+	err = p.transcodingService.EncodeChunk(ctx, transcoding.ChunkTask{
+		MovieID:          job.MovieID,
+		Version:          job.Version,
+		Index:            job.Index,
+		SourceObject:     job.SourceObject,
+		ProfileSet:       job.ProfileSet,
+		ComplexityFactor: job.ComplexityFactor,
+		ToneMap:          job.ToneMap,
+		SourceHeight:     job.SourceHeight,
+		SourceKbps:       job.SourceKbps,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// handleJob puts the chunk back for another worker, this worker is shutting down
+			return ctx.Err()
+		}
+
+		if job.Attempt+1 < maxChunkAttempts {
+			logger.Warn().Err(err).Int("attempt", job.Attempt+1).Msg("Chunk encoding failed, retrying")
+			retry := *job
+			retry.Attempt++
+			return p.queueService.PublishChunkJob(ctx, retry)
+		}
+
+		logger.Error().Err(err).Msg("Chunk encoding FAILED")
+		return p.queueService.MarkChunkFailed(ctx, job.MovieID, job.Version, job.Index, err.Error())
+	}
+
+	return p.queueService.MarkChunkDone(ctx, job.MovieID, job.Version, job.Index)
+}
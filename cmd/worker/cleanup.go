@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Movies looked up per query
+	cleanupBatchSize = 500
+	// A work directory without a change for this long belongs to no running transcode
+	staleWorkDirAge = time.Hour
+)
+
+// Why a raw source is removed
+const (
+	cleanupMovieDeleted = "movie_deleted"
+	cleanupTranscoded   = "transcoded"
+	cleanupOrphaned     = "orphaned"
+)
+
+// RawCleaner removes the uploaded sources that are no longer needed every interval: sources of movies
+// deleted or transcoded and READY before the retention period, and sources no movie refers to anymore,
+// e.g. of a failed or replaced upload. Multipart uploads that were never completed are aborted.
+// Only objects older than the retention period are touched. A dry run only logs what would be removed.
+type RawCleaner struct {
+	movieRepo *movieRepository.MovieRepository
+	client    *minio.Client
+	bucket    string
+	retention time.Duration
+	interval  time.Duration
+	dryRun    bool
+}
+
+// NewRawCleaner creates a new raw source cleaner
+func NewRawCleaner(movieRepo *movieRepository.MovieRepository, client *minio.Client, bucket string, retention, interval time.Duration, dryRun bool) *RawCleaner {
+	return &RawCleaner{
+		movieRepo: movieRepo,
+		client:    client,
+		bucket:    bucket,
+		retention: retention,
+		interval:  interval,
+		dryRun:    dryRun,
+	}
+}
+
+// Start runs the cleaner until the context is cancelled, the first run starts right away
+func (c *RawCleaner) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "raw_cleaner")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Dur("interval", c.interval).Dur("retention", c.retention).Bool("dry_run", c.dryRun).Msg("Raw source cleaner started")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.clean(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Raw source cleaner received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *RawCleaner) clean(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	cutoff := time.Now().Add(-c.retention)
+
+	// Sources old enough to be removed, by movie
+	candidates := make(map[int64][]string)
+	for object := range c.client.ListObjects(ctx, c.bucket, minio.ListObjectsOptions{Prefix: storage.RawVideoPrefix, Recursive: true}) {
+		if object.Err != nil {
+			logger.Error().Err(object.Err).Msg("Failed to list raw sources")
+			return
+		}
+		movieID, ok := rawSourceMovieID(object.Key)
+		if ok && object.LastModified.Before(cutoff) {
+			candidates[movieID] = append(candidates[movieID], object.Key)
+		}
+	}
+
+	removed := make(map[string]int)
+	movieIDs := make([]int64, 0, len(candidates))
+	for movieID := range candidates {
+		movieIDs = append(movieIDs, movieID)
+	}
+	for start := 0; start < len(movieIDs) && ctx.Err() == nil; start += cleanupBatchSize {
+		batch := movieIDs[start:min(start+cleanupBatchSize, len(movieIDs))]
+		sources, err := c.movieRepo.FindRawSources(ctx, batch)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to find raw sources")
+			return
+		}
+		byMovie := make(map[int64]movies.RawSource, len(sources))
+		for _, source := range sources {
+			byMovie[source.MovieID] = source
+		}
+
+		for _, movieID := range batch {
+			source, found := byMovie[movieID]
+			for _, key := range candidates[movieID] {
+				reason := rawSourceCleanupReason(source, found, key, cutoff)
+				if reason == "" {
+					continue
+				}
+				if c.remove(ctx, movieID, key, reason) {
+					removed[reason]++
+				}
+			}
+		}
+	}
+
+	aborted := c.abortIncompleteUploads(ctx, cutoff)
+
+	if len(removed) > 0 || aborted > 0 {
+		logger.Info().
+			Bool("dry_run", c.dryRun).
+			Int(cleanupMovieDeleted, removed[cleanupMovieDeleted]).
+			Int(cleanupTranscoded, removed[cleanupTranscoded]).
+			Int(cleanupOrphaned, removed[cleanupOrphaned]).
+			Int("incomplete_uploads", aborted).
+			Msg("Cleaned up raw sources")
+	}
+}
+
+// remove deletes a source, in a dry run it only logs it
+func (c *RawCleaner) remove(ctx context.Context, movieID int64, key, reason string) bool {
+	logger := zerolog.Ctx(ctx)
+	if c.dryRun {
+		logger.Info().Int64("movie_id", movieID).Str("object", key).Str("reason", reason).Msg("Raw source would be removed")
+		return true
+	}
+	if err := c.client.RemoveObject(ctx, c.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		logger.Error().Err(err).Int64("movie_id", movieID).Str("object", key).Msg("Failed to remove raw source")
+		return false
+	}
+	logger.Debug().Int64("movie_id", movieID).Str("object", key).Str("reason", reason).Msg("Removed raw source")
+	return true
+}
+
+// abortIncompleteUploads aborts the multipart uploads of sources started before the cutoff, returning how many
+func (c *RawCleaner) abortIncompleteUploads(ctx context.Context, cutoff time.Time) int {
+	logger := zerolog.Ctx(ctx)
+	core := minio.Core{Client: c.client}
+	aborted := 0
+	for upload := range c.client.ListIncompleteUploads(ctx, c.bucket, storage.RawVideoPrefix, true) {
+		if upload.Err != nil {
+			logger.Error().Err(upload.Err).Msg("Failed to list incomplete uploads")
+			break
+		}
+		if !upload.Initiated.Before(cutoff) {
+			continue
+		}
+		if !c.dryRun {
+			if err := core.AbortMultipartUpload(ctx, c.bucket, upload.Key, upload.UploadID); err != nil {
+				logger.Error().Err(err).Str("object", upload.Key).Msg("Failed to abort incomplete upload")
+				continue
+			}
+		}
+		aborted++
+	}
+	return aborted
+}
+
+// rawSourceCleanupReason returns why a source older than the cutoff can go, empty when it is still needed.
+// FAILED and unfinished uploads keep the source they refer to, so the transcode can be retried.
+func rawSourceCleanupReason(source movies.RawSource, found bool, key string, cutoff time.Time) string {
+	switch {
+	case !found || (source.DeletedAt != nil && source.DeletedAt.Before(cutoff)):
+		return cleanupMovieDeleted
+	case source.DeletedAt != nil:
+		return ""
+	case source.RawFilePath == nil || *source.RawFilePath != key:
+		return cleanupOrphaned
+	case source.UploadStatus != nil && *source.UploadStatus == movies.UploadStatusReady &&
+		source.ProcessedAt != nil && source.ProcessedAt.Before(cutoff):
+		return cleanupTranscoded
+	default:
+		return ""
+	}
+}
+
+// rawSourceMovieID parses the movie of a raw-videos/movie-{id}.ext key
+func rawSourceMovieID(key string) (int64, bool) {
+	name := strings.TrimPrefix(key, storage.RawVideoPrefix+"movie-")
+	if name == key || strings.Contains(name, "/") {
+		return 0, false
+	}
+	movieID, err := strconv.ParseInt(strings.TrimSuffix(name, filepath.Ext(name)), 10, 64)
+	return movieID, err == nil && movieID > 0
+}
+
+// clearStaleWorkDirs removes the movie-* work directories interrupted transcodes left in dir.
+// Directories changed within staleWorkDirAge may belong to another worker on the same host and are kept.
+func clearStaleWorkDirs(ctx context.Context, dir string) {
+	logger := zerolog.Ctx(ctx)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn().Err(err).Str("dir", dir).Msg("Failed to list transcoding work directories")
+		}
+		return
+	}
+
+	cutoff := time.Now().Add(-staleWorkDirAge)
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "movie-") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if lastChange(path).After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			logger.Warn().Err(err).Str("dir", path).Msg("Failed to remove stale transcoding work directory")
+			continue
+		}
+		logger.Info().Str("dir", path).Msg("Removed stale transcoding work directory")
+	}
+}
+
+// lastChange returns the latest modification time of a directory and everything in it
+func lastChange(dir string) time.Time {
+	var latest time.Time
+	filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/rs/zerolog"
+)
+
+// scheduledPollInterval is how often delayed jobs and retries that are due are moved to their queues
+const scheduledPollInterval = time.Second
+
+// promoteBatchSize bounds how many due jobs of a queue one poll moves
+const promoteBatchSize = 100
+
+// JobHandler runs a job of a named queue. An error retries the job while it has attempts left.
+type JobHandler func(ctx context.Context, job *queue.Job) error
+
+// decoded returns a handler that decodes the payload of the job before running it
+func decoded[T any](run func(ctx context.Context, payload *T) error) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		var payload T
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		return run(ctx, &payload)
+	}
+}
+
+// jobHandlers returns the handler of every job type of the named queues
+func (p *JobProcessor) jobHandlers() map[string]JobHandler {
+	return map[string]JobHandler{
+		queue.JobTypeChunk:        decoded(p.processChunkJob),
+		queue.JobTypeAudio:        decoded(p.processAudioJob),
+		queue.JobTypeNotification: decoded(p.processNotification),
+	}
+}
+
+// QueueConsumers run the jobs of the named queues, each queue with its own number of parallel consumers.
+// Transcoding jobs are consumed by the JobProcessor.
+type QueueConsumers struct {
+	processor *JobProcessor
+
+	mu          sync.Mutex
+	ctx         context.Context            // set by Start
+	concurrency map[string]int             // per queue, queues missing or at 0 are left to other workers
+	running     map[string][]chan struct{} // closing a channel stops its consumer after the current job
+	wg          sync.WaitGroup
+}
+
+// NewQueueConsumers creates the consumers of the named queues
+func NewQueueConsumers(processor *JobProcessor, concurrency map[string]int) *QueueConsumers {
+	return &QueueConsumers{processor: processor, concurrency: concurrency, running: make(map[string][]chan struct{})}
+}
+
+// Start runs the consumers until the context is cancelled and their jobs are put back
+func (q *QueueConsumers) Start(ctx context.Context) {
+	q.mu.Lock()
+	q.ctx = ctx
+	q.scale()
+	q.mu.Unlock()
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.promote(componentContext(ctx, "queue_scheduler"))
+	}()
+
+	<-ctx.Done()
+	q.wg.Wait()
+}
+
+// promote moves delayed jobs and retries to their queues once they are due, every worker does so
+func (q *QueueConsumers) promote(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	ticker := time.NewTicker(scheduledPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		moved, err := q.processor.queueService.PromoteScheduledJobs(ctx, promoteBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error().Err(err).Msg("Failed to promote scheduled jobs")
+			}
+			continue
+		}
+		if moved > 0 {
+			logger.Debug().Int("jobs", moved).Msg("Promoted scheduled jobs")
+		}
+	}
+}
+
+// Resize changes the number of consumers per queue, e.g. after a config reload.
+// Consumers that are no longer needed stop once their current job is done.
+func (q *QueueConsumers) Resize(concurrency map[string]int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.concurrency = concurrency
+	if q.ctx != nil {
+		q.scale()
+	}
+}
+
+// scale starts or stops consumers until every queue has as many as configured, q.mu must be held
+func (q *QueueConsumers) scale() {
+	if q.ctx.Err() != nil {
+		return
+	}
+	names := make(map[string]bool)
+	for name := range q.concurrency {
+		names[name] = true
+	}
+	for name := range q.running {
+		names[name] = true
+	}
+
+	for name := range names {
+		want := q.concurrency[name]
+		if want < 0 {
+			want = 0
+		}
+		running := q.running[name]
+		if want == len(running) {
+			continue
+		}
+
+		queueCtx := componentContext(q.ctx, "queue_"+name)
+		for len(running) < want {
+			stop := make(chan struct{})
+			running = append(running, stop)
+			q.wg.Add(1)
+			go func() {
+				defer q.wg.Done()
+				q.consume(queueCtx, name, stop)
+			}()
+		}
+		for len(running) > want {
+			close(running[len(running)-1])
+			running = running[:len(running)-1]
+		}
+		q.running[name] = running
+		zerolog.Ctx(queueCtx).Info().Int("consumers", want).Msg("Queue consumers set")
+	}
+}
+
+// consume runs the jobs of one queue one after another until stop is closed or the context is cancelled
+func (q *QueueConsumers) consume(ctx context.Context, name string, stop <-chan struct{}) {
+	logger := zerolog.Ctx(ctx)
+	for ctx.Err() == nil {
+		select {
+		case <-stop:
+			logger.Info().Msg("Queue consumer stopped")
+			return
+		default:
+		}
+
+		// Blocking with a timeout lets the loop notice a shutdown
+		job, err := q.processor.queueService.PopJob(ctx, name, 5*time.Second)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			logger.Error().Err(err).Msg("Error consuming job")
+			continue
+		}
+		if job == nil {
+			continue
+		}
+		if err := q.processor.handleJob(ctx, job); err != nil && ctx.Err() == nil {
+			logger.Error().Err(err).Str("job_type", job.Type).Msg("Error processing job")
+		}
+	}
+	logger.Info().Msg("Queue consumer received shutdown signal")
+}
+
+// handleJob runs a job of a named queue with the handler of its type, jobs of unknown types are dropped.
+// A job interrupted by the shutdown is put back for another worker, a failed job is retried with a backoff
+// while it has attempts left and then kept in the dead letter list of its queue.
+func (p *JobProcessor) handleJob(ctx context.Context, job *queue.Job) error {
+	logger := zerolog.Ctx(ctx).With().Str("job_type", job.Type).Str("job_id", job.ID).Logger()
+
+	handler, ok := p.handlers[job.Type]
+	if !ok {
+		logger.Warn().Msg("Dropping job of unknown type")
+		return nil
+	}
+
+	err := handler(ctx, job)
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		if reqErr := p.queueService.Requeue(context.WithoutCancel(ctx), *job); reqErr != nil {
+			logger.Error().Err(reqErr).Msg("Failed to requeue job")
+		}
+		return err
+	}
+
+	retried, retryErr := p.queueService.Retry(ctx, *job, err)
+	if retryErr != nil {
+		logger.Error().Err(retryErr).Msg("Failed to retry job")
+		return err
+	}
+	if retried {
+		logger.Warn().Err(err).Int("attempt", job.Attempt+1).Int("max_attempts", job.MaxAttempts).Msg("Job failed, retrying")
+		return nil
+	}
+	return err
+}
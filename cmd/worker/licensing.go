@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	"github.com/rs/zerolog"
+)
+
+// LicenseEnforcer unpublishes published titles once all their licensing windows closed.
+// Admins publishing such a title again are overruled on the next run until a new window is added.
+type LicenseEnforcer struct {
+	movieRepo *movieRepository.MovieRepository
+	interval  time.Duration
+}
+
+// NewLicenseEnforcer creates a new license enforcer
+func NewLicenseEnforcer(movieRepo *movieRepository.MovieRepository, interval time.Duration) *LicenseEnforcer {
+	return &LicenseEnforcer{
+		movieRepo: movieRepo,
+		interval:  interval,
+	}
+}
+
+// Start runs the enforcer until the context is cancelled
+func (e *LicenseEnforcer) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "license_enforcer")
+	zerolog.Ctx(ctx).Info().Dur("interval", e.interval).Msg("License enforcer started")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.enforce(ctx)
+
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("License enforcer received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforce unpublishes the titles whose windows closed since the last run
+func (e *LicenseEnforcer) enforce(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	movieIDs, err := e.movieRepo.FindMoviesWithClosedLicenses(ctx, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to find closed licenses")
+		return
+	}
+
+	for _, movieID := range movieIDs {
+		unpublished, err := e.movieRepo.SetMoviePublished(ctx, movieID, false)
+		if err != nil {
+			logger.Error().Err(err).Int64("movie_id", movieID).Msg("Failed to unpublish movie")
+			continue
+		}
+		if !unpublished {
+			continue
+		}
+
+		logger.Info().Int64("movie_id", movieID).Msg("Unpublished movie, all licensing windows closed")
+		if err := e.movieRepo.RecordCatalogChanges(ctx, movieID, movies.CatalogChangeVisibility); err != nil {
+			logger.Error().Err(err).Int64("movie_id", movieID).Msg("Failed to record catalog change")
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"github.com/martinmanurung/cinestream/pkg/tracing"
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// componentContext gives a background loop its own logger, the services it calls log through the context
+func componentContext(ctx context.Context, component string) context.Context {
+	return zlog.With().Str("component", component).Logger().WithContext(ctx)
+}
+
+// jobContext carries a queued job's logger and trace, every entry of the job and of ffmpeg
+// can be found by its movie_id and job_id and joined with the request that enqueued it.
+// Extra fields are key/value pairs.
+func jobContext(ctx context.Context, kind, jobID string, movieID int64, trace *tracing.Context, extra ...interface{}) (context.Context, *zerolog.Logger) {
+	fields := zlog.With().
+		Str("component", "processor").
+		Str("job_type", kind).
+		Int64("movie_id", movieID).
+		Fields(extra)
+	if jobID != "" {
+		fields = fields.Str("job_id", jobID)
+	}
+	if trace != nil {
+		ctx = tracing.NewContext(ctx, *trace)
+		fields = fields.Str("trace_id", trace.TraceID()).Str("request_id", trace.RequestID)
+	}
+
+	logger := fields.Logger()
+	return logger.WithContext(ctx), &logger
+}
@@ -2,45 +2,68 @@ package main
 
 import (
 	"context"
-	"log"
+	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
 	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
+	userRepository "github.com/martinmanurung/cinestream/internal/domain/users/repository"
+	"github.com/martinmanurung/cinestream/internal/platform/cache"
+	"github.com/martinmanurung/cinestream/internal/platform/cdn"
 	"github.com/martinmanurung/cinestream/internal/platform/config"
 	"github.com/martinmanurung/cinestream/internal/platform/database"
+	"github.com/martinmanurung/cinestream/internal/platform/health"
+	"github.com/martinmanurung/cinestream/internal/platform/logging"
+	"github.com/martinmanurung/cinestream/internal/platform/mailer"
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	"github.com/martinmanurung/cinestream/internal/platform/payment"
 	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/internal/platform/retention"
 	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/internal/platform/stt"
 	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/martinmanurung/cinestream/internal/platform/views"
 	"github.com/redis/go-redis/v9"
-	"github.com/rs/zerolog"
 	zlog "github.com/rs/zerolog/log"
 )
 
 func main() {
-	// Setup zerolog
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	zlog.Logger = zlog.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
-
-	zlog.Info().Msg("Starting CineStream Transcoding Worker...")
-
-	// Load configuration
-	cfg, err := config.LoadConfig()
+	// Load configuration, from the file and CINESTREAM_* environment variables
+	configPath := flag.String("config", "", "path of the YAML config file, defaults to $"+config.PathEnv+" or ./app-config.yaml")
+	check := flag.Bool("check", false, "check the config and every dependency, print a pass/fail report and exit")
+	runBackup := flag.Bool("backup", false, "write a backup to the backup bucket and exit")
+	restore := flag.String("restore", "", "restore the tables of a backup archive, e.g. backups/20260101T020000Z.tar.gz.enc, and exit")
+	confirm := flag.Bool("yes", false, "with --restore, replace the rows of the archived tables instead of only checking the archive")
+	flag.Parse()
+	if *check {
+		os.Exit(runSelfTest(*configPath))
+	}
+	if *runBackup || *restore != "" {
+		os.Exit(runBackupCommand(*configPath, *restore, *confirm))
+	}
+	cfg, err := config.LoadConfig(*configPath, "worker")
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to load config")
 	}
 
+	// Log level and format are shared with the API
+	logging.Setup(cfg.Log, "worker")
+	zlog.Info().Str("config_file", cfg.File).Msg("Starting CineStream Transcoding Worker...")
+
 	// Initialize database
 	db, err := database.InitMySQL(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to initialize database")
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Failed to get database instance: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to get database instance")
 	}
 	defer sqlDB.Close()
 
@@ -49,7 +72,7 @@ func main() {
 	// Initialize MinIO
 	minioClient, err := storage.InitMinIO(cfg.MinIO)
 	if err != nil {
-		log.Fatalf("Failed to initialize MinIO: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to initialize MinIO")
 	}
 	zlog.Info().Msg("MinIO initialized successfully")
 
@@ -63,30 +86,294 @@ func main() {
 
 	// Ping Redis to verify connection
 	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		zlog.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
 	defer redisClient.Close()
 	zlog.Info().Msg("Redis initialized successfully")
 
+	// Operators can add ladders without a rebuild, a broken entry stops the worker at startup
+	if err := transcoding.ConfigureProfileSets(cfg.Transcoding); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to load transcoding profiles")
+	}
+
+	// The encoder is tested before any job is accepted, a configured GPU that does not work stops the worker
+	encoder, err := transcoding.ConfigureEncoder(ctx, cfg.Transcoding.Encoder, cfg.Transcoding.VAAPIDevice)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to configure video encoder")
+	}
+	zlog.Info().Str("encoder", encoder).Msg("Video encoder passed the self-test")
+	transcoding.EnableDASH(cfg.Transcoding.DASHEnabled)
+
 	// Initialize services
 	queueService := queue.NewRedisQueue(redisClient)
+	if err := queueService.SetRoutes(cfg.Queue.Routes); err != nil {
+		zlog.Fatal().Err(err).Msg("Invalid queue routes")
+	}
+	claimIdle, err := time.ParseDuration(cfg.Queue.ClaimIdle)
+	if err != nil || claimIdle <= 0 {
+		claimIdle = 5 * time.Minute
+	}
+	hostname, _ := os.Hostname()
+	consumer := queue.ConsumerOptions{Name: fmt.Sprintf("%s-%d", hostname, os.Getpid()), ClaimIdle: claimIdle}
+	if err := queueService.SetupConsumer(ctx, consumer); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to join the transcoding consumer group")
+	}
+	zlog.Info().Str("consumer", consumer.Name).Dur("claim_idle", claimIdle).Msg("Joined the transcoding consumer group")
 	transcodingService := transcoding.NewTranscodingService(minioClient, cfg.MinIO.BucketRaw, cfg.MinIO.BucketProcessed)
 
 	// Initialize repository
+	// Transcoding results and the change log drop the API's catalog cache
 	movieRepo := movieRepository.NewMovieRepository(db)
+	if cfg.Cache.Enabled {
+		catalogTTL, err := time.ParseDuration(cfg.Cache.CatalogTTL)
+		if err != nil || catalogTTL <= 0 {
+			catalogTTL = 5 * time.Minute
+		}
+		movieRepo.WithCatalogCache(cache.New(redisClient, "catalog", catalogTTL))
+	}
+
+	// Long sources can be split across all running workers
+	chunking := ChunkingOptions{Enabled: cfg.Transcoding.ChunkedEnabled}
+	if chunking.MinDuration, err = time.ParseDuration(cfg.Transcoding.ChunkedMinDuration); err != nil || chunking.MinDuration <= 0 {
+		chunking.MinDuration = 45 * time.Minute
+	}
+	if chunking.ChunkLength, err = time.ParseDuration(cfg.Transcoding.ChunkDuration); err != nil || chunking.ChunkLength <= 0 {
+		chunking.ChunkLength = 5 * time.Minute
+	}
+	if chunking.WaitTimeout, err = time.ParseDuration(cfg.Transcoding.ChunkWaitTimeout); err != nil || chunking.WaitTimeout <= 0 {
+		chunking.WaitTimeout = 6 * time.Hour
+	}
+
+	// Draft subtitles are optional, a configured but missing tool stops the worker at startup
+	transcriber, err := stt.New(cfg.SpeechToText)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to configure speech-to-text")
+	}
+	if transcriber != nil {
+		zlog.Info().Str("provider", transcriber.Name()).Msg("Speech-to-text subtitle drafts enabled")
+	}
+	speech := SpeechOptions{Transcriber: transcriber, Language: cfg.SpeechToText.Language}
+
+	// Notifications queued by the API and the worker are sent from the notifications queue
+	var sender notification.Sender = notification.NewLogSender(zlog.Logger)
+	if cfg.Notifications.Sender == "smtp" {
+		sender = mailer.NewMailer(cfg.Mail.Host, cfg.Mail.Port, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.From)
+	}
+	notifier := notification.NewNotifier(queueService)
+	notifications := NotificationOptions{Dispatcher: notification.NewDispatcher(sender), Notifier: notifier}
 
 	// Create job processor
-	processor := NewJobProcessor(db, queueService, transcodingService, movieRepo)
+	processor := NewJobProcessor(db, queueService, transcodingService, movieRepo, chunking, cfg.Transcoding.EncryptHLS, speech, notifications, cfg.Queue.MaxRetries)
 
 	// Create context with cancellation for graceful shutdown
 	workerCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Orders and payment gateways of the reaper and the settlement reconciler
+	orderRepo := orderRepository.NewOrderRepository(db)
+	payments, err := payment.NewRegistryFromConfig(cfg.PaymentGW)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to initialize payment gateway")
+	}
+
+	// Start expiring unpaid orders in the background
+	if cfg.Orders.ReaperEnabled {
+		interval, err := time.ParseDuration(cfg.Orders.ReaperInterval)
+		if err != nil || interval <= 0 {
+			interval = time.Minute
+		}
+		batchSize := cfg.Orders.ReaperBatchSize
+		if batchSize <= 0 {
+			batchSize = 100
+		}
+
+		reaper := NewOrderReaper(orderRepo, payments, interval, batchSize, cfg.Orders.CancelOnGateway)
+		go reaper.Start(workerCtx)
+	}
+
+	// Start reconciling each day's paid orders against the gateway settlements
+	if cfg.Orders.SettlementEnabled {
+		interval, err := time.ParseDuration(cfg.Orders.SettlementInterval)
+		if err != nil || interval <= 0 {
+			interval = time.Hour
+		}
+		delay, err := time.ParseDuration(cfg.Orders.SettlementDelay)
+		if err != nil || delay < 0 {
+			delay = 6 * time.Hour
+		}
+
+		reconciler := NewSettlementReconciler(orderRepo, payments, interval, delay)
+		go reconciler.Start(workerCtx)
+	}
+
+	// Start reminding viewers whose access ends soon
+	if cfg.Notifications.ExpiryReminderEnabled {
+		before, err := time.ParseDuration(cfg.Notifications.ExpiryReminderBefore)
+		if err != nil || before <= 0 {
+			before = 24 * time.Hour
+		}
+		interval, err := time.ParseDuration(cfg.Notifications.ExpiryReminderInterval)
+		if err != nil || interval <= 0 {
+			interval = 15 * time.Minute
+		}
+
+		reminder := NewExpiryReminder(orderRepo, notifier, before, interval)
+		go reminder.Start(workerCtx)
+	}
+
+	// Start purging the CDN from the catalog change log
+	dispatchInterval, err := time.ParseDuration(cfg.CDN.DispatchInterval)
+	if err != nil || dispatchInterval <= 0 {
+		dispatchInterval = 10 * time.Second
+	}
+	dispatchBatchSize := cfg.CDN.DispatchBatchSize
+	if dispatchBatchSize <= 0 {
+		dispatchBatchSize = 100
+	}
+	maxAttempts := cfg.CDN.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	purger := cdn.NewPurger(cfg.CDN.PurgeURL, cfg.CDN.PurgeToken)
+	dispatcher := NewCatalogChangeDispatcher(movieRepo, purger, dispatchInterval, dispatchBatchSize, maxAttempts)
+	go dispatcher.Start(workerCtx)
+
+	// Start unpublishing titles whose licensing windows all closed
+	enforceInterval, err := time.ParseDuration(cfg.Licensing.EnforceInterval)
+	if err != nil || enforceInterval <= 0 {
+		enforceInterval = 15 * time.Minute
+	}
+	licenseEnforcer := NewLicenseEnforcer(movieRepo, enforceInterval)
+	go licenseEnforcer.Start(workerCtx)
+
+	// Start moving playback counters from Redis to MySQL
+	viewFlushInterval, err := time.ParseDuration(cfg.Views.FlushInterval)
+	if err != nil || viewFlushInterval <= 0 {
+		viewFlushInterval = time.Minute
+	}
+	viewFlusher := NewViewFlusher(views.NewCounter(redisClient), movieRepo, viewFlushInterval)
+	go viewFlusher.Start(workerCtx)
+
+	// Start recomputing the popularity scores behind sort=popular and the trending movies
+	popularityInterval, err := time.ParseDuration(cfg.Popularity.Interval)
+	if err != nil || popularityInterval <= 0 {
+		popularityInterval = 15 * time.Minute
+	}
+	popularityWindowDays := cfg.Popularity.WindowDays
+	if popularityWindowDays <= 0 {
+		popularityWindowDays = 14
+	}
+	popularityHalfLifeDays := cfg.Popularity.HalfLifeDays
+	if popularityHalfLifeDays <= 0 {
+		popularityHalfLifeDays = 3
+	}
+	popularityWeights := movies.PopularityWeights{
+		View:       cfg.Popularity.ViewWeight,
+		Completion: cfg.Popularity.CompletionWeight,
+		Order:      cfg.Popularity.OrderWeight,
+		Rating:     cfg.Popularity.RatingWeight,
+	}
+	popularityScorer := NewPopularityScorer(movieRepo, popularityWeights, popularityWindowDays, popularityHalfLifeDays, popularityInterval)
+	go popularityScorer.Start(workerCtx)
+
+	// Start deleting expired refresh tokens, the API already refuses them
+	refreshTokenIdleExpiry, err := time.ParseDuration(cfg.JWT.RefreshTokenIdleExpiry)
+	if err != nil || refreshTokenIdleExpiry <= 0 {
+		refreshTokenIdleExpiry = 30 * 24 * time.Hour
+	}
+	refreshTokenPurgeInterval, err := time.ParseDuration(cfg.JWT.RefreshTokenPurgeInterval)
+	if err != nil || refreshTokenPurgeInterval <= 0 {
+		refreshTokenPurgeInterval = time.Hour
+	}
+	tokenPurger := NewRefreshTokenPurger(userRepository.NewUser(db), refreshTokenIdleExpiry, refreshTokenPurgeInterval)
+	go tokenPurger.Start(workerCtx)
+
+	// Start deleting and anonymizing data past its retention period
+	if cfg.Retention.Enabled {
+		rules := make([]retention.Rule, len(cfg.Retention.Rules))
+		for i, rule := range cfg.Retention.Rules {
+			rules[i] = retention.Rule{
+				Target: rule.Target,
+				After:  time.Duration(rule.AfterDays) * 24 * time.Hour,
+				DryRun: rule.DryRun,
+			}
+		}
+		if err := retention.ValidateRules(rules); err != nil {
+			zlog.Fatal().Err(err).Msg("Invalid retention rules")
+		}
+		interval, err := time.ParseDuration(cfg.Retention.Interval)
+		if err != nil || interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		batchSize := cfg.Retention.BatchSize
+		if batchSize <= 0 {
+			batchSize = 1000
+		}
+
+		retentionScheduler := NewRetentionScheduler(retention.NewEngine(db, rules, batchSize), interval)
+		go retentionScheduler.Start(workerCtx)
+	}
+
+	// Start removing the uploaded sources no movie needs anymore
+	if cfg.Cleanup.Enabled {
+		interval, err := time.ParseDuration(cfg.Cleanup.Interval)
+		if err != nil || interval <= 0 {
+			interval = 6 * time.Hour
+		}
+		retentionPeriod := time.Duration(cfg.Cleanup.RawRetentionDays) * 24 * time.Hour
+
+		rawCleaner := NewRawCleaner(movieRepo, minioClient, cfg.MinIO.BucketRaw, retentionPeriod, interval, cfg.Cleanup.DryRun)
+		go rawCleaner.Start(workerCtx)
+	}
+
+	// Start writing the daily backup of the critical tables and the stored originals
+	if cfg.Backup.Enabled {
+		backupEngine, err := newBackupEngine(cfg, db, minioClient)
+		if err != nil {
+			zlog.Fatal().Err(err).Msg("Failed to configure backups")
+		}
+		// Validated as HH:MM
+		at, _ := time.Parse("15:04", cfg.Backup.Time)
+		backupScheduler := NewBackupScheduler(backupEngine, redisClient, time.Duration(at.Hour())*time.Hour+time.Duration(at.Minute())*time.Minute)
+		go backupScheduler.Start(workerCtx)
+	}
+
+	// Probe listener, the worker has no HTTP server of its own
+	if cfg.Health.WorkerPort != "" {
+		healthTimeout, err := time.ParseDuration(cfg.Health.Timeout)
+		if err != nil || healthTimeout <= 0 {
+			healthTimeout = 2 * time.Second
+		}
+		healthChecker := health.NewChecker(healthTimeout)
+		healthChecker.Add("mysql", health.MySQL(sqlDB))
+		healthChecker.Add("redis", health.Redis(redisClient))
+		healthChecker.Add("minio", health.MinIO(minioClient, cfg.MinIO.BucketRaw, cfg.MinIO.BucketProcessed))
+
+		go func() {
+			zlog.Info().Str("port", cfg.Health.WorkerPort).Msg("Starting health listener")
+			if err := health.Serve(workerCtx, ":"+cfg.Health.WorkerPort, healthChecker); err != nil {
+				zlog.Error().Err(err).Msg("Health listener stopped")
+			}
+		}()
+	}
+
+	// Transcodes interrupted by a crash or a kill leave their work directories behind
+	clearStaleWorkDirs(componentContext(workerCtx, "processor"), transcoding.TempDir)
+
 	// Start processing jobs in a goroutine
 	processorDone := make(chan error, 1)
 	go func() {
 		processorDone <- processor.Start(workerCtx)
 	}()
+	consumers := NewQueueConsumers(processor, cfg.Queue.Consumers)
+	consumersDone := make(chan struct{})
+	go func() {
+		consumers.Start(workerCtx)
+		close(consumersDone)
+	}()
+
+	// Operational settings apply without a restart when the config file changes
+	watchConfig(cfg, consumers)
 
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -97,7 +384,8 @@ func main() {
 		zlog.Info().Msg("Received shutdown signal, stopping worker...")
 		cancel() // Cancel the processor context
 
-		// Wait for processor to finish with timeout
+		// Wait for processor and queue consumers to finish with timeout
+		deadline := time.Now().Add(30 * time.Second)
 		select {
 		case err := <-processorDone:
 			if err != nil && err != context.Canceled {
@@ -105,9 +393,14 @@ func main() {
 			} else {
 				zlog.Info().Msg("Worker stopped gracefully")
 			}
-		case <-time.After(30 * time.Second):
+		case <-time.After(time.Until(deadline)):
 			zlog.Warn().Msg("Worker shutdown timeout, forcing exit")
 		}
+		select {
+		case <-consumersDone:
+		case <-time.After(time.Until(deadline)):
+			zlog.Warn().Msg("Queue consumers shutdown timeout, forcing exit")
+		}
 	case err := <-processorDone:
 		if err != nil {
 			zlog.Fatal().Err(err).Msg("Worker stopped with error")
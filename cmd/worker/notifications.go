@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	"github.com/rs/zerolog"
+)
+
+// NotificationOptions sends the jobs of the notifications queue and queues the worker's own notifications
+type NotificationOptions struct {
+	Dispatcher *notification.Dispatcher
+	Notifier   *notification.Notifier
+}
+
+// processNotification renders and sends a queued notification,
+// a failed send is retried by handleJob while the notification has attempts left
+func (p *JobProcessor) processNotification(ctx context.Context, event *notification.Event) error {
+	logger := zerolog.Ctx(ctx).With().Str("notification_id", event.ID).Str("notification_type", event.Type).Logger()
+	if err := p.notifications.Dispatcher.Deliver(ctx, *event); err != nil {
+		return err
+	}
+	logger.Info().Int("recipients", len(event.To)).Msg("Notification sent")
+	return nil
+}
+
+// notifyTranscodingCompleted tells the admin who uploaded the video that the movie is ready to stream
+func (p *JobProcessor) notifyTranscodingCompleted(ctx context.Context, movieID int64) {
+	logger := zerolog.Ctx(ctx)
+	uploader, err := p.movieRepo.FindVideoUploader(ctx, movieID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to find the uploader to notify")
+		return
+	}
+	if uploader == nil {
+		return
+	}
+
+	err = p.notifications.Notifier.Notify(ctx, notification.Event{
+		Type: notification.EventTranscodingCompleted,
+		To:   []string{uploader.Email},
+		Data: map[string]string{
+			"name":        uploader.Name,
+			"movie_title": uploader.MovieTitle,
+			"movie_id":    strconv.FormatInt(movieID, 10),
+		},
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to queue transcoding completed notification")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	"github.com/rs/zerolog"
+)
+
+// PopularityScorer recomputes the popularity score of every movie from its recent activity
+type PopularityScorer struct {
+	movieRepo    *movieRepository.MovieRepository
+	weights      movies.PopularityWeights
+	windowDays   int
+	halfLifeDays float64
+	interval     time.Duration
+}
+
+// NewPopularityScorer creates a new popularity scorer
+func NewPopularityScorer(movieRepo *movieRepository.MovieRepository, weights movies.PopularityWeights, windowDays int, halfLifeDays float64, interval time.Duration) *PopularityScorer {
+	return &PopularityScorer{
+		movieRepo:    movieRepo,
+		weights:      weights,
+		windowDays:   windowDays,
+		halfLifeDays: halfLifeDays,
+		interval:     interval,
+	}
+}
+
+// Start runs the scorer until the context is cancelled, the first run starts right away
+func (s *PopularityScorer) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "popularity_scorer")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Dur("interval", s.interval).Int("window_days", s.windowDays).Msg("Popularity scorer started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.run(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to recompute popularity scores")
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Popularity scorer received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PopularityScorer) run(ctx context.Context) error {
+	// Activity is counted per UTC day, today counts as one of the days
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(s.windowDays - 1))
+
+	signals, err := s.movieRepo.FindPopularitySignals(ctx, since)
+	if err != nil {
+		return err
+	}
+	ratings, err := s.movieRepo.FindAverageEditorialScores(ctx)
+	if err != nil {
+		return err
+	}
+
+	scores := movies.PopularityScores(signals, ratings, s.weights, today, s.halfLifeDays)
+	changed, err := s.movieRepo.UpdatePopularityScores(ctx, scores)
+	if err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Int("scored", len(scores)).Int64("changed", changed).Msg("Popularity scores recomputed")
+	return nil
+}
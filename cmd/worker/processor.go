@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"path"
+	"time"
 
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
 	"github.com/martinmanurung/cinestream/internal/domain/movies/repository"
 	"github.com/martinmanurung/cinestream/internal/platform/queue"
 	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +21,13 @@ type JobProcessor struct {
 	queueService       queue.QueueService
 	transcodingService transcoding.TranscodingService
 	movieRepo          *repository.MovieRepository
+	chunking           ChunkingOptions
+	encryptHLS         bool
+	speech             SpeechOptions
+	notifications      NotificationOptions
+	maxRetries         int // deliveries of a job after the first before it is given up
+
+	handlers map[string]JobHandler // per job type of the named queues, see jobHandlers
 }
 
 // NewJobProcessor creates a new job processor
@@ -25,23 +36,37 @@ func NewJobProcessor(
 	queueService queue.QueueService,
 	transcodingService transcoding.TranscodingService,
 	movieRepo *repository.MovieRepository,
+	chunking ChunkingOptions,
+	encryptHLS bool,
+	speech SpeechOptions,
+	notifications NotificationOptions,
+	maxRetries int,
 ) *JobProcessor {
-	return &JobProcessor{
+	p := &JobProcessor{
 		db:                 db,
 		queueService:       queueService,
 		transcodingService: transcodingService,
 		movieRepo:          movieRepo,
+		chunking:           chunking,
+		encryptHLS:         encryptHLS,
+		speech:             speech,
+		notifications:      notifications,
+		maxRetries:         maxRetries,
 	}
+	p.handlers = p.jobHandlers()
+	return p
 }
 
-// Start begins processing jobs from the queue
+// Start begins processing transcoding jobs, the jobs of the named queues run on QueueConsumers
 func (p *JobProcessor) Start(ctx context.Context) error {
-	log.Println("Job processor started, waiting for transcoding jobs...")
+	ctx = componentContext(ctx, "processor")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Msg("Job processor started, waiting for transcoding jobs...")
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Job processor received shutdown signal")
+			logger.Info().Msg("Job processor received shutdown signal")
 			return ctx.Err()
 		default:
 			// Consume job from queue (blocking call with timeout)
@@ -49,10 +74,10 @@ func (p *JobProcessor) Start(ctx context.Context) error {
 			if err != nil {
 				// Check if context was cancelled
 				if ctx.Err() != nil {
-					log.Println("Context cancelled, stopping processor")
+					logger.Info().Msg("Context cancelled, stopping processor")
 					return ctx.Err()
 				}
-				log.Printf("Error consuming job: %v", err)
+				logger.Error().Err(err).Msg("Error consuming job")
 				continue
 			}
 
@@ -61,15 +86,21 @@ func (p *JobProcessor) Start(ctx context.Context) error {
 				continue
 			}
 
-			// Process the job
-			log.Printf("Processing job for movie ID: %d", job.MovieID)
-			if err := p.processJob(ctx, job); err != nil {
+			// Process the job, other workers leave it alone while the heartbeat runs
+			release := p.queueService.HoldTranscodingJob(ctx, job)
+			err = p.processJob(ctx, job)
+			release()
+			if err != nil {
 				// Check if error is due to context cancellation
 				if ctx.Err() != nil {
-					log.Printf("Job processing interrupted for movie %d: %v", job.MovieID, ctx.Err())
+					// Not acknowledged, another worker takes the job over
+					logger.Warn().Err(ctx.Err()).Str("job_id", job.ID).Int64("movie_id", job.MovieID).Msg("Job processing interrupted")
 					return ctx.Err()
 				}
-				log.Printf("Error processing job for movie %d: %v", job.MovieID, err)
+				logger.Error().Err(err).Str("job_id", job.ID).Int64("movie_id", job.MovieID).Msg("Error processing job")
+			}
+			if err := p.queueService.AckTranscodingJob(ctx, job); err != nil {
+				logger.Error().Err(err).Str("job_id", job.ID).Int64("movie_id", job.MovieID).Msg("Failed to acknowledge job")
 			}
 		}
 	}
@@ -79,41 +110,249 @@ func (p *JobProcessor) Start(ctx context.Context) error {
 func (p *JobProcessor) processJob(ctx context.Context, job *queue.TranscodingJob) error {
 	movieID := job.MovieID
 	rawFilePath := job.RawFilePath
+	ctx, logger := jobContext(ctx, "transcode", job.ID, movieID, job.Trace)
+	logger.Info().Msg("Processing job")
 
-	// Update status to PROCESSING
-	log.Printf("Movie %d: Updating status to PROCESSING", movieID)
-	if err := p.movieRepo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
-		"upload_status": "PROCESSING",
-	}); err != nil {
-		return fmt.Errorf("failed to update status to PROCESSING: %w", err)
+	// A live title keeps serving its current version while the new one is transcoded
+	movieVideo, err := p.movieRepo.FindMovieVideoByMovieID(ctx, movieID)
+	if err != nil {
+		return fmt.Errorf("failed to load movie video: %w", err)
 	}
+	live := movieVideo != nil && movieVideo.UploadStatus == "READY" && movieVideo.HLSPlaylistURL != ""
 
-	// Perform transcoding
-	log.Printf("Movie %d: Starting transcoding from %s", movieID, rawFilePath)
-	hlsURL, err := p.transcodingService.TranscodeToHLS(ctx, movieID, rawFilePath)
+	// A job that keeps killing its workers, e.g. by running out of memory, is not tried forever
+	if job.Deliveries > int64(p.maxRetries)+1 {
+		err := fmt.Errorf("job was taken over %d times after its worker died", job.Deliveries-1)
+		logger.Error().Err(err).Msg("Giving up job")
+		p.markFailed(ctx, movieID, live, err)
+		return err
+	}
+
+	if !live {
+		// Update status to PROCESSING
+		logger.Info().Msg("Updating status to PROCESSING")
+		if err := p.movieRepo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
+			"upload_status": "PROCESSING",
+		}); err != nil {
+			return fmt.Errorf("failed to update status to PROCESSING: %w", err)
+		}
+	}
+
+	// Reject files that are no decodable video before any encoding work
+	source, err := p.transcodingService.ValidateSource(ctx, rawFilePath)
+	switch {
+	case errors.Is(err, transcoding.ErrUnsupportedSource):
+		logger.Error().Err(err).Msg("Source REJECTED")
+		p.markFailed(ctx, movieID, live, err)
+		return fmt.Errorf("source validation failed: %w", err)
+	case err != nil:
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Probe trouble such as an unreachable MinIO is not the file's fault, transcoding reports real problems
+		logger.Warn().Err(err).Msg("Source validation skipped")
+	default:
+		logger.Info().
+			Str("video_codec", source.VideoCodec).
+			Str("resolution", source.Resolution()).
+			Float64("duration_seconds", source.DurationSeconds).
+			Msg("Source validated")
+		p.storeSourceInfo(ctx, movieID, source)
+	}
+
+	version, err := p.movieRepo.ReserveOutputVersion(ctx, movieID)
 	if err != nil {
-		// Update status to FAILED with error message
-		log.Printf("Movie %d: Transcoding FAILED: %v", movieID, err)
-		updateErr := p.movieRepo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
-			"upload_status": "FAILED",
-			"error_message": err.Error(),
-		})
-		if updateErr != nil {
-			log.Printf("Movie %d: Failed to update error status: %v", movieID, updateErr)
+		return fmt.Errorf("failed to reserve output version: %w", err)
+	}
+
+	// Use the profile set assigned to the movie, falling back to the standard ladder
+	profileSet := transcoding.LookupProfileSet("")
+	movie, err := p.movieRepo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		logger.Warn().Err(err).Str("profile_set", profileSet.Name).Msg("Failed to load movie, using default profile set")
+	} else if movie != nil {
+		profileSet = transcoding.LookupProfileSet(movie.ProfileSet)
+	}
+	// Jobs enqueued by hand may use another set or only some of its profiles
+	if job.ProfileSet != "" {
+		profileSet = transcoding.LookupProfileSet(job.ProfileSet)
+	}
+	fullSet := profileSet
+	if len(job.Profiles) > 0 {
+		profileSet = profileSet.Only(job.Profiles)
+		if len(profileSet.Profiles) == 0 {
+			err := fmt.Errorf("profile set %s has none of the profiles %v", profileSet.Name, job.Profiles)
+			p.markFailed(ctx, movieID, live, err)
+			return err
+		}
+	}
+
+	key, err := p.contentKey(ctx, movieID)
+	if err != nil {
+		return fmt.Errorf("failed to load content key: %w", err)
+	}
+
+	// Regenerating some qualities of a live title keeps its other renditions
+	var keep *transcoding.KeptOutput
+	if len(job.Profiles) > 0 && live {
+		if keep, err = p.keptOutput(ctx, movieID, movieVideo.HLSPlaylistURL, fullSet); err != nil {
+			return err
 		}
+	}
+
+	// Perform transcoding
+	logger.Info().
+		Int("version", version).
+		Str("source", rawFilePath).
+		Str("profile_set", profileSet.Name).
+		Strs("profiles", job.Profiles).
+		Msg("Starting transcoding")
+	result, err := p.transcode(ctx, movieID, version, rawFilePath, profileSet, key, len(job.Profiles) == 0, keep)
+	if err != nil {
+		logger.Error().Err(err).Msg("Transcoding FAILED")
+		p.markFailed(ctx, movieID, live, err)
 		return fmt.Errorf("transcoding failed: %w", err)
 	}
 
-	// Update status to READY with HLS URL
-	log.Printf("Movie %d: Transcoding completed successfully, HLS URL: %s", movieID, hlsURL)
-	if err := p.movieRepo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
-		"upload_status":    "READY",
-		"hls_playlist_url": hlsURL,
-		"error_message":    nil,
-	}); err != nil {
+	// Switch to the new version with READY status and HLS URL in one update
+	logger.Info().Str("hls_url", result.HLSURL).Float64("complexity", result.ComplexityFactor).Msg("Transcoding completed successfully")
+	var dashManifestURL interface{}
+	if result.DASHURL != "" {
+		dashManifestURL = result.DASHURL
+	}
+	published, err := p.movieRepo.PublishOutputVersion(ctx, movieID, version, map[string]interface{}{
+		"upload_status":     "READY",
+		"hls_playlist_url":  result.HLSURL,
+		"dash_manifest_url": dashManifestURL, // NULL when this version has no DASH output
+		"complexity_factor": result.ComplexityFactor,
+		"error_message":     nil,
+		"processed_at":      time.Now(), // raw source cleanup keeps the source for raw_retention_days after this
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update status to READY: %w", err)
 	}
+	if !published {
+		logger.Info().Int("version", version).Msg("A newer version is already live, this version is left for cleanup")
+		return nil
+	}
+
+	// Cached catalog responses still show the title as processing
+	if err := p.movieRepo.RecordCatalogChanges(ctx, movieID, movies.CatalogChangePublished); err != nil {
+		logger.Error().Err(err).Msg("Failed to record catalog change")
+	}
 
-	log.Printf("Movie %d: Processing completed successfully", movieID)
+	// Store the bitrates chosen for the live version
+	renditions := make([]movies.MovieRendition, 0, len(result.Renditions))
+	for _, rendition := range result.Renditions {
+		renditions = append(renditions, movies.MovieRendition{
+			MovieID:     movieID,
+			Name:        rendition.Name,
+			Resolution:  rendition.Resolution,
+			BitrateKbps: rendition.BitrateKbps,
+			VideoRange:  rendition.VideoRange,
+		})
+	}
+	if err := p.movieRepo.ReplaceMovieRenditions(ctx, movieID, renditions); err != nil {
+		logger.Error().Err(err).Msg("Failed to store renditions")
+	}
+
+	// Store the audio languages so the detail endpoint can list them
+	audioTracks := make([]movies.MovieAudioTrack, 0, len(result.AudioTracks))
+	for _, track := range result.AudioTracks {
+		audioTracks = append(audioTracks, movies.MovieAudioTrack{
+			MovieID:   movieID,
+			Language:  track.Language,
+			Name:      track.Name,
+			Channels:  track.Channels,
+			IsDefault: track.Default,
+		})
+	}
+	if err := p.movieRepo.ReplaceMovieAudioTracks(ctx, movieID, audioTracks); err != nil {
+		logger.Error().Err(err).Msg("Failed to store audio tracks")
+	}
+
+	// Older output is removed, the previous version stays for viewers that started before the switch
+	if err := p.transcodingService.RemoveSupersededVersions(ctx, movieID, version); err != nil {
+		logger.Warn().Err(err).Msg("Failed to remove superseded versions")
+	}
+
+	// The uploader learns the movie can be streamed, re-transcodes notify them again
+	p.notifyTranscodingCompleted(ctx, movieID)
+
+	// Draft subtitles take long and are reviewed by an admin before players see them
+	if p.speech.Transcriber != nil && len(result.AudioTracks) > 0 {
+		p.generateSubtitleDraft(ctx, movieID, rawFilePath)
+	}
+
+	logger.Info().Msg("Processing completed successfully")
 	return nil
 }
+
+// keptOutput describes the live version whose renditions a narrowed job copies, see transcoding.KeptOutput
+func (p *JobProcessor) keptOutput(ctx context.Context, movieID int64, hlsPlaylistURL string, set transcoding.ProfileSet) (*transcoding.KeptOutput, error) {
+	renditions, err := p.movieRepo.FindMovieRenditions(ctx, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load live renditions: %w", err)
+	}
+
+	keep := &transcoding.KeptOutput{Prefix: path.Dir(hlsPlaylistURL), ProfileSet: set}
+	for _, rendition := range renditions {
+		keep.Renditions = append(keep.Renditions, transcoding.Rendition{
+			Name:        rendition.Name,
+			Resolution:  rendition.Resolution,
+			BitrateKbps: rendition.BitrateKbps,
+			VideoRange:  rendition.VideoRange,
+		})
+	}
+	return keep, nil
+}
+
+// markFailed stores the error message, a live title stays READY on its current version
+func (p *JobProcessor) markFailed(ctx context.Context, movieID int64, live bool, cause error) {
+	updates := map[string]interface{}{
+		"error_message": cause.Error(),
+	}
+	if !live {
+		updates["upload_status"] = "FAILED"
+	}
+	if err := p.movieRepo.UpdateMovieVideo(ctx, movieID, updates); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to update error status")
+	}
+}
+
+// storeSourceInfo keeps the probed source properties and replaces the entered duration with the real one
+func (p *JobProcessor) storeSourceInfo(ctx context.Context, movieID int64, source *transcoding.SourceInfo) {
+	if err := p.movieRepo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
+		"source_resolution":       source.Resolution(),
+		"source_video_codec":      source.VideoCodec,
+		"source_duration_seconds": int(source.DurationSeconds),
+	}); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to store source info")
+	}
+
+	if err := p.movieRepo.UpdateMovie(ctx, movieID, map[string]interface{}{
+		"duration_minutes": source.DurationMinutes(),
+	}); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to update duration")
+	}
+}
+
+// contentKey returns the AES-128 key for the movie's segments, nil when encryption is disabled.
+// The key is created on the first transcode and reused by later versions.
+func (p *JobProcessor) contentKey(ctx context.Context, movieID int64) (*transcoding.ContentKey, error) {
+	if !p.encryptHLS {
+		return nil, nil
+	}
+
+	newKey, err := transcoding.GenerateContentKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := p.movieRepo.FindOrCreateContentKey(ctx, movieID, newKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Playlists are served by the API, a root-relative URI points players at the key endpoint
+	return &transcoding.ContentKey{Key: key, URI: fmt.Sprintf("/api/v1/keys/%d", movieID)}, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
+	"github.com/martinmanurung/cinestream/internal/platform/payment"
+	"github.com/rs/zerolog"
+)
+
+// OrderReaper periodically transitions unpaid orders past their expires_at to EXPIRED
+type OrderReaper struct {
+	orderRepo       orderRepository.OrderRepository
+	payments        *payment.Registry
+	interval        time.Duration
+	batchSize       int
+	cancelOnGateway bool
+}
+
+// NewOrderReaper creates a new order reaper
+func NewOrderReaper(
+	orderRepo orderRepository.OrderRepository,
+	payments *payment.Registry,
+	interval time.Duration,
+	batchSize int,
+	cancelOnGateway bool,
+) *OrderReaper {
+	return &OrderReaper{
+		orderRepo:       orderRepo,
+		payments:        payments,
+		interval:        interval,
+		batchSize:       batchSize,
+		cancelOnGateway: cancelOnGateway,
+	}
+}
+
+// Start runs the reaper until the context is cancelled
+func (r *OrderReaper) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "order_reaper")
+	zerolog.Ctx(ctx).Info().Dur("interval", r.interval).Msg("Order reaper started")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.reap(ctx)
+
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("Order reaper received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reap expires stale pending orders in batches until none are left
+func (r *OrderReaper) reap(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	expired := 0
+
+	for ctx.Err() == nil {
+		stale, err := r.orderRepo.FindStalePendingOrders(time.Now(), r.batchSize)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to find stale orders")
+			return
+		}
+
+		if len(stale) == 0 {
+			break
+		}
+
+		processed := 0
+		for _, order := range stale {
+			// Cancel on the gateway first so the user can no longer pay an order we expire
+			if r.cancelOnGateway {
+				if err := r.cancelOnProvider(ctx, order); err != nil {
+					logger.Warn().Err(err).Int64("order_id", order.ID).Msg("Failed to cancel order on the gateway")
+					continue
+				}
+			}
+
+			ok, err := r.orderRepo.ExpirePendingOrder(order.ID)
+			if err != nil {
+				logger.Error().Err(err).Int64("order_id", order.ID).Msg("Failed to expire order")
+				continue
+			}
+			processed++
+			if ok {
+				expired++
+			}
+		}
+
+		// Every order in the batch failed, retry on the next tick instead of looping
+		if processed == 0 || len(stale) < r.batchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		logger.Info().Int("expired", expired).Msg("Expired unpaid orders")
+	}
+}
+
+// cancelOnProvider cancels the transaction on the gateway the order was created with
+func (r *OrderReaper) cancelOnProvider(ctx context.Context, order orders.Order) error {
+	paymentService, err := r.payments.Get(order.PaymentProvider)
+	if err != nil {
+		return err
+	}
+
+	paymentRef := ""
+	if order.PaymentGatewayRef != nil {
+		paymentRef = *order.PaymentGatewayRef
+	}
+	return paymentService.CancelTransaction(ctx, order.ID, paymentRef)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+	"github.com/martinmanurung/cinestream/internal/platform/logging"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// watchConfig applies the operational settings of a changed config file without a restart:
+// the log level, the consumers of the named queues and the transcoding ladders.
+// Jobs already running keep the ladder they started with.
+func watchConfig(cfg *config.Config, consumers *QueueConsumers) {
+	config.Watch(cfg, "worker", func(previous, current *config.Config) {
+		logging.SetLevel(current.Log)
+		consumers.Resize(current.Queue.Consumers)
+		if err := transcoding.ConfigureProfileSets(current.Transcoding); err != nil {
+			zlog.Error().Err(err).Msg("Invalid transcoding profiles, keeping the previous ones")
+		}
+	})
+}
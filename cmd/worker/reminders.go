@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	"github.com/rs/zerolog"
+)
+
+// expiryReminderBatchSize is how many reminders one run queues at most, the next run continues
+const expiryReminderBatchSize = 500
+
+// ExpiryReminder emails viewers whose rental or complimentary access ends within the reminder window, once per access
+type ExpiryReminder struct {
+	orderRepo orderRepository.OrderRepository
+	notifier  *notification.Notifier
+	before    time.Duration
+	interval  time.Duration
+}
+
+// NewExpiryReminder creates a new access expiry reminder
+func NewExpiryReminder(orderRepo orderRepository.OrderRepository, notifier *notification.Notifier, before, interval time.Duration) *ExpiryReminder {
+	return &ExpiryReminder{
+		orderRepo: orderRepo,
+		notifier:  notifier,
+		before:    before,
+		interval:  interval,
+	}
+}
+
+// Start runs the reminder until the context is cancelled
+func (r *ExpiryReminder) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "expiry_reminder")
+	zerolog.Ctx(ctx).Info().Dur("interval", r.interval).Dur("before", r.before).Msg("Expiry reminder started")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.remind(ctx)
+
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("Expiry reminder received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// remind queues a reminder for every access ending within the window
+func (r *ExpiryReminder) remind(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	now := time.Now()
+
+	expiring, err := r.orderRepo.FindExpiringAccess(now, now.Add(r.before), expiryReminderBatchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to find expiring access")
+		return
+	}
+
+	reminded := 0
+	for _, access := range expiring {
+		if ctx.Err() != nil {
+			break
+		}
+		err := r.notifier.Notify(ctx, notification.Event{
+			Type: notification.EventAccessExpiring,
+			To:   []string{access.UserEmail},
+			Data: map[string]string{
+				"name":        access.UserName,
+				"movie_title": access.MovieTitle,
+				"expires_at":  access.AccessExpiresAt.Format("2 January 2006 15:04 MST"),
+			},
+		})
+		if err != nil {
+			logger.Error().Err(err).Int64("access_id", access.AccessID).Msg("Failed to queue expiry reminder")
+			continue
+		}
+		if err := r.orderRepo.MarkExpiryReminded(access.AccessID, now); err != nil {
+			logger.Error().Err(err).Int64("access_id", access.AccessID).Msg("Failed to mark expiry reminded")
+			continue
+		}
+		reminded++
+	}
+
+	if reminded > 0 {
+		logger.Info().Int("reminded", reminded).Msg("Queued access expiry reminders")
+	}
+}
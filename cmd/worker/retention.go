@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/retention"
+	"github.com/rs/zerolog"
+)
+
+// RetentionScheduler applies the data retention rules every interval
+type RetentionScheduler struct {
+	engine   *retention.Engine
+	interval time.Duration
+}
+
+// NewRetentionScheduler creates a new retention scheduler
+func NewRetentionScheduler(engine *retention.Engine, interval time.Duration) *RetentionScheduler {
+	return &RetentionScheduler{
+		engine:   engine,
+		interval: interval,
+	}
+}
+
+// Start runs the scheduler until the context is cancelled, the first run starts right away
+func (s *RetentionScheduler) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "retention")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Dur("interval", s.interval).Int("rules", len(s.engine.Rules())).Msg("Retention scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Retention scheduler received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *RetentionScheduler) run(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	reports, err := s.engine.Run(ctx, time.Now())
+	for _, report := range reports {
+		event := logger.Info()
+		if report.Status != retention.StatusOK {
+			event = logger.Error().Str("error", *report.Error)
+		}
+		event.
+			Str("target", report.Target).
+			Str("action", report.Action).
+			Bool("dry_run", report.DryRun).
+			Time("cutoff", report.Cutoff).
+			Int64("matched", report.Matched).
+			Int64("affected", report.Affected).
+			Dur("took", report.FinishedAt.Sub(report.StartedAt)).
+			Msg("Retention rule applied")
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to store retention reports")
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
+	"github.com/martinmanurung/cinestream/internal/platform/payment"
+	"github.com/rs/zerolog"
+)
+
+// settlementLookback is how many past days are reconciled when their report is missing, e.g. after an outage
+const settlementLookback = 7
+
+// SettlementReconciler reconciles each day's paid orders against the settlement state of the gateways
+// and stores a daily summary with the mismatches for finance
+type SettlementReconciler struct {
+	orderRepo orderRepository.OrderRepository
+	payments  *payment.Registry
+	interval  time.Duration
+	delay     time.Duration
+}
+
+// NewSettlementReconciler creates a new settlement reconciler, a day is reconciled delay after it ended
+func NewSettlementReconciler(orderRepo orderRepository.OrderRepository, payments *payment.Registry, interval, delay time.Duration) *SettlementReconciler {
+	return &SettlementReconciler{
+		orderRepo: orderRepo,
+		payments:  payments,
+		interval:  interval,
+		delay:     delay,
+	}
+}
+
+// Start runs the reconciler until the context is cancelled
+func (s *SettlementReconciler) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "settlement_reconciler")
+	zerolog.Ctx(ctx).Info().Dur("interval", s.interval).Dur("delay", s.delay).Msg("Settlement reconciler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.reconcilePendingDays(ctx)
+
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("Settlement reconciler received shutdown signal")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcilePendingDays reconciles the finished days of the lookback that have no report yet, oldest first
+func (s *SettlementReconciler) reconcilePendingDays(ctx context.Context) {
+	// The latest day whose end is at least delay ago, days are in UTC
+	latest := time.Now().UTC().Add(-s.delay).Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	for i := settlementLookback - 1; i >= 0 && ctx.Err() == nil; i-- {
+		day := latest.AddDate(0, 0, -i)
+		logger := zerolog.Ctx(ctx).With().Str("day", day.Format(orders.SettlementDayLayout)).Logger()
+
+		exists, err := s.orderRepo.SettlementReportExists(day)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to check settlement report")
+			return
+		}
+		if exists {
+			continue
+		}
+
+		report, err := s.reconcile(ctx, day)
+		if err != nil {
+			// A gateway outage is retried on the next run
+			logger.Error().Err(err).Msg("Failed to reconcile settlements")
+			return
+		}
+		if err := s.orderRepo.SaveSettlementReport(report); err != nil {
+			logger.Error().Err(err).Msg("Failed to save settlement report")
+			return
+		}
+
+		logger.Info().
+			Str("status", report.Status).
+			Int("paid_orders", report.PaidOrders).
+			Float64("paid_amount", report.PaidAmount).
+			Int("settled_orders", report.SettledOrders).
+			Float64("settled_amount", report.SettledAmount).
+			Int("mismatches", report.Mismatches).
+			Int("unchecked", report.Unchecked).
+			Msg("Settlement day reconciled")
+	}
+}
+
+// reconcile compares the orders of a day with their gateway transactions
+func (s *SettlementReconciler) reconcile(ctx context.Context, day time.Time) (*orders.SettlementReport, error) {
+	list, err := s.orderRepo.FindOrdersForSettlement(day, day.Add(24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+
+	report := &orders.SettlementReport{ReportDate: day}
+	for _, order := range list {
+		paidLocally := order.PaymentStatus == orders.PaymentStatusPaid || order.PaymentStatus == orders.PaymentStatusRefunded
+		if paidLocally {
+			report.PaidOrders++
+			report.PaidAmount += order.Amount
+		}
+
+		tx, err := s.checkTransaction(ctx, order)
+		if err != nil {
+			if errors.Is(err, payment.ErrGatewayUnavailable) {
+				return nil, fmt.Errorf("order %d: %w", order.ID, err)
+			}
+			zerolog.Ctx(ctx).Warn().Err(err).Int64("order_id", order.ID).Msg("Failed to check gateway transaction")
+		}
+		if tx == nil {
+			if paidLocally {
+				report.Unchecked++
+			}
+			continue
+		}
+
+		settled := tx.Settled || tx.Refunded
+		if settled {
+			report.SettledOrders++
+			report.SettledAmount += tx.Amount
+		}
+
+		kind := ""
+		switch {
+		case paidLocally && !settled:
+			kind = orders.MismatchNotSettled
+		case !paidLocally && settled:
+			kind = orders.MismatchMissingLocally
+		case settled && math.Abs(tx.Amount-order.Amount) >= 0.01:
+			kind = orders.MismatchAmount
+		}
+		if kind != "" {
+			report.MismatchList = append(report.MismatchList, orders.SettlementMismatch{
+				OrderID:         order.ID,
+				PaymentProvider: order.PaymentProvider,
+				Kind:            kind,
+				LocalStatus:     string(order.PaymentStatus),
+				GatewayStatus:   tx.Status,
+				LocalAmount:     order.Amount,
+				GatewayAmount:   tx.Amount,
+			})
+		}
+	}
+
+	report.Mismatches = len(report.MismatchList)
+	report.Status = orders.SettlementStatusBalanced
+	if report.Mismatches > 0 {
+		report.Status = orders.SettlementStatusMismatch
+	}
+	return report, nil
+}
+
+// checkTransaction returns the gateway transaction of an order, nil when its gateway cannot report settlements
+func (s *SettlementReconciler) checkTransaction(ctx context.Context, order orders.Order) (*payment.GatewayTransaction, error) {
+	provider, err := s.payments.Get(order.PaymentProvider)
+	if err != nil {
+		return nil, err
+	}
+	checker, ok := provider.(payment.SettlementChecker)
+	if !ok {
+		return nil, nil
+	}
+
+	paymentRef := ""
+	if order.PaymentGatewayRef != nil {
+		paymentRef = *order.PaymentGatewayRef
+	}
+	return checker.CheckTransaction(ctx, order.ID, paymentRef)
+}
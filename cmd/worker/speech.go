@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/stt"
+	"github.com/rs/zerolog"
+)
+
+// SpeechOptions controls the draft subtitles generated after a transcode, disabled when Transcriber is nil
+type SpeechOptions struct {
+	Transcriber stt.Transcriber
+	Language    string // passed to the model, empty = detected
+}
+
+// generateSubtitleDraft transcribes the source audio into a WebVTT draft for admin review.
+// Failures are only logged, the title is already live.
+func (p *JobProcessor) generateSubtitleDraft(ctx context.Context, movieID int64, rawFilePath string) {
+	logger := zerolog.Ctx(ctx)
+
+	// Re-transcodes of the same title would otherwise pile up identical drafts
+	open, err := p.movieRepo.HasOpenSubtitleDraft(ctx, movieID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to check subtitle drafts")
+		return
+	}
+	if open {
+		logger.Info().Msg("Subtitle draft awaiting review, speech-to-text skipped")
+		return
+	}
+
+	workDir, err := os.MkdirTemp("", "speech-")
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create speech-to-text work dir")
+		return
+	}
+	defer os.RemoveAll(workDir)
+
+	audioPath := filepath.Join(workDir, "audio.wav")
+	if err := p.transcodingService.ExtractSpeechAudio(ctx, rawFilePath, audioPath); err != nil {
+		logger.Error().Err(err).Msg("Failed to extract audio for speech-to-text")
+		return
+	}
+
+	transcriber := p.speech.Transcriber
+	logger.Info().Str("provider", transcriber.Name()).Msg("Generating subtitle draft")
+	vtt, err := transcriber.Transcribe(ctx, audioPath, p.speech.Language)
+	if err != nil {
+		logger.Error().Err(err).Msg("Speech-to-text FAILED")
+		return
+	}
+
+	objectName, err := p.transcodingService.UploadSubtitleDraft(ctx, movieID, vtt)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to upload subtitle draft")
+		return
+	}
+
+	language := p.speech.Language
+	if language == "" {
+		language = "und"
+	}
+	draft := &movies.SubtitleDraft{
+		MovieID:    movieID,
+		Language:   language,
+		Provider:   transcriber.Name(),
+		ObjectName: objectName,
+		Status:     movies.SubtitleDraftPending,
+	}
+	if err := p.movieRepo.CreateSubtitleDraft(ctx, draft); err != nil {
+		logger.Error().Err(err).Msg("Failed to store subtitle draft")
+		return
+	}
+
+	logger.Info().Int64("draft_id", draft.ID).Msg("Subtitle draft stored for review")
+}
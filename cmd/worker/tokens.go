@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	userRepository "github.com/martinmanurung/cinestream/internal/domain/users/repository"
+	"github.com/rs/zerolog"
+)
+
+// RefreshTokenPurger deletes refresh tokens that reached their absolute expiry or were unused for too long
+type RefreshTokenPurger struct {
+	userRepo   *userRepository.User
+	idleExpiry time.Duration
+	interval   time.Duration
+}
+
+// NewRefreshTokenPurger creates a new refresh token purger
+func NewRefreshTokenPurger(userRepo *userRepository.User, idleExpiry, interval time.Duration) *RefreshTokenPurger {
+	return &RefreshTokenPurger{
+		userRepo:   userRepo,
+		idleExpiry: idleExpiry,
+		interval:   interval,
+	}
+}
+
+// Start runs the purger until the context is cancelled
+func (p *RefreshTokenPurger) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "refresh_token_purger")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Dur("interval", p.interval).Dur("idle_expiry", p.idleExpiry).Msg("Refresh token purger started")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Refresh token purger received shutdown signal")
+			return
+		case <-ticker.C:
+			now := time.Now()
+			deleted, err := p.userRepo.DeleteExpiredRefreshTokens(ctx, now, now.Add(-p.idleExpiry))
+			if err != nil {
+				logger.Error().Err(err).Msg("Failed to purge refresh tokens")
+				continue
+			}
+			if deleted > 0 {
+				logger.Info().Int64("deleted", deleted).Msg("Deleted expired refresh tokens")
+			}
+		}
+	}
+}
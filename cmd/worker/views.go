@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	movieRepository "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	"github.com/martinmanurung/cinestream/internal/platform/views"
+	"github.com/rs/zerolog"
+)
+
+// ViewFlusher moves the playback counters the API records in Redis to the daily views in MySQL
+type ViewFlusher struct {
+	counter   *views.Counter
+	movieRepo *movieRepository.MovieRepository
+	interval  time.Duration
+}
+
+// NewViewFlusher creates a new view flusher
+func NewViewFlusher(counter *views.Counter, movieRepo *movieRepository.MovieRepository, interval time.Duration) *ViewFlusher {
+	return &ViewFlusher{
+		counter:   counter,
+		movieRepo: movieRepo,
+		interval:  interval,
+	}
+}
+
+// Start runs the flusher until the context is cancelled
+func (f *ViewFlusher) Start(ctx context.Context) {
+	ctx = componentContext(ctx, "view_flusher")
+	logger := zerolog.Ctx(ctx)
+	logger.Info().Dur("interval", f.interval).Msg("View flusher started")
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("View flusher received shutdown signal")
+			return
+		case <-ticker.C:
+			if err := f.counter.Flush(ctx, f.movieRepo.AddDailyViews); err != nil {
+				logger.Error().Err(err).Msg("Failed to flush view counters")
+			}
+		}
+	}
+}
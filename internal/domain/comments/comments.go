@@ -0,0 +1,85 @@
+package comments
+
+import "time"
+
+// Comment statuses
+const (
+	StatusVisible = "VISIBLE"
+	StatusHidden  = "HIDDEN"
+	StatusPending = "PENDING" // held back by content moderation until an admin makes it VISIBLE or HIDDEN
+)
+
+// Comment is a timestamped comment shown as an overlay while watching a movie
+type Comment struct {
+	ID               int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID          int64      `json:"movie_id" gorm:"not null"`
+	UserExtID        string     `json:"user_ext_id" gorm:"not null;column:user_ext_id"`
+	TimestampSeconds int        `json:"timestamp_seconds" gorm:"not null"`
+	Body             string     `json:"body" gorm:"type:varchar(500);not null"`
+	Status           string     `json:"status" gorm:"type:enum('VISIBLE','HIDDEN','PENDING');default:'VISIBLE'"`
+	ModerationReason *string    `json:"moderation_reason,omitempty" gorm:"type:varchar(255)"` // why content moderation held it back
+	ModeratedBy      *string    `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for Comment
+func (Comment) TableName() string {
+	return "movie_comments"
+}
+
+// Request DTOs
+
+// CreateCommentRequest represents a comment posted while watching
+type CreateCommentRequest struct {
+	TimestampSeconds int    `json:"timestamp_seconds" validate:"min=0"`
+	Body             string `json:"body" validate:"required,min=1,max=500"`
+}
+
+// ModerateCommentRequest represents an admin moderation decision
+type ModerateCommentRequest struct {
+	Status string `json:"status" validate:"required,oneof=VISIBLE HIDDEN"`
+}
+
+// Response DTOs
+
+// CommentResponse represents a comment in the overlay
+type CommentResponse struct {
+	ID               int64     `json:"id"`
+	UserExtID        string    `json:"user_ext_id"`
+	UserName         string    `json:"user_name"`
+	TimestampSeconds int       `json:"timestamp_seconds"`
+	Body             string    `json:"body"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// AdminCommentResponse represents a comment in the moderation queue
+type AdminCommentResponse struct {
+	ID               int64      `json:"id"`
+	MovieID          int64      `json:"movie_id"`
+	MovieTitle       string     `json:"movie_title"`
+	UserExtID        string     `json:"user_ext_id"`
+	UserName         string     `json:"user_name"`
+	TimestampSeconds int        `json:"timestamp_seconds"`
+	Body             string     `json:"body"`
+	Status           string     `json:"status"`
+	ModerationReason *string    `json:"moderation_reason,omitempty"`
+	ModeratedBy      *string    `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+// AdminCommentListWithPagination represents the paginated moderation list
+type AdminCommentListWithPagination struct {
+	Comments   []AdminCommentResponse `json:"comments"`
+	Pagination PaginationMeta         `json:"pagination"`
+}
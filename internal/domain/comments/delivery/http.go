@@ -0,0 +1,198 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/comments"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type CommentUsecase interface {
+	PostComment(ctx context.Context, userExtID string, movieID int64, req comments.CreateCommentRequest) (*comments.Comment, error)
+	GetCommentsInRange(ctx context.Context, userExtID string, movieID int64, from, to int) ([]comments.CommentResponse, error)
+	DeleteOwnComment(ctx context.Context, userExtID string, commentID int64) error
+	GetAllCommentsAdmin(ctx context.Context, page, limit int, status string, movieID int64) (*comments.AdminCommentListWithPagination, error)
+	ModerateComment(ctx context.Context, adminExtID string, commentID int64, req comments.ModerateCommentRequest) error
+	DeleteComment(ctx context.Context, commentID int64) error
+}
+
+type CommentHandler struct {
+	ctx     context.Context
+	usecase CommentUsecase
+}
+
+func NewCommentHandler(ctx context.Context, usecase CommentUsecase) *CommentHandler {
+	return &CommentHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// PostComment posts a comment at a position of the movie
+// POST /api/v1/movies/:id/comments
+func (h *CommentHandler) PostComment(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req comments.CreateCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.PostComment(ctx, userExtID, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "comment_posted", result)
+}
+
+// GetComments returns comments between two positions for overlay display
+// GET /api/v1/movies/:id/comments?from=0&to=300
+func (h *CommentHandler) GetComments(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_from", err.Error())
+	}
+
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_to", err.Error())
+	}
+
+	result, err := h.usecase.GetCommentsInRange(ctx, userExtID, movieID, from, to)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// DeleteOwnComment deletes a comment posted by the current user
+// DELETE /api/v1/comments/:id
+func (h *CommentHandler) DeleteOwnComment(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_comment_id", err.Error())
+	}
+
+	err = h.usecase.DeleteOwnComment(ctx, userExtID, commentID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "comment_deleted", nil)
+}
+
+// GetAllCommentsAdmin lists comments for moderation (Admin only)
+// GET /api/v1/admin/comments?page=1&status=VISIBLE&movie_id=1
+func (h *CommentHandler) GetAllCommentsAdmin(c echo.Context) error {
+	ctx := h.ctx
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := c.QueryParam("status")
+	movieID, _ := strconv.ParseInt(c.QueryParam("movie_id"), 10, 64)
+
+	result, err := h.usecase.GetAllCommentsAdmin(ctx, page, limit, status, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Comments,
+		"pagination": result.Pagination,
+	})
+}
+
+// ModerateComment hides or restores a comment (Admin only)
+// PATCH /api/v1/admin/comments/:id
+func (h *CommentHandler) ModerateComment(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_comment_id", err.Error())
+	}
+
+	var req comments.ModerateCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	err = h.usecase.ModerateComment(ctx, adminExtID, commentID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "comment_moderated", nil)
+}
+
+// DeleteComment permanently deletes a comment (Admin only)
+// DELETE /api/v1/admin/comments/:id
+func (h *CommentHandler) DeleteComment(c echo.Context) error {
+	ctx := h.ctx
+
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_comment_id", err.Error())
+	}
+
+	err = h.usecase.DeleteComment(ctx, commentID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "comment_deleted", nil)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/comments"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// HasMovieAccess checks whether the user has an active purchase or rental of the movie
+func (r *CommentRepository) HasMovieAccess(ctx context.Context, userExtID string, movieID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("user_movie_access").
+		Where("user_ext_id = ? AND movie_id = ?", userExtID, movieID).
+		Where("access_expires_at IS NULL OR access_expires_at > ?", time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountCommentsSince counts the comments a user posted after since, used for rate limiting
+func (r *CommentRepository) CountCommentsSince(ctx context.Context, userExtID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&comments.Comment{}).
+		Where("user_ext_id = ? AND created_at > ?", userExtID, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, comment *comments.Comment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *CommentRepository) FindCommentByID(ctx context.Context, commentID int64) (*comments.Comment, error) {
+	var comment comments.Comment
+	err := r.db.WithContext(ctx).Where("id = ?", commentID).First(&comment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FindVisibleCommentsInRange returns visible comments between two positions of the movie
+func (r *CommentRepository) FindVisibleCommentsInRange(ctx context.Context, movieID int64, from, to, limit int) ([]comments.CommentResponse, error) {
+	var results []comments.CommentResponse
+	err := r.db.WithContext(ctx).
+		Table("movie_comments").
+		Select("movie_comments.id, movie_comments.user_ext_id, COALESCE(users.name, '') as user_name, movie_comments.timestamp_seconds, movie_comments.body, movie_comments.created_at").
+		Joins("LEFT JOIN users ON users.ext_id = movie_comments.user_ext_id").
+		Where("movie_comments.movie_id = ? AND movie_comments.status = ?", movieID, comments.StatusVisible).
+		Where("movie_comments.timestamp_seconds BETWEEN ? AND ?", from, to).
+		Order("movie_comments.timestamp_seconds ASC, movie_comments.id ASC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FindAllComments returns comments for moderation with optional filters
+func (r *CommentRepository) FindAllComments(ctx context.Context, page, limit int, status string, movieID int64) ([]comments.AdminCommentResponse, int64, error) {
+	var results []comments.AdminCommentResponse
+	var totalCount int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).
+		Table("movie_comments").
+		Select("movie_comments.*, movies.title as movie_title, COALESCE(users.name, '') as user_name").
+		Joins("JOIN movies ON movies.id = movie_comments.movie_id").
+		Joins("LEFT JOIN users ON users.ext_id = movie_comments.user_ext_id")
+
+	if status != "" {
+		query = query.Where("movie_comments.status = ?", status)
+	}
+	if movieID > 0 {
+		query = query.Where("movie_comments.movie_id = ?", movieID)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("movie_comments.created_at DESC").Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+func (r *CommentRepository) UpdateCommentStatus(ctx context.Context, commentID int64, status, moderatedBy string, moderatedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&comments.Comment{}).
+		Where("id = ?", commentID).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"moderated_by": moderatedBy,
+			"moderated_at": moderatedAt,
+		}).Error
+}
+
+func (r *CommentRepository) DeleteComment(ctx context.Context, commentID int64) error {
+	return r.db.WithContext(ctx).Where("id = ?", commentID).Delete(&comments.Comment{}).Error
+}
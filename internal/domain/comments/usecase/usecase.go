@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/comments"
+	"github.com/martinmanurung/cinestream/internal/platform/moderation"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// rateLimitWindow and rateLimitMax cap how many comments a user can post in a short period
+	rateLimitWindow = time.Minute
+	rateLimitMax    = 5
+
+	// maxOverlayRange and maxOverlayComments bound a single overlay fetch
+	maxOverlayRange    = 30 * 60 // seconds
+	maxOverlayComments = 500
+)
+
+type CommentRepository interface {
+	HasMovieAccess(ctx context.Context, userExtID string, movieID int64) (bool, error)
+	CountCommentsSince(ctx context.Context, userExtID string, since time.Time) (int64, error)
+	CreateComment(ctx context.Context, comment *comments.Comment) error
+	FindCommentByID(ctx context.Context, commentID int64) (*comments.Comment, error)
+	FindVisibleCommentsInRange(ctx context.Context, movieID int64, from, to, limit int) ([]comments.CommentResponse, error)
+	FindAllComments(ctx context.Context, page, limit int, status string, movieID int64) ([]comments.AdminCommentResponse, int64, error)
+	UpdateCommentStatus(ctx context.Context, commentID int64, status, moderatedBy string, moderatedAt time.Time) error
+	DeleteComment(ctx context.Context, commentID int64) error
+}
+
+// ContentModerator scans comments before they are shown, see moderation.Moderator
+type ContentModerator interface {
+	CheckText(ctx context.Context, text string) (moderation.Verdict, error)
+}
+
+type CommentUsecase struct {
+	repo      CommentRepository
+	moderator ContentModerator // nil when comments are shown unscanned
+}
+
+func NewCommentUsecase(repo CommentRepository, moderator ContentModerator) *CommentUsecase {
+	return &CommentUsecase{repo: repo, moderator: moderator}
+}
+
+// PostComment adds a timestamped comment, only users with access to the movie can comment
+func (u *CommentUsecase) PostComment(ctx context.Context, userExtID string, movieID int64, req comments.CreateCommentRequest) (*comments.Comment, error) {
+	if err := u.requireAccess(ctx, userExtID, movieID); err != nil {
+		return nil, err
+	}
+
+	recent, err := u.repo.CountCommentsSince(ctx, userExtID, time.Now().Add(-rateLimitWindow))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if recent >= rateLimitMax {
+		return nil, apperr.RateLimited("comment_rate_limited", "too many comments, please slow down")
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, apperr.Validation("comment_body_required", nil)
+	}
+
+	comment := &comments.Comment{
+		MovieID:          movieID,
+		UserExtID:        userExtID,
+		TimestampSeconds: req.TimestampSeconds,
+		Body:             body,
+		Status:           comments.StatusVisible,
+	}
+	if reason := u.moderate(ctx, body); reason != "" {
+		comment.Status = comments.StatusPending
+		comment.ModerationReason = &reason
+	}
+	if err := u.repo.CreateComment(ctx, comment); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return comment, nil
+}
+
+// moderate returns why a comment has to wait for an admin, empty when it can be shown.
+// A comment the moderation service cannot scan waits as well.
+func (u *CommentUsecase) moderate(ctx context.Context, body string) string {
+	if u.moderator == nil {
+		return ""
+	}
+	verdict, err := u.moderator.CheckText(ctx, body)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Content moderation failed, holding the comment for review")
+		return "moderation_unavailable"
+	}
+	if !verdict.Flagged {
+		return ""
+	}
+	if verdict.Reason == "" {
+		return "flagged"
+	}
+	return verdict.Reason
+}
+
+// GetCommentsInRange returns the visible comments between two positions for overlay display
+func (u *CommentUsecase) GetCommentsInRange(ctx context.Context, userExtID string, movieID int64, from, to int) ([]comments.CommentResponse, error) {
+	if from < 0 || to < from {
+		return nil, apperr.Validation("invalid_time_range", nil)
+	}
+	if to-from > maxOverlayRange {
+		return nil, apperr.Validation("time_range_too_large", "max range is 1800 seconds")
+	}
+
+	if err := u.requireAccess(ctx, userExtID, movieID); err != nil {
+		return nil, err
+	}
+
+	result, err := u.repo.FindVisibleCommentsInRange(ctx, movieID, from, to, maxOverlayComments)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if result == nil {
+		result = []comments.CommentResponse{}
+	}
+
+	return result, nil
+}
+
+// DeleteOwnComment lets a user remove their own comment
+func (u *CommentUsecase) DeleteOwnComment(ctx context.Context, userExtID string, commentID int64) error {
+	comment, err := u.repo.FindCommentByID(ctx, commentID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if comment == nil || comment.UserExtID != userExtID {
+		return apperr.NotFound("comment_not_found", nil)
+	}
+
+	if err := u.repo.DeleteComment(ctx, commentID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// GetAllCommentsAdmin lists comments for moderation (Admin only)
+func (u *CommentUsecase) GetAllCommentsAdmin(ctx context.Context, page, limit int, status string, movieID int64) (*comments.AdminCommentListWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	list, totalCount, err := u.repo.FindAllComments(ctx, page, limit, status, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &comments.AdminCommentListWithPagination{
+		Comments: list,
+		Pagination: comments.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+// ModerateComment hides or restores a comment, or decides on one held back by content moderation (Admin only)
+func (u *CommentUsecase) ModerateComment(ctx context.Context, adminExtID string, commentID int64, req comments.ModerateCommentRequest) error {
+	comment, err := u.repo.FindCommentByID(ctx, commentID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if comment == nil {
+		return apperr.NotFound("comment_not_found", nil)
+	}
+
+	if err := u.repo.UpdateCommentStatus(ctx, commentID, req.Status, adminExtID, time.Now()); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// DeleteComment permanently removes a comment (Admin only)
+func (u *CommentUsecase) DeleteComment(ctx context.Context, commentID int64) error {
+	comment, err := u.repo.FindCommentByID(ctx, commentID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if comment == nil {
+		return apperr.NotFound("comment_not_found", nil)
+	}
+
+	if err := u.repo.DeleteComment(ctx, commentID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+func (u *CommentUsecase) requireAccess(ctx context.Context, userExtID string, movieID int64) error {
+	hasAccess, err := u.repo.HasMovieAccess(ctx, userExtID, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !hasAccess {
+		return apperr.Forbidden("movie_access_required", nil)
+	}
+	return nil
+}
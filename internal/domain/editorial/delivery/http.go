@@ -0,0 +1,176 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/editorial"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type EditorialUsecase interface {
+	CreateReview(ctx context.Context, authorExtID string, req editorial.CreateReviewRequest) (*editorial.ReviewResponse, error)
+	GetReview(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error)
+	GetAllReviews(ctx context.Context, page, limit int, status string, movieID int64) (*editorial.ReviewListWithPagination, error)
+	UpdateReview(ctx context.Context, reviewID int64, req editorial.UpdateReviewRequest) (*editorial.ReviewResponse, error)
+	PublishReview(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error)
+	UnpublishReview(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error)
+	DeleteReview(ctx context.Context, reviewID int64) error
+}
+
+type EditorialHandler struct {
+	ctx     context.Context
+	usecase EditorialUsecase
+}
+
+func NewEditorialHandler(ctx context.Context, usecase EditorialUsecase) *EditorialHandler {
+	return &EditorialHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// CreateReview creates a draft editorial review (Staff only)
+// POST /api/v1/editorial/reviews
+func (h *EditorialHandler) CreateReview(c echo.Context) error {
+	ctx := h.ctx
+
+	authorExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || authorExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req editorial.CreateReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.CreateReview(ctx, authorExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, "review_created", result)
+}
+
+// GetAllReviews lists editorial reviews in any status (Staff only)
+// GET /api/v1/editorial/reviews?page=1&status=DRAFT&movie_id=1
+func (h *EditorialHandler) GetAllReviews(c echo.Context) error {
+	ctx := h.ctx
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := c.QueryParam("status")
+	movieID, _ := strconv.ParseInt(c.QueryParam("movie_id"), 10, 64)
+
+	result, err := h.usecase.GetAllReviews(ctx, page, limit, status, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Reviews,
+		"pagination": result.Pagination,
+	})
+}
+
+// GetReview returns a single editorial review (Staff only)
+// GET /api/v1/editorial/reviews/:id
+func (h *EditorialHandler) GetReview(c echo.Context) error {
+	ctx := h.ctx
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+	result, err := h.usecase.GetReview(ctx, reviewID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// UpdateReview edits an editorial review (Staff only)
+// PUT /api/v1/editorial/reviews/:id
+func (h *EditorialHandler) UpdateReview(c echo.Context) error {
+	ctx := h.ctx
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+	var req editorial.UpdateReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.UpdateReview(ctx, reviewID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "review_updated", result)
+}
+
+// PublishReview publishes a draft so it is shown on the movie detail (Staff only)
+// POST /api/v1/editorial/reviews/:id/publish
+func (h *EditorialHandler) PublishReview(c echo.Context) error {
+	ctx := h.ctx
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+	result, err := h.usecase.PublishReview(ctx, reviewID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "review_published", result)
+}
+
+// UnpublishReview moves a published review back to draft (Staff only)
+// POST /api/v1/editorial/reviews/:id/unpublish
+func (h *EditorialHandler) UnpublishReview(c echo.Context) error {
+	ctx := h.ctx
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+	result, err := h.usecase.UnpublishReview(ctx, reviewID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "review_unpublished", result)
+}
+
+// DeleteReview permanently deletes an editorial review (Staff only)
+// DELETE /api/v1/editorial/reviews/:id
+func (h *EditorialHandler) DeleteReview(c echo.Context) error {
+	ctx := h.ctx
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+	err = h.usecase.DeleteReview(ctx, reviewID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "review_deleted", nil)
+}
@@ -0,0 +1,77 @@
+package editorial
+
+import "time"
+
+// Editorial review statuses
+const (
+	StatusDraft     = "DRAFT"
+	StatusPublished = "PUBLISHED"
+)
+
+// EditorialReview is a critic/staff review of a movie, separate from user feedback
+type EditorialReview struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID     int64      `json:"movie_id" gorm:"not null"`
+	AuthorExtID string     `json:"author_ext_id" gorm:"not null;column:author_ext_id"`
+	Headline    string     `json:"headline" gorm:"type:varchar(255);not null"`
+	Body        string     `json:"body" gorm:"type:text;not null"` // Markdown
+	Score       float64    `json:"score" gorm:"type:decimal(3,1);not null"`
+	Status      string     `json:"status" gorm:"type:enum('DRAFT','PUBLISHED');default:'DRAFT'"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for EditorialReview
+func (EditorialReview) TableName() string {
+	return "editorial_reviews"
+}
+
+// Request DTOs
+
+// CreateReviewRequest represents a new editorial review, always saved as a draft
+type CreateReviewRequest struct {
+	MovieID  int64   `json:"movie_id" validate:"required,gt=0"`
+	Headline string  `json:"headline" validate:"required,min=1,max=255"`
+	Body     string  `json:"body" validate:"required,min=1"`
+	Score    float64 `json:"score" validate:"min=0,max=10"`
+}
+
+// UpdateReviewRequest represents changes to an editorial review
+type UpdateReviewRequest struct {
+	Headline string   `json:"headline" validate:"omitempty,min=1,max=255"`
+	Body     string   `json:"body"`
+	Score    *float64 `json:"score" validate:"omitempty,min=0,max=10"`
+}
+
+// Response DTOs
+
+// ReviewResponse represents an editorial review in the staff workspace
+type ReviewResponse struct {
+	ID          int64      `json:"id"`
+	MovieID     int64      `json:"movie_id"`
+	MovieTitle  string     `json:"movie_title"`
+	AuthorExtID string     `json:"author_ext_id"`
+	AuthorName  string     `json:"author_name"`
+	Headline    string     `json:"headline"`
+	Body        string     `json:"body"`
+	Score       float64    `json:"score"`
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+// ReviewListWithPagination represents the paginated staff review list
+type ReviewListWithPagination struct {
+	Reviews    []ReviewResponse `json:"reviews"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/editorial"
+	"gorm.io/gorm"
+)
+
+type EditorialRepository struct {
+	db *gorm.DB
+}
+
+func NewEditorialRepository(db *gorm.DB) *EditorialRepository {
+	return &EditorialRepository{db: db}
+}
+
+// MovieExists checks whether the movie can be reviewed
+func (r *EditorialRepository) MovieExists(ctx context.Context, movieID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("movies").Where("id = ? AND deleted_at IS NULL", movieID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *EditorialRepository) CreateReview(ctx context.Context, review *editorial.EditorialReview) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+func (r *EditorialRepository) FindReviewByID(ctx context.Context, reviewID int64) (*editorial.EditorialReview, error) {
+	var review editorial.EditorialReview
+	err := r.db.WithContext(ctx).Where("id = ?", reviewID).First(&review).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// FindReviewDetail returns a review with movie title and author name
+func (r *EditorialRepository) FindReviewDetail(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error) {
+	var result editorial.ReviewResponse
+	err := r.reviewQuery(ctx).Where("editorial_reviews.id = ?", reviewID).Take(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindAllReviews returns reviews for the staff workspace with optional filters
+func (r *EditorialRepository) FindAllReviews(ctx context.Context, page, limit int, status string, movieID int64) ([]editorial.ReviewResponse, int64, error) {
+	var results []editorial.ReviewResponse
+	var totalCount int64
+
+	offset := (page - 1) * limit
+
+	query := r.reviewQuery(ctx)
+	if status != "" {
+		query = query.Where("editorial_reviews.status = ?", status)
+	}
+	if movieID > 0 {
+		query = query.Where("editorial_reviews.movie_id = ?", movieID)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("editorial_reviews.updated_at DESC").Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+func (r *EditorialRepository) UpdateReview(ctx context.Context, reviewID int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&editorial.EditorialReview{}).
+		Where("id = ?", reviewID).
+		Updates(updates).Error
+}
+
+// UpdateReviewStatus moves a review between draft and published
+func (r *EditorialRepository) UpdateReviewStatus(ctx context.Context, reviewID int64, status string, publishedAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&editorial.EditorialReview{}).
+		Where("id = ?", reviewID).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"published_at": publishedAt,
+		}).Error
+}
+
+func (r *EditorialRepository) DeleteReview(ctx context.Context, reviewID int64) error {
+	return r.db.WithContext(ctx).Where("id = ?", reviewID).Delete(&editorial.EditorialReview{}).Error
+}
+
+func (r *EditorialRepository) reviewQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("editorial_reviews").
+		Select("editorial_reviews.*, movies.title as movie_title, COALESCE(users.name, '') as author_name").
+		Joins("JOIN movies ON movies.id = editorial_reviews.movie_id").
+		Joins("LEFT JOIN users ON users.ext_id = editorial_reviews.author_ext_id")
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/editorial"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+type EditorialRepository interface {
+	MovieExists(ctx context.Context, movieID int64) (bool, error)
+	CreateReview(ctx context.Context, review *editorial.EditorialReview) error
+	FindReviewByID(ctx context.Context, reviewID int64) (*editorial.EditorialReview, error)
+	FindReviewDetail(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error)
+	FindAllReviews(ctx context.Context, page, limit int, status string, movieID int64) ([]editorial.ReviewResponse, int64, error)
+	UpdateReview(ctx context.Context, reviewID int64, updates map[string]interface{}) error
+	UpdateReviewStatus(ctx context.Context, reviewID int64, status string, publishedAt *time.Time) error
+	DeleteReview(ctx context.Context, reviewID int64) error
+}
+
+type EditorialUsecase struct {
+	repo EditorialRepository
+}
+
+func NewEditorialUsecase(repo EditorialRepository) *EditorialUsecase {
+	return &EditorialUsecase{repo: repo}
+}
+
+// CreateReview saves a new editorial review as a draft
+func (u *EditorialUsecase) CreateReview(ctx context.Context, authorExtID string, req editorial.CreateReviewRequest) (*editorial.ReviewResponse, error) {
+	exists, err := u.repo.MovieExists(ctx, req.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !exists {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, apperr.Validation("review_body_required", nil)
+	}
+
+	review := &editorial.EditorialReview{
+		MovieID:     req.MovieID,
+		AuthorExtID: authorExtID,
+		Headline:    strings.TrimSpace(req.Headline),
+		Body:        body,
+		Score:       req.Score,
+		Status:      editorial.StatusDraft,
+	}
+	if err := u.repo.CreateReview(ctx, review); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return u.GetReview(ctx, review.ID)
+}
+
+// GetReview returns a single editorial review in any status
+func (u *EditorialUsecase) GetReview(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error) {
+	review, err := u.repo.FindReviewDetail(ctx, reviewID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if review == nil {
+		return nil, apperr.NotFound("review_not_found", nil)
+	}
+	return review, nil
+}
+
+// GetAllReviews lists editorial reviews for the staff workspace
+func (u *EditorialUsecase) GetAllReviews(ctx context.Context, page, limit int, status string, movieID int64) (*editorial.ReviewListWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	if status != "" && status != editorial.StatusDraft && status != editorial.StatusPublished {
+		return nil, apperr.Validation("invalid_status", "status must be DRAFT or PUBLISHED")
+	}
+
+	list, totalCount, err := u.repo.FindAllReviews(ctx, page, limit, status, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &editorial.ReviewListWithPagination{
+		Reviews: list,
+		Pagination: editorial.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+// UpdateReview edits an editorial review, published reviews stay published
+func (u *EditorialUsecase) UpdateReview(ctx context.Context, reviewID int64, req editorial.UpdateReviewRequest) (*editorial.ReviewResponse, error) {
+	if _, err := u.findReview(ctx, reviewID); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if headline := strings.TrimSpace(req.Headline); headline != "" {
+		updates["headline"] = headline
+	}
+	if body := strings.TrimSpace(req.Body); body != "" {
+		updates["body"] = body
+	}
+	if req.Score != nil {
+		updates["score"] = *req.Score
+	}
+
+	if len(updates) == 0 {
+		return nil, apperr.Validation("no_fields_to_update", nil)
+	}
+
+	if err := u.repo.UpdateReview(ctx, reviewID, updates); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return u.GetReview(ctx, reviewID)
+}
+
+// PublishReview makes a draft visible on the movie detail
+func (u *EditorialUsecase) PublishReview(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error) {
+	review, err := u.findReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == editorial.StatusPublished {
+		return nil, apperr.Conflict("review_already_published", nil)
+	}
+
+	now := time.Now()
+	if err := u.repo.UpdateReviewStatus(ctx, reviewID, editorial.StatusPublished, &now); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return u.GetReview(ctx, reviewID)
+}
+
+// UnpublishReview moves a published review back to draft
+func (u *EditorialUsecase) UnpublishReview(ctx context.Context, reviewID int64) (*editorial.ReviewResponse, error) {
+	review, err := u.findReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == editorial.StatusDraft {
+		return nil, apperr.Conflict("review_not_published", nil)
+	}
+
+	if err := u.repo.UpdateReviewStatus(ctx, reviewID, editorial.StatusDraft, nil); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return u.GetReview(ctx, reviewID)
+}
+
+// DeleteReview permanently removes an editorial review
+func (u *EditorialUsecase) DeleteReview(ctx context.Context, reviewID int64) error {
+	if _, err := u.findReview(ctx, reviewID); err != nil {
+		return err
+	}
+
+	if err := u.repo.DeleteReview(ctx, reviewID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+func (u *EditorialUsecase) findReview(ctx context.Context, reviewID int64) (*editorial.EditorialReview, error) {
+	review, err := u.repo.FindReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if review == nil {
+		return nil, apperr.NotFound("review_not_found", nil)
+	}
+	return review, nil
+}
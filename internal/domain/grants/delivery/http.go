@@ -0,0 +1,109 @@
+package delivery
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/grants"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type GrantUsecase interface {
+	ImportGrant(ctx context.Context, actorExtID string, movieID int64, req grants.ImportGrantRequest, file multipart.File, fileHeader *multipart.FileHeader) (*grants.ImportGrantResponse, error)
+	GetGrants(ctx context.Context, movieID int64) ([]grants.GrantSummary, error)
+	RedeemGrant(ctx context.Context, req grants.RedeemGrantRequest) (*grants.RedeemGrantResponse, error)
+}
+
+type GrantHandler struct {
+	ctx     context.Context
+	usecase GrantUsecase
+}
+
+func NewGrantHandler(ctx context.Context, usecase GrantUsecase) *GrantHandler {
+	return &GrantHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// ImportGrant grants complimentary access to a movie to the emails of a CSV and emails their redemption links (Admin only)
+// POST /api/v1/admin/movies/:id/access-grants (multipart: file, campaign, access_days, redeem_days)
+func (h *GrantHandler) ImportGrant(c echo.Context) error {
+	ctx := h.ctx
+
+	actorExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || actorExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req grants.ImportGrantRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	file, fileHeader, err := c.Request().FormFile("file")
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "file_required", err.Error())
+	}
+	defer file.Close()
+
+	result, err := h.usecase.ImportGrant(ctx, actorExtID, movieID, req, file, fileHeader)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "access_grant_imported", result)
+}
+
+// GetGrants lists the access grant campaigns of a movie with their redemption counts (Admin only)
+// GET /api/v1/admin/movies/:id/access-grants
+func (h *GrantHandler) GetGrants(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetGrants(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// RedeemGrant starts the complimentary access of an emailed redemption link
+// POST /api/v1/access-grants/redeem
+func (h *GrantHandler) RedeemGrant(c echo.Context) error {
+	ctx := h.ctx
+
+	var req grants.RedeemGrantRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.RedeemGrant(ctx, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "access_grant_redeemed", result)
+}
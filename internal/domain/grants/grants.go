@@ -0,0 +1,109 @@
+package grants
+
+import "time"
+
+// Recipient statuses
+const (
+	StatusInvited  = "INVITED"
+	StatusRedeemed = "REDEEMED"
+)
+
+// MaxRecipients is the most emails one import may grant access to
+const MaxRecipients = 5000
+
+// AccessGrant is a campaign giving complimentary, time-limited access to a movie,
+// e.g. press screeners or giveaway winners
+type AccessGrant struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID    int64     `json:"movie_id" gorm:"not null;index"`
+	Campaign   string    `json:"campaign" gorm:"type:varchar(100);not null"`
+	AccessDays int       `json:"access_days" gorm:"not null"` // counted from redemption
+	RedeemBy   time.Time `json:"redeem_by" gorm:"not null"`   // links stop working afterwards
+	CreatedBy  string    `json:"created_by" gorm:"type:varchar(50);not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for AccessGrant
+func (AccessGrant) TableName() string {
+	return "access_grants"
+}
+
+// AccessGrantRecipient is an email of a campaign, its redemption link carries a token only stored as its hash
+type AccessGrantRecipient struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	GrantID    int64      `json:"grant_id" gorm:"not null"`
+	Email      string     `json:"email" gorm:"type:varchar(255);not null"`
+	UserExtID  string     `json:"user_ext_id" gorm:"column:user_ext_id;not null"`
+	TokenHash  string     `json:"-" gorm:"type:char(64);unique;not null"`
+	Status     string     `json:"status" gorm:"type:enum('INVITED','REDEEMED');default:'INVITED'"`
+	NewAccount bool       `json:"new_account"` // the import created the account, it has no password yet
+	RedeemedAt *time.Time `json:"redeemed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for AccessGrantRecipient
+func (AccessGrantRecipient) TableName() string {
+	return "access_grant_recipients"
+}
+
+// InvitedUser is an account created by an import for an email without one
+type InvitedUser struct {
+	ExtID    string
+	Name     string
+	Email    string
+	Password string // bcrypt hash of a random password nobody knows
+}
+
+// Invitation is a recipient with the raw token of its link, only known until the email is sent
+type Invitation struct {
+	Recipient AccessGrantRecipient
+	Token     string
+}
+
+// Request DTOs
+
+// ImportGrantRequest is the form sent along with the CSV of emails
+type ImportGrantRequest struct {
+	Campaign   string `form:"campaign" validate:"required,min=3,max=100"`
+	AccessDays int    `form:"access_days" validate:"required,min=1,max=365"`
+	RedeemDays int    `form:"redeem_days" validate:"required,min=1,max=365"` // how long the links can be redeemed
+}
+
+// RedeemGrantRequest redeems the token of an emailed link
+type RedeemGrantRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Response DTOs
+
+// RejectedRow is a CSV row that was not imported
+type RejectedRow struct {
+	Line   int    `json:"line"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// ImportGrantResponse summarizes an import, links are emailed in the background
+type ImportGrantResponse struct {
+	Grant       AccessGrant   `json:"grant"`
+	Invited     int           `json:"invited"`
+	NewAccounts int           `json:"new_accounts"`
+	Duplicates  int           `json:"duplicates"`
+	Rejected    []RejectedRow `json:"rejected"`
+}
+
+// GrantSummary is a campaign of a movie with its redemption counts
+type GrantSummary struct {
+	AccessGrant
+	Recipients int64 `json:"recipients"`
+	Redeemed   int64 `json:"redeemed"`
+}
+
+// RedeemGrantResponse is the access granted by a redeemed link.
+// PasswordSetupToken is set for accounts created by the import, it is used with the reset password endpoint.
+type RedeemGrantResponse struct {
+	MovieID            int64     `json:"movie_id"`
+	MovieTitle         string    `json:"movie_title"`
+	AccessExpiresAt    time.Time `json:"access_expires_at"`
+	PasswordSetupToken string    `json:"password_setup_token,omitempty"`
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/grants"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"gorm.io/gorm"
+)
+
+type GrantRepository struct {
+	db *gorm.DB
+}
+
+func NewGrantRepository(db *gorm.DB) *GrantRepository {
+	return &GrantRepository{db: db}
+}
+
+// grantMovie is the part of a movie needed to grant access to it
+type grantMovie struct {
+	Title     string
+	TakenDown bool
+}
+
+// FindMovie returns the title of a movie and whether it is taken down, found is false if it does not exist
+func (r *GrantRepository) FindMovie(ctx context.Context, movieID int64) (title string, takenDown bool, found bool, err error) {
+	var movie grantMovie
+	err = r.db.WithContext(ctx).
+		Table("movies").
+		Select("title, taken_down_at IS NOT NULL AS taken_down").
+		Where("id = ? AND deleted_at IS NULL", movieID).
+		Take(&movie).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", false, false, nil
+		}
+		return "", false, false, err
+	}
+	return movie.Title, movie.TakenDown, true, nil
+}
+
+// accountEmail is the ext id of an account with its email
+type accountEmail struct {
+	ExtID string
+	Email string
+}
+
+// FindUsersByEmails returns the ext ids of the accounts with the emails, keyed by the lowercased email
+func (r *GrantRepository) FindUsersByEmails(ctx context.Context, emails []string) (map[string]string, error) {
+	found := make(map[string]string, len(emails))
+	for start := 0; start < len(emails); start += 500 {
+		end := min(start+500, len(emails))
+		var accounts []accountEmail
+		err := r.db.WithContext(ctx).
+			Table("users").
+			Select("ext_id, email").
+			Where("email IN ?", emails[start:end]).
+			Find(&accounts).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			found[strings.ToLower(account.Email)] = account.ExtID
+		}
+	}
+	return found, nil
+}
+
+// PasswordSetupPending reports whether an invited account still has to choose its password
+func (r *GrantRepository) PasswordSetupPending(ctx context.Context, userExtID string) (bool, error) {
+	var pending []bool
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Where("ext_id = ?", userExtID).
+		Pluck("password_reset_required", &pending).Error
+	if err != nil {
+		return false, err
+	}
+	return len(pending) > 0 && pending[0], nil
+}
+
+// CreateGrant stores a campaign with its recipients and creates the invited accounts in one transaction.
+// The invited accounts have to reset their password before they can sign in.
+func (r *GrantRepository) CreateGrant(ctx context.Context, grant *grants.AccessGrant, invited []grants.InvitedUser, recipients []grants.AccessGrantRecipient) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(grant).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, user := range invited {
+			err := tx.Table("users").Create(map[string]interface{}{
+				"ext_id":                  user.ExtID,
+				"name":                    user.Name,
+				"email":                   user.Email,
+				"password":                user.Password,
+				"role":                    constant.RoleUser,
+				"password_reset_required": true,
+				"invited_at":              now,
+				"created_at":              now,
+				"updated_at":              now,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(recipients) == 0 {
+			return nil
+		}
+		for i := range recipients {
+			recipients[i].GrantID = grant.ID
+		}
+		return tx.CreateInBatches(recipients, 500).Error
+	})
+}
+
+// FindGrantsByMovie returns the campaigns of a movie with their redemption counts, newest first
+func (r *GrantRepository) FindGrantsByMovie(ctx context.Context, movieID int64) ([]grants.GrantSummary, error) {
+	var summaries []grants.GrantSummary
+	err := r.db.WithContext(ctx).
+		Table("access_grants AS g").
+		Select("g.*, COUNT(r.id) AS recipients, COALESCE(SUM(r.status = ?), 0) AS redeemed", grants.StatusRedeemed).
+		Joins("LEFT JOIN access_grant_recipients AS r ON r.grant_id = g.id").
+		Where("g.movie_id = ?", movieID).
+		Group("g.id").
+		Order("g.created_at DESC, g.id DESC").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+// FindRecipientByTokenHash returns the recipient of a redemption link with its campaign, nil if unknown
+func (r *GrantRepository) FindRecipientByTokenHash(ctx context.Context, tokenHash string) (*grants.AccessGrantRecipient, *grants.AccessGrant, error) {
+	var recipient grants.AccessGrantRecipient
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Take(&recipient).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	var grant grants.AccessGrant
+	if err := r.db.WithContext(ctx).Take(&grant, recipient.GrantID).Error; err != nil {
+		return nil, nil, err
+	}
+	return &recipient, &grant, nil
+}
+
+// RedeemRecipient marks an invited recipient redeemed and grants its access until expiresAt in one transaction.
+// setupTokenHash, when set, is stored as a password reset token of the account valid until setupExpiresAt.
+// It returns false when the recipient was redeemed already.
+func (r *GrantRepository) RedeemRecipient(ctx context.Context, recipient grants.AccessGrantRecipient, movieID int64, expiresAt time.Time, setupTokenHash string, setupExpiresAt time.Time) (bool, error) {
+	redeemed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&grants.AccessGrantRecipient{}).
+			Where("id = ? AND status = ?", recipient.ID, grants.StatusInvited).
+			Updates(map[string]interface{}{"status": grants.StatusRedeemed, "redeemed_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		redeemed = true
+
+		err := tx.Table("user_movie_access").Create(map[string]interface{}{
+			"user_ext_id":        recipient.UserExtID,
+			"movie_id":           movieID,
+			"grant_recipient_id": recipient.ID,
+			"access_granted_at":  now,
+			"access_expires_at":  expiresAt,
+			"created_at":         now,
+			"updated_at":         now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if setupTokenHash == "" {
+			return nil
+		}
+		return tx.Table("password_reset_tokens").Create(map[string]interface{}{
+			"user_ext_id": recipient.UserExtID,
+			"token_hash":  setupTokenHash,
+			"expires_at":  setupExpiresAt,
+			"created_at":  now,
+		}).Error
+	})
+	return redeemed, err
+}
@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/grants"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/segmentio/ksuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxImportSize is the largest CSV accepted, MaxRecipients emails fit well below it
+const maxImportSize = 2 << 20
+
+type GrantRepository interface {
+	FindMovie(ctx context.Context, movieID int64) (title string, takenDown bool, found bool, err error)
+	FindUsersByEmails(ctx context.Context, emails []string) (map[string]string, error)
+	PasswordSetupPending(ctx context.Context, userExtID string) (bool, error)
+	CreateGrant(ctx context.Context, grant *grants.AccessGrant, invited []grants.InvitedUser, recipients []grants.AccessGrantRecipient) error
+	FindGrantsByMovie(ctx context.Context, movieID int64) ([]grants.GrantSummary, error)
+	FindRecipientByTokenHash(ctx context.Context, tokenHash string) (*grants.AccessGrantRecipient, *grants.AccessGrant, error)
+	RedeemRecipient(ctx context.Context, recipient grants.AccessGrantRecipient, movieID int64, expiresAt time.Time, setupTokenHash string, setupExpiresAt time.Time) (bool, error)
+}
+
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
+
+// GrantOptions configures the redemption links
+type GrantOptions struct {
+	RedeemURL           string        // page of the frontend that submits the token, the token is added as ?token=
+	PasswordSetupExpiry time.Duration // how long an invited account can use the token returned on redemption
+}
+
+type GrantUsecase struct {
+	repo    GrantRepository
+	mailer  Mailer
+	options GrantOptions
+}
+
+func NewGrantUsecase(repo GrantRepository, mailer Mailer, options GrantOptions) *GrantUsecase {
+	return &GrantUsecase{
+		repo:    repo,
+		mailer:  mailer,
+		options: options,
+	}
+}
+
+// ImportGrant grants complimentary access to a movie to every email of a CSV (Admin only).
+// The first column holds the emails, a header row is skipped. Emails without an account get an invited
+// account that chooses its password after redeeming. Redemption links are emailed in the background.
+func (u *GrantUsecase) ImportGrant(ctx context.Context, actorExtID string, movieID int64, req grants.ImportGrantRequest, file multipart.File, fileHeader *multipart.FileHeader) (*grants.ImportGrantResponse, error) {
+	if fileHeader.Size > maxImportSize {
+		return nil, apperr.Validation("grant_file_too_large", "max 2 MB")
+	}
+
+	title, takenDown, found, err := u.repo.FindMovie(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !found {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+	if takenDown {
+		return nil, apperr.Conflict("movie_taken_down", nil)
+	}
+
+	emails, duplicates, rejected, err := parseEmails(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		return nil, err
+	}
+	if len(emails) == 0 {
+		return nil, apperr.Validation("no_valid_emails", rejected)
+	}
+
+	existing, err := u.repo.FindUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// Invited accounts share the hash of one random password nobody knows, they sign in after choosing their own
+	var unusablePassword string
+	var invited []grants.InvitedUser
+	recipients := make([]grants.AccessGrantRecipient, 0, len(emails))
+	tokens := make([]string, 0, len(emails))
+	for _, email := range emails {
+		userExtID, ok := existing[email]
+		if !ok {
+			if unusablePassword == "" {
+				if unusablePassword, err = newUnusablePassword(); err != nil {
+					return nil, apperr.Internal(err)
+				}
+			}
+			userExtID = "user_" + ksuid.New().String()
+			invited = append(invited, grants.InvitedUser{
+				ExtID:    userExtID,
+				Name:     strings.SplitN(email, "@", 2)[0],
+				Email:    email,
+				Password: unusablePassword,
+			})
+		}
+
+		token, err := newToken()
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		tokens = append(tokens, token)
+		recipients = append(recipients, grants.AccessGrantRecipient{
+			Email:      email,
+			UserExtID:  userExtID,
+			TokenHash:  hashToken(token),
+			Status:     grants.StatusInvited,
+			NewAccount: !ok,
+		})
+	}
+
+	grant := &grants.AccessGrant{
+		MovieID:    movieID,
+		Campaign:   strings.TrimSpace(req.Campaign),
+		AccessDays: req.AccessDays,
+		RedeemBy:   time.Now().AddDate(0, 0, req.RedeemDays),
+		CreatedBy:  actorExtID,
+	}
+	if err := u.repo.CreateGrant(ctx, grant, invited, recipients); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	invitations := make([]grants.Invitation, len(recipients))
+	for i, recipient := range recipients {
+		invitations[i] = grants.Invitation{Recipient: recipient, Token: tokens[i]}
+	}
+	go u.sendInvitations(*grant, title, invitations)
+
+	return &grants.ImportGrantResponse{
+		Grant:       *grant,
+		Invited:     len(recipients),
+		NewAccounts: len(invited),
+		Duplicates:  duplicates,
+		Rejected:    rejected,
+	}, nil
+}
+
+// sendInvitations emails the redemption links one after another, a failed email is only logged
+func (u *GrantUsecase) sendInvitations(grant grants.AccessGrant, movieTitle string, invitations []grants.Invitation) {
+	failed := 0
+	for _, invitation := range invitations {
+		account := ""
+		if invitation.Recipient.NewAccount {
+			account = "We created a CineStream account for this address, after redeeming you choose its password.\n\n"
+		}
+		body := fmt.Sprintf("Hi,\n\n"+
+			"You have been given complimentary access to \"%s\" on CineStream (%s).\n"+
+			"Open the link below before %s to start your %d days of access.\n\n%s\n\n%s"+
+			"If you did not expect this email, you can ignore it.",
+			movieTitle, grant.Campaign, grant.RedeemBy.Format("2 January 2006"), grant.AccessDays,
+			tokenLink(u.options.RedeemURL, invitation.Token), account)
+
+		if err := u.mailer.Send([]string{invitation.Recipient.Email}, fmt.Sprintf("Watch \"%s\" on CineStream", movieTitle), body); err != nil {
+			failed++
+			log.Printf("[ACCESS_GRANT] grant %d: %v", grant.ID, err)
+		}
+	}
+	log.Printf("[ACCESS_GRANT] grant %d: sent %d of %d redemption links", grant.ID, len(invitations)-failed, len(invitations))
+}
+
+// GetGrants lists the campaigns of a movie with their redemption counts (Admin only)
+func (u *GrantUsecase) GetGrants(ctx context.Context, movieID int64) ([]grants.GrantSummary, error) {
+	_, _, found, err := u.repo.FindMovie(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !found {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	summaries, err := u.repo.FindGrantsByMovie(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if summaries == nil {
+		summaries = []grants.GrantSummary{}
+	}
+	return summaries, nil
+}
+
+// RedeemGrant starts the complimentary access of an emailed link, each link can be redeemed once.
+// Accounts created by the import also get a token to choose their password with.
+func (u *GrantUsecase) RedeemGrant(ctx context.Context, req grants.RedeemGrantRequest) (*grants.RedeemGrantResponse, error) {
+	recipient, grant, err := u.repo.FindRecipientByTokenHash(ctx, hashToken(req.Token))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if recipient == nil {
+		return nil, apperr.Validation("invalid_or_expired_grant_token", nil)
+	}
+	if recipient.Status == grants.StatusRedeemed {
+		return nil, apperr.Conflict("grant_already_redeemed", nil)
+	}
+
+	now := time.Now()
+	if now.After(grant.RedeemBy) {
+		return nil, apperr.Validation("invalid_or_expired_grant_token", nil)
+	}
+
+	title, takenDown, found, err := u.repo.FindMovie(ctx, grant.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !found {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+	if takenDown {
+		return nil, apperr.Forbidden("movie_temporarily_unavailable", nil)
+	}
+
+	// An invited account that chose a password in the meantime needs no setup token
+	var setupToken, setupTokenHash string
+	if recipient.NewAccount {
+		pending, err := u.repo.PasswordSetupPending(ctx, recipient.UserExtID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if pending {
+			if setupToken, err = newToken(); err != nil {
+				return nil, apperr.Internal(err)
+			}
+			setupTokenHash = hashToken(setupToken)
+		}
+	}
+
+	expiresAt := now.AddDate(0, 0, grant.AccessDays)
+	redeemed, err := u.repo.RedeemRecipient(ctx, *recipient, grant.MovieID, expiresAt, setupTokenHash, now.Add(u.options.PasswordSetupExpiry))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !redeemed {
+		return nil, apperr.Conflict("grant_already_redeemed", nil)
+	}
+
+	return &grants.RedeemGrantResponse{
+		MovieID:            grant.MovieID,
+		MovieTitle:         title,
+		AccessExpiresAt:    expiresAt,
+		PasswordSetupToken: setupToken,
+	}, nil
+}
+
+// parseEmails reads the emails of the first CSV column, lowercased and without duplicates.
+// A first row that is not an email is taken as the header. Invalid rows are returned as rejected.
+func parseEmails(r io.Reader) (emails []string, duplicates int, rejected []grants.RejectedRow, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	seen := make(map[string]bool)
+	rejected = []grants.RejectedRow{}
+	for {
+		record, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			var parseErr *csv.ParseError
+			if errors.As(readErr, &parseErr) {
+				return nil, 0, nil, apperr.Validation("invalid_csv", parseErr.Error())
+			}
+			return nil, 0, nil, apperr.Internal(readErr)
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff"))
+		if value == "" {
+			continue
+		}
+
+		address, parseErr := mail.ParseAddress(value)
+		if parseErr != nil {
+			if line == 1 && !strings.Contains(value, "@") {
+				continue // header
+			}
+			rejected = append(rejected, grants.RejectedRow{Line: line, Value: value, Reason: "invalid_email"})
+			continue
+		}
+
+		email := strings.ToLower(address.Address)
+		if seen[email] {
+			duplicates++
+			continue
+		}
+		if len(emails) == grants.MaxRecipients {
+			return nil, 0, nil, apperr.Validation("too_many_recipients", fmt.Sprintf("max %d emails per import", grants.MaxRecipients))
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
+	return emails, duplicates, rejected, nil
+}
+
+// tokenLink adds the token as ?token= to a frontend page, only the token is returned when no page is configured
+func tokenLink(page, token string) string {
+	if page == "" {
+		return token
+	}
+
+	link, err := url.Parse(page)
+	if err != nil {
+		return page + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// newToken returns a random token for an emailed link
+func newToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// hashToken returns the SHA256 hex digest stored instead of the raw token
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// newUnusablePassword returns the bcrypt hash of a random password that is thrown away
+func newUnusablePassword() (string, error) {
+	password, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
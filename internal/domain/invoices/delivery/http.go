@@ -0,0 +1,55 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/invoices"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type InvoiceUsecase interface {
+	GetInvoice(ctx context.Context, userExtID string, orderID int64) (*invoices.Document, error)
+}
+
+type InvoiceHandler struct {
+	ctx     context.Context
+	usecase InvoiceUsecase
+}
+
+func NewInvoiceHandler(ctx context.Context, usecase InvoiceUsecase) *InvoiceHandler {
+	return &InvoiceHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// GetInvoice downloads the receipt of a paid order of the current user
+// GET /api/v1/orders/:id/invoice
+func (h *InvoiceHandler) GetInvoice(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_order_id", err.Error())
+	}
+
+	document, err := h.usecase.GetInvoice(ctx, userExtID, orderID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	defer document.Body.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, document.FileName))
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return c.Stream(http.StatusOK, document.ContentType, document.Body)
+}
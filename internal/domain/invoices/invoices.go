@@ -0,0 +1,44 @@
+package invoices
+
+import (
+	"io"
+	"time"
+)
+
+// Invoice is the stored receipt of a paid order, generated once and kept in object storage
+type Invoice struct {
+	OrderID       int64      `json:"order_id" gorm:"primaryKey;autoIncrement:false"`
+	InvoiceNumber string     `json:"invoice_number" gorm:"type:varchar(32);unique;not null"`
+	ObjectName    string     `json:"-" gorm:"type:varchar(255);not null"`
+	EmailedAt     *time.Time `json:"emailed_at,omitempty"` // set once the receipt email was queued
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for Invoice
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// InvoiceOrder is a paid order with everything printed on its receipt
+type InvoiceOrder struct {
+	OrderID           int64
+	UserExtID         string
+	InvoiceNumber     *string // NULL until the order is paid
+	PaymentStatus     string
+	OrderType         string
+	Amount            float64
+	PaymentProvider   string
+	PaymentGatewayRef *string
+	PaidAt            *time.Time
+	Simulated         bool // paid through the development simulation, not a real charge
+	MovieTitle        string
+	CustomerName      string
+	CustomerEmail     string
+}
+
+// Document is a receipt opened for download, the caller closes Body
+type Document struct {
+	FileName    string
+	ContentType string
+	Body        io.ReadCloser
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/invoices"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// FindInvoiceOrder returns an order with its movie and customer, nil if the order does not exist
+func (r *InvoiceRepository) FindInvoiceOrder(ctx context.Context, orderID int64) (*invoices.InvoiceOrder, error) {
+	var order invoices.InvoiceOrder
+	err := r.db.WithContext(ctx).
+		Table("orders").
+		Select("orders.id AS order_id, orders.user_ext_id, orders.invoice_number, orders.payment_status, orders.order_type, "+
+			"orders.amount, orders.payment_provider, orders.payment_gateway_ref, orders.paid_at, orders.simulated_by IS NOT NULL AS simulated, "+
+			"movies.title AS movie_title, users.name AS customer_name, users.email AS customer_email").
+		Joins("JOIN movies ON movies.id = orders.movie_id").
+		Joins("JOIN users ON users.ext_id = orders.user_ext_id").
+		Where("orders.id = ?", orderID).
+		Take(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindInvoice returns the stored receipt of an order, nil if none was generated yet
+func (r *InvoiceRepository) FindInvoice(ctx context.Context, orderID int64) (*invoices.Invoice, error) {
+	var invoice invoices.Invoice
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Take(&invoice).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// SaveInvoice records a generated receipt, a receipt generated concurrently for the same order is kept
+func (r *InvoiceRepository) SaveInvoice(ctx context.Context, invoice *invoices.Invoice) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(invoice).Error
+}
+
+// MarkInvoiceEmailed records that the receipt email of an order was queued
+func (r *InvoiceRepository) MarkInvoiceEmailed(ctx context.Context, orderID int64, emailedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&invoices.Invoice{}).
+		Where("order_id = ?", orderID).
+		Update("emailed_at", emailedAt).Error
+}
+
+// WantsEmailNotifications reads the email channel preference of a user, users without saved preferences get emails
+func (r *InvoiceRepository) WantsEmailNotifications(ctx context.Context, userExtID string) (bool, error) {
+	var enabled []bool
+	err := r.db.WithContext(ctx).
+		Table("user_preferences").
+		Where("user_ext_id = ?", userExtID).
+		Pluck("email_notifications", &enabled).Error
+	if err != nil {
+		return false, err
+	}
+	return len(enabled) == 0 || enabled[0], nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/invoices"
+)
+
+// receiptContentType is served with stored receipts, browsers print them to PDF when needed
+const receiptContentType = "text/html; charset=utf-8"
+
+var receiptTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Receipt {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 640px; margin: 40px auto; }
+h1 { font-size: 24px; margin-bottom: 4px; }
+table { width: 100%; border-collapse: collapse; margin: 24px 0; }
+th, td { text-align: left; padding: 8px; border-bottom: 1px solid #ddd; }
+td.amount, th.amount { text-align: right; }
+.muted { color: #777; font-size: 13px; }
+.notice { border: 1px solid #c00; color: #c00; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>Receipt</h1>
+<p class="muted">{{.Number}} &middot; paid {{.PaidAt}}</p>
+{{if .Simulated}}<p class="notice">Simulated payment in a development environment, nothing was charged.</p>{{end}}
+<p><strong>{{.SellerName}}</strong>{{range .SellerAddress}}<br>{{.}}{{end}}</p>
+<p>Billed to<br><strong>{{.CustomerName}}</strong><br>{{.CustomerEmail}}</p>
+<table>
+<tr><th>Item</th><th class="amount">Amount</th></tr>
+<tr><td>{{.Item}}</td><td class="amount">{{.Amount}}</td></tr>
+<tr><th>Total paid</th><th class="amount">{{.Amount}}</th></tr>
+</table>
+<p class="muted">Order #{{.OrderID}} &middot; paid with {{.Provider}}{{if .Reference}} &middot; reference {{.Reference}}{{end}}</p>
+</body>
+</html>
+`))
+
+// receiptView is what the receipt template prints
+type receiptView struct {
+	Number        string
+	PaidAt        string
+	Simulated     bool
+	SellerName    string
+	SellerAddress []string
+	CustomerName  string
+	CustomerEmail string
+	Item          string
+	Amount        string
+	OrderID       int64
+	Provider      string
+	Reference     string
+}
+
+// renderReceipt returns the HTML receipt of a paid order
+func (u *InvoiceUsecase) renderReceipt(order *invoices.InvoiceOrder) ([]byte, error) {
+	item := fmt.Sprintf("%s (%s)", order.MovieTitle, order.OrderType) // rental or purchase
+	paidAt := time.Now()
+	if order.PaidAt != nil {
+		paidAt = *order.PaidAt
+	}
+	view := receiptView{
+		Number:        *order.InvoiceNumber,
+		PaidAt:        paidAt.Format("2 January 2006 15:04 MST"),
+		Simulated:     order.Simulated,
+		SellerName:    u.options.SellerName,
+		SellerAddress: splitLines(u.options.SellerAddress),
+		CustomerName:  order.CustomerName,
+		CustomerEmail: order.CustomerEmail,
+		Item:          item,
+		Amount:        formatAmount(order.Amount, u.options.Currency),
+		OrderID:       order.OrderID,
+		Provider:      order.PaymentProvider,
+	}
+	if order.PaymentGatewayRef != nil {
+		view.Reference = *order.PaymentGatewayRef
+	}
+
+	var buf bytes.Buffer
+	if err := receiptTemplate.Execute(&buf, view); err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// splitLines splits a multi-line config value into its non-empty lines
+func splitLines(value string) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// formatAmount prints an amount with thousands separators, e.g. IDR 25,000 or USD 4.99.
+// Rupiah amounts have no minor unit on receipts.
+func formatAmount(amount float64, currency string) string {
+	currency = strings.ToUpper(currency)
+	decimals := 2
+	if currency == "IDR" {
+		decimals = 0
+	}
+
+	formatted := fmt.Sprintf("%.*f", decimals, amount)
+	whole, fraction, _ := strings.Cut(formatted, ".")
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	result := grouped.String()
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if negative {
+		result = "-" + result
+	}
+	return currency + " " + result
+}
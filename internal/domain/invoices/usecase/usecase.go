@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/invoices"
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+type InvoiceRepository interface {
+	FindInvoiceOrder(ctx context.Context, orderID int64) (*invoices.InvoiceOrder, error)
+	FindInvoice(ctx context.Context, orderID int64) (*invoices.Invoice, error)
+	SaveInvoice(ctx context.Context, invoice *invoices.Invoice) error
+	MarkInvoiceEmailed(ctx context.Context, orderID int64, emailedAt time.Time) error
+	WantsEmailNotifications(ctx context.Context, userExtID string) (bool, error)
+}
+
+type InvoiceStorage interface {
+	PutInvoice(ctx context.Context, objectName, contentType string, data []byte) error
+	GetInvoice(ctx context.Context, objectName string) (io.ReadCloser, error)
+}
+
+// Notifier queues the payment confirmation, the worker emails it
+type Notifier interface {
+	Notify(ctx context.Context, event notification.Event) error
+}
+
+// InvoiceOptions is what receipts print about the seller, and whether they are emailed once paid
+type InvoiceOptions struct {
+	SellerName    string
+	SellerAddress string // lines separated by newlines
+	Currency      string // e.g. IDR
+	Email         bool
+}
+
+type InvoiceUsecase struct {
+	repo     InvoiceRepository
+	storage  InvoiceStorage
+	notifier Notifier
+	options  InvoiceOptions
+}
+
+func NewInvoiceUsecase(repo InvoiceRepository, storage InvoiceStorage, notifier Notifier, options InvoiceOptions) *InvoiceUsecase {
+	return &InvoiceUsecase{
+		repo:     repo,
+		storage:  storage,
+		notifier: notifier,
+		options:  options,
+	}
+}
+
+// GetInvoice opens the receipt of a paid order of the user, it is generated on the first request
+func (u *InvoiceUsecase) GetInvoice(ctx context.Context, userExtID string, orderID int64) (*invoices.Document, error) {
+	order, err := u.repo.FindInvoiceOrder(ctx, orderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if order == nil || order.UserExtID != userExtID {
+		return nil, apperr.NotFound("order_not_found", nil)
+	}
+	if order.InvoiceNumber == nil {
+		return nil, apperr.Conflict("invoice_not_available", "receipts are issued once the order is paid")
+	}
+
+	invoice, err := u.ensureInvoice(ctx, order)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	body, err := u.storage.GetInvoice(ctx, invoice.ObjectName)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return &invoices.Document{
+		FileName:    invoice.InvoiceNumber + ".html",
+		ContentType: receiptContentType,
+		Body:        body,
+	}, nil
+}
+
+// SendInvoice generates the receipt of a freshly paid order and queues the payment confirmation email,
+// unless receipt emails are off or the customer opted out of emails. Failures are only logged.
+func (u *InvoiceUsecase) SendInvoice(ctx context.Context, orderID int64) {
+	if err := u.sendInvoice(ctx, orderID); err != nil {
+		log.Printf("[INVOICE] order %d: %v", orderID, err)
+	}
+}
+
+func (u *InvoiceUsecase) sendInvoice(ctx context.Context, orderID int64) error {
+	order, err := u.repo.FindInvoiceOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order == nil || order.InvoiceNumber == nil {
+		return fmt.Errorf("order is not paid")
+	}
+
+	invoice, err := u.ensureInvoice(ctx, order)
+	if err != nil {
+		return err
+	}
+	if !u.options.Email || invoice.EmailedAt != nil {
+		return nil
+	}
+
+	wants, err := u.repo.WantsEmailNotifications(ctx, order.UserExtID)
+	if err != nil {
+		return err
+	}
+	if !wants {
+		return nil
+	}
+
+	err = u.notifier.Notify(ctx, notification.Event{
+		Type: notification.EventPaymentConfirmed,
+		To:   []string{order.CustomerEmail},
+		Data: map[string]string{
+			"name":           order.CustomerName,
+			"invoice_number": invoice.InvoiceNumber,
+			"movie_title":    order.MovieTitle,
+			"order_type":     order.OrderType,
+			"amount":         formatAmount(order.Amount, u.options.Currency),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return u.repo.MarkInvoiceEmailed(ctx, orderID, time.Now())
+}
+
+// ensureInvoice returns the stored receipt of a paid order, rendering and storing it the first time
+func (u *InvoiceUsecase) ensureInvoice(ctx context.Context, order *invoices.InvoiceOrder) (*invoices.Invoice, error) {
+	invoice, err := u.repo.FindInvoice(ctx, order.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+	if invoice != nil {
+		return invoice, nil
+	}
+
+	data, err := u.renderReceipt(order)
+	if err != nil {
+		return nil, err
+	}
+	objectName := storage.InvoiceObjectName(*order.InvoiceNumber, "html")
+	if err := u.storage.PutInvoice(ctx, objectName, receiptContentType, data); err != nil {
+		return nil, err
+	}
+
+	invoice = &invoices.Invoice{
+		OrderID:       order.OrderID,
+		InvoiceNumber: *order.InvoiceNumber,
+		ObjectName:    objectName,
+	}
+	if err := u.repo.SaveInvoice(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to save invoice: %w", err)
+	}
+	return invoice, nil
+}
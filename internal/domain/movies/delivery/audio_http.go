@@ -0,0 +1,103 @@
+package delivery
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type AudioUsecase interface {
+	UploadAlternateAudio(ctx context.Context, movieID int64, req movies.UploadAlternateAudioRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.AlternateAudio, error)
+	GetAlternateAudio(ctx context.Context, movieID int64) ([]movies.AlternateAudio, error)
+	DeleteAlternateAudio(ctx context.Context, movieID, trackID int64) error
+}
+
+type AudioHandler struct {
+	ctx     context.Context
+	usecase AudioUsecase
+}
+
+func NewAudioHandler(ctx context.Context, usecase AudioUsecase) *AudioHandler {
+	return &AudioHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// UploadAlternateAudio uploads a dub or audio description, packaged by the worker without re-encoding video (Admin only)
+// POST /api/v1/admin/movies/:id/audio-tracks (multipart: file, language, name, kind)
+func (h *AudioHandler) UploadAlternateAudio(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req movies.UploadAlternateAudioRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	file, fileHeader, err := c.Request().FormFile("file")
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "file_required", err.Error())
+	}
+	defer file.Close()
+
+	result, err := h.usecase.UploadAlternateAudio(ctx, movieID, req, file, fileHeader)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusAccepted, "audio_track_queued", result)
+}
+
+// GetAlternateAudio lists the uploaded audio tracks of a movie with their status (Admin only)
+// GET /api/v1/admin/movies/:id/audio-tracks
+func (h *AudioHandler) GetAlternateAudio(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetAlternateAudio(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// DeleteAlternateAudio removes an uploaded audio track (Admin only)
+// DELETE /api/v1/admin/movies/:id/audio-tracks/:trackID
+func (h *AudioHandler) DeleteAlternateAudio(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	trackID, err := strconv.ParseInt(c.Param("trackID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_track_id", err.Error())
+	}
+
+	if err := h.usecase.DeleteAlternateAudio(ctx, movieID, trackID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -14,6 +14,8 @@ type GenreUsecase interface {
 	GetAllGenres(ctx context.Context) (*movies.GenreListResponse, error)
 	CreateGenre(ctx context.Context, req movies.GenreRequest) (*movies.Genre, error)
 	DeleteGenre(ctx context.Context, genreID int) error
+	UpdateGenre(ctx context.Context, genreID int, req movies.UpdateGenreRequest) (*movies.Genre, error)
+	GetGenreMovies(ctx context.Context, genreID int, page, limit int, sort string) (*movies.GenreMoviesResponse, error)
 }
 
 type GenreHandler struct {
@@ -35,12 +37,7 @@ func (h *GenreHandler) GetAllGenres(c echo.Context) error {
 
 	result, err := h.usecase.GetAllGenres(ctx)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "success", result)
@@ -62,12 +59,7 @@ func (h *GenreHandler) CreateGenre(c echo.Context) error {
 
 	result, err := h.usecase.CreateGenre(ctx, req)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusCreated, "genre_created", result)
@@ -85,13 +77,61 @@ func (h *GenreHandler) DeleteGenre(c echo.Context) error {
 
 	err = h.usecase.DeleteGenre(ctx, genreID)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// GetGenreMovies returns the movies of a genre with result counts (Public)
+// GET /api/v1/genres/:id/movies?page=1&limit=12&sort=popular
+func (h *GenreHandler) GetGenreMovies(c echo.Context) error {
+	ctx := withRequester(h.ctx, c)
+
+	genreID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_genre_id", err.Error())
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetGenreMovies(ctx, genreID, page, limit, c.QueryParam("sort"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"genre":      result.Genre,
+		"data":       result.Movies,
+		"pagination": result.Pagination,
+	})
+}
+
+// UpdateGenre updates genre name, description and artwork (Admin only)
+// PUT /api/v1/admin/genres/:id
+func (h *GenreHandler) UpdateGenre(c echo.Context) error {
+	ctx := h.ctx
+
+	genreID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_genre_id", err.Error())
+	}
+
+	var req movies.UpdateGenreRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdateGenre(ctx, genreID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "genre_updated", result)
+}
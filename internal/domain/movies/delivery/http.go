@@ -8,16 +8,20 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/constant"
 	"github.com/martinmanurung/cinestream/pkg/response"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
 )
 
 type MovieUsecase interface {
-	UploadMovie(ctx context.Context, req movies.UploadMovieRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.UploadMovieResponse, error)
-	GetMovieList(ctx context.Context, page, limit int, genre string) (*movies.MovieListWithPagination, error)
+	UploadMovie(ctx context.Context, adminExtID string, req movies.UploadMovieRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.UploadMovieResponse, error)
+	GetMovieList(ctx context.Context, page, limit int, genre, sort, accessibility string) (*movies.MovieListWithPagination, error)
 	GetMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error)
 	UpdateMovie(ctx context.Context, movieID int64, req movies.UpdateMovieRequest) error
 	DeleteMovie(ctx context.Context, movieID int64) error
 	GetAllMoviesAdmin(ctx context.Context, page, limit int, status string) (*movies.MovieListWithPagination, error)
+	GetMovieAdmin(ctx context.Context, movieID int64) (*movies.AdminMovieDetailResponse, error)
+	GetCatalogStats(ctx context.Context) (*movies.CatalogStats, error)
 }
 
 type MovieHandler struct {
@@ -35,7 +39,7 @@ func NewMovieHandler(ctx context.Context, usecase MovieUsecase) *MovieHandler {
 // UploadMovie handles movie upload (Admin only)
 // POST /api/v1/admin/movies
 func (h *MovieHandler) UploadMovie(c echo.Context) error {
-	ctx := h.ctx
+	ctx := tracing.WithRequest(h.ctx, c.Request())
 
 	// Parse multipart form
 	if err := c.Request().ParseMultipartForm(100 << 20); err != nil { // 100 MB max
@@ -66,24 +70,20 @@ func (h *MovieHandler) UploadMovie(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, "file_too_large", "maximum file size is 2GB")
 	}
 
-	// Call usecase
-	result, err := h.usecase.UploadMovie(ctx, req, file, fileHeader)
+	// Call usecase, the uploader is notified once transcoding completes
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	result, err := h.usecase.UploadMovie(ctx, adminExtID, req, file, fileHeader)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusAccepted, result.Message, result)
 }
 
 // GetMovieList returns paginated list of movies (Public)
-// GET /api/v1/movies?page=1&limit=12&genre=action
+// GET /api/v1/movies?page=1&limit=12&genre=action&sort=popular
 func (h *MovieHandler) GetMovieList(c echo.Context) error {
-	ctx := h.ctx
+	ctx := withRequester(h.ctx, c)
 
 	// Parse query params
 	page, _ := strconv.Atoi(c.QueryParam("page"))
@@ -97,16 +97,13 @@ func (h *MovieHandler) GetMovieList(c echo.Context) error {
 	}
 
 	genre := c.QueryParam("genre")
+	sort := c.QueryParam("sort") // popular (default)|newest|price_asc|price_desc|title|duration|views
+	accessibility := c.QueryParam("accessibility") // cc,ad,no_flashing
 
 	// Call usecase
-	result, err := h.usecase.GetMovieList(ctx, page, limit, genre)
+	result, err := h.usecase.GetMovieList(ctx, page, limit, genre, sort, accessibility)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -119,7 +116,7 @@ func (h *MovieHandler) GetMovieList(c echo.Context) error {
 // GetMovieDetail returns detailed movie information (Public)
 // GET /api/v1/movies/:id
 func (h *MovieHandler) GetMovieDetail(c echo.Context) error {
-	ctx := h.ctx
+	ctx := withRequester(h.ctx, c)
 
 	// Parse movie ID from URL
 	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -130,12 +127,7 @@ func (h *MovieHandler) GetMovieDetail(c echo.Context) error {
 	// Call usecase
 	result, err := h.usecase.GetMovieDetail(ctx, movieID)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "success", result)
@@ -166,12 +158,7 @@ func (h *MovieHandler) UpdateMovie(c echo.Context) error {
 	// Call usecase
 	err = h.usecase.UpdateMovie(ctx, movieID, req)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "movie_updated_successfully", nil)
@@ -191,19 +178,14 @@ func (h *MovieHandler) DeleteMovie(c echo.Context) error {
 	// Call usecase
 	err = h.usecase.DeleteMovie(ctx, movieID)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
 
-// GetAllMoviesAdmin returns all movies with any status (Admin only)
-// GET /api/v1/admin/movies?page=1&limit=12&status=PENDING
+// GetAllMoviesAdmin returns the movies of the given upload statuses, all of them without status (Admin only)
+// GET /api/v1/admin/movies?page=1&limit=12&status=PENDING,FAILED
 func (h *MovieHandler) GetAllMoviesAdmin(c echo.Context) error {
 	ctx := h.ctx
 
@@ -218,17 +200,12 @@ func (h *MovieHandler) GetAllMoviesAdmin(c echo.Context) error {
 		limit = 12
 	}
 
-	status := c.QueryParam("status") // PENDING, PROCESSING, READY, FAILED
+	status := c.QueryParam("status") // comma separated: PENDING, PROCESSING, READY, FAILED
 
 	// Call usecase
 	result, err := h.usecase.GetAllMoviesAdmin(ctx, page, limit, status)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -238,3 +215,49 @@ func (h *MovieHandler) GetAllMoviesAdmin(c echo.Context) error {
 	})
 }
 
+// GetMovieAdmin returns a movie of any status with its video and genre IDs (Admin only)
+// GET /api/v1/admin/movies/:id
+func (h *MovieHandler) GetMovieAdmin(c echo.Context) error {
+	ctx := h.ctx
+
+	// Parse movie ID from URL
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	// Call usecase
+	result, err := h.usecase.GetMovieAdmin(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// withRequester carries the authenticated user (if any) so catalog responses can include in_watchlist
+// and soft-launched titles are shown to their allowed viewers
+func withRequester(ctx context.Context, c echo.Context) context.Context {
+	if userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string); ok && userExtID != "" {
+		ctx = context.WithValue(ctx, constant.CtxKeyUserExtID, userExtID)
+		if role, ok := c.Get(string(constant.CtxKeyUserRole)).(string); ok {
+			ctx = context.WithValue(ctx, constant.CtxKeyUserRole, role)
+		}
+	}
+	return ctx
+}
+
+// GetCatalogStats returns the number of public titles, per genre and overall, for the marketing site
+// GET /api/v1/catalog/stats
+func (h *MovieHandler) GetCatalogStats(c echo.Context) error {
+	ctx := h.ctx
+
+	result, err := h.usecase.GetCatalogStats(ctx)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	// Same for every visitor, CDNs may keep it until the next catalog change purges it
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return response.Success(c, http.StatusOK, "success", result)
+}
@@ -0,0 +1,119 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type LicensingUsecase interface {
+	CreateLicenseWindow(ctx context.Context, movieID int64, req movies.CreateLicenseWindowRequest) (*movies.LicenseWindow, error)
+	GetLicenseWindows(ctx context.Context, movieID int64) ([]movies.LicenseWindow, error)
+	DeleteLicenseWindow(ctx context.Context, movieID, windowID int64) error
+	GetExpiringLicenses(ctx context.Context, days int) ([]movies.ExpiringLicenseWindow, error)
+}
+
+type LicensingHandler struct {
+	ctx     context.Context
+	usecase LicensingUsecase
+}
+
+func NewLicensingHandler(ctx context.Context, usecase LicensingUsecase) *LicensingHandler {
+	return &LicensingHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// CreateLicenseWindow adds a per-region licensing window to a movie (Admin only)
+// POST /api/v1/admin/movies/:id/license-windows
+func (h *LicensingHandler) CreateLicenseWindow(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req movies.CreateLicenseWindowRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.CreateLicenseWindow(ctx, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "license_window_created", result)
+}
+
+// GetLicenseWindows lists the licensing windows of a movie (Admin only)
+// GET /api/v1/admin/movies/:id/license-windows
+func (h *LicensingHandler) GetLicenseWindows(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetLicenseWindows(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// DeleteLicenseWindow removes a licensing window of a movie (Admin only)
+// DELETE /api/v1/admin/movies/:id/license-windows/:windowID
+func (h *LicensingHandler) DeleteLicenseWindow(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	windowID, err := strconv.ParseInt(c.Param("windowID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_window_id", err.Error())
+	}
+
+	if err := h.usecase.DeleteLicenseWindow(ctx, movieID, windowID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetExpiringLicenses reports the licensing windows closing soon without a renewal (Admin only)
+// GET /api/v1/admin/licensing/expiring?days=30
+func (h *LicensingHandler) GetExpiringLicenses(c echo.Context) error {
+	ctx := h.ctx
+
+	days := 0
+	if value := c.QueryParam("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return response.Error(c, http.StatusBadRequest, "invalid_days", "days must be a positive number")
+		}
+		days = parsed
+	}
+
+	result, err := h.usecase.GetExpiringLicenses(ctx, days)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
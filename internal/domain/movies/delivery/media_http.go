@@ -0,0 +1,161 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type MediaUsecase interface {
+	UploadMovieMedia(ctx context.Context, movieID int64, kind string, file multipart.File, fileHeader *multipart.FileHeader) (*movies.MovieMediaResponse, error)
+	OpenMovieMedia(ctx context.Context, movieID int64, kind, expires, signature string) (io.ReadCloser, *storage.MediaObject, string, error)
+	GetMediaReviews(ctx context.Context, status string) ([]movies.MediaReview, error)
+	ApproveMediaReview(ctx context.Context, reviewID int64, reviewerExtID string) (*movies.MovieMediaResponse, error)
+	RejectMediaReview(ctx context.Context, reviewID int64, reviewerExtID string) error
+}
+
+type MediaHandler struct {
+	ctx     context.Context
+	usecase MediaUsecase
+}
+
+func NewMediaHandler(ctx context.Context, usecase MediaUsecase) *MediaHandler {
+	return &MediaHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// UploadMovieMedia uploads a poster or trailer to the private media bucket (Admin only)
+// POST /api/v1/admin/movies/:id/media/:kind (kind: poster | trailer)
+func (h *MediaHandler) UploadMovieMedia(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	file, fileHeader, err := c.Request().FormFile("file")
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "file_required", err.Error())
+	}
+	defer file.Close()
+
+	result, err := h.usecase.UploadMovieMedia(ctx, movieID, c.Param("kind"), file, fileHeader)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	if result.ReviewID != nil {
+		return response.Success(c, http.StatusAccepted, "media_held_for_review", result)
+	}
+
+	return response.Success(c, http.StatusCreated, "media_uploaded", result)
+}
+
+// GetMovieMedia streams a poster or trailer from the media bucket (Public, signed for private movies)
+// GET /api/v1/media/movies/:id/:kind?expires=...&signature=...
+func (h *MediaHandler) GetMovieMedia(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	reader, info, visibility, err := h.usecase.OpenMovieMedia(ctx, movieID, c.Param("kind"), c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	defer reader.Close()
+
+	// Public media can be cached by browsers and CDNs, private media only by the browser
+	header := c.Response().Header()
+	if visibility == movies.VisibilityPrivate {
+		header.Set("Cache-Control", "private, max-age=300")
+	} else {
+		header.Set("Cache-Control", "public, max-age=86400")
+	}
+	etag := fmt.Sprintf("\"%s\"", info.ETag)
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(info.Size, 10))
+
+	return c.Stream(http.StatusOK, contentType, reader)
+}
+
+// GetMediaReviews lists uploads held back by content moderation, newest first (Admin only)
+// GET /api/v1/admin/media-reviews?status=PENDING
+func (h *MediaHandler) GetMediaReviews(c echo.Context) error {
+	ctx := h.ctx
+
+	result, err := h.usecase.GetMediaReviews(ctx, c.QueryParam("status"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// ApproveMediaReview publishes a held upload despite the moderation verdict (Admin only)
+// POST /api/v1/admin/media-reviews/:reviewID/approve
+func (h *MediaHandler) ApproveMediaReview(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	reviewID, err := strconv.ParseInt(c.Param("reviewID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+
+	result, err := h.usecase.ApproveMediaReview(ctx, reviewID, adminExtID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "media_review_approved", result)
+}
+
+// RejectMediaReview deletes a held upload (Admin only)
+// POST /api/v1/admin/media-reviews/:reviewID/reject
+func (h *MediaHandler) RejectMediaReview(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	reviewID, err := strconv.ParseInt(c.Param("reviewID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_review_id", err.Error())
+	}
+
+	if err := h.usecase.RejectMediaReview(ctx, reviewID, adminExtID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
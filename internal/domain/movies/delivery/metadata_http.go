@@ -0,0 +1,55 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type MetadataUsecase interface {
+	EnrichMovie(ctx context.Context, movieID int64, req movies.EnrichMovieRequest) (*movies.EnrichMovieResponse, error)
+}
+
+type MetadataHandler struct {
+	ctx     context.Context
+	usecase MetadataUsecase
+}
+
+func NewMetadataHandler(ctx context.Context, usecase MetadataUsecase) *MetadataHandler {
+	return &MetadataHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// EnrichMovie fills in empty metadata of a movie from TMDB (Admin only)
+// POST /api/v1/admin/movies/:id/enrich (optional body: title, year, overwrite)
+func (h *MetadataHandler) EnrichMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	// The body is optional, by default the movie's own title and year are searched
+	var req movies.EnrichMovieRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.EnrichMovie(ctx, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_enriched", result)
+}
@@ -0,0 +1,81 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type PersonUsecase interface {
+	GetPersonMovies(ctx context.Context, personID int64, page, limit int, sort string) (*movies.PersonMoviesResponse, error)
+	UpdatePerson(ctx context.Context, personID int64, req movies.UpdatePersonRequest) (*movies.Person, error)
+}
+
+type PersonHandler struct {
+	ctx     context.Context
+	usecase PersonUsecase
+}
+
+func NewPersonHandler(ctx context.Context, usecase PersonUsecase) *PersonHandler {
+	return &PersonHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// GetPersonMovies returns the movies directed by a person with result counts (Public)
+// GET /api/v1/people/:id/movies?page=1&limit=12&sort=popular
+func (h *PersonHandler) GetPersonMovies(c echo.Context) error {
+	ctx := withRequester(h.ctx, c)
+
+	personID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_person_id", err.Error())
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetPersonMovies(ctx, personID, page, limit, c.QueryParam("sort"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"person":     result.Person,
+		"data":       result.Movies,
+		"pagination": result.Pagination,
+	})
+}
+
+// UpdatePerson updates a person's name, bio and photo (Admin only)
+// PUT /api/v1/admin/people/:id
+func (h *PersonHandler) UpdatePerson(c echo.Context) error {
+	ctx := h.ctx
+
+	personID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_person_id", err.Error())
+	}
+
+	var req movies.UpdatePersonRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdatePerson(ctx, personID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "person_updated", result)
+}
@@ -0,0 +1,59 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type PopularUsecase interface {
+	GetPopularMovies(ctx context.Context, days, limit int) (*movies.PopularMoviesResponse, error)
+	GetTrendingMovies(ctx context.Context, limit int) ([]movies.MovieListResponse, error)
+}
+
+type PopularHandler struct {
+	ctx     context.Context
+	usecase PopularUsecase
+}
+
+func NewPopularHandler(ctx context.Context, usecase PopularUsecase) *PopularHandler {
+	return &PopularHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// GetPopularMovies returns the titles watched in the last days, ranked by popularity score (Public)
+// GET /api/v1/movies/popular?days=7&limit=10
+func (h *PopularHandler) GetPopularMovies(c echo.Context) error {
+	ctx := withRequester(h.ctx, c)
+
+	days, _ := strconv.Atoi(c.QueryParam("days"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetPopularMovies(ctx, days, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "popular_movies_retrieved", result)
+}
+
+// GetTrendingMovies returns the titles with the highest popularity score (Public)
+// GET /api/v1/movies/trending?limit=20
+func (h *PopularHandler) GetTrendingMovies(c echo.Context) error {
+	ctx := withRequester(h.ctx, c)
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetTrendingMovies(ctx, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "trending_movies_retrieved", result)
+}
@@ -0,0 +1,166 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type PublishingUsecase interface {
+	PublishMovie(ctx context.Context, movieID int64) error
+	UnpublishMovie(ctx context.Context, movieID int64) error
+	SoftLaunchMovie(ctx context.Context, movieID int64) error
+	ReleaseMovie(ctx context.Context, movieID int64) error
+	GetBetaViewers(ctx context.Context, movieID int64) ([]movies.MovieBetaViewer, error)
+	AddBetaViewers(ctx context.Context, adminExtID string, movieID int64, req movies.AddBetaViewersRequest) ([]movies.MovieBetaViewer, error)
+	RemoveBetaViewer(ctx context.Context, movieID int64, userExtID string) error
+}
+
+type PublishingHandler struct {
+	ctx     context.Context
+	usecase PublishingUsecase
+}
+
+func NewPublishingHandler(ctx context.Context, usecase PublishingUsecase) *PublishingHandler {
+	return &PublishingHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// PublishMovie lists an unpublished movie in the catalog again (Admin only)
+// POST /api/v1/admin/movies/:id/publish
+func (h *PublishingHandler) PublishMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.PublishMovie(ctx, movieID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_published", nil)
+}
+
+// UnpublishMovie pulls a movie from the catalog without touching orders or paid access (Admin only)
+// POST /api/v1/admin/movies/:id/unpublish
+func (h *PublishingHandler) UnpublishMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.UnpublishMovie(ctx, movieID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_unpublished", nil)
+}
+
+// SoftLaunchMovie limits a movie to testers and its allowlist (Admin only)
+// POST /api/v1/admin/movies/:id/soft-launch
+func (h *PublishingHandler) SoftLaunchMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.SoftLaunchMovie(ctx, movieID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_soft_launched", nil)
+}
+
+// ReleaseMovie ends the soft launch, the movie is listed for everyone (Admin only)
+// POST /api/v1/admin/movies/:id/release
+func (h *PublishingHandler) ReleaseMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.ReleaseMovie(ctx, movieID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_released", nil)
+}
+
+// GetBetaViewers lists the allowlist of a movie (Admin only)
+// GET /api/v1/admin/movies/:id/beta-viewers
+func (h *PublishingHandler) GetBetaViewers(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetBetaViewers(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// AddBetaViewers puts users on the allowlist of a movie (Admin only)
+// POST /api/v1/admin/movies/:id/beta-viewers
+func (h *PublishingHandler) AddBetaViewers(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req movies.AddBetaViewersRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.AddBetaViewers(ctx, adminExtID, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "beta_viewers_added", result)
+}
+
+// RemoveBetaViewer takes a user off the allowlist of a movie (Admin only)
+// DELETE /api/v1/admin/movies/:id/beta-viewers/:userExtID
+func (h *PublishingHandler) RemoveBetaViewer(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.RemoveBetaViewer(ctx, movieID, c.Param("userExtID")); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "beta_viewer_removed", nil)
+}
@@ -0,0 +1,84 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
+)
+
+type QueueUsecase interface {
+	GetTranscodingQueue(ctx context.Context, limit int) (*queue.PendingJobs, error)
+	EnqueueTranscodingJob(ctx context.Context, adminExtID string, req movies.EnqueueTranscodingJobRequest) (*queue.TranscodingJob, error)
+	RemoveTranscodingJob(ctx context.Context, jobID string) error
+}
+
+type QueueHandler struct {
+	ctx     context.Context
+	usecase QueueUsecase
+}
+
+func NewQueueHandler(ctx context.Context, usecase QueueUsecase) *QueueHandler {
+	return &QueueHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// GetTranscodingQueue lists the pending transcoding jobs without taking them off the queue,
+// the jobs the workers hold and the backlog of every worker (Admin only)
+// GET /api/v1/admin/transcoding/jobs?limit=50
+func (h *QueueHandler) GetTranscodingQueue(c echo.Context) error {
+	ctx := h.ctx
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetTranscodingQueue(ctx, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "transcoding_jobs_retrieved", result)
+}
+
+// EnqueueTranscodingJob queues a transcode with custom parameters, e.g. only the 480p rendition (Admin only)
+// POST /api/v1/admin/transcoding/jobs
+func (h *QueueHandler) EnqueueTranscodingJob(c echo.Context) error {
+	ctx := tracing.WithRequest(h.ctx, c.Request())
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	var req movies.EnqueueTranscodingJobRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.EnqueueTranscodingJob(ctx, adminExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusAccepted, "transcoding_job_queued", result)
+}
+
+// RemoveTranscodingJob takes a pending job off the queue, a job a worker already took keeps running (Admin only)
+// DELETE /api/v1/admin/transcoding/jobs/:jobID
+func (h *QueueHandler) RemoveTranscodingJob(c echo.Context) error {
+	ctx := h.ctx
+
+	if err := h.usecase.RemoveTranscodingJob(ctx, c.Param("jobID")); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "transcoding_job_removed", nil)
+}
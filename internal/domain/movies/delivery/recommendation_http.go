@@ -0,0 +1,48 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type RecommendationUsecase interface {
+	GetRecommendations(ctx context.Context, userExtID string, limit int) (*movies.RecommendationsResponse, error)
+}
+
+type RecommendationHandler struct {
+	ctx     context.Context
+	usecase RecommendationUsecase
+}
+
+func NewRecommendationHandler(ctx context.Context, usecase RecommendationUsecase) *RecommendationHandler {
+	return &RecommendationHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// GetRecommendations suggests movies based on the genres of the user's rented and watched titles
+// GET /api/v1/users/me/recommendations?limit=12
+func (h *RecommendationHandler) GetRecommendations(c echo.Context) error {
+	ctx := withRequester(h.ctx, c)
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetRecommendations(ctx, userExtID, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "recommendations_retrieved", result)
+}
@@ -0,0 +1,133 @@
+package delivery
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type SEOUsecase interface {
+	GetMovieMeta(ctx context.Context, movieID int64) (*movies.MovieMeta, error)
+	GetMovieSEO(ctx context.Context, movieID int64) (*movies.MovieSEOResponse, error)
+	UpdateMovieSEO(ctx context.Context, movieID int64, req movies.UpdateMovieSEORequest) (*movies.MovieSEOResponse, error)
+	ClearMovieSEO(ctx context.Context, movieID int64) error
+	GetSitemap(ctx context.Context) ([]movies.SitemapURL, error)
+}
+
+type SEOHandler struct {
+	ctx     context.Context
+	usecase SEOUsecase
+}
+
+func NewSEOHandler(ctx context.Context, usecase SEOUsecase) *SEOHandler {
+	return &SEOHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// sitemapURLSet is the root element of a sitemap, see sitemaps.org
+type sitemapURLSet struct {
+	XMLName xml.Name            `xml:"urlset"`
+	XMLNS   string              `xml:"xmlns,attr"`
+	URLs    []movies.SitemapURL `xml:"url"`
+}
+
+// GetMovieMeta returns the title, description, canonical URL and image for the <head> of a movie page
+// GET /api/v1/movies/:id/meta
+func (h *SEOHandler) GetMovieMeta(c echo.Context) error {
+	ctx := withRequester(h.ctx, c)
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetMovieMeta(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetSitemap lists the movie pages visitors can watch as a sitemap.xml
+// GET /api/v1/sitemap.xml
+func (h *SEOHandler) GetSitemap(c echo.Context) error {
+	ctx := h.ctx
+
+	urls, err := h.usecase.GetSitemap(ctx)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=3600")
+	return c.XML(http.StatusOK, sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: urls})
+}
+
+// GetMovieSEO returns the SEO overrides of a movie and the resulting meta (Admin only)
+// GET /api/v1/admin/movies/:id/seo
+func (h *SEOHandler) GetMovieSEO(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetMovieSEO(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// UpdateMovieSEO replaces the SEO title, meta description and canonical URL of a movie (Admin only)
+// PUT /api/v1/admin/movies/:id/seo
+func (h *SEOHandler) UpdateMovieSEO(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req movies.UpdateMovieSEORequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdateMovieSEO(ctx, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_seo_updated", result)
+}
+
+// ClearMovieSEO removes the SEO overrides of a movie, the defaults apply again (Admin only)
+// DELETE /api/v1/admin/movies/:id/seo
+func (h *SEOHandler) ClearMovieSEO(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.ClearMovieSEO(ctx, movieID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "movie_seo_cleared", nil)
+}
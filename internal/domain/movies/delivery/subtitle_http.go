@@ -0,0 +1,209 @@
+package delivery
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type SubtitleUsecase interface {
+	UploadSubtitle(ctx context.Context, movieID int64, req movies.UploadSubtitleRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.Subtitle, error)
+	GetSubtitles(ctx context.Context, movieID int64) ([]movies.Subtitle, error)
+	DeleteSubtitle(ctx context.Context, movieID int64, language string) error
+	GetSubtitleDrafts(ctx context.Context, movieID int64) ([]movies.SubtitleDraft, error)
+	GetSubtitleDraftFile(ctx context.Context, movieID, draftID int64) ([]byte, error)
+	ApproveSubtitleDraft(ctx context.Context, movieID, draftID int64, reviewerExtID string, req movies.ApproveSubtitleDraftRequest) (*movies.Subtitle, error)
+	RejectSubtitleDraft(ctx context.Context, movieID, draftID int64, reviewerExtID string) error
+}
+
+type SubtitleHandler struct {
+	ctx     context.Context
+	usecase SubtitleUsecase
+}
+
+func NewSubtitleHandler(ctx context.Context, usecase SubtitleUsecase) *SubtitleHandler {
+	return &SubtitleHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// UploadSubtitle uploads an .srt/.vtt subtitle for a language, stored as WebVTT (Admin only)
+// POST /api/v1/admin/movies/:id/subtitles (multipart: file, language, label, is_default)
+func (h *SubtitleHandler) UploadSubtitle(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req movies.UploadSubtitleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	file, fileHeader, err := c.Request().FormFile("file")
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "file_required", err.Error())
+	}
+	defer file.Close()
+
+	result, err := h.usecase.UploadSubtitle(ctx, movieID, req, file, fileHeader)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "subtitle_uploaded", result)
+}
+
+// GetSubtitles lists the subtitle tracks of a movie (Admin only)
+// GET /api/v1/admin/movies/:id/subtitles
+func (h *SubtitleHandler) GetSubtitles(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetSubtitles(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// DeleteSubtitle removes the subtitle track of a language (Admin only)
+// DELETE /api/v1/admin/movies/:id/subtitles/:language
+func (h *SubtitleHandler) DeleteSubtitle(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.DeleteSubtitle(ctx, movieID, c.Param("language")); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSubtitleDrafts lists the speech-to-text drafts of a movie (Admin only)
+// GET /api/v1/admin/movies/:id/subtitle-drafts
+func (h *SubtitleHandler) GetSubtitleDrafts(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.GetSubtitleDrafts(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetSubtitleDraftFile downloads the WebVTT file of a draft for review (Admin only)
+// GET /api/v1/admin/movies/:id/subtitle-drafts/:draftID/file
+func (h *SubtitleHandler) GetSubtitleDraftFile(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	draftID, err := strconv.ParseInt(c.Param("draftID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_draft_id", err.Error())
+	}
+
+	vtt, err := h.usecase.GetSubtitleDraftFile(ctx, movieID, draftID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Blob(http.StatusOK, "text/vtt; charset=utf-8", vtt)
+}
+
+// ApproveSubtitleDraft publishes a draft as the subtitle track of a language (Admin only)
+// POST /api/v1/admin/movies/:id/subtitle-drafts/:draftID/approve
+func (h *SubtitleHandler) ApproveSubtitleDraft(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	draftID, err := strconv.ParseInt(c.Param("draftID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_draft_id", err.Error())
+	}
+
+	var req movies.ApproveSubtitleDraftRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.ApproveSubtitleDraft(ctx, movieID, draftID, adminExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "subtitle_draft_approved", result)
+}
+
+// RejectSubtitleDraft discards a draft (Admin only)
+// DELETE /api/v1/admin/movies/:id/subtitle-drafts/:draftID
+func (h *SubtitleHandler) RejectSubtitleDraft(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	draftID, err := strconv.ParseInt(c.Param("draftID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_draft_id", err.Error())
+	}
+
+	if err := h.usecase.RejectSubtitleDraft(ctx, movieID, draftID, adminExtID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,211 @@
+package delivery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
+)
+
+type UploadUsecase interface {
+	InitUpload(ctx context.Context, req movies.InitUploadRequest) (*movies.InitUploadResponse, error)
+	UploadPart(ctx context.Context, sessionID int64, partNumber int, data io.Reader, size int64) (*movies.UploadPartResponse, error)
+	GetUploadSession(ctx context.Context, sessionID int64) (*movies.UploadSessionResponse, error)
+	CompleteUpload(ctx context.Context, adminExtID string, sessionID int64) (*movies.UploadMovieResponse, error)
+	AbortUpload(ctx context.Context, sessionID int64) error
+	PresignUpload(ctx context.Context, req movies.PresignUploadRequest) (*movies.PresignUploadResponse, error)
+	ConfirmUpload(ctx context.Context, adminExtID string, movieID int64, req movies.ConfirmUploadRequest) (*movies.UploadMovieResponse, error)
+	RetranscodeMovie(ctx context.Context, movieID int64) (*movies.UploadMovieResponse, error)
+}
+
+type UploadHandler struct {
+	ctx     context.Context
+	usecase UploadUsecase
+}
+
+func NewUploadHandler(ctx context.Context, usecase UploadUsecase) *UploadHandler {
+	return &UploadHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// InitUpload starts a chunked movie upload (Admin only)
+// POST /api/v1/admin/movies/uploads
+func (h *UploadHandler) InitUpload(c echo.Context) error {
+	ctx := h.ctx
+
+	var req movies.InitUploadRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.InitUpload(ctx, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "upload_session_created", result)
+}
+
+// UploadPart receives a single part as the raw request body (Admin only)
+// PUT /api/v1/admin/movies/uploads/:id/parts/:part
+func (h *UploadHandler) UploadPart(c echo.Context) error {
+	ctx := h.ctx
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_upload_session_id", err.Error())
+	}
+
+	partNumber, err := strconv.Atoi(c.Param("part"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_part_number", err.Error())
+	}
+
+	// Content-Length is required so the part can be streamed to MinIO without buffering
+	size := c.Request().ContentLength
+	if size <= 0 {
+		return response.Error(c, http.StatusLengthRequired, "content_length_required", nil)
+	}
+
+	result, err := h.usecase.UploadPart(ctx, sessionID, partNumber, c.Request().Body, size)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "part_uploaded", result)
+}
+
+// GetUploadSession returns upload progress so an interrupted upload can be resumed (Admin only)
+// GET /api/v1/admin/movies/uploads/:id
+func (h *UploadHandler) GetUploadSession(c echo.Context) error {
+	ctx := h.ctx
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_upload_session_id", err.Error())
+	}
+
+	result, err := h.usecase.GetUploadSession(ctx, sessionID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// CompleteUpload finalizes a chunked upload and enqueues transcoding (Admin only)
+// POST /api/v1/admin/movies/uploads/:id/complete
+func (h *UploadHandler) CompleteUpload(c echo.Context) error {
+	ctx := tracing.WithRequest(h.ctx, c.Request())
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_upload_session_id", err.Error())
+	}
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	result, err := h.usecase.CompleteUpload(ctx, adminExtID, sessionID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusAccepted, result.Message, result)
+}
+
+// AbortUpload cancels a chunked upload (Admin only)
+// DELETE /api/v1/admin/movies/uploads/:id
+func (h *UploadHandler) AbortUpload(c echo.Context) error {
+	ctx := h.ctx
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_upload_session_id", err.Error())
+	}
+
+	err = h.usecase.AbortUpload(ctx, sessionID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PresignUpload returns a presigned URL for uploading the raw video directly to MinIO (Admin only)
+// POST /api/v1/admin/movies/uploads/presign
+func (h *UploadHandler) PresignUpload(c echo.Context) error {
+	ctx := h.ctx
+
+	var req movies.PresignUploadRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.PresignUpload(ctx, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "upload_url_created", result)
+}
+
+// ConfirmUpload is called once the browser finished the presigned upload (Admin only)
+// POST /api/v1/admin/movies/:id/upload/confirm
+func (h *UploadHandler) ConfirmUpload(c echo.Context) error {
+	ctx := tracing.WithRequest(h.ctx, c.Request())
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req movies.ConfirmUploadRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	result, err := h.usecase.ConfirmUpload(ctx, adminExtID, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusAccepted, result.Message, result)
+}
+
+// RetranscodeMovie transcodes the stored raw video again, e.g. after changing the profile set (Admin only)
+// POST /api/v1/admin/movies/:id/retranscode
+func (h *UploadHandler) RetranscodeMovie(c echo.Context) error {
+	ctx := tracing.WithRequest(h.ctx, c.Request())
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	result, err := h.usecase.RetranscodeMovie(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusAccepted, result.Message, result)
+}
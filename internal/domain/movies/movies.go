@@ -1,6 +1,13 @@
 package movies
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Movie represents a movie entity in the database
 type Movie struct {
@@ -9,24 +16,230 @@ type Movie struct {
 	Description     string    `json:"description" gorm:"type:text"`
 	ReleaseDate     time.Time `json:"release_date" gorm:"type:date"`
 	Director        string    `json:"director" gorm:"type:varchar(255)"`
+	DirectorID      *int64    `json:"director_id,omitempty"` // people.id, kept in sync with Director
 	PosterURL       string    `json:"poster_url" gorm:"type:varchar(255)"`
 	TrailerURL      string    `json:"trailer_url" gorm:"type:varchar(255)"`
 	DurationMinutes int       `json:"duration_minutes"`
-	Price           float64   `json:"price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Price           float64   `json:"price" gorm:"type:decimal(10,2);not null;default:0.00"` // rental price
+	PurchasePrice   *float64  `json:"purchase_price" gorm:"type:decimal(10,2)"`              // nil when the movie cannot be bought
+	Visibility      string    `json:"visibility" gorm:"type:enum('PUBLIC','PRIVATE');default:'PUBLIC'"`
+	IsPublished     bool      `json:"is_published" gorm:"not null;default:true"`                       // false hides the title from the catalog and new orders
+	BetaAccess      bool      `json:"beta_access" gorm:"not null;default:false"`                       // soft launch, only testers and MovieBetaViewer users see the title
+	ProfileSet      string    `json:"profile_set" gorm:"type:varchar(32);not null;default:'standard'"` // transcoding ladder used by the worker
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Soft delete, rows and files are kept so purchase history and paid access survive
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Checkout link lifetime in minutes, nil uses the configured default
+	PaymentExpiry *int `json:"payment_expiry_minutes,omitempty" gorm:"column:payment_expiry_minutes"`
+
+	// Distinct devices one rental can be streamed on, nil uses the limit of the user's plan
+	RentalDeviceLimit *int `json:"rental_device_limit,omitempty" gorm:"column:rental_device_limit"`
+
+	// Playback starts of all time, added by the worker from the daily counters
+	ViewCount int64 `json:"view_count" gorm:"not null;default:0"`
+
+	// Recomputed by the worker from recent activity, see PopularityScores
+	PopularityScore float64 `json:"popularity_score" gorm:"type:decimal(12,4);not null;default:0"`
+
+	// Emergency takedown, the title is hidden and unplayable while set
+	TakenDownAt    *time.Time `json:"taken_down_at,omitempty"`
+	TakedownReason *string    `json:"takedown_reason,omitempty" gorm:"type:varchar(255)"`
+
+	// Declared by admins for accessibility compliance, filterable with ?accessibility=
+	Accessibility Accessibility `json:"accessibility" gorm:"embedded"`
+
+	// Search appearance set by marketing, see MovieMeta
+	SEO SEO `json:"seo" gorm:"embedded"`
+}
+
+// SEO overrides what search engines and link previews show for a title, nil fields fall back to the movie's own
+type SEO struct {
+	Title           *string `json:"seo_title" gorm:"column:seo_title;type:varchar(255)"`               // defaults to the title
+	MetaDescription *string `json:"meta_description" gorm:"column:meta_description;type:varchar(500)"` // defaults to the start of the description
+	CanonicalURL    *string `json:"canonical_url" gorm:"column:canonical_url;type:varchar(512)"`       // defaults to the movie page of the site
+}
+
+// Accessibility describes what a title offers viewers with disabilities and what they should be warned about
+type Accessibility struct {
+	ClosedCaptions         bool `json:"closed_captions" gorm:"column:closed_captions;not null;default:false"`
+	AudioDescription       bool `json:"audio_description" gorm:"column:audio_description;not null;default:false"`
+	FlashingContentWarning bool `json:"flashing_content_warning" gorm:"column:flashing_content_warning;not null;default:false"` // may trigger photosensitive seizures
+}
+
+// Built-in transcoding profile sets, more can be configured (see transcoding.ConfigureProfileSets)
+const (
+	ProfileSetStandard  = "standard"
+	ProfileSetLowCostSD = "low_cost_sd"
+	ProfileSetPremium   = "premium"
+)
+
+// Movie visibility states
+const (
+	VisibilityPublic  = "PUBLIC"
+	VisibilityPrivate = "PRIVATE"
+)
+
+// IsAvailable reports whether a title can be streamed now: transcoded, public, published, not taken down and released.
+// A zero release date counts as released. Regions are only restricted when ordering, see LicenseWindow.
+func IsAvailable(uploadStatus, visibility string, published bool, takenDownAt *time.Time, releaseDate time.Time, now time.Time) bool {
+	if uploadStatus != "READY" || visibility != VisibilityPublic || !published || takenDownAt != nil {
+		return false
+	}
+	return releaseDate.IsZero() || !releaseDate.After(now)
+}
+
+// Movie media kinds stored in the media bucket
+const (
+	MediaKindPoster  = "poster"
+	MediaKindTrailer = "trailer"
+)
+
+// Catalog sort options accepted by the movie list
+const (
+	SortNewest    = "newest"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortTitle     = "title"
+	SortDuration  = "duration"
+	SortViews     = "views"
+	SortPopular   = "popular"
+)
+
+// MovieFilter narrows the movie catalog
+type MovieFilter struct {
+	// Statuses are the upload statuses to list, empty means READY unless AllStatuses is set.
+	// A movie without a video counts as PENDING.
+	Statuses    []string
+	AllStatuses bool // list movies of every upload status, Statuses is ignored
+
+	Genre      string // genre name
+	GenreID    int
+	DirectorID int64
+	PublicOnly bool        // hide titles that were taken down
+	Viewer     *BetaViewer // with PublicOnly, soft-launched titles are only listed for allowed viewers
+
+	Accessibility []AccessibilityCondition // see ParseAccessibility, all have to apply
+}
+
+// Accessibility filter values accepted by the movie list, e.g. ?accessibility=cc,ad
+const (
+	AccessibilityCC         = "cc"          // closed captions
+	AccessibilityAD         = "ad"          // audio description
+	AccessibilityNoFlashing = "no_flashing" // no flashing content warning
+)
+
+// AccessibilityCondition is a validated accessibility filter on a movies column
+type AccessibilityCondition struct {
+	Column string
+	Value  bool
+}
+
+var accessibilityConditions = map[string]AccessibilityCondition{
+	AccessibilityCC:         {Column: "closed_captions", Value: true},
+	AccessibilityAD:         {Column: "audio_description", Value: true},
+	AccessibilityNoFlashing: {Column: "flashing_content_warning", Value: false},
+}
+
+// Upload statuses of a movie's video
+const (
+	UploadStatusPending    = "PENDING"
+	UploadStatusProcessing = "PROCESSING"
+	UploadStatusReady      = "READY"
+	UploadStatusFailed     = "FAILED"
+)
+
+var uploadStatuses = []string{UploadStatusPending, UploadStatusProcessing, UploadStatusReady, UploadStatusFailed}
+
+// ParseUploadStatuses converts a comma separated status query value, e.g. PENDING,FAILED, into upload statuses.
+// Values are case-insensitive and duplicates are dropped, empty means no filter.
+func ParseUploadStatuses(value string) ([]string, bool) {
+	var statuses []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToUpper(strings.TrimSpace(part))
+		if part == "" || seen[part] {
+			continue
+		}
+		valid := false
+		for _, status := range uploadStatuses {
+			valid = valid || status == part
+		}
+		if !valid {
+			return nil, false
+		}
+		seen[part] = true
+		statuses = append(statuses, part)
+	}
+	return statuses, true
+}
+
+// ParseAccessibility converts a comma separated accessibility query value into conditions, empty means no filter
+func ParseAccessibility(value string) ([]AccessibilityCondition, bool) {
+	var conditions []AccessibilityCondition
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		condition, ok := accessibilityConditions[part]
+		if !ok {
+			return nil, false
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, true
+}
+
+// SortSpec is a validated ordering for the movie catalog
+type SortSpec struct {
+	Column string // column of the movies table
+	Desc   bool
+}
+
+var sortSpecs = map[string]SortSpec{
+	SortNewest:    {Column: "created_at", Desc: true},
+	SortPriceAsc:  {Column: "price"},
+	SortPriceDesc: {Column: "price", Desc: true},
+	SortTitle:     {Column: "title"},
+	SortDuration:  {Column: "duration_minutes"},
+	SortViews:     {Column: "view_count", Desc: true},
+	SortPopular:   {Column: "popularity_score", Desc: true},
+}
+
+// ParseSort converts a sort query value into a SortSpec, empty means most popular first
+func ParseSort(sort string) (SortSpec, bool) {
+	if sort == "" {
+		sort = SortPopular
+	}
+	spec, ok := sortSpecs[sort]
+	return spec, ok
 }
 
 // MovieVideo represents the video processing status for a movie
 type MovieVideo struct {
-	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
-	MovieID        int64      `json:"movie_id" gorm:"uniqueIndex;not null"`
-	UploadStatus   string     `json:"upload_status" gorm:"type:enum('PENDING','PROCESSING','READY','FAILED');default:'PENDING'"`
-	RawFilePath    string     `json:"raw_file_path" gorm:"type:varchar(255)"`
-	HLSPlaylistURL string     `json:"hls_playlist_url" gorm:"type:varchar(255)"`
-	ErrorMessage   string     `json:"error_message" gorm:"type:text"`
-	UploadedAt     time.Time  `json:"uploaded_at" gorm:"autoCreateTime"`
-	ProcessedAt    *time.Time `json:"processed_at"`
+	ID                  int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID             int64      `json:"movie_id" gorm:"uniqueIndex;not null"`
+	UploadStatus        string     `json:"upload_status" gorm:"type:enum('PENDING','PROCESSING','READY','FAILED');default:'PENDING'"`
+	RawFilePath         string     `json:"raw_file_path" gorm:"type:varchar(255)"`
+	HLSPlaylistURL      string     `json:"hls_playlist_url" gorm:"type:varchar(255)"`
+	OutputVersion       int        `json:"output_version" gorm:"not null;default:0"`             // live movie-{id}/v{n}/ output, 0 for legacy unversioned output
+	LatestOutputVersion int        `json:"latest_output_version" gorm:"not null;default:0"`      // last version reserved by the worker
+	ComplexityFactor    *float64   `json:"complexity_factor,omitempty" gorm:"type:decimal(4,2)"` // per-title bitrate multiplier picked by the worker
+	ErrorMessage        string     `json:"error_message" gorm:"type:text"`
+	UploadedAt          time.Time  `json:"uploaded_at" gorm:"autoCreateTime"`
+	UploadedBy          *string    `json:"uploaded_by,omitempty" gorm:"type:varchar(50)"` // admin notified when transcoding completes
+	ProcessedAt         *time.Time `json:"processed_at"`
+
+	// Probed by the worker before transcoding
+	SourceResolution      string `json:"source_resolution,omitempty" gorm:"type:varchar(16)"`
+	SourceVideoCodec      string `json:"source_video_codec,omitempty" gorm:"type:varchar(32)"`
+	SourceDurationSeconds *int   `json:"source_duration_seconds,omitempty"`
+
+	// DASH manifest of the live version, nil when it was only published as HLS
+	DASHManifestURL *string `json:"dash_manifest_url,omitempty" gorm:"column:dash_manifest_url;type:varchar(255)"`
 }
 
 // TableName overrides the table name for Movie
@@ -39,10 +252,342 @@ func (MovieVideo) TableName() string {
 	return "movie_videos"
 }
 
+// RawSource is what decides whether the uploaded source of a movie is still needed, deleted movies included
+type RawSource struct {
+	MovieID      int64
+	DeletedAt    *time.Time
+	UploadStatus *string // nil without a video row
+	RawFilePath  *string // the source the movie refers to, older uploads of it are orphans
+	ProcessedAt  *time.Time
+}
+
+// VideoUploader is the admin who uploaded a movie's video, notified when transcoding completes
+type VideoUploader struct {
+	MovieTitle string
+	Name       string
+	Email      string
+}
+
+// MovieRendition is a variant the worker produced for a movie, with the bitrate chosen for the title
+type MovieRendition struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID     int64     `json:"movie_id" gorm:"not null;index"`
+	Name        string    `json:"name" gorm:"type:varchar(32);not null"`
+	Resolution  string    `json:"resolution" gorm:"type:varchar(16);not null"`
+	BitrateKbps int       `json:"bitrate_kbps" gorm:"not null"`
+	VideoRange  string    `json:"video_range" gorm:"type:varchar(8);not null;default:'SDR'"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for MovieRendition
+func (MovieRendition) TableName() string {
+	return "movie_renditions"
+}
+
+// MovieAudioTrack is an audio language the worker found in the source and published as HLS rendition
+type MovieAudioTrack struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID   int64     `json:"movie_id" gorm:"not null;index"`
+	Language  string    `json:"language" gorm:"type:varchar(16);not null"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	Channels  int       `json:"channels" gorm:"not null;default:2"`
+	IsDefault bool      `json:"is_default" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for MovieAudioTrack
+func (MovieAudioTrack) TableName() string {
+	return "movie_audio_tracks"
+}
+
+// Kinds of alternate audio tracks uploaded next to the source
+const (
+	AudioKindDub         = "DUB"
+	AudioKindDescription = "AUDIO_DESCRIPTION" // narrated description of the picture for blind viewers
+)
+
+// Alternate audio statuses
+const (
+	AlternateAudioPending    = "PENDING"
+	AlternateAudioProcessing = "PROCESSING"
+	AlternateAudioReady      = "READY"
+	AlternateAudioFailed     = "FAILED"
+)
+
+// AlternateAudio is an audio file uploaded for an existing movie, such as a dub or an audio description.
+// The worker packages it as an HLS audio rendition outside the output versions, so re-transcodes keep it.
+type AlternateAudio struct {
+	ID             int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID        int64     `json:"movie_id" gorm:"not null;index"`
+	Language       string    `json:"language" gorm:"type:varchar(16);not null"` // BCP 47 tag
+	Name           string    `json:"name" gorm:"type:varchar(100);not null"`    // shown in the player menu
+	Kind           string    `json:"kind" gorm:"type:varchar(24);not null"`
+	SourceObject   string    `json:"-" gorm:"type:varchar(255);not null"`                     // uploaded file in the raw bucket
+	PlaylistObject *string   `json:"playlist_object,omitempty" gorm:"type:varchar(255)"`      // media playlist in the processed bucket, set when READY
+	Status         string    `json:"status" gorm:"type:varchar(16);not null;default:PENDING"` // PENDING, PROCESSING, READY or FAILED
+	ErrorMessage   *string   `json:"error_message,omitempty" gorm:"type:text"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for AlternateAudio
+func (AlternateAudio) TableName() string {
+	return "movie_alternate_audio"
+}
+
+// UploadAlternateAudioRequest holds the form fields sent with an alternate audio file
+type UploadAlternateAudioRequest struct {
+	Language string `form:"language" validate:"required,bcp47_language_tag,max=16"`
+	Name     string `form:"name" validate:"max=100"` // Optional: defaults to the language tag
+	Kind     string `form:"kind" validate:"required,oneof=DUB AUDIO_DESCRIPTION"`
+}
+
+// WorldwideRegion is the region of licensing windows valid in every country
+const WorldwideRegion = "WW"
+
+// LicenseWindow is the period a movie may be sold in a region.
+// Movies without windows are not restricted, once every window closed the worker unpublishes the title.
+type LicenseWindow struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID   int64     `json:"movie_id" gorm:"not null;index"`
+	Region    string    `json:"region" gorm:"type:char(2);not null"` // ISO 3166-1 alpha-2 or WW
+	StartsAt  time.Time `json:"starts_at" gorm:"not null"`
+	EndsAt    time.Time `json:"ends_at" gorm:"not null;index"` // exclusive
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for LicenseWindow
+func (LicenseWindow) TableName() string {
+	return "movie_license_windows"
+}
+
+// CreateLicenseWindowRequest adds a licensing window to a movie
+type CreateLicenseWindowRequest struct {
+	Region   string    `json:"region" validate:"required,len=2,alpha"` // ISO 3166-1 alpha-2, WW for worldwide
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+// BetaViewer is who browses the catalog, a nil viewer is an anonymous visitor
+type BetaViewer struct {
+	UserExtID string
+	Tester    bool // the TESTER role sees every soft-launched title
+}
+
+// MovieBetaViewer allows a user to watch a movie while it is soft-launched
+type MovieBetaViewer struct {
+	MovieID   int64     `json:"movie_id" gorm:"primaryKey;autoIncrement:false"`
+	UserExtID string    `json:"user_ext_id" gorm:"primaryKey;type:varchar(100)"`
+	AddedBy   string    `json:"added_by" gorm:"type:varchar(100);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for MovieBetaViewer
+func (MovieBetaViewer) TableName() string {
+	return "movie_beta_viewers"
+}
+
+// AddBetaViewersRequest adds users to the allowlist of a soft-launched movie
+type AddBetaViewersRequest struct {
+	UserExtIDs []string `json:"user_ext_ids" validate:"required,min=1,max=500,dive,required"`
+}
+
+// EnqueueTranscodingJobRequest queues a transcode by hand, e.g. to encode one rendition of a movie again.
+// Empty fields use the movie's raw video and profile set. With next the job runs before all pending jobs.
+// A live movie keeps the renditions outside Profiles, they are copied into the new version.
+type EnqueueTranscodingJobRequest struct {
+	MovieID     int64    `json:"movie_id" validate:"required,gt=0"`
+	RawFilePath string   `json:"raw_file_path"`
+	ProfileSet  string   `json:"profile_set"`
+	Profiles    []string `json:"profiles" validate:"omitempty,max=10,dive,required"`
+	Next        bool     `json:"next"`
+}
+
+// ExpiringLicenseWindow is a row of the expiring licenses report
+type ExpiringLicenseWindow struct {
+	LicenseWindow `gorm:"embedded"`
+	MovieTitle    string `json:"movie_title"`
+	Visibility    string `json:"visibility"`
+}
+
+// MovieContentKey is the AES-128 key the HLS segments of a movie are encrypted with.
+// It is kept across output versions, so re-transcoding never breaks running streams.
+type MovieContentKey struct {
+	MovieID   int64     `json:"movie_id" gorm:"primaryKey;autoIncrement:false"`
+	Key       []byte    `json:"-" gorm:"column:content_key;type:varbinary(16);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for MovieContentKey
+func (MovieContentKey) TableName() string {
+	return "movie_content_keys"
+}
+
+// Catalog change types recorded for every edit that is visible in cached catalog responses
+const (
+	CatalogChangePublished    = "PUBLISHED"
+	CatalogChangeUpdated      = "UPDATED"
+	CatalogChangePrice        = "PRICE_CHANGED"
+	CatalogChangeVisibility   = "VISIBILITY_CHANGED"
+	CatalogChangeAvailability = "AVAILABILITY_CHANGED" // takedown or restore
+	CatalogChangePoster       = "POSTER_UPDATED"
+	CatalogChangeTrailer      = "TRAILER_UPDATED"
+	CatalogChangeDeleted      = "DELETED"
+)
+
+// CatalogChange is an entry of the catalog change log, the worker purges caches and the CDN from it
+type CatalogChange struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID     int64      `json:"movie_id" gorm:"not null;index"`
+	ChangeType  string     `json:"change_type" gorm:"type:varchar(32);not null"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	LastError   *string    `json:"last_error,omitempty" gorm:"type:varchar(500)"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+// TableName overrides the table name for CatalogChange
+func (CatalogChange) TableName() string {
+	return "catalog_changes"
+}
+
+// PurgePaths returns the API paths whose cached responses are stale after the change.
+// Paths ending in "*" are prefixes so list endpoints are purged with every query string.
+func (c CatalogChange) PurgePaths() []string {
+	detail := fmt.Sprintf("/api/v1/movies/%d", c.MovieID)
+
+	switch c.ChangeType {
+	case CatalogChangePoster:
+		return []string{detail, fmt.Sprintf("/api/v1/media/movies/%d/%s", c.MovieID, MediaKindPoster)}
+	case CatalogChangeTrailer:
+		return []string{detail, fmt.Sprintf("/api/v1/media/movies/%d/%s", c.MovieID, MediaKindTrailer)}
+	}
+
+	// Everything else can change what the listings show
+	return []string{detail, "/api/v1/movies*", "/api/v1/genres/*", "/api/v1/people/*", "/api/v1/catalog/stats"}
+}
+
+// CatalogStats summarizes the public catalog for the marketing site
+type CatalogStats struct {
+	TotalTitles       int64             `json:"total_titles"`
+	NewestReleaseDate *time.Time        `json:"newest_release_date"` // nil while the catalog is empty
+	Genres            []GenreTitleCount `json:"genres" gorm:"-"`
+	GeneratedAt       time.Time         `json:"generated_at" gorm:"-"`
+}
+
+// MovieDailyViews is the number of playback starts of a movie on one day (UTC)
+type MovieDailyViews struct {
+	MovieID  int64     `json:"movie_id" gorm:"primaryKey"`
+	ViewDate time.Time `json:"view_date" gorm:"primaryKey;type:date"`
+	Views    int64     `json:"views" gorm:"not null;default:0"`
+}
+
+// TableName overrides the table name for MovieDailyViews
+func (MovieDailyViews) TableName() string {
+	return "movie_daily_views"
+}
+
+// CompletionThreshold is the share of a movie a viewer has to reach for it to count as watched to the end
+const CompletionThreshold = 0.9
+
+// PopularityWeights weigh the activity behind the popularity score
+type PopularityWeights struct {
+	View       float64 // per playback start
+	Completion float64 // per viewer who watched to the end
+	Order      float64 // per paid rental or purchase
+	Rating     float64 // per point of the average published editorial score (0-10)
+}
+
+// PopularitySignal is the activity of a movie on one day (UTC)
+type PopularitySignal struct {
+	MovieID     int64
+	Day         time.Time
+	Views       int64
+	Completions int64
+	Orders      int64
+}
+
+// PopularityScores scores every movie with activity or a rating. Activity counts half as much
+// every halfLifeDays days before today, ratings do not decay. Scores are rounded to the
+// precision of movies.popularity_score, so the same activity always gives the same ranking.
+func PopularityScores(signals []PopularitySignal, ratings map[int64]float64, weights PopularityWeights, today time.Time, halfLifeDays float64) map[int64]float64 {
+	scores := make(map[int64]float64)
+	for _, signal := range signals {
+		age := today.Sub(signal.Day).Hours() / 24
+		if age < 0 {
+			age = 0
+		}
+		decay := math.Pow(0.5, age/halfLifeDays)
+		scores[signal.MovieID] += decay * (weights.View*float64(signal.Views) +
+			weights.Completion*float64(signal.Completions) +
+			weights.Order*float64(signal.Orders))
+	}
+	for movieID, rating := range ratings {
+		scores[movieID] += weights.Rating * rating
+	}
+
+	for movieID, score := range scores {
+		score = math.Round(score*1e4) / 1e4
+		if score <= 0 {
+			delete(scores, movieID)
+			continue
+		}
+		scores[movieID] = score
+	}
+	return scores
+}
+
+// PopularMoviesResponse lists the public titles watched in the last days, most popular first
+type PopularMoviesResponse struct {
+	Days   int                 `json:"days"`
+	Movies []MovieListResponse `json:"movies"`
+}
+
+// RecommendationsResponse lists the titles suggested to a user, best match first
+type RecommendationsResponse struct {
+	Personalized bool                `json:"personalized"` // false while the user has no history, popular titles are suggested then
+	Movies       []MovieListResponse `json:"movies"`
+}
+
+// GenreTitleCount is the number of public titles in a genre
+type GenreTitleCount struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	TotalTitles int64  `json:"total_titles"`
+}
+
+// Upload session statuses
+const (
+	UploadSessionInProgress = "IN_PROGRESS"
+	UploadSessionCompleted  = "COMPLETED"
+	UploadSessionAborted    = "ABORTED"
+)
+
+// MovieUploadSession tracks a chunked (multipart) raw video upload
+type MovieUploadSession struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID    int64     `json:"movie_id" gorm:"not null;index"`
+	UploadID   string    `json:"-" gorm:"type:varchar(255);not null"`
+	ObjectName string    `json:"object_name" gorm:"type:varchar(255);not null"`
+	FileName   string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	FileSize   int64     `json:"file_size" gorm:"not null"`
+	Status     string    `json:"status" gorm:"type:enum('IN_PROGRESS','COMPLETED','ABORTED');default:'IN_PROGRESS'"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for MovieUploadSession
+func (MovieUploadSession) TableName() string {
+	return "movie_upload_sessions"
+}
+
 // Genre represents a movie genre
 type Genre struct {
-	ID   int    `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name string `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	ID          int    `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string `json:"description" gorm:"type:varchar(1000);not null;default:''"`
+	ArtworkURL  string `json:"artwork_url" gorm:"type:varchar(255);not null;default:''"`
 }
 
 // TableName overrides the table name for Genre
@@ -50,6 +595,21 @@ func (Genre) TableName() string {
 	return "genres"
 }
 
+// Person is a director (or other credited person) with a browse page
+type Person struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Bio       *string   `json:"bio,omitempty" gorm:"type:text"`
+	PhotoURL  *string   `json:"photo_url,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for Person
+func (Person) TableName() string {
+	return "people"
+}
+
 // MovieGenre represents the many-to-many relationship between movies and genres
 type MovieGenre struct {
 	MovieID int64 `json:"movie_id" gorm:"primaryKey;not null"`
@@ -65,57 +625,253 @@ func (MovieGenre) TableName() string {
 
 // UploadMovieRequest represents the request to upload a new movie
 type UploadMovieRequest struct {
-	Title           string  `form:"title" validate:"required,min=1,max=255"`
-	Description     string  `form:"description"`
-	ReleaseDate     string  `form:"release_date"` // Format: YYYY-MM-DD
-	Director        string  `form:"director" validate:"max=255"`
-	PosterURL       string  `form:"poster_url" validate:"omitempty,url"`
-	TrailerURL      string  `form:"trailer_url" validate:"omitempty,url"`
-	DurationMinutes int     `form:"duration_minutes" validate:"omitempty,min=1"`
-	Price           float64 `form:"price" validate:"required,min=0"`
-	GenreIDs        []int   `form:"genre_ids"` // Optional: comma-separated genre IDs
+	Title           string   `form:"title" validate:"required,min=1,max=255"`
+	Description     string   `form:"description"`
+	ReleaseDate     string   `form:"release_date"` // Format: YYYY-MM-DD
+	Director        string   `form:"director" validate:"max=255"`
+	PosterURL       string   `form:"poster_url" validate:"omitempty,url"`
+	TrailerURL      string   `form:"trailer_url" validate:"omitempty,url"`
+	DurationMinutes int      `form:"duration_minutes" validate:"omitempty,min=1"`
+	Price           float64  `form:"price" validate:"required,min=0"`
+	PurchasePrice   *float64 `form:"purchase_price" validate:"omitempty,min=0"` // Optional: enables buying
+	GenreIDs        []int    `form:"genre_ids"`                                 // Optional: comma-separated genre IDs
+	Visibility      string   `form:"visibility" validate:"omitempty,oneof=PUBLIC PRIVATE"`
+	ProfileSet      string   `form:"profile_set" validate:"omitempty,max=32"` // Optional: defaults to standard
+	Enrich          bool     `form:"enrich"`                                  // Optional: fill empty fields from TMDB
 }
 
 // UpdateMovieRequest represents the request to update movie metadata
 type UpdateMovieRequest struct {
-	Title           string  `json:"title" validate:"omitempty,min=1,max=255"`
-	Description     string  `json:"description"`
-	ReleaseDate     string  `json:"release_date"` // Format: YYYY-MM-DD
-	Director        string  `json:"director" validate:"omitempty,max=255"`
-	PosterURL       string  `json:"poster_url" validate:"omitempty,url"`
-	TrailerURL      string  `json:"trailer_url" validate:"omitempty,url"`
-	DurationMinutes int     `json:"duration_minutes" validate:"omitempty,min=1"`
-	Price           float64 `json:"price" validate:"omitempty,min=0"`
-	GenreIDs        []int   `json:"genre_ids"` // Optional: update movie genres
+	Title           string   `json:"title" validate:"omitempty,min=1,max=255"`
+	Description     string   `json:"description"`
+	ReleaseDate     string   `json:"release_date"` // Format: YYYY-MM-DD
+	Director        string   `json:"director" validate:"omitempty,max=255"`
+	PosterURL       string   `json:"poster_url" validate:"omitempty,url"`
+	TrailerURL      string   `json:"trailer_url" validate:"omitempty,url"`
+	DurationMinutes int      `json:"duration_minutes" validate:"omitempty,min=1"`
+	Price           float64  `json:"price" validate:"omitempty,min=0"`
+	PurchasePrice   *float64 `json:"purchase_price" validate:"omitempty,min=0"`
+	DisablePurchase bool     `json:"disable_purchase"` // Optional: stop offering the movie for purchase
+	GenreIDs        []int    `json:"genre_ids"`        // Optional: update movie genres
+	Visibility      string   `json:"visibility" validate:"omitempty,oneof=PUBLIC PRIVATE"`
+	ProfileSet      string   `json:"profile_set" validate:"omitempty,max=32"` // Optional: used by the next transcode
+
+	// Optional: checkout link lifetime in minutes, reset_payment_expiry goes back to the configured default
+	PaymentExpiry      *int `json:"payment_expiry_minutes" validate:"omitempty,min=30,max=1440"`
+	ResetPaymentExpiry bool `json:"reset_payment_expiry"`
+
+	// Optional: devices per rental, reset_rental_device_limit goes back to the plan's limit
+	RentalDeviceLimit      *int `json:"rental_device_limit" validate:"omitempty,min=1,max=20"`
+	ResetRentalDeviceLimit bool `json:"reset_rental_device_limit"`
+
+	// Optional: accessibility flags, only the sent ones change
+	ClosedCaptions         *bool `json:"closed_captions"`
+	AudioDescription       *bool `json:"audio_description"`
+	FlashingContentWarning *bool `json:"flashing_content_warning"`
+}
+
+// MovieMetadataRequest holds the movie fields sent as JSON by the upload flows
+type MovieMetadataRequest struct {
+	Title           string   `json:"title" validate:"required,min=1,max=255"`
+	Description     string   `json:"description"`
+	ReleaseDate     string   `json:"release_date"` // Format: YYYY-MM-DD
+	Director        string   `json:"director" validate:"max=255"`
+	PosterURL       string   `json:"poster_url" validate:"omitempty,url"`
+	TrailerURL      string   `json:"trailer_url" validate:"omitempty,url"`
+	DurationMinutes int      `json:"duration_minutes" validate:"omitempty,min=1"`
+	Price           float64  `json:"price" validate:"min=0"`
+	PurchasePrice   *float64 `json:"purchase_price" validate:"omitempty,min=0"`
+	GenreIDs        []int    `json:"genre_ids"`
+	Visibility      string   `json:"visibility" validate:"omitempty,oneof=PUBLIC PRIVATE"`
+	ProfileSet      string   `json:"profile_set" validate:"omitempty,max=32"`
+	Enrich          bool     `json:"enrich"` // fill empty fields from TMDB
+}
+
+// InitUploadRequest represents the request to start a chunked movie upload
+type InitUploadRequest struct {
+	MovieMetadataRequest
+	FileName string `json:"file_name" validate:"required,max=255"`
+	FileSize int64  `json:"file_size" validate:"required,gt=0"`
+}
+
+// PresignUploadRequest represents the request for a direct-to-MinIO upload URL
+type PresignUploadRequest struct {
+	MovieMetadataRequest
+	FileName string `json:"file_name" validate:"required,max=255"`
+}
+
+// ConfirmUploadRequest is sent once the browser finished uploading to the presigned URL
+type ConfirmUploadRequest struct {
+	ObjectName string `json:"object_name" validate:"required"`
 }
 
 // Response DTOs
 
+// InitUploadResponse represents the response after starting a chunked upload
+type InitUploadResponse struct {
+	UploadSessionID int64 `json:"upload_session_id"`
+	MovieID         int64 `json:"movie_id"`
+	PartSize        int64 `json:"part_size"`
+	TotalParts      int   `json:"total_parts"`
+}
+
+// PresignUploadResponse contains the presigned PUT URL for the raw bucket
+type PresignUploadResponse struct {
+	MovieID    int64     `json:"movie_id"`
+	ObjectName string    `json:"object_name"`
+	UploadURL  string    `json:"upload_url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// UploadPartResponse represents a single uploaded part
+type UploadPartResponse struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size"`
+}
+
+// UploadSessionResponse represents the state of a chunked upload, used to resume it
+type UploadSessionResponse struct {
+	UploadSessionID int64                `json:"upload_session_id"`
+	MovieID         int64                `json:"movie_id"`
+	FileName        string               `json:"file_name"`
+	FileSize        int64                `json:"file_size"`
+	Status          string               `json:"status"`
+	PartSize        int64                `json:"part_size"`
+	TotalParts      int                  `json:"total_parts"`
+	UploadedParts   []UploadPartResponse `json:"uploaded_parts"`
+}
+
 // MovieListResponse represents a movie in the list view (catalog)
 type MovieListResponse struct {
-	ID              int64   `json:"id"`
-	Title           string  `json:"title"`
-	PosterURL       string  `json:"poster_url"`
-	Price           float64 `json:"price"`
-	DurationMinutes int     `json:"duration_minutes"`
-	UploadStatus    string  `json:"upload_status"`
+	ID              int64      `json:"id"`
+	Title           string     `json:"title"`
+	PosterURL       string     `json:"poster_url"`
+	Price           float64    `json:"price"`
+	PurchasePrice   *float64   `json:"purchase_price,omitempty"`
+	DurationMinutes int        `json:"duration_minutes"`
+	UploadStatus    string     `json:"upload_status"`
+	Visibility      string     `json:"visibility"`
+	IsPublished     bool       `json:"is_published"`
+	BetaAccess      bool       `json:"beta_access,omitempty"`           // soft-launched, only listed for testers and the allowlist
+	TakenDownAt     *time.Time `json:"taken_down_at,omitempty"`         // only returned to admins
+	ReleaseDate     *time.Time `json:"-"`                               // only used for Available
+	Available       bool       `json:"available" gorm:"-"`              // see IsAvailable
+	InWatchlist     *bool      `json:"in_watchlist,omitempty" gorm:"-"` // only set for authenticated requests
+	Views           int64      `json:"views,omitempty"`                 // only set for popular movies
+	PopularityScore float64    `json:"popularity_score,omitempty"`      // only set for trending movies
+
+	Accessibility Accessibility `json:"accessibility" gorm:"embedded"`
 }
 
 // MovieDetailResponse represents detailed movie information
 type MovieDetailResponse struct {
-	ID              int64     `json:"id"`
-	Title           string    `json:"title"`
-	Description     string    `json:"description"`
-	ReleaseDate     string    `json:"release_date"`
-	Director        string    `json:"director"`
-	PosterURL       string    `json:"poster_url"`
-	TrailerURL      string    `json:"trailer_url"`
-	DurationMinutes int       `json:"duration_minutes"`
-	Price           float64   `json:"price"`
-	UploadStatus    string    `json:"upload_status"`
-	Genres          []string  `json:"genres,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int64      `json:"id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	ReleaseDate     string     `json:"release_date"`
+	Director        string     `json:"director"`
+	DirectorID      *int64     `json:"director_id,omitempty"`
+	PosterURL       string     `json:"poster_url"`
+	TrailerURL      string     `json:"trailer_url"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Price           float64    `json:"price"`
+	PurchasePrice   *float64   `json:"purchase_price,omitempty"`
+	UploadStatus    string     `json:"upload_status"`
+	Visibility      string     `json:"visibility"`
+	IsPublished     bool       `json:"-"`
+	BetaAccess      bool       `json:"beta_access"`
+	TakenDownAt     *time.Time `json:"-"`
+	Available       bool       `json:"available" gorm:"-"`              // see IsAvailable
+	InWatchlist     *bool      `json:"in_watchlist,omitempty" gorm:"-"` // only set for authenticated requests
+	Genres          []string   `json:"genres,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	EditorialReviews []EditorialReviewSummary `json:"editorial_reviews" gorm:"-"` // published staff reviews
+
+	Accessibility Accessibility `json:"accessibility" gorm:"embedded"`
+
+	AudioTracks []AudioTrackSummary `json:"audio_tracks,omitempty" gorm:"-"` // languages of the live version
+
+	SEO SEO `json:"-" gorm:"embedded"` // served by the meta endpoint
+}
+
+// MovieMeta is what the frontend renders into the <head> of a movie page, with the SEO overrides applied
+type MovieMeta struct {
+	MovieID      int64     `json:"movie_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	CanonicalURL string    `json:"canonical_url,omitempty"` // empty when neither an override nor the site URL is configured
+	ImageURL     string    `json:"image_url,omitempty"`     // the poster
+	ReleaseDate  string    `json:"release_date,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// MovieSEOResponse is the SEO overrides of a movie and the meta they result in, for the admin UI
+type MovieSEOResponse struct {
+	SEO
+	Meta MovieMeta `json:"meta"`
+}
+
+// UpdateMovieSEORequest replaces the SEO overrides of a movie, empty fields go back to the default
+type UpdateMovieSEORequest struct {
+	Title           string `json:"seo_title" validate:"omitempty,max=255"`
+	MetaDescription string `json:"meta_description" validate:"omitempty,max=500"`
+	CanonicalURL    string `json:"canonical_url" validate:"omitempty,url,max=512"`
+}
+
+// SitemapMovie is a publicly listed movie of the sitemap
+type SitemapMovie struct {
+	ID           int64
+	CanonicalURL *string
+	UpdatedAt    time.Time
+}
+
+// SitemapURL is an entry of the sitemap, LastMod is a W3C date
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// AdminMovieDetailResponse is a movie with its video and genre IDs, for the processing diagnostics of the admin UI
+type AdminMovieDetailResponse struct {
+	Movie
+	Video    *MovieVideo `json:"video"` // raw file, error message and HLS URL, nil before anything was uploaded
+	GenreIDs []int       `json:"genre_ids"`
+}
+
+// AudioTrackSummary is an audio language listed on the movie detail
+type AudioTrackSummary struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+	Default  bool   `json:"default"`
+	Kind     string `json:"kind,omitempty"` // DUB or AUDIO_DESCRIPTION for uploaded tracks, empty for tracks of the source
+}
+
+// EditorialReviewSummary is a published critic/staff review shown on the movie detail
+type EditorialReviewSummary struct {
+	ID          int64     `json:"id"`
+	AuthorName  string    `json:"author_name"`
+	Headline    string    `json:"headline"`
+	Body        string    `json:"body"` // Markdown
+	Score       float64   `json:"score"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// EnrichMovieRequest looks up a movie on TMDB, the title and year default to the movie's own
+type EnrichMovieRequest struct {
+	Title     string `json:"title" validate:"omitempty,max=255"`
+	Year      int    `json:"year" validate:"omitempty,min=1870,max=2100"`
+	Overwrite bool   `json:"overwrite"` // replace fields that are already set
+}
+
+// EnrichMovieResponse lists the fields filled from TMDB
+type EnrichMovieResponse struct {
+	MovieID       int64    `json:"movie_id"`
+	TMDBID        int64    `json:"tmdb_id"`
+	MatchedTitle  string   `json:"matched_title"`
+	UpdatedFields []string `json:"updated_fields"`
 }
 
 // UploadMovieResponse represents the response after uploading a movie
@@ -140,10 +896,138 @@ type MovieListWithPagination struct {
 
 // GenreRequest represents request to create a new genre
 type GenreRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=100"`
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+	ArtworkURL  string `json:"artwork_url" validate:"max=255"`
+}
+
+// UpdateGenreRequest represents genre metadata edited by admins
+type UpdateGenreRequest struct {
+	Name        string  `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	ArtworkURL  *string `json:"artwork_url" validate:"omitempty,max=255"`
+}
+
+// UpdatePersonRequest represents person metadata edited by admins
+type UpdatePersonRequest struct {
+	Name     string  `json:"name" validate:"omitempty,min=1,max=255"`
+	Bio      *string `json:"bio"`
+	PhotoURL *string `json:"photo_url" validate:"omitempty,max=255"`
+}
+
+// GenreMoviesResponse is a genre browse page
+type GenreMoviesResponse struct {
+	Genre      Genre               `json:"genre"`
+	Movies     []MovieListResponse `json:"movies"`
+	Pagination PaginationMeta      `json:"pagination"`
+}
+
+// PersonMoviesResponse is a person (director) browse page
+type PersonMoviesResponse struct {
+	Person     Person              `json:"person"`
+	Movies     []MovieListResponse `json:"movies"`
+	Pagination PaginationMeta      `json:"pagination"`
 }
 
 // GenreListResponse represents list of all genres
 type GenreListResponse struct {
 	Genres []Genre `json:"genres"`
 }
+
+// MovieMediaResponse represents a stored poster/trailer after upload
+type MovieMediaResponse struct {
+	MovieID    int64  `json:"movie_id"`
+	Kind       string `json:"kind"`
+	ObjectName string `json:"object_name"`
+	URL        string `json:"url"` // empty while the upload waits for review
+
+	// Set when content moderation held the upload back, see MediaReview
+	ReviewID     *int64 `json:"review_id,omitempty"`
+	ReviewReason string `json:"review_reason,omitempty"`
+}
+
+// Media review statuses
+const (
+	MediaReviewPending  = "PENDING"
+	MediaReviewApproved = "APPROVED"
+	MediaReviewRejected = "REJECTED"
+)
+
+// MediaReview is an uploaded poster that content moderation flagged or could not scan,
+// it is only shown once an admin approves it
+type MediaReview struct {
+	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID         int64      `json:"movie_id" gorm:"not null;index"`
+	Kind            string     `json:"kind" gorm:"type:varchar(16);not null"`
+	ObjectName      string     `json:"object_name" gorm:"type:varchar(255);not null"` // held file in the media bucket
+	Reason          string     `json:"reason" gorm:"type:varchar(255);not null"`      // from the moderation service
+	Status          string     `json:"status" gorm:"type:varchar(16);not null;default:PENDING"`
+	ReviewedByExtID *string    `json:"reviewed_by_ext_id,omitempty" gorm:"type:varchar(255);column:reviewed_by_ext_id"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	PreviewURL string `json:"preview_url,omitempty" gorm:"-"` // short-lived link for the admin, pending reviews only
+}
+
+// TableName specifies the table name for MediaReview model
+func (MediaReview) TableName() string {
+	return "media_reviews"
+}
+
+// Subtitle is a WebVTT subtitle track of a movie, one per language
+type Subtitle struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID    int64     `json:"movie_id" gorm:"not null;uniqueIndex:idx_subtitles_movie_language"`
+	Language   string    `json:"language" gorm:"type:varchar(16);not null;uniqueIndex:idx_subtitles_movie_language"` // BCP 47 tag, e.g. "en" or "pt-BR"
+	Label      string    `json:"label" gorm:"type:varchar(64);not null"`                                             // shown in the player menu
+	ObjectName string    `json:"object_name" gorm:"type:varchar(255);not null"`                                      // WebVTT file in the processed bucket
+	IsDefault  bool      `json:"is_default" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for Subtitle model
+func (Subtitle) TableName() string {
+	return "subtitles"
+}
+
+// UploadSubtitleRequest holds the form fields sent with a subtitle file
+type UploadSubtitleRequest struct {
+	Language  string `form:"language" validate:"required,bcp47_language_tag,max=16"`
+	Label     string `form:"label" validate:"max=64"` // Optional: defaults to the language tag
+	IsDefault bool   `form:"is_default"`              // Optional: selected by players without a language preference
+}
+
+// Subtitle draft statuses
+const (
+	SubtitleDraftPending  = "DRAFT"
+	SubtitleDraftApproved = "APPROVED"
+	SubtitleDraftRejected = "REJECTED"
+)
+
+// SubtitleDraft is a WebVTT file generated by speech-to-text, players never see it until it is approved
+type SubtitleDraft struct {
+	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID         int64      `json:"movie_id" gorm:"not null;index"`
+	Language        string     `json:"language" gorm:"type:varchar(16);not null"`     // requested from the model, "und" when detected
+	Provider        string     `json:"provider" gorm:"type:varchar(64);not null"`     // e.g. "whisper:small"
+	ObjectName      string     `json:"object_name" gorm:"type:varchar(255);not null"` // WebVTT file in the raw bucket
+	Status          string     `json:"status" gorm:"type:varchar(16);not null;default:DRAFT"`
+	ReviewedByExtID *string    `json:"reviewed_by_ext_id,omitempty" gorm:"type:varchar(255);column:reviewed_by_ext_id"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for SubtitleDraft model
+func (SubtitleDraft) TableName() string {
+	return "subtitle_drafts"
+}
+
+// ApproveSubtitleDraftRequest publishes a draft as the subtitle track of a language
+type ApproveSubtitleDraftRequest struct {
+	Language  string `json:"language" validate:"required,bcp47_language_tag,max=16"`
+	Label     string `json:"label" validate:"max=64"` // Optional: defaults to the language tag
+	IsDefault bool   `json:"is_default"`
+}
@@ -0,0 +1,36 @@
+package movies
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUploadStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   []string
+		wantOK bool
+	}{
+		{name: "empty means no filter", value: "", want: nil, wantOK: true},
+		{name: "single status", value: "FAILED", want: []string{UploadStatusFailed}, wantOK: true},
+		{name: "multiple statuses", value: "PENDING,FAILED", want: []string{UploadStatusPending, UploadStatusFailed}, wantOK: true},
+		{name: "case and spaces are ignored", value: " pending , Processing ", want: []string{UploadStatusPending, UploadStatusProcessing}, wantOK: true},
+		{name: "duplicates are dropped", value: "READY,ready,READY", want: []string{UploadStatusReady}, wantOK: true},
+		{name: "empty parts are skipped", value: ",,READY,", want: []string{UploadStatusReady}, wantOK: true},
+		{name: "only commas", value: ",,", want: nil, wantOK: true},
+		{name: "unknown status", value: "PENDING,DONE", want: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseUploadStatuses(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseUploadStatuses(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUploadStatuses(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/cache"
+	"github.com/rs/zerolog"
+)
+
+// The public catalog reads are cached, every write to movies, genres or people through the
+// repository drops the whole catalog cache. Writes made elsewhere show once the entries expire,
+// or once the worker's catalog dispatcher invalidates the cache.
+
+// WithCatalogCache caches the movie list, movie detail and genre reads in c
+func (r *MovieRepository) WithCatalogCache(c *cache.Cache) *MovieRepository {
+	r.catalogCache = c
+	return r
+}
+
+// InvalidateCatalogCache drops every cached catalog read, a failure is only logged
+func (r *MovieRepository) InvalidateCatalogCache(ctx context.Context) {
+	r.invalidateCatalogCache(ctx)
+}
+
+func (r *MovieRepository) invalidateCatalogCache(ctx context.Context) {
+	if r.catalogCache == nil {
+		return
+	}
+	if err := r.catalogCache.Invalidate(ctx); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to invalidate catalog cache")
+	}
+}
+
+// cachedMovieList is a page of the movie list in the cache
+type cachedMovieList struct {
+	Movies []movies.MovieListResponse
+	Total  int64
+}
+
+// FindAllMovies returns paginated list of movies with optional filters.
+// Public lists are cached unless the viewer may see soft-launched titles, those depend on the viewer.
+func (r *MovieRepository) FindAllMovies(ctx context.Context, page, limit int, filter movies.MovieFilter, sort movies.SortSpec) ([]movies.MovieListResponse, int64, error) {
+	if r.catalogCache == nil || !filter.PublicOnly {
+		return r.findAllMovies(ctx, page, limit, filter, sort)
+	}
+	if filter.Viewer != nil {
+		if filter.Viewer.Tester {
+			return r.findAllMovies(ctx, page, limit, filter, sort)
+		}
+		allowed, err := r.isBetaViewer(ctx, filter.Viewer.UserExtID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if allowed {
+			return r.findAllMovies(ctx, page, limit, filter, sort)
+		}
+		// Not on any allowlist, the user sees what a visitor sees
+		filter.Viewer = nil
+	}
+
+	key := fmt.Sprintf("movies:%d:%d:%v:%t:%s:%d:%d:%s:%t", page, limit, filter.Statuses, filter.AllStatuses, filter.Genre, filter.GenreID, filter.DirectorID, sort.Column, sort.Desc)
+	for _, condition := range filter.Accessibility {
+		key += fmt.Sprintf(":%s=%t", condition.Column, condition.Value)
+	}
+
+	var cached cachedMovieList
+	if r.catalogCache.Get(ctx, key, &cached) {
+		return cached.Movies, cached.Total, nil
+	}
+	results, total, err := r.findAllMovies(ctx, page, limit, filter, sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.catalogCache.Set(ctx, key, cachedMovieList{Movies: results, Total: total})
+	return results, total, nil
+}
+
+// isBetaViewer reports whether the user is on the allowlist of any movie
+func (r *MovieRepository) isBetaViewer(ctx context.Context, userExtID string) (bool, error) {
+	var found []int
+	err := r.db.WithContext(ctx).
+		Model(&movies.MovieBetaViewer{}).
+		Where("user_ext_id = ?", userExtID).
+		Limit(1).
+		Pluck("1", &found).Error
+	if err != nil {
+		return false, err
+	}
+	return len(found) > 0, nil
+}
+
+// FindMovieDetail returns detailed information about a movie
+func (r *MovieRepository) FindMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error) {
+	if r.catalogCache == nil {
+		return r.findMovieDetail(ctx, movieID)
+	}
+
+	key := fmt.Sprintf("movie:%d", movieID)
+	var cached movies.MovieDetailResponse
+	if r.catalogCache.Get(ctx, key, &cached) {
+		return &cached, nil
+	}
+	result, err := r.findMovieDetail(ctx, movieID)
+	if err != nil || result == nil {
+		return result, err
+	}
+	r.catalogCache.Set(ctx, key, result)
+	return result, nil
+}
+
+// GetAllGenres returns all available genres
+func (r *MovieRepository) GetAllGenres(ctx context.Context) ([]movies.Genre, error) {
+	if r.catalogCache == nil {
+		return r.getAllGenres(ctx)
+	}
+
+	var cached []movies.Genre
+	if r.catalogCache.Get(ctx, "genres", &cached) {
+		return cached, nil
+	}
+	genres, err := r.getAllGenres(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.catalogCache.Set(ctx, "genres", genres)
+	return genres, nil
+}
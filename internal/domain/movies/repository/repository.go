@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/cache"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MovieRepository struct {
-	db *gorm.DB
+	db           *gorm.DB
+	catalogCache *cache.Cache // nil when the catalog reads are not cached, see WithCatalogCache
 }
 
 func NewMovieRepository(db *gorm.DB) *MovieRepository {
@@ -19,7 +24,11 @@ func NewMovieRepository(db *gorm.DB) *MovieRepository {
 
 // CreateMovie creates a new movie record
 func (r *MovieRepository) CreateMovie(ctx context.Context, movie *movies.Movie) error {
-	return r.db.WithContext(ctx).Create(movie).Error
+	if err := r.db.WithContext(ctx).Create(movie).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
 }
 
 // CreateMovieVideo creates a movie_video record
@@ -40,6 +49,19 @@ func (r *MovieRepository) FindMovieByID(ctx context.Context, movieID int64) (*mo
 	return &movie, nil
 }
 
+// FindMovieByIDWithDeleted finds a movie by its ID, including soft deleted movies
+func (r *MovieRepository) FindMovieByIDWithDeleted(ctx context.Context, movieID int64) (*movies.Movie, error) {
+	var movie movies.Movie
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ?", movieID).First(&movie).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &movie, nil
+}
+
 // FindMovieVideoByMovieID finds movie_video record by movie_id
 func (r *MovieRepository) FindMovieVideoByMovieID(ctx context.Context, movieID int64) (*movies.MovieVideo, error) {
 	var movieVideo movies.MovieVideo
@@ -53,8 +75,43 @@ func (r *MovieRepository) FindMovieVideoByMovieID(ctx context.Context, movieID i
 	return &movieVideo, nil
 }
 
-// FindAllMovies returns paginated list of movies with optional filters
-func (r *MovieRepository) FindAllMovies(ctx context.Context, page, limit int, status string, genre string) ([]movies.MovieListResponse, int64, error) {
+// FindVideoUploader returns the admin who uploaded a movie's video, nil for videos uploaded before it was recorded
+func (r *MovieRepository) FindVideoUploader(ctx context.Context, movieID int64) (*movies.VideoUploader, error) {
+	var uploader movies.VideoUploader
+	err := r.db.WithContext(ctx).
+		Table("movie_videos").
+		Select("movies.title AS movie_title, users.name, users.email").
+		Joins("JOIN movies ON movies.id = movie_videos.movie_id").
+		Joins("JOIN users ON users.ext_id = movie_videos.uploaded_by").
+		Where("movie_videos.movie_id = ?", movieID).
+		Take(&uploader).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &uploader, nil
+}
+
+// movieListColumns are the movies columns of a catalog entry, see MovieListResponse
+const movieListColumns = "movies.id, movies.title, movies.poster_url, movies.price, movies.purchase_price, movies.duration_minutes, movies.visibility, movies.is_published, movies.beta_access, movies.taken_down_at, movies.release_date, movies.closed_captions, movies.audio_description, movies.flashing_content_warning"
+
+// filterUploadStatus narrows a query joined with movie_videos to the statuses of the filter.
+// Without a status filter only READY movies are listed, movies without a video count as PENDING.
+func filterUploadStatus(query *gorm.DB, filter movies.MovieFilter) *gorm.DB {
+	switch {
+	case filter.AllStatuses:
+		return query
+	case len(filter.Statuses) > 0:
+		return query.Where("COALESCE(movie_videos.upload_status, ?) IN ?", movies.UploadStatusPending, filter.Statuses)
+	default:
+		return query.Where("movie_videos.upload_status = ?", movies.UploadStatusReady)
+	}
+}
+
+// findAllMovies returns paginated list of movies with optional filters
+func (r *MovieRepository) findAllMovies(ctx context.Context, page, limit int, filter movies.MovieFilter, sort movies.SortSpec) ([]movies.MovieListResponse, int64, error) {
 	var results []movies.MovieListResponse
 	var totalCount int64
 
@@ -63,22 +120,40 @@ func (r *MovieRepository) FindAllMovies(ctx context.Context, page, limit int, st
 	// Base query with JOIN to movie_videos
 	query := r.db.WithContext(ctx).
 		Table("movies").
-		Select("movies.id, movies.title, movies.poster_url, movies.price, movies.duration_minutes, COALESCE(movie_videos.upload_status, 'PENDING') as upload_status").
-		Joins("LEFT JOIN movie_videos ON movie_videos.movie_id = movies.id")
+		Select(movieListColumns + ", COALESCE(movie_videos.upload_status, 'PENDING') as upload_status").
+		Joins("LEFT JOIN movie_videos ON movie_videos.movie_id = movies.id").
+		Where("movies.deleted_at IS NULL")
+	query = filterUploadStatus(query, filter)
 
-	// Apply status filter if provided
-	if status != "" {
-		query = query.Where("movie_videos.upload_status = ?", status)
-	} else {
-		// By default, only show READY movies for public
-		query = query.Where("movie_videos.upload_status = ?", "READY")
+	if filter.PublicOnly {
+		query = query.Where("movies.is_published = ? AND movies.taken_down_at IS NULL", true)
+
+		// Soft-launched titles are listed for testers and the users on their allowlist
+		switch {
+		case filter.Viewer == nil:
+			query = query.Where("movies.beta_access = ?", false)
+		case !filter.Viewer.Tester:
+			query = query.Where("movies.beta_access = ? OR EXISTS (SELECT 1 FROM movie_beta_viewers bv WHERE bv.movie_id = movies.id AND bv.user_ext_id = ?)", false, filter.Viewer.UserExtID)
+		}
 	}
 
 	// Apply genre filter if provided
-	if genre != "" {
+	if filter.Genre != "" {
 		query = query.Joins("JOIN movie_genres ON movie_genres.movie_id = movies.id").
 			Joins("JOIN genres ON genres.id = movie_genres.genre_id").
-			Where("genres.name = ?", genre)
+			Where("genres.name = ?", filter.Genre)
+	}
+	if filter.GenreID > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = movies.id AND mg.genre_id = ?)", filter.GenreID)
+	}
+
+	if filter.DirectorID > 0 {
+		query = query.Where("movies.director_id = ?", filter.DirectorID)
+	}
+
+	// Columns come from validated AccessibilityConditions
+	for _, condition := range filter.Accessibility {
+		query = query.Where(clause.Eq{Column: clause.Column{Table: "movies", Name: condition.Column}, Value: condition.Value})
 	}
 
 	// Count total records
@@ -88,22 +163,92 @@ func (r *MovieRepository) FindAllMovies(ctx context.Context, page, limit int, st
 	}
 
 	// Get paginated results
-	if err := query.Offset(offset).Limit(limit).Order("movies.created_at DESC").Find(&results).Error; err != nil {
+	// Sort column comes from a validated SortSpec, movies.id keeps pagination stable on ties
+	query = query.Order(clause.OrderByColumn{Column: clause.Column{Table: "movies", Name: sort.Column}, Desc: sort.Desc}).
+		Order("movies.id DESC")
+	if err := query.Offset(offset).Limit(limit).Find(&results).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return results, totalCount, nil
 }
 
-// FindMovieDetail returns detailed information about a movie
-func (r *MovieRepository) FindMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error) {
+// GetCatalogStats counts the titles a visitor can watch: READY, public, published, not taken down,
+// not soft-launched and released by now
+func (r *MovieRepository) GetCatalogStats(ctx context.Context, now time.Time) (*movies.CatalogStats, error) {
+	var stats movies.CatalogStats
+	err := watchableByVisitors(r.db.WithContext(ctx).Table("movies"), now).
+		Select("COUNT(*) AS total_titles, MAX(movies.release_date) AS newest_release_date").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Genres without public titles are left out
+	stats.Genres = []movies.GenreTitleCount{}
+	err = watchableByVisitors(r.db.WithContext(ctx).Table("movies"), now).
+		Select("genres.id, genres.name, COUNT(*) AS total_titles").
+		Joins("JOIN movie_genres ON movie_genres.movie_id = movies.id").
+		Joins("JOIN genres ON genres.id = movie_genres.genre_id").
+		Group("genres.id, genres.name").
+		Order("total_titles DESC, genres.name ASC").
+		Scan(&stats.Genres).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// FindSitemapMovies returns the titles a visitor can watch, as counted by GetCatalogStats, most recently updated first
+func (r *MovieRepository) FindSitemapMovies(ctx context.Context, now time.Time, limit int) ([]movies.SitemapMovie, error) {
+	var results []movies.SitemapMovie
+	err := watchableByVisitors(r.db.WithContext(ctx).Table("movies"), now).
+		Select("movies.id, movies.canonical_url, movies.updated_at").
+		Order("movies.updated_at DESC").
+		Limit(limit).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// watchableByVisitors keeps the titles that are READY, public, published, not taken down, not soft-launched and released by now
+func watchableByVisitors(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.
+		Joins("JOIN movie_videos ON movie_videos.movie_id = movies.id").
+		Where("movie_videos.upload_status = ? AND movies.visibility = ? AND movies.is_published = ? AND movies.taken_down_at IS NULL", "READY", movies.VisibilityPublic, true).
+		Where("movies.beta_access = ? AND movies.deleted_at IS NULL", false).
+		Where("movies.release_date IS NULL OR movies.release_date <= ?", now)
+}
+
+// FindWatchlistedMovieIDs returns which of the given movies are in the user's watchlist
+func (r *MovieRepository) FindWatchlistedMovieIDs(ctx context.Context, userExtID string, movieIDs []int64) ([]int64, error) {
+	var ids []int64
+	if len(movieIDs) == 0 {
+		return ids, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("watchlist_items").
+		Where("user_ext_id = ? AND movie_id IN ?", userExtID, movieIDs).
+		Pluck("movie_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// findMovieDetail returns detailed information about a movie
+func (r *MovieRepository) findMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error) {
 	var result movies.MovieDetailResponse
 
 	err := r.db.WithContext(ctx).
 		Table("movies").
 		Select("movies.*, COALESCE(movie_videos.upload_status, 'PENDING') as upload_status").
 		Joins("LEFT JOIN movie_videos ON movie_videos.movie_id = movies.id").
-		Where("movies.id = ?", movieID).
+		Where("movies.id = ? AND movies.deleted_at IS NULL", movieID).
 		First(&result).Error
 
 	if err != nil {
@@ -121,10 +266,27 @@ func (r *MovieRepository) FindMovieDetail(ctx context.Context, movieID int64) (*
 
 	// Get genres
 	result.Genres = r.getMovieGenres(ctx, movieID)
+	result.AudioTracks = r.getMovieAudioTracks(ctx, movieID)
 
 	return &result, nil
 }
 
+// FindPublishedEditorialReviews returns the published staff reviews of a movie, newest first
+func (r *MovieRepository) FindPublishedEditorialReviews(ctx context.Context, movieID int64) ([]movies.EditorialReviewSummary, error) {
+	var results []movies.EditorialReviewSummary
+	err := r.db.WithContext(ctx).
+		Table("editorial_reviews").
+		Select("editorial_reviews.id, COALESCE(users.name, '') as author_name, editorial_reviews.headline, editorial_reviews.body, editorial_reviews.score, editorial_reviews.published_at").
+		Joins("LEFT JOIN users ON users.ext_id = editorial_reviews.author_ext_id").
+		Where("editorial_reviews.movie_id = ? AND editorial_reviews.status = ?", movieID, "PUBLISHED").
+		Order("editorial_reviews.published_at DESC").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // UpdateMovie updates movie metadata
 func (r *MovieRepository) UpdateMovie(ctx context.Context, movieID int64, updates map[string]interface{}) error {
 	result := r.db.WithContext(ctx).Model(&movies.Movie{}).Where("id = ?", movieID).Updates(updates)
@@ -134,6 +296,7 @@ func (r *MovieRepository) UpdateMovie(ctx context.Context, movieID int64, update
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("movie with id %d not found", movieID)
 	}
+	r.invalidateCatalogCache(ctx)
 	return nil
 }
 
@@ -146,10 +309,133 @@ func (r *MovieRepository) UpdateMovieVideo(ctx context.Context, movieID int64, u
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("movie_video with movie_id %d not found", movieID)
 	}
+	r.invalidateCatalogCache(ctx)
+	return nil
+}
+
+// ReserveOutputVersion hands out the next output version so concurrent transcodes never share a prefix
+func (r *MovieRepository) ReserveOutputVersion(ctx context.Context, movieID int64) (int, error) {
+	var version int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&movies.MovieVideo{}).
+			Where("movie_id = ?", movieID).
+			Update("latest_output_version", gorm.Expr("GREATEST(latest_output_version, output_version) + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("movie_video with movie_id %d not found", movieID)
+		}
+		return tx.Model(&movies.MovieVideo{}).
+			Where("movie_id = ?", movieID).
+			Pluck("latest_output_version", &version).Error
+	})
+	return version, err
+}
+
+// PublishOutputVersion switches the live output to version in a single update.
+// Returns false when a newer version is already live.
+func (r *MovieRepository) PublishOutputVersion(ctx context.Context, movieID int64, version int, updates map[string]interface{}) (bool, error) {
+	updates["output_version"] = version
+	result := r.db.WithContext(ctx).Model(&movies.MovieVideo{}).
+		Where("movie_id = ? AND output_version < ?", movieID, version).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.invalidateCatalogCache(ctx)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindMovieRenditions returns the renditions of the live version
+func (r *MovieRepository) FindMovieRenditions(ctx context.Context, movieID int64) ([]movies.MovieRendition, error) {
+	var renditions []movies.MovieRendition
+	err := r.db.WithContext(ctx).Where("movie_id = ?", movieID).Order("id ASC").Find(&renditions).Error
+	return renditions, err
+}
+
+// ReplaceMovieRenditions stores the renditions of the latest transcode, dropping older ones
+func (r *MovieRepository) ReplaceMovieRenditions(ctx context.Context, movieID int64, renditions []movies.MovieRendition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("movie_id = ?", movieID).Delete(&movies.MovieRendition{}).Error; err != nil {
+			return err
+		}
+		if len(renditions) == 0 {
+			return nil
+		}
+		return tx.Create(&renditions).Error
+	})
+}
+
+// ReplaceMovieAudioTracks stores the audio languages of the latest transcode, dropping older ones
+func (r *MovieRepository) ReplaceMovieAudioTracks(ctx context.Context, movieID int64, tracks []movies.MovieAudioTrack) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("movie_id = ?", movieID).Delete(&movies.MovieAudioTrack{}).Error; err != nil {
+			return err
+		}
+		if len(tracks) == 0 {
+			return nil
+		}
+		return tx.Create(&tracks).Error
+	})
+	if err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
+}
+
+// RecordCatalogChanges appends entries to the catalog change log
+func (r *MovieRepository) RecordCatalogChanges(ctx context.Context, movieID int64, changeTypes ...string) error {
+	if len(changeTypes) == 0 {
+		return nil
+	}
+
+	changes := make([]movies.CatalogChange, 0, len(changeTypes))
+	for _, changeType := range changeTypes {
+		changes = append(changes, movies.CatalogChange{MovieID: movieID, ChangeType: changeType})
+	}
+	if err := r.db.WithContext(ctx).Create(&changes).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
 	return nil
 }
 
-// DeleteMovie deletes a movie (CASCADE will delete movie_videos too)
+// FindPendingCatalogChanges returns unprocessed changes that have not used up their attempts, oldest first
+func (r *MovieRepository) FindPendingCatalogChanges(ctx context.Context, maxAttempts, limit int) ([]movies.CatalogChange, error) {
+	var changes []movies.CatalogChange
+	err := r.db.WithContext(ctx).
+		Where("processed_at IS NULL AND attempts < ?", maxAttempts).
+		Order("id ASC").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
+}
+
+// MarkCatalogChangesProcessed marks changes whose purge succeeded
+func (r *MovieRepository) MarkCatalogChangesProcessed(ctx context.Context, ids []int64, processedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&movies.CatalogChange{}).
+		Where("id IN ?", ids).
+		Update("processed_at", processedAt).Error
+}
+
+// MarkCatalogChangesFailed counts a failed purge attempt, they are retried until maxAttempts
+func (r *MovieRepository) MarkCatalogChangesFailed(ctx context.Context, ids []int64, lastError string) error {
+	if len(lastError) > 500 {
+		lastError = lastError[:500]
+	}
+	return r.db.WithContext(ctx).Model(&movies.CatalogChange{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		}).Error
+}
+
+// DeleteMovie soft deletes a movie, its videos, orders and access rows are kept
 func (r *MovieRepository) DeleteMovie(ctx context.Context, movieID int64) error {
 	result := r.db.WithContext(ctx).Delete(&movies.Movie{}, movieID)
 	if result.Error != nil {
@@ -158,6 +444,7 @@ func (r *MovieRepository) DeleteMovie(ctx context.Context, movieID int64) error
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("movie with id %d not found", movieID)
 	}
+	r.invalidateCatalogCache(ctx)
 	return nil
 }
 
@@ -176,10 +463,80 @@ func (r *MovieRepository) GetHLSURL(ctx context.Context, movieID int64) (string,
 	return movieVideo.HLSPlaylistURL, nil
 }
 
+// FindOrCreateContentKey returns the content key of a movie, storing newKey when the movie has none yet
+func (r *MovieRepository) FindOrCreateContentKey(ctx context.Context, movieID int64, newKey []byte) ([]byte, error) {
+	key := movies.MovieContentKey{MovieID: movieID}
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Attrs(movies.MovieContentKey{Key: newKey}).
+		FirstOrCreate(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return key.Key, nil
+}
+
+// FindContentKey returns the content key of a movie, nil when its segments are not encrypted
+func (r *MovieRepository) FindContentKey(ctx context.Context, movieID int64) ([]byte, error) {
+	var key movies.MovieContentKey
+	err := r.db.WithContext(ctx).Where("movie_id = ?", movieID).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key.Key, nil
+}
+
+// GetDASHURL returns the DASH manifest of a READY movie, empty when it has none
+func (r *MovieRepository) GetDASHURL(ctx context.Context, movieID int64) (string, error) {
+	movieVideo, err := r.FindMovieVideoByMovieID(ctx, movieID)
+	if err != nil {
+		return "", err
+	}
+	if movieVideo == nil || movieVideo.UploadStatus != "READY" || movieVideo.DASHManifestURL == nil {
+		return "", nil
+	}
+	return *movieVideo.DASHManifestURL, nil
+}
+
+// Upload session methods
+
+// CreateUploadSession creates a new chunked upload session
+func (r *MovieRepository) CreateUploadSession(ctx context.Context, session *movies.MovieUploadSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindUploadSessionByID finds a chunked upload session by its ID
+func (r *MovieRepository) FindUploadSessionByID(ctx context.Context, sessionID int64) (*movies.MovieUploadSession, error) {
+	var session movies.MovieUploadSession
+	err := r.db.WithContext(ctx).Where("id = ?", sessionID).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateUploadSessionStatus updates the status of a chunked upload session
+func (r *MovieRepository) UpdateUploadSessionStatus(ctx context.Context, sessionID int64, status string) error {
+	result := r.db.WithContext(ctx).Model(&movies.MovieUploadSession{}).Where("id = ?", sessionID).Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("upload session with id %d not found", sessionID)
+	}
+	return nil
+}
+
 // Genre-related methods
 
-// GetAllGenres returns all available genres
-func (r *MovieRepository) GetAllGenres(ctx context.Context) ([]movies.Genre, error) {
+// getAllGenres returns all available genres
+func (r *MovieRepository) getAllGenres(ctx context.Context) ([]movies.Genre, error) {
 	var genres []movies.Genre
 	err := r.db.WithContext(ctx).Order("name ASC").Find(&genres).Error
 	return genres, err
@@ -187,7 +544,11 @@ func (r *MovieRepository) GetAllGenres(ctx context.Context) ([]movies.Genre, err
 
 // CreateGenre creates a new genre
 func (r *MovieRepository) CreateGenre(ctx context.Context, genre *movies.Genre) error {
-	return r.db.WithContext(ctx).Create(genre).Error
+	if err := r.db.WithContext(ctx).Create(genre).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
 }
 
 // DeleteGenre deletes a genre by ID
@@ -199,6 +560,72 @@ func (r *MovieRepository) DeleteGenre(ctx context.Context, genreID int) error {
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("genre with id %d not found", genreID)
 	}
+	r.invalidateCatalogCache(ctx)
+	return nil
+}
+
+// FindGenreByID returns a genre, nil if not found
+func (r *MovieRepository) FindGenreByID(ctx context.Context, genreID int) (*movies.Genre, error) {
+	var genre movies.Genre
+	err := r.db.WithContext(ctx).Where("id = ?", genreID).First(&genre).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// UpdateGenre updates genre name and metadata
+func (r *MovieRepository) UpdateGenre(ctx context.Context, genreID int, updates map[string]interface{}) error {
+	if err := r.db.WithContext(ctx).Model(&movies.Genre{}).Where("id = ?", genreID).Updates(updates).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
+}
+
+// People-related methods
+
+// FindPersonByID returns a person, nil if not found
+func (r *MovieRepository) FindPersonByID(ctx context.Context, personID int64) (*movies.Person, error) {
+	var person movies.Person
+	err := r.db.WithContext(ctx).Where("id = ?", personID).First(&person).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &person, nil
+}
+
+// FindOrCreatePerson returns the person with the given name, creating it when missing
+func (r *MovieRepository) FindOrCreatePerson(ctx context.Context, name string) (*movies.Person, error) {
+	var person movies.Person
+	err := r.db.WithContext(ctx).Where(movies.Person{Name: name}).FirstOrCreate(&person).Error
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// UpdatePerson updates person metadata
+func (r *MovieRepository) UpdatePerson(ctx context.Context, personID int64, updates map[string]interface{}) error {
+	if err := r.db.WithContext(ctx).Model(&movies.Person{}).Where("id = ?", personID).Updates(updates).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
+}
+
+// UpdateDirectorName renames the director on every movie linked to the person
+func (r *MovieRepository) UpdateDirectorName(ctx context.Context, personID int64, name string) error {
+	if err := r.db.WithContext(ctx).Model(&movies.Movie{}).Where("director_id = ?", personID).Update("director", name).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
 	return nil
 }
 
@@ -215,6 +642,26 @@ func (r *MovieRepository) getMovieGenres(ctx context.Context, movieID int64) []s
 	return genreNames
 }
 
+// getMovieAudioTracks gets the audio languages of a movie, default track first and uploaded tracks last
+func (r *MovieRepository) getMovieAudioTracks(ctx context.Context, movieID int64) []movies.AudioTrackSummary {
+	var tracks []movies.AudioTrackSummary
+	r.db.WithContext(ctx).
+		Model(&movies.MovieAudioTrack{}).
+		Select("language, name, is_default AS `default`").
+		Where("movie_id = ?", movieID).
+		Order("is_default DESC, id ASC").
+		Scan(&tracks)
+
+	var uploaded []movies.AudioTrackSummary
+	r.db.WithContext(ctx).
+		Model(&movies.AlternateAudio{}).
+		Select("language, name, kind").
+		Where("movie_id = ? AND status = ?", movieID, movies.AlternateAudioReady).
+		Order("id ASC").
+		Scan(&uploaded)
+	return append(tracks, uploaded...)
+}
+
 // AddMovieGenres adds multiple genres to a movie
 func (r *MovieRepository) AddMovieGenres(ctx context.Context, movieID int64, genreIDs []int) error {
 	if len(genreIDs) == 0 {
@@ -230,14 +677,20 @@ func (r *MovieRepository) AddMovieGenres(ctx context.Context, movieID int64, gen
 		})
 	}
 
-	return r.db.WithContext(ctx).Create(&movieGenres).Error
+	if err := r.db.WithContext(ctx).Create(&movieGenres).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
 }
 
 // RemoveAllMovieGenres removes all genres from a movie
 func (r *MovieRepository) RemoveAllMovieGenres(ctx context.Context, movieID int64) error {
-	return r.db.WithContext(ctx).
-		Where("movie_id = ?", movieID).
-		Delete(&movies.MovieGenre{}).Error
+	if err := r.db.WithContext(ctx).Where("movie_id = ?", movieID).Delete(&movies.MovieGenre{}).Error; err != nil {
+		return err
+	}
+	r.invalidateCatalogCache(ctx)
+	return nil
 }
 
 // GetMovieGenreIDs gets all genre IDs for a specific movie
@@ -249,3 +702,611 @@ func (r *MovieRepository) GetMovieGenreIDs(ctx context.Context, movieID int64) (
 		Pluck("genre_id", &genreIDs).Error
 	return genreIDs, err
 }
+
+// SaveSubtitle creates or replaces the subtitle of a movie for its language
+// A default subtitle clears the default flag of the other languages
+func (r *MovieRepository) SaveSubtitle(ctx context.Context, subtitle *movies.Subtitle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if subtitle.IsDefault {
+			if err := tx.Model(&movies.Subtitle{}).
+				Where("movie_id = ? AND language <> ?", subtitle.MovieID, subtitle.Language).
+				Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "movie_id"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"label", "object_name", "is_default", "updated_at"}),
+		}).Create(subtitle).Error
+	})
+}
+
+// FindSubtitlesByMovieID returns the subtitles of a movie, the default first
+func (r *MovieRepository) FindSubtitlesByMovieID(ctx context.Context, movieID int64) ([]movies.Subtitle, error) {
+	var subtitles []movies.Subtitle
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Order("is_default DESC, language ASC").
+		Find(&subtitles).Error
+	return subtitles, err
+}
+
+// DeleteSubtitle deletes the subtitle of a movie for a language, false when it did not exist
+func (r *MovieRepository) DeleteSubtitle(ctx context.Context, movieID int64, language string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("movie_id = ? AND language = ?", movieID, language).Delete(&movies.Subtitle{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateSubtitleDraft stores a generated subtitle awaiting review
+func (r *MovieRepository) CreateSubtitleDraft(ctx context.Context, draft *movies.SubtitleDraft) error {
+	return r.db.WithContext(ctx).Create(draft).Error
+}
+
+// FindSubtitleDrafts returns the subtitle drafts of a movie, newest first
+func (r *MovieRepository) FindSubtitleDrafts(ctx context.Context, movieID int64) ([]movies.SubtitleDraft, error) {
+	var drafts []movies.SubtitleDraft
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Order("created_at DESC, id DESC").
+		Find(&drafts).Error
+	return drafts, err
+}
+
+// FindSubtitleDraft returns a subtitle draft of a movie, nil when it does not exist
+func (r *MovieRepository) FindSubtitleDraft(ctx context.Context, movieID, draftID int64) (*movies.SubtitleDraft, error) {
+	var draft movies.SubtitleDraft
+	err := r.db.WithContext(ctx).Where("id = ? AND movie_id = ?", draftID, movieID).First(&draft).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// HasOpenSubtitleDraft reports whether a movie has a draft that is not reviewed yet
+func (r *MovieRepository) HasOpenSubtitleDraft(ctx context.Context, movieID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&movies.SubtitleDraft{}).
+		Where("movie_id = ? AND status = ?", movieID, movies.SubtitleDraftPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ReviewSubtitleDraft moves a pending draft to the given status, false when it was already reviewed
+func (r *MovieRepository) ReviewSubtitleDraft(ctx context.Context, draftID int64, status, reviewerExtID string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&movies.SubtitleDraft{}).
+		Where("id = ? AND status = ?", draftID, movies.SubtitleDraftPending).
+		Updates(map[string]interface{}{
+			"status":             status,
+			"reviewed_by_ext_id": reviewerExtID,
+			"reviewed_at":        time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateMediaReview holds back an uploaded file until an admin decides on it
+func (r *MovieRepository) CreateMediaReview(ctx context.Context, review *movies.MediaReview) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+// FindMediaReviews returns the newest media reviews, of every status when status is empty
+func (r *MovieRepository) FindMediaReviews(ctx context.Context, status string, limit int) ([]movies.MediaReview, error) {
+	var reviews []movies.MediaReview
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&reviews).Error
+	return reviews, err
+}
+
+// FindMediaReview returns a media review, nil when it does not exist
+func (r *MovieRepository) FindMediaReview(ctx context.Context, reviewID int64) (*movies.MediaReview, error) {
+	var review movies.MediaReview
+	err := r.db.WithContext(ctx).Where("id = ?", reviewID).First(&review).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ReviewMediaReview records the decision on a pending media review, false when it was already decided
+func (r *MovieRepository) ReviewMediaReview(ctx context.Context, reviewID int64, status, reviewerExtID string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&movies.MediaReview{}).
+		Where("id = ? AND status = ?", reviewID, movies.MediaReviewPending).
+		Updates(map[string]interface{}{
+			"status":             status,
+			"reviewed_by_ext_id": reviewerExtID,
+			"reviewed_at":        time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateAlternateAudio stores an uploaded alternate audio track waiting for the worker
+func (r *MovieRepository) CreateAlternateAudio(ctx context.Context, track *movies.AlternateAudio) error {
+	return r.db.WithContext(ctx).Create(track).Error
+}
+
+// FindAlternateAudio returns an alternate audio track, nil when it does not exist
+func (r *MovieRepository) FindAlternateAudio(ctx context.Context, trackID int64) (*movies.AlternateAudio, error) {
+	var track movies.AlternateAudio
+	err := r.db.WithContext(ctx).Where("id = ?", trackID).First(&track).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &track, nil
+}
+
+// FindAlternateAudioByMovieID returns the alternate audio tracks of a movie in upload order
+func (r *MovieRepository) FindAlternateAudioByMovieID(ctx context.Context, movieID int64) ([]movies.AlternateAudio, error) {
+	var tracks []movies.AlternateAudio
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Order("id ASC").
+		Find(&tracks).Error
+	return tracks, err
+}
+
+// FindReadyAlternateAudio returns the alternate audio tracks players can select
+func (r *MovieRepository) FindReadyAlternateAudio(ctx context.Context, movieID int64) ([]movies.AlternateAudio, error) {
+	var tracks []movies.AlternateAudio
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ? AND status = ?", movieID, movies.AlternateAudioReady).
+		Order("id ASC").
+		Find(&tracks).Error
+	return tracks, err
+}
+
+// UpdateAlternateAudio updates the status fields of an alternate audio track
+func (r *MovieRepository) UpdateAlternateAudio(ctx context.Context, trackID int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&movies.AlternateAudio{}).Where("id = ?", trackID).Updates(updates).Error
+}
+
+// DeleteAlternateAudio deletes an alternate audio track of a movie, false when it did not exist
+func (r *MovieRepository) DeleteAlternateAudio(ctx context.Context, movieID, trackID int64) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND movie_id = ?", trackID, movieID).Delete(&movies.AlternateAudio{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindMovieAudioTracks returns the audio tracks of the live version, default track first
+func (r *MovieRepository) FindMovieAudioTracks(ctx context.Context, movieID int64) ([]movies.MovieAudioTrack, error) {
+	var tracks []movies.MovieAudioTrack
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Order("is_default DESC, id ASC").
+		Find(&tracks).Error
+	return tracks, err
+}
+
+// CreateLicenseWindow stores a licensing window of a movie
+func (r *MovieRepository) CreateLicenseWindow(ctx context.Context, window *movies.LicenseWindow) error {
+	return r.db.WithContext(ctx).Create(window).Error
+}
+
+// FindLicenseWindows returns the licensing windows of a movie by region and start
+func (r *MovieRepository) FindLicenseWindows(ctx context.Context, movieID int64) ([]movies.LicenseWindow, error) {
+	var windows []movies.LicenseWindow
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Order("region ASC, starts_at ASC").
+		Find(&windows).Error
+	return windows, err
+}
+
+// HasOverlappingLicenseWindow reports whether the movie already has a window in the region overlapping [startsAt, endsAt)
+func (r *MovieRepository) HasOverlappingLicenseWindow(ctx context.Context, movieID int64, region string, startsAt, endsAt time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&movies.LicenseWindow{}).
+		Where("movie_id = ? AND region = ? AND starts_at < ? AND ends_at > ?", movieID, region, endsAt, startsAt).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteLicenseWindow removes a licensing window of a movie, false when it does not exist
+func (r *MovieRepository) DeleteLicenseWindow(ctx context.Context, movieID, windowID int64) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND movie_id = ?", windowID, movieID).Delete(&movies.LicenseWindow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindExpiringLicenseWindows returns the windows closing between from and to, soonest first.
+// Windows followed by a renewal in the same region are left out.
+func (r *MovieRepository) FindExpiringLicenseWindows(ctx context.Context, from, to time.Time) ([]movies.ExpiringLicenseWindow, error) {
+	var windows []movies.ExpiringLicenseWindow
+	err := r.db.WithContext(ctx).
+		Table("movie_license_windows AS w").
+		Select("w.*, movies.title AS movie_title, movies.visibility").
+		Joins("JOIN movies ON movies.id = w.movie_id AND movies.deleted_at IS NULL").
+		Where("w.ends_at >= ? AND w.ends_at < ?", from, to).
+		Where(`NOT EXISTS (SELECT 1 FROM movie_license_windows AS renewal
+			WHERE renewal.movie_id = w.movie_id AND renewal.region = w.region
+			AND renewal.starts_at <= w.ends_at AND renewal.ends_at > w.ends_at)`).
+		Order("w.ends_at ASC, w.id ASC").
+		Scan(&windows).Error
+	return windows, err
+}
+
+// FindRawSources returns the uploaded sources the movies refer to, deleted movies included.
+// Movies that do not exist anymore are missing from the result.
+func (r *MovieRepository) FindRawSources(ctx context.Context, movieIDs []int64) ([]movies.RawSource, error) {
+	var sources []movies.RawSource
+	err := r.db.WithContext(ctx).
+		Table("movies m").
+		Select("m.id AS movie_id, m.deleted_at, v.upload_status, v.raw_file_path, v.processed_at").
+		Joins("LEFT JOIN movie_videos v ON v.movie_id = m.id").
+		Where("m.id IN ?", movieIDs).
+		Scan(&sources).Error
+	return sources, err
+}
+
+// FindMoviesWithClosedLicenses returns published movies whose licensing windows all closed before now
+func (r *MovieRepository) FindMoviesWithClosedLicenses(ctx context.Context, now time.Time) ([]int64, error) {
+	var movieIDs []int64
+	err := r.db.WithContext(ctx).
+		Model(&movies.Movie{}).
+		Where("is_published = ?", true).
+		Where("EXISTS (SELECT 1 FROM movie_license_windows WHERE movie_license_windows.movie_id = movies.id)").
+		Where("NOT EXISTS (SELECT 1 FROM movie_license_windows WHERE movie_license_windows.movie_id = movies.id AND movie_license_windows.ends_at > ?)", now).
+		Pluck("movies.id", &movieIDs).Error
+	return movieIDs, err
+}
+
+// SetMoviePublished publishes or unpublishes a movie, false when it already was in that state
+func (r *MovieRepository) SetMoviePublished(ctx context.Context, movieID int64, published bool) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&movies.Movie{}).
+		Where("id = ? AND is_published = ?", movieID, !published).
+		Update("is_published", published)
+	if result.Error == nil && result.RowsAffected > 0 {
+		r.invalidateCatalogCache(ctx)
+	}
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetMovieBetaAccess soft-launches a movie or releases it to everyone, false when it already was in that state
+func (r *MovieRepository) SetMovieBetaAccess(ctx context.Context, movieID int64, beta bool) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&movies.Movie{}).
+		Where("id = ? AND beta_access = ?", movieID, !beta).
+		Update("beta_access", beta)
+	if result.Error == nil && result.RowsAffected > 0 {
+		r.invalidateCatalogCache(ctx)
+	}
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindBetaViewers returns the allowlist of a movie, latest additions first
+func (r *MovieRepository) FindBetaViewers(ctx context.Context, movieID int64) ([]movies.MovieBetaViewer, error) {
+	var viewers []movies.MovieBetaViewer
+	err := r.db.WithContext(ctx).
+		Where("movie_id = ?", movieID).
+		Order("created_at DESC, user_ext_id").
+		Find(&viewers).Error
+	return viewers, err
+}
+
+// AddBetaViewers puts users on the allowlist of a movie, users already on it are kept as they are
+func (r *MovieRepository) AddBetaViewers(ctx context.Context, viewers []movies.MovieBetaViewer) error {
+	if len(viewers) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&viewers).Error
+}
+
+// RemoveBetaViewer takes a user off the allowlist of a movie, false when the user was not on it
+func (r *MovieRepository) RemoveBetaViewer(ctx context.Context, movieID int64, userExtID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("movie_id = ? AND user_ext_id = ?", movieID, userExtID).
+		Delete(&movies.MovieBetaViewer{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// HasBetaAccess reports whether a user is on the allowlist of a movie
+func (r *MovieRepository) HasBetaAccess(ctx context.Context, movieID int64, userExtID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&movies.MovieBetaViewer{}).
+		Where("movie_id = ? AND user_ext_id = ?", movieID, userExtID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindExistingUserExtIDs returns which of the given ext ids belong to an account
+func (r *MovieRepository) FindExistingUserExtIDs(ctx context.Context, userExtIDs []string) ([]string, error) {
+	var existing []string
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Where("ext_id IN ?", userExtIDs).
+		Pluck("ext_id", &existing).Error
+	return existing, err
+}
+
+// AddDailyViews adds flushed playback starts to the daily counters and the all-time view counts.
+// Views of movies deleted in the meantime are dropped.
+func (r *MovieRepository) AddDailyViews(ctx context.Context, day time.Time, counts map[int64]int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for movieID, views := range counts {
+			result := tx.Model(&movies.Movie{}).
+				Where("id = ?", movieID).
+				Update("view_count", gorm.Expr("view_count + ?", views))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			row := movies.MovieDailyViews{MovieID: movieID, ViewDate: day, Views: views}
+			err := tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("views + VALUES(views)")}),
+			}).Create(&row).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindPopularMovies returns the public READY movies watched since the given day, highest popularity score first.
+// Titles the worker has not scored yet are ranked by their playback starts in the period.
+// The list is shared by every visitor, so soft-launched titles are left out.
+func (r *MovieRepository) FindPopularMovies(ctx context.Context, since time.Time, limit int) ([]movies.MovieListResponse, error) {
+	var results []movies.MovieListResponse
+	err := r.db.WithContext(ctx).
+		Table("movie_daily_views").
+		Select(movieListColumns+", movie_videos.upload_status, movies.popularity_score, SUM(movie_daily_views.views) AS views").
+		Joins("JOIN movies ON movies.id = movie_daily_views.movie_id").
+		Joins("JOIN movie_videos ON movie_videos.movie_id = movies.id").
+		Where("movie_daily_views.view_date >= ?", since).
+		Where("movie_videos.upload_status = ? AND movies.is_published = ? AND movies.taken_down_at IS NULL AND movies.deleted_at IS NULL", "READY", true).
+		Where("movies.beta_access = ?", false).
+		Group("movies.id, movie_videos.upload_status").
+		Order("movies.popularity_score DESC, views DESC, movies.id DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// FindPopularitySignals returns the daily activity per movie since the given day: playback starts,
+// viewers who reached the end and paid orders. Refunded orders do not count.
+func (r *MovieRepository) FindPopularitySignals(ctx context.Context, since time.Time) ([]movies.PopularitySignal, error) {
+	type dailyCount struct {
+		MovieID int64
+		Day     time.Time
+		Count   int64
+	}
+	collect := func(query *gorm.DB) ([]dailyCount, error) {
+		var rows []dailyCount
+		err := query.Find(&rows).Error
+		return rows, err
+	}
+
+	db := r.db.WithContext(ctx)
+	views, err := collect(db.Table("movie_daily_views").
+		Select("movie_id, view_date AS day, SUM(views) AS count").
+		Where("view_date >= ?", since).
+		Group("movie_id, view_date"))
+	if err != nil {
+		return nil, err
+	}
+	completions, err := collect(db.Table("watch_progress").
+		Select("movie_id, DATE(updated_at) AS day, COUNT(*) AS count").
+		Where("updated_at >= ? AND duration_seconds > 0 AND position_seconds >= duration_seconds * ?", since, movies.CompletionThreshold).
+		Group("movie_id, DATE(updated_at)"))
+	if err != nil {
+		return nil, err
+	}
+	orders, err := collect(db.Table("orders").
+		Select("movie_id, DATE(paid_at) AS day, COUNT(*) AS count").
+		Where("paid_at >= ? AND payment_status = ?", since, "PAID").
+		Group("movie_id, DATE(paid_at)"))
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		movieID int64
+		day     string
+	}
+	signals := make(map[key]*movies.PopularitySignal)
+	signal := func(row dailyCount) *movies.PopularitySignal {
+		day := row.Day.UTC().Truncate(24 * time.Hour)
+		k := key{row.MovieID, day.Format("2006-01-02")}
+		if signals[k] == nil {
+			signals[k] = &movies.PopularitySignal{MovieID: row.MovieID, Day: day}
+		}
+		return signals[k]
+	}
+	for _, row := range views {
+		signal(row).Views += row.Count
+	}
+	for _, row := range completions {
+		signal(row).Completions += row.Count
+	}
+	for _, row := range orders {
+		signal(row).Orders += row.Count
+	}
+
+	result := make([]movies.PopularitySignal, 0, len(signals))
+	for _, s := range signals {
+		result = append(result, *s)
+	}
+	// Sum in the same order on every run
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MovieID != result[j].MovieID {
+			return result[i].MovieID < result[j].MovieID
+		}
+		return result[i].Day.Before(result[j].Day)
+	})
+	return result, nil
+}
+
+// FindAverageEditorialScores returns the average published editorial score per reviewed movie
+func (r *MovieRepository) FindAverageEditorialScores(ctx context.Context) (map[int64]float64, error) {
+	var rows []struct {
+		MovieID int64
+		Score   float64
+	}
+	err := r.db.WithContext(ctx).
+		Table("editorial_reviews").
+		Select("movie_id, AVG(score) AS score").
+		Where("status = ?", "PUBLISHED").
+		Group("movie_id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int64]float64, len(rows))
+	for _, row := range rows {
+		scores[row.MovieID] = row.Score
+	}
+	return scores, nil
+}
+
+// UpdatePopularityScores stores the recomputed scores, movies missing from scores drop to 0.
+// Returns how many movies changed.
+func (r *MovieRepository) UpdatePopularityScores(ctx context.Context, scores map[int64]float64) (int64, error) {
+	var changed int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reset := tx.Model(&movies.Movie{}).Where("popularity_score <> 0")
+		if len(scores) > 0 {
+			ids := make([]int64, 0, len(scores))
+			for movieID := range scores {
+				ids = append(ids, movieID)
+			}
+			reset = reset.Where("id NOT IN ?", ids)
+		}
+		result := reset.Update("popularity_score", 0)
+		if result.Error != nil {
+			return result.Error
+		}
+		changed += result.RowsAffected
+
+		for movieID, score := range scores {
+			result := tx.Model(&movies.Movie{}).
+				Where("id = ? AND popularity_score <> ?", movieID, score).
+				Update("popularity_score", score)
+			if result.Error != nil {
+				return result.Error
+			}
+			changed += result.RowsAffected
+		}
+		return nil
+	})
+	if err == nil && changed > 0 {
+		r.invalidateCatalogCache(ctx)
+	}
+	return changed, err
+}
+
+// FindTrendingMovies returns the public READY movies with the highest popularity score,
+// ties are broken by id so the order never changes between requests
+func (r *MovieRepository) FindTrendingMovies(ctx context.Context, limit int) ([]movies.MovieListResponse, error) {
+	var results []movies.MovieListResponse
+	err := r.db.WithContext(ctx).
+		Table("movies").
+		Select(movieListColumns+", movie_videos.upload_status, movies.popularity_score").
+		Joins("JOIN movie_videos ON movie_videos.movie_id = movies.id").
+		Where("movie_videos.upload_status = ? AND movies.is_published = ? AND movies.taken_down_at IS NULL AND movies.deleted_at IS NULL", "READY", true).
+		Where("movies.beta_access = ? AND movies.popularity_score > 0", false).
+		Order("movies.popularity_score DESC, movies.id DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// FindViewingHistory returns the movies a user rented, bought or started watching
+func (r *MovieRepository) FindViewingHistory(ctx context.Context, userExtID string) ([]int64, error) {
+	var movieIDs []int64
+	err := r.db.WithContext(ctx).
+		Raw("SELECT movie_id FROM user_movie_access WHERE user_ext_id = ? UNION SELECT movie_id FROM watch_progress WHERE user_ext_id = ?", userExtID, userExtID).
+		Scan(&movieIDs).Error
+	return movieIDs, err
+}
+
+// FindMovieGenreIDs returns the genre ids of each of the given movies
+func (r *MovieRepository) FindMovieGenreIDs(ctx context.Context, movieIDs []int64) (map[int64][]int, error) {
+	genreIDs := make(map[int64][]int, len(movieIDs))
+	if len(movieIDs) == 0 {
+		return genreIDs, nil
+	}
+
+	var rows []movies.MovieGenre
+	if err := r.db.WithContext(ctx).Where("movie_id IN ?", movieIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		genreIDs[row.MovieID] = append(genreIDs[row.MovieID], row.GenreID)
+	}
+	return genreIDs, nil
+}
+
+// FindCoViewedMovies counts, per movie, the other users who got access to it and to one of the given movies.
+// The given movies themselves are left out, the most shared movies come first.
+func (r *MovieRepository) FindCoViewedMovies(ctx context.Context, userExtID string, movieIDs []int64, limit int) (map[int64]int64, error) {
+	counts := make(map[int64]int64)
+	if len(movieIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		MovieID int64
+		Viewers int64
+	}
+	err := r.db.WithContext(ctx).
+		Table("user_movie_access AS mine").
+		Select("other.movie_id, COUNT(DISTINCT other.user_ext_id) AS viewers").
+		Joins("JOIN user_movie_access AS other ON other.user_ext_id = mine.user_ext_id").
+		Where("mine.movie_id IN ? AND mine.user_ext_id <> ?", movieIDs, userExtID).
+		Where("other.movie_id NOT IN ?", movieIDs).
+		Group("other.movie_id").
+		Order("viewers DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.MovieID] = row.Viewers
+	}
+	return counts, nil
+}
+
+// FindRecommendableMovies returns public READY movies in one of the genres or among the given movies,
+// most viewed first. Without genres and movies the most viewed titles of the catalog are returned.
+// Soft-launched titles are never recommended.
+func (r *MovieRepository) FindRecommendableMovies(ctx context.Context, genreIDs []int, movieIDs []int64, exclude []int64, limit int) ([]movies.MovieListResponse, error) {
+	var results []movies.MovieListResponse
+	query := r.db.WithContext(ctx).
+		Table("movies").
+		Select(movieListColumns+", movie_videos.upload_status").
+		Joins("JOIN movie_videos ON movie_videos.movie_id = movies.id").
+		Where("movie_videos.upload_status = ? AND movies.visibility = ? AND movies.is_published = ? AND movies.taken_down_at IS NULL AND movies.deleted_at IS NULL", "READY", movies.VisibilityPublic, true).
+		Where("movies.beta_access = ?", false)
+
+	if len(exclude) > 0 {
+		query = query.Where("movies.id NOT IN ?", exclude)
+	}
+	switch {
+	case len(genreIDs) > 0 && len(movieIDs) > 0:
+		query = query.Where("movies.id IN ? OR EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = movies.id AND mg.genre_id IN ?)", movieIDs, genreIDs)
+	case len(genreIDs) > 0:
+		query = query.Where("EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = movies.id AND mg.genre_id IN ?)", genreIDs)
+	case len(movieIDs) > 0:
+		query = query.Where("movies.id IN ?", movieIDs)
+	}
+
+	err := query.Order("movies.view_count DESC, movies.id DESC").Limit(limit).Find(&results).Error
+	return results, err
+}
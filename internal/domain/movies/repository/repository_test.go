@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB builds MySQL statements without a database
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+	return db
+}
+
+func TestFilterUploadStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter movies.MovieFilter
+		want   string // expected condition, empty when there must be none
+	}{
+		{
+			name:   "all statuses",
+			filter: movies.MovieFilter{AllStatuses: true, Statuses: []string{movies.UploadStatusFailed}},
+		},
+		{
+			name:   "multiple statuses",
+			filter: movies.MovieFilter{Statuses: []string{movies.UploadStatusPending, movies.UploadStatusFailed}},
+			want:   "COALESCE(movie_videos.upload_status, 'PENDING') IN ('PENDING','FAILED')",
+		},
+		{
+			name:   "default is READY",
+			filter: movies.MovieFilter{},
+			want:   "movie_videos.upload_status = 'READY'",
+		},
+	}
+
+	db := dryRunDB(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var results []movies.MovieListResponse
+				return filterUploadStatus(tx.Table("movies").Joins("LEFT JOIN movie_videos ON movie_videos.movie_id = movies.id"), tt.filter).Find(&results)
+			})
+
+			if tt.want == "" {
+				if strings.Contains(sql, "WHERE") {
+					t.Errorf("expected no status condition, got %s", sql)
+				}
+				return
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("expected %s in %s", tt.want, sql)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// maxAlternateAudioSize is the largest audio file accepted, a lossless feature length track fits
+const maxAlternateAudioSize = 2 << 30
+
+// alternateAudioExtensions are the containers ffmpeg reads an audio stream from
+var alternateAudioExtensions = map[string]bool{
+	".aac":  true,
+	".ac3":  true,
+	".eac3": true,
+	".flac": true,
+	".m4a":  true,
+	".mka":  true,
+	".mp3":  true,
+	".ogg":  true,
+	".opus": true,
+	".wav":  true,
+}
+
+// UploadAlternateAudio stores a dub or audio description for a movie and queues it for packaging (Admin only)
+// The video is not encoded again, the track is listed once the worker marks it READY
+func (u *MovieUsecase) UploadAlternateAudio(ctx context.Context, movieID int64, req movies.UploadAlternateAudioRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.AlternateAudio, error) {
+	if !alternateAudioExtensions[strings.ToLower(filepath.Ext(fileHeader.Filename))] {
+		return nil, apperr.Validation("unsupported_audio_format", "use .aac, .ac3, .eac3, .flac, .m4a, .mka, .mp3, .ogg, .opus or .wav")
+	}
+	if fileHeader.Size > maxAlternateAudioSize {
+		return nil, apperr.Validation("audio_file_too_large", "max 2 GB")
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	objectName, err := u.storageService.UploadAlternateAudio(ctx, file, fileHeader, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = req.Language
+	}
+	track := &movies.AlternateAudio{
+		MovieID:      movieID,
+		Language:     req.Language,
+		Name:         name,
+		Kind:         req.Kind,
+		SourceObject: objectName,
+		Status:       movies.AlternateAudioPending,
+	}
+	if err := u.repo.CreateAlternateAudio(ctx, track); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if err := u.queueService.PublishAudioJob(ctx, movieID, track.ID); err != nil {
+		message := fmt.Sprintf("Failed to queue audio job: %v", err)
+		u.repo.UpdateAlternateAudio(ctx, track.ID, map[string]interface{}{
+			"status":        movies.AlternateAudioFailed,
+			"error_message": message,
+		})
+		return nil, apperr.Internal(err)
+	}
+
+	return track, nil
+}
+
+// GetAlternateAudio returns the uploaded audio tracks of a movie with their processing status (Admin only)
+func (u *MovieUsecase) GetAlternateAudio(ctx context.Context, movieID int64) ([]movies.AlternateAudio, error) {
+	tracks, err := u.repo.FindAlternateAudioByMovieID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return tracks, nil
+}
+
+// DeleteAlternateAudio removes an uploaded audio track and its rendition (Admin only)
+func (u *MovieUsecase) DeleteAlternateAudio(ctx context.Context, movieID, trackID int64) error {
+	track, err := u.repo.FindAlternateAudio(ctx, trackID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if track == nil || track.MovieID != movieID {
+		return apperr.NotFound("audio_track_not_found", nil)
+	}
+
+	// Master playlists list tracks from the database, so the record goes first
+	if _, err := u.repo.DeleteAlternateAudio(ctx, movieID, trackID); err != nil {
+		return apperr.Internal(err)
+	}
+	if err := u.storageService.DeleteAlternateAudio(ctx, movieID, trackID, track.SourceObject); err != nil {
+		return apperr.Internal(err)
+	}
+
+	// The movie detail lists ready tracks
+	if track.Status == movies.AlternateAudioReady {
+		u.recordCatalogChanges(ctx, movieID, movies.CatalogChangeUpdated)
+	}
+	return nil
+}
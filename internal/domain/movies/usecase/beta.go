@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/jwt"
+)
+
+// betaViewer returns who is browsing the catalog, nil for anonymous requests
+func betaViewer(ctx context.Context) *movies.BetaViewer {
+	userExtID, err := jwt.GetUserExtIDFromStdContext(ctx)
+	if err != nil {
+		return nil
+	}
+	role, _ := ctx.Value(constant.CtxKeyUserRole).(string)
+	return &movies.BetaViewer{UserExtID: userExtID, Tester: role == constant.RoleTester}
+}
+
+// canSeeBetaMovie reports whether the requester may see a soft-launched movie
+func (u *MovieUsecase) canSeeBetaMovie(ctx context.Context, movieID int64) (bool, error) {
+	viewer := betaViewer(ctx)
+	if viewer == nil {
+		return false, nil
+	}
+	if viewer.Tester {
+		return true, nil
+	}
+	return u.repo.HasBetaAccess(ctx, movieID, viewer.UserExtID)
+}
+
+// SoftLaunchMovie limits a movie to testers and its allowlist until it is released (Admin only)
+func (u *MovieUsecase) SoftLaunchMovie(ctx context.Context, movieID int64) error {
+	return u.setMovieBetaAccess(ctx, movieID, true)
+}
+
+// ReleaseMovie ends the soft launch of a movie, everyone can see it from now on (Admin only).
+// The allowlist is kept, so the movie can be soft-launched again to the same users.
+func (u *MovieUsecase) ReleaseMovie(ctx context.Context, movieID int64) error {
+	return u.setMovieBetaAccess(ctx, movieID, false)
+}
+
+func (u *MovieUsecase) setMovieBetaAccess(ctx context.Context, movieID int64, beta bool) error {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if movie == nil {
+		return apperr.NotFound("movie_not_found", nil)
+	}
+
+	changed, err := u.repo.SetMovieBetaAccess(ctx, movieID, beta)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !changed {
+		if beta {
+			return apperr.Conflict("movie_already_soft_launched", nil)
+		}
+		return apperr.Conflict("movie_not_soft_launched", nil)
+	}
+
+	u.recordCatalogChanges(ctx, movieID, movies.CatalogChangeVisibility)
+	return nil
+}
+
+// GetBetaViewers lists the users allowed to see a soft-launched movie (Admin only)
+func (u *MovieUsecase) GetBetaViewers(ctx context.Context, movieID int64) ([]movies.MovieBetaViewer, error) {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	viewers, err := u.repo.FindBetaViewers(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if viewers == nil {
+		viewers = []movies.MovieBetaViewer{}
+	}
+	return viewers, nil
+}
+
+// AddBetaViewers puts users on the allowlist of a movie and returns the whole list (Admin only).
+// Nothing is added when one of the users does not exist.
+func (u *MovieUsecase) AddBetaViewers(ctx context.Context, adminExtID string, movieID int64, req movies.AddBetaViewersRequest) ([]movies.MovieBetaViewer, error) {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	userExtIDs := make([]string, 0, len(req.UserExtIDs))
+	seen := make(map[string]bool, len(req.UserExtIDs))
+	for _, userExtID := range req.UserExtIDs {
+		userExtID = strings.TrimSpace(userExtID)
+		if userExtID == "" || seen[userExtID] {
+			continue
+		}
+		seen[userExtID] = true
+		userExtIDs = append(userExtIDs, userExtID)
+	}
+	if len(userExtIDs) == 0 {
+		return nil, apperr.Validation("no_users", "user_ext_ids must contain at least one user")
+	}
+
+	existing, err := u.repo.FindExistingUserExtIDs(ctx, userExtIDs)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, userExtID := range existing {
+		known[userExtID] = true
+	}
+	var unknown []string
+	for _, userExtID := range userExtIDs {
+		if !known[userExtID] {
+			unknown = append(unknown, userExtID)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, apperr.Validation("unknown_users", "no account found for: "+strings.Join(unknown, ", "))
+	}
+
+	viewers := make([]movies.MovieBetaViewer, len(userExtIDs))
+	for i, userExtID := range userExtIDs {
+		viewers[i] = movies.MovieBetaViewer{MovieID: movieID, UserExtID: userExtID, AddedBy: adminExtID}
+	}
+	if err := u.repo.AddBetaViewers(ctx, viewers); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return u.GetBetaViewers(ctx, movieID)
+}
+
+// RemoveBetaViewer takes a user off the allowlist of a movie (Admin only)
+func (u *MovieUsecase) RemoveBetaViewer(ctx context.Context, movieID int64, userExtID string) error {
+	removed, err := u.repo.RemoveBetaViewer(ctx, movieID, userExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !removed {
+		return apperr.NotFound("beta_viewer_not_found", nil)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// resolveDirectorID links a director name to its people record, creating it on first use
+func (u *MovieUsecase) resolveDirectorID(ctx context.Context, director string) (*int64, error) {
+	name := strings.TrimSpace(director)
+	if name == "" {
+		return nil, nil
+	}
+
+	person, err := u.repo.FindOrCreatePerson(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &person.ID, nil
+}
+
+// GetGenreMovies returns the browse page of a genre (Public - only READY movies)
+func (u *MovieUsecase) GetGenreMovies(ctx context.Context, genreID int, page, limit int, sort string) (*movies.GenreMoviesResponse, error) {
+	genre, err := u.repo.FindGenreByID(ctx, genreID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if genre == nil {
+		return nil, apperr.NotFound("genre_not_found", nil)
+	}
+
+	list, err := u.browseMovies(ctx, page, limit, sort, movies.MovieFilter{GenreID: genreID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &movies.GenreMoviesResponse{
+		Genre:      *genre,
+		Movies:     list.Movies,
+		Pagination: list.Pagination,
+	}, nil
+}
+
+// GetPersonMovies returns the browse page of a director (Public - only READY movies)
+func (u *MovieUsecase) GetPersonMovies(ctx context.Context, personID int64, page, limit int, sort string) (*movies.PersonMoviesResponse, error) {
+	person, err := u.repo.FindPersonByID(ctx, personID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if person == nil {
+		return nil, apperr.NotFound("person_not_found", nil)
+	}
+
+	list, err := u.browseMovies(ctx, page, limit, sort, movies.MovieFilter{DirectorID: personID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &movies.PersonMoviesResponse{
+		Person:     *person,
+		Movies:     list.Movies,
+		Pagination: list.Pagination,
+	}, nil
+}
+
+// browseMovies lists public READY movies matching the filter
+func (u *MovieUsecase) browseMovies(ctx context.Context, page, limit int, sort string, filter movies.MovieFilter) (*movies.MovieListWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 12
+	}
+
+	sortSpec, ok := movies.ParseSort(sort)
+	if !ok {
+		return nil, apperr.Validation("invalid_sort", "sort must be one of: popular, newest, price_asc, price_desc, title, duration, views")
+	}
+
+	filter.Statuses = []string{movies.UploadStatusReady}
+	filter.PublicOnly = true
+	filter.Viewer = betaViewer(ctx)
+	movieList, totalCount, err := u.repo.FindAllMovies(ctx, page, limit, filter, sortSpec)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movieList == nil {
+		movieList = []movies.MovieListResponse{}
+	}
+	u.resolveListMediaURLs(ctx, movieList)
+	markListAvailable(movieList)
+	if err := u.markListInWatchlist(ctx, movieList); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &movies.MovieListWithPagination{
+		Movies: movieList,
+		Pagination: movies.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+// UpdatePerson updates a person's name, bio and photo (Admin only)
+// Renaming also updates the director name shown on their movies
+func (u *MovieUsecase) UpdatePerson(ctx context.Context, personID int64, req movies.UpdatePersonRequest) (*movies.Person, error) {
+	person, err := u.repo.FindPersonByID(ctx, personID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if person == nil {
+		return nil, apperr.NotFound("person_not_found", nil)
+	}
+
+	updates := make(map[string]interface{})
+	name := strings.TrimSpace(req.Name)
+	if name != "" && name != person.Name {
+		updates["name"] = name
+	}
+	if req.Bio != nil {
+		updates["bio"] = *req.Bio
+	}
+	if req.PhotoURL != nil {
+		updates["photo_url"] = *req.PhotoURL
+	}
+	if len(updates) == 0 {
+		return person, nil
+	}
+
+	if err := u.repo.UpdatePerson(ctx, personID, updates); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if _, renamed := updates["name"]; renamed {
+		if err := u.repo.UpdateDirectorName(ctx, personID, name); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	}
+
+	person, err = u.repo.FindPersonByID(ctx, personID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return person, nil
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/tmdb"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// MetadataProvider looks up catalog metadata on an external movie database, see tmdb.Client
+type MetadataProvider interface {
+	LookupMovie(ctx context.Context, title string, year int) (*tmdb.Movie, error)
+}
+
+// EnrichMovie fills in description, poster, release date, director and genres from TMDB (Admin only)
+// Fields that are already set are kept unless req.Overwrite, genres are matched to existing genres by name
+func (u *MovieUsecase) EnrichMovie(ctx context.Context, movieID int64, req movies.EnrichMovieRequest) (*movies.EnrichMovieResponse, error) {
+	if u.metadata == nil {
+		return nil, apperr.Unavailable("metadata_enrichment_disabled", "TMDB is not configured")
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	title := req.Title
+	if title == "" {
+		title = movie.Title
+	}
+	year := req.Year
+	if year == 0 && !movie.ReleaseDate.IsZero() {
+		year = movie.ReleaseDate.Year()
+	}
+
+	found, err := u.metadata.LookupMovie(ctx, title, year)
+	if err != nil {
+		if errors.Is(err, tmdb.ErrNotFound) {
+			return nil, apperr.NotFound("tmdb_movie_not_found", nil)
+		}
+		return nil, apperr.Unavailable("tmdb_unavailable", err.Error())
+	}
+
+	updates := make(map[string]interface{})
+	if found.Overview != "" && (movie.Description == "" || req.Overwrite) {
+		updates["description"] = found.Overview
+	}
+	if found.PosterURL != "" && (movie.PosterURL == "" || req.Overwrite) {
+		updates["poster_url"] = found.PosterURL
+	}
+	if !found.ReleaseDate.IsZero() && (movie.ReleaseDate.IsZero() || req.Overwrite) {
+		updates["release_date"] = found.ReleaseDate
+	}
+	if found.Director != "" && (movie.Director == "" || req.Overwrite) {
+		directorID, err := u.resolveDirectorID(ctx, found.Director)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		updates["director"] = found.Director
+		updates["director_id"] = directorID
+	}
+
+	genreIDs, err := u.matchGenres(ctx, movieID, found.Genres, req.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(updates)+1)
+	for column := range updates {
+		if column != "director_id" {
+			fields = append(fields, column)
+		}
+	}
+
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := u.repo.UpdateMovie(ctx, movieID, updates); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	}
+
+	if len(genreIDs) > 0 {
+		if err := u.repo.RemoveAllMovieGenres(ctx, movieID); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if err := u.repo.AddMovieGenres(ctx, movieID, genreIDs); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		fields = append(fields, "genres")
+	}
+	sort.Strings(fields)
+
+	u.recordCatalogChanges(ctx, movieID, updateChangeTypes(updates, len(genreIDs) > 0)...)
+
+	return &movies.EnrichMovieResponse{
+		MovieID:       movieID,
+		TMDBID:        found.ID,
+		MatchedTitle:  found.Title,
+		UpdatedFields: fields,
+	}, nil
+}
+
+// matchGenres maps TMDB genre names to existing genres, nil when the movie keeps its genres.
+// Genres are managed by admins, so names without a matching genre are skipped.
+func (u *MovieUsecase) matchGenres(ctx context.Context, movieID int64, names []string, overwrite bool) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if !overwrite {
+		current, err := u.repo.GetMovieGenreIDs(ctx, movieID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if len(current) > 0 {
+			return nil, nil
+		}
+	}
+
+	genres, err := u.repo.GetAllGenres(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	byName := make(map[string]int, len(genres))
+	for _, genre := range genres {
+		byName[strings.ToLower(genre.Name)] = genre.ID
+	}
+
+	var genreIDs []int
+	for _, name := range names {
+		if id, ok := byName[strings.ToLower(name)]; ok {
+			genreIDs = append(genreIDs, id)
+		}
+	}
+	return genreIDs, nil
+}
+
+// enrichUploadedMovie fills in a new movie from TMDB when the upload asked for it.
+// The upload already succeeded, so a failed lookup is only logged.
+func (u *MovieUsecase) enrichUploadedMovie(ctx context.Context, movieID int64, enrich bool) {
+	if !enrich {
+		return
+	}
+	if _, err := u.EnrichMovie(ctx, movieID, movies.EnrichMovieRequest{}); err != nil {
+		log.Printf("Warning: Failed to enrich movie %d from TMDB: %v", movieID, err)
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// Bounds of the expiring licenses report, in days
+const (
+	defaultExpiringLicenseDays = 30
+	maxExpiringLicenseDays     = 365
+)
+
+// CreateLicenseWindow adds a licensing window to a movie (Admin only)
+// Windows of the same region must not overlap, a renewal starts when the previous window ends
+func (u *MovieUsecase) CreateLicenseWindow(ctx context.Context, movieID int64, req movies.CreateLicenseWindowRequest) (*movies.LicenseWindow, error) {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	region := strings.ToUpper(req.Region)
+	startsAt := req.StartsAt.UTC().Truncate(time.Second)
+	endsAt := req.EndsAt.UTC().Truncate(time.Second)
+
+	overlaps, err := u.repo.HasOverlappingLicenseWindow(ctx, movieID, region, startsAt, endsAt)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if overlaps {
+		return nil, apperr.Conflict("license_window_overlaps", "the movie already has a window in this region for that period")
+	}
+
+	window := &movies.LicenseWindow{
+		MovieID:  movieID,
+		Region:   region,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	}
+	if err := u.repo.CreateLicenseWindow(ctx, window); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return window, nil
+}
+
+// GetLicenseWindows lists the licensing windows of a movie (Admin only)
+func (u *MovieUsecase) GetLicenseWindows(ctx context.Context, movieID int64) ([]movies.LicenseWindow, error) {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	windows, err := u.repo.FindLicenseWindows(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return windows, nil
+}
+
+// DeleteLicenseWindow removes a licensing window of a movie (Admin only)
+func (u *MovieUsecase) DeleteLicenseWindow(ctx context.Context, movieID, windowID int64) error {
+	deleted, err := u.repo.DeleteLicenseWindow(ctx, movieID, windowID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !deleted {
+		return apperr.NotFound("license_window_not_found", nil)
+	}
+	return nil
+}
+
+// GetExpiringLicenses reports the licensing windows closing within the next days without a renewal (Admin only)
+func (u *MovieUsecase) GetExpiringLicenses(ctx context.Context, days int) ([]movies.ExpiringLicenseWindow, error) {
+	if days <= 0 {
+		days = defaultExpiringLicenseDays
+	}
+	if days > maxExpiringLicenseDays {
+		return nil, apperr.Validation("invalid_days", "max 365")
+	}
+
+	now := time.Now()
+	windows, err := u.repo.FindExpiringLicenseWindows(ctx, now, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return windows, nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/signedurl"
+)
+
+// Media serving modes
+const (
+	MediaModeProxy     = "proxy"
+	MediaModePresigned = "presigned"
+)
+
+// MediaOptions controls how posters/trailers stored in the media bucket are exposed
+type MediaOptions struct {
+	PublicMode    string
+	PrivateMode   string
+	PresignExpiry time.Duration
+	ProxyBaseURL  string
+	Signer        *signedurl.Signer
+}
+
+// UploadMovieMedia stores a poster or trailer in the private media bucket (Admin only)
+func (u *MovieUsecase) UploadMovieMedia(ctx context.Context, movieID int64, kind string, file multipart.File, fileHeader *multipart.FileHeader) (*movies.MovieMediaResponse, error) {
+	if kind != movies.MediaKindPoster && kind != movies.MediaKindTrailer {
+		return nil, apperr.Validation("invalid_media_kind", nil)
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	// Posters are scanned before anyone sees them, flagged ones wait in the moderation queue
+	if kind == movies.MediaKindPoster && u.moderator != nil {
+		reason, err := u.scanMedia(ctx, file, fileHeader)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if reason != "" {
+			return u.holdMovieMedia(ctx, movieID, kind, reason, file, fileHeader)
+		}
+	}
+
+	objectName, err := u.storageService.UploadMovieMedia(ctx, file, fileHeader, movieID, kind)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// The object name replaces the absolute URL, it is resolved when the movie is served
+	if err := u.repo.UpdateMovie(ctx, movieID, map[string]interface{}{
+		kind + "_url": objectName,
+		"updated_at":  time.Now(),
+	}); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	changeType := movies.CatalogChangePoster
+	if kind == movies.MediaKindTrailer {
+		changeType = movies.CatalogChangeTrailer
+	}
+	u.recordCatalogChanges(ctx, movieID, changeType)
+
+	return &movies.MovieMediaResponse{
+		MovieID:    movieID,
+		Kind:       kind,
+		ObjectName: objectName,
+		URL:        u.resolveMediaURL(ctx, movieID, movie.Visibility, kind, objectName),
+	}, nil
+}
+
+// OpenMovieMedia opens a poster/trailer for the media proxy (Public)
+// Private movies require a valid signature issued by resolveMediaURL
+func (u *MovieUsecase) OpenMovieMedia(ctx context.Context, movieID int64, kind, expires, signature string) (io.ReadCloser, *storage.MediaObject, string, error) {
+	if kind != movies.MediaKindPoster && kind != movies.MediaKindTrailer {
+		return nil, nil, "", apperr.NotFound("media_not_found", nil)
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, nil, "", apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, nil, "", apperr.NotFound("movie_not_found", nil)
+	}
+
+	if movie.Visibility == movies.VisibilityPrivate {
+		if u.media.Signer == nil || !u.media.Signer.Verify(mediaProxyPath(movieID, kind), expires, signature) {
+			return nil, nil, "", apperr.Forbidden("invalid_or_expired_signature", nil)
+		}
+	}
+
+	objectName := movie.PosterURL
+	if kind == movies.MediaKindTrailer {
+		objectName = movie.TrailerURL
+	}
+	if objectName == "" || isAbsoluteURL(objectName) {
+		return nil, nil, "", apperr.NotFound("media_not_found", nil)
+	}
+
+	reader, info, err := u.storageService.GetMediaObject(ctx, objectName)
+	if err != nil {
+		return nil, nil, "", apperr.NotFound("media_not_found", err.Error())
+	}
+
+	return reader, info, movie.Visibility, nil
+}
+
+// resolveListMediaURLs resolves poster URLs for a catalog page
+func (u *MovieUsecase) resolveListMediaURLs(ctx context.Context, movieList []movies.MovieListResponse) {
+	for i := range movieList {
+		movieList[i].PosterURL = u.resolveMediaURL(ctx, movieList[i].ID, movieList[i].Visibility, movies.MediaKindPoster, movieList[i].PosterURL)
+	}
+}
+
+// resolveMediaURL turns a stored media object name into a URL clients can load
+// Absolute URLs (external posters/trailers) are returned unchanged
+func (u *MovieUsecase) resolveMediaURL(ctx context.Context, movieID int64, visibility, kind, value string) string {
+	if value == "" || isAbsoluteURL(value) {
+		return value
+	}
+
+	mode := u.media.PublicMode
+	if visibility == movies.VisibilityPrivate {
+		mode = u.media.PrivateMode
+	}
+
+	if mode == MediaModePresigned {
+		presignedURL, err := u.storageService.PresignMediaURL(ctx, value, u.media.PresignExpiry)
+		if err != nil {
+			fmt.Printf("Warning: Failed to presign %s for movie %d: %v\n", kind, movieID, err)
+			return ""
+		}
+		return presignedURL
+	}
+
+	path := mediaProxyPath(movieID, kind)
+	proxyURL := strings.TrimSuffix(u.media.ProxyBaseURL, "/") + path
+	if visibility == movies.VisibilityPrivate && u.media.Signer != nil {
+		proxyURL += "?" + u.media.Signer.SignedQuery(path, u.media.PresignExpiry).Encode()
+	}
+	return proxyURL
+}
+
+// mediaProxyPath returns the media proxy path for a movie poster/trailer
+func mediaProxyPath(movieID int64, kind string) string {
+	return fmt.Sprintf("/api/v1/media/movies/%d/%s", movieID, kind)
+}
+
+// isAbsoluteURL reports whether a poster/trailer value is an external URL rather than an object name
+func isAbsoluteURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/moderation"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxMediaReviews caps the moderation queue returned at once
+	maxMediaReviews = 100
+	// mediaReviewPreviewExpiry is how long admins can open a held file
+	mediaReviewPreviewExpiry = 15 * time.Minute
+)
+
+// ContentModerator scans uploaded images before they are published, see moderation.Moderator
+type ContentModerator interface {
+	CheckImage(ctx context.Context, image io.Reader, contentType string) (moderation.Verdict, error)
+}
+
+// scanMedia returns why an uploaded file has to wait for an admin, empty when it can be published.
+// A file the moderation service cannot scan waits as well, the file is rewound for the upload.
+func (u *MovieUsecase) scanMedia(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader) (string, error) {
+	reason := ""
+	verdict, err := u.moderator.CheckImage(ctx, file, fileHeader.Header.Get("Content-Type"))
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Content moderation failed, holding the upload for review")
+		reason = "moderation_unavailable"
+	} else if verdict.Flagged {
+		reason = verdict.Reason
+		if reason == "" {
+			reason = "flagged"
+		}
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return reason, nil
+}
+
+// holdMovieMedia stores a file moderation held back, the movie keeps its current media until an admin approves it
+func (u *MovieUsecase) holdMovieMedia(ctx context.Context, movieID int64, kind, reason string, file multipart.File, fileHeader *multipart.FileHeader) (*movies.MovieMediaResponse, error) {
+	objectName, err := u.storageService.UploadHeldMovieMedia(ctx, file, fileHeader, movieID, kind)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	review := &movies.MediaReview{
+		MovieID:    movieID,
+		Kind:       kind,
+		ObjectName: objectName,
+		Reason:     reason,
+		Status:     movies.MediaReviewPending,
+	}
+	if err := u.repo.CreateMediaReview(ctx, review); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.MovieMediaResponse{
+		MovieID:      movieID,
+		Kind:         kind,
+		ObjectName:   objectName,
+		ReviewID:     &review.ID,
+		ReviewReason: reason,
+	}, nil
+}
+
+// GetMediaReviews returns the newest held uploads, of every status when status is empty (Admin only)
+func (u *MovieUsecase) GetMediaReviews(ctx context.Context, status string) ([]movies.MediaReview, error) {
+	switch status {
+	case "", movies.MediaReviewPending, movies.MediaReviewApproved, movies.MediaReviewRejected:
+	default:
+		return nil, apperr.Validation("invalid_status", "status must be one of: PENDING, APPROVED, REJECTED")
+	}
+
+	reviews, err := u.repo.FindMediaReviews(ctx, status, maxMediaReviews)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if reviews == nil {
+		reviews = []movies.MediaReview{}
+	}
+
+	for i := range reviews {
+		if reviews[i].Status != movies.MediaReviewPending {
+			continue
+		}
+		previewURL, err := u.storageService.PresignMediaURL(ctx, reviews[i].ObjectName, mediaReviewPreviewExpiry)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Int64("review_id", reviews[i].ID).Msg("Failed to presign held media")
+			continue
+		}
+		reviews[i].PreviewURL = previewURL
+	}
+	return reviews, nil
+}
+
+// ApproveMediaReview overrides the moderation verdict and publishes the held file (Admin only)
+func (u *MovieUsecase) ApproveMediaReview(ctx context.Context, reviewID int64, reviewerExtID string) (*movies.MovieMediaResponse, error) {
+	review, err := u.findMediaReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != movies.MediaReviewPending {
+		return nil, apperr.Conflict("media_review_already_decided", review.Status)
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, review.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	// Claiming the review first keeps two admins from deciding on it at once
+	claimed, err := u.repo.ReviewMediaReview(ctx, review.ID, movies.MediaReviewApproved, reviewerExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !claimed {
+		return nil, apperr.Conflict("media_review_already_decided", nil)
+	}
+
+	if err := u.repo.UpdateMovie(ctx, movie.ID, map[string]interface{}{
+		review.Kind + "_url": review.ObjectName,
+		"updated_at":         time.Now(),
+	}); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	changeType := movies.CatalogChangePoster
+	if review.Kind == movies.MediaKindTrailer {
+		changeType = movies.CatalogChangeTrailer
+	}
+	u.recordCatalogChanges(ctx, movie.ID, changeType)
+
+	return &movies.MovieMediaResponse{
+		MovieID:    movie.ID,
+		Kind:       review.Kind,
+		ObjectName: review.ObjectName,
+		URL:        u.resolveMediaURL(ctx, movie.ID, movie.Visibility, review.Kind, review.ObjectName),
+	}, nil
+}
+
+// RejectMediaReview confirms the moderation verdict and deletes the held file (Admin only)
+func (u *MovieUsecase) RejectMediaReview(ctx context.Context, reviewID int64, reviewerExtID string) error {
+	review, err := u.findMediaReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	rejected, err := u.repo.ReviewMediaReview(ctx, review.ID, movies.MediaReviewRejected, reviewerExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !rejected {
+		return apperr.Conflict("media_review_already_decided", review.Status)
+	}
+
+	if err := u.storageService.DeleteMediaObject(ctx, review.ObjectName); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (u *MovieUsecase) findMediaReview(ctx context.Context, reviewID int64) (*movies.MediaReview, error) {
+	review, err := u.repo.FindMediaReview(ctx, reviewID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if review == nil {
+		return nil, apperr.NotFound("media_review_not_found", nil)
+	}
+	return review, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// Bounds of the popular movies list
+const (
+	defaultPopularDays  = 7
+	maxPopularDays      = 90
+	defaultPopularLimit = 10
+	maxPopularLimit     = 50
+)
+
+// Bounds of the trending movies list
+const (
+	defaultTrendingLimit = 20
+	maxTrendingLimit     = 100
+)
+
+// GetPopularMovies returns the public titles watched in the last days, ranked by popularity score (Public)
+// Views of today are included once the worker flushed them, usually within a minute.
+func (u *MovieUsecase) GetPopularMovies(ctx context.Context, days, limit int) (*movies.PopularMoviesResponse, error) {
+	if days < 1 || days > maxPopularDays {
+		days = defaultPopularDays
+	}
+	if limit < 1 || limit > maxPopularLimit {
+		limit = defaultPopularLimit
+	}
+
+	// Daily counters are kept per UTC day, today counts as one of the days
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	movieList, err := u.repo.FindPopularMovies(ctx, since, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movieList == nil {
+		movieList = []movies.MovieListResponse{}
+	}
+	u.resolveListMediaURLs(ctx, movieList)
+	markListAvailable(movieList)
+	if err := u.markListInWatchlist(ctx, movieList); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.PopularMoviesResponse{
+		Days:   days,
+		Movies: movieList,
+	}, nil
+}
+
+// GetTrendingMovies returns the public titles with the highest popularity score (Public).
+// Scores change when the worker recomputes them, the order is stable in between.
+func (u *MovieUsecase) GetTrendingMovies(ctx context.Context, limit int) ([]movies.MovieListResponse, error) {
+	if limit < 1 || limit > maxTrendingLimit {
+		limit = defaultTrendingLimit
+	}
+
+	movieList, err := u.repo.FindTrendingMovies(ctx, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movieList == nil {
+		movieList = []movies.MovieListResponse{}
+	}
+	u.resolveListMediaURLs(ctx, movieList)
+	markListAvailable(movieList)
+	if err := u.markListInWatchlist(ctx, movieList); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return movieList, nil
+}
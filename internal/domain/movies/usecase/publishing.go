@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// PublishMovie lists an unpublished movie in the catalog again (Admin only)
+func (u *MovieUsecase) PublishMovie(ctx context.Context, movieID int64) error {
+	return u.setMoviePublished(ctx, movieID, true)
+}
+
+// UnpublishMovie pulls a movie from the catalog and stops new orders (Admin only)
+// Orders and access that were already paid for are kept and can still be streamed.
+func (u *MovieUsecase) UnpublishMovie(ctx context.Context, movieID int64) error {
+	return u.setMoviePublished(ctx, movieID, false)
+}
+
+func (u *MovieUsecase) setMoviePublished(ctx context.Context, movieID int64, published bool) error {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if movie == nil {
+		return apperr.NotFound("movie_not_found", nil)
+	}
+
+	changed, err := u.repo.SetMoviePublished(ctx, movieID, published)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !changed {
+		if published {
+			return apperr.Conflict("movie_already_published", nil)
+		}
+		return apperr.Conflict("movie_not_published", nil)
+	}
+
+	u.recordCatalogChanges(ctx, movieID, movies.CatalogChangeVisibility)
+	return nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// Bounds of the transcoding queue listing
+const (
+	defaultQueuePeek = 50
+	maxQueuePeek     = 500
+)
+
+// GetTranscodingQueue lists the pending transcoding jobs, next job first, and the jobs in flight (Admin only)
+func (u *MovieUsecase) GetTranscodingQueue(ctx context.Context, limit int) (*queue.PendingJobs, error) {
+	if limit < 1 || limit > maxQueuePeek {
+		limit = defaultQueuePeek
+	}
+
+	pending, err := u.queueService.PeekTranscodingJobs(ctx, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return pending, nil
+}
+
+// EnqueueTranscodingJob queues a transcode of a movie with custom parameters (Admin only).
+// Profiles narrow the ladder, the version the job publishes only has those renditions.
+func (u *MovieUsecase) EnqueueTranscodingJob(ctx context.Context, adminExtID string, req movies.EnqueueTranscodingJobRequest) (*queue.TranscodingJob, error) {
+	movie, err := u.repo.FindMovieByID(ctx, req.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	rawFilePath := strings.TrimSpace(req.RawFilePath)
+	if rawFilePath == "" {
+		movieVideo, err := u.repo.FindMovieVideoByMovieID(ctx, req.MovieID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if movieVideo == nil || movieVideo.RawFilePath == "" {
+			return nil, apperr.NotFound("movie_video_not_found", nil)
+		}
+		rawFilePath = movieVideo.RawFilePath
+	} else {
+		exists, err := u.storageService.RawVideoExists(ctx, rawFilePath)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if !exists {
+			return nil, apperr.NotFound("raw_video_not_found", nil)
+		}
+	}
+
+	if err := validateProfileSet(req.ProfileSet); err != nil {
+		return nil, err
+	}
+	profileSetName := req.ProfileSet
+	if profileSetName == "" {
+		profileSetName = movie.ProfileSet
+	}
+	profileSet := transcoding.LookupProfileSet(profileSetName)
+	for _, name := range req.Profiles {
+		if len(profileSet.Only([]string{name}).Profiles) == 0 {
+			return nil, apperr.Validation("invalid_profile", "profile set "+profileSet.Name+" has no profile "+name)
+		}
+	}
+
+	job, err := u.queueService.EnqueueTranscodingJob(ctx, queue.TranscodingJob{
+		MovieID:     req.MovieID,
+		RawFilePath: rawFilePath,
+		ProfileSet:  req.ProfileSet,
+		Profiles:    req.Profiles,
+		RequestedBy: adminExtID,
+	}, req.Next)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return job, nil
+}
+
+// RemoveTranscodingJob takes a pending job off the queue (Admin only)
+func (u *MovieUsecase) RemoveTranscodingJob(ctx context.Context, jobID string) error {
+	removed, err := u.queueService.RemoveTranscodingJob(ctx, jobID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !removed {
+		return apperr.NotFound("job_not_found", nil)
+	}
+	return nil
+}
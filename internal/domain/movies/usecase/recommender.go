@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// Weights of the recommendation signals, each signal is between 0 and 1
+const (
+	genreOverlapWeight = 0.6
+	coViewerWeight     = 0.4
+)
+
+// Bounds of the recommendations
+const (
+	defaultRecommendationLimit = 12
+	maxRecommendationLimit     = 50
+	recommendationCandidates   = 200 // most viewed matching titles that are scored
+	coViewedMoviesLimit        = 100
+)
+
+// recommender scores titles for one user by how much their genres overlap with the user's history
+// and by how many viewers of the same titles also rented them
+type recommender struct {
+	genreShare   map[int]float64 // share of the history titles in each genre
+	coViewers    map[int64]int64
+	maxCoViewers int64
+}
+
+func newRecommender(historyGenres map[int64][]int, coViewers map[int64]int64) *recommender {
+	r := &recommender{genreShare: make(map[int]float64), coViewers: coViewers}
+	for _, genreIDs := range historyGenres {
+		for _, genreID := range genreIDs {
+			r.genreShare[genreID]++
+		}
+	}
+	for genreID := range r.genreShare {
+		r.genreShare[genreID] /= float64(len(historyGenres))
+	}
+	for _, viewers := range coViewers {
+		if viewers > r.maxCoViewers {
+			r.maxCoViewers = viewers
+		}
+	}
+	return r
+}
+
+// genreIDs returns the genres of the user's history
+func (r *recommender) genreIDs() []int {
+	ids := make([]int, 0, len(r.genreShare))
+	for genreID := range r.genreShare {
+		ids = append(ids, genreID)
+	}
+	return ids
+}
+
+// coViewedIDs returns the titles shared with viewers of the user's history
+func (r *recommender) coViewedIDs() []int64 {
+	ids := make([]int64, 0, len(r.coViewers))
+	for movieID := range r.coViewers {
+		ids = append(ids, movieID)
+	}
+	return ids
+}
+
+// score rates a title between 0 and 1
+func (r *recommender) score(movieID int64, genreIDs []int) float64 {
+	overlap := 0.0
+	for _, genreID := range genreIDs {
+		overlap += r.genreShare[genreID]
+	}
+	if overlap > 1 {
+		overlap = 1
+	}
+
+	shared := 0.0
+	if r.maxCoViewers > 0 {
+		shared = float64(r.coViewers[movieID]) / float64(r.maxCoViewers)
+	}
+
+	return genreOverlapWeight*overlap + coViewerWeight*shared
+}
+
+// GetRecommendations suggests titles based on the genres of the user's rented and watched movies.
+// Titles the user already rented, bought or watched are left out. Without history popular titles are suggested.
+func (u *MovieUsecase) GetRecommendations(ctx context.Context, userExtID string, limit int) (*movies.RecommendationsResponse, error) {
+	if limit < 1 || limit > maxRecommendationLimit {
+		limit = defaultRecommendationLimit
+	}
+
+	history, err := u.repo.FindViewingHistory(ctx, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	historyGenres, err := u.repo.FindMovieGenreIDs(ctx, history)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	coViewers, err := u.repo.FindCoViewedMovies(ctx, userExtID, history, coViewedMoviesLimit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	rec := newRecommender(historyGenres, coViewers)
+
+	genreIDs, coViewedIDs := rec.genreIDs(), rec.coViewedIDs()
+	personalized := len(genreIDs) > 0 || len(coViewedIDs) > 0
+
+	candidateLimit := limit
+	if personalized {
+		candidateLimit = recommendationCandidates
+	}
+	movieList, err := u.repo.FindRecommendableMovies(ctx, genreIDs, coViewedIDs, history, candidateLimit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if personalized {
+		ids := make([]int64, len(movieList))
+		for i := range movieList {
+			ids[i] = movieList[i].ID
+		}
+		candidateGenres, err := u.repo.FindMovieGenreIDs(ctx, ids)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+
+		scores := make(map[int64]float64, len(movieList))
+		for _, movie := range movieList {
+			scores[movie.ID] = rec.score(movie.ID, candidateGenres[movie.ID])
+		}
+		// Stable, so equal scores keep the most viewed first
+		sort.SliceStable(movieList, func(i, j int) bool {
+			return scores[movieList[i].ID] > scores[movieList[j].ID]
+		})
+		if len(movieList) > limit {
+			movieList = movieList[:limit]
+		}
+	}
+
+	if movieList == nil {
+		movieList = []movies.MovieListResponse{}
+	}
+	u.resolveListMediaURLs(ctx, movieList)
+	markListAvailable(movieList)
+	if err := u.markListInWatchlist(ctx, movieList); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.RecommendationsResponse{
+		Personalized: personalized,
+		Movies:       movieList,
+	}, nil
+}
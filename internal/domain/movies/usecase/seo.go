@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+const (
+	// metaDescriptionLength is where the description is cut when no meta description is set
+	metaDescriptionLength = 160
+
+	// maxSitemapURLs is the most URLs one sitemap file may list
+	maxSitemapURLs = 50000
+)
+
+// GetMovieMeta returns the page metadata of a movie visible in the catalog, with the SEO overrides applied
+func (u *MovieUsecase) GetMovieMeta(ctx context.Context, movieID int64) (*movies.MovieMeta, error) {
+	movieDetail, err := u.publicMovieDetail(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := u.movieMeta(movieDetail.ID, movieDetail.Title, movieDetail.Description, movieDetail.SEO)
+	meta.ImageURL = u.resolveMediaURL(ctx, movieDetail.ID, movieDetail.Visibility, movies.MediaKindPoster, movieDetail.PosterURL)
+	meta.ReleaseDate = movieDetail.ReleaseDate
+	meta.UpdatedAt = movieDetail.UpdatedAt
+	return meta, nil
+}
+
+// GetMovieSEO returns the SEO overrides of a movie and the meta they result in (Admin only)
+func (u *MovieUsecase) GetMovieSEO(ctx context.Context, movieID int64) (*movies.MovieSEOResponse, error) {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	meta := u.movieMeta(movie.ID, movie.Title, movie.Description, movie.SEO)
+	meta.ImageURL = u.resolveMediaURL(ctx, movie.ID, movie.Visibility, movies.MediaKindPoster, movie.PosterURL)
+	if !movie.ReleaseDate.IsZero() {
+		meta.ReleaseDate = movie.ReleaseDate.Format("2006-01-02")
+	}
+	meta.UpdatedAt = movie.UpdatedAt
+	return &movies.MovieSEOResponse{SEO: movie.SEO, Meta: *meta}, nil
+}
+
+// UpdateMovieSEO replaces the SEO overrides of a movie, empty fields fall back to the defaults (Admin only)
+func (u *MovieUsecase) UpdateMovieSEO(ctx context.Context, movieID int64, req movies.UpdateMovieSEORequest) (*movies.MovieSEOResponse, error) {
+	updates := map[string]interface{}{
+		"seo_title":        nullIfBlank(req.Title),
+		"meta_description": nullIfBlank(req.MetaDescription),
+		"canonical_url":    nullIfBlank(req.CanonicalURL),
+	}
+	if err := u.updateMovieSEO(ctx, movieID, updates); err != nil {
+		return nil, err
+	}
+	return u.GetMovieSEO(ctx, movieID)
+}
+
+// ClearMovieSEO removes the SEO overrides of a movie (Admin only)
+func (u *MovieUsecase) ClearMovieSEO(ctx context.Context, movieID int64) error {
+	return u.updateMovieSEO(ctx, movieID, map[string]interface{}{
+		"seo_title":        nil,
+		"meta_description": nil,
+		"canonical_url":    nil,
+	})
+}
+
+func (u *MovieUsecase) updateMovieSEO(ctx context.Context, movieID int64, updates map[string]interface{}) error {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if movie == nil {
+		return apperr.NotFound("movie_not_found", nil)
+	}
+
+	updates["updated_at"] = time.Now()
+	if err := u.repo.UpdateMovie(ctx, movieID, updates); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// GetSitemap lists the movie pages a visitor can watch, titles without a canonical URL are left out
+// while no site URL is configured
+func (u *MovieUsecase) GetSitemap(ctx context.Context) ([]movies.SitemapURL, error) {
+	sitemapMovies, err := u.repo.FindSitemapMovies(ctx, time.Now(), maxSitemapURLs)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	urls := make([]movies.SitemapURL, 0, len(sitemapMovies))
+	for _, movie := range sitemapMovies {
+		loc := u.canonicalURL(movie.ID, movie.CanonicalURL)
+		if loc == "" {
+			continue
+		}
+		urls = append(urls, movies.SitemapURL{Loc: loc, LastMod: movie.UpdatedAt.UTC().Format("2006-01-02")})
+	}
+	return urls, nil
+}
+
+// movieMeta applies the SEO overrides to the title and description of a movie
+func (u *MovieUsecase) movieMeta(movieID int64, title, description string, seo movies.SEO) *movies.MovieMeta {
+	meta := &movies.MovieMeta{
+		MovieID:      movieID,
+		Title:        title,
+		Description:  summarize(description, metaDescriptionLength),
+		CanonicalURL: u.canonicalURL(movieID, seo.CanonicalURL),
+	}
+	if seo.Title != nil {
+		meta.Title = *seo.Title
+	}
+	if seo.MetaDescription != nil {
+		meta.Description = *seo.MetaDescription
+	}
+	return meta
+}
+
+// canonicalURL returns the override or the movie page of the site, empty when neither is set
+func (u *MovieUsecase) canonicalURL(movieID int64, override *string) string {
+	if override != nil {
+		return *override
+	}
+	if u.siteURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/movies/%d", u.siteURL, movieID)
+}
+
+// summarize cuts text to at most limit characters at a word boundary
+func summarize(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	cut := string([]rune(text)[:limit-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// nullIfBlank stores an empty value as NULL
+func nullIfBlank(value string) interface{} {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// catalogStatsTTL bounds how stale the stats get when the catalog changes on another instance or in the worker
+const catalogStatsTTL = 5 * time.Minute
+
+// GetCatalogStats returns the public catalog stats, aggregated at most once per catalogStatsTTL
+func (u *MovieUsecase) GetCatalogStats(ctx context.Context) (*movies.CatalogStats, error) {
+	// Held during the query so concurrent misses aggregate once
+	u.statsMu.Lock()
+	defer u.statsMu.Unlock()
+
+	if u.stats != nil && time.Since(u.stats.GeneratedAt) < catalogStatsTTL {
+		return u.stats, nil
+	}
+
+	now := time.Now()
+	stats, err := u.repo.GetCatalogStats(ctx, now)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	stats.GeneratedAt = now
+
+	u.stats = stats
+	return stats, nil
+}
+
+// invalidateCatalogStats drops the cached stats after a catalog change on this instance
+func (u *MovieUsecase) invalidateCatalogStats() {
+	u.statsMu.Lock()
+	u.stats = nil
+	u.statsMu.Unlock()
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/hls"
+	"github.com/martinmanurung/cinestream/pkg/subtitle"
+)
+
+// maxSubtitleSize is the largest subtitle file accepted, feature length subtitles are well below it
+const maxSubtitleSize = 5 << 20
+
+// UploadSubtitle converts an .srt/.vtt file to WebVTT and stores it as the movie's track for the language (Admin only)
+// Uploading a language again replaces its track
+func (u *MovieUsecase) UploadSubtitle(ctx context.Context, movieID int64, req movies.UploadSubtitleRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.Subtitle, error) {
+	format, err := subtitle.FormatFromFileName(fileHeader.Filename)
+	if err != nil {
+		return nil, apperr.Validation("unsupported_subtitle_format", err.Error())
+	}
+	if fileHeader.Size > maxSubtitleSize {
+		return nil, apperr.Validation("subtitle_file_too_large", "max 5 MB")
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSubtitleSize))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	vtt, lastCue, err := subtitle.ToWebVTT(data, format)
+	if err != nil {
+		if errors.Is(err, subtitle.ErrNoCues) {
+			return nil, apperr.Validation("subtitle_has_no_cues", nil)
+		}
+		return nil, apperr.Validation("invalid_subtitle_file", err.Error())
+	}
+
+	return u.publishSubtitle(ctx, movie, req.Language, req.Label, req.IsDefault, vtt, lastCue)
+}
+
+// publishSubtitle stores a WebVTT file with its playlist and makes it the movie's track for the language
+func (u *MovieUsecase) publishSubtitle(ctx context.Context, movie *movies.Movie, language, label string, isDefault bool, vtt []byte, lastCue time.Duration) (*movies.Subtitle, error) {
+	// The playlist covers the whole movie even when the last cue ends earlier
+	duration := time.Duration(movie.DurationMinutes) * time.Minute
+	if lastCue > duration {
+		duration = lastCue
+	}
+	playlist := hls.SubtitlePlaylist(language+".vtt", duration)
+
+	objectName, err := u.storageService.UploadSubtitle(ctx, movie.ID, language, vtt, playlist)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if label == "" {
+		label = language
+	}
+	track := &movies.Subtitle{
+		MovieID:    movie.ID,
+		Language:   language,
+		Label:      label,
+		ObjectName: objectName,
+		IsDefault:  isDefault,
+	}
+	if err := u.repo.SaveSubtitle(ctx, track); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return track, nil
+}
+
+// GetSubtitles returns the subtitle tracks of a movie (Admin only)
+func (u *MovieUsecase) GetSubtitles(ctx context.Context, movieID int64) ([]movies.Subtitle, error) {
+	subtitles, err := u.repo.FindSubtitlesByMovieID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return subtitles, nil
+}
+
+// DeleteSubtitle removes the subtitle track of a movie for a language (Admin only)
+func (u *MovieUsecase) DeleteSubtitle(ctx context.Context, movieID int64, language string) error {
+	deleted, err := u.repo.DeleteSubtitle(ctx, movieID, language)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !deleted {
+		return apperr.NotFound("subtitle_not_found", nil)
+	}
+
+	// Players only learn about tracks from the database, so the record goes first
+	if err := u.storageService.DeleteSubtitle(ctx, movieID, language); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// GetSubtitleDrafts returns the speech-to-text drafts of a movie (Admin only)
+func (u *MovieUsecase) GetSubtitleDrafts(ctx context.Context, movieID int64) ([]movies.SubtitleDraft, error) {
+	drafts, err := u.repo.FindSubtitleDrafts(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return drafts, nil
+}
+
+// GetSubtitleDraftFile returns the WebVTT content of a draft for review (Admin only)
+func (u *MovieUsecase) GetSubtitleDraftFile(ctx context.Context, movieID, draftID int64) ([]byte, error) {
+	draft, err := u.findSubtitleDraft(ctx, movieID, draftID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status == movies.SubtitleDraftRejected {
+		return nil, apperr.NotFound("subtitle_draft_not_found", nil)
+	}
+
+	data, err := u.storageService.GetSubtitleDraft(ctx, draft.ObjectName, maxSubtitleSize)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return data, nil
+}
+
+// ApproveSubtitleDraft publishes a draft as the subtitle track of a language (Admin only)
+// An existing track of the language is replaced, the draft file is kept for reference
+func (u *MovieUsecase) ApproveSubtitleDraft(ctx context.Context, movieID, draftID int64, reviewerExtID string, req movies.ApproveSubtitleDraftRequest) (*movies.Subtitle, error) {
+	draft, err := u.findSubtitleDraft(ctx, movieID, draftID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != movies.SubtitleDraftPending {
+		return nil, apperr.Conflict("subtitle_draft_already_reviewed", draft.Status)
+	}
+
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	data, err := u.storageService.GetSubtitleDraft(ctx, draft.ObjectName, maxSubtitleSize)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	vtt, lastCue, err := subtitle.ToWebVTT(data, subtitle.FormatWebVTT)
+	if err != nil {
+		if errors.Is(err, subtitle.ErrNoCues) {
+			return nil, apperr.Validation("subtitle_has_no_cues", nil)
+		}
+		return nil, apperr.Validation("invalid_subtitle_file", err.Error())
+	}
+
+	// Claiming the draft first keeps two admins from publishing it twice
+	claimed, err := u.repo.ReviewSubtitleDraft(ctx, draft.ID, movies.SubtitleDraftApproved, reviewerExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !claimed {
+		return nil, apperr.Conflict("subtitle_draft_already_reviewed", nil)
+	}
+
+	return u.publishSubtitle(ctx, movie, req.Language, req.Label, req.IsDefault, vtt, lastCue)
+}
+
+// RejectSubtitleDraft discards a draft and its file (Admin only)
+func (u *MovieUsecase) RejectSubtitleDraft(ctx context.Context, movieID, draftID int64, reviewerExtID string) error {
+	draft, err := u.findSubtitleDraft(ctx, movieID, draftID)
+	if err != nil {
+		return err
+	}
+
+	rejected, err := u.repo.ReviewSubtitleDraft(ctx, draft.ID, movies.SubtitleDraftRejected, reviewerExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !rejected {
+		return apperr.Conflict("subtitle_draft_already_reviewed", draft.Status)
+	}
+
+	if err := u.storageService.DeleteSubtitleDraft(ctx, draft.ObjectName); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (u *MovieUsecase) findSubtitleDraft(ctx context.Context, movieID, draftID int64) (*movies.SubtitleDraft, error) {
+	draft, err := u.repo.FindSubtitleDraft(ctx, movieID, draftID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if draft == nil {
+		return nil, apperr.NotFound("subtitle_draft_not_found", nil)
+	}
+	return draft, nil
+}
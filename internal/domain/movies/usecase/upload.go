@@ -0,0 +1,425 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+const (
+	// uploadPartSize is the part size clients should use for chunked uploads (10 MB)
+	// Every part except the last one must be exactly this size
+	uploadPartSize = int64(10 << 20)
+	// maxUploadParts is the maximum number of parts allowed by S3/MinIO
+	maxUploadParts = 10000
+	// presignedUploadExpiry is how long a presigned PUT URL stays valid
+	presignedUploadExpiry = time.Hour
+)
+
+// InitUpload creates the movie record and starts a chunked upload session (Admin only)
+func (u *MovieUsecase) InitUpload(ctx context.Context, req movies.InitUploadRequest) (*movies.InitUploadResponse, error) {
+	totalParts := countUploadParts(req.FileSize)
+	if totalParts > maxUploadParts {
+		return nil, apperr.Validation("file_too_large", nil)
+	}
+
+	// 1. Build movie from metadata
+	movie, err := movieFromMetadata(req.MovieMetadataRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Create movie record and movie_video record with PENDING status
+	if err := u.createMovieWithPendingVideo(ctx, movie); err != nil {
+		return nil, err
+	}
+
+	// 3. Add genres if provided
+	if len(req.GenreIDs) > 0 {
+		if err := u.repo.AddMovieGenres(ctx, movie.ID, req.GenreIDs); err != nil {
+			// Log error but don't fail the upload
+			fmt.Printf("Warning: Failed to add genres to movie %d: %v\n", movie.ID, err)
+		}
+	}
+	u.enrichUploadedMovie(ctx, movie.ID, req.Enrich)
+
+	// 4. Start multipart upload in MinIO raw bucket
+	contentType := mime.TypeByExtension(filepath.Ext(req.FileName))
+	objectName, uploadID, err := u.storageService.NewRawVideoMultipartUpload(ctx, movie.ID, req.FileName, contentType)
+	if err != nil {
+		u.repo.UpdateMovieVideo(ctx, movie.ID, map[string]interface{}{
+			"upload_status": "FAILED",
+			"error_message": fmt.Sprintf("Failed to start upload: %v", err),
+		})
+		return nil, apperr.Internal(err)
+	}
+
+	// 5. Persist upload session so the client can resume it later
+	session := &movies.MovieUploadSession{
+		MovieID:    movie.ID,
+		UploadID:   uploadID,
+		ObjectName: objectName,
+		FileName:   req.FileName,
+		FileSize:   req.FileSize,
+		Status:     movies.UploadSessionInProgress,
+	}
+
+	if err := u.repo.CreateUploadSession(ctx, session); err != nil {
+		_ = u.storageService.AbortRawVideoMultipartUpload(ctx, objectName, uploadID)
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.InitUploadResponse{
+		UploadSessionID: session.ID,
+		MovieID:         movie.ID,
+		PartSize:        uploadPartSize,
+		TotalParts:      totalParts,
+	}, nil
+}
+
+// UploadPart streams a single part of a chunked upload into MinIO (Admin only)
+// Re-uploading an existing part number replaces it, which makes retries safe
+func (u *MovieUsecase) UploadPart(ctx context.Context, sessionID int64, partNumber int, data io.Reader, size int64) (*movies.UploadPartResponse, error) {
+	session, err := u.findActiveUploadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalParts := countUploadParts(session.FileSize)
+	if partNumber < 1 || partNumber > totalParts {
+		return nil, apperr.Validation("invalid_part_number", nil)
+	}
+
+	if size != expectedPartSize(session.FileSize, partNumber) {
+		return nil, apperr.Validation("invalid_part_size", nil)
+	}
+
+	part, err := u.storageService.UploadRawVideoPart(ctx, session.ObjectName, session.UploadID, partNumber, data, size)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.UploadPartResponse{
+		PartNumber: part.PartNumber,
+		ETag:       part.ETag,
+		Size:       size,
+	}, nil
+}
+
+// GetUploadSession returns the state of a chunked upload including uploaded parts (Admin only)
+func (u *MovieUsecase) GetUploadSession(ctx context.Context, sessionID int64) (*movies.UploadSessionResponse, error) {
+	session, err := u.repo.FindUploadSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if session == nil {
+		return nil, apperr.NotFound("upload_session_not_found", nil)
+	}
+
+	uploadedParts := []movies.UploadPartResponse{}
+	if session.Status == movies.UploadSessionInProgress {
+		parts, err := u.storageService.ListRawVideoParts(ctx, session.ObjectName, session.UploadID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		for _, part := range parts {
+			uploadedParts = append(uploadedParts, movies.UploadPartResponse{
+				PartNumber: part.PartNumber,
+				ETag:       part.ETag,
+				Size:       part.Size,
+			})
+		}
+	}
+
+	return &movies.UploadSessionResponse{
+		UploadSessionID: session.ID,
+		MovieID:         session.MovieID,
+		FileName:        session.FileName,
+		FileSize:        session.FileSize,
+		Status:          session.Status,
+		PartSize:        uploadPartSize,
+		TotalParts:      countUploadParts(session.FileSize),
+		UploadedParts:   uploadedParts,
+	}, nil
+}
+
+// CompleteUpload assembles the uploaded parts and enqueues transcoding (Admin only)
+func (u *MovieUsecase) CompleteUpload(ctx context.Context, adminExtID string, sessionID int64) (*movies.UploadMovieResponse, error) {
+	session, err := u.findActiveUploadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Make sure every part is present before assembling the object
+	parts, err := u.storageService.ListRawVideoParts(ctx, session.ObjectName, session.UploadID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	var uploadedSize int64
+	for _, part := range parts {
+		uploadedSize += part.Size
+	}
+	if len(parts) != countUploadParts(session.FileSize) || uploadedSize != session.FileSize {
+		return nil, apperr.Conflict("upload_incomplete", map[string]interface{}{
+			"uploaded_parts": len(parts),
+			"uploaded_size":  uploadedSize,
+		})
+	}
+
+	// 2. Complete multipart upload in MinIO
+	if err := u.storageService.CompleteRawVideoMultipartUpload(ctx, session.ObjectName, session.UploadID); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if err := u.repo.UpdateUploadSessionStatus(ctx, session.ID, movies.UploadSessionCompleted); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// 3. Update movie_video with raw_file_path and the uploader
+	if err := u.repo.UpdateMovieVideo(ctx, session.MovieID, map[string]interface{}{
+		"raw_file_path": session.ObjectName,
+		"uploaded_by":   adminExtID,
+	}); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// 4. Publish transcoding job to Redis queue
+	if err := u.queueService.PublishTranscodingJob(ctx, session.MovieID, session.ObjectName); err != nil {
+		u.repo.UpdateMovieVideo(ctx, session.MovieID, map[string]interface{}{
+			"upload_status": "FAILED",
+			"error_message": fmt.Sprintf("Failed to queue transcoding job: %v", err),
+		})
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.UploadMovieResponse{
+		MovieID: session.MovieID,
+		Message: "Movie accepted and is now processing",
+	}, nil
+}
+
+// AbortUpload aborts a chunked upload and discards the uploaded parts (Admin only)
+func (u *MovieUsecase) AbortUpload(ctx context.Context, sessionID int64) error {
+	session, err := u.findActiveUploadSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.storageService.AbortRawVideoMultipartUpload(ctx, session.ObjectName, session.UploadID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	if err := u.repo.UpdateUploadSessionStatus(ctx, session.ID, movies.UploadSessionAborted); err != nil {
+		return apperr.Internal(err)
+	}
+
+	u.repo.UpdateMovieVideo(ctx, session.MovieID, map[string]interface{}{
+		"upload_status": "FAILED",
+		"error_message": "Upload aborted",
+	})
+
+	return nil
+}
+
+// PresignUpload creates the movie record and returns a presigned PUT URL for the raw bucket (Admin only)
+// The movie_video record is only created once the upload is confirmed
+func (u *MovieUsecase) PresignUpload(ctx context.Context, req movies.PresignUploadRequest) (*movies.PresignUploadResponse, error) {
+	// 1. Build movie from metadata
+	movie, err := movieFromMetadata(req.MovieMetadataRequest)
+	if err != nil {
+		return nil, err
+	}
+	movie.CreatedAt = time.Now()
+	movie.UpdatedAt = time.Now()
+	if movie.Visibility == "" {
+		movie.Visibility = movies.VisibilityPublic
+	}
+	if movie.ProfileSet == "" {
+		movie.ProfileSet = movies.ProfileSetStandard
+	}
+
+	// 2. Create movie record
+	if err := u.repo.CreateMovie(ctx, movie); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// 3. Add genres if provided
+	if len(req.GenreIDs) > 0 {
+		if err := u.repo.AddMovieGenres(ctx, movie.ID, req.GenreIDs); err != nil {
+			// Log error but don't fail the upload
+			fmt.Printf("Warning: Failed to add genres to movie %d: %v\n", movie.ID, err)
+		}
+	}
+	u.enrichUploadedMovie(ctx, movie.ID, req.Enrich)
+
+	// 4. Presign PUT URL for the raw bucket
+	objectName, uploadURL, err := u.storageService.PresignRawVideoUpload(ctx, movie.ID, req.FileName, presignedUploadExpiry)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.PresignUploadResponse{
+		MovieID:    movie.ID,
+		ObjectName: objectName,
+		UploadURL:  uploadURL,
+		ExpiresAt:  time.Now().Add(presignedUploadExpiry),
+	}, nil
+}
+
+// ConfirmUpload creates the movie_video record and enqueues transcoding after a presigned upload (Admin only)
+func (u *MovieUsecase) ConfirmUpload(ctx context.Context, adminExtID string, movieID int64, req movies.ConfirmUploadRequest) (*movies.UploadMovieResponse, error) {
+	// 1. Check if movie exists and has no video yet
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	movieVideo, err := u.repo.FindMovieVideoByMovieID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movieVideo != nil {
+		return nil, apperr.Conflict("movie_video_already_exists", nil)
+	}
+
+	// 2. Object must belong to this movie and actually exist in the raw bucket
+	if !strings.HasPrefix(req.ObjectName, fmt.Sprintf("raw-videos/movie-%d.", movieID)) {
+		return nil, apperr.Validation("invalid_object_name", nil)
+	}
+
+	exists, err := u.storageService.RawVideoExists(ctx, req.ObjectName)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !exists {
+		return nil, apperr.Validation("raw_video_not_uploaded", nil)
+	}
+
+	// 3. Create movie_video record with PENDING status
+	movieVideo = &movies.MovieVideo{
+		MovieID:      movieID,
+		UploadStatus: "PENDING",
+		RawFilePath:  req.ObjectName,
+		UploadedAt:   time.Now(),
+		UploadedBy:   &adminExtID,
+	}
+
+	if err := u.repo.CreateMovieVideo(ctx, movieVideo); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// 4. Publish transcoding job to Redis queue
+	if err := u.queueService.PublishTranscodingJob(ctx, movieID, req.ObjectName); err != nil {
+		u.repo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
+			"upload_status": "FAILED",
+			"error_message": fmt.Sprintf("Failed to queue transcoding job: %v", err),
+		})
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.UploadMovieResponse{
+		MovieID: movieID,
+		Message: "Movie accepted and is now processing",
+	}, nil
+}
+
+// RetranscodeMovie enqueues a new transcode of the stored raw video (Admin only)
+// A READY movie keeps streaming its current output version until the new one is published
+func (u *MovieUsecase) RetranscodeMovie(ctx context.Context, movieID int64) (*movies.UploadMovieResponse, error) {
+	movieVideo, err := u.repo.FindMovieVideoByMovieID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movieVideo == nil || movieVideo.RawFilePath == "" {
+		return nil, apperr.NotFound("movie_video_not_found", nil)
+	}
+	if movieVideo.UploadStatus == "PENDING" || movieVideo.UploadStatus == "PROCESSING" {
+		return nil, apperr.Conflict("transcoding_in_progress", nil)
+	}
+
+	if movieVideo.UploadStatus == "FAILED" {
+		if err := u.repo.UpdateMovieVideo(ctx, movieID, map[string]interface{}{
+			"upload_status": "PENDING",
+			"error_message": nil,
+		}); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	}
+
+	if err := u.queueService.PublishTranscodingJob(ctx, movieID, movieVideo.RawFilePath); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &movies.UploadMovieResponse{
+		MovieID: movieID,
+		Message: "Movie queued for re-transcoding",
+	}, nil
+}
+
+// movieFromMetadata builds a movie entity from upload metadata
+func movieFromMetadata(req movies.MovieMetadataRequest) (*movies.Movie, error) {
+	var releaseDate time.Time
+	if req.ReleaseDate != "" {
+		var err error
+		releaseDate, err = time.Parse("2006-01-02", req.ReleaseDate)
+		if err != nil {
+			return nil, apperr.Validation("invalid_release_date_format", err)
+		}
+	}
+	if err := validateProfileSet(req.ProfileSet); err != nil {
+		return nil, err
+	}
+
+	return &movies.Movie{
+		Title:           req.Title,
+		Description:     req.Description,
+		ReleaseDate:     releaseDate,
+		Director:        req.Director,
+		PosterURL:       req.PosterURL,
+		TrailerURL:      req.TrailerURL,
+		DurationMinutes: req.DurationMinutes,
+		Price:           req.Price,
+		PurchasePrice:   req.PurchasePrice,
+		Visibility:      req.Visibility,
+		ProfileSet:      req.ProfileSet,
+	}, nil
+}
+
+// findActiveUploadSession loads an upload session and makes sure it still accepts parts
+func (u *MovieUsecase) findActiveUploadSession(ctx context.Context, sessionID int64) (*movies.MovieUploadSession, error) {
+	session, err := u.repo.FindUploadSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if session == nil {
+		return nil, apperr.NotFound("upload_session_not_found", nil)
+	}
+	if session.Status != movies.UploadSessionInProgress {
+		return nil, apperr.Conflict("upload_session_closed", nil)
+	}
+	return session, nil
+}
+
+// countUploadParts returns the number of parts needed for a file of the given size
+func countUploadParts(fileSize int64) int {
+	return int((fileSize + uploadPartSize - 1) / uploadPartSize)
+}
+
+// expectedPartSize returns the exact size a given part must have
+func expectedPartSize(fileSize int64, partNumber int) int64 {
+	if partNumber < countUploadParts(fileSize) {
+		return uploadPartSize
+	}
+	return fileSize - int64(partNumber-1)*uploadPartSize
+}
@@ -3,12 +3,17 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
-	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/martinmanurung/cinestream/internal/domain/movies"
-	"github.com/martinmanurung/cinestream/pkg/response"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/martinmanurung/cinestream/internal/platform/transcoding"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
 )
 
 type MovieRepository interface {
@@ -16,19 +21,75 @@ type MovieRepository interface {
 	CreateMovieVideo(ctx context.Context, movieVideo *movies.MovieVideo) error
 	FindMovieByID(ctx context.Context, movieID int64) (*movies.Movie, error)
 	FindMovieVideoByMovieID(ctx context.Context, movieID int64) (*movies.MovieVideo, error)
-	FindAllMovies(ctx context.Context, page, limit int, status string, genre string) ([]movies.MovieListResponse, int64, error)
+	FindAllMovies(ctx context.Context, page, limit int, filter movies.MovieFilter, sort movies.SortSpec) ([]movies.MovieListResponse, int64, error)
+	FindWatchlistedMovieIDs(ctx context.Context, userExtID string, movieIDs []int64) ([]int64, error)
 	FindMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error)
+	FindPublishedEditorialReviews(ctx context.Context, movieID int64) ([]movies.EditorialReviewSummary, error)
 	UpdateMovie(ctx context.Context, movieID int64, updates map[string]interface{}) error
 	UpdateMovieVideo(ctx context.Context, movieID int64, updates map[string]interface{}) error
 	DeleteMovie(ctx context.Context, movieID int64) error
+	SetMoviePublished(ctx context.Context, movieID int64, published bool) (bool, error)
+	// Soft launch methods
+	SetMovieBetaAccess(ctx context.Context, movieID int64, beta bool) (bool, error)
+	FindBetaViewers(ctx context.Context, movieID int64) ([]movies.MovieBetaViewer, error)
+	AddBetaViewers(ctx context.Context, viewers []movies.MovieBetaViewer) error
+	RemoveBetaViewer(ctx context.Context, movieID int64, userExtID string) (bool, error)
+	HasBetaAccess(ctx context.Context, movieID int64, userExtID string) (bool, error)
+	FindExistingUserExtIDs(ctx context.Context, userExtIDs []string) ([]string, error)
 	GetHLSURL(ctx context.Context, movieID int64) (string, error)
+	// Upload session methods
+	CreateUploadSession(ctx context.Context, session *movies.MovieUploadSession) error
+	FindUploadSessionByID(ctx context.Context, sessionID int64) (*movies.MovieUploadSession, error)
+	UpdateUploadSessionStatus(ctx context.Context, sessionID int64, status string) error
 	// Genre methods
 	GetAllGenres(ctx context.Context) ([]movies.Genre, error)
 	CreateGenre(ctx context.Context, genre *movies.Genre) error
 	DeleteGenre(ctx context.Context, genreID int) error
+	FindGenreByID(ctx context.Context, genreID int) (*movies.Genre, error)
+	UpdateGenre(ctx context.Context, genreID int, updates map[string]interface{}) error
 	AddMovieGenres(ctx context.Context, movieID int64, genreIDs []int) error
 	RemoveAllMovieGenres(ctx context.Context, movieID int64) error
 	GetMovieGenreIDs(ctx context.Context, movieID int64) ([]int, error)
+	// People methods
+	FindPersonByID(ctx context.Context, personID int64) (*movies.Person, error)
+	FindOrCreatePerson(ctx context.Context, name string) (*movies.Person, error)
+	UpdatePerson(ctx context.Context, personID int64, updates map[string]interface{}) error
+	UpdateDirectorName(ctx context.Context, personID int64, name string) error
+	// Subtitle methods
+	SaveSubtitle(ctx context.Context, subtitle *movies.Subtitle) error
+	FindSubtitlesByMovieID(ctx context.Context, movieID int64) ([]movies.Subtitle, error)
+	DeleteSubtitle(ctx context.Context, movieID int64, language string) (bool, error)
+	FindSubtitleDrafts(ctx context.Context, movieID int64) ([]movies.SubtitleDraft, error)
+	FindSubtitleDraft(ctx context.Context, movieID, draftID int64) (*movies.SubtitleDraft, error)
+	ReviewSubtitleDraft(ctx context.Context, draftID int64, status, reviewerExtID string) (bool, error)
+	// Content moderation of uploaded media
+	CreateMediaReview(ctx context.Context, review *movies.MediaReview) error
+	FindMediaReviews(ctx context.Context, status string, limit int) ([]movies.MediaReview, error)
+	FindMediaReview(ctx context.Context, reviewID int64) (*movies.MediaReview, error)
+	ReviewMediaReview(ctx context.Context, reviewID int64, status, reviewerExtID string) (bool, error)
+	// Alternate audio methods
+	CreateAlternateAudio(ctx context.Context, track *movies.AlternateAudio) error
+	FindAlternateAudio(ctx context.Context, trackID int64) (*movies.AlternateAudio, error)
+	FindAlternateAudioByMovieID(ctx context.Context, movieID int64) ([]movies.AlternateAudio, error)
+	UpdateAlternateAudio(ctx context.Context, trackID int64, updates map[string]interface{}) error
+	DeleteAlternateAudio(ctx context.Context, movieID, trackID int64) (bool, error)
+	// Licensing window methods
+	CreateLicenseWindow(ctx context.Context, window *movies.LicenseWindow) error
+	FindLicenseWindows(ctx context.Context, movieID int64) ([]movies.LicenseWindow, error)
+	HasOverlappingLicenseWindow(ctx context.Context, movieID int64, region string, startsAt, endsAt time.Time) (bool, error)
+	DeleteLicenseWindow(ctx context.Context, movieID, windowID int64) (bool, error)
+	FindExpiringLicenseWindows(ctx context.Context, from, to time.Time) ([]movies.ExpiringLicenseWindow, error)
+	// Catalog change log
+	RecordCatalogChanges(ctx context.Context, movieID int64, changeTypes ...string) error
+	GetCatalogStats(ctx context.Context, now time.Time) (*movies.CatalogStats, error)
+	FindSitemapMovies(ctx context.Context, now time.Time, limit int) ([]movies.SitemapMovie, error)
+	FindPopularMovies(ctx context.Context, since time.Time, limit int) ([]movies.MovieListResponse, error)
+	FindTrendingMovies(ctx context.Context, limit int) ([]movies.MovieListResponse, error)
+	// Recommendation methods
+	FindViewingHistory(ctx context.Context, userExtID string) ([]int64, error)
+	FindMovieGenreIDs(ctx context.Context, movieIDs []int64) (map[int64][]int, error)
+	FindCoViewedMovies(ctx context.Context, userExtID string, movieIDs []int64, limit int) (map[int64]int64, error)
+	FindRecommendableMovies(ctx context.Context, genreIDs []int, movieIDs []int64, exclude []int64, limit int) ([]movies.MovieListResponse, error)
 }
 
 type StorageService interface {
@@ -36,39 +97,81 @@ type StorageService interface {
 	GetHLSURL(ctx context.Context, movieID int64) (string, error)
 	DeleteRawVideo(ctx context.Context, objectName string) error
 	DeleteProcessedVideo(ctx context.Context, movieID int64) error
+	// Multipart (chunked) upload methods
+	NewRawVideoMultipartUpload(ctx context.Context, movieID int64, fileName, contentType string) (string, string, error)
+	UploadRawVideoPart(ctx context.Context, objectName, uploadID string, partNumber int, data io.Reader, size int64) (*storage.UploadedPart, error)
+	ListRawVideoParts(ctx context.Context, objectName, uploadID string) ([]storage.UploadedPart, error)
+	CompleteRawVideoMultipartUpload(ctx context.Context, objectName, uploadID string) error
+	AbortRawVideoMultipartUpload(ctx context.Context, objectName, uploadID string) error
+	// Presigned (direct-to-MinIO) upload methods
+	PresignRawVideoUpload(ctx context.Context, movieID int64, fileName string, expiry time.Duration) (string, string, error)
+	RawVideoExists(ctx context.Context, objectName string) (bool, error)
+	// Poster/trailer media methods
+	UploadMovieMedia(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64, kind string) (string, error)
+	PresignMediaURL(ctx context.Context, objectName string, expiry time.Duration) (string, error)
+	GetMediaObject(ctx context.Context, objectName string) (io.ReadCloser, *storage.MediaObject, error)
+	UploadHeldMovieMedia(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64, kind string) (string, error)
+	DeleteMediaObject(ctx context.Context, objectName string) error
+	// Subtitle methods
+	UploadSubtitle(ctx context.Context, movieID int64, language string, vtt, playlist []byte) (string, error)
+	DeleteSubtitle(ctx context.Context, movieID int64, language string) error
+	GetSubtitleDraft(ctx context.Context, objectName string, maxSize int64) ([]byte, error)
+	DeleteSubtitleDraft(ctx context.Context, objectName string) error
+	// Alternate audio methods
+	UploadAlternateAudio(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64) (string, error)
+	DeleteAlternateAudio(ctx context.Context, movieID, trackID int64, sourceObject string) error
 }
 
 type QueueService interface {
 	PublishTranscodingJob(ctx context.Context, movieID int64, rawFilePath string) error
+	PublishAudioJob(ctx context.Context, movieID, trackID int64) error
+	EnqueueTranscodingJob(ctx context.Context, job queue.TranscodingJob, next bool) (*queue.TranscodingJob, error)
+	PeekTranscodingJobs(ctx context.Context, limit int) (*queue.PendingJobs, error)
+	RemoveTranscodingJob(ctx context.Context, jobID string) (bool, error)
 }
 
 type MovieUsecase struct {
 	repo           MovieRepository
 	storageService StorageService
 	queueService   QueueService
+	media          MediaOptions
+	metadata       MetadataProvider // nil when enrichment is disabled
+	moderator      ContentModerator // nil when uploads are published unscanned
+	siteURL        string           // public site the default canonical URLs point to, see seo.go
+
+	// Catalog stats served from memory, see stats.go
+	statsMu sync.Mutex
+	stats   *movies.CatalogStats
 }
 
-func NewMovieUsecase(repo MovieRepository, storageService StorageService, queueService QueueService) *MovieUsecase {
+func NewMovieUsecase(repo MovieRepository, storageService StorageService, queueService QueueService, media MediaOptions, metadata MetadataProvider, moderator ContentModerator, siteURL string) *MovieUsecase {
 	return &MovieUsecase{
 		repo:           repo,
 		storageService: storageService,
 		queueService:   queueService,
+		media:          media,
+		metadata:       metadata,
+		moderator:      moderator,
+		siteURL:        strings.TrimRight(siteURL, "/"),
 	}
 }
 
 // UploadMovie handles the complete movie upload process (Admin only)
-func (u *MovieUsecase) UploadMovie(ctx context.Context, req movies.UploadMovieRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.UploadMovieResponse, error) {
+func (u *MovieUsecase) UploadMovie(ctx context.Context, adminExtID string, req movies.UploadMovieRequest, file multipart.File, fileHeader *multipart.FileHeader) (*movies.UploadMovieResponse, error) {
 	// 1. Parse release date
 	var releaseDate time.Time
 	var err error
 	if req.ReleaseDate != "" {
 		releaseDate, err = time.Parse("2006-01-02", req.ReleaseDate)
 		if err != nil {
-			return nil, response.NewError(http.StatusBadRequest, "invalid_release_date_format", err)
+			return nil, apperr.Validation("invalid_release_date_format", err)
 		}
 	}
+	if err := validateProfileSet(req.ProfileSet); err != nil {
+		return nil, err
+	}
 
-	// 2. Create movie record in database
+	// 2-3. Create movie record and movie_video record with PENDING status
 	movie := &movies.Movie{
 		Title:           req.Title,
 		Description:     req.Description,
@@ -78,23 +181,13 @@ func (u *MovieUsecase) UploadMovie(ctx context.Context, req movies.UploadMovieRe
 		TrailerURL:      req.TrailerURL,
 		DurationMinutes: req.DurationMinutes,
 		Price:           req.Price,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		PurchasePrice:   req.PurchasePrice,
+		Visibility:      req.Visibility,
+		ProfileSet:      req.ProfileSet,
 	}
 
-	if err := u.repo.CreateMovie(ctx, movie); err != nil {
-		return nil, response.InternalServerError(err)
-	}
-
-	// 3. Create movie_video record with PENDING status
-	movieVideo := &movies.MovieVideo{
-		MovieID:      movie.ID,
-		UploadStatus: "PENDING",
-		UploadedAt:   time.Now(),
-	}
-
-	if err := u.repo.CreateMovieVideo(ctx, movieVideo); err != nil {
-		return nil, response.InternalServerError(err)
+	if err := u.createMovieWithPendingVideo(ctx, movie); err != nil {
+		return nil, err
 	}
 
 	// 4. Upload video file to MinIO raw bucket
@@ -105,14 +198,15 @@ func (u *MovieUsecase) UploadMovie(ctx context.Context, req movies.UploadMovieRe
 			"upload_status": "FAILED",
 			"error_message": fmt.Sprintf("Failed to upload file: %v", err),
 		})
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
-	// 5. Update movie_video with raw_file_path
+	// 5. Update movie_video with raw_file_path and the uploader
 	if err := u.repo.UpdateMovieVideo(ctx, movie.ID, map[string]interface{}{
 		"raw_file_path": rawFilePath,
+		"uploaded_by":   adminExtID,
 	}); err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	// 6. Publish transcoding job to Redis queue
@@ -122,7 +216,7 @@ func (u *MovieUsecase) UploadMovie(ctx context.Context, req movies.UploadMovieRe
 			"upload_status": "FAILED",
 			"error_message": fmt.Sprintf("Failed to queue transcoding job: %v", err),
 		})
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	// 7. Add genres if provided
@@ -132,6 +226,7 @@ func (u *MovieUsecase) UploadMovie(ctx context.Context, req movies.UploadMovieRe
 			fmt.Printf("Warning: Failed to add genres to movie %d: %v\n", movie.ID, err)
 		}
 	}
+	u.enrichUploadedMovie(ctx, movie.ID, req.Enrich)
 
 	// 8. Return success response
 	return &movies.UploadMovieResponse{
@@ -140,8 +235,42 @@ func (u *MovieUsecase) UploadMovie(ctx context.Context, req movies.UploadMovieRe
 	}, nil
 }
 
+// createMovieWithPendingVideo creates the movie record and its movie_video record with PENDING status
+func (u *MovieUsecase) createMovieWithPendingVideo(ctx context.Context, movie *movies.Movie) error {
+	movie.CreatedAt = time.Now()
+	movie.UpdatedAt = time.Now()
+	if movie.Visibility == "" {
+		movie.Visibility = movies.VisibilityPublic
+	}
+	if movie.ProfileSet == "" {
+		movie.ProfileSet = movies.ProfileSetStandard
+	}
+
+	directorID, err := u.resolveDirectorID(ctx, movie.Director)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	movie.DirectorID = directorID
+
+	if err := u.repo.CreateMovie(ctx, movie); err != nil {
+		return apperr.Internal(err)
+	}
+
+	movieVideo := &movies.MovieVideo{
+		MovieID:      movie.ID,
+		UploadStatus: "PENDING",
+		UploadedAt:   time.Now(),
+	}
+
+	if err := u.repo.CreateMovieVideo(ctx, movieVideo); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
 // GetMovieList returns paginated list of movies (Public - only READY movies)
-func (u *MovieUsecase) GetMovieList(ctx context.Context, page, limit int, genre string) (*movies.MovieListWithPagination, error) {
+func (u *MovieUsecase) GetMovieList(ctx context.Context, page, limit int, genre, sort, accessibility string) (*movies.MovieListWithPagination, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -149,10 +278,26 @@ func (u *MovieUsecase) GetMovieList(ctx context.Context, page, limit int, genre
 		limit = 12
 	}
 
+	sortSpec, ok := movies.ParseSort(sort)
+	if !ok {
+		return nil, apperr.Validation("invalid_sort", "sort must be one of: popular, newest, price_asc, price_desc, title, duration, views")
+	}
+
+	conditions, ok := movies.ParseAccessibility(accessibility)
+	if !ok {
+		return nil, apperr.Validation("invalid_accessibility", "accessibility must be a comma separated list of: cc, ad, no_flashing")
+	}
+
 	// For public, only show READY movies
-	movieList, totalCount, err := u.repo.FindAllMovies(ctx, page, limit, "READY", genre)
+	filter := movies.MovieFilter{Statuses: []string{movies.UploadStatusReady}, Genre: genre, PublicOnly: true, Viewer: betaViewer(ctx), Accessibility: conditions}
+	movieList, totalCount, err := u.repo.FindAllMovies(ctx, page, limit, filter, sortSpec)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
+	}
+	u.resolveListMediaURLs(ctx, movieList)
+	markListAvailable(movieList)
+	if err := u.markListInWatchlist(ctx, movieList); err != nil {
+		return nil, apperr.Internal(err)
 	}
 
 	totalPages := int(totalCount) / limit
@@ -173,32 +318,83 @@ func (u *MovieUsecase) GetMovieList(ctx context.Context, page, limit int, genre
 
 // GetMovieDetail returns detailed information about a movie (Public)
 func (u *MovieUsecase) GetMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error) {
+	movieDetail, err := u.publicMovieDetail(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseDate, _ := time.Parse("2006-01-02", movieDetail.ReleaseDate)
+	movieDetail.Available = movies.IsAvailable(movieDetail.UploadStatus, movieDetail.Visibility, movieDetail.IsPublished, movieDetail.TakenDownAt, releaseDate, time.Now())
+
+	movieDetail.PosterURL = u.resolveMediaURL(ctx, movieDetail.ID, movieDetail.Visibility, movies.MediaKindPoster, movieDetail.PosterURL)
+	movieDetail.TrailerURL = u.resolveMediaURL(ctx, movieDetail.ID, movieDetail.Visibility, movies.MediaKindTrailer, movieDetail.TrailerURL)
+
+	if err := u.markDetailInWatchlist(ctx, movieDetail); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	reviews, err := u.repo.FindPublishedEditorialReviews(ctx, movieDetail.ID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if reviews == nil {
+		reviews = []movies.EditorialReviewSummary{}
+	}
+	movieDetail.EditorialReviews = reviews
+
+	return movieDetail, nil
+}
+
+// publicMovieDetail returns a movie the viewer may see in the catalog, others are not found
+func (u *MovieUsecase) publicMovieDetail(ctx context.Context, movieID int64) (*movies.MovieDetailResponse, error) {
 	movieDetail, err := u.repo.FindMovieDetail(ctx, movieID)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	if movieDetail == nil {
-		return nil, response.NewError(http.StatusNotFound, "movie_not_found", nil)
+		return nil, apperr.NotFound("movie_not_found", nil)
 	}
 
-	// Only show READY movies to public
-	if movieDetail.UploadStatus != "READY" {
-		return nil, response.NewError(http.StatusNotFound, "movie_not_available", nil)
+	// Only show READY movies to public, taken down titles are hidden pending investigation
+	if movieDetail.UploadStatus != "READY" || !movieDetail.IsPublished || movieDetail.TakenDownAt != nil {
+		return nil, apperr.NotFound("movie_not_available", nil)
 	}
 
+	// Soft-launched titles look missing to everyone but testers and their allowlist
+	if movieDetail.BetaAccess {
+		allowed, err := u.canSeeBetaMovie(ctx, movieDetail.ID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if !allowed {
+			return nil, apperr.NotFound("movie_not_available", nil)
+		}
+	}
 	return movieDetail, nil
 }
 
+// markListAvailable computes the available flag of a catalog page
+func markListAvailable(movieList []movies.MovieListResponse) {
+	now := time.Now()
+	for i := range movieList {
+		var releaseDate time.Time
+		if movieList[i].ReleaseDate != nil {
+			releaseDate = *movieList[i].ReleaseDate
+		}
+		movieList[i].Available = movies.IsAvailable(movieList[i].UploadStatus, movieList[i].Visibility, movieList[i].IsPublished, movieList[i].TakenDownAt, releaseDate, now)
+	}
+}
+
 // UpdateMovie updates movie metadata (Admin only)
 func (u *MovieUsecase) UpdateMovie(ctx context.Context, movieID int64, req movies.UpdateMovieRequest) error {
 	// Check if movie exists
 	movie, err := u.repo.FindMovieByID(ctx, movieID)
 	if err != nil {
-		return response.InternalServerError(err)
+		return apperr.Internal(err)
 	}
 	if movie == nil {
-		return response.NewError(http.StatusNotFound, "movie_not_found", nil)
+		return apperr.NotFound("movie_not_found", nil)
 	}
 
 	// Build updates map
@@ -213,12 +409,17 @@ func (u *MovieUsecase) UpdateMovie(ctx context.Context, movieID int64, req movie
 	if req.ReleaseDate != "" {
 		releaseDate, err := time.Parse("2006-01-02", req.ReleaseDate)
 		if err != nil {
-			return response.NewError(http.StatusBadRequest, "invalid_release_date_format", err)
+			return apperr.Validation("invalid_release_date_format", err)
 		}
 		updates["release_date"] = releaseDate
 	}
 	if req.Director != "" {
+		directorID, err := u.resolveDirectorID(ctx, req.Director)
+		if err != nil {
+			return apperr.Internal(err)
+		}
 		updates["director"] = req.Director
+		updates["director_id"] = directorID
 	}
 	if req.PosterURL != "" {
 		updates["poster_url"] = req.PosterURL
@@ -232,15 +433,48 @@ func (u *MovieUsecase) UpdateMovie(ctx context.Context, movieID int64, req movie
 	if req.Price >= 0 {
 		updates["price"] = req.Price
 	}
+	if req.DisablePurchase {
+		updates["purchase_price"] = nil
+	} else if req.PurchasePrice != nil {
+		updates["purchase_price"] = *req.PurchasePrice
+	}
+	if req.ResetPaymentExpiry {
+		updates["payment_expiry_minutes"] = nil
+	} else if req.PaymentExpiry != nil {
+		updates["payment_expiry_minutes"] = *req.PaymentExpiry
+	}
+	if req.ResetRentalDeviceLimit {
+		updates["rental_device_limit"] = nil
+	} else if req.RentalDeviceLimit != nil {
+		updates["rental_device_limit"] = *req.RentalDeviceLimit
+	}
+	if req.Visibility != "" {
+		updates["visibility"] = req.Visibility
+	}
+	if req.ProfileSet != "" {
+		if err := validateProfileSet(req.ProfileSet); err != nil {
+			return err
+		}
+		updates["profile_set"] = req.ProfileSet
+	}
+	if req.ClosedCaptions != nil {
+		updates["closed_captions"] = *req.ClosedCaptions
+	}
+	if req.AudioDescription != nil {
+		updates["audio_description"] = *req.AudioDescription
+	}
+	if req.FlashingContentWarning != nil {
+		updates["flashing_content_warning"] = *req.FlashingContentWarning
+	}
 
 	if len(updates) == 0 {
-		return response.NewError(http.StatusBadRequest, "no_fields_to_update", nil)
+		return apperr.Validation("no_fields_to_update", nil)
 	}
 
 	updates["updated_at"] = time.Now()
 
 	if err := u.repo.UpdateMovie(ctx, movieID, updates); err != nil {
-		return response.InternalServerError(err)
+		return apperr.Internal(err)
 	}
 
 	// Update genres if provided
@@ -255,45 +489,87 @@ func (u *MovieUsecase) UpdateMovie(ctx context.Context, movieID int64, req movie
 		}
 	}
 
+	u.recordCatalogChanges(ctx, movieID, updateChangeTypes(updates, len(req.GenreIDs) > 0)...)
+
 	return nil
 }
 
-// DeleteMovie deletes a movie and its associated files (Admin only)
-func (u *MovieUsecase) DeleteMovie(ctx context.Context, movieID int64) error {
-	// Check if movie exists
-	movie, err := u.repo.FindMovieByID(ctx, movieID)
-	if err != nil {
-		return response.InternalServerError(err)
+// updateChangeTypes maps the updated columns to catalog change types
+func updateChangeTypes(updates map[string]interface{}, genresChanged bool) []string {
+	var changeTypes []string
+	other := genresChanged
+	for column := range updates {
+		switch column {
+		case "price", "purchase_price":
+			changeTypes = appendChangeType(changeTypes, movies.CatalogChangePrice)
+		case "visibility":
+			changeTypes = appendChangeType(changeTypes, movies.CatalogChangeVisibility)
+		case "poster_url":
+			changeTypes = appendChangeType(changeTypes, movies.CatalogChangePoster)
+		case "trailer_url":
+			changeTypes = appendChangeType(changeTypes, movies.CatalogChangeTrailer)
+		case "updated_at", "payment_expiry_minutes", "rental_device_limit", "profile_set":
+			// not part of catalog responses
+		default:
+			other = true
+		}
 	}
-	if movie == nil {
-		return response.NewError(http.StatusNotFound, "movie_not_found", nil)
+	if other {
+		changeTypes = appendChangeType(changeTypes, movies.CatalogChangeUpdated)
 	}
+	return changeTypes
+}
 
-	// Get movie_video to delete files
-	movieVideo, err := u.repo.FindMovieVideoByMovieID(ctx, movieID)
-	if err != nil {
-		return response.InternalServerError(err)
+func appendChangeType(changeTypes []string, changeType string) []string {
+	for _, existing := range changeTypes {
+		if existing == changeType {
+			return changeTypes
+		}
 	}
+	return append(changeTypes, changeType)
+}
 
-	// Delete raw video file from MinIO
-	if movieVideo != nil && movieVideo.RawFilePath != "" {
-		_ = u.storageService.DeleteRawVideo(ctx, movieVideo.RawFilePath)
+// recordCatalogChanges adds the edit to the change log the worker purges caches from.
+// A failure is only logged, the cached responses then expire by their TTL.
+func (u *MovieUsecase) recordCatalogChanges(ctx context.Context, movieID int64, changeTypes ...string) {
+	if err := u.repo.RecordCatalogChanges(ctx, movieID, changeTypes...); err != nil {
+		fmt.Printf("Warning: Failed to record catalog change for movie %d: %v\n", movieID, err)
 	}
+	u.invalidateCatalogStats()
+}
 
-	// Delete processed video files from MinIO
-	if movieVideo != nil {
-		_ = u.storageService.DeleteProcessedVideo(ctx, movieID)
+// validateProfileSet accepts the built-in and configured profile sets, empty means the default
+func validateProfileSet(name string) error {
+	if name != "" && !transcoding.HasProfileSet(name) {
+		return apperr.Validation("invalid_profile_set", nil)
+	}
+	return nil
+}
+
+// DeleteMovie soft deletes a movie (Admin only)
+// Files are kept, users who paid for the movie can still stream it until their access ends.
+func (u *MovieUsecase) DeleteMovie(ctx context.Context, movieID int64) error {
+	// Check if movie exists
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if movie == nil {
+		return apperr.NotFound("movie_not_found", nil)
 	}
 
-	// Delete movie from database (CASCADE will delete movie_video)
 	if err := u.repo.DeleteMovie(ctx, movieID); err != nil {
-		return response.InternalServerError(err)
+		return apperr.Internal(err)
 	}
 
+	// The change log has no foreign key, the entry outlives the movie
+	u.recordCatalogChanges(ctx, movieID, movies.CatalogChangeDeleted)
+
 	return nil
 }
 
-// GetAllMoviesAdmin returns all movies with any status (Admin only)
+// GetAllMoviesAdmin returns the movies of the given upload statuses, e.g. "PENDING,FAILED",
+// an empty status lists movies of every status (Admin only)
 func (u *MovieUsecase) GetAllMoviesAdmin(ctx context.Context, page, limit int, status string) (*movies.MovieListWithPagination, error) {
 	if page < 1 {
 		page = 1
@@ -302,11 +578,19 @@ func (u *MovieUsecase) GetAllMoviesAdmin(ctx context.Context, page, limit int, s
 		limit = 12
 	}
 
-	// Admin can see all statuses
-	movieList, totalCount, err := u.repo.FindAllMovies(ctx, page, limit, status, "")
+	statuses, ok := movies.ParseUploadStatuses(status)
+	if !ok {
+		return nil, apperr.Validation("invalid_status", "status must be a comma separated list of: PENDING, PROCESSING, READY, FAILED")
+	}
+	filter := movies.MovieFilter{Statuses: statuses, AllStatuses: len(statuses) == 0}
+
+	sortSpec, _ := movies.ParseSort(movies.SortNewest)
+	movieList, totalCount, err := u.repo.FindAllMovies(ctx, page, limit, filter, sortSpec)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
+	u.resolveListMediaURLs(ctx, movieList)
+	markListAvailable(movieList)
 
 	totalPages := int(totalCount) / limit
 	if int(totalCount)%limit != 0 {
@@ -324,13 +608,42 @@ func (u *MovieUsecase) GetAllMoviesAdmin(ctx context.Context, page, limit int, s
 	}, nil
 }
 
+// GetMovieAdmin returns a movie of any status with its video and genre IDs (Admin only)
+func (u *MovieUsecase) GetMovieAdmin(ctx context.Context, movieID int64) (*movies.AdminMovieDetailResponse, error) {
+	movie, err := u.repo.FindMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if movie == nil {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	movieVideo, err := u.repo.FindMovieVideoByMovieID(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	genreIDs, err := u.repo.GetMovieGenreIDs(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if genreIDs == nil {
+		genreIDs = []int{}
+	}
+
+	movie.PosterURL = u.resolveMediaURL(ctx, movie.ID, movie.Visibility, movies.MediaKindPoster, movie.PosterURL)
+	movie.TrailerURL = u.resolveMediaURL(ctx, movie.ID, movie.Visibility, movies.MediaKindTrailer, movie.TrailerURL)
+
+	return &movies.AdminMovieDetailResponse{Movie: *movie, Video: movieVideo, GenreIDs: genreIDs}, nil
+}
+
 // Genre management methods
 
 // GetAllGenres returns all available genres
 func (u *MovieUsecase) GetAllGenres(ctx context.Context) (*movies.GenreListResponse, error) {
 	genres, err := u.repo.GetAllGenres(ctx)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	return &movies.GenreListResponse{
@@ -341,11 +654,13 @@ func (u *MovieUsecase) GetAllGenres(ctx context.Context) (*movies.GenreListRespo
 // CreateGenre creates a new genre (Admin only)
 func (u *MovieUsecase) CreateGenre(ctx context.Context, req movies.GenreRequest) (*movies.Genre, error) {
 	genre := &movies.Genre{
-		Name: req.Name,
+		Name:        req.Name,
+		Description: req.Description,
+		ArtworkURL:  req.ArtworkURL,
 	}
 
 	if err := u.repo.CreateGenre(ctx, genre); err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	return genre, nil
@@ -354,8 +669,43 @@ func (u *MovieUsecase) CreateGenre(ctx context.Context, req movies.GenreRequest)
 // DeleteGenre deletes a genre (Admin only)
 func (u *MovieUsecase) DeleteGenre(ctx context.Context, genreID int) error {
 	if err := u.repo.DeleteGenre(ctx, genreID); err != nil {
-		return response.InternalServerError(err)
+		return apperr.Internal(err)
 	}
 
 	return nil
 }
+
+// UpdateGenre updates genre name, description and artwork (Admin only)
+func (u *MovieUsecase) UpdateGenre(ctx context.Context, genreID int, req movies.UpdateGenreRequest) (*movies.Genre, error) {
+	genre, err := u.repo.FindGenreByID(ctx, genreID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if genre == nil {
+		return nil, apperr.NotFound("genre_not_found", nil)
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ArtworkURL != nil {
+		updates["artwork_url"] = *req.ArtworkURL
+	}
+	if len(updates) == 0 {
+		return genre, nil
+	}
+
+	if err := u.repo.UpdateGenre(ctx, genreID, updates); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	genre, err = u.repo.FindGenreByID(ctx, genreID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return genre, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// filterRecorder records the filter of the movie listing, every other repository method panics
+type filterRecorder struct {
+	MovieRepository
+	filter *movies.MovieFilter
+}
+
+func (r *filterRecorder) FindAllMovies(ctx context.Context, page, limit int, filter movies.MovieFilter, sort movies.SortSpec) ([]movies.MovieListResponse, int64, error) {
+	r.filter = &filter
+	return nil, 0, nil
+}
+
+func TestGetAllMoviesAdminStatusFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		want    *movies.MovieFilter
+		wantErr bool
+	}{
+		{
+			name:   "empty status lists all",
+			status: "",
+			want:   &movies.MovieFilter{AllStatuses: true},
+		},
+		{
+			name:   "single status",
+			status: "failed",
+			want:   &movies.MovieFilter{Statuses: []string{movies.UploadStatusFailed}},
+		},
+		{
+			name:   "multiple statuses",
+			status: "PENDING,PROCESSING",
+			want:   &movies.MovieFilter{Statuses: []string{movies.UploadStatusPending, movies.UploadStatusProcessing}},
+		},
+		{
+			name:    "unknown status is rejected",
+			status:  "READY,DELETED",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &filterRecorder{}
+			u := NewMovieUsecase(repo, nil, nil, MediaOptions{}, nil, nil, "")
+
+			_, err := u.GetAllMoviesAdmin(context.Background(), 1, 12, tt.status)
+			if tt.wantErr {
+				if !errors.Is(err, apperr.Validation("invalid_status", nil)) {
+					t.Fatalf("expected invalid_status error, got %v", err)
+				}
+				if repo.filter != nil {
+					t.Errorf("repository must not be queried for an invalid status")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(repo.filter, tt.want) {
+				t.Errorf("filter = %+v, want %+v", repo.filter, tt.want)
+			}
+		})
+	}
+}
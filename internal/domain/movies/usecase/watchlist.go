@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/martinmanurung/cinestream/internal/domain/movies"
+	"github.com/martinmanurung/cinestream/pkg/jwt"
+)
+
+// markListInWatchlist sets in_watchlist on each movie when the request is authenticated
+func (u *MovieUsecase) markListInWatchlist(ctx context.Context, movieList []movies.MovieListResponse) error {
+	userExtID, err := jwt.GetUserExtIDFromStdContext(ctx)
+	if err != nil {
+		return nil // anonymous request, no flag
+	}
+
+	ids := make([]int64, len(movieList))
+	for i := range movieList {
+		ids[i] = movieList[i].ID
+	}
+
+	inWatchlist, err := u.watchlistedSet(ctx, userExtID, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range movieList {
+		flag := inWatchlist[movieList[i].ID]
+		movieList[i].InWatchlist = &flag
+	}
+	return nil
+}
+
+// markDetailInWatchlist sets in_watchlist on a movie detail when the request is authenticated
+func (u *MovieUsecase) markDetailInWatchlist(ctx context.Context, movieDetail *movies.MovieDetailResponse) error {
+	userExtID, err := jwt.GetUserExtIDFromStdContext(ctx)
+	if err != nil {
+		return nil
+	}
+
+	inWatchlist, err := u.watchlistedSet(ctx, userExtID, []int64{movieDetail.ID})
+	if err != nil {
+		return err
+	}
+
+	flag := inWatchlist[movieDetail.ID]
+	movieDetail.InWatchlist = &flag
+	return nil
+}
+
+func (u *MovieUsecase) watchlistedSet(ctx context.Context, userExtID string, movieIDs []int64) (map[int64]bool, error) {
+	ids, err := u.repo.FindWatchlistedMovieIDs(ctx, userExtID, movieIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
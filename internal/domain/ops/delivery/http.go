@@ -0,0 +1,88 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/platform/backup"
+	"github.com/martinmanurung/cinestream/internal/platform/metrics"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/internal/platform/retention"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type OpsUsecase interface {
+	GetSummary(ctx context.Context, window string, top int) (*metrics.Summary, error)
+	GetRetentionReports(ctx context.Context, target string, limit int) ([]retention.Report, error)
+	GetQueues(ctx context.Context) ([]queue.NamedQueueState, error)
+	GetBackups(ctx context.Context, limit int) (*backup.Status, error)
+}
+
+type OpsHandler struct {
+	ctx     context.Context
+	usecase OpsUsecase
+}
+
+func NewOpsHandler(ctx context.Context, usecase OpsUsecase) *OpsHandler {
+	return &OpsHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// GetSummary returns failed logins by IP, webhook signature failures, error hotspots and recent panics (Admin only)
+// GET /api/v1/admin/ops/summary?window=24h&top=10
+func (h *OpsHandler) GetSummary(c echo.Context) error {
+	ctx := h.ctx
+
+	top, _ := strconv.Atoi(c.QueryParam("top"))
+
+	result, err := h.usecase.GetSummary(ctx, c.QueryParam("window"), top)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetRetentionReports lists the latest data retention runs of the worker, dry runs included (Admin only)
+// GET /api/v1/admin/ops/retention?target=orders&limit=50
+func (h *OpsHandler) GetRetentionReports(c echo.Context) error {
+	ctx := h.ctx
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetRetentionReports(ctx, c.QueryParam("target"), limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetBackups returns the latest successful backup and the latest backup runs of the worker, failed ones included (Admin only)
+// GET /api/v1/admin/ops/backups?limit=20
+func (h *OpsHandler) GetBackups(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetBackups(h.ctx, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetQueues returns the backlog of the named job queues: jobs ready to run, delayed or waiting for a retry,
+// and jobs that ran out of attempts (Admin only)
+// GET /api/v1/admin/ops/queues
+func (h *OpsHandler) GetQueues(c echo.Context) error {
+	result, err := h.usecase.GetQueues(h.ctx)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
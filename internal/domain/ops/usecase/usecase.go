@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/backup"
+	"github.com/martinmanurung/cinestream/internal/platform/metrics"
+	"github.com/martinmanurung/cinestream/internal/platform/queue"
+	"github.com/martinmanurung/cinestream/internal/platform/retention"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+const (
+	defaultWindow = 24 * time.Hour
+	maxWindow     = 7 * 24 * time.Hour
+	defaultTop    = 10
+	maxTop        = 100
+
+	defaultRetentionReports = 50
+	maxRetentionReports     = 500
+
+	defaultBackupRuns = 20
+	maxBackupRuns     = 200
+)
+
+type MetricsReader interface {
+	Summary(ctx context.Context, window time.Duration, top int) (*metrics.Summary, error)
+}
+
+type RetentionReportReader interface {
+	FindReports(ctx context.Context, target string, limit int) ([]retention.Report, error)
+}
+
+// QueueReader reads the backlog of the named job queues
+type QueueReader interface {
+	GetNamedQueueStates(ctx context.Context) ([]queue.NamedQueueState, error)
+}
+
+// BackupRunReader reads the runs of the worker's backups
+type BackupRunReader interface {
+	FindRuns(ctx context.Context, limit int) ([]backup.Run, error)
+	FindLastSuccess(ctx context.Context) (*backup.Run, error)
+}
+
+type OpsUsecase struct {
+	metrics   MetricsReader
+	retention RetentionReportReader
+	queues    QueueReader
+	backups   BackupRunReader
+}
+
+func NewOpsUsecase(metrics MetricsReader, retention RetentionReportReader, queues QueueReader, backups BackupRunReader) *OpsUsecase {
+	return &OpsUsecase{metrics: metrics, retention: retention, queues: queues, backups: backups}
+}
+
+// GetSummary returns the security/ops summary of the last window (Admin only)
+// window is a Go duration like "1h" or "24h", empty means 24 hours
+func (u *OpsUsecase) GetSummary(ctx context.Context, window string, top int) (*metrics.Summary, error) {
+	duration := defaultWindow
+	if window != "" {
+		parsed, err := time.ParseDuration(window)
+		if err != nil || parsed < time.Hour || parsed > maxWindow {
+			return nil, apperr.Validation("invalid_window", "window must be a duration between 1h and 168h")
+		}
+		duration = parsed
+	}
+
+	if top < 1 || top > maxTop {
+		top = defaultTop
+	}
+
+	summary, err := u.metrics.Summary(ctx, duration, top)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return summary, nil
+}
+
+// GetRetentionReports returns the latest retention runs, newest first (Admin only)
+// target narrows them to one built-in target, dry runs show what a rule would change
+func (u *OpsUsecase) GetRetentionReports(ctx context.Context, target string, limit int) ([]retention.Report, error) {
+	if target != "" && !slices.Contains(retention.Targets(), target) {
+		return nil, apperr.Validation("invalid_target", "target must be one of: "+strings.Join(retention.Targets(), ", "))
+	}
+	if limit < 1 || limit > maxRetentionReports {
+		limit = defaultRetentionReports
+	}
+
+	reports, err := u.retention.FindReports(ctx, target, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if reports == nil {
+		reports = []retention.Report{}
+	}
+	return reports, nil
+}
+
+// GetBackups returns the latest successful backup and the latest runs, newest first (Admin only)
+func (u *OpsUsecase) GetBackups(ctx context.Context, limit int) (*backup.Status, error) {
+	if limit < 1 || limit > maxBackupRuns {
+		limit = defaultBackupRuns
+	}
+
+	lastSuccess, err := u.backups.FindLastSuccess(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	runs, err := u.backups.FindRuns(ctx, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if runs == nil {
+		runs = []backup.Run{}
+	}
+	return &backup.Status{LastSuccess: lastSuccess, Runs: runs}, nil
+}
+
+// GetQueues returns the pending, scheduled and dead jobs of every named queue (Admin only)
+func (u *OpsUsecase) GetQueues(ctx context.Context) ([]queue.NamedQueueState, error) {
+	states, err := u.queues.GetNamedQueueStates(ctx)
+	if err != nil {
+		return nil, apperr.Unavailable("queues_temporarily_unavailable", nil)
+	}
+	return states, nil
+}
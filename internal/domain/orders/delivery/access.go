@@ -0,0 +1,75 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// GrantAccess handles POST /api/v1/admin/access
+// Gives a user complimentary access to a movie until expires_at (Admin only)
+func (h *OrderHandler) GrantAccess(c echo.Context) error {
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	var req orders.GrantAccessRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	result, err := h.orderUsecase.GrantAccess(adminExtID, &req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Access granted", result)
+}
+
+// GetAccess handles GET /api/v1/admin/access/:id
+// Returns movie access with the log of who granted or revoked it (Admin only)
+func (h *OrderHandler) GetAccess(c echo.Context) error {
+	accessID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid access ID", nil)
+	}
+
+	result, err := h.orderUsecase.GetAccess(accessID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Access retrieved successfully", result)
+}
+
+// RevokeAccess handles DELETE /api/v1/admin/access/:id
+// Ends movie access before it expires, e.g. after a chargeback, the reason is optional (Admin only)
+func (h *OrderHandler) RevokeAccess(c echo.Context) error {
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	accessID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid access ID", nil)
+	}
+
+	var req orders.RevokeAccessRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if err := h.orderUsecase.RevokeAccess(adminExtID, accessID, &req); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Access revoked", nil)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/martinmanurung/cinestream/internal/domain/orders"
 	"github.com/martinmanurung/cinestream/internal/domain/orders/usecase"
 	"github.com/martinmanurung/cinestream/pkg/constant"
 	"github.com/martinmanurung/cinestream/pkg/response"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
 )
 
 // OrderHandler handles HTTP requests for order operations
@@ -33,9 +35,12 @@ func NewOrderHandler(ctx context.Context, orderUsecase usecase.OrderUsecase) *Or
 // @Produce json
 // @Param request body orders.CreateOrderRequest true "Order Request"
 // @Success 201 {object} response.Response{data=orders.CreateOrderResponse}
+// @Success 200 {object} response.Response{data=orders.CreateOrderResponse} "Pending order for the movie, pay it with its checkout URL"
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response "Movie already owned or an order of the other type is pending"
 // @Failure 500 {object} response.Response
+// @Failure 503 {object} response.Response "Payment gateway temporarily unavailable"
 // @Router /api/v1/orders [post]
 // @Security BearerAuth
 func (h *OrderHandler) CreateOrder(c echo.Context) error {
@@ -56,10 +61,16 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
+	// Licensing windows are checked for the client's country
+	req.Region, _ = c.Get(string(constant.CtxKeyRegion)).(string)
+
 	// Create order using user_ext_id string directly
-	result, err := h.orderUsecase.CreateOrder(userExtID, &req)
+	result, err := h.orderUsecase.CreateOrder(tracing.WithRequest(h.ctx, c.Request()), userExtID, &req)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err.Error(), nil)
+		return response.HandleError(c, err)
+	}
+	if result.Existing {
+		return response.Success(c, http.StatusOK, "Pending order found", result)
 	}
 
 	return response.Success(c, http.StatusCreated, "Order created successfully", result)
@@ -98,7 +109,7 @@ func (h *OrderHandler) GetUserOrders(c echo.Context) error {
 	// Get orders using user_ext_id string directly
 	result, err := h.orderUsecase.GetUserOrders(userExtID, page, limit)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err.Error(), nil)
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "Orders retrieved successfully", result)
@@ -112,6 +123,7 @@ func (h *OrderHandler) GetUserOrders(c echo.Context) error {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
 // @Param status query string false "Filter by payment status" Enums(PENDING, PAID, FAILED, EXPIRED)
+// @Param invoice query string false "Filter by invoice number or its start, e.g. INV-2026-"
 // @Success 200 {object} response.Response{data=orders.OrdersListWrapper}
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
@@ -130,13 +142,16 @@ func (h *OrderHandler) GetAllOrders(c echo.Context) error {
 		limit = 20
 	}
 
-	// Get status filter
-	status := c.QueryParam("status")
+	// Get status and invoice number filters
+	filter := orders.OrderFilter{
+		Status:  c.QueryParam("status"),
+		Invoice: strings.TrimSpace(c.QueryParam("invoice")),
+	}
 
 	// Get all orders
-	result, err := h.orderUsecase.GetAllOrders(page, limit, status)
+	result, err := h.orderUsecase.GetAllOrders(page, limit, filter)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err.Error(), nil)
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "Orders retrieved successfully", result)
@@ -164,25 +179,32 @@ func (h *OrderHandler) GetOrderDetail(c echo.Context) error {
 	// Get order detail
 	result, err := h.orderUsecase.GetOrderDetail(orderID)
 	if err != nil {
-		return response.Error(c, http.StatusNotFound, err.Error(), nil)
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "Order detail retrieved successfully", result)
 }
 
 // SimulatePaymentSuccess handles POST /api/v1/orders/:id/simulate-payment
-// @Summary Simulate payment success for testing (Development only)
+// Only registered when server.environment is development, the order records the admin who simulated it
+// @Summary Simulate payment success for testing (Development only, Admin only)
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/orders/{id}/simulate-payment [post]
 // @Security BearerAuth
 func (h *OrderHandler) SimulatePaymentSuccess(c echo.Context) error {
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
 	// Parse order ID
 	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -190,9 +212,123 @@ func (h *OrderHandler) SimulatePaymentSuccess(c echo.Context) error {
 	}
 
 	// Simulate payment success
-	if err := h.orderUsecase.SimulatePaymentSuccess(orderID); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err.Error(), nil)
+	if err := h.orderUsecase.SimulatePaymentSuccess(adminExtID, orderID); err != nil {
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "Payment simulated successfully. Movie access granted!", nil)
 }
+
+// CancelOrder handles POST /api/v1/orders/:id/cancel
+// @Summary Cancel a pending order
+// @Tags Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/orders/{id}/cancel [post]
+// @Security BearerAuth
+func (h *OrderHandler) CancelOrder(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid order ID", nil)
+	}
+
+	if err := h.orderUsecase.CancelOrder(tracing.WithRequest(h.ctx, c.Request()), userExtID, orderID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Order cancelled successfully", nil)
+}
+
+// RefundOrder handles POST /api/v1/admin/orders/:id/refund
+// @Summary Refund a paid order and revoke movie access (Admin only)
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body orders.RefundOrderRequest false "Refund Request"
+// @Success 200 {object} response.Response{data=orders.RefundResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/orders/{id}/refund [post]
+// @Security BearerAuth
+func (h *OrderHandler) RefundOrder(c echo.Context) error {
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid order ID", nil)
+	}
+
+	var req orders.RefundOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	result, err := h.orderUsecase.RefundOrder(tracing.WithRequest(h.ctx, c.Request()), adminExtID, orderID, &req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Order refunded successfully", result)
+}
+
+// GetOrderPaymentEvents handles GET /api/v1/admin/orders/:id/events
+// @Summary List the payment webhooks received for an order with their outcome (Admin only)
+// @Tags Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} response.Response{data=[]orders.PaymentEvent}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/admin/orders/{id}/events [get]
+// @Security BearerAuth
+func (h *OrderHandler) GetOrderPaymentEvents(c echo.Context) error {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid order ID", nil)
+	}
+
+	events, err := h.orderUsecase.GetOrderPaymentEvents(orderID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Payment events retrieved successfully", events)
+}
+
+// ResetRentalDevices handles DELETE /api/v1/admin/orders/:id/devices
+// @Summary Forget the devices of an order's rental so new devices can stream it (Admin only)
+// @Tags Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/admin/orders/{id}/devices [delete]
+// @Security BearerAuth
+func (h *OrderHandler) ResetRentalDevices(c echo.Context) error {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid order ID", nil)
+	}
+
+	removed, err := h.orderUsecase.ResetRentalDevices(orderID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Rental devices reset", map[string]int64{"removed_devices": removed})
+}
@@ -0,0 +1,156 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"github.com/martinmanurung/cinestream/internal/domain/orders/usecase"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// OfflineHandler handles offline download license requests
+type OfflineHandler struct {
+	ctx          context.Context
+	orderUsecase usecase.OrderUsecase
+}
+
+// NewOfflineHandler creates a new offline license handler
+func NewOfflineHandler(ctx context.Context, orderUsecase usecase.OrderUsecase) *OfflineHandler {
+	return &OfflineHandler{
+		ctx:          ctx,
+		orderUsecase: orderUsecase,
+	}
+}
+
+// IssueLicense handles POST /api/v1/movies/:id/offline-license
+// @Summary Issue an offline playback license for a downloaded movie
+// @Tags Offline
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param request body orders.OfflineLicenseRequest true "Device"
+// @Success 201 {object} response.Response{data=orders.OfflineLicenseResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/movies/{id}/offline-license [post]
+// @Security BearerAuth
+func (h *OfflineHandler) IssueLicense(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	var req orders.OfflineLicenseRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	result, err := h.orderUsecase.IssueOfflineLicense(userExtID, movieID, &req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Offline license issued", result)
+}
+
+// RenewLicense handles POST /api/v1/offline-licenses/:licenseID/renew
+// @Summary Renew an offline license when the device reconnects
+// @Tags Offline
+// @Accept json
+// @Produce json
+// @Param licenseID path string true "License ID"
+// @Param request body orders.OfflineLicenseRequest true "Device"
+// @Success 200 {object} response.Response{data=orders.OfflineLicenseResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/offline-licenses/{licenseID}/renew [post]
+// @Security BearerAuth
+func (h *OfflineHandler) RenewLicense(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	var req orders.OfflineLicenseRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	result, err := h.orderUsecase.RenewOfflineLicense(userExtID, c.Param("licenseID"), &req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Offline license renewed", result)
+}
+
+// GetRevocationList handles GET /api/v1/offline-licenses/revocations
+// @Summary Get revoked offline licenses that downloads must stop playing
+// @Tags Offline
+// @Produce json
+// @Param since query string false "Only licenses revoked after this RFC3339 time"
+// @Success 200 {object} response.Response{data=orders.RevocationListResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/offline-licenses/revocations [get]
+// @Security BearerAuth
+func (h *OfflineHandler) GetRevocationList(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	var since *time.Time
+	if s := c.QueryParam("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, "Invalid since, expected RFC3339", nil)
+		}
+		since = &t
+	}
+
+	result, err := h.orderUsecase.GetRevocationList(userExtID, since)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Revocation list retrieved successfully", result)
+}
+
+// RevokeLicense handles DELETE /api/v1/admin/offline-licenses/:licenseID
+// @Summary Revoke an offline license (Admin only)
+// @Tags Offline
+// @Produce json
+// @Param licenseID path string true "License ID"
+// @Param reason query string false "Revoke reason"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /api/v1/admin/offline-licenses/{licenseID} [delete]
+// @Security BearerAuth
+func (h *OfflineHandler) RevokeLicense(c echo.Context) error {
+	if err := h.orderUsecase.RevokeOfflineLicense(c.Param("licenseID"), c.QueryParam("reason")); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Offline license revoked", nil)
+}
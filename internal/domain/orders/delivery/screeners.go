@@ -0,0 +1,188 @@
+package delivery
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// CreateScreener handles POST /api/v1/admin/movies/:id/screeners
+// Creates a screener link, its token is only returned here (Admin only)
+func (h *OrderHandler) CreateScreener(c echo.Context) error {
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	var req orders.CreateScreenerRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	result, err := h.orderUsecase.CreateScreener(adminExtID, movieID, &req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Screener created", result)
+}
+
+// GetScreeners handles GET /api/v1/admin/movies/:id/screeners
+// Lists the screener links of a movie with their used views (Admin only)
+func (h *OrderHandler) GetScreeners(c echo.Context) error {
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	result, err := h.orderUsecase.GetScreeners(movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Screeners retrieved successfully", result)
+}
+
+// GetScreenerViews handles GET /api/v1/admin/screeners/:id/views
+// Returns the view log of a screener link with IP addresses and times (Admin only)
+func (h *OrderHandler) GetScreenerViews(c echo.Context) error {
+	screenerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid screener ID", nil)
+	}
+
+	result, err := h.orderUsecase.GetScreenerViews(screenerID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Screener views retrieved successfully", result)
+}
+
+// RevokeScreener handles DELETE /api/v1/admin/screeners/:id
+// Revokes a screener link, running views stop too (Admin only)
+func (h *OrderHandler) RevokeScreener(c echo.Context) error {
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	screenerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid screener ID", nil)
+	}
+
+	if err := h.orderUsecase.RevokeScreener(adminExtID, screenerID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Screener revoked", nil)
+}
+
+// GetScreener handles GET /api/v1/screeners/:token
+// Shows the movie and remaining views of a screener link, no account needed
+func (h *StreamingHandler) GetScreener(c echo.Context) error {
+	result, err := h.orderUsecase.GetScreener(c.Param("token"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Screener retrieved successfully", result)
+}
+
+// StartScreenerView handles POST /api/v1/screeners/:token/views
+// Uses one view of the link and returns its playback URLs
+// Optional device hints: ?codecs=h264,hevc&max_resolution=720
+func (h *StreamingHandler) StartScreenerView(c echo.Context) error {
+	caps, err := orders.ParseDeviceCapabilities(c.QueryParams())
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	result, err := h.orderUsecase.StartScreenerView(c.Param("token"), streamDevice(c), caps)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "Screener view started", result)
+}
+
+// ProxyScreenerHLS handles GET /api/v1/screeners/:token/views/:viewID/hls/*
+// Streams the playlists and segments of a screener view, the key URI of media playlists
+// is pointed at the view's key endpoint since screener viewers have no JWT
+func (h *StreamingHandler) ProxyScreenerHLS(c echo.Context) error {
+	token, viewID := c.Param("token"), c.Param("viewID")
+
+	file := c.Param("*")
+	if file == "" || strings.Contains(file, "..") {
+		return response.Error(c, http.StatusBadRequest, "Invalid file path", nil)
+	}
+
+	movieID, err := h.orderUsecase.CheckScreenerView(token, viewID, c.RealIP())
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	if path.Base(file) == masterPlaylist {
+		caps, err := orders.ParseDeviceCapabilities(c.QueryParams())
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+		}
+		return h.serveMasterPlaylist(c, movieID, path.Dir(file), caps)
+	}
+
+	objectName := fmt.Sprintf("movie-%d/%s", movieID, file)
+	if path.Ext(file) == ".m3u8" {
+		playlist, err := h.readProcessedFile(ctx, objectName)
+		if err != nil {
+			return response.Error(c, http.StatusNotFound, "File not found", nil)
+		}
+		keyURI := fmt.Sprintf(`URI="/api/v1/keys/%d"`, movieID)
+		screenerKeyURI := fmt.Sprintf(`URI="/api/v1/screeners/%s/views/%s/key"`, token, viewID)
+		playlist = bytes.ReplaceAll(playlist, []byte(keyURI), []byte(screenerKeyURI))
+
+		c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+		return c.Blob(http.StatusOK, hlsContentType(file), playlist)
+	}
+
+	object, err := h.storage.StreamProcessedFile(ctx, objectName)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, "File not found", nil)
+	}
+	defer object.Close()
+
+	var body io.Reader = object
+	if h.limiter != nil {
+		// Screener viewers have no plan, the default rate applies
+		body = h.limiter.Reader(ctx, "screener:"+viewID, "", object)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return c.Stream(http.StatusOK, hlsContentType(file), body)
+}
+
+// GetScreenerContentKey handles GET /api/v1/screeners/:token/views/:viewID/key
+// Serves the AES-128 key of an encrypted movie to a running screener view
+func (h *StreamingHandler) GetScreenerContentKey(c echo.Context) error {
+	key, err := h.orderUsecase.GetScreenerContentKey(c.Param("token"), c.Param("viewID"), c.RealIP())
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return c.Blob(http.StatusOK, echo.MIMEOctetStream, key)
+}
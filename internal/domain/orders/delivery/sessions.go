@@ -0,0 +1,81 @@
+package delivery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// headerDeviceID is sent by players to identify the device across stream requests
+const headerDeviceID = "X-Device-ID"
+
+// GetSessions handles GET /api/v1/users/me/sessions
+// Returns the devices currently streaming on the account
+func (h *StreamingHandler) GetSessions(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	result, err := h.orderUsecase.GetStreamSessions(userExtID, streamDevice(c).Fingerprint)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Stream sessions retrieved successfully", result)
+}
+
+// EndSession handles DELETE /api/v1/users/me/sessions/:sessionID
+// Kicks a device, its player is rejected on the next playlist/segment request
+func (h *StreamingHandler) EndSession(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	if err := h.orderUsecase.EndStreamSession(userExtID, c.Param("sessionID")); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Stream session ended", nil)
+}
+
+// EndAllSessions handles DELETE /api/v1/users/me/sessions
+// Kicks every device including the current one
+func (h *StreamingHandler) EndAllSessions(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	if err := h.orderUsecase.EndStreamSession(userExtID, ""); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "All stream sessions ended", nil)
+}
+
+// streamDevice identifies the requesting device by its X-Device-ID header,
+// players without one are identified by their user agent
+func streamDevice(c echo.Context) orders.StreamDevice {
+	userAgent := c.Request().UserAgent()
+	fingerprint := strings.TrimSpace(c.Request().Header.Get(headerDeviceID))
+	if fingerprint == "" || len(fingerprint) > 64 {
+		hash := sha256.Sum256([]byte(fingerprint + "|" + userAgent))
+		fingerprint = "ua:" + hex.EncodeToString(hash[:])[:32]
+	}
+
+	role, _ := c.Get(string(constant.CtxKeyUserRole)).(string)
+	return orders.StreamDevice{
+		Fingerprint: fingerprint,
+		IPAddress:   c.RealIP(),
+		UserAgent:   userAgent,
+		Plan:        role,
+	}
+}
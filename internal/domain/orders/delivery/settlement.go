@@ -0,0 +1,68 @@
+package delivery
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// GetSettlementReports handles GET /api/v1/admin/settlements
+// @Summary List the daily settlement summaries, the last 30 days by default (Admin only)
+// @Tags Orders
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=[]orders.SettlementReport}
+// @Failure 400 {object} response.Response
+// @Router /api/v1/admin/settlements [get]
+// @Security BearerAuth
+func (h *OrderHandler) GetSettlementReports(c echo.Context) error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today.AddDate(0, 0, -30), today
+
+	var err error
+	if value := c.QueryParam("from"); value != "" {
+		if from, err = time.Parse(orders.SettlementDayLayout, value); err != nil {
+			return response.Error(c, http.StatusBadRequest, "invalid_from", "from must be YYYY-MM-DD")
+		}
+	}
+	if value := c.QueryParam("to"); value != "" {
+		if to, err = time.Parse(orders.SettlementDayLayout, value); err != nil {
+			return response.Error(c, http.StatusBadRequest, "invalid_to", "to must be YYYY-MM-DD")
+		}
+	}
+
+	reports, err := h.orderUsecase.GetSettlementReports(from, to)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Settlement reports retrieved successfully", reports)
+}
+
+// GetSettlementReport handles GET /api/v1/admin/settlements/:date
+// @Summary Get the settlement summary of a day with its mismatches (Admin only)
+// @Tags Orders
+// @Produce json
+// @Param date path string true "Day (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=orders.SettlementReport}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/admin/settlements/{date} [get]
+// @Security BearerAuth
+func (h *OrderHandler) GetSettlementReport(c echo.Context) error {
+	day, err := time.Parse(orders.SettlementDayLayout, c.Param("date"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_date", "date must be YYYY-MM-DD")
+	}
+
+	report, err := h.orderUsecase.GetSettlementReport(day)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Settlement report retrieved successfully", report)
+}
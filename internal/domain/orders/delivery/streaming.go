@@ -2,31 +2,55 @@ package delivery
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
 	"github.com/martinmanurung/cinestream/internal/domain/orders/usecase"
+	"github.com/martinmanurung/cinestream/internal/platform/throttle"
 	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/hls"
 	"github.com/martinmanurung/cinestream/pkg/response"
 )
 
+// Master playlist names written by the transcoder
+const (
+	masterPlaylist     = "master.m3u8"
+	hevcMasterPlaylist = "master_hevc.m3u8"
+)
+
+// HLSStorage defines the storage operations needed by the streaming proxy
+type HLSStorage interface {
+	StreamProcessedFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+}
+
 // StreamingHandler handles movie streaming requests
 type StreamingHandler struct {
 	ctx          context.Context
 	orderUsecase usecase.OrderUsecase
+	storage      HLSStorage
+	limiter      *throttle.Limiter // nil when throttling is disabled
 }
 
 // NewStreamingHandler creates a new streaming handler
-func NewStreamingHandler(ctx context.Context, orderUsecase usecase.OrderUsecase) *StreamingHandler {
+func NewStreamingHandler(ctx context.Context, orderUsecase usecase.OrderUsecase, storage HLSStorage, limiter *throttle.Limiter) *StreamingHandler {
 	return &StreamingHandler{
 		ctx:          ctx,
 		orderUsecase: orderUsecase,
+		storage:      storage,
+		limiter:      limiter,
 	}
 }
 
 // GetStreamURL handles GET /api/v1/movies/:id/stream
-// Returns HLS streaming URL if user has access
+// Returns HLS streaming URL if user has access and starts a stream session for the device
+// Players should send the same X-Device-ID header here and on HLS requests
+// Optional device hints: ?codecs=h264,hevc&max_resolution=720&drm=widevine
 func (h *StreamingHandler) GetStreamURL(c echo.Context) error {
 	// Get user_ext_id from JWT context
 	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
@@ -40,11 +64,257 @@ func (h *StreamingHandler) GetStreamURL(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
 	}
 
+	caps, err := orders.ParseDeviceCapabilities(c.QueryParams())
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
 	// Check access and get HLS URL using user_ext_id string directly
-	streamResp, err := h.orderUsecase.CheckStreamAccess(userExtID, movieID)
+	device := streamDevice(c)
+	streamResp, err := h.orderUsecase.CheckStreamAccess(userExtID, movieID, caps, &device)
 	if err != nil {
-		return response.Error(c, http.StatusForbidden, err.Error(), nil)
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, streamResp.Message, streamResp)
 }
+
+// GetEntitlement handles GET /api/v1/movies/:id/entitlement
+// Returns access state, progress and pending order for the current user
+func (h *StreamingHandler) GetEntitlement(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	result, err := h.orderUsecase.GetEntitlement(userExtID, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Entitlement retrieved successfully", result)
+}
+
+// SaveProgress handles PUT /api/v1/movies/:id/progress
+// Stores the player's current position so playback can be resumed
+func (h *StreamingHandler) SaveProgress(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	var req orders.SaveProgressRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid request body", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if err := h.orderUsecase.SaveWatchProgress(userExtID, movieID, &req); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "Progress saved", nil)
+}
+
+// ProxyHLS handles GET /api/v1/movies/:id/hls/*
+// Streams HLS playlists and segments through the API, throttled per stream session
+func (h *StreamingHandler) ProxyHLS(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	file := c.Param("*")
+	if file == "" || strings.Contains(file, "..") {
+		return response.Error(c, http.StatusBadRequest, "Invalid file path", nil)
+	}
+
+	if _, err := h.orderUsecase.CheckStreamAccess(userExtID, movieID, nil, nil); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	// Keeps the device's session alive, a kicked device stops here
+	if err := h.orderUsecase.TouchStreamSession(userExtID, movieID, streamDevice(c)); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	if path.Base(file) == masterPlaylist {
+		caps, err := orders.ParseDeviceCapabilities(c.QueryParams())
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, err.Error(), nil)
+		}
+		return h.serveMasterPlaylist(c, movieID, path.Dir(file), caps)
+	}
+
+	object, err := h.storage.StreamProcessedFile(ctx, fmt.Sprintf("movie-%d/%s", movieID, file))
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, "File not found", nil)
+	}
+	defer object.Close()
+
+	var body io.Reader = object
+	if h.limiter != nil {
+		// One session per user and movie, the user role acts as the plan for now
+		role, _ := c.Get(string(constant.CtxKeyUserRole)).(string)
+		sessionKey := fmt.Sprintf("%s:%d", userExtID, movieID)
+		body = h.limiter.Reader(ctx, sessionKey, role, object)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return c.Stream(http.StatusOK, hlsContentType(file), body)
+}
+
+// GetContentKey handles GET /api/v1/keys/:movieID
+// Serves the AES-128 key referenced by the EXT-X-KEY tags of an encrypted movie's playlists
+func (h *StreamingHandler) GetContentKey(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("movieID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "Invalid movie ID", nil)
+	}
+
+	key, err := h.orderUsecase.GetContentKey(userExtID, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return c.Blob(http.StatusOK, echo.MIMEOctetStream, key)
+}
+
+// serveMasterPlaylist picks the HEVC or H.264 master for the device and drops
+// the variants it cannot decode or that exceed its max resolution
+// The movie's subtitle tracks and uploaded audio tracks are added as EXT-X-MEDIA renditions
+// dir is the output version directory ("v3"), "." for legacy unversioned output
+func (h *StreamingHandler) serveMasterPlaylist(c echo.Context, movieID int64, dir string, caps *orders.DeviceCapabilities) error {
+	ctx := c.Request().Context()
+
+	var master []byte
+	if caps.SupportsCodec(orders.CodecHEVC) {
+		// HEVC renditions are optional, fall back to the H.264 master when they do not exist
+		master, _ = h.readProcessedFile(ctx, fmt.Sprintf("movie-%d/%s", movieID, path.Join(dir, hevcMasterPlaylist)))
+	}
+	if master == nil {
+		data, err := h.readProcessedFile(ctx, fmt.Sprintf("movie-%d/%s", movieID, path.Join(dir, masterPlaylist)))
+		if err != nil {
+			return response.Error(c, http.StatusNotFound, "File not found", nil)
+		}
+		master = data
+	}
+
+	filtered, kept := hls.FilterMaster(master, func(v hls.Variant) bool {
+		if !caps.SupportsCodec(v.VideoCodec()) {
+			return false
+		}
+		return caps.MaxResolution == 0 || v.Height == 0 || v.Height <= caps.MaxResolution
+	})
+	if kept == 0 {
+		return response.Error(c, http.StatusNotAcceptable, "No stream variant matches the device capabilities", caps)
+	}
+
+	// Subtitle playlists live next to the output versions, e.g. v3/master.m3u8 -> ../subtitles/en.m3u8
+	tracks, err := h.orderUsecase.GetSubtitleTracks(movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	if len(tracks) > 0 {
+		prefix := relativePrefix(dir)
+		renditions := make([]hls.SubtitleRendition, len(tracks))
+		for i, track := range tracks {
+			renditions[i] = hls.SubtitleRendition{
+				Language: track.Language,
+				Name:     track.Label,
+				URI:      prefix + "subtitles/" + track.Language + ".m3u8",
+				Default:  track.Default,
+			}
+		}
+		filtered = hls.AddSubtitles(filtered, renditions)
+	}
+
+	// Uploaded dubs and audio descriptions live next to the output versions too, e.g. ../audio/12/index.m3u8
+	audioTracks, err := h.orderUsecase.GetAudioTracks(movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	var main *hls.AudioRendition
+	var uploaded []hls.AudioRendition
+	for _, track := range audioTracks {
+		if track.Playlist == "" {
+			if main == nil && track.Default {
+				main = &hls.AudioRendition{Language: track.Language, Name: track.Name}
+			}
+			continue
+		}
+		uploaded = append(uploaded, hls.AudioRendition{
+			Language:       track.Language,
+			Name:           track.Name,
+			URI:            relativePrefix(dir) + track.Playlist,
+			DescribesVideo: track.Kind == orders.AudioKindDescription,
+		})
+	}
+	filtered = hls.AddAudio(filtered, main, uploaded)
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	c.Response().Header().Set(echo.HeaderVary, "Authorization")
+	return c.Blob(http.StatusOK, hlsContentType(masterPlaylist), filtered)
+}
+
+// relativePrefix leads from the output version directory back to the movie directory
+func relativePrefix(dir string) string {
+	if dir == "." {
+		return ""
+	}
+	return strings.Repeat("../", strings.Count(dir, "/")+1)
+}
+
+func (h *StreamingHandler) readProcessedFile(ctx context.Context, objectName string) ([]byte, error) {
+	object, err := h.storage.StreamProcessedFile(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return io.ReadAll(object)
+}
+
+// hlsContentType returns the MIME type of an HLS playlist or segment
+func hlsContentType(file string) string {
+	switch path.Ext(file) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".mpd":
+		return "application/dash+xml"
+	case ".ts":
+		return "video/mp2t"
+	case ".m4s", ".mp4":
+		return "video/mp4"
+	case ".vtt":
+		return "text/vtt"
+	default:
+		return echo.MIMEOctetStream
+	}
+}
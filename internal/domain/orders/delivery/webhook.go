@@ -2,7 +2,9 @@ package delivery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"time"
@@ -14,145 +16,228 @@ import (
 	"github.com/martinmanurung/cinestream/pkg/response"
 )
 
+// SignatureFailureRecorder counts webhooks that failed signature verification
+type SignatureFailureRecorder interface {
+	RecordWebhookSignatureFailure(ctx context.Context, provider string)
+}
+
+// InvoiceSender issues the receipt of a freshly paid order
+type InvoiceSender interface {
+	SendInvoice(ctx context.Context, orderID int64)
+}
+
 // WebhookHandler handles payment gateway webhooks
 type WebhookHandler struct {
-	ctx            context.Context
-	orderRepo      orderRepository.OrderRepository
-	paymentService payment.PaymentService
-	serverKey      string
+	ctx       context.Context
+	orderRepo orderRepository.OrderRepository
+	payments  *payment.Registry
+	metrics   SignatureFailureRecorder
+	invoices  InvoiceSender // optional, see WithInvoices
 }
 
 // NewWebhookHandler creates a new webhook handler
 func NewWebhookHandler(
 	ctx context.Context,
 	orderRepo orderRepository.OrderRepository,
-	paymentService payment.PaymentService,
-	serverKey string,
+	payments *payment.Registry,
+	metrics SignatureFailureRecorder,
 ) *WebhookHandler {
 	return &WebhookHandler{
-		ctx:            ctx,
-		orderRepo:      orderRepo,
-		paymentService: paymentService,
-		serverKey:      serverKey,
+		ctx:       ctx,
+		orderRepo: orderRepo,
+		payments:  payments,
+		metrics:   metrics,
 	}
 }
 
-// MidtransNotification represents the webhook payload from Midtrans
-type MidtransNotification struct {
-	TransactionStatus string `json:"transaction_status"`
-	OrderID           string `json:"order_id"`
-	GrossAmount       string `json:"gross_amount"`
-	StatusCode        string `json:"status_code"`
-	SignatureKey      string `json:"signature_key"`
-	PaymentType       string `json:"payment_type"`
-	TransactionID     string `json:"transaction_id"`
-	FraudStatus       string `json:"fraud_status"`
-	TransactionTime   string `json:"transaction_time"`
+// WithInvoices issues receipts through invoices once a payment is applied
+func (h *WebhookHandler) WithInvoices(invoices InvoiceSender) *WebhookHandler {
+	h.invoices = invoices
+	return h
 }
 
 // HandlePaymentWebhook handles POST /api/v1/webhooks/payment
+// Kept for the notification URL already configured on the Midtrans dashboard
 // @Summary Handle payment notification from Midtrans
 // @Tags Webhooks
 // @Accept json
 // @Produce json
-// @Param notification body MidtransNotification true "Payment Notification"
 // @Success 200 {object} response.SuccessResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /api/v1/webhooks/payment [post]
 func (h *WebhookHandler) HandlePaymentWebhook(c echo.Context) error {
-	// 1. Parse webhook payload
-	var notification MidtransNotification
-	if err := c.Bind(&notification); err != nil {
-		log.Printf("[WEBHOOK] Failed to parse notification: %v", err)
-		return response.Error(c, http.StatusBadRequest, "Invalid notification payload", nil)
-	}
+	return h.handleNotification(c, payment.ProviderMidtrans)
+}
 
-	log.Printf("[WEBHOOK] Received notification for order: %s, status: %s",
-		notification.OrderID, notification.TransactionStatus)
+// HandleProviderWebhook handles POST /api/v1/webhooks/:provider
+// @Summary Handle payment notification from a payment provider
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider (midtrans, stripe)"
+// @Success 200 {object} response.SuccessResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/webhooks/{provider} [post]
+func (h *WebhookHandler) HandleProviderWebhook(c echo.Context) error {
+	return h.handleNotification(c, c.Param("provider"))
+}
 
-	// 2. Verify signature to ensure request is authentic
-	isValid := h.paymentService.VerifySignature(
-		notification.OrderID,
-		notification.StatusCode,
-		notification.GrossAmount,
-		h.serverKey,
-		notification.SignatureKey,
-	)
-
-	if !isValid {
-		log.Printf("[WEBHOOK] Invalid signature for order: %s", notification.OrderID)
-		return response.Error(c, http.StatusUnauthorized, "Invalid signature", nil)
+// handleNotification verifies a provider notification and applies it to the order
+func (h *WebhookHandler) handleNotification(c echo.Context, providerName string) error {
+	paymentService, err := h.payments.Get(providerName)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, "Unknown payment provider", nil)
 	}
 
-	log.Printf("[WEBHOOK] Signature verified for order: %s", notification.OrderID)
+	// Every webhook is kept with what became of it, see PaymentEvent
+	event := &orders.PaymentEvent{Provider: paymentService.Name()}
+	defer h.recordEvent(event)
+
+	// 1. Read the raw payload, signatures are computed over the exact body
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed to read %s notification: %v", providerName, err)
+		event.Fail(orders.PaymentEventInvalidPayload, err)
+		return response.Error(c, http.StatusBadRequest, "Invalid notification payload", nil)
+	}
+	event.SetPayload(body)
 
-	// 3. Find order by payment gateway reference
-	order, err := h.orderRepo.FindOrderByPaymentRef(notification.OrderID)
+	// 2. Verify signature to ensure request is authentic
+	notification, err := paymentService.ParseNotification(c.Request().Header, body)
 	if err != nil {
-		log.Printf("[WEBHOOK] Order not found: %s, error: %v", notification.OrderID, err)
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			log.Printf("[WEBHOOK] Invalid %s signature", providerName)
+			h.metrics.RecordWebhookSignatureFailure(h.ctx, providerName)
+			event.Fail(orders.PaymentEventInvalidSignature, err)
+			return response.Error(c, http.StatusUnauthorized, "Invalid signature", nil)
+		}
+		log.Printf("[WEBHOOK] Failed to parse %s notification: %v", providerName, err)
+		event.Fail(orders.PaymentEventInvalidPayload, err)
+		return response.Error(c, http.StatusBadRequest, "Invalid notification payload", nil)
+	}
+	event.SignatureValid = true
+	event.OrderID = &notification.OrderID
+	event.TransactionStatus = notification.TransactionStatus
+
+	log.Printf("[WEBHOOK] Received %s notification for order: %d, status: %s",
+		providerName, notification.OrderID, notification.TransactionStatus)
+
+	// 3. Find the order, it must belong to the provider that sent the notification
+	order, err := h.orderRepo.FindOrderByID(notification.OrderID)
+	if err != nil || order.PaymentProvider != paymentService.Name() {
+		log.Printf("[WEBHOOK] Order not found: %d, provider: %s, error: %v", notification.OrderID, providerName, err)
+		if err == nil {
+			err = fmt.Errorf("order belongs to payment provider %s", order.PaymentProvider)
+		}
+		event.Fail(orders.PaymentEventOrderNotFound, err)
 		return response.Error(c, http.StatusNotFound, "Order not found", nil)
 	}
 
-	log.Printf("[WEBHOOK] Found order ID: %d for payment ref: %s", order.ID, notification.OrderID)
+	// Cancelled/refunded orders were finalized by us, late notifications must not overwrite them
+	if order.PaymentStatus == orders.PaymentStatusCancelled || order.PaymentStatus == orders.PaymentStatusRefunded {
+		log.Printf("[WEBHOOK] Order %d already %s, ignoring status: %s", order.ID, order.PaymentStatus, notification.TransactionStatus)
+		event.Action = orders.PaymentEventIgnored
+		return response.Success(c, http.StatusOK, "Notification processed", nil)
+	}
+
+	// 4. Process based on the mapped outcome
+	record := &orders.PaymentNotification{
+		OrderID:           order.ID,
+		TransactionID:     notification.TransactionID,
+		TransactionStatus: notification.TransactionStatus,
+		FraudStatus:       notification.FraudStatus,
+		StatusCode:        notification.StatusCode,
+	}
 
-	// 4. Process based on transaction status
-	switch notification.TransactionStatus {
-	case "capture", "settlement":
+	var applied bool
+	switch notification.Outcome {
+	case payment.OutcomePaid:
 		// Payment successful
-		if notification.FraudStatus == "accept" || notification.FraudStatus == "" {
-			if err := h.handleSuccessfulPayment(order); err != nil {
-				log.Printf("[WEBHOOK] Failed to process successful payment: %v", err)
-				return response.Error(c, http.StatusInternalServerError, err.Error(), nil)
-			}
+		event.Action = orders.PaymentEventPaid
+		applied, err = h.handleSuccessfulPayment(order, record)
+		if err != nil {
+			log.Printf("[WEBHOOK] Failed to process successful payment: %v", err)
+			event.Fail(orders.PaymentEventError, err)
+			return response.HandleError(c, err)
+		}
+		if applied {
 			log.Printf("[WEBHOOK] Successfully processed payment for order: %d", order.ID)
+			// The receipt is generated and emailed after the gateway got its answer
+			if h.invoices != nil {
+				go h.invoices.SendInvoice(h.ctx, order.ID)
+			}
 		}
 
-	case "pending":
-		// Payment pending, no action needed
-		log.Printf("[WEBHOOK] Payment pending for order: %d", order.ID)
-
-	case "deny", "cancel", "expire":
+	case payment.OutcomeFailed:
 		// Payment failed or cancelled
-		now := time.Now()
-		if err := h.orderRepo.UpdateOrderStatus(order.ID, orders.PaymentStatusFailed, &now); err != nil {
-			log.Printf("[WEBHOOK] Failed to update failed order status: %v", err)
-			return response.Error(c, http.StatusInternalServerError, err.Error(), nil)
+		event.Action = orders.PaymentEventFailed
+		applied, err = h.orderRepo.ApplyPaymentNotification(record, orders.PaymentStatusFailed, nil, nil)
+		if err == nil && applied {
+			log.Printf("[WEBHOOK] Payment failed/cancelled for order: %d, status: %s",
+				order.ID, notification.TransactionStatus)
 		}
-		log.Printf("[WEBHOOK] Payment failed/cancelled for order: %d, status: %s",
+
+	default:
+		// Payment pending, no action needed
+		log.Printf("[WEBHOOK] Payment %s for order: %d", notification.TransactionStatus, order.ID)
+		event.Action = orders.PaymentEventPending
+		applied, err = h.orderRepo.ApplyPaymentNotification(record, "", nil, nil)
+	}
+
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed to record notification for order %d: %v", order.ID, err)
+		event.Fail(orders.PaymentEventError, err)
+		return response.HandleError(c, err)
+	}
+
+	// Duplicates are acknowledged too, otherwise the gateway keeps retrying
+	if !applied {
+		log.Printf("[WEBHOOK] Duplicate notification for order: %d, status: %s, ignoring",
 			order.ID, notification.TransactionStatus)
+		event.Action = orders.PaymentEventDuplicate
 	}
 
 	// 5. Return 200 OK to acknowledge receipt
 	return response.Success(c, http.StatusOK, "Notification processed", nil)
 }
 
-// handleSuccessfulPayment processes a successful payment
-func (h *WebhookHandler) handleSuccessfulPayment(order *orders.Order) error {
-	// 1. Update order status to PAID
+// handleSuccessfulPayment marks the order PAID and grants movie access in one transaction
+// Returns false when the notification was already processed
+func (h *WebhookHandler) handleSuccessfulPayment(order *orders.Order, record *orders.PaymentNotification) (bool, error) {
+	// Create user movie access, rentals expire and purchases are permanent
 	now := time.Now()
-	if err := h.orderRepo.UpdateOrderStatus(order.ID, orders.PaymentStatusPaid, &now); err != nil {
-		return fmt.Errorf("failed to update order status: %w", err)
-	}
-
-	log.Printf("[WEBHOOK] Updated order %d status to PAID", order.ID)
-
-	// 2. Create user movie access with 48-hour expiry
-	expiresAt := now.Add(48 * time.Hour)
+	expiresAt := order.AccessExpiry(now)
 	access := &orders.UserMovieAccess{
 		UserExtID:       order.UserExtID,
 		MovieID:         order.MovieID,
-		OrderID:         order.ID,
+		OrderID:         &order.ID,
 		AccessGrantedAt: now,
-		AccessExpiresAt: &expiresAt,
+		AccessExpiresAt: expiresAt,
 	}
 
-	if err := h.orderRepo.CreateUserMovieAccess(access); err != nil {
-		return fmt.Errorf("failed to create user movie access: %w", err)
+	applied, err := h.orderRepo.ApplyPaymentNotification(record, orders.PaymentStatusPaid, &now, access)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply successful payment: %w", err)
+	}
+
+	if applied {
+		expiry := "never"
+		if expiresAt != nil {
+			expiry = expiresAt.Format("2006-01-02 15:04:05")
+		}
+		log.Printf("[WEBHOOK] Updated order %d (%s) status to PAID, movie access for user %s, movie %d, expires at %s",
+			order.ID, order.OrderType, order.UserExtID, order.MovieID, expiry)
 	}
 
-	log.Printf("[WEBHOOK] Created movie access for user %s, movie %d, expires at %s",
-		order.UserExtID, order.MovieID, expiresAt.Format("2006-01-02 15:04:05"))
+	return applied, nil
+}
 
-	return nil
+// recordEvent stores a webhook with its outcome, a failure is only logged so the gateway still gets its answer
+func (h *WebhookHandler) recordEvent(event *orders.PaymentEvent) {
+	if err := h.orderRepo.CreatePaymentEvent(event); err != nil {
+		log.Printf("[WEBHOOK] Failed to store %s payment event: %v", event.Provider, err)
+	}
 }
@@ -1,6 +1,13 @@
 package orders
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 // PaymentStatus represents the status of a payment
 type PaymentStatus string
@@ -10,18 +17,41 @@ const (
 	PaymentStatusPaid    PaymentStatus = "PAID"
 	PaymentStatusFailed  PaymentStatus = "FAILED"
 	PaymentStatusExpired PaymentStatus = "EXPIRED"
+	// PaymentStatusCancelled is set when the user cancels a pending order
+	PaymentStatusCancelled PaymentStatus = "CANCELLED"
+	// PaymentStatusRefunded is set when an admin refunds a paid order
+	PaymentStatusRefunded PaymentStatus = "REFUNDED"
+)
+
+// OrderType is what an order buys: a time-limited rental or permanent ownership
+type OrderType string
+
+const (
+	OrderTypeRental   OrderType = "rental"
+	OrderTypePurchase OrderType = "purchase"
 )
 
+// RentalAccessDuration is how long a rental can be watched after payment
+const RentalAccessDuration = 48 * time.Hour
+
+// DefaultPaymentExpiry is how long a checkout link stays payable unless configured otherwise
+const DefaultPaymentExpiry = 24 * time.Hour
+
 // Order represents an order in the system
 type Order struct {
 	ID                int64         `json:"id" gorm:"primaryKey;autoIncrement"`
 	UserExtID         string        `json:"user_ext_id" gorm:"not null;index;column:user_ext_id"`
 	MovieID           int64         `json:"movie_id" gorm:"not null;index"`
 	Amount            float64       `json:"amount" gorm:"type:decimal(10,2);not null"`
-	PaymentStatus     PaymentStatus `json:"payment_status" gorm:"type:enum('PENDING','PAID','FAILED','EXPIRED');default:'PENDING';not null"`
+	OrderType         OrderType     `json:"order_type" gorm:"type:enum('rental','purchase');default:'rental';not null"`
+	PaymentStatus     PaymentStatus `json:"payment_status" gorm:"type:enum('PENDING','PAID','FAILED','EXPIRED','CANCELLED','REFUNDED');default:'PENDING';not null"`
+	PaymentProvider   string        `json:"payment_provider" gorm:"type:varchar(32);default:'midtrans';not null"`
 	PaymentGatewayRef *string       `json:"payment_gateway_ref,omitempty" gorm:"unique"`
 	CheckoutURL       *string       `json:"checkout_url,omitempty" gorm:"type:text"`
 	PaidAt            *time.Time    `json:"paid_at,omitempty"`
+	InvoiceNumber     *string       `json:"invoice_number,omitempty" gorm:"type:varchar(32);unique"` // assigned once paid, see FormatInvoiceNumber
+	SimulatedBy       *string       `json:"simulated_by,omitempty" gorm:"type:varchar(50)"`          // admin who simulated the payment in development, NULL for real payments
+	Region            *string       `json:"region,omitempty" gorm:"type:char(2)"`                    // client's country when ordering, territory of rights holder reports
 	ExpiresAt         *time.Time    `json:"expires_at,omitempty"`
 	CreatedAt         time.Time     `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt         time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
@@ -37,16 +67,69 @@ func (Order) TableName() string {
 	return "orders"
 }
 
+// InvoiceSequence is the last invoice number handed out in a year, numbers run from 1 without gaps
+type InvoiceSequence struct {
+	Year       int   `gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int64 `gorm:"not null"`
+}
+
+// TableName specifies the table name for InvoiceSequence model
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
+
+// FormatInvoiceNumber returns the invoice number printed on receipts, e.g. INV-2026-000042
+func FormatInvoiceNumber(year int, number int64) string {
+	return fmt.Sprintf("INV-%d-%06d", year, number)
+}
+
+// OrderFilter narrows the admin order list, empty fields match every order
+type OrderFilter struct {
+	Status  string
+	Invoice string // invoice number or its start, e.g. INV-2026-
+}
+
+// AccessExpiry returns when access granted at grantedAt ends, nil for purchases (permanent access)
+func (o *Order) AccessExpiry(grantedAt time.Time) *time.Time {
+	if o.OrderType == OrderTypePurchase {
+		return nil
+	}
+	expiresAt := grantedAt.Add(RentalAccessDuration)
+	return &expiresAt
+}
+
+// PaymentExpiresIn returns the seconds left to pay the order, nil unless it is still PENDING
+func (o *Order) PaymentExpiresIn(now time.Time) *int64 {
+	return PaymentExpiresIn(o.PaymentStatus, o.ExpiresAt, now)
+}
+
+// PaymentExpiresIn returns the seconds left until expiresAt for a PENDING order, nil otherwise
+func PaymentExpiresIn(status PaymentStatus, expiresAt *time.Time, now time.Time) *int64 {
+	if status != PaymentStatusPending || expiresAt == nil {
+		return nil
+	}
+	seconds := int64(expiresAt.Sub(now) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	return &seconds
+}
+
 // UserMovieAccess represents user's access rights to a movie after purchase
 type UserMovieAccess struct {
-	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement"`
-	UserExtID       string     `json:"user_ext_id" gorm:"not null;index;column:user_ext_id"`
-	MovieID         int64      `json:"movie_id" gorm:"not null;index"`
-	OrderID         int64      `json:"order_id" gorm:"not null;unique"`
-	AccessGrantedAt time.Time  `json:"access_granted_at" gorm:"autoCreateTime"`
-	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"` // NULL = permanent access
-	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	ID               int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID        string     `json:"user_ext_id" gorm:"not null;index;column:user_ext_id"`
+	MovieID          int64      `json:"movie_id" gorm:"not null;index"`
+	OrderID          *int64     `json:"order_id,omitempty" gorm:"unique"`             // NULL for access granted by a campaign or an admin
+	GrantRecipientID *int64     `json:"grant_recipient_id,omitempty" gorm:"unique"`   // set for access granted by a campaign, see grants
+	GrantedBy        *string    `json:"granted_by,omitempty" gorm:"type:varchar(50)"` // admin who granted complimentary access
+	AccessGrantedAt  time.Time  `json:"access_granted_at" gorm:"autoCreateTime"`
+	AccessExpiresAt  *time.Time `json:"access_expires_at,omitempty"`                  // NULL = permanent access
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`                         // set when an admin ended the access early, access_expires_at is then this time
+	RevokedBy        *string    `json:"revoked_by,omitempty" gorm:"type:varchar(50)"` // admin who revoked it
+	ExpiryRemindedAt *time.Time `json:"-"`                                            // set once the user was reminded that access ends soon
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // TableName specifies the table name for UserMovieAccess model
@@ -54,17 +137,338 @@ func (UserMovieAccess) TableName() string {
 	return "user_movie_access"
 }
 
+// Access audit log events
+const (
+	AccessAuditGranted = "granted"
+	AccessAuditRevoked = "revoked"
+)
+
+// AccessAuditLog records an admin granting or revoking movie access by hand, e.g. complimentary access or a chargeback
+type AccessAuditLog struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccessID   int64     `json:"access_id" gorm:"not null;index"`
+	UserExtID  string    `json:"user_ext_id" gorm:"type:varchar(100);not null"`
+	MovieID    int64     `json:"movie_id" gorm:"not null"`
+	Event      string    `json:"event" gorm:"type:varchar(20);not null"` // granted or revoked
+	ActorExtID string    `json:"actor_ext_id" gorm:"type:varchar(50);not null"`
+	Reason     *string   `json:"reason,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for AccessAuditLog model
+func (AccessAuditLog) TableName() string {
+	return "user_movie_access_audit_logs"
+}
+
+// ExpiringAccess is access that ends soon, for the reminder email
+type ExpiringAccess struct {
+	AccessID        int64
+	AccessExpiresAt time.Time
+	MovieTitle      string
+	UserName        string
+	UserEmail       string
+}
+
+// PaymentNotification records a processed payment gateway notification
+// Midtrans retries notifications until it gets a 200, so each one is applied at most once
+type PaymentNotification struct {
+	ID                int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID           int64     `json:"order_id" gorm:"not null"`
+	TransactionID     string    `json:"transaction_id" gorm:"type:varchar(255);not null"`
+	TransactionStatus string    `json:"transaction_status" gorm:"type:varchar(50);not null"`
+	FraudStatus       string    `json:"fraud_status" gorm:"type:varchar(50);not null"`
+	StatusCode        string    `json:"status_code" gorm:"type:varchar(10);not null"`
+	ProcessedAt       time.Time `json:"processed_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for PaymentNotification model
+func (PaymentNotification) TableName() string {
+	return "payment_notifications"
+}
+
+// Actions recorded on payment events, what became of a webhook
+const (
+	PaymentEventPaid             = "PAID"              // order marked paid, access granted
+	PaymentEventFailed           = "FAILED"            // order marked failed
+	PaymentEventPending          = "PENDING"           // recorded, nothing to apply
+	PaymentEventDuplicate        = "DUPLICATE"         // same notification already processed
+	PaymentEventIgnored          = "IGNORED"           // order already cancelled or refunded
+	PaymentEventInvalidSignature = "INVALID_SIGNATURE" // rejected with 401
+	PaymentEventInvalidPayload   = "INVALID_PAYLOAD"   // body unreadable or not a notification
+	PaymentEventOrderNotFound    = "ORDER_NOT_FOUND"   // unknown order or order of another provider
+	PaymentEventError            = "ERROR"             // processing failed, the gateway retries
+)
+
+// PaymentEvent is an incoming payment webhook as received, kept so support can debug missing payments.
+// Unlike PaymentNotification it is also stored for webhooks that were rejected or failed.
+type PaymentEvent struct {
+	ID                int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Provider          string    `json:"provider" gorm:"type:varchar(32);not null"`
+	OrderID           *int64    `json:"order_id,omitempty" gorm:"index"` // nil when the payload could not be read
+	TransactionStatus string    `json:"transaction_status,omitempty" gorm:"type:varchar(50)"`
+	SignatureValid    bool      `json:"signature_valid" gorm:"not null"`
+	Action            string    `json:"action" gorm:"type:varchar(32);not null"`
+	Error             *string   `json:"error,omitempty" gorm:"type:text"`
+	Payload           string    `json:"payload" gorm:"type:mediumtext"` // raw body, cut at MaxPaymentEventPayload
+	PayloadTruncated  bool      `json:"payload_truncated" gorm:"not null;default:false"`
+	ReceivedAt        time.Time `json:"received_at" gorm:"autoCreateTime"`
+}
+
+// SetPayload stores the body as valid UTF-8 of at most MaxPaymentEventPayload bytes,
+// a longer body is cut before the character that would cross the limit
+func (e *PaymentEvent) SetPayload(body []byte) {
+	if len(body) > MaxPaymentEventPayload {
+		end := MaxPaymentEventPayload
+		for end > 0 && !utf8.RuneStart(body[end]) {
+			end--
+		}
+		body = body[:end]
+		e.PayloadTruncated = true
+	}
+	e.Payload = strings.ToValidUTF8(string(body), string(utf8.RuneError))
+}
+
+// Fail sets the action of a webhook that was rejected or failed and why
+func (e *PaymentEvent) Fail(action string, err error) {
+	e.Action = action
+	message := err.Error()
+	e.Error = &message
+}
+
+// MaxPaymentEventPayload caps the stored body of a webhook, unauthenticated requests can send anything
+const MaxPaymentEventPayload = 64 << 10
+
+// TableName specifies the table name for PaymentEvent model
+func (PaymentEvent) TableName() string {
+	return "payment_events"
+}
+
+// OrderRefund records a refund issued for a paid order
+type OrderRefund struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID    int64     `json:"order_id" gorm:"not null;index"`
+	Amount     float64   `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Reason     *string   `json:"reason,omitempty" gorm:"type:varchar(255)"`
+	GatewayRef *string   `json:"gateway_ref,omitempty" gorm:"type:varchar(255)"`
+	RefundedBy string    `json:"refunded_by" gorm:"type:varchar(100);not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for OrderRefund model
+func (OrderRefund) TableName() string {
+	return "order_refunds"
+}
+
+// Settlement report statuses
+const (
+	SettlementStatusBalanced = "BALANCED" // every paid order was settled with the same amount
+	SettlementStatusMismatch = "MISMATCH"
+)
+
+// Settlement mismatch kinds
+const (
+	MismatchNotSettled     = "NOT_SETTLED"     // paid locally but not settled on the gateway
+	MismatchMissingLocally = "MISSING_LOCALLY" // settled on the gateway but not paid locally
+	MismatchAmount         = "AMOUNT_MISMATCH" // settled with another amount than the order
+)
+
+// SettlementDayLayout is the format of a settlement day in URLs
+const SettlementDayLayout = "2006-01-02"
+
+// SettlementReport is the end-of-day reconciliation of the paid orders against the gateways
+type SettlementReport struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ReportDate    time.Time `json:"report_date" gorm:"type:date;not null;uniqueIndex"`
+	PaidOrders    int       `json:"paid_orders" gorm:"not null"`
+	PaidAmount    float64   `json:"paid_amount" gorm:"type:decimal(14,2);not null"`
+	SettledOrders int       `json:"settled_orders" gorm:"not null"`
+	SettledAmount float64   `json:"settled_amount" gorm:"type:decimal(14,2);not null"`
+	Mismatches    int       `json:"mismatches" gorm:"not null"`
+	Unchecked     int       `json:"unchecked" gorm:"not null"` // orders of gateways that cannot report settlements
+	Status        string    `json:"status" gorm:"type:enum('BALANCED','MISMATCH');not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	MismatchList []SettlementMismatch `json:"mismatch_list,omitempty" gorm:"foreignKey:ReportID"`
+}
+
+// TableName specifies the table name for SettlementReport model
+func (SettlementReport) TableName() string {
+	return "settlement_reports"
+}
+
+// SettlementMismatch is an order whose local and gateway state disagree
+type SettlementMismatch struct {
+	ID              int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ReportID        int64     `json:"-" gorm:"not null;index"`
+	OrderID         int64     `json:"order_id" gorm:"not null;index"`
+	PaymentProvider string    `json:"payment_provider" gorm:"type:varchar(32);not null"`
+	Kind            string    `json:"kind" gorm:"type:enum('NOT_SETTLED','MISSING_LOCALLY','AMOUNT_MISMATCH');not null"`
+	LocalStatus     string    `json:"local_status" gorm:"type:varchar(20);not null"`
+	GatewayStatus   string    `json:"gateway_status" gorm:"type:varchar(50);not null"` // empty when the gateway has no transaction
+	LocalAmount     float64   `json:"local_amount" gorm:"type:decimal(10,2);not null"`
+	GatewayAmount   float64   `json:"gateway_amount" gorm:"type:decimal(10,2);not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for SettlementMismatch model
+func (SettlementMismatch) TableName() string {
+	return "settlement_mismatches"
+}
+
+// OfflineLicense allows a downloaded movie to be played without a connection until ExpiresAt
+type OfflineLicense struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	LicenseID    string     `json:"license_id" gorm:"type:varchar(36);not null;uniqueIndex"`
+	UserExtID    string     `json:"user_ext_id" gorm:"not null;index;column:user_ext_id"`
+	MovieID      int64      `json:"movie_id" gorm:"not null"`
+	AccessID     *int64     `json:"-" gorm:"index"` // access the license was issued or last renewed under
+	DeviceID     string     `json:"device_id" gorm:"type:varchar(255);not null"`
+	IssuedAt     time.Time  `json:"issued_at" gorm:"autoCreateTime"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	RenewedAt    *time.Time `json:"renewed_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"` // NOT NULL = on the revocation list
+	RevokeReason *string    `json:"revoke_reason,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for OfflineLicense model
+func (OfflineLicense) TableName() string {
+	return "offline_licenses"
+}
+
+// StreamSession is a device streaming a movie, it counts against the concurrent stream limit
+// while it is not ended and was seen within the idle timeout
+type StreamSession struct {
+	ID                int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SessionID         string     `json:"session_id" gorm:"type:varchar(36);not null;uniqueIndex"`
+	UserExtID         string     `json:"user_ext_id" gorm:"not null;column:user_ext_id"`
+	MovieID           int64      `json:"movie_id" gorm:"not null"`
+	DeviceFingerprint string     `json:"device_fingerprint" gorm:"type:varchar(64);not null"`
+	IPAddress         string     `json:"ip_address" gorm:"type:varchar(45);not null"`
+	UserAgent         string     `json:"user_agent" gorm:"type:varchar(255);not null"`
+	StartedAt         time.Time  `json:"started_at" gorm:"not null"`
+	LastSeenAt        time.Time  `json:"last_seen_at" gorm:"not null"`
+	EndedAt           *time.Time `json:"ended_at,omitempty"` // set when the user kicked the device
+	EndReason         *string    `json:"end_reason,omitempty" gorm:"type:varchar(50)"`
+}
+
+// TableName specifies the table name for StreamSession model
+func (StreamSession) TableName() string {
+	return "stream_sessions"
+}
+
+// StreamDevice identifies the device of a streaming request
+type StreamDevice struct {
+	Fingerprint string // X-Device-ID header, or a hash of the user agent
+	IPAddress   string
+	UserAgent   string
+	Plan        string // user role, acts as the plan for the rental device limit
+}
+
+// AccessDevice is a device that streamed an access grant, counted against the rental device limit
+type AccessDevice struct {
+	ID                int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccessID          int64     `json:"access_id" gorm:"not null;uniqueIndex:idx_access_devices_access_device"`
+	DeviceFingerprint string    `json:"device_fingerprint" gorm:"type:varchar(64);not null;uniqueIndex:idx_access_devices_access_device"`
+	UserAgent         string    `json:"user_agent" gorm:"type:varchar(255);not null"`
+	FirstSeenAt       time.Time `json:"first_seen_at" gorm:"not null"`
+	LastSeenAt        time.Time `json:"last_seen_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for AccessDevice model
+func (AccessDevice) TableName() string {
+	return "access_devices"
+}
+
+// StreamSessionResponse is an active streaming session shown to the user
+type StreamSessionResponse struct {
+	SessionID  string    `json:"session_id"`
+	MovieID    int64     `json:"movie_id"`
+	MovieTitle string    `json:"movie_title,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"` // the device making the request
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// WatchProgress stores the last playback position of a user for a movie
+type WatchProgress struct {
+	ID              int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID       string    `json:"user_ext_id" gorm:"not null;column:user_ext_id"`
+	MovieID         int64     `json:"movie_id" gorm:"not null"`
+	PositionSeconds int       `json:"position_seconds" gorm:"not null;default:0"`
+	DurationSeconds int       `json:"duration_seconds" gorm:"not null;default:0"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for WatchProgress model
+func (WatchProgress) TableName() string {
+	return "watch_progress"
+}
+
+// ScreenerViewWindow is how long a started screener view can play, seeking and pausing included
+const ScreenerViewWindow = 6 * time.Hour
+
+// Screener is a shareable link to watch a movie without an account, e.g. an unreleased title sent to a
+// distributor. It allows MaxViews views between ValidFrom and ValidUntil, only the hash of its token is stored.
+type Screener struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	MovieID    int64      `json:"movie_id" gorm:"not null;index"`
+	Label      string     `json:"label" gorm:"type:varchar(255);not null"` // who the link was sent to
+	TokenHash  string     `json:"-" gorm:"type:char(64);not null;unique"`
+	MaxViews   int        `json:"max_views" gorm:"not null"`
+	ViewsUsed  int        `json:"views_used" gorm:"not null;default:0"`
+	ValidFrom  time.Time  `json:"valid_from" gorm:"not null"`
+	ValidUntil time.Time  `json:"valid_until" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy  *string    `json:"revoked_by,omitempty" gorm:"type:varchar(50)"`
+	CreatedBy  string     `json:"created_by" gorm:"type:varchar(50);not null"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for Screener model
+func (Screener) TableName() string {
+	return "screeners"
+}
+
+// ScreenerView is a view started through a screener link, kept as the log distributors are shown
+type ScreenerView struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ScreenerID int64     `json:"screener_id" gorm:"not null;index"`
+	ViewID     string    `json:"view_id" gorm:"type:varchar(36);not null;uniqueIndex"` // part of the playback URLs
+	IPAddress  string    `json:"ip_address" gorm:"type:varchar(45);not null"`
+	UserAgent  string    `json:"user_agent" gorm:"type:varchar(255);not null"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null"`
+	LastSeenAt time.Time `json:"last_seen_at" gorm:"not null"` // last playlist or segment request
+}
+
+// TableName specifies the table name for ScreenerView model
+func (ScreenerView) TableName() string {
+	return "screener_views"
+}
+
 // CreateOrderRequest represents the request to create a new order
 type CreateOrderRequest struct {
-	MovieID int64 `json:"movie_id" validate:"required,gt=0"`
+	MovieID int64     `json:"movie_id" validate:"required,gt=0"`
+	Type    OrderType `json:"type" validate:"omitempty,oneof=rental purchase"` // defaults to rental
+	Region  string    `json:"-"`                                               // client's country, set by the handler
 }
 
 // CreateOrderResponse represents the response after creating an order
 type CreateOrderResponse struct {
-	OrderID     int64   `json:"order_id"`
-	CheckoutURL string  `json:"checkout_url"`
-	Amount      float64 `json:"amount"`
-	Message     string  `json:"message"`
+	OrderID     int64     `json:"order_id"`
+	OrderType   OrderType `json:"order_type"`
+	CheckoutURL string    `json:"checkout_url"`
+	Amount      float64   `json:"amount"`
+	ExpiresAt   time.Time `json:"expires_at"`         // the checkout link stops accepting payments
+	ExpiresIn   int64     `json:"expires_in_seconds"` // time left to pay
+	Existing    bool      `json:"existing,omitempty"` // the user's pending order for the movie, no new order was created
+	Message     string    `json:"message"`
 }
 
 // OrderListResponse represents a single order in list view
@@ -73,9 +477,14 @@ type OrderListResponse struct {
 	MovieID           int64         `json:"movie_id"`
 	MovieTitle        string        `json:"movie_title"`
 	Amount            float64       `json:"amount"`
+	OrderType         OrderType     `json:"order_type"`
 	PaymentStatus     PaymentStatus `json:"payment_status"`
 	PaymentGatewayRef string        `json:"payment_gateway_ref,omitempty"`
 	PaidAt            *time.Time    `json:"paid_at,omitempty"`
+	InvoiceNumber     *string       `json:"invoice_number,omitempty"`
+	SimulatedBy       *string       `json:"simulated_by,omitempty"`
+	ExpiresAt         *time.Time    `json:"expires_at,omitempty"`
+	ExpiresIn         *int64        `json:"expires_in_seconds,omitempty"` // only set while PENDING
 	CreatedAt         time.Time     `json:"created_at"`
 }
 
@@ -88,11 +497,15 @@ type OrderDetailResponse struct {
 	MovieID           int64         `json:"movie_id"`
 	MovieTitle        string        `json:"movie_title"`
 	Amount            float64       `json:"amount"`
+	OrderType         OrderType     `json:"order_type"`
 	PaymentStatus     PaymentStatus `json:"payment_status"`
 	PaymentGatewayRef string        `json:"payment_gateway_ref,omitempty"`
 	CheckoutURL       string        `json:"checkout_url,omitempty"`
 	PaidAt            *time.Time    `json:"paid_at,omitempty"`
+	InvoiceNumber     *string       `json:"invoice_number,omitempty"`
+	SimulatedBy       *string       `json:"simulated_by,omitempty"`
 	ExpiresAt         *time.Time    `json:"expires_at,omitempty"`
+	ExpiresIn         *int64        `json:"expires_in_seconds,omitempty"` // only set while PENDING
 	CreatedAt         time.Time     `json:"created_at"`
 	UpdatedAt         time.Time     `json:"updated_at"`
 }
@@ -111,9 +524,272 @@ type PaginationMeta struct {
 	PerPage     int   `json:"per_page"`
 }
 
+// RefundOrderRequest represents an admin refund of a paid order
+type RefundOrderRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// RefundResponse represents a processed refund
+type RefundResponse struct {
+	RefundID   int64     `json:"refund_id"`
+	OrderID    int64     `json:"order_id"`
+	Amount     float64   `json:"amount"`
+	GatewayRef string    `json:"gateway_ref,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OfflineLicenseRequest identifies the device holding the downloaded movie
+type OfflineLicenseRequest struct {
+	DeviceID string `json:"device_id" validate:"required,max=255"`
+}
+
+// OfflineLicenseResponse represents an issued or renewed offline license
+type OfflineLicenseResponse struct {
+	LicenseID string    `json:"license_id"`
+	MovieID   int64     `json:"movie_id"`
+	DeviceID  string    `json:"device_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RevokedLicense is a single entry of the offline revocation list
+type RevokedLicense struct {
+	LicenseID string    `json:"license_id"`
+	MovieID   int64     `json:"movie_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// RevocationListResponse lists licenses that downloads must stop playing
+type RevocationListResponse struct {
+	Revoked     []RevokedLicense `json:"revoked"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// Access types returned by the entitlement endpoint
+const (
+	AccessTypePurchase = "PURCHASE" // permanent access
+	AccessTypeRental   = "RENTAL"   // access with an expiry
+)
+
+// SaveProgressRequest represents a playback position update from the player
+type SaveProgressRequest struct {
+	PositionSeconds int `json:"position_seconds" validate:"min=0"`
+	DurationSeconds int `json:"duration_seconds" validate:"omitempty,min=0"`
+}
+
+// GrantAccessRequest gives a user complimentary access to a movie until ExpiresAt
+type GrantAccessRequest struct {
+	UserExtID string    `json:"user_ext_id" validate:"required,max=100"`
+	MovieID   int64     `json:"movie_id" validate:"required,gt=0"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+	Reason    string    `json:"reason" validate:"max=255"`
+}
+
+// RevokeAccessRequest ends movie access before it expires, e.g. after a chargeback
+type RevokeAccessRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// AccessDetailResponse is movie access with the log of the admins who granted or revoked it
+type AccessDetailResponse struct {
+	UserMovieAccess
+	AuditLogs []AccessAuditLog `json:"audit_logs"`
+}
+
+// CreateScreenerRequest represents a new screener link of a movie, ValidFrom defaults to now
+type CreateScreenerRequest struct {
+	Label      string     `json:"label" validate:"required,max=255"`
+	MaxViews   int        `json:"max_views" validate:"required,min=1,max=100"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil time.Time  `json:"valid_until" validate:"required"`
+}
+
+// ScreenerCreatedResponse is a new screener with its link, the token is only shown once
+type ScreenerCreatedResponse struct {
+	Screener
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// ScreenerInfoResponse is what a screener link shows before a view is started
+type ScreenerInfoResponse struct {
+	MovieID        int64     `json:"movie_id"`
+	MovieTitle     string    `json:"movie_title"`
+	Label          string    `json:"label"`
+	ViewsRemaining int       `json:"views_remaining"`
+	ValidFrom      time.Time `json:"valid_from"`
+	ValidUntil     time.Time `json:"valid_until"`
+}
+
+// ScreenerViewResponse is a started screener view with its playback URLs
+type ScreenerViewResponse struct {
+	ViewID         string          `json:"view_id"`
+	ProxyURL       string          `json:"proxy_url"`
+	ExpiresAt      time.Time       `json:"expires_at"` // the view stops playing afterwards
+	ViewsRemaining int             `json:"views_remaining"`
+	Subtitles      []SubtitleTrack `json:"subtitles,omitempty"`
+}
+
+// ProgressResponse represents the user's playback progress for a movie
+type ProgressResponse struct {
+	PositionSeconds int       `json:"position_seconds"`
+	DurationSeconds int       `json:"duration_seconds"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PendingOrderResponse represents an unpaid order the user can still complete
+type PendingOrderResponse struct {
+	OrderID     int64      `json:"order_id"`
+	CheckoutURL string     `json:"checkout_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ExpiresIn   *int64     `json:"expires_in_seconds,omitempty"`
+}
+
+// EntitlementResponse describes what the current user can do with a movie
+type EntitlementResponse struct {
+	MovieID         int64                 `json:"movie_id"`
+	HasAccess       bool                  `json:"has_access"`
+	AccessType      string                `json:"access_type,omitempty"`
+	AccessExpiresAt *time.Time            `json:"access_expires_at,omitempty"`
+	Progress        *ProgressResponse     `json:"progress,omitempty"`
+	PendingOrder    *PendingOrderResponse `json:"pending_order,omitempty"`
+}
+
 // StreamURLResponse represents the response for streaming URL request
 type StreamURLResponse struct {
-	HLSURL          string     `json:"hls_url"`
-	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"`
-	Message         string     `json:"message"`
+	HLSURL          string              `json:"hls_url"`
+	ProxyURL        string              `json:"proxy_url"`
+	DASHURL         string              `json:"dash_manifest_url,omitempty"` // empty when only HLS was published
+	DASHProxyURL    string              `json:"dash_proxy_url,omitempty"`
+	Capabilities    *DeviceCapabilities `json:"capabilities,omitempty"`
+	AccessExpiresAt *time.Time          `json:"access_expires_at,omitempty"`
+	SessionID       string              `json:"session_id,omitempty"`
+	Subtitles       []SubtitleTrack     `json:"subtitles,omitempty"`
+	AudioTracks     []AudioTrack        `json:"audio_tracks,omitempty"`
+	Message         string              `json:"message"`
+}
+
+// Audio track kinds, uploaded tracks use the kind they were uploaded with
+const (
+	AudioKindSource      = "SOURCE"
+	AudioKindDub         = "DUB"
+	AudioKindDescription = "AUDIO_DESCRIPTION"
+)
+
+// WorldwideRegion is the region of licensing windows valid in every country
+const WorldwideRegion = "WW"
+
+// LicenseWindow is the period a movie may be ordered in a region, WW windows apply to every region
+type LicenseWindow struct {
+	Region   string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// AudioTrack is a selectable audio language of a movie.
+// Tracks of the source are in the output versions, uploaded tracks are added to the master playlist by the proxy.
+type AudioTrack struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Default  bool   `json:"default"`
+	URL      string `json:"url,omitempty"` // media playlist through the HLS proxy, empty for tracks of the source
+	Playlist string `json:"-"`             // uploaded tracks only, relative to the movie directory
+}
+
+// SubtitleTrack is a WebVTT subtitle of a movie, also announced in the HLS master playlist
+type SubtitleTrack struct {
+	Language string `json:"language"`
+	Label    string `json:"label"`
+	Default  bool   `json:"default"`
+	URL      string `json:"url"` // WebVTT file through the HLS proxy
+}
+
+// Video codecs a client can declare support for
+const (
+	CodecH264 = "h264"
+	CodecHEVC = "hevc"
+)
+
+// DeviceCapabilities are client hints used to tailor the HLS master playlist to the device
+type DeviceCapabilities struct {
+	Codecs        []string `json:"codecs,omitempty"`         // e.g. h264, hevc
+	MaxResolution int      `json:"max_resolution,omitempty"` // max frame height, e.g. 720 (0 = no limit)
+	DRM           []string `json:"drm,omitempty"`            // e.g. widevine, fairplay (streams are currently served clear)
+}
+
+// ParseDeviceCapabilities reads the codecs, max_resolution and drm hints from query params
+// max_resolution accepts "720", "720p" or "1280x720"
+func ParseDeviceCapabilities(query url.Values) (*DeviceCapabilities, error) {
+	caps := &DeviceCapabilities{
+		Codecs: splitHint(query.Get("codecs")),
+		DRM:    splitHint(query.Get("drm")),
+	}
+
+	for _, codec := range caps.Codecs {
+		if codec != CodecH264 && codec != CodecHEVC {
+			return nil, fmt.Errorf("unsupported codec hint: %s", codec)
+		}
+	}
+
+	if res := strings.ToLower(strings.TrimSpace(query.Get("max_resolution"))); res != "" {
+		if _, height, ok := strings.Cut(res, "x"); ok {
+			res = height
+		}
+		height, err := strconv.Atoi(strings.TrimSuffix(res, "p"))
+		if err != nil || height <= 0 {
+			return nil, fmt.Errorf("invalid max_resolution: %s", query.Get("max_resolution"))
+		}
+		caps.MaxResolution = height
+	}
+
+	return caps, nil
+}
+
+// IsEmpty reports whether the client sent no hints at all
+func (d *DeviceCapabilities) IsEmpty() bool {
+	return d == nil || (len(d.Codecs) == 0 && d.MaxResolution == 0 && len(d.DRM) == 0)
+}
+
+// SupportsCodec reports whether the device can decode the codec
+// Clients that send no codec hints are assumed to support H.264 only
+func (d *DeviceCapabilities) SupportsCodec(codec string) bool {
+	if d == nil || len(d.Codecs) == 0 {
+		return codec == CodecH264
+	}
+	for _, c := range d.Codecs {
+		if c == codec {
+			return true
+		}
+	}
+	return false
+}
+
+// Query encodes the hints back into query params, used to carry them to the streaming proxy
+func (d *DeviceCapabilities) Query() url.Values {
+	query := url.Values{}
+	if d == nil {
+		return query
+	}
+	if len(d.Codecs) > 0 {
+		query.Set("codecs", strings.Join(d.Codecs, ","))
+	}
+	if d.MaxResolution > 0 {
+		query.Set("max_resolution", strconv.Itoa(d.MaxResolution))
+	}
+	if len(d.DRM) > 0 {
+		query.Set("drm", strings.Join(d.DRM, ","))
+	}
+	return query
+}
+
+// splitHint splits a comma separated hint into lowercase values
+func splitHint(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
 }
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	movieRepo "github.com/martinmanurung/cinestream/internal/domain/movies/repository"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
 	userRepo "github.com/martinmanurung/cinestream/internal/domain/users/repository"
+	"gorm.io/gorm"
 )
 
 // MovieRepositoryAdapter adapts the movie repository to order usecase interface
@@ -18,24 +22,140 @@ func NewMovieRepositoryAdapter(repo *movieRepo.MovieRepository) *MovieRepository
 }
 
 // FindMovieByID adapts the movie repository method
+// Soft deleted movies are found too, their paid access stays streamable
 func (a *MovieRepositoryAdapter) FindMovieByID(movieID int64) (map[string]interface{}, error) {
-	movie, err := (*a.repo).FindMovieByID(context.Background(), movieID)
+	movie, err := (*a.repo).FindMovieByIDWithDeleted(context.Background(), movieID)
 	if err != nil {
 		return nil, err
 	}
+	if movie == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
 
 	return map[string]interface{}{
 		"id":    movie.ID,
 		"title": movie.Title,
 		"price": movie.Price,
+		// nil when the movie is rental only
+		"purchase_price": movie.PurchasePrice,
+		// nil when checkout links use the configured expiry
+		"payment_expiry_minutes": movie.PaymentExpiry,
+		// nil when the plan's rental device limit applies
+		"rental_device_limit": movie.RentalDeviceLimit,
+		// Taken down titles stay purchasable records but can no longer be played
+		"taken_down": movie.TakenDownAt != nil,
+		// Unpublished or deleted titles are not sold anymore, existing access is kept
+		"orderable": movie.IsPublished && !movie.DeletedAt.Valid,
+		// Soft-launched titles are only sold and streamed to testers and the allowlist
+		"beta_access": movie.BetaAccess,
 	}, nil
 }
 
+// HasBetaAccess reports whether the user is on the allowlist of a soft-launched movie
+func (a *MovieRepositoryAdapter) HasBetaAccess(movieID int64, userExtID string) (bool, error) {
+	return (*a.repo).HasBetaAccess(context.Background(), movieID, userExtID)
+}
+
+// GetMovieUploadStatus returns the processing status of the movie's video, PENDING before anything was uploaded
+func (a *MovieRepositoryAdapter) GetMovieUploadStatus(movieID int64) (string, error) {
+	video, err := (*a.repo).FindMovieVideoByMovieID(context.Background(), movieID)
+	if err != nil {
+		return "", err
+	}
+	if video == nil {
+		return "PENDING", nil
+	}
+	return video.UploadStatus, nil
+}
+
 // GetMovieHLSURL gets the HLS URL for a movie
 func (a *MovieRepositoryAdapter) GetMovieHLSURL(movieID int64) (string, error) {
 	return (*a.repo).GetHLSURL(context.Background(), movieID)
 }
 
+// GetMovieDASHURL gets the DASH manifest of a movie, empty when it is only streamed as HLS
+func (a *MovieRepositoryAdapter) GetMovieDASHURL(movieID int64) (string, error) {
+	return (*a.repo).GetDASHURL(context.Background(), movieID)
+}
+
+// GetMovieContentKey returns the AES-128 key of the movie's HLS segments, nil when they are not encrypted
+func (a *MovieRepositoryAdapter) GetMovieContentKey(movieID int64) ([]byte, error) {
+	return (*a.repo).FindContentKey(context.Background(), movieID)
+}
+
+// GetMovieSubtitles adapts the movie subtitles to subtitle tracks, the URL is left to the usecase
+func (a *MovieRepositoryAdapter) GetMovieSubtitles(movieID int64) ([]orders.SubtitleTrack, error) {
+	subtitles, err := (*a.repo).FindSubtitlesByMovieID(context.Background(), movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]orders.SubtitleTrack, len(subtitles))
+	for i, subtitle := range subtitles {
+		tracks[i] = orders.SubtitleTrack{
+			Language: subtitle.Language,
+			Label:    subtitle.Label,
+			Default:  subtitle.IsDefault,
+		}
+	}
+	return tracks, nil
+}
+
+// GetMovieLicenseWindows adapts the licensing windows of a movie, empty when it is not restricted
+func (a *MovieRepositoryAdapter) GetMovieLicenseWindows(movieID int64) ([]orders.LicenseWindow, error) {
+	windows, err := (*a.repo).FindLicenseWindows(context.Background(), movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]orders.LicenseWindow, len(windows))
+	for i, window := range windows {
+		result[i] = orders.LicenseWindow{
+			Region:   window.Region,
+			StartsAt: window.StartsAt,
+			EndsAt:   window.EndsAt,
+		}
+	}
+	return result, nil
+}
+
+// GetMovieAudioTracks adapts the source audio tracks and the ready uploaded tracks, the URL is left to the usecase
+func (a *MovieRepositoryAdapter) GetMovieAudioTracks(movieID int64) ([]orders.AudioTrack, error) {
+	ctx := context.Background()
+
+	sourceTracks, err := (*a.repo).FindMovieAudioTracks(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := (*a.repo).FindReadyAlternateAudio(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("movie-%d/", movieID)
+	tracks := make([]orders.AudioTrack, 0, len(sourceTracks)+len(uploaded))
+	for _, track := range sourceTracks {
+		tracks = append(tracks, orders.AudioTrack{
+			Language: track.Language,
+			Name:     track.Name,
+			Kind:     orders.AudioKindSource,
+			Default:  track.IsDefault,
+		})
+	}
+	for _, track := range uploaded {
+		if track.PlaylistObject == nil {
+			continue
+		}
+		tracks = append(tracks, orders.AudioTrack{
+			Language: track.Language,
+			Name:     track.Name,
+			Kind:     track.Kind,
+			Playlist: strings.TrimPrefix(*track.PlaylistObject, prefix),
+		})
+	}
+	return tracks, nil
+}
+
 // UserRepositoryAdapter adapts the user repository to order usecase interface
 type UserRepositoryAdapter struct {
 	repo *userRepo.User
@@ -52,6 +172,9 @@ func (a *UserRepositoryAdapter) FindUserByExtID(userExtID string) (map[string]in
 	if err != nil {
 		return nil, err
 	}
+	if user == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
 
 	return map[string]interface{}{
 		"id":     user.ID,
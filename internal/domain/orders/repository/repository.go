@@ -5,22 +5,89 @@ import (
 
 	"github.com/martinmanurung/cinestream/internal/domain/orders"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // OrderRepository defines the interface for order data operations
 type OrderRepository interface {
+	// WithTransaction runs fn with a repository bound to a single database transaction
+	WithTransaction(fn func(txRepo OrderRepository) error) error
+
 	CreateOrder(order *orders.Order) error
 	FindOrderByID(orderID int64) (*orders.Order, error)
 	FindOrdersByUserExtID(userExtID string, page, limit int) ([]orders.Order, int64, error)
-	FindAllOrders(page, limit int, status string) ([]orders.Order, int64, error)
+	FindAllOrders(page, limit int, filter orders.OrderFilter) ([]orders.Order, int64, error)
 	UpdateOrderStatus(orderID int64, status orders.PaymentStatus, paidAt *time.Time) error
+	TransitionOrderStatus(orderID int64, from, to orders.PaymentStatus) (bool, error)
 	UpdateOrderPaymentDetails(orderID int64, paymentRef, checkoutURL string, expiresAt *time.Time) error
 	FindOrderByPaymentRef(paymentRef string) (*orders.Order, error)
+	ApplyPaymentNotification(notification *orders.PaymentNotification, status orders.PaymentStatus, paidAt *time.Time, access *orders.UserMovieAccess) (bool, error)
+	AssignInvoiceNumber(orderID int64, paidAt time.Time) error
+	MarkPaymentSimulated(orderID int64, adminExtID string) error
+	CreatePaymentEvent(event *orders.PaymentEvent) error
+	FindPaymentEvents(orderID int64, limit int) ([]orders.PaymentEvent, error)
 
 	// User movie access operations
 	CreateUserMovieAccess(access *orders.UserMovieAccess) error
 	CheckUserAccess(userExtID string, movieID int64) (*orders.UserMovieAccess, error)
 	FindUserAccessByOrderID(orderID int64) (*orders.UserMovieAccess, error)
+	FindExpiringAccess(from, to time.Time, limit int) ([]orders.ExpiringAccess, error)
+	MarkExpiryReminded(accessID int64, remindedAt time.Time) error
+	FindUserAccessByID(accessID int64) (*orders.UserMovieAccess, error)
+	RevokeUserAccess(accessID int64, adminExtID string, revokedAt time.Time) (bool, error)
+	CreateAccessAuditLog(entry *orders.AccessAuditLog) error
+	FindAccessAuditLogs(accessID int64) ([]orders.AccessAuditLog, error)
+
+	FindPendingOrder(userExtID string, movieID int64) (*orders.Order, error)
+	LockUserOrders(userExtID string) error
+	FindStalePendingOrders(now time.Time, limit int) ([]orders.Order, error)
+	ExpirePendingOrder(orderID int64) (bool, error)
+	CreateOrderRefund(refund *orders.OrderRefund) error
+	RevokeUserAccessByOrderID(orderID int64, revokedAt time.Time) error
+
+	// Settlement reconciliation
+	FindOrdersForSettlement(from, to time.Time) ([]orders.Order, error)
+	SettlementReportExists(day time.Time) (bool, error)
+	SaveSettlementReport(report *orders.SettlementReport) error
+	FindSettlementReports(from, to time.Time) ([]orders.SettlementReport, error)
+	FindSettlementReport(day time.Time) (*orders.SettlementReport, error)
+
+	// Watch progress operations
+	UpsertWatchProgress(progress *orders.WatchProgress) error
+	FindWatchProgress(userExtID string, movieID int64) (*orders.WatchProgress, error)
+
+	// Offline license operations
+	CreateOfflineLicense(license *orders.OfflineLicense) error
+	FindOfflineLicense(licenseID string) (*orders.OfflineLicense, error)
+	RenewOfflineLicense(licenseID string, accessID int64, expiresAt, renewedAt time.Time) error
+	RevokeOfflineLicense(licenseID, reason string, revokedAt time.Time) error
+	FindRevokedOfflineLicenses(userExtID string, since *time.Time) ([]orders.OfflineLicense, error)
+	RevokeOfflineLicensesForAccess(accessID int64, reason string, revokedAt time.Time) error
+
+	// Stream session operations
+	CreateStreamSession(session *orders.StreamSession) error
+	FindLatestStreamSession(userExtID, fingerprint string) (*orders.StreamSession, error)
+	FindActiveStreamSessions(userExtID string, seenSince time.Time) ([]orders.StreamSession, error)
+	TouchStreamSession(id, movieID int64, ipAddress string, seenAt time.Time) error
+	EndStreamSessions(userExtID, sessionID, reason string, endedAt time.Time) (int64, error)
+
+	// Rental device operations
+	FindAccessDevice(accessID int64, fingerprint string) (*orders.AccessDevice, error)
+	CountAccessDevices(accessID int64) (int64, error)
+	CreateAccessDevice(device *orders.AccessDevice) error
+	TouchAccessDevice(id int64, seenAt time.Time) error
+	DeleteAccessDevices(accessID int64) (int64, error)
+
+	// Screener link operations
+	CreateScreener(screener *orders.Screener) error
+	FindScreenersByMovie(movieID int64) ([]orders.Screener, error)
+	FindScreenerByID(id int64) (*orders.Screener, error)
+	FindScreenerByTokenHash(tokenHash string) (*orders.Screener, error)
+	RevokeScreener(id int64, adminExtID string, revokedAt time.Time) (bool, error)
+	StartScreenerView(screenerID int64, view *orders.ScreenerView) (bool, error)
+	FindScreenerView(screenerID int64, viewID string) (*orders.ScreenerView, error)
+	TouchScreenerView(id int64, ipAddress string, seenAt time.Time) error
+	FindScreenerViews(screenerID int64) ([]orders.ScreenerView, error)
 }
 
 type orderRepository struct {
@@ -32,6 +99,13 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
 
+// WithTransaction runs fn inside a database transaction, any error rolls it back
+func (r *orderRepository) WithTransaction(fn func(txRepo OrderRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&orderRepository{db: tx})
+	})
+}
+
 // CreateOrder creates a new order in the database
 func (r *orderRepository) CreateOrder(order *orders.Order) error {
 	return r.db.Create(order).Error
@@ -84,8 +158,8 @@ func (r *orderRepository) FindOrdersByUserExtID(userExtID string, page, limit in
 	return ordersList, total, nil
 }
 
-// FindAllOrders finds all orders with optional status filter and pagination
-func (r *orderRepository) FindAllOrders(page, limit int, status string) ([]orders.Order, int64, error) {
+// FindAllOrders finds all orders with optional status and invoice number filters and pagination
+func (r *orderRepository) FindAllOrders(page, limit int, filter orders.OrderFilter) ([]orders.Order, int64, error) {
 	var ordersList []orders.Order
 	var total int64
 
@@ -94,8 +168,11 @@ func (r *orderRepository) FindAllOrders(page, limit int, status string) ([]order
 	query := r.db.Model(&orders.Order{})
 
 	// Apply status filter if provided
-	if status != "" {
-		query = query.Where("payment_status = ?", status)
+	if filter.Status != "" {
+		query = query.Where("payment_status = ?", filter.Status)
+	}
+	if filter.Invoice != "" {
+		query = query.Where("invoice_number LIKE ?", filter.Invoice+"%")
 	}
 
 	// Count total
@@ -109,8 +186,11 @@ func (r *orderRepository) FindAllOrders(page, limit int, status string) ([]order
 		Joins("LEFT JOIN movies ON orders.movie_id = movies.id").
 		Joins("LEFT JOIN users ON orders.user_ext_id = users.ext_id")
 
-	if status != "" {
-		queryBuilder = queryBuilder.Where("orders.payment_status = ?", status)
+	if filter.Status != "" {
+		queryBuilder = queryBuilder.Where("orders.payment_status = ?", filter.Status)
+	}
+	if filter.Invoice != "" {
+		queryBuilder = queryBuilder.Where("orders.invoice_number LIKE ?", filter.Invoice+"%")
 	}
 
 	err := queryBuilder.Order("orders.created_at DESC").
@@ -140,6 +220,19 @@ func (r *orderRepository) UpdateOrderStatus(orderID int64, status orders.Payment
 		Updates(updates).Error
 }
 
+// TransitionOrderStatus moves an order from one payment status to another.
+// Returns false when the order was no longer in the from status, e.g. a webhook changed it meanwhile.
+func (r *orderRepository) TransitionOrderStatus(orderID int64, from, to orders.PaymentStatus) (bool, error) {
+	result := r.db.Model(&orders.Order{}).
+		Where("id = ? AND payment_status = ?", orderID, from).
+		Update("payment_status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateOrderPaymentDetails updates payment gateway reference, checkout URL, and expiration
 func (r *orderRepository) UpdateOrderPaymentDetails(orderID int64, paymentRef, checkoutURL string, expiresAt *time.Time) error {
 	updates := map[string]interface{}{
@@ -183,8 +276,10 @@ func (r *orderRepository) CreateUserMovieAccess(access *orders.UserMovieAccess)
 func (r *orderRepository) CheckUserAccess(userExtID string, movieID int64) (*orders.UserMovieAccess, error) {
 	var access orders.UserMovieAccess
 
+	// Permanent access first, then the rental that runs longest
 	err := r.db.Where("user_ext_id = ? AND movie_id = ?", userExtID, movieID).
 		Where("access_expires_at IS NULL OR access_expires_at > ?", time.Now()).
+		Order("access_expires_at IS NOT NULL, access_expires_at DESC").
 		First(&access).Error
 
 	if err != nil {
@@ -205,3 +300,621 @@ func (r *orderRepository) FindUserAccessByOrderID(orderID int64) (*orders.UserMo
 
 	return &access, nil
 }
+
+// FindUserAccessByID finds user movie access by ID
+func (r *orderRepository) FindUserAccessByID(accessID int64) (*orders.UserMovieAccess, error) {
+	var access orders.UserMovieAccess
+
+	if err := r.db.Where("id = ?", accessID).First(&access).Error; err != nil {
+		return nil, err
+	}
+
+	return &access, nil
+}
+
+// RevokeUserAccess ends access that is still running, false when it already ended
+func (r *orderRepository) RevokeUserAccess(accessID int64, adminExtID string, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&orders.UserMovieAccess{}).
+		Where("id = ? AND (access_expires_at IS NULL OR access_expires_at > ?)", accessID, revokedAt).
+		Updates(map[string]interface{}{
+			"access_expires_at": revokedAt,
+			"revoked_at":        revokedAt,
+			"revoked_by":        adminExtID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateAccessAuditLog records an admin granting or revoking access
+func (r *orderRepository) CreateAccessAuditLog(entry *orders.AccessAuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// FindAccessAuditLogs returns the audit log of an access, oldest first
+func (r *orderRepository) FindAccessAuditLogs(accessID int64) ([]orders.AccessAuditLog, error) {
+	var entries []orders.AccessAuditLog
+	err := r.db.Where("access_id = ?", accessID).Order("created_at ASC, id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// FindExpiringAccess returns access ending in (from, to] whose user was not reminded yet.
+// Disabled accounts and users who turned off email notifications are left out.
+func (r *orderRepository) FindExpiringAccess(from, to time.Time, limit int) ([]orders.ExpiringAccess, error) {
+	var expiring []orders.ExpiringAccess
+
+	err := r.db.Table("user_movie_access").
+		Select("user_movie_access.id AS access_id, user_movie_access.access_expires_at, "+
+			"movies.title AS movie_title, users.name AS user_name, users.email AS user_email").
+		Joins("JOIN movies ON movies.id = user_movie_access.movie_id").
+		Joins("JOIN users ON users.ext_id = user_movie_access.user_ext_id").
+		Joins("LEFT JOIN user_preferences ON user_preferences.user_ext_id = user_movie_access.user_ext_id").
+		Where("user_movie_access.access_expires_at > ? AND user_movie_access.access_expires_at <= ?", from, to).
+		Where("user_movie_access.expiry_reminded_at IS NULL AND users.disabled_at IS NULL").
+		Where("(user_preferences.email_notifications IS NULL OR user_preferences.email_notifications = ?)", true).
+		Order("user_movie_access.access_expires_at ASC").
+		Limit(limit).
+		Scan(&expiring).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return expiring, nil
+}
+
+// MarkExpiryReminded records that the user was reminded that the access ends soon
+func (r *orderRepository) MarkExpiryReminded(accessID int64, remindedAt time.Time) error {
+	return r.db.Model(&orders.UserMovieAccess{}).
+		Where("id = ?", accessID).
+		Update("expiry_reminded_at", remindedAt).Error
+}
+
+// CreateOfflineLicense creates a new offline license
+func (r *orderRepository) CreateOfflineLicense(license *orders.OfflineLicense) error {
+	return r.db.Create(license).Error
+}
+
+// FindOfflineLicense finds an offline license by its public license ID
+func (r *orderRepository) FindOfflineLicense(licenseID string) (*orders.OfflineLicense, error) {
+	var license orders.OfflineLicense
+
+	err := r.db.Where("license_id = ?", licenseID).First(&license).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &license, nil
+}
+
+// RenewOfflineLicense extends the validity of an offline license under the access that now backs it
+func (r *orderRepository) RenewOfflineLicense(licenseID string, accessID int64, expiresAt, renewedAt time.Time) error {
+	return r.db.Model(&orders.OfflineLicense{}).
+		Where("license_id = ? AND revoked_at IS NULL", licenseID).
+		Updates(map[string]interface{}{
+			"access_id":  accessID,
+			"expires_at": expiresAt,
+			"renewed_at": renewedAt,
+		}).Error
+}
+
+// RevokeOfflineLicense puts an offline license on the revocation list
+func (r *orderRepository) RevokeOfflineLicense(licenseID, reason string, revokedAt time.Time) error {
+	return r.db.Model(&orders.OfflineLicense{}).
+		Where("license_id = ? AND revoked_at IS NULL", licenseID).
+		Updates(map[string]interface{}{
+			"revoked_at":    revokedAt,
+			"revoke_reason": reason,
+		}).Error
+}
+
+// FindRevokedOfflineLicenses returns the user's revoked licenses, optionally only those revoked after since
+func (r *orderRepository) FindRevokedOfflineLicenses(userExtID string, since *time.Time) ([]orders.OfflineLicense, error) {
+	var licenses []orders.OfflineLicense
+
+	query := r.db.Where("user_ext_id = ? AND revoked_at IS NOT NULL", userExtID)
+	if since != nil {
+		query = query.Where("revoked_at > ?", since)
+	}
+
+	if err := query.Order("revoked_at ASC").Find(&licenses).Error; err != nil {
+		return nil, err
+	}
+
+	return licenses, nil
+}
+
+// FindPendingOrder finds the latest unpaid, unexpired order of a user for a movie
+func (r *orderRepository) FindPendingOrder(userExtID string, movieID int64) (*orders.Order, error) {
+	var order orders.Order
+
+	err := r.db.Where("user_ext_id = ? AND movie_id = ? AND payment_status = ?", userExtID, movieID, orders.PaymentStatusPending).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		First(&order).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// LockUserOrders makes other transactions ordering for the user wait until this one ends
+func (r *orderRepository) LockUserOrders(userExtID string) error {
+	var ids []int64
+	return r.db.Table("users").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ext_id = ?", userExtID).
+		Pluck("id", &ids).Error
+}
+
+// UpsertWatchProgress creates or updates the playback position of a user for a movie
+func (r *orderRepository) UpsertWatchProgress(progress *orders.WatchProgress) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_ext_id"}, {Name: "movie_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position_seconds", "duration_seconds", "updated_at"}),
+	}).Create(progress).Error
+}
+
+// FindWatchProgress finds the playback position of a user for a movie
+func (r *orderRepository) FindWatchProgress(userExtID string, movieID int64) (*orders.WatchProgress, error) {
+	var progress orders.WatchProgress
+
+	err := r.db.Where("user_ext_id = ? AND movie_id = ?", userExtID, movieID).First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &progress, nil
+}
+
+// CreatePaymentEvent stores an incoming payment webhook
+func (r *orderRepository) CreatePaymentEvent(event *orders.PaymentEvent) error {
+	return r.db.Create(event).Error
+}
+
+// FindPaymentEvents returns the newest webhooks received for an order
+func (r *orderRepository) FindPaymentEvents(orderID int64, limit int) ([]orders.PaymentEvent, error) {
+	var events []orders.PaymentEvent
+	err := r.db.Where("order_id = ?", orderID).
+		Order("received_at DESC, id DESC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// CreateOrderRefund records a refund for an order
+func (r *orderRepository) CreateOrderRefund(refund *orders.OrderRefund) error {
+	return r.db.Create(refund).Error
+}
+
+// RevokeUserAccessByOrderID ends the movie access granted by an order
+func (r *orderRepository) RevokeUserAccessByOrderID(orderID int64, revokedAt time.Time) error {
+	return r.db.Model(&orders.UserMovieAccess{}).
+		Where("order_id = ?", orderID).
+		Update("access_expires_at", revokedAt).Error
+}
+
+// FindOrdersForSettlement returns the orders to reconcile for [from, to): orders paid in it, including
+// refunded ones, and unpaid orders created in it that got a gateway transaction.
+// Simulated payments never reached the gateway and are left out.
+func (r *orderRepository) FindOrdersForSettlement(from, to time.Time) ([]orders.Order, error) {
+	paid := []orders.PaymentStatus{orders.PaymentStatusPaid, orders.PaymentStatusRefunded}
+
+	var ordersList []orders.Order
+	err := r.db.
+		Where("simulated_by IS NULL").
+		Where(r.db.
+			Where("payment_status IN ? AND paid_at >= ? AND paid_at < ?", paid, from, to).
+			Or("payment_status NOT IN ? AND payment_gateway_ref IS NOT NULL AND created_at >= ? AND created_at < ?", paid, from, to)).
+		Order("id ASC").
+		Find(&ordersList).Error
+	return ordersList, err
+}
+
+// SettlementReportExists reports whether the day was reconciled already
+func (r *orderRepository) SettlementReportExists(day time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&orders.SettlementReport{}).
+		Where("report_date = ?", day.Format(orders.SettlementDayLayout)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SaveSettlementReport stores a report with its mismatches, replacing an earlier report of the same day
+func (r *orderRepository) SaveSettlementReport(report *orders.SettlementReport) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []orders.SettlementReport
+		if err := tx.Where("report_date = ?", report.ReportDate.Format(orders.SettlementDayLayout)).Find(&existing).Error; err != nil {
+			return err
+		}
+		for _, old := range existing {
+			if err := tx.Where("report_id = ?", old.ID).Delete(&orders.SettlementMismatch{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&old).Error; err != nil {
+				return err
+			}
+		}
+
+		// Mismatches are created with the report through the association
+		return tx.Create(report).Error
+	})
+}
+
+// FindSettlementReports returns the reports of the days in [from, to] without their mismatches, newest first
+func (r *orderRepository) FindSettlementReports(from, to time.Time) ([]orders.SettlementReport, error) {
+	var reports []orders.SettlementReport
+	err := r.db.
+		Where("report_date >= ? AND report_date <= ?", from.Format(orders.SettlementDayLayout), to.Format(orders.SettlementDayLayout)).
+		Order("report_date DESC").
+		Find(&reports).Error
+	return reports, err
+}
+
+// FindSettlementReport returns the report of a day with its mismatches
+func (r *orderRepository) FindSettlementReport(day time.Time) (*orders.SettlementReport, error) {
+	var report orders.SettlementReport
+	err := r.db.
+		Preload("MismatchList", func(db *gorm.DB) *gorm.DB { return db.Order("order_id ASC") }).
+		Where("report_date = ?", day.Format(orders.SettlementDayLayout)).
+		First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// RevokeOfflineLicensesForAccess revokes the active offline licenses issued under an access,
+// licenses backed by another order or grant of the same movie stay valid
+func (r *orderRepository) RevokeOfflineLicensesForAccess(accessID int64, reason string, revokedAt time.Time) error {
+	return r.db.Model(&orders.OfflineLicense{}).
+		Where("access_id = ? AND revoked_at IS NULL", accessID).
+		Updates(map[string]interface{}{
+			"revoked_at":    revokedAt,
+			"revoke_reason": reason,
+		}).Error
+}
+
+// FindStalePendingOrders finds PENDING orders whose payment link expired before now
+func (r *orderRepository) FindStalePendingOrders(now time.Time, limit int) ([]orders.Order, error) {
+	var result []orders.Order
+
+	err := r.db.
+		Where("payment_status = ? AND expires_at IS NOT NULL AND expires_at < ?", orders.PaymentStatusPending, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExpirePendingOrder marks an order EXPIRED only if it is still PENDING.
+// Returns false when the order was paid or changed in the meantime.
+func (r *orderRepository) ExpirePendingOrder(orderID int64) (bool, error) {
+	result := r.db.Model(&orders.Order{}).
+		Where("id = ? AND payment_status = ?", orderID, orders.PaymentStatusPending).
+		Update("payment_status", orders.PaymentStatusExpired)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ApplyPaymentNotification records a gateway notification and applies its effect in one transaction.
+// An empty status only records the notification, a non-nil access is granted once per order.
+// Returns false without changing anything when the notification was already processed.
+func (r *orderRepository) ApplyPaymentNotification(notification *orders.PaymentNotification, status orders.PaymentStatus, paidAt *time.Time, access *orders.UserMovieAccess) (bool, error) {
+	applied := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(notification)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // duplicate delivery
+		}
+		applied = true
+
+		switch status {
+		case orders.PaymentStatusPaid:
+			// A capture followed by a settlement must not move paid_at
+			result := tx.Model(&orders.Order{}).
+				Where("id = ? AND payment_status <> ?", notification.OrderID, orders.PaymentStatusPaid).
+				Updates(map[string]interface{}{
+					"payment_status": status,
+					"paid_at":        paidAt,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				if err := assignInvoiceNumber(tx, notification.OrderID, *paidAt); err != nil {
+					return err
+				}
+			}
+		case "":
+		default:
+			// Failures never downgrade an order that was already paid
+			err := tx.Model(&orders.Order{}).
+				Where("id = ? AND payment_status IN ?", notification.OrderID,
+					[]orders.PaymentStatus{orders.PaymentStatusPending, orders.PaymentStatusExpired}).
+				Update("payment_status", status).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if access != nil {
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "order_id"}},
+				DoNothing: true,
+			}).Create(access).Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// MarkPaymentSimulated records the admin who simulated the payment of an order
+func (r *orderRepository) MarkPaymentSimulated(orderID int64, adminExtID string) error {
+	return r.db.Model(&orders.Order{}).
+		Where("id = ?", orderID).
+		Update("simulated_by", adminExtID).Error
+}
+
+// AssignInvoiceNumber gives a paid order the next invoice number of the year it was paid in,
+// call it in the transaction that marks the order paid
+func (r *orderRepository) AssignInvoiceNumber(orderID int64, paidAt time.Time) error {
+	return assignInvoiceNumber(r.db, orderID, paidAt)
+}
+
+// assignInvoiceNumber numbers an order inside the transaction tx, a rollback takes the number back,
+// so the numbers of a year have no gaps. An order that already has a number keeps it.
+func assignInvoiceNumber(tx *gorm.DB, orderID int64, paidAt time.Time) error {
+	// Locking the order first keeps concurrent notifications of the same order from numbering it twice
+	var order orders.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, invoice_number").
+		Where("id = ?", orderID).
+		First(&order).Error
+	if err != nil {
+		return err
+	}
+	if order.InvoiceNumber != nil {
+		return nil
+	}
+
+	// The upsert locks the year's counter until the transaction ends, payments take numbers one at a time
+	year := paidAt.Year()
+	err = tx.Exec("INSERT INTO invoice_sequences (year, last_number) VALUES (?, 1) ON DUPLICATE KEY UPDATE last_number = last_number + 1", year).Error
+	if err != nil {
+		return err
+	}
+	var sequence orders.InvoiceSequence
+	if err := tx.Where("year = ?", year).First(&sequence).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&orders.Order{}).
+		Where("id = ?", orderID).
+		Update("invoice_number", orders.FormatInvoiceNumber(year, sequence.LastNumber)).Error
+}
+
+// CreateStreamSession records a device starting to stream
+func (r *orderRepository) CreateStreamSession(session *orders.StreamSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindLatestStreamSession returns the newest session of a user's device
+func (r *orderRepository) FindLatestStreamSession(userExtID, fingerprint string) (*orders.StreamSession, error) {
+	var session orders.StreamSession
+
+	err := r.db.Where("user_ext_id = ? AND device_fingerprint = ?", userExtID, fingerprint).
+		Order("id DESC").
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// FindActiveStreamSessions returns the user's sessions that are not ended and were seen after seenSince
+func (r *orderRepository) FindActiveStreamSessions(userExtID string, seenSince time.Time) ([]orders.StreamSession, error) {
+	var sessions []orders.StreamSession
+
+	err := r.db.Where("user_ext_id = ? AND ended_at IS NULL AND last_seen_at > ?", userExtID, seenSince).
+		Order("started_at ASC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// TouchStreamSession marks a session as seen, it also follows the device to another movie
+func (r *orderRepository) TouchStreamSession(id, movieID int64, ipAddress string, seenAt time.Time) error {
+	return r.db.Model(&orders.StreamSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"movie_id":     movieID,
+			"ip_address":   ipAddress,
+			"last_seen_at": seenAt,
+		}).Error
+}
+
+// EndStreamSessions ends one session of the user, or all of them when sessionID is empty
+func (r *orderRepository) EndStreamSessions(userExtID, sessionID, reason string, endedAt time.Time) (int64, error) {
+	query := r.db.Model(&orders.StreamSession{}).
+		Where("user_ext_id = ? AND ended_at IS NULL", userExtID)
+	if sessionID != "" {
+		query = query.Where("session_id = ?", sessionID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"ended_at":   endedAt,
+		"end_reason": reason,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// FindAccessDevice returns a device that already streamed the access grant
+func (r *orderRepository) FindAccessDevice(accessID int64, fingerprint string) (*orders.AccessDevice, error) {
+	var device orders.AccessDevice
+
+	err := r.db.Where("access_id = ? AND device_fingerprint = ?", accessID, fingerprint).
+		First(&device).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// CountAccessDevices counts the distinct devices that streamed the access grant
+func (r *orderRepository) CountAccessDevices(accessID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&orders.AccessDevice{}).Where("access_id = ?", accessID).Count(&count).Error
+	return count, err
+}
+
+// CreateAccessDevice records a new device of an access grant, a concurrent insert of the same device is ignored
+func (r *orderRepository) CreateAccessDevice(device *orders.AccessDevice) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(device).Error
+}
+
+// TouchAccessDevice updates the last time a device streamed the access grant
+func (r *orderRepository) TouchAccessDevice(id int64, seenAt time.Time) error {
+	return r.db.Model(&orders.AccessDevice{}).Where("id = ?", id).Update("last_seen_at", seenAt).Error
+}
+
+// DeleteAccessDevices forgets the devices of an access grant, returns how many were removed
+func (r *orderRepository) DeleteAccessDevices(accessID int64) (int64, error) {
+	result := r.db.Where("access_id = ?", accessID).Delete(&orders.AccessDevice{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateScreener stores a new screener link
+func (r *orderRepository) CreateScreener(screener *orders.Screener) error {
+	return r.db.Create(screener).Error
+}
+
+// FindScreenersByMovie returns the screener links of a movie, newest first
+func (r *orderRepository) FindScreenersByMovie(movieID int64) ([]orders.Screener, error) {
+	var screeners []orders.Screener
+
+	err := r.db.Where("movie_id = ?", movieID).
+		Order("id DESC").
+		Find(&screeners).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return screeners, nil
+}
+
+// FindScreenerByID finds a screener link by ID
+func (r *orderRepository) FindScreenerByID(id int64) (*orders.Screener, error) {
+	var screener orders.Screener
+
+	if err := r.db.Where("id = ?", id).First(&screener).Error; err != nil {
+		return nil, err
+	}
+
+	return &screener, nil
+}
+
+// FindScreenerByTokenHash finds the screener link of a token
+func (r *orderRepository) FindScreenerByTokenHash(tokenHash string) (*orders.Screener, error) {
+	var screener orders.Screener
+
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&screener).Error; err != nil {
+		return nil, err
+	}
+
+	return &screener, nil
+}
+
+// RevokeScreener revokes a screener link, false when it was already revoked
+func (r *orderRepository) RevokeScreener(id int64, adminExtID string, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&orders.Screener{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at": revokedAt,
+			"revoked_by": adminExtID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// StartScreenerView uses one view of a screener link and logs it,
+// false when the link was revoked or has no views left in the meantime
+func (r *orderRepository) StartScreenerView(screenerID int64, view *orders.ScreenerView) (bool, error) {
+	started := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The conditional increment keeps concurrent starts from going over max_views
+		result := tx.Model(&orders.Screener{}).
+			Where("id = ? AND revoked_at IS NULL AND views_used < max_views", screenerID).
+			Update("views_used", gorm.Expr("views_used + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		view.ScreenerID = screenerID
+		if err := tx.Create(view).Error; err != nil {
+			return err
+		}
+		started = true
+		return nil
+	})
+	return started, err
+}
+
+// FindScreenerView finds a view started through a screener link
+func (r *orderRepository) FindScreenerView(screenerID int64, viewID string) (*orders.ScreenerView, error) {
+	var view orders.ScreenerView
+
+	err := r.db.Where("screener_id = ? AND view_id = ?", screenerID, viewID).
+		First(&view).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &view, nil
+}
+
+// TouchScreenerView logs the latest playlist or segment request of a screener view
+func (r *orderRepository) TouchScreenerView(id int64, ipAddress string, seenAt time.Time) error {
+	return r.db.Model(&orders.ScreenerView{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"ip_address":   ipAddress,
+			"last_seen_at": seenAt,
+		}).Error
+}
+
+// FindScreenerViews returns the view log of a screener link, oldest first
+func (r *orderRepository) FindScreenerViews(screenerID int64) ([]orders.ScreenerView, error) {
+	var views []orders.ScreenerView
+
+	err := r.db.Where("screener_id = ?", screenerID).
+		Order("started_at ASC").
+		Find(&views).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
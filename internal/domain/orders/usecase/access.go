@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
+	"gorm.io/gorm"
+)
+
+// GrantAccess gives a user complimentary access to a movie until the expiry, recorded in the access audit log
+func (u *orderUsecase) GrantAccess(adminExtID string, req *orders.GrantAccessRequest) (*orders.AccessDetailResponse, error) {
+	if !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidAccessExpiry
+	}
+
+	if _, err := u.userRepo.FindUserByExtID(req.UserExtID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if _, err := u.movieRepo.FindMovieByID(req.MovieID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMovieNotFound
+		}
+		return nil, fmt.Errorf("failed to get movie: %w", err)
+	}
+
+	expiresAt := req.ExpiresAt
+	access := &orders.UserMovieAccess{
+		UserExtID:       req.UserExtID,
+		MovieID:         req.MovieID,
+		GrantedBy:       &adminExtID,
+		AccessExpiresAt: &expiresAt,
+	}
+	entry := &orders.AccessAuditLog{
+		UserExtID:  req.UserExtID,
+		MovieID:    req.MovieID,
+		Event:      orders.AccessAuditGranted,
+		ActorExtID: adminExtID,
+		Reason:     optionalReason(req.Reason),
+	}
+	err := u.orderRepo.WithTransaction(func(txRepo orderRepository.OrderRepository) error {
+		if err := txRepo.CreateUserMovieAccess(access); err != nil {
+			return err
+		}
+		entry.AccessID = access.ID
+		return txRepo.CreateAccessAuditLog(entry)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant access: %w", err)
+	}
+
+	return &orders.AccessDetailResponse{UserMovieAccess: *access, AuditLogs: []orders.AccessAuditLog{*entry}}, nil
+}
+
+// GetAccess returns movie access with the log of the admins who granted or revoked it
+func (u *orderUsecase) GetAccess(accessID int64) (*orders.AccessDetailResponse, error) {
+	access, err := u.findAccess(accessID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := u.orderRepo.FindAccessAuditLogs(accessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access audit logs: %w", err)
+	}
+	if entries == nil {
+		entries = []orders.AccessAuditLog{}
+	}
+
+	return &orders.AccessDetailResponse{UserMovieAccess: *access, AuditLogs: entries}, nil
+}
+
+// RevokeAccess ends running access of any kind, purchased, from a campaign or complimentary.
+// The order behind purchased access is left alone, refunds go through RefundOrder.
+// Offline downloads issued under this access are revoked, those backed by other access stay valid.
+func (u *orderUsecase) RevokeAccess(adminExtID string, accessID int64, req *orders.RevokeAccessRequest) error {
+	access, err := u.findAccess(accessID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = u.orderRepo.WithTransaction(func(txRepo orderRepository.OrderRepository) error {
+		revoked, err := txRepo.RevokeUserAccess(accessID, adminExtID, now)
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return ErrAccessAlreadyEnded
+		}
+		if err := txRepo.RevokeOfflineLicensesForAccess(accessID, "access revoked", now); err != nil {
+			return err
+		}
+		return txRepo.CreateAccessAuditLog(&orders.AccessAuditLog{
+			AccessID:   accessID,
+			UserExtID:  access.UserExtID,
+			MovieID:    access.MovieID,
+			Event:      orders.AccessAuditRevoked,
+			ActorExtID: adminExtID,
+			Reason:     optionalReason(req.Reason),
+		})
+	})
+	if err == ErrAccessAlreadyEnded {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke access: %w", err)
+	}
+	return nil
+}
+
+// findAccess maps a missing access to ErrAccessNotFound
+func (u *orderUsecase) findAccess(accessID int64) (*orders.UserMovieAccess, error) {
+	access, err := u.orderRepo.FindUserAccessByID(accessID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAccessNotFound
+		}
+		return nil, fmt.Errorf("failed to get access: %w", err)
+	}
+	return access, nil
+}
+
+// optionalReason returns nil for an empty reason
+func optionalReason(reason string) *string {
+	if reason == "" {
+		return nil
+	}
+	return &reason
+}
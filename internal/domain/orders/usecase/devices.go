@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"gorm.io/gorm"
+)
+
+// registerAccessDevice records the device streaming an access grant.
+// Devices seen before always pass, a new device is refused once the rental reached its device limit.
+func (u *orderUsecase) registerAccessDevice(access *orders.UserMovieAccess, device orders.StreamDevice) error {
+	now := time.Now()
+
+	known, err := u.orderRepo.FindAccessDevice(access.ID, device.Fingerprint)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to find rental device: %w", err)
+	}
+	if known != nil {
+		if now.Sub(known.LastSeenAt) < sessionTouchInterval {
+			return nil
+		}
+		if err := u.orderRepo.TouchAccessDevice(known.ID, now); err != nil {
+			return fmt.Errorf("failed to update rental device: %w", err)
+		}
+		return nil
+	}
+
+	limit, err := u.rentalDeviceLimit(access.MovieID, device.Plan)
+	if err != nil {
+		return err
+	}
+	if limit > 0 {
+		count, err := u.orderRepo.CountAccessDevices(access.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count rental devices: %w", err)
+		}
+		if count >= int64(limit) {
+			return ErrRentalDeviceLimitReached.WithDetails(fmt.Sprintf("this rental can be played on %d devices", limit))
+		}
+	}
+
+	if err := u.orderRepo.CreateAccessDevice(&orders.AccessDevice{
+		AccessID:          access.ID,
+		DeviceFingerprint: device.Fingerprint,
+		UserAgent:         truncate(device.UserAgent, 255),
+		FirstSeenAt:       now,
+		LastSeenAt:        now,
+	}); err != nil {
+		return fmt.Errorf("failed to record rental device: %w", err)
+	}
+	return nil
+}
+
+// rentalDeviceLimit returns how many devices one rental of the movie can be streamed on, 0 means unlimited.
+// The movie's own limit wins over the limit of the plan, plans without a limit use the default.
+func (u *orderUsecase) rentalDeviceLimit(movieID int64, plan string) (int, error) {
+	movie, err := u.movieRepo.FindMovieByID(movieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrMovieNotFound
+		}
+		return 0, fmt.Errorf("failed to get movie: %w", err)
+	}
+	if limit, _ := movie["rental_device_limit"].(*int); limit != nil && *limit > 0 {
+		return *limit, nil
+	}
+
+	if limit, ok := u.sessions.PlanRentalDevices[strings.ToLower(plan)]; ok {
+		return limit, nil
+	}
+	return u.sessions.RentalDevices, nil
+}
+
+// ResetRentalDevices forgets the devices of an order's access, e.g. after the customer replaced a phone (Admin only)
+func (u *orderUsecase) ResetRentalDevices(orderID int64) (int64, error) {
+	access, err := u.orderRepo.FindUserAccessByOrderID(orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrOrderAccessNotFound
+		}
+		return 0, fmt.Errorf("failed to find access: %w", err)
+	}
+
+	removed, err := u.orderRepo.DeleteAccessDevices(access.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset rental devices: %w", err)
+	}
+	return removed, nil
+}
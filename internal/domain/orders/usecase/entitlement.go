@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"gorm.io/gorm"
+)
+
+// GetEntitlement returns the access state, playback progress and pending order of a user for a movie
+// so the client can render the right call-to-action in a single request
+func (u *orderUsecase) GetEntitlement(userExtID string, movieID int64) (*orders.EntitlementResponse, error) {
+	if _, err := u.movieRepo.FindMovieByID(movieID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMovieNotFound
+		}
+		return nil, fmt.Errorf("failed to get movie: %w", err)
+	}
+
+	result := &orders.EntitlementResponse{MovieID: movieID}
+
+	// 1. Active access (purchase or rental)
+	access, err := u.orderRepo.CheckUserAccess(userExtID, movieID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if access != nil {
+		result.HasAccess = true
+		result.AccessExpiresAt = access.AccessExpiresAt
+		result.AccessType = orders.AccessTypePurchase
+		if access.AccessExpiresAt != nil {
+			result.AccessType = orders.AccessTypeRental
+		}
+	}
+
+	// 2. Playback progress, only meaningful while the user can still watch
+	if result.HasAccess {
+		progress, err := u.orderRepo.FindWatchProgress(userExtID, movieID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to get watch progress: %w", err)
+		}
+		if progress != nil {
+			result.Progress = &orders.ProgressResponse{
+				PositionSeconds: progress.PositionSeconds,
+				DurationSeconds: progress.DurationSeconds,
+				UpdatedAt:       progress.UpdatedAt,
+			}
+		}
+	}
+
+	// 3. Pending order the user can still pay
+	if !result.HasAccess {
+		order, err := u.orderRepo.FindPendingOrder(userExtID, movieID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to get pending order: %w", err)
+		}
+		if order != nil {
+			checkoutURL := ""
+			if order.CheckoutURL != nil {
+				checkoutURL = *order.CheckoutURL
+			}
+			result.PendingOrder = &orders.PendingOrderResponse{
+				OrderID:     order.ID,
+				CheckoutURL: checkoutURL,
+				ExpiresAt:   order.ExpiresAt,
+				ExpiresIn:   order.PaymentExpiresIn(time.Now()),
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// SaveWatchProgress stores the playback position reported by the player
+func (u *orderUsecase) SaveWatchProgress(userExtID string, movieID int64, req *orders.SaveProgressRequest) error {
+	if _, err := u.orderRepo.CheckUserAccess(userExtID, movieID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrAccessRequired
+		}
+		return fmt.Errorf("failed to check access: %w", err)
+	}
+
+	progress := &orders.WatchProgress{
+		UserExtID:       userExtID,
+		MovieID:         movieID,
+		PositionSeconds: req.PositionSeconds,
+		DurationSeconds: req.DurationSeconds,
+	}
+
+	if err := u.orderRepo.UpsertWatchProgress(progress); err != nil {
+		return fmt.Errorf("failed to save watch progress: %w", err)
+	}
+
+	return nil
+}
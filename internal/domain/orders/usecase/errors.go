@@ -0,0 +1,69 @@
+package usecase
+
+import "github.com/martinmanurung/cinestream/pkg/apperr"
+
+// Errors returned to clients, other errors of the order usecase are internal
+var (
+	ErrMovieNotFound       = apperr.NotFound("movie_not_found", nil)
+	ErrMovieUnavailable    = apperr.Forbidden("movie_temporarily_unavailable", nil)
+	ErrMovieNotPurchasable = apperr.Validation("movie_not_available_for_purchase", nil)
+	ErrMovieNotPublished   = apperr.Forbidden("movie_not_published", "the movie is no longer sold")
+	ErrInvalidMoviePrice   = apperr.Conflict("invalid_movie_price", nil)
+	ErrMovieNotLicensed    = apperr.Forbidden("movie_not_licensed_in_region", "the movie is not available in your country")
+	ErrLicenseEndingSoon   = apperr.Forbidden("movie_license_ending", "the movie leaves the catalog soon and can no longer be ordered")
+	ErrUserNotFound        = apperr.NotFound("user_not_found", nil)
+	ErrAccessRequired      = apperr.Forbidden("movie_access_required", "you need to rent this movie first")
+	ErrContentKeyNotFound  = apperr.NotFound("content_key_not_found", "the movie is not encrypted")
+	ErrMovieNotReady       = apperr.Conflict("movie_not_ready", "the movie is still being processed and cannot be ordered yet")
+	ErrMovieVideoFailed    = apperr.Conflict("movie_video_unavailable", "the movie's video could not be processed and cannot be ordered")
+
+	// ErrPaymentsUnavailable is returned while the payment gateway is down, no order is kept
+	ErrPaymentsUnavailable = apperr.Unavailable("payments_temporarily_unavailable", "please try again in a few minutes")
+
+	ErrOrderNotFound    = apperr.NotFound("order_not_found", nil)
+	ErrOrderAlreadyPaid = apperr.Conflict("order_already_paid", nil)
+	ErrOrderNotPending  = apperr.Conflict("order_not_pending", "only pending orders can be cancelled")
+	ErrOrderNotPaid     = apperr.Conflict("order_not_paid", "only paid orders can be refunded")
+
+	// ErrMovieAlreadyOwned is returned for orders that would charge for access the user already has
+	ErrMovieAlreadyOwned = apperr.Conflict("movie_already_owned", "you already have access to this movie")
+	// ErrOtherOrderPending is returned while an order of the other type waits for payment
+	ErrOtherOrderPending = apperr.Conflict("order_pending_for_movie", "pay or cancel your pending order for this movie first")
+
+	ErrSettlementReportNotFound = apperr.NotFound("settlement_report_not_found", "the day was not reconciled yet")
+	ErrInvalidSettlementRange   = apperr.Validation("invalid_settlement_range", "from must not be after to and the range is at most 366 days")
+
+	ErrAccessNotFound      = apperr.NotFound("access_not_found", nil)
+	ErrAccessAlreadyEnded  = apperr.Conflict("access_already_ended", "the access expired or was revoked before")
+	ErrInvalidAccessExpiry = apperr.Validation("invalid_access_expiry", "expires_at must be in the future")
+
+	// ErrOrderAccessNotFound is returned for orders that never granted stream access
+	ErrOrderAccessNotFound = apperr.NotFound("order_access_not_found", nil)
+	// ErrRentalDeviceLimitReached is returned to a new device once a rental was streamed on the maximum number of devices
+	ErrRentalDeviceLimitReached = apperr.Forbidden("rental_device_limit_reached", nil)
+
+	ErrLicenseNotFound       = apperr.NotFound("license_not_found", nil)
+	ErrLicenseOtherDevice    = apperr.Forbidden("license_issued_to_another_device", nil)
+	ErrLicenseRevoked        = apperr.Forbidden("license_revoked", nil)
+	ErrLicenseAlreadyRevoked = apperr.Conflict("license_already_revoked", nil)
+	ErrRentalEnded           = apperr.Forbidden("rental_ended", "rental is no longer active, license revoked")
+
+	// ErrStreamLimitReached is returned when the user already streams on the maximum number of devices
+	ErrStreamLimitReached = apperr.RateLimited("max_concurrent_streams_reached", "stop playback on another device")
+	// ErrStreamSessionEnded is returned to a device the user signed out of streaming
+	ErrStreamSessionEnded = apperr.Forbidden("stream_session_ended", "start playback again")
+	// ErrStreamSessionNotFound is returned when ending a session that is unknown or already ended
+	ErrStreamSessionNotFound = apperr.NotFound("stream_session_not_found", nil)
+
+	ErrScreenerNotFound       = apperr.NotFound("screener_not_found", nil)
+	ErrScreenerRevoked        = apperr.Forbidden("screener_revoked", "the screener link was revoked")
+	ErrScreenerNotYetValid    = apperr.Forbidden("screener_not_yet_valid", "the screener link cannot be watched yet")
+	ErrScreenerExpired        = apperr.Forbidden("screener_expired", "the screener link has expired")
+	ErrScreenerViewsUsed      = apperr.Forbidden("screener_views_used", "every view of the screener link was used")
+	ErrScreenerAlreadyRevoked = apperr.Conflict("screener_already_revoked", nil)
+	ErrInvalidScreenerWindow  = apperr.Validation("invalid_screener_window", "valid_until must be in the future and after valid_from")
+	// ErrScreenerViewNotFound is returned for playback URLs of a view that was never started
+	ErrScreenerViewNotFound = apperr.NotFound("screener_view_not_found", nil)
+	// ErrScreenerViewEnded is returned once a view played for its window, another view has to be started
+	ErrScreenerViewEnded = apperr.Forbidden("screener_view_ended", "start a new view")
+)
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"gorm.io/gorm"
+)
+
+// offlineLicenseTTL is how long a download can be played before the device has to reconnect
+const offlineLicenseTTL = 48 * time.Hour
+
+// IssueOfflineLicense issues a license for a downloaded movie on a device
+func (u *orderUsecase) IssueOfflineLicense(userExtID string, movieID int64, req *orders.OfflineLicenseRequest) (*orders.OfflineLicenseResponse, error) {
+	if err := u.ensureMovieAvailable(userExtID, movieID); err != nil {
+		return nil, err
+	}
+
+	access, err := u.orderRepo.CheckUserAccess(userExtID, movieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAccessRequired
+		}
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+
+	license := &orders.OfflineLicense{
+		LicenseID: uuid.New().String(),
+		UserExtID: userExtID,
+		MovieID:   movieID,
+		AccessID:  &access.ID,
+		DeviceID:  req.DeviceID,
+		ExpiresAt: offlineExpiry(time.Now(), access),
+	}
+
+	if err := u.orderRepo.CreateOfflineLicense(license); err != nil {
+		return nil, fmt.Errorf("failed to create offline license: %w", err)
+	}
+
+	return toOfflineLicenseResponse(license), nil
+}
+
+// RenewOfflineLicense extends a license when the device reconnects and the rental is still active
+// A license whose rental has ended is revoked instead
+func (u *orderUsecase) RenewOfflineLicense(userExtID, licenseID string, req *orders.OfflineLicenseRequest) (*orders.OfflineLicenseResponse, error) {
+	license, err := u.orderRepo.FindOfflineLicense(licenseID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrLicenseNotFound
+		}
+		return nil, fmt.Errorf("failed to get license: %w", err)
+	}
+
+	if license.UserExtID != userExtID {
+		return nil, ErrLicenseNotFound
+	}
+	if license.DeviceID != req.DeviceID {
+		return nil, ErrLicenseOtherDevice
+	}
+	if license.RevokedAt != nil {
+		return nil, ErrLicenseRevoked
+	}
+
+	now := time.Now()
+	access, err := u.orderRepo.CheckUserAccess(userExtID, license.MovieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			if revokeErr := u.orderRepo.RevokeOfflineLicense(licenseID, "rental expired", now); revokeErr != nil {
+				return nil, fmt.Errorf("failed to revoke license: %w", revokeErr)
+			}
+			return nil, ErrRentalEnded
+		}
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+
+	license.ExpiresAt = offlineExpiry(now, access)
+	if err := u.orderRepo.RenewOfflineLicense(licenseID, access.ID, license.ExpiresAt, now); err != nil {
+		return nil, fmt.Errorf("failed to renew license: %w", err)
+	}
+
+	return toOfflineLicenseResponse(license), nil
+}
+
+// GetRevocationList returns the user's revoked licenses that downloads must check before playback
+func (u *orderUsecase) GetRevocationList(userExtID string, since *time.Time) (*orders.RevocationListResponse, error) {
+	licenses, err := u.orderRepo.FindRevokedOfflineLicenses(userExtID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revoked licenses: %w", err)
+	}
+
+	revoked := make([]orders.RevokedLicense, 0, len(licenses))
+	for _, license := range licenses {
+		reason := ""
+		if license.RevokeReason != nil {
+			reason = *license.RevokeReason
+		}
+		revoked = append(revoked, orders.RevokedLicense{
+			LicenseID: license.LicenseID,
+			MovieID:   license.MovieID,
+			RevokedAt: *license.RevokedAt,
+			Reason:    reason,
+		})
+	}
+
+	return &orders.RevocationListResponse{
+		Revoked:     revoked,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// RevokeOfflineLicense revokes a license (admin)
+func (u *orderUsecase) RevokeOfflineLicense(licenseID, reason string) error {
+	license, err := u.orderRepo.FindOfflineLicense(licenseID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrLicenseNotFound
+		}
+		return fmt.Errorf("failed to get license: %w", err)
+	}
+
+	if license.RevokedAt != nil {
+		return ErrLicenseAlreadyRevoked
+	}
+
+	if reason == "" {
+		reason = "revoked by admin"
+	}
+
+	if err := u.orderRepo.RevokeOfflineLicense(licenseID, reason, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke license: %w", err)
+	}
+
+	return nil
+}
+
+// offlineExpiry caps the offline window at the end of the rental
+func offlineExpiry(now time.Time, access *orders.UserMovieAccess) time.Time {
+	expiresAt := now.Add(offlineLicenseTTL)
+	if access.AccessExpiresAt != nil && access.AccessExpiresAt.Before(expiresAt) {
+		expiresAt = *access.AccessExpiresAt
+	}
+	return expiresAt
+}
+
+func toOfflineLicenseResponse(license *orders.OfflineLicense) *orders.OfflineLicenseResponse {
+	return &orders.OfflineLicenseResponse{
+		LicenseID: license.LicenseID,
+		MovieID:   license.MovieID,
+		DeviceID:  license.DeviceID,
+		ExpiresAt: license.ExpiresAt,
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
+	"gorm.io/gorm"
+)
+
+// CancelOrder cancels a pending order of the user and its gateway transaction
+func (u *orderUsecase) CancelOrder(ctx context.Context, userExtID string, orderID int64) error {
+	order, err := u.orderRepo.FindOrderByID(orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrOrderNotFound
+		}
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.UserExtID != userExtID {
+		return ErrOrderNotFound
+	}
+
+	if order.PaymentStatus != orders.PaymentStatusPending {
+		return ErrOrderNotPending
+	}
+
+	paymentService, err := u.payments.Get(order.PaymentProvider)
+	if err != nil {
+		return err
+	}
+	if err := paymentService.CancelTransaction(ctx, order.ID, derefString(order.PaymentGatewayRef)); err != nil {
+		return gatewayError(paymentService, err)
+	}
+
+	// A settlement webhook may have paid the order meanwhile, it must stay PAID then
+	cancelled, err := u.orderRepo.TransitionOrderStatus(order.ID, orders.PaymentStatusPending, orders.PaymentStatusCancelled)
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	if !cancelled {
+		return ErrOrderNotPending
+	}
+
+	return nil
+}
+
+// RefundOrder refunds a paid order through its payment gateway, revokes the movie access and records the refund
+func (u *orderUsecase) RefundOrder(ctx context.Context, adminExtID string, orderID int64, req *orders.RefundOrderRequest) (*orders.RefundResponse, error) {
+	order, err := u.orderRepo.FindOrderByID(orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.PaymentStatus != orders.PaymentStatusPaid {
+		return nil, ErrOrderNotPaid
+	}
+
+	paymentService, err := u.payments.Get(order.PaymentProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Mark the order as refunded while it is still PAID, so concurrent requests cannot both refund it
+	claimed, err := u.orderRepo.TransitionOrderStatus(order.ID, orders.PaymentStatusPaid, orders.PaymentStatusRefunded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update order status: %w", err)
+	}
+	if !claimed {
+		return nil, ErrOrderNotPaid
+	}
+
+	// 2. Refund on the payment gateway, the order is PAID again if it fails
+	gatewayRef, err := paymentService.RefundTransaction(ctx, order.ID, derefString(order.PaymentGatewayRef), order.Amount, req.Reason)
+	if err != nil {
+		if _, releaseErr := u.orderRepo.TransitionOrderStatus(order.ID, orders.PaymentStatusRefunded, orders.PaymentStatusPaid); releaseErr != nil {
+			log.Printf("[ORDER] Failed to restore order %d to PAID after a failed refund: %v", order.ID, releaseErr)
+		}
+		return nil, gatewayError(paymentService, err)
+	}
+
+	// 3. Revoke the access granted by this order, including its offline downloads, and record the refund
+	now := time.Now()
+	refund := &orders.OrderRefund{
+		OrderID:    order.ID,
+		Amount:     order.Amount,
+		GatewayRef: &gatewayRef,
+		RefundedBy: adminExtID,
+	}
+	if req.Reason != "" {
+		refund.Reason = &req.Reason
+	}
+
+	err = u.orderRepo.WithTransaction(func(txRepo orderRepository.OrderRepository) error {
+		access, err := txRepo.FindUserAccessByOrderID(order.ID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get movie access: %w", err)
+		}
+		if access != nil {
+			if err := txRepo.RevokeUserAccessByOrderID(order.ID, now); err != nil {
+				return fmt.Errorf("failed to revoke movie access: %w", err)
+			}
+			if err := txRepo.RevokeOfflineLicensesForAccess(access.ID, "order refunded", now); err != nil {
+				return fmt.Errorf("failed to revoke offline licenses: %w", err)
+			}
+		}
+		if err := txRepo.CreateOrderRefund(refund); err != nil {
+			return fmt.Errorf("failed to record refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		// The money is back with the customer, only the local bookkeeping is missing
+		log.Printf("[ORDER] Order %d refunded on %s as %s but not recorded: %v", order.ID, paymentService.Name(), gatewayRef, err)
+		return nil, err
+	}
+
+	return &orders.RefundResponse{
+		RefundID:   refund.ID,
+		OrderID:    order.ID,
+		Amount:     refund.Amount,
+		GatewayRef: gatewayRef,
+		CreatedAt:  refund.CreatedAt,
+	}, nil
+}
+
+// derefString returns the value of an optional string, empty when nil
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"gorm.io/gorm"
+)
+
+// CreateScreener creates a screener link of a movie whose video is ready, released or not
+func (u *orderUsecase) CreateScreener(adminExtID string, movieID int64, req *orders.CreateScreenerRequest) (*orders.ScreenerCreatedResponse, error) {
+	movie, err := u.movieRepo.FindMovieByID(movieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMovieNotFound
+		}
+		return nil, fmt.Errorf("failed to get movie: %w", err)
+	}
+	if takenDown, _ := movie["taken_down"].(bool); takenDown {
+		return nil, ErrMovieUnavailable
+	}
+
+	uploadStatus, err := u.movieRepo.GetMovieUploadStatus(movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie upload status: %w", err)
+	}
+	switch uploadStatus {
+	case "READY":
+	case "FAILED":
+		return nil, ErrMovieVideoFailed
+	default:
+		return nil, ErrMovieNotReady
+	}
+
+	now := time.Now()
+	validFrom := now
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+	if !req.ValidUntil.After(validFrom) || !req.ValidUntil.After(now) {
+		return nil, ErrInvalidScreenerWindow
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate screener token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	screener := &orders.Screener{
+		MovieID:    movieID,
+		Label:      req.Label,
+		TokenHash:  hashScreenerToken(token),
+		MaxViews:   req.MaxViews,
+		ValidFrom:  validFrom,
+		ValidUntil: req.ValidUntil,
+		CreatedBy:  adminExtID,
+	}
+	if err := u.orderRepo.CreateScreener(screener); err != nil {
+		return nil, fmt.Errorf("failed to create screener: %w", err)
+	}
+
+	return &orders.ScreenerCreatedResponse{
+		Screener: *screener,
+		Token:    token,
+		URL:      "/api/v1/screeners/" + token,
+	}, nil
+}
+
+// GetScreeners returns the screener links of a movie
+func (u *orderUsecase) GetScreeners(movieID int64) ([]orders.Screener, error) {
+	screeners, err := u.orderRepo.FindScreenersByMovie(movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screeners: %w", err)
+	}
+	return screeners, nil
+}
+
+// GetScreenerViews returns the view log of a screener link
+func (u *orderUsecase) GetScreenerViews(screenerID int64) ([]orders.ScreenerView, error) {
+	if _, err := u.findScreener(screenerID); err != nil {
+		return nil, err
+	}
+
+	views, err := u.orderRepo.FindScreenerViews(screenerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screener views: %w", err)
+	}
+	return views, nil
+}
+
+// RevokeScreener revokes a screener link, running views stop on their next playlist/segment request
+func (u *orderUsecase) RevokeScreener(adminExtID string, screenerID int64) error {
+	if _, err := u.findScreener(screenerID); err != nil {
+		return err
+	}
+
+	revoked, err := u.orderRepo.RevokeScreener(screenerID, adminExtID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke screener: %w", err)
+	}
+	if !revoked {
+		return ErrScreenerAlreadyRevoked
+	}
+	return nil
+}
+
+// GetScreener returns what a screener link offers, without using a view
+func (u *orderUsecase) GetScreener(token string) (*orders.ScreenerInfoResponse, error) {
+	screener, movie, err := u.validScreener(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	title, _ := movie["title"].(string)
+	return &orders.ScreenerInfoResponse{
+		MovieID:        screener.MovieID,
+		MovieTitle:     title,
+		Label:          screener.Label,
+		ViewsRemaining: screener.MaxViews - screener.ViewsUsed,
+		ValidFrom:      screener.ValidFrom,
+		ValidUntil:     screener.ValidUntil,
+	}, nil
+}
+
+// StartScreenerView uses one view of a screener link and returns its playback URLs.
+// The view plays for ScreenerViewWindow, seeking and reloading the playlists does not use more views.
+func (u *orderUsecase) StartScreenerView(token string, device orders.StreamDevice, caps *orders.DeviceCapabilities) (*orders.ScreenerViewResponse, error) {
+	now := time.Now()
+	screener, _, err := u.validScreener(token, now)
+	if err != nil {
+		return nil, err
+	}
+	if screener.ViewsUsed >= screener.MaxViews {
+		return nil, ErrScreenerViewsUsed
+	}
+
+	hlsURL, err := u.movieRepo.GetMovieHLSURL(screener.MovieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie stream URL: %w", err)
+	}
+
+	view := &orders.ScreenerView{
+		ViewID:     uuid.New().String(),
+		IPAddress:  device.IPAddress,
+		UserAgent:  truncate(device.UserAgent, 255),
+		StartedAt:  now,
+		LastSeenAt: now,
+	}
+	started, err := u.orderRepo.StartScreenerView(screener.ID, view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start screener view: %w", err)
+	}
+	if !started {
+		// Revoked or used up since it was read
+		return nil, ErrScreenerViewsUsed
+	}
+
+	base := fmt.Sprintf("/api/v1/screeners/%s/views/%s/hls/", token, view.ViewID)
+	proxyURL := base + strings.TrimPrefix(hlsURL, fmt.Sprintf("movie-%d/", screener.MovieID))
+	if !caps.IsEmpty() {
+		proxyURL += "?" + caps.Query().Encode()
+	}
+
+	subtitles, err := u.GetSubtitleTracks(screener.MovieID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subtitles {
+		subtitles[i].URL = base + "subtitles/" + subtitles[i].Language + ".vtt"
+	}
+
+	return &orders.ScreenerViewResponse{
+		ViewID:         view.ViewID,
+		ProxyURL:       proxyURL,
+		ExpiresAt:      screenerViewExpiry(screener, view),
+		ViewsRemaining: screener.MaxViews - screener.ViewsUsed - 1,
+		Subtitles:      subtitles,
+	}, nil
+}
+
+// CheckScreenerView checks a playlist/segment request of a screener view and logs it,
+// returns the movie of the screener
+func (u *orderUsecase) CheckScreenerView(token, viewID, ipAddress string) (int64, error) {
+	now := time.Now()
+	screener, _, err := u.validScreener(token, now)
+	if err != nil {
+		return 0, err
+	}
+
+	view, err := u.orderRepo.FindScreenerView(screener.ID, viewID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrScreenerViewNotFound
+		}
+		return 0, fmt.Errorf("failed to get screener view: %w", err)
+	}
+	if !now.Before(screenerViewExpiry(screener, view)) {
+		return 0, ErrScreenerViewEnded
+	}
+
+	// Segments are requested every few seconds, the log only needs the latest time roughly
+	if now.Sub(view.LastSeenAt) >= sessionTouchInterval || view.IPAddress != ipAddress {
+		if err := u.orderRepo.TouchScreenerView(view.ID, ipAddress, now); err != nil {
+			return 0, fmt.Errorf("failed to log screener view: %w", err)
+		}
+	}
+
+	return screener.MovieID, nil
+}
+
+// GetScreenerContentKey returns the AES-128 key of the movie to a running screener view
+func (u *orderUsecase) GetScreenerContentKey(token, viewID, ipAddress string) ([]byte, error) {
+	movieID, err := u.CheckScreenerView(token, viewID, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := u.movieRepo.GetMovieContentKey(movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrContentKeyNotFound
+	}
+
+	return key, nil
+}
+
+// findScreener finds a screener link by ID for the admin endpoints
+func (u *orderUsecase) findScreener(screenerID int64) (*orders.Screener, error) {
+	screener, err := u.orderRepo.FindScreenerByID(screenerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrScreenerNotFound
+		}
+		return nil, fmt.Errorf("failed to get screener: %w", err)
+	}
+	return screener, nil
+}
+
+// validScreener returns the screener link of a token with its movie, when it is neither revoked nor
+// outside its date range and the movie was not taken down. Soft launch does not apply, screeners are for unreleased titles.
+func (u *orderUsecase) validScreener(token string, now time.Time) (*orders.Screener, map[string]interface{}, error) {
+	screener, err := u.orderRepo.FindScreenerByTokenHash(hashScreenerToken(token))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, ErrScreenerNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get screener: %w", err)
+	}
+
+	switch {
+	case screener.RevokedAt != nil:
+		return nil, nil, ErrScreenerRevoked
+	case now.Before(screener.ValidFrom):
+		return nil, nil, ErrScreenerNotYetValid
+	case !now.Before(screener.ValidUntil):
+		return nil, nil, ErrScreenerExpired
+	}
+
+	movie, err := u.movieRepo.FindMovieByID(screener.MovieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, ErrMovieNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get movie: %w", err)
+	}
+	if takenDown, _ := movie["taken_down"].(bool); takenDown {
+		return nil, nil, ErrMovieUnavailable
+	}
+
+	return screener, movie, nil
+}
+
+// screenerViewExpiry is when a view stops playing, at the latest when the link expires
+func screenerViewExpiry(screener *orders.Screener, view *orders.ScreenerView) time.Time {
+	expiresAt := view.StartedAt.Add(orders.ScreenerViewWindow)
+	if screener.ValidUntil.Before(expiresAt) {
+		return screener.ValidUntil
+	}
+	return expiresAt
+}
+
+// hashScreenerToken returns the SHA256 hex digest stored instead of the raw token
+func hashScreenerToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
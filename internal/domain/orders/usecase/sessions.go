@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval limits how often HLS requests write the last seen time of a session
+const sessionTouchInterval = 30 * time.Second
+
+// Stream session end reasons
+const (
+	sessionEndKicked = "ended_by_user"
+)
+
+// StreamSessionOptions controls the concurrent stream limit and the devices per rental
+type StreamSessionOptions struct {
+	MaxConcurrent int           // devices streaming at the same time, 0 means unlimited
+	IdleTimeout   time.Duration // a session without HLS requests for this long no longer counts
+
+	RentalDevices     int            // distinct devices one rental can be streamed on, 0 means unlimited
+	PlanRentalDevices map[string]int // per plan (lowercase user role) overrides of RentalDevices, movies can override both
+}
+
+// startStreamSession starts a session for the device, or continues the running one.
+// A device whose session was ended gets a new session, subject to the limit.
+func (u *orderUsecase) startStreamSession(userExtID string, movieID int64, device orders.StreamDevice) (*orders.StreamSession, error) {
+	now := time.Now()
+
+	session, err := u.findLatestStreamSession(userExtID, device.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if session != nil && session.EndedAt == nil && u.sessionActive(session, now) {
+		if err := u.touchStreamSession(session, movieID, device, now); err != nil {
+			return nil, err
+		}
+		return session, nil
+	}
+
+	if err := u.checkStreamLimit(userExtID, device.Fingerprint, now); err != nil {
+		return nil, err
+	}
+
+	session = &orders.StreamSession{
+		SessionID:         uuid.New().String(),
+		UserExtID:         userExtID,
+		MovieID:           movieID,
+		DeviceFingerprint: device.Fingerprint,
+		IPAddress:         device.IPAddress,
+		UserAgent:         truncate(device.UserAgent, 255),
+		StartedAt:         now,
+		LastSeenAt:        now,
+	}
+	if err := u.orderRepo.CreateStreamSession(session); err != nil {
+		return nil, fmt.Errorf("failed to create stream session: %w", err)
+	}
+
+	return session, nil
+}
+
+// TouchStreamSession keeps the device's session alive on every proxied playlist/segment.
+// An ended session stops the device's playback, an idle one is resumed when the limit allows.
+func (u *orderUsecase) TouchStreamSession(userExtID string, movieID int64, device orders.StreamDevice) error {
+	now := time.Now()
+
+	session, err := u.findLatestStreamSession(userExtID, device.Fingerprint)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		// Players that skip GET /stream still count against the limits
+		access, err := u.orderRepo.CheckUserAccess(userExtID, movieID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrAccessRequired
+			}
+			return fmt.Errorf("failed to check access: %w", err)
+		}
+		if err := u.registerAccessDevice(access, device); err != nil {
+			return err
+		}
+		_, err = u.startStreamSession(userExtID, movieID, device)
+		return err
+	}
+	if session.EndedAt != nil {
+		return ErrStreamSessionEnded
+	}
+
+	if !u.sessionActive(session, now) {
+		if err := u.checkStreamLimit(userExtID, device.Fingerprint, now); err != nil {
+			return err
+		}
+	}
+
+	return u.touchStreamSession(session, movieID, device, now)
+}
+
+// GetStreamSessions returns the user's active streaming sessions, marking the requesting device
+func (u *orderUsecase) GetStreamSessions(userExtID, currentFingerprint string) ([]orders.StreamSessionResponse, error) {
+	sessions, err := u.orderRepo.FindActiveStreamSessions(userExtID, u.sessionCutoff(time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream sessions: %w", err)
+	}
+
+	result := make([]orders.StreamSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		item := orders.StreamSessionResponse{
+			SessionID:  session.SessionID,
+			MovieID:    session.MovieID,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			Current:    session.DeviceFingerprint == currentFingerprint,
+			StartedAt:  session.StartedAt,
+			LastSeenAt: session.LastSeenAt,
+		}
+		if movie, err := u.movieRepo.FindMovieByID(session.MovieID); err == nil {
+			item.MovieTitle, _ = movie["title"].(string)
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// EndStreamSession kicks a device, its next playlist/segment request is rejected
+// An empty sessionID ends the sessions of every device
+func (u *orderUsecase) EndStreamSession(userExtID, sessionID string) error {
+	ended, err := u.orderRepo.EndStreamSessions(userExtID, sessionID, sessionEndKicked, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to end stream session: %w", err)
+	}
+	if sessionID != "" && ended == 0 {
+		return ErrStreamSessionNotFound
+	}
+	return nil
+}
+
+// checkStreamLimit rejects a new stream when other devices already use every slot
+func (u *orderUsecase) checkStreamLimit(userExtID, fingerprint string, now time.Time) error {
+	if u.sessions.MaxConcurrent <= 0 {
+		return nil
+	}
+
+	active, err := u.orderRepo.FindActiveStreamSessions(userExtID, u.sessionCutoff(now))
+	if err != nil {
+		return fmt.Errorf("failed to check stream sessions: %w", err)
+	}
+
+	devices := make(map[string]bool)
+	for _, session := range active {
+		if session.DeviceFingerprint != fingerprint {
+			devices[session.DeviceFingerprint] = true
+		}
+	}
+	if len(devices) >= u.sessions.MaxConcurrent {
+		return ErrStreamLimitReached
+	}
+	return nil
+}
+
+func (u *orderUsecase) findLatestStreamSession(userExtID, fingerprint string) (*orders.StreamSession, error) {
+	session, err := u.orderRepo.FindLatestStreamSession(userExtID, fingerprint)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get stream session: %w", err)
+	}
+	return session, nil
+}
+
+// touchStreamSession writes the last seen time at most every sessionTouchInterval
+func (u *orderUsecase) touchStreamSession(session *orders.StreamSession, movieID int64, device orders.StreamDevice, now time.Time) error {
+	if session.MovieID == movieID && session.IPAddress == device.IPAddress && now.Sub(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+
+	if err := u.orderRepo.TouchStreamSession(session.ID, movieID, device.IPAddress, now); err != nil {
+		return fmt.Errorf("failed to update stream session: %w", err)
+	}
+	session.MovieID = movieID
+	session.IPAddress = device.IPAddress
+	session.LastSeenAt = now
+	return nil
+}
+
+func (u *orderUsecase) sessionActive(session *orders.StreamSession, now time.Time) bool {
+	return session.LastSeenAt.After(u.sessionCutoff(now))
+}
+
+func (u *orderUsecase) sessionCutoff(now time.Time) time.Time {
+	return now.Add(-u.sessions.IdleTimeout)
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/orders"
+	"gorm.io/gorm"
+)
+
+// maxSettlementRange limits how many daily reports are listed at once
+const maxSettlementRange = 366 * 24 * time.Hour
+
+// GetSettlementReports returns the daily settlement summaries of [from, to], newest first (Admin only)
+func (u *orderUsecase) GetSettlementReports(from, to time.Time) ([]orders.SettlementReport, error) {
+	if to.Before(from) || to.Sub(from) > maxSettlementRange {
+		return nil, ErrInvalidSettlementRange
+	}
+
+	reports, err := u.orderRepo.FindSettlementReports(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement reports: %w", err)
+	}
+	return reports, nil
+}
+
+// GetSettlementReport returns the settlement summary of a day with its mismatches (Admin only)
+func (u *orderUsecase) GetSettlementReport(day time.Time) (*orders.SettlementReport, error) {
+	report, err := u.orderRepo.FindSettlementReport(day)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSettlementReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get settlement report: %w", err)
+	}
+	return report, nil
+}
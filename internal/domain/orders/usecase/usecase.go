@@ -1,13 +1,18 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/martinmanurung/cinestream/internal/domain/orders"
 	orderRepository "github.com/martinmanurung/cinestream/internal/domain/orders/repository"
 	"github.com/martinmanurung/cinestream/internal/platform/payment"
+	"github.com/martinmanurung/cinestream/pkg/constant"
 	"gorm.io/gorm"
 )
 
@@ -15,6 +20,18 @@ import (
 type MovieRepository interface {
 	FindMovieByID(movieID int64) (map[string]interface{}, error)
 	GetMovieHLSURL(movieID int64) (string, error)
+	GetMovieDASHURL(movieID int64) (string, error)
+	GetMovieSubtitles(movieID int64) ([]orders.SubtitleTrack, error)
+	GetMovieAudioTracks(movieID int64) ([]orders.AudioTrack, error)
+	GetMovieLicenseWindows(movieID int64) ([]orders.LicenseWindow, error)
+	GetMovieContentKey(movieID int64) ([]byte, error)
+	HasBetaAccess(movieID int64, userExtID string) (bool, error)
+	GetMovieUploadStatus(movieID int64) (string, error)
+}
+
+// ViewRecorder counts playback starts for the popularity ranking
+type ViewRecorder interface {
+	RecordStart(ctx context.Context, movieID int64, viewerID string) error
 }
 
 // UserRepository defines minimal user repository interface needed by order usecase
@@ -24,57 +41,160 @@ type UserRepository interface {
 
 // OrderUsecase defines the interface for order business logic
 type OrderUsecase interface {
-	CreateOrder(userExtID string, req *orders.CreateOrderRequest) (*orders.CreateOrderResponse, error)
+	CreateOrder(ctx context.Context, userExtID string, req *orders.CreateOrderRequest) (*orders.CreateOrderResponse, error)
 	GetUserOrders(userExtID string, page, limit int) (*orders.OrdersListWrapper, error)
-	GetAllOrders(page, limit int, status string) (*orders.OrdersListWrapper, error)
+	GetAllOrders(page, limit int, filter orders.OrderFilter) (*orders.OrdersListWrapper, error)
 	GetOrderDetail(orderID int64) (*orders.OrderDetailResponse, error)
-	CheckStreamAccess(userExtID string, movieID int64) (*orders.StreamURLResponse, error)
-	SimulatePaymentSuccess(orderID int64) error // For development/testing
+	CheckStreamAccess(userExtID string, movieID int64, caps *orders.DeviceCapabilities, device *orders.StreamDevice) (*orders.StreamURLResponse, error)
+	GetSubtitleTracks(movieID int64) ([]orders.SubtitleTrack, error)
+	GetAudioTracks(movieID int64) ([]orders.AudioTrack, error)
+	GetContentKey(userExtID string, movieID int64) ([]byte, error)
+	SimulatePaymentSuccess(adminExtID string, orderID int64) error // development only, see server.environment
+	CancelOrder(ctx context.Context, userExtID string, orderID int64) error
+	RefundOrder(ctx context.Context, adminExtID string, orderID int64, req *orders.RefundOrderRequest) (*orders.RefundResponse, error)
+	ResetRentalDevices(orderID int64) (int64, error)
+	GetOrderPaymentEvents(orderID int64) ([]orders.PaymentEvent, error)
+
+	// End-of-day settlement reports, reconciled by the worker
+	GetSettlementReports(from, to time.Time) ([]orders.SettlementReport, error)
+	GetSettlementReport(day time.Time) (*orders.SettlementReport, error)
+
+	// Entitlement and playback progress
+	GetEntitlement(userExtID string, movieID int64) (*orders.EntitlementResponse, error)
+	SaveWatchProgress(userExtID string, movieID int64, req *orders.SaveProgressRequest) error
+
+	// Offline downloads
+	IssueOfflineLicense(userExtID string, movieID int64, req *orders.OfflineLicenseRequest) (*orders.OfflineLicenseResponse, error)
+	RenewOfflineLicense(userExtID, licenseID string, req *orders.OfflineLicenseRequest) (*orders.OfflineLicenseResponse, error)
+	GetRevocationList(userExtID string, since *time.Time) (*orders.RevocationListResponse, error)
+	RevokeOfflineLicense(licenseID, reason string) error
+
+	// Streaming sessions
+	TouchStreamSession(userExtID string, movieID int64, device orders.StreamDevice) error
+	GetStreamSessions(userExtID, currentFingerprint string) ([]orders.StreamSessionResponse, error)
+	EndStreamSession(userExtID, sessionID string) error
+
+	// Access managed by admins, e.g. complimentary access or a revocation after a chargeback
+	GrantAccess(adminExtID string, req *orders.GrantAccessRequest) (*orders.AccessDetailResponse, error)
+	GetAccess(accessID int64) (*orders.AccessDetailResponse, error)
+	RevokeAccess(adminExtID string, accessID int64, req *orders.RevokeAccessRequest) error
+
+	// Screener links, watched without an account
+	CreateScreener(adminExtID string, movieID int64, req *orders.CreateScreenerRequest) (*orders.ScreenerCreatedResponse, error)
+	GetScreeners(movieID int64) ([]orders.Screener, error)
+	GetScreenerViews(screenerID int64) ([]orders.ScreenerView, error)
+	RevokeScreener(adminExtID string, screenerID int64) error
+	GetScreener(token string) (*orders.ScreenerInfoResponse, error)
+	StartScreenerView(token string, device orders.StreamDevice, caps *orders.DeviceCapabilities) (*orders.ScreenerViewResponse, error)
+	CheckScreenerView(token, viewID, ipAddress string) (int64, error)
+	GetScreenerContentKey(token, viewID, ipAddress string) ([]byte, error)
 }
 
 type orderUsecase struct {
-	orderRepo      orderRepository.OrderRepository
-	movieRepo      MovieRepository
-	userRepo       UserRepository
-	paymentService payment.PaymentService
+	orderRepo     orderRepository.OrderRepository
+	movieRepo     MovieRepository
+	userRepo      UserRepository
+	payments      *payment.Registry
+	sessions      StreamSessionOptions
+	paymentExpiry time.Duration
+	licenseCutoff time.Duration
+	views         ViewRecorder
 }
 
 // NewOrderUsecase creates a new order usecase
+// paymentExpiry is how long checkout links stay payable when the movie does not set its own,
+// licenseCutoff is how long before a licensing window closes new orders are refused,
+// views counts playback starts and may be nil
 func NewOrderUsecase(
 	orderRepo orderRepository.OrderRepository,
 	movieRepo MovieRepository,
 	userRepo UserRepository,
-	paymentService payment.PaymentService,
+	payments *payment.Registry,
+	sessions StreamSessionOptions,
+	paymentExpiry time.Duration,
+	licenseCutoff time.Duration,
+	views ViewRecorder,
 ) OrderUsecase {
+	if sessions.IdleTimeout <= 0 {
+		sessions.IdleTimeout = 5 * time.Minute
+	}
+	if paymentExpiry <= 0 {
+		paymentExpiry = orders.DefaultPaymentExpiry
+	}
 	return &orderUsecase{
-		orderRepo:      orderRepo,
-		movieRepo:      movieRepo,
-		userRepo:       userRepo,
-		paymentService: paymentService,
+		orderRepo:     orderRepo,
+		movieRepo:     movieRepo,
+		userRepo:      userRepo,
+		payments:      payments,
+		sessions:      sessions,
+		paymentExpiry: paymentExpiry,
+		licenseCutoff: licenseCutoff,
+		views:         views,
 	}
 }
 
 // CreateOrder creates a new order and initiates payment
-func (u *orderUsecase) CreateOrder(userExtID string, req *orders.CreateOrderRequest) (*orders.CreateOrderResponse, error) {
+func (u *orderUsecase) CreateOrder(ctx context.Context, userExtID string, req *orders.CreateOrderRequest) (*orders.CreateOrderResponse, error) {
 	// 1. Get movie details and price
 	movie, err := u.movieRepo.FindMovieByID(req.MovieID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("movie not found")
+			return nil, ErrMovieNotFound
 		}
 		return nil, fmt.Errorf("failed to get movie: %w", err)
 	}
 
+	if orderable, _ := movie["orderable"].(bool); !orderable {
+		return nil, ErrMovieNotPublished
+	}
+	if takenDown, _ := movie["taken_down"].(bool); takenDown {
+		return nil, ErrMovieUnavailable
+	}
+
+	if err := u.ensureBetaAccess(userExtID, req.MovieID, movie); err != nil {
+		return nil, err
+	}
+
+	// Only titles that can be streamed right away are sold
+	uploadStatus, err := u.movieRepo.GetMovieUploadStatus(req.MovieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie upload status: %w", err)
+	}
+	switch uploadStatus {
+	case "READY":
+	case "FAILED":
+		return nil, ErrMovieVideoFailed
+	default:
+		return nil, ErrMovieNotReady
+	}
+
+	if err := u.ensureLicensed(req.MovieID, req.Region, time.Now()); err != nil {
+		return nil, err
+	}
+
+	orderType := req.Type
+	if orderType == "" {
+		orderType = orders.OrderTypeRental
+	}
+
+	// Rentals use the regular price, purchases the movie's purchase price
 	price, ok := movie["price"].(float64)
 	if !ok {
-		return nil, fmt.Errorf("invalid movie price")
+		return nil, ErrInvalidMoviePrice
+	}
+	if orderType == orders.OrderTypePurchase {
+		purchasePrice, _ := movie["purchase_price"].(*float64)
+		if purchasePrice == nil {
+			return nil, ErrMovieNotPurchasable
+		}
+		price = *purchasePrice
 	}
 
 	// 2. Get user details
 	user, err := u.userRepo.FindUserByExtID(userExtID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -82,45 +202,152 @@ func (u *orderUsecase) CreateOrder(userExtID string, req *orders.CreateOrderRequ
 	userEmail, _ := user["email"].(string)
 	userName, _ := user["name"].(string)
 
-	// 3. Create order record with PENDING status
+	// 3-5. Create the order and its gateway transaction atomically.
+	// The gateway call runs inside the DB transaction so a gateway error rolls the order back
+	// instead of leaving a PENDING order without checkout URL.
+	paymentService := u.payments.Default()
 	order := &orders.Order{
-		UserExtID:     userExtID,
-		MovieID:       req.MovieID,
-		Amount:        price,
-		PaymentStatus: orders.PaymentStatusPending,
+		UserExtID:       userExtID,
+		MovieID:         req.MovieID,
+		Amount:          price,
+		OrderType:       orderType,
+		PaymentStatus:   orders.PaymentStatusPending,
+		PaymentProvider: paymentService.Name(),
 	}
-
-	if err := u.orderRepo.CreateOrder(order); err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	if req.Region != "" {
+		order.Region = &req.Region
 	}
 
-	// 4. Create payment transaction with Midtrans
-	checkoutURL, paymentRef, err := u.paymentService.CreateTransaction(
-		order.ID,
-		price,
-		userEmail,
-		userName,
-	)
+	// The order and the gateway link expire at the same instant
+	expiresAt := time.Now().Add(u.moviePaymentExpiry(movie)).Truncate(time.Second)
+
+	var checkoutURL, paymentRef string
+	var existing *orders.Order
+	gatewayCreated := false
+
+	err = u.orderRepo.WithTransaction(func(txRepo orderRepository.OrderRepository) error {
+		// A double submit waits for the first order and then finds it pending
+		if err := txRepo.LockUserOrders(userExtID); err != nil {
+			return fmt.Errorf("failed to lock user orders: %w", err)
+		}
+		var err error
+		if existing, err = findDuplicateOrder(txRepo, userExtID, req.MovieID, orderType); err != nil || existing != nil {
+			return err
+		}
+
+		// 3. Create order record with PENDING status
+		if err := txRepo.CreateOrder(order); err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		// 4. Create payment transaction with the configured gateway
+		checkoutURL, paymentRef, err = paymentService.CreateTransaction(
+			ctx,
+			order.ID,
+			price,
+			userEmail,
+			userName,
+			expiresAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create payment transaction: %w", err)
+		}
+		gatewayCreated = true
+
+		// 5. Update order with payment details
+		if err := txRepo.UpdateOrderPaymentDetails(order.ID, paymentRef, checkoutURL, &expiresAt); err != nil {
+			return fmt.Errorf("failed to update order payment details: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create payment transaction: %w", err)
+		// The order was rolled back, so the gateway transaction must not stay payable
+		if gatewayCreated {
+			if cancelErr := paymentService.CancelTransaction(ctx, order.ID, paymentRef); cancelErr != nil {
+				log.Printf("[ORDER] Failed to cancel orphan %s transaction for order %d: %v", paymentService.Name(), order.ID, cancelErr)
+			}
+		}
+		return nil, gatewayError(paymentService, err)
 	}
 
-	// 5. Update order with payment details
-	expiresAt := time.Now().Add(24 * time.Hour) // Payment link expires in 24 hours
-
-	if err := u.orderRepo.UpdateOrderPaymentDetails(order.ID, paymentRef, checkoutURL, &expiresAt); err != nil {
-		return nil, fmt.Errorf("failed to update order payment details: %w", err)
+	// The pending order is paid through its own checkout link
+	if existing != nil {
+		response := &orders.CreateOrderResponse{
+			OrderID:   existing.ID,
+			OrderType: existing.OrderType,
+			Amount:    existing.Amount,
+			Existing:  true,
+			Message:   "You already have a pending order for this movie. Please proceed to payment.",
+		}
+		if existing.CheckoutURL != nil {
+			response.CheckoutURL = *existing.CheckoutURL
+		}
+		if existing.ExpiresAt != nil {
+			response.ExpiresAt = *existing.ExpiresAt
+		}
+		if expiresIn := existing.PaymentExpiresIn(time.Now()); expiresIn != nil {
+			response.ExpiresIn = *expiresIn
+		}
+		return response, nil
 	}
 
 	// 6. Return response
 	return &orders.CreateOrderResponse{
 		OrderID:     order.ID,
+		OrderType:   order.OrderType,
 		CheckoutURL: checkoutURL,
 		Amount:      price,
+		ExpiresAt:   expiresAt,
+		ExpiresIn:   int64(time.Until(expiresAt) / time.Second),
 		Message:     "Order created successfully. Please proceed to payment.",
 	}, nil
 }
 
+// findDuplicateOrder returns the user's pending order of the same type for the movie, which is paid instead of a new one.
+// Orders for access the user already has are refused, a rental may still be upgraded to a purchase.
+func findDuplicateOrder(repo orderRepository.OrderRepository, userExtID string, movieID int64, orderType orders.OrderType) (*orders.Order, error) {
+	access, err := repo.CheckUserAccess(userExtID, movieID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check user access: %w", err)
+	}
+	if access != nil && (access.AccessExpiresAt == nil || orderType == orders.OrderTypeRental) {
+		return nil, ErrMovieAlreadyOwned
+	}
+
+	pending, err := repo.FindPendingOrder(userExtID, movieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending order: %w", err)
+	}
+	if pending.OrderType != orderType {
+		return nil, ErrOtherOrderPending
+	}
+	return pending, nil
+}
+
+// gatewayError turns a payment gateway outage into ErrPaymentsUnavailable so clients can retry later,
+// other errors are returned unchanged
+func gatewayError(paymentService payment.PaymentService, err error) error {
+	if errors.Is(err, payment.ErrGatewayUnavailable) {
+		log.Printf("[ORDER] Payment gateway %s unavailable: %v", paymentService.Name(), err)
+		return ErrPaymentsUnavailable
+	}
+	return err
+}
+
+// moviePaymentExpiry returns how long the checkout link for a movie stays payable,
+// the movie's own setting overrides the configured default
+func (u *orderUsecase) moviePaymentExpiry(movie map[string]interface{}) time.Duration {
+	expiry := u.paymentExpiry
+	if minutes, _ := movie["payment_expiry_minutes"].(*int); minutes != nil && *minutes > 0 {
+		expiry = time.Duration(*minutes) * time.Minute
+	}
+	return payment.ClampCheckoutExpiry(expiry)
+}
+
 // GetUserOrders retrieves all orders for a specific user with pagination
 func (u *orderUsecase) GetUserOrders(userExtID string, page, limit int) (*orders.OrdersListWrapper, error) {
 	if page < 1 {
@@ -136,6 +363,7 @@ func (u *orderUsecase) GetUserOrders(userExtID string, page, limit int) (*orders
 	}
 
 	// Map to response DTOs
+	now := time.Now()
 	orderResponses := make([]orders.OrderListResponse, len(ordersList))
 	for i, order := range ordersList {
 		paymentRef := ""
@@ -148,9 +376,14 @@ func (u *orderUsecase) GetUserOrders(userExtID string, page, limit int) (*orders
 			MovieID:           order.MovieID,
 			MovieTitle:        order.MovieTitle,
 			Amount:            order.Amount,
+			OrderType:         order.OrderType,
 			PaymentStatus:     order.PaymentStatus,
 			PaymentGatewayRef: paymentRef,
 			PaidAt:            order.PaidAt,
+			InvoiceNumber:     order.InvoiceNumber,
+			SimulatedBy:       order.SimulatedBy,
+			ExpiresAt:         order.ExpiresAt,
+			ExpiresIn:         order.PaymentExpiresIn(now),
 			CreatedAt:         order.CreatedAt,
 		}
 	}
@@ -168,8 +401,8 @@ func (u *orderUsecase) GetUserOrders(userExtID string, page, limit int) (*orders
 	}, nil
 }
 
-// GetAllOrders retrieves all orders (admin) with optional status filter and pagination
-func (u *orderUsecase) GetAllOrders(page, limit int, status string) (*orders.OrdersListWrapper, error) {
+// GetAllOrders retrieves all orders (admin) with optional status and invoice number filters and pagination
+func (u *orderUsecase) GetAllOrders(page, limit int, filter orders.OrderFilter) (*orders.OrdersListWrapper, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -177,12 +410,13 @@ func (u *orderUsecase) GetAllOrders(page, limit int, status string) (*orders.Ord
 		limit = 20
 	}
 
-	ordersList, total, err := u.orderRepo.FindAllOrders(page, limit, status)
+	ordersList, total, err := u.orderRepo.FindAllOrders(page, limit, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all orders: %w", err)
 	}
 
 	// Map to response DTOs
+	now := time.Now()
 	orderResponses := make([]orders.OrderListResponse, len(ordersList))
 	for i, order := range ordersList {
 		paymentRef := ""
@@ -195,9 +429,14 @@ func (u *orderUsecase) GetAllOrders(page, limit int, status string) (*orders.Ord
 			MovieID:           order.MovieID,
 			MovieTitle:        order.MovieTitle,
 			Amount:            order.Amount,
+			OrderType:         order.OrderType,
 			PaymentStatus:     order.PaymentStatus,
 			PaymentGatewayRef: paymentRef,
 			PaidAt:            order.PaidAt,
+			InvoiceNumber:     order.InvoiceNumber,
+			SimulatedBy:       order.SimulatedBy,
+			ExpiresAt:         order.ExpiresAt,
+			ExpiresIn:         order.PaymentExpiresIn(now),
 			CreatedAt:         order.CreatedAt,
 		}
 	}
@@ -220,7 +459,7 @@ func (u *orderUsecase) GetOrderDetail(orderID int64) (*orders.OrderDetailRespons
 	order, err := u.orderRepo.FindOrderByID(orderID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("order not found")
+			return nil, ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
@@ -243,23 +482,56 @@ func (u *orderUsecase) GetOrderDetail(orderID int64) (*orders.OrderDetailRespons
 		MovieID:           order.MovieID,
 		MovieTitle:        order.MovieTitle,
 		Amount:            order.Amount,
+		OrderType:         order.OrderType,
 		PaymentStatus:     order.PaymentStatus,
 		PaymentGatewayRef: paymentRef,
 		CheckoutURL:       checkoutURL,
 		PaidAt:            order.PaidAt,
+		InvoiceNumber:     order.InvoiceNumber,
+		SimulatedBy:       order.SimulatedBy,
 		ExpiresAt:         order.ExpiresAt,
+		ExpiresIn:         order.PaymentExpiresIn(time.Now()),
 		CreatedAt:         order.CreatedAt,
 		UpdatedAt:         order.UpdatedAt,
 	}, nil
 }
 
+// maxPaymentEvents caps the webhooks listed for an order
+const maxPaymentEvents = 200
+
+// GetOrderPaymentEvents returns the payment webhooks received for an order, newest first (Admin only)
+func (u *orderUsecase) GetOrderPaymentEvents(orderID int64) ([]orders.PaymentEvent, error) {
+	if _, err := u.orderRepo.FindOrderByID(orderID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	events, err := u.orderRepo.FindPaymentEvents(orderID, maxPaymentEvents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment events: %w", err)
+	}
+	if events == nil {
+		events = []orders.PaymentEvent{}
+	}
+	return events, nil
+}
+
 // CheckStreamAccess checks if user has access to stream a movie
-func (u *orderUsecase) CheckStreamAccess(userExtID string, movieID int64) (*orders.StreamURLResponse, error) {
+// Device capabilities (optional) are forwarded to the proxy URL so the master playlist can be tailored
+// When device is set a stream session is started for it, subject to the concurrent stream limit
+func (u *orderUsecase) CheckStreamAccess(userExtID string, movieID int64, caps *orders.DeviceCapabilities, device *orders.StreamDevice) (*orders.StreamURLResponse, error) {
+	// Checked on every proxied playlist/segment, so a takedown stops running streams too
+	if err := u.ensureMovieAvailable(userExtID, movieID); err != nil {
+		return nil, err
+	}
+
 	// 1. Check if user has active access
 	access, err := u.orderRepo.CheckUserAccess(userExtID, movieID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("access denied: you need to rent this movie first")
+			return nil, ErrAccessRequired
 		}
 		return nil, fmt.Errorf("failed to check access: %w", err)
 	}
@@ -270,57 +542,270 @@ func (u *orderUsecase) CheckStreamAccess(userExtID string, movieID int64) (*orde
 		return nil, fmt.Errorf("failed to get movie stream URL: %w", err)
 	}
 
-	// 3. Return stream URL
+	// 3. Start or continue the device's stream session, a new device counts against the rental's device limit
+	sessionID := ""
+	if device != nil {
+		if err := u.registerAccessDevice(access, *device); err != nil {
+			return nil, err
+		}
+		session, err := u.startStreamSession(userExtID, movieID, *device)
+		if err != nil {
+			return nil, err
+		}
+		sessionID = session.SessionID
+		u.recordView(movieID, userExtID)
+	}
+
+	// 4. Subtitle and audio tracks, only listed for the stream request and not for every proxied segment
+	var subtitles []orders.SubtitleTrack
+	var audioTracks []orders.AudioTrack
+	if device != nil {
+		subtitles, err = u.GetSubtitleTracks(movieID)
+		if err != nil {
+			return nil, err
+		}
+		audioTracks, err = u.GetAudioTracks(movieID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Return stream URL
 	message := "Access granted. Enjoy your movie!"
 	if access.AccessExpiresAt != nil {
 		message = fmt.Sprintf("Access granted until %s", access.AccessExpiresAt.Format("2006-01-02 15:04:05"))
 	}
 
+	// The proxy path keeps the output version (v{n}/master.m3u8), so a running session stays on
+	// the version it started with when a re-transcode is published
+	proxyURL := fmt.Sprintf("/api/v1/movies/%d/hls/%s", movieID, strings.TrimPrefix(hlsURL, fmt.Sprintf("movie-%d/", movieID)))
+	if !caps.IsEmpty() {
+		proxyURL += "?" + caps.Query().Encode()
+	} else {
+		caps = nil
+	}
+
+	// DASH is optional and, like subtitles, only looked up for the stream request
+	dashURL, dashProxyURL := "", ""
+	if device != nil {
+		dashURL, err = u.movieRepo.GetMovieDASHURL(movieID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get movie DASH manifest: %w", err)
+		}
+		if dashURL != "" {
+			dashProxyURL = fmt.Sprintf("/api/v1/movies/%d/hls/%s", movieID, strings.TrimPrefix(dashURL, fmt.Sprintf("movie-%d/", movieID)))
+		}
+	}
+
 	return &orders.StreamURLResponse{
 		HLSURL:          hlsURL,
+		ProxyURL:        proxyURL,
+		DASHURL:         dashURL,
+		DASHProxyURL:    dashProxyURL,
+		Capabilities:    caps,
 		AccessExpiresAt: access.AccessExpiresAt,
+		SessionID:       sessionID,
+		Subtitles:       subtitles,
+		AudioTracks:     audioTracks,
 		Message:         message,
 	}, nil
 }
 
+// recordView counts a playback start, a failure is only logged and does not stop playback
+func (u *orderUsecase) recordView(movieID int64, userExtID string) {
+	if u.views == nil {
+		return
+	}
+	if err := u.views.RecordStart(context.Background(), movieID, userExtID); err != nil {
+		log.Printf("[VIEWS] failed to count view of movie %d: %v", movieID, err)
+	}
+}
+
+// GetContentKey returns the AES-128 key of a movie's HLS segments to a user with active access
+func (u *orderUsecase) GetContentKey(userExtID string, movieID int64) ([]byte, error) {
+	// Same checks as the HLS proxy, a takedown or an ended rental stops key delivery too
+	if err := u.ensureMovieAvailable(userExtID, movieID); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.orderRepo.CheckUserAccess(userExtID, movieID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAccessRequired
+		}
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+
+	key, err := u.movieRepo.GetMovieContentKey(movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrContentKeyNotFound
+	}
+
+	return key, nil
+}
+
+// GetSubtitleTracks returns the subtitle tracks of a movie with their URLs through the HLS proxy
+func (u *orderUsecase) GetSubtitleTracks(movieID int64) ([]orders.SubtitleTrack, error) {
+	tracks, err := u.movieRepo.GetMovieSubtitles(movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtitles: %w", err)
+	}
+	for i := range tracks {
+		tracks[i].URL = fmt.Sprintf("/api/v1/movies/%d/hls/subtitles/%s.vtt", movieID, tracks[i].Language)
+	}
+	return tracks, nil
+}
+
+// GetAudioTracks returns the audio tracks of a movie, uploaded tracks with their URLs through the HLS proxy
+func (u *orderUsecase) GetAudioTracks(movieID int64) ([]orders.AudioTrack, error) {
+	tracks, err := u.movieRepo.GetMovieAudioTracks(movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audio tracks: %w", err)
+	}
+	for i := range tracks {
+		if tracks[i].Playlist != "" {
+			tracks[i].URL = fmt.Sprintf("/api/v1/movies/%d/hls/%s", movieID, tracks[i].Playlist)
+		}
+	}
+	return tracks, nil
+}
+
 // SimulatePaymentSuccess simulates a successful payment (for development/testing only)
-// This method updates order status to PAID and grants movie access to the user
-func (u *orderUsecase) SimulatePaymentSuccess(orderID int64) error {
+// This method updates order status to PAID, records the admin who simulated it and grants movie access to the user
+func (u *orderUsecase) SimulatePaymentSuccess(adminExtID string, orderID int64) error {
 	// 1. Get order details
 	order, err := u.orderRepo.FindOrderByID(orderID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("order not found")
+			return ErrOrderNotFound
 		}
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 
 	// 2. Check if already paid
 	if order.PaymentStatus == orders.PaymentStatusPaid {
-		return fmt.Errorf("order already paid")
+		return ErrOrderAlreadyPaid
 	}
 
-	// 3. Update order status to PAID
+	// 3-4. Update order status to PAID with its invoice number and grant user access to the movie
 	now := time.Now()
-	if err := u.orderRepo.UpdateOrderStatus(orderID, orders.PaymentStatusPaid, &now); err != nil {
-		return fmt.Errorf("failed to update order status: %w", err)
+	err = u.orderRepo.WithTransaction(func(txRepo orderRepository.OrderRepository) error {
+		if err := txRepo.UpdateOrderStatus(orderID, orders.PaymentStatusPaid, &now); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		if err := txRepo.MarkPaymentSimulated(orderID, adminExtID); err != nil {
+			return fmt.Errorf("failed to mark payment simulated: %w", err)
+		}
+		if err := txRepo.AssignInvoiceNumber(orderID, now); err != nil {
+			return fmt.Errorf("failed to assign invoice number: %w", err)
+		}
+
+		access := &orders.UserMovieAccess{
+			UserExtID:       order.UserExtID,
+			MovieID:         order.MovieID,
+			OrderID:         &orderID,
+			AccessGrantedAt: now,
+			AccessExpiresAt: order.AccessExpiry(now), // rentals expire, purchases are permanent
+		}
+		if err := txRepo.CreateUserMovieAccess(access); err != nil {
+			return fmt.Errorf("failed to grant movie access: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// 4. Grant user access to the movie
-	access := &orders.UserMovieAccess{
-		UserExtID:       order.UserExtID,
-		MovieID:         order.MovieID,
-		OrderID:         orderID,
-		AccessGrantedAt: now,
-		AccessExpiresAt: nil, // Permanent access (or set expiration as needed)
+	log.Printf("[ORDER] Admin %s simulated payment success for order %d, granted access to user %s for movie %d",
+		adminExtID, orderID, order.UserExtID, order.MovieID)
+
+	return nil
+}
+
+// ensureMovieAvailable rejects titles that were taken down pending investigation,
+// and soft-launched titles the user may not see yet
+func (u *orderUsecase) ensureMovieAvailable(userExtID string, movieID int64) error {
+	movie, err := u.movieRepo.FindMovieByID(movieID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrMovieNotFound
+		}
+		return fmt.Errorf("failed to get movie: %w", err)
 	}
 
-	if err := u.orderRepo.CreateUserMovieAccess(access); err != nil {
-		return fmt.Errorf("failed to grant movie access: %w", err)
+	if takenDown, _ := movie["taken_down"].(bool); takenDown {
+		return ErrMovieUnavailable
 	}
 
-	fmt.Printf("INFO - Simulated payment success for order %d, granted access to user %s for movie %d\n",
-		orderID, order.UserExtID, order.MovieID)
+	return u.ensureBetaAccess(userExtID, movieID, movie)
+}
+
+// ensureBetaAccess hides a soft-launched title from users who are neither testers nor on its allowlist.
+// The role is read from the account, so a revoked TESTER role takes effect before the token expires.
+func (u *orderUsecase) ensureBetaAccess(userExtID string, movieID int64, movie map[string]interface{}) error {
+	if beta, _ := movie["beta_access"].(bool); !beta {
+		return nil
+	}
+
+	allowed, err := u.movieRepo.HasBetaAccess(movieID, userExtID)
+	if err != nil {
+		return fmt.Errorf("failed to check beta access: %w", err)
+	}
+	if allowed {
+		return nil
+	}
 
+	user, err := u.userRepo.FindUserByExtID(userExtID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrMovieNotFound
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if role, _ := user["role"].(string); role == constant.RoleTester {
+		return nil
+	}
+
+	return ErrMovieNotFound
+}
+
+// ensureLicensed rejects orders outside the movie's licensing window for the region,
+// or when the window closes within the cutoff. Movies without windows are not restricted.
+func (u *orderUsecase) ensureLicensed(movieID int64, region string, now time.Time) error {
+	windows, err := u.movieRepo.GetMovieLicenseWindows(movieID)
+	if err != nil {
+		return fmt.Errorf("failed to get license windows: %w", err)
+	}
+	if len(windows) == 0 {
+		return nil
+	}
+
+	// Adjacent windows count as one, a renewal keeps the title orderable
+	var openUntil time.Time
+	for changed := true; changed; {
+		changed = false
+		for _, window := range windows {
+			if window.Region != region && window.Region != orders.WorldwideRegion {
+				continue
+			}
+			start := now
+			if !openUntil.IsZero() {
+				start = openUntil
+			}
+			if !window.StartsAt.After(start) && window.EndsAt.After(start) {
+				openUntil = window.EndsAt
+				changed = true
+			}
+		}
+	}
+
+	if openUntil.IsZero() {
+		return ErrMovieNotLicensed
+	}
+	if openUntil.Sub(now) < u.licenseCutoff {
+		return ErrLicenseEndingSoon
+	}
 	return nil
 }
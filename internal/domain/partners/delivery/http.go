@@ -0,0 +1,153 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/partners"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type PartnerUsecase interface {
+	CreateAPIKey(ctx context.Context, adminExtID string, req partners.CreateAPIKeyRequest) (*partners.CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context) ([]partners.APIKey, error)
+	UpdateQuotas(ctx context.Context, keyID int64, req partners.QuotaRequest) (*partners.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID int64) error
+	GetUsage(ctx context.Context, keyID int64, month string) (*partners.UsageReport, error)
+}
+
+type PartnerHandler struct {
+	ctx     context.Context
+	usecase PartnerUsecase
+}
+
+func NewPartnerHandler(ctx context.Context, usecase PartnerUsecase) *PartnerHandler {
+	return &PartnerHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// CreateAPIKey issues a partner API key, the key is only returned once (Admin only)
+// POST /api/v1/admin/partners/keys
+func (h *PartnerHandler) CreateAPIKey(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req partners.CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.CreateAPIKey(ctx, adminExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return response.Success(c, http.StatusCreated, "api_key_created", result)
+}
+
+// GetAPIKeys lists partner API keys (Admin only)
+// GET /api/v1/admin/partners/keys
+func (h *PartnerHandler) GetAPIKeys(c echo.Context) error {
+	ctx := h.ctx
+
+	result, err := h.usecase.GetAPIKeys(ctx)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// UpdateQuotas replaces the monthly quotas of a key (Admin only)
+// PUT /api/v1/admin/partners/keys/:id/quotas
+func (h *PartnerHandler) UpdateQuotas(c echo.Context) error {
+	ctx := h.ctx
+
+	keyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_api_key_id", err.Error())
+	}
+
+	var req partners.QuotaRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdateQuotas(ctx, keyID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "quotas_updated", result)
+}
+
+// RevokeAPIKey revokes a partner API key (Admin only)
+// DELETE /api/v1/admin/partners/keys/:id
+func (h *PartnerHandler) RevokeAPIKey(c echo.Context) error {
+	ctx := h.ctx
+
+	keyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_api_key_id", err.Error())
+	}
+
+	if err := h.usecase.RevokeAPIKey(ctx, keyID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetKeyUsage returns the metered traffic of a key in a month (Admin only)
+// GET /api/v1/admin/partners/keys/:id/usage?month=2025-12
+func (h *PartnerHandler) GetKeyUsage(c echo.Context) error {
+	ctx := h.ctx
+
+	keyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_api_key_id", err.Error())
+	}
+
+	result, err := h.usecase.GetUsage(ctx, keyID, c.QueryParam("month"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetOwnUsage returns the traffic of the calling partner's key, authenticated with X-API-Key
+// GET /api/v1/partner/usage?month=2025-12
+func (h *PartnerHandler) GetOwnUsage(c echo.Context) error {
+	ctx := h.ctx
+
+	keyID, ok := c.Get(string(constant.CtxKeyPartnerKeyID)).(int64)
+	if !ok || keyID == 0 {
+		return response.Error(c, http.StatusUnauthorized, "api_key_required", nil)
+	}
+
+	result, err := h.usecase.GetUsage(ctx, keyID, c.QueryParam("month"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
@@ -0,0 +1,78 @@
+package partners
+
+import "time"
+
+// APIKey authenticates a partner integration, the key itself is only stored as SHA-256 hash.
+// Quotas apply per calendar month (UTC), nil means unlimited.
+type APIKey struct {
+	ID               int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name             string     `json:"name" gorm:"type:varchar(100);not null"`
+	KeyPrefix        string     `json:"key_prefix" gorm:"type:varchar(16);not null"` // shown so partners can tell keys apart
+	KeyHash          string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	SoftRequestQuota *int64     `json:"soft_request_quota"` // exceeding it only adds a warning header
+	HardRequestQuota *int64     `json:"hard_request_quota"` // exceeding it answers 429
+	SoftByteQuota    *int64     `json:"soft_byte_quota"`
+	HardByteQuota    *int64     `json:"hard_byte_quota"`
+	CreatedByExtID   string     `json:"created_by_ext_id" gorm:"type:varchar(255);not null;column:created_by_ext_id"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for APIKey
+func (APIKey) TableName() string {
+	return "partner_api_keys"
+}
+
+// SoftQuotaExceeded reports whether the month's traffic used up a soft quota
+func (k APIKey) SoftQuotaExceeded(requests, bytes int64) bool {
+	return exceeds(requests, k.SoftRequestQuota) || exceeds(bytes, k.SoftByteQuota)
+}
+
+// HardQuotaExceeded reports whether the month's traffic used up a hard quota
+func (k APIKey) HardQuotaExceeded(requests, bytes int64) bool {
+	return exceeds(requests, k.HardRequestQuota) || exceeds(bytes, k.HardByteQuota)
+}
+
+func exceeds(used int64, quota *int64) bool {
+	return quota != nil && used >= *quota
+}
+
+// Request DTOs
+
+// QuotaRequest sets the monthly quotas of a key, omitted quotas are unlimited
+type QuotaRequest struct {
+	SoftRequestQuota *int64 `json:"soft_request_quota" validate:"omitempty,gt=0"`
+	HardRequestQuota *int64 `json:"hard_request_quota" validate:"omitempty,gt=0"`
+	SoftByteQuota    *int64 `json:"soft_byte_quota" validate:"omitempty,gt=0"`
+	HardByteQuota    *int64 `json:"hard_byte_quota" validate:"omitempty,gt=0"`
+}
+
+// CreateAPIKeyRequest represents a new partner API key
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	QuotaRequest
+}
+
+// Response DTOs
+
+// CreateAPIKeyResponse contains the plain key, it is only shown once
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// UsageReport is the metered traffic of a key in one month
+type UsageReport struct {
+	KeyID             int64  `json:"key_id"`
+	Name              string `json:"name"`
+	Month             string `json:"month"` // YYYY-MM, UTC
+	Requests          int64  `json:"requests"`
+	Bytes             int64  `json:"bytes"`
+	SoftRequestQuota  *int64 `json:"soft_request_quota"`
+	HardRequestQuota  *int64 `json:"hard_request_quota"`
+	SoftByteQuota     *int64 `json:"soft_byte_quota"`
+	HardByteQuota     *int64 `json:"hard_byte_quota"`
+	SoftQuotaExceeded bool   `json:"soft_quota_exceeded"`
+	HardQuotaExceeded bool   `json:"hard_quota_exceeded"`
+}
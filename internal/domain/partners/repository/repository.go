@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/partners"
+	"gorm.io/gorm"
+)
+
+type PartnerRepository struct {
+	db *gorm.DB
+}
+
+func NewPartnerRepository(db *gorm.DB) *PartnerRepository {
+	return &PartnerRepository{db: db}
+}
+
+func (r *PartnerRepository) CreateAPIKey(ctx context.Context, key *partners.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// FindAPIKeyByID returns nil when the key does not exist
+func (r *PartnerRepository) FindAPIKeyByID(ctx context.Context, keyID int64) (*partners.APIKey, error) {
+	var key partners.APIKey
+	err := r.db.WithContext(ctx).Where("id = ?", keyID).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindActiveAPIKeyByHash returns the key presented by a partner, nil when it is unknown or revoked
+func (r *PartnerRepository) FindActiveAPIKeyByHash(ctx context.Context, keyHash string) (*partners.APIKey, error) {
+	var key partners.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ? AND revoked_at IS NULL", keyHash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindAllAPIKeys lists every key, revoked ones included, newest first
+func (r *PartnerRepository) FindAllAPIKeys(ctx context.Context) ([]partners.APIKey, error) {
+	var keys []partners.APIKey
+	err := r.db.WithContext(ctx).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// UpdateAPIKeyQuotas replaces the quotas of a key, nil values remove a quota
+func (r *PartnerRepository) UpdateAPIKeyQuotas(ctx context.Context, keyID int64, quotas partners.QuotaRequest) error {
+	return r.db.WithContext(ctx).Model(&partners.APIKey{}).Where("id = ?", keyID).Updates(map[string]interface{}{
+		"soft_request_quota": quotas.SoftRequestQuota,
+		"hard_request_quota": quotas.HardRequestQuota,
+		"soft_byte_quota":    quotas.SoftByteQuota,
+		"hard_byte_quota":    quotas.HardByteQuota,
+	}).Error
+}
+
+// RevokeAPIKey revokes a key, false when it was already revoked
+func (r *PartnerRepository) RevokeAPIKey(ctx context.Context, keyID int64, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&partners.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", at)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/partners"
+	"github.com/martinmanurung/cinestream/internal/platform/usage"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// keyPrefix marks partner keys so they are recognisable in logs and secret scanners
+const keyPrefix = "csk_"
+
+type PartnerRepository interface {
+	CreateAPIKey(ctx context.Context, key *partners.APIKey) error
+	FindAPIKeyByID(ctx context.Context, keyID int64) (*partners.APIKey, error)
+	FindActiveAPIKeyByHash(ctx context.Context, keyHash string) (*partners.APIKey, error)
+	FindAllAPIKeys(ctx context.Context) ([]partners.APIKey, error)
+	UpdateAPIKeyQuotas(ctx context.Context, keyID int64, quotas partners.QuotaRequest) error
+	RevokeAPIKey(ctx context.Context, keyID int64, at time.Time) (bool, error)
+}
+
+// UsageMeter stores the monthly traffic counters of the keys
+type UsageMeter interface {
+	Record(ctx context.Context, keyID int64, bytes int64) error
+	Month(ctx context.Context, keyID int64, at time.Time) (usage.Totals, error)
+}
+
+type PartnerUsecase struct {
+	repo  PartnerRepository
+	meter UsageMeter
+}
+
+func NewPartnerUsecase(repo PartnerRepository, meter UsageMeter) *PartnerUsecase {
+	return &PartnerUsecase{repo: repo, meter: meter}
+}
+
+// CreateAPIKey issues a key for a partner, the plain key is returned only in this response (Admin only)
+func (u *PartnerUsecase) CreateAPIKey(ctx context.Context, adminExtID string, req partners.CreateAPIKeyRequest) (*partners.CreateAPIKeyResponse, error) {
+	if err := validateQuotas(req.QuotaRequest); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, apperr.Internal(fmt.Errorf("failed to generate api key: %w", err))
+	}
+	plain := keyPrefix + hex.EncodeToString(secret)
+
+	key := &partners.APIKey{
+		Name:             strings.TrimSpace(req.Name),
+		KeyPrefix:        plain[:len(keyPrefix)+8],
+		KeyHash:          hashKey(plain),
+		SoftRequestQuota: req.SoftRequestQuota,
+		HardRequestQuota: req.HardRequestQuota,
+		SoftByteQuota:    req.SoftByteQuota,
+		HardByteQuota:    req.HardByteQuota,
+		CreatedByExtID:   adminExtID,
+	}
+	if err := u.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &partners.CreateAPIKeyResponse{APIKey: *key, Key: plain}, nil
+}
+
+// GetAPIKeys lists every partner key (Admin only)
+func (u *PartnerUsecase) GetAPIKeys(ctx context.Context) ([]partners.APIKey, error) {
+	keys, err := u.repo.FindAllAPIKeys(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if keys == nil {
+		keys = []partners.APIKey{}
+	}
+	return keys, nil
+}
+
+// UpdateQuotas replaces the monthly quotas of a key (Admin only)
+func (u *PartnerUsecase) UpdateQuotas(ctx context.Context, keyID int64, req partners.QuotaRequest) (*partners.APIKey, error) {
+	if err := validateQuotas(req); err != nil {
+		return nil, err
+	}
+	if _, err := u.findAPIKey(ctx, keyID); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.UpdateAPIKeyQuotas(ctx, keyID, req); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return u.findAPIKey(ctx, keyID)
+}
+
+// RevokeAPIKey stops a key from authenticating, its usage stays readable (Admin only)
+func (u *PartnerUsecase) RevokeAPIKey(ctx context.Context, keyID int64) error {
+	if _, err := u.findAPIKey(ctx, keyID); err != nil {
+		return err
+	}
+
+	revoked, err := u.repo.RevokeAPIKey(ctx, keyID, time.Now())
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !revoked {
+		return apperr.Conflict("api_key_already_revoked", nil)
+	}
+	return nil
+}
+
+// GetUsage returns the traffic of a key in a month ("2025-12"), the current month when empty
+func (u *PartnerUsecase) GetUsage(ctx context.Context, keyID int64, month string) (*partners.UsageReport, error) {
+	at := time.Now().UTC()
+	if month != "" {
+		parsed, err := time.Parse(usage.MonthLayout, month)
+		if err != nil {
+			return nil, apperr.Validation("invalid_month", "month must be formatted as YYYY-MM")
+		}
+		at = parsed
+	}
+
+	key, err := u.findAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := u.meter.Month(ctx, keyID, at)
+	if err != nil {
+		return nil, apperr.Unavailable("usage_temporarily_unavailable", nil)
+	}
+
+	return &partners.UsageReport{
+		KeyID:             key.ID,
+		Name:              key.Name,
+		Month:             at.Format(usage.MonthLayout),
+		Requests:          totals.Requests,
+		Bytes:             totals.Bytes,
+		SoftRequestQuota:  key.SoftRequestQuota,
+		HardRequestQuota:  key.HardRequestQuota,
+		SoftByteQuota:     key.SoftByteQuota,
+		HardByteQuota:     key.HardByteQuota,
+		SoftQuotaExceeded: key.SoftQuotaExceeded(totals.Requests, totals.Bytes),
+		HardQuotaExceeded: key.HardQuotaExceeded(totals.Requests, totals.Bytes),
+	}, nil
+}
+
+// CheckPartnerKey authenticates a partner key and reports whether its monthly quotas are used up.
+// Metering is best effort, when the counters cannot be read the request is let through.
+func (u *PartnerUsecase) CheckPartnerKey(ctx context.Context, apiKey string) (int64, bool, bool, error) {
+	key, err := u.repo.FindActiveAPIKeyByHash(ctx, hashKey(apiKey))
+	if err != nil {
+		return 0, false, false, apperr.Internal(err)
+	}
+	if key == nil {
+		return 0, false, false, apperr.Unauthorized("invalid_api_key", nil)
+	}
+
+	totals, err := u.meter.Month(ctx, key.ID, time.Now())
+	if err != nil {
+		log.Printf("Warning: Failed to read usage of partner key %d: %v", key.ID, err)
+		return key.ID, false, false, nil
+	}
+	return key.ID, key.SoftQuotaExceeded(totals.Requests, totals.Bytes), key.HardQuotaExceeded(totals.Requests, totals.Bytes), nil
+}
+
+// RecordPartnerUsage meters a served partner request
+func (u *PartnerUsecase) RecordPartnerUsage(ctx context.Context, keyID int64, bytes int64) {
+	if err := u.meter.Record(ctx, keyID, bytes); err != nil {
+		log.Printf("Warning: Failed to record usage of partner key %d: %v", keyID, err)
+	}
+}
+
+func (u *PartnerUsecase) findAPIKey(ctx context.Context, keyID int64) (*partners.APIKey, error) {
+	key, err := u.repo.FindAPIKeyByID(ctx, keyID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if key == nil {
+		return nil, apperr.NotFound("api_key_not_found", nil)
+	}
+	return key, nil
+}
+
+// validateQuotas rejects a soft quota that would only warn after the hard quota already blocks
+func validateQuotas(req partners.QuotaRequest) error {
+	if req.SoftRequestQuota != nil && req.HardRequestQuota != nil && *req.SoftRequestQuota > *req.HardRequestQuota {
+		return apperr.Validation("soft_quota_above_hard_quota", "soft_request_quota must not exceed hard_request_quota")
+	}
+	if req.SoftByteQuota != nil && req.HardByteQuota != nil && *req.SoftByteQuota > *req.HardByteQuota {
+		return apperr.Validation("soft_quota_above_hard_quota", "soft_byte_quota must not exceed hard_byte_quota")
+	}
+	return nil
+}
+
+// hashKey returns the hex SHA-256 of a key, keys are random enough that no salt is needed
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
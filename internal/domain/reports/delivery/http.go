@@ -0,0 +1,154 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/reports"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type ReportUsecase interface {
+	CreateReport(ctx context.Context, reporterExtID string, req reports.CreateReportRequest) (*reports.ReportCreatedResponse, error)
+	GetAllReportsAdmin(ctx context.Context, page, limit int, status, targetType string, movieID int64) (*reports.AdminReportListWithPagination, error)
+	UpdateReport(ctx context.Context, adminExtID string, reportID int64, req reports.UpdateReportRequest) error
+	TakedownMovie(ctx context.Context, adminExtID string, movieID int64, req reports.TakedownRequest) (*reports.TakedownResponse, error)
+	RestoreMovie(ctx context.Context, movieID int64) error
+}
+
+type ReportHandler struct {
+	ctx     context.Context
+	usecase ReportUsecase
+}
+
+func NewReportHandler(ctx context.Context, usecase ReportUsecase) *ReportHandler {
+	return &ReportHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// CreateReport files a copyright or abuse report against a movie or review (Public)
+// POST /api/v1/reports
+func (h *ReportHandler) CreateReport(c echo.Context) error {
+	ctx := h.ctx
+
+	// Set by the optional JWT middleware when the reporter is signed in
+	reporterExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	var req reports.CreateReportRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.CreateReport(ctx, reporterExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, "report_submitted", result)
+}
+
+// GetAllReportsAdmin lists reports for handling (Admin only)
+// GET /api/v1/admin/reports?page=1&status=OPEN&target_type=MOVIE&movie_id=1
+func (h *ReportHandler) GetAllReportsAdmin(c echo.Context) error {
+	ctx := h.ctx
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := c.QueryParam("status")
+	targetType := c.QueryParam("target_type")
+	movieID, _ := strconv.ParseInt(c.QueryParam("movie_id"), 10, 64)
+
+	result, err := h.usecase.GetAllReportsAdmin(ctx, page, limit, status, targetType, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Reports,
+		"pagination": result.Pagination,
+	})
+}
+
+// UpdateReport records the handling of a report (Admin only)
+// PATCH /api/v1/admin/reports/:id
+func (h *ReportHandler) UpdateReport(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	reportID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_report_id", err.Error())
+	}
+
+	var req reports.UpdateReportRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	err = h.usecase.UpdateReport(ctx, adminExtID, reportID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "report_updated", nil)
+}
+
+// TakedownMovie immediately unpublishes a movie and stops its playback (Admin only)
+// POST /api/v1/admin/movies/:id/takedown
+func (h *ReportHandler) TakedownMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, _ := c.Get(string(constant.CtxKeyUserExtID)).(string)
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+	var req reports.TakedownRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.TakedownMovie(ctx, adminExtID, movieID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "movie_taken_down", result)
+}
+
+// RestoreMovie lifts the takedown of a movie (Admin only)
+// DELETE /api/v1/admin/movies/:id/takedown
+func (h *ReportHandler) RestoreMovie(c echo.Context) error {
+	ctx := h.ctx
+
+	movieID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+	err = h.usecase.RestoreMovie(ctx, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "movie_restored", nil)
+}
@@ -0,0 +1,111 @@
+package reports
+
+import "time"
+
+// Report target types
+const (
+	TargetMovie  = "MOVIE"
+	TargetReview = "REVIEW"
+)
+
+// Report categories
+const (
+	CategoryCopyright = "COPYRIGHT"
+	CategoryAbuse     = "ABUSE"
+	CategoryOther     = "OTHER"
+)
+
+// Report statuses
+const (
+	StatusOpen     = "OPEN"
+	StatusInReview = "IN_REVIEW"
+	StatusResolved = "RESOLVED"
+	StatusRejected = "REJECTED"
+)
+
+// ContentReport is a copyright or abuse report against a movie or an editorial review
+type ContentReport struct {
+	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	TargetType     string     `json:"target_type" gorm:"type:enum('MOVIE','REVIEW');not null"`
+	TargetID       int64      `json:"target_id" gorm:"not null"`
+	MovieID        int64      `json:"movie_id" gorm:"not null"`
+	Category       string     `json:"category" gorm:"type:enum('COPYRIGHT','ABUSE','OTHER');not null"`
+	ReporterExtID  *string    `json:"reporter_ext_id,omitempty" gorm:"column:reporter_ext_id"`
+	ReporterName   string     `json:"reporter_name" gorm:"type:varchar(255);not null"`
+	ReporterEmail  string     `json:"reporter_email" gorm:"type:varchar(255);not null"`
+	Description    string     `json:"description" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"type:enum('OPEN','IN_REVIEW','RESOLVED','REJECTED');default:'OPEN'"`
+	ResolutionNote *string    `json:"resolution_note,omitempty"`
+	HandledBy      *string    `json:"handled_by,omitempty"`
+	HandledAt      *time.Time `json:"handled_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for ContentReport
+func (ContentReport) TableName() string {
+	return "content_reports"
+}
+
+// Request DTOs
+
+// CreateReportRequest represents a public copyright or abuse report
+type CreateReportRequest struct {
+	TargetType    string `json:"target_type" validate:"required,oneof=MOVIE REVIEW"`
+	TargetID      int64  `json:"target_id" validate:"required,gt=0"`
+	Category      string `json:"category" validate:"required,oneof=COPYRIGHT ABUSE OTHER"`
+	ReporterName  string `json:"reporter_name" validate:"required,min=1,max=255"`
+	ReporterEmail string `json:"reporter_email" validate:"required,email,max=255"`
+	Description   string `json:"description" validate:"required,min=10,max=5000"`
+}
+
+// UpdateReportRequest represents an admin decision on a report
+type UpdateReportRequest struct {
+	Status         string `json:"status" validate:"required,oneof=IN_REVIEW RESOLVED REJECTED"`
+	ResolutionNote string `json:"resolution_note" validate:"max=500"`
+}
+
+// TakedownRequest represents an emergency takedown of a movie
+type TakedownRequest struct {
+	Reason   string `json:"reason" validate:"required,min=1,max=255"`
+	ReportID int64  `json:"report_id" validate:"omitempty,gt=0"` // optional report that triggered the takedown
+}
+
+// Response DTOs
+
+// ReportCreatedResponse is returned to the reporter
+type ReportCreatedResponse struct {
+	ReportID  int64     `json:"report_id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminReportResponse represents a report in the admin queue
+type AdminReportResponse struct {
+	ContentReport
+	MovieTitle     string     `json:"movie_title"`
+	MovieTakenDown *time.Time `json:"movie_taken_down_at,omitempty" gorm:"column:movie_taken_down_at"`
+}
+
+// TakedownResponse describes the result of a takedown
+type TakedownResponse struct {
+	MovieID         int64      `json:"movie_id"`
+	TakenDownAt     *time.Time `json:"taken_down_at"`
+	Reason          string     `json:"reason,omitempty"`
+	RevokedLicenses int64      `json:"revoked_offline_licenses"`
+	ReportID        int64      `json:"report_id,omitempty"`
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+// AdminReportListWithPagination represents the paginated report queue
+type AdminReportListWithPagination struct {
+	Reports    []AdminReportResponse `json:"reports"`
+	Pagination PaginationMeta        `json:"pagination"`
+}
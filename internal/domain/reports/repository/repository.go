@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/reports"
+	"gorm.io/gorm"
+)
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// movieState is the part of a movie needed to report or take it down
+type movieState struct {
+	ID          int64
+	TakenDownAt *time.Time
+}
+
+// FindMovieTakedown returns whether the movie exists and when it was taken down
+func (r *ReportRepository) FindMovieTakedown(ctx context.Context, movieID int64) (bool, *time.Time, error) {
+	var movie movieState
+	err := r.db.WithContext(ctx).
+		Table("movies").
+		Select("id, taken_down_at").
+		Where("id = ? AND deleted_at IS NULL", movieID).
+		Take(&movie).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	return true, movie.TakenDownAt, nil
+}
+
+// FindPublishedReviewMovieID returns the movie of a published editorial review, 0 if not found
+func (r *ReportRepository) FindPublishedReviewMovieID(ctx context.Context, reviewID int64) (int64, error) {
+	var movieIDs []int64
+	err := r.db.WithContext(ctx).
+		Table("editorial_reviews").
+		Where("id = ? AND status = ?", reviewID, "PUBLISHED").
+		Limit(1).
+		Pluck("movie_id", &movieIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(movieIDs) == 0 {
+		return 0, nil
+	}
+	return movieIDs[0], nil
+}
+
+func (r *ReportRepository) CreateReport(ctx context.Context, report *reports.ContentReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+func (r *ReportRepository) FindReportByID(ctx context.Context, reportID int64) (*reports.ContentReport, error) {
+	var report reports.ContentReport
+	err := r.db.WithContext(ctx).Where("id = ?", reportID).First(&report).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindAllReports returns the admin report queue with optional filters, oldest open reports first
+func (r *ReportRepository) FindAllReports(ctx context.Context, page, limit int, status, targetType string, movieID int64) ([]reports.AdminReportResponse, int64, error) {
+	var results []reports.AdminReportResponse
+	var totalCount int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).
+		Table("content_reports").
+		Select("content_reports.*, movies.title as movie_title, movies.taken_down_at as movie_taken_down_at").
+		Joins("JOIN movies ON movies.id = content_reports.movie_id")
+
+	if status != "" {
+		query = query.Where("content_reports.status = ?", status)
+	}
+	if targetType != "" {
+		query = query.Where("content_reports.target_type = ?", targetType)
+	}
+	if movieID > 0 {
+		query = query.Where("content_reports.movie_id = ?", movieID)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("content_reports.created_at ASC").Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+// UpdateReportStatus records an admin decision on a report
+func (r *ReportRepository) UpdateReportStatus(ctx context.Context, reportID int64, status string, note *string, handledBy string, handledAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&reports.ContentReport{}).
+		Where("id = ?", reportID).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"resolution_note": note,
+			"handled_by":      handledBy,
+			"handled_at":      handledAt,
+		}).Error
+}
+
+// TakedownMovie hides the movie and revokes every active offline license of it in one transaction
+// Returns the number of revoked licenses
+func (r *ReportRepository) TakedownMovie(ctx context.Context, movieID int64, reason string, takenDownAt time.Time) (int64, error) {
+	var revoked int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("movies").
+			Where("id = ?", movieID).
+			Updates(map[string]interface{}{
+				"taken_down_at":   takenDownAt,
+				"takedown_reason": reason,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Table("offline_licenses").
+			Where("movie_id = ? AND revoked_at IS NULL", movieID).
+			Updates(map[string]interface{}{
+				"revoked_at":    takenDownAt,
+				"revoke_reason": "title taken down",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected
+		return recordAvailabilityChange(tx, movieID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, nil
+}
+
+// RestoreMovie lifts a takedown, revoked offline licenses stay revoked and must be issued again
+func (r *ReportRepository) RestoreMovie(ctx context.Context, movieID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("movies").
+			Where("id = ?", movieID).
+			Updates(map[string]interface{}{
+				"taken_down_at":   nil,
+				"takedown_reason": nil,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return recordAvailabilityChange(tx, movieID)
+	})
+}
+
+// recordAvailabilityChange adds an AVAILABILITY_CHANGED entry to the catalog change log (see movies.CatalogChange)
+func recordAvailabilityChange(tx *gorm.DB, movieID int64) error {
+	return tx.Table("catalog_changes").Create(map[string]interface{}{
+		"movie_id":    movieID,
+		"change_type": "AVAILABILITY_CHANGED",
+	}).Error
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/reports"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+type ReportRepository interface {
+	FindMovieTakedown(ctx context.Context, movieID int64) (bool, *time.Time, error)
+	FindPublishedReviewMovieID(ctx context.Context, reviewID int64) (int64, error)
+	CreateReport(ctx context.Context, report *reports.ContentReport) error
+	FindReportByID(ctx context.Context, reportID int64) (*reports.ContentReport, error)
+	FindAllReports(ctx context.Context, page, limit int, status, targetType string, movieID int64) ([]reports.AdminReportResponse, int64, error)
+	UpdateReportStatus(ctx context.Context, reportID int64, status string, note *string, handledBy string, handledAt time.Time) error
+	TakedownMovie(ctx context.Context, movieID int64, reason string, takenDownAt time.Time) (int64, error)
+	RestoreMovie(ctx context.Context, movieID int64) error
+}
+
+type ReportUsecase struct {
+	repo ReportRepository
+}
+
+func NewReportUsecase(repo ReportRepository) *ReportUsecase {
+	return &ReportUsecase{repo: repo}
+}
+
+// CreateReport files a copyright or abuse report, reporterExtID is empty for anonymous reporters
+func (u *ReportUsecase) CreateReport(ctx context.Context, reporterExtID string, req reports.CreateReportRequest) (*reports.ReportCreatedResponse, error) {
+	movieID, err := u.resolveTargetMovie(ctx, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, apperr.Validation("description_required", nil)
+	}
+
+	report := &reports.ContentReport{
+		TargetType:    req.TargetType,
+		TargetID:      req.TargetID,
+		MovieID:       movieID,
+		Category:      req.Category,
+		ReporterName:  strings.TrimSpace(req.ReporterName),
+		ReporterEmail: strings.TrimSpace(req.ReporterEmail),
+		Description:   description,
+		Status:        reports.StatusOpen,
+	}
+	if reporterExtID != "" {
+		report.ReporterExtID = &reporterExtID
+	}
+
+	if err := u.repo.CreateReport(ctx, report); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &reports.ReportCreatedResponse{
+		ReportID:  report.ID,
+		Status:    report.Status,
+		CreatedAt: report.CreatedAt,
+	}, nil
+}
+
+// GetAllReportsAdmin lists reports for the admin queue (Admin only)
+func (u *ReportUsecase) GetAllReportsAdmin(ctx context.Context, page, limit int, status, targetType string, movieID int64) (*reports.AdminReportListWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	list, totalCount, err := u.repo.FindAllReports(ctx, page, limit, status, targetType, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &reports.AdminReportListWithPagination{
+		Reports: list,
+		Pagination: reports.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+// UpdateReport moves a report through the review workflow (Admin only)
+func (u *ReportUsecase) UpdateReport(ctx context.Context, adminExtID string, reportID int64, req reports.UpdateReportRequest) error {
+	if _, err := u.findReport(ctx, reportID); err != nil {
+		return err
+	}
+
+	var note *string
+	if trimmed := strings.TrimSpace(req.ResolutionNote); trimmed != "" {
+		note = &trimmed
+	}
+
+	if err := u.repo.UpdateReportStatus(ctx, reportID, req.Status, note, adminExtID, time.Now()); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// TakedownMovie immediately hides a movie and stops playback pending investigation (Admin only)
+// Streams are proxied through an access check on every request, so hiding the title ends them;
+// offline licenses are revoked so devices drop the download on their next revocation sync
+func (u *ReportUsecase) TakedownMovie(ctx context.Context, adminExtID string, movieID int64, req reports.TakedownRequest) (*reports.TakedownResponse, error) {
+	exists, takenDownAt, err := u.repo.FindMovieTakedown(ctx, movieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !exists {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+	if takenDownAt != nil {
+		return nil, apperr.Conflict("movie_already_taken_down", nil)
+	}
+
+	if req.ReportID > 0 {
+		report, err := u.findReport(ctx, req.ReportID)
+		if err != nil {
+			return nil, err
+		}
+		if report.MovieID != movieID {
+			return nil, apperr.Validation("report_not_for_movie", nil)
+		}
+	}
+
+	now := time.Now()
+	reason := strings.TrimSpace(req.Reason)
+	revoked, err := u.repo.TakedownMovie(ctx, movieID, reason, now)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if req.ReportID > 0 {
+		if err := u.repo.UpdateReportStatus(ctx, req.ReportID, reports.StatusInReview, &reason, adminExtID, now); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	}
+
+	return &reports.TakedownResponse{
+		MovieID:         movieID,
+		TakenDownAt:     &now,
+		Reason:          reason,
+		RevokedLicenses: revoked,
+		ReportID:        req.ReportID,
+	}, nil
+}
+
+// RestoreMovie lifts a takedown once the investigation is closed (Admin only)
+func (u *ReportUsecase) RestoreMovie(ctx context.Context, movieID int64) error {
+	exists, takenDownAt, err := u.repo.FindMovieTakedown(ctx, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !exists {
+		return apperr.NotFound("movie_not_found", nil)
+	}
+	if takenDownAt == nil {
+		return apperr.Conflict("movie_not_taken_down", nil)
+	}
+
+	if err := u.repo.RestoreMovie(ctx, movieID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// resolveTargetMovie validates the reported target and returns the movie it belongs to
+func (u *ReportUsecase) resolveTargetMovie(ctx context.Context, targetType string, targetID int64) (int64, error) {
+	switch targetType {
+	case reports.TargetMovie:
+		exists, _, err := u.repo.FindMovieTakedown(ctx, targetID)
+		if err != nil {
+			return 0, apperr.Internal(err)
+		}
+		if !exists {
+			return 0, apperr.NotFound("movie_not_found", nil)
+		}
+		return targetID, nil
+	case reports.TargetReview:
+		movieID, err := u.repo.FindPublishedReviewMovieID(ctx, targetID)
+		if err != nil {
+			return 0, apperr.Internal(err)
+		}
+		if movieID == 0 {
+			return 0, apperr.NotFound("review_not_found", nil)
+		}
+		return movieID, nil
+	default:
+		return 0, apperr.Validation("invalid_target_type", nil)
+	}
+}
+
+func (u *ReportUsecase) findReport(ctx context.Context, reportID int64) (*reports.ContentReport, error) {
+	report, err := u.repo.FindReportByID(ctx, reportID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if report == nil {
+		return nil, apperr.NotFound("report_not_found", nil)
+	}
+	return report, nil
+}
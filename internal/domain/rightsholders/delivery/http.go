@@ -0,0 +1,332 @@
+package delivery
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/rightsholders"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type RightsHolderUsecase interface {
+	CreateRightsHolder(ctx context.Context, adminExtID string, req rightsholders.CreateRightsHolderRequest) (*rightsholders.RightsHolder, error)
+	GetRightsHolders(ctx context.Context) ([]rightsholders.RightsHolder, error)
+	GetRightsHolder(ctx context.Context, holderID int64) (*rightsholders.RightsHolderResponse, error)
+	AddMember(ctx context.Context, adminExtID string, holderID int64, req rightsholders.AddMemberRequest) (*rightsholders.Member, error)
+	RemoveMember(ctx context.Context, holderID int64, userExtID string) error
+	AssignTitle(ctx context.Context, adminExtID string, holderID int64, req rightsholders.AssignTitleRequest) (*rightsholders.Title, error)
+	RemoveTitle(ctx context.Context, holderID, movieID int64) error
+	GetAnalytics(ctx context.Context, holderID int64, from, to time.Time) (*rightsholders.AnalyticsReport, error)
+	GetOwnTitles(ctx context.Context, userExtID string) ([]rightsholders.Title, error)
+	GetOwnAnalytics(ctx context.Context, userExtID string, from, to time.Time) (*rightsholders.AnalyticsReport, error)
+	UpdateShare(ctx context.Context, holderID int64, req rightsholders.UpdateShareRequest) (*rightsholders.RightsHolder, error)
+	UpdateTitleShare(ctx context.Context, holderID, movieID int64, req rightsholders.UpdateShareRequest) error
+	GetStatement(ctx context.Context, holderID int64, period string) (*rightsholders.RoyaltyStatement, error)
+	GetOwnStatement(ctx context.Context, userExtID, period string) (*rightsholders.RoyaltyStatement, error)
+}
+
+type RightsHolderHandler struct {
+	ctx     context.Context
+	usecase RightsHolderUsecase
+}
+
+func NewRightsHolderHandler(ctx context.Context, usecase RightsHolderUsecase) *RightsHolderHandler {
+	return &RightsHolderHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// CreateRightsHolder adds a studio or distributor (Admin only)
+// POST /api/v1/admin/rights-holders
+func (h *RightsHolderHandler) CreateRightsHolder(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req rightsholders.CreateRightsHolderRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.CreateRightsHolder(ctx, adminExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "rights_holder_created", result)
+}
+
+// GetRightsHolders lists every rights holder (Admin only)
+// GET /api/v1/admin/rights-holders
+func (h *RightsHolderHandler) GetRightsHolders(c echo.Context) error {
+	result, err := h.usecase.GetRightsHolders(h.ctx)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetRightsHolder returns a rights holder with its members and titles (Admin only)
+// GET /api/v1/admin/rights-holders/:id
+func (h *RightsHolderHandler) GetRightsHolder(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	result, err := h.usecase.GetRightsHolder(h.ctx, holderID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// AddMember lets an account with the RIGHTS_HOLDER role report for the rights holder (Admin only)
+// POST /api/v1/admin/rights-holders/:id/members
+func (h *RightsHolderHandler) AddMember(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	var req rightsholders.AddMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.AddMember(ctx, adminExtID, holderID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "member_added", result)
+}
+
+// RemoveMember removes an account from the rights holder (Admin only)
+// DELETE /api/v1/admin/rights-holders/:id/members/:user_id
+func (h *RightsHolderHandler) RemoveMember(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	if err := h.usecase.RemoveMember(h.ctx, holderID, c.Param("user_id")); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "member_removed", nil)
+}
+
+// AssignTitle gives the rights holder the analytics of a movie (Admin only)
+// POST /api/v1/admin/rights-holders/:id/titles
+func (h *RightsHolderHandler) AssignTitle(c echo.Context) error {
+	ctx := h.ctx
+
+	adminExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || adminExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	var req rightsholders.AssignTitleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.AssignTitle(ctx, adminExtID, holderID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "title_assigned", result)
+}
+
+// RemoveTitle takes a movie away from the rights holder (Admin only)
+// DELETE /api/v1/admin/rights-holders/:id/titles/:movie_id
+func (h *RightsHolderHandler) RemoveTitle(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("movie_id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	if err := h.usecase.RemoveTitle(h.ctx, holderID, movieID); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "title_removed", nil)
+}
+
+// GetAnalytics returns the analytics of a rights holder's titles as JSON or CSV (Admin only)
+// GET /api/v1/admin/rights-holders/:id/analytics?from=2026-01-01&to=2026-01-31&format=csv
+func (h *RightsHolderHandler) GetAnalytics(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	from, to, format, err := parseReportQuery(c)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	report, err := h.usecase.GetAnalytics(h.ctx, holderID, from, to)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return writeReport(c, report, format)
+}
+
+// GetOwnTitles lists the titles of the rights holder the account reports for (Rights holder only)
+// GET /api/v1/rights-holder/titles
+func (h *RightsHolderHandler) GetOwnTitles(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	result, err := h.usecase.GetOwnTitles(h.ctx, userExtID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetOwnAnalytics returns the views, watch time, revenue and territories of the account's titles as JSON or CSV (Rights holder only)
+// GET /api/v1/rights-holder/analytics?from=2026-01-01&to=2026-01-31&format=csv
+func (h *RightsHolderHandler) GetOwnAnalytics(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	from, to, format, err := parseReportQuery(c)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	report, err := h.usecase.GetOwnAnalytics(h.ctx, userExtID, from, to)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return writeReport(c, report, format)
+}
+
+// parseReportQuery reads the report period, the last 30 days by default, and the format
+func parseReportQuery(c echo.Context) (from, to time.Time, format string, err error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to = today.AddDate(0, 0, -30), today
+
+	if value := c.QueryParam("from"); value != "" {
+		if from, err = time.Parse(rightsholders.DayLayout, value); err != nil {
+			return from, to, "", apperr.Validation("invalid_from", "from must be YYYY-MM-DD")
+		}
+	}
+	if value := c.QueryParam("to"); value != "" {
+		if to, err = time.Parse(rightsholders.DayLayout, value); err != nil {
+			return from, to, "", apperr.Validation("invalid_to", "to must be YYYY-MM-DD")
+		}
+	}
+
+	format, err = parseFormat(c)
+	return from, to, format, err
+}
+
+// writeReport answers with the report as JSON, or as CSV with a row per title (territory ALL) followed by its territories
+func writeReport(c echo.Context, report *rightsholders.AnalyticsReport, format string) error {
+	// Revenue figures of the partner, proxies and browsers must not keep a copy
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	if format != "csv" {
+		return response.Success(c, http.StatusOK, "success", report)
+	}
+
+	filename := fmt.Sprintf("analytics-%d-%s-%s.csv", report.RightsHolderID, report.From, report.To)
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	writer.Write([]string{"from", "to", "movie_id", "movie_title", "territory", "views", "watch_seconds", "orders", "revenue", "refunds", "net_revenue"})
+	for _, title := range report.Titles {
+		movieID := strconv.FormatInt(title.MovieID, 10)
+		writer.Write([]string{
+			report.From,
+			report.To,
+			movieID,
+			title.MovieTitle,
+			"ALL",
+			strconv.FormatInt(title.Views, 10),
+			strconv.FormatInt(title.WatchSeconds, 10),
+			strconv.FormatInt(title.Orders, 10),
+			formatAmount(title.Revenue),
+			formatAmount(title.Refunds),
+			formatAmount(title.NetRevenue),
+		})
+		// Views and watch time are not known per territory
+		for _, territory := range title.Territories {
+			writer.Write([]string{
+				report.From,
+				report.To,
+				movieID,
+				title.MovieTitle,
+				territory.Territory,
+				"",
+				"",
+				strconv.FormatInt(territory.Orders, 10),
+				formatAmount(territory.Revenue),
+				formatAmount(territory.Refunds),
+				formatAmount(territory.NetRevenue),
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,169 @@
+package delivery
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/rightsholders"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// UpdateShare sets the revenue share of the rights holder's titles without their own (Admin only)
+// PUT /api/v1/admin/rights-holders/:id/share
+func (h *RightsHolderHandler) UpdateShare(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	var req rightsholders.UpdateShareRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdateShare(h.ctx, holderID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "share_updated", result)
+}
+
+// UpdateTitleShare sets the revenue share of one title, a null share falls back to the rights holder's (Admin only)
+// PUT /api/v1/admin/rights-holders/:id/titles/:movie_id/share
+func (h *RightsHolderHandler) UpdateTitleShare(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("movie_id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	var req rightsholders.UpdateShareRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	if err := h.usecase.UpdateTitleShare(h.ctx, holderID, movieID, req); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "share_updated", nil)
+}
+
+// GetStatement returns the royalty statement of a rights holder for a month as JSON or CSV (Admin only)
+// GET /api/v1/admin/rights-holders/:id/statements/:period?format=csv
+func (h *RightsHolderHandler) GetStatement(c echo.Context) error {
+	holderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_rights_holder_id", err.Error())
+	}
+
+	format, err := parseFormat(c)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	statement, err := h.usecase.GetStatement(h.ctx, holderID, c.Param("period"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return writeStatement(c, statement, format)
+}
+
+// GetOwnStatement returns the royalty statement of the account's rights holder for a month as JSON or CSV (Rights holder only)
+// GET /api/v1/rights-holder/statements/:period?format=csv
+func (h *RightsHolderHandler) GetOwnStatement(c echo.Context) error {
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	format, err := parseFormat(c)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	statement, err := h.usecase.GetOwnStatement(h.ctx, userExtID, c.Param("period"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return writeStatement(c, statement, format)
+}
+
+// parseFormat reads the response format, json by default
+func parseFormat(c echo.Context) (string, error) {
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return "", apperr.Validation("invalid_format", "format must be json or csv")
+	}
+	return format, nil
+}
+
+// writeStatement answers with the statement as JSON, or as CSV with a row per title followed by a TOTAL row
+func writeStatement(c echo.Context, statement *rightsholders.RoyaltyStatement, format string) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	if format != "csv" {
+		return response.Success(c, http.StatusOK, "success", statement)
+	}
+
+	filename := fmt.Sprintf("royalties-%d-%s.csv", statement.RightsHolderID, statement.Period)
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	provisional := strconv.FormatBool(statement.Provisional)
+	writer := csv.NewWriter(c.Response())
+	writer.Write([]string{"period", "provisional", "currency", "movie_id", "movie_title", "orders", "revenue", "refunds", "fees", "net_revenue", "share_percent", "payable"})
+	for _, line := range statement.Lines {
+		writer.Write([]string{
+			statement.Period,
+			provisional,
+			statement.Currency,
+			strconv.FormatInt(line.MovieID, 10),
+			line.MovieTitle,
+			strconv.FormatInt(line.Orders, 10),
+			formatAmount(line.Revenue),
+			formatAmount(line.Refunds),
+			formatAmount(line.Fees),
+			formatAmount(line.NetRevenue),
+			formatAmount(line.SharePercent),
+			formatAmount(line.Payable),
+		})
+	}
+	writer.Write([]string{
+		statement.Period,
+		provisional,
+		statement.Currency,
+		"",
+		"TOTAL",
+		strconv.FormatInt(statement.Orders, 10),
+		formatAmount(statement.Revenue),
+		formatAmount(statement.Refunds),
+		formatAmount(statement.Fees),
+		formatAmount(statement.NetRevenue),
+		"",
+		formatAmount(statement.Payable),
+	})
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/rightsholders"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RightsHolderRepository struct {
+	db *gorm.DB
+}
+
+func NewRightsHolderRepository(db *gorm.DB) *RightsHolderRepository {
+	return &RightsHolderRepository{db: db}
+}
+
+func (r *RightsHolderRepository) CreateRightsHolder(ctx context.Context, holder *rightsholders.RightsHolder) error {
+	return r.db.WithContext(ctx).Create(holder).Error
+}
+
+// FindRightsHolders lists every rights holder by name
+func (r *RightsHolderRepository) FindRightsHolders(ctx context.Context) ([]rightsholders.RightsHolder, error) {
+	var holders []rightsholders.RightsHolder
+	err := r.db.WithContext(ctx).Order("name ASC, id ASC").Find(&holders).Error
+	return holders, err
+}
+
+// FindRightsHolderByID returns nil when the rights holder does not exist
+func (r *RightsHolderRepository) FindRightsHolderByID(ctx context.Context, holderID int64) (*rightsholders.RightsHolder, error) {
+	var holder rightsholders.RightsHolder
+	err := r.db.WithContext(ctx).Where("id = ?", holderID).First(&holder).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &holder, nil
+}
+
+// FindMembers lists the accounts of a rights holder with their names and emails
+func (r *RightsHolderRepository) FindMembers(ctx context.Context, holderID int64) ([]rightsholders.Member, error) {
+	var members []rightsholders.Member
+	err := r.db.WithContext(ctx).
+		Table("rights_holder_members m").
+		Select("m.user_ext_id, m.rights_holder_id, m.added_by, m.created_at, u.name, u.email").
+		Joins("JOIN users u ON u.ext_id = m.user_ext_id").
+		Where("m.rights_holder_id = ?", holderID).
+		Order("u.email ASC").
+		Scan(&members).Error
+	return members, err
+}
+
+// FindMembership returns the membership of an account, nil when it belongs to no rights holder
+func (r *RightsHolderRepository) FindMembership(ctx context.Context, userExtID string) (*rightsholders.Member, error) {
+	var member rightsholders.Member
+	err := r.db.WithContext(ctx).Where("user_ext_id = ?", userExtID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// accountRole is the ext id and role of an account
+type accountRole struct {
+	ExtID string
+	Name  string
+	Role  string
+}
+
+// FindUserByEmail returns the ext id, name and role of the account with the email, found is false if there is none
+func (r *RightsHolderRepository) FindUserByEmail(ctx context.Context, email string) (extID, name, role string, found bool, err error) {
+	var account accountRole
+	err = r.db.WithContext(ctx).
+		Table("users").
+		Select("ext_id, name, role").
+		Where("email = ?", email).
+		Take(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", "", false, nil
+		}
+		return "", "", "", false, err
+	}
+	return account.ExtID, account.Name, account.Role, true, nil
+}
+
+func (r *RightsHolderRepository) AddMember(ctx context.Context, member *rightsholders.Member) error {
+	return r.db.WithContext(ctx).Create(member).Error
+}
+
+// RemoveMember reports whether the account was a member of the rights holder
+func (r *RightsHolderRepository) RemoveMember(ctx context.Context, holderID int64, userExtID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("rights_holder_id = ? AND user_ext_id = ?", holderID, userExtID).
+		Delete(&rightsholders.Member{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindMovie returns the title of a movie, found is false if it does not exist
+func (r *RightsHolderRepository) FindMovie(ctx context.Context, movieID int64) (title string, found bool, err error) {
+	err = r.db.WithContext(ctx).
+		Table("movies").
+		Select("title").
+		Where("id = ? AND deleted_at IS NULL", movieID).
+		Take(&title).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return title, true, nil
+}
+
+// FindTitles lists the movies of a rights holder with their titles, deleted movies included for their history
+func (r *RightsHolderRepository) FindTitles(ctx context.Context, holderID int64) ([]rightsholders.Title, error) {
+	var titles []rightsholders.Title
+	err := r.db.WithContext(ctx).
+		Table("rights_holder_titles t").
+		Select("t.movie_id, t.rights_holder_id, t.share_percent, t.assigned_by, t.created_at, m.title AS movie_title").
+		Joins("JOIN movies m ON m.id = t.movie_id").
+		Where("t.rights_holder_id = ?", holderID).
+		Order("m.title ASC, t.movie_id ASC").
+		Scan(&titles).Error
+	return titles, err
+}
+
+// AssignTitle assigns the movie to the rights holder, a previous rights holder loses it along with its share of the title
+func (r *RightsHolderRepository) AssignTitle(ctx context.Context, title *rightsholders.Title) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "movie_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rights_holder_id", "share_percent", "assigned_by", "created_at"}),
+		}).
+		Create(title).Error
+}
+
+// UpdateRightsHolderShare sets the revenue share of the titles without their own
+func (r *RightsHolderRepository) UpdateRightsHolderShare(ctx context.Context, holderID int64, sharePercent float64) error {
+	return r.db.WithContext(ctx).Model(&rightsholders.RightsHolder{}).Where("id = ?", holderID).Update("share_percent", sharePercent).Error
+}
+
+// UpdateTitleShare sets the revenue share of one title, nil falls back to the rights holder's.
+// It reports whether the movie belongs to the rights holder.
+func (r *RightsHolderRepository) UpdateTitleShare(ctx context.Context, holderID, movieID int64, sharePercent *float64) (bool, error) {
+	// MySQL reports no affected row when the share does not change, so the title is looked up first
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&rightsholders.Title{}).
+		Where("rights_holder_id = ? AND movie_id = ?", holderID, movieID).
+		Count(&count).Error
+	if err != nil || count == 0 {
+		return false, err
+	}
+	return true, r.db.WithContext(ctx).
+		Model(&rightsholders.Title{}).
+		Where("rights_holder_id = ? AND movie_id = ?", holderID, movieID).
+		Update("share_percent", sharePercent).Error
+}
+
+// RemoveTitle reports whether the movie belonged to the rights holder
+func (r *RightsHolderRepository) RemoveTitle(ctx context.Context, holderID, movieID int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("rights_holder_id = ? AND movie_id = ?", holderID, movieID).
+		Delete(&rightsholders.Title{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountViews sums the daily views of the movies on the days [from, to]
+func (r *RightsHolderRepository) CountViews(ctx context.Context, movieIDs []int64, from, to time.Time) ([]rightsholders.TitleCount, error) {
+	var counts []rightsholders.TitleCount
+	err := r.db.WithContext(ctx).
+		Table("movie_daily_views").
+		Select("movie_id, SUM(views) AS count").
+		Where("movie_id IN ? AND view_date BETWEEN ? AND ?", movieIDs, from.Format(rightsholders.DayLayout), to.Format(rightsholders.DayLayout)).
+		Group("movie_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// SumWatchSeconds sums the furthest positions of the viewers who watched the movies in [from, until)
+func (r *RightsHolderRepository) SumWatchSeconds(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.TitleCount, error) {
+	var counts []rightsholders.TitleCount
+	err := r.db.WithContext(ctx).
+		Table("watch_progress").
+		Select("movie_id, SUM(position_seconds) AS count").
+		Where("movie_id IN ? AND updated_at >= ? AND updated_at < ?", movieIDs, from, until).
+		Group("movie_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// FindSales returns the orders paid and the amounts refunded in [from, until) per movie and territory.
+// Refunded orders were paid once, their refund is counted on the day it was made.
+// Payments simulated by an admin never reached a gateway and are left out.
+func (r *RightsHolderRepository) FindSales(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.Sales, error) {
+	var paid []rightsholders.Sales
+	err := r.db.WithContext(ctx).
+		Table("orders").
+		Select("movie_id, COALESCE(region, ?) AS territory, COUNT(*) AS orders, SUM(amount) AS revenue", rightsholders.UnknownTerritory).
+		Where("movie_id IN ? AND payment_status IN ('PAID', 'REFUNDED') AND paid_at >= ? AND paid_at < ?", movieIDs, from, until).
+		Where("simulated_by IS NULL").
+		Group("movie_id, territory").
+		Scan(&paid).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var refunded []rightsholders.Sales
+	err = r.db.WithContext(ctx).
+		Table("order_refunds rf").
+		Select("o.movie_id, COALESCE(o.region, ?) AS territory, SUM(rf.amount) AS refunds", rightsholders.UnknownTerritory).
+		Joins("JOIN orders o ON o.id = rf.order_id").
+		Where("o.movie_id IN ? AND rf.created_at >= ? AND rf.created_at < ?", movieIDs, from, until).
+		Where("o.simulated_by IS NULL").
+		Group("o.movie_id, territory").
+		Scan(&refunded).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// A territory with only refunds in the period still shows up
+	index := make(map[rightsholders.Sales]int, len(paid))
+	for i, sales := range paid {
+		index[rightsholders.Sales{MovieID: sales.MovieID, Territory: sales.Territory}] = i
+	}
+	for _, refund := range refunded {
+		if i, ok := index[rightsholders.Sales{MovieID: refund.MovieID, Territory: refund.Territory}]; ok {
+			paid[i].Refunds = refund.Refunds
+			continue
+		}
+		paid = append(paid, refund)
+	}
+	return paid, nil
+}
+
+// FindProviderSales returns the orders paid in [from, until) per movie and payment provider, refunded orders included.
+// Simulated payments are left out, no gateway fee was charged for them.
+func (r *RightsHolderRepository) FindProviderSales(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.ProviderSales, error) {
+	var sales []rightsholders.ProviderSales
+	err := r.db.WithContext(ctx).
+		Table("orders").
+		Select("movie_id, payment_provider, COUNT(*) AS orders, SUM(amount) AS revenue").
+		Where("movie_id IN ? AND payment_status IN ('PAID', 'REFUNDED') AND paid_at >= ? AND paid_at < ?", movieIDs, from, until).
+		Where("simulated_by IS NULL").
+		Group("movie_id, payment_provider").
+		Scan(&sales).Error
+	return sales, err
+}
+
+// SumRefunds returns the amounts refunded in [from, until) per movie, whenever the order was paid.
+// Refunds of simulated payments are left out like their payments.
+func (r *RightsHolderRepository) SumRefunds(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.TitleAmount, error) {
+	var refunds []rightsholders.TitleAmount
+	err := r.db.WithContext(ctx).
+		Table("order_refunds rf").
+		Select("o.movie_id, SUM(rf.amount) AS amount").
+		Joins("JOIN orders o ON o.id = rf.order_id").
+		Where("o.movie_id IN ? AND rf.created_at >= ? AND rf.created_at < ?", movieIDs, from, until).
+		Where("o.simulated_by IS NULL").
+		Group("o.movie_id").
+		Scan(&refunds).Error
+	return refunds, err
+}
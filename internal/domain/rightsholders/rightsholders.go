@@ -0,0 +1,199 @@
+package rightsholders
+
+import "time"
+
+// DayLayout is the format of the report period days, days are in UTC
+const DayLayout = "2006-01-02"
+
+// MaxReportRange is the longest period one analytics report covers
+const MaxReportRange = 366 * 24 * time.Hour
+
+// PeriodLayout is the format of a royalty settlement period, a calendar month in UTC
+const PeriodLayout = "2006-01"
+
+// UnknownTerritory groups orders placed without a known country, e.g. before territories were recorded
+const UnknownTerritory = "UNKNOWN"
+
+// RightsHolder is a studio or distributor owning the rights of some titles.
+// Its members are accounts with the RIGHTS_HOLDER role, they only see the analytics of its titles.
+type RightsHolder struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name         string    `json:"name" gorm:"type:varchar(255);not null"`
+	SharePercent float64   `json:"share_percent" gorm:"type:decimal(5,2);not null;default:0"` // revenue share of titles without their own
+	CreatedBy    string    `json:"created_by" gorm:"type:varchar(50);not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for RightsHolder
+func (RightsHolder) TableName() string {
+	return "rights_holders"
+}
+
+// Member links an account to the rights holder it reports for, an account belongs to one rights holder
+type Member struct {
+	UserExtID      string    `json:"user_ext_id" gorm:"primaryKey;column:user_ext_id"`
+	RightsHolderID int64     `json:"rights_holder_id" gorm:"not null"`
+	AddedBy        string    `json:"added_by" gorm:"type:varchar(50);not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Loaded via join
+	Name  string `json:"name" gorm:"->"`
+	Email string `json:"email" gorm:"->"`
+}
+
+// TableName overrides the table name for Member
+func (Member) TableName() string {
+	return "rights_holder_members"
+}
+
+// Title links a movie to its rights holder, a movie has at most one
+type Title struct {
+	MovieID        int64     `json:"movie_id" gorm:"primaryKey;autoIncrement:false"`
+	RightsHolderID int64     `json:"rights_holder_id" gorm:"not null"`
+	SharePercent   *float64  `json:"share_percent" gorm:"type:decimal(5,2)"` // nil uses the share of the rights holder
+	AssignedBy     string    `json:"assigned_by" gorm:"type:varchar(50);not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Loaded via join
+	MovieTitle string `json:"movie_title" gorm:"->"`
+}
+
+// TableName overrides the table name for Title
+func (Title) TableName() string {
+	return "rights_holder_titles"
+}
+
+// Request DTOs
+
+// CreateRightsHolderRequest represents a new rights holder
+type CreateRightsHolderRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+// AddMemberRequest adds an account with the RIGHTS_HOLDER role to a rights holder
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// AssignTitleRequest assigns a movie to a rights holder, replacing a previous rights holder
+type AssignTitleRequest struct {
+	MovieID int64 `json:"movie_id" validate:"required,gt=0"`
+}
+
+// UpdateShareRequest sets the revenue share of a rights holder, or of one title where nil falls back to the rights holder's
+type UpdateShareRequest struct {
+	SharePercent *float64 `json:"share_percent" validate:"omitempty,gte=0,lte=100"`
+}
+
+// Response DTOs
+
+// RightsHolderResponse represents a rights holder with its members and titles
+type RightsHolderResponse struct {
+	RightsHolder
+	Members []Member `json:"members"`
+	Titles  []Title  `json:"titles"`
+}
+
+// TitleAnalytics are the numbers of one title in the report period.
+// Views count viewers starting the movie once per day, watch time is the furthest position
+// of the viewers who watched in the period. Revenue is the amount of the orders paid in the period,
+// refunds the amount refunded in the period.
+type TitleAnalytics struct {
+	MovieID      int64                `json:"movie_id"`
+	MovieTitle   string               `json:"movie_title"`
+	Views        int64                `json:"views"`
+	WatchSeconds int64                `json:"watch_seconds"`
+	Orders       int64                `json:"orders"`
+	Revenue      float64              `json:"revenue"`
+	Refunds      float64              `json:"refunds"`
+	NetRevenue   float64              `json:"net_revenue"`
+	Territories  []TerritoryAnalytics `json:"territories"`
+}
+
+// TerritoryAnalytics are the sales of a title in one country, the country the order was placed from
+type TerritoryAnalytics struct {
+	Territory  string  `json:"territory"`
+	Orders     int64   `json:"orders"`
+	Revenue    float64 `json:"revenue"`
+	Refunds    float64 `json:"refunds"`
+	NetRevenue float64 `json:"net_revenue"`
+}
+
+// AnalyticsReport is the analytics of every title of a rights holder in [From, To]
+type AnalyticsReport struct {
+	RightsHolderID   int64            `json:"rights_holder_id"`
+	RightsHolderName string           `json:"rights_holder_name"`
+	From             string           `json:"from"`
+	To               string           `json:"to"`
+	Titles           []TitleAnalytics `json:"titles"`
+}
+
+// RoyaltyLine is what one title earned the rights holder in a settlement period.
+// Net is the revenue of the orders paid in the period less the refunds made and the payment fees,
+// Payable is the rights holder's share of it. A negative payable is deducted from the other titles.
+type RoyaltyLine struct {
+	MovieID      int64   `json:"movie_id"`
+	MovieTitle   string  `json:"movie_title"`
+	Orders       int64   `json:"orders"`
+	Revenue      float64 `json:"revenue"`
+	Refunds      float64 `json:"refunds"`
+	Fees         float64 `json:"fees"`
+	NetRevenue   float64 `json:"net_revenue"`
+	SharePercent float64 `json:"share_percent"`
+	Payable      float64 `json:"payable"`
+}
+
+// RoyaltyStatement is what a rights holder is owed for a settlement period.
+// A statement of the running month is provisional, its numbers still change.
+type RoyaltyStatement struct {
+	RightsHolderID   int64         `json:"rights_holder_id"`
+	RightsHolderName string        `json:"rights_holder_name"`
+	Period           string        `json:"period"`
+	Currency         string        `json:"currency"`
+	Provisional      bool          `json:"provisional"`
+	Lines            []RoyaltyLine `json:"lines"`
+	Orders           int64         `json:"orders"`
+	Revenue          float64       `json:"revenue"`
+	Refunds          float64       `json:"refunds"`
+	Fees             float64       `json:"fees"`
+	NetRevenue       float64       `json:"net_revenue"`
+	Payable          float64       `json:"payable"`
+}
+
+// PaymentFee is what a payment provider keeps of an order, Percent of the amount plus Fixed
+type PaymentFee struct {
+	Percent float64
+	Fixed   float64
+}
+
+// Repository rows
+
+// TitleCount is a per-title count loaded by the repository
+type TitleCount struct {
+	MovieID int64
+	Count   int64
+}
+
+// TitleAmount is a per-title amount loaded by the repository
+type TitleAmount struct {
+	MovieID int64
+	Amount  float64
+}
+
+// ProviderSales are the orders of a title paid through one payment provider, loaded by the repository
+type ProviderSales struct {
+	MovieID         int64
+	PaymentProvider string
+	Orders          int64
+	Revenue         float64
+}
+
+// Sales are the orders of a title in a territory loaded by the repository
+type Sales struct {
+	MovieID   int64
+	Territory string
+	Orders    int64
+	Revenue   float64
+	Refunds   float64
+}
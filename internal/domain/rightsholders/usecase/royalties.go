@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/rightsholders"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// UpdateShare sets the revenue share of the rights holder's titles without their own (Admin only)
+func (u *RightsHolderUsecase) UpdateShare(ctx context.Context, holderID int64, req rightsholders.UpdateShareRequest) (*rightsholders.RightsHolder, error) {
+	if req.SharePercent == nil {
+		return nil, apperr.Validation("share_percent_required", nil)
+	}
+
+	holder, err := u.findRightsHolder(ctx, holderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.UpdateRightsHolderShare(ctx, holderID, *req.SharePercent); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	holder.SharePercent = *req.SharePercent
+	return holder, nil
+}
+
+// UpdateTitleShare sets the revenue share of one title, nil falls back to the rights holder's (Admin only)
+func (u *RightsHolderUsecase) UpdateTitleShare(ctx context.Context, holderID, movieID int64, req rightsholders.UpdateShareRequest) error {
+	updated, err := u.repo.UpdateTitleShare(ctx, holderID, movieID, req.SharePercent)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !updated {
+		return apperr.NotFound("title_not_found", nil)
+	}
+	return nil
+}
+
+// GetStatement returns what the rights holder is owed for a month, period is YYYY-MM (Admin only)
+func (u *RightsHolderUsecase) GetStatement(ctx context.Context, holderID int64, period string) (*rightsholders.RoyaltyStatement, error) {
+	holder, err := u.findRightsHolder(ctx, holderID)
+	if err != nil {
+		return nil, err
+	}
+	return u.buildStatement(ctx, *holder, period)
+}
+
+// GetOwnStatement returns what the rights holder the account reports for is owed for a month
+func (u *RightsHolderUsecase) GetOwnStatement(ctx context.Context, userExtID, period string) (*rightsholders.RoyaltyStatement, error) {
+	holder, err := u.findOwnRightsHolder(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+	return u.buildStatement(ctx, *holder, period)
+}
+
+// buildStatement computes the royalties of every title of the rights holder in the month.
+// Each title's orders paid in the month less the refunds made in it and the payment fees are shared
+// by the title's percentage. Shares apply as they are now, not as they were during the month.
+func (u *RightsHolderUsecase) buildStatement(ctx context.Context, holder rightsholders.RightsHolder, period string) (*rightsholders.RoyaltyStatement, error) {
+	from, err := time.Parse(rightsholders.PeriodLayout, period)
+	if err != nil {
+		return nil, apperr.Validation("invalid_period", "period must be formatted as YYYY-MM")
+	}
+	until := from.AddDate(0, 1, 0)
+	now := time.Now().UTC()
+	if from.After(now) {
+		return nil, apperr.Validation("period_in_future", nil)
+	}
+
+	statement := &rightsholders.RoyaltyStatement{
+		RightsHolderID:   holder.ID,
+		RightsHolderName: holder.Name,
+		Period:           period,
+		Currency:         u.royalties.Currency,
+		Provisional:      until.After(now),
+		Lines:            []rightsholders.RoyaltyLine{},
+	}
+
+	titles, err := u.repo.FindTitles(ctx, holder.ID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if len(titles) == 0 {
+		return statement, nil
+	}
+
+	movieIDs := make([]int64, len(titles))
+	byMovie := make(map[int64]*rightsholders.RoyaltyLine, len(titles))
+	statement.Lines = make([]rightsholders.RoyaltyLine, len(titles))
+	for i, title := range titles {
+		movieIDs[i] = title.MovieID
+		share := holder.SharePercent
+		if title.SharePercent != nil {
+			share = *title.SharePercent
+		}
+		statement.Lines[i] = rightsholders.RoyaltyLine{MovieID: title.MovieID, MovieTitle: title.MovieTitle, SharePercent: share}
+		byMovie[title.MovieID] = &statement.Lines[i]
+	}
+
+	sales, err := u.repo.FindProviderSales(ctx, movieIDs, from, until)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	for _, s := range sales {
+		line := byMovie[s.MovieID]
+		line.Orders += s.Orders
+		line.Revenue += s.Revenue
+		fee := u.royalties.Fees[s.PaymentProvider]
+		line.Fees += s.Revenue*fee.Percent/100 + fee.Fixed*float64(s.Orders)
+	}
+
+	refunds, err := u.repo.SumRefunds(ctx, movieIDs, from, until)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	for _, refund := range refunds {
+		byMovie[refund.MovieID].Refunds = refund.Amount
+	}
+
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		line.Revenue = roundAmount(line.Revenue)
+		line.Refunds = roundAmount(line.Refunds)
+		line.Fees = roundAmount(line.Fees)
+		line.NetRevenue = roundAmount(line.Revenue - line.Refunds - line.Fees)
+		line.Payable = roundAmount(line.NetRevenue * line.SharePercent / 100)
+
+		statement.Orders += line.Orders
+		statement.Revenue += line.Revenue
+		statement.Refunds += line.Refunds
+		statement.Fees += line.Fees
+		statement.NetRevenue += line.NetRevenue
+		statement.Payable += line.Payable
+	}
+	statement.Revenue = roundAmount(statement.Revenue)
+	statement.Refunds = roundAmount(statement.Refunds)
+	statement.Fees = roundAmount(statement.Fees)
+	statement.NetRevenue = roundAmount(statement.NetRevenue)
+	statement.Payable = roundAmount(statement.Payable)
+
+	return statement, nil
+}
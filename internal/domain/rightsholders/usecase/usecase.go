@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/rightsholders"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+)
+
+type RightsHolderRepository interface {
+	CreateRightsHolder(ctx context.Context, holder *rightsholders.RightsHolder) error
+	FindRightsHolders(ctx context.Context) ([]rightsholders.RightsHolder, error)
+	FindRightsHolderByID(ctx context.Context, holderID int64) (*rightsholders.RightsHolder, error)
+	FindMembers(ctx context.Context, holderID int64) ([]rightsholders.Member, error)
+	FindMembership(ctx context.Context, userExtID string) (*rightsholders.Member, error)
+	FindUserByEmail(ctx context.Context, email string) (extID, name, role string, found bool, err error)
+	AddMember(ctx context.Context, member *rightsholders.Member) error
+	RemoveMember(ctx context.Context, holderID int64, userExtID string) (bool, error)
+	FindMovie(ctx context.Context, movieID int64) (title string, found bool, err error)
+	FindTitles(ctx context.Context, holderID int64) ([]rightsholders.Title, error)
+	AssignTitle(ctx context.Context, title *rightsholders.Title) error
+	RemoveTitle(ctx context.Context, holderID, movieID int64) (bool, error)
+	CountViews(ctx context.Context, movieIDs []int64, from, to time.Time) ([]rightsholders.TitleCount, error)
+	SumWatchSeconds(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.TitleCount, error)
+	FindSales(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.Sales, error)
+	UpdateRightsHolderShare(ctx context.Context, holderID int64, sharePercent float64) error
+	UpdateTitleShare(ctx context.Context, holderID, movieID int64, sharePercent *float64) (bool, error)
+	FindProviderSales(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.ProviderSales, error)
+	SumRefunds(ctx context.Context, movieIDs []int64, from, until time.Time) ([]rightsholders.TitleAmount, error)
+}
+
+// RoyaltyOptions configures the royalty statements
+type RoyaltyOptions struct {
+	Currency string                              // printed on the statements, amounts are not converted
+	Fees     map[string]rightsholders.PaymentFee // per payment provider, providers missing keep nothing
+}
+
+type RightsHolderUsecase struct {
+	repo      RightsHolderRepository
+	royalties RoyaltyOptions
+}
+
+func NewRightsHolderUsecase(repo RightsHolderRepository, royalties RoyaltyOptions) *RightsHolderUsecase {
+	return &RightsHolderUsecase{repo: repo, royalties: royalties}
+}
+
+// CreateRightsHolder adds a studio or distributor (Admin only)
+func (u *RightsHolderUsecase) CreateRightsHolder(ctx context.Context, adminExtID string, req rightsholders.CreateRightsHolderRequest) (*rightsholders.RightsHolder, error) {
+	holder := &rightsholders.RightsHolder{
+		Name:      strings.TrimSpace(req.Name),
+		CreatedBy: adminExtID,
+	}
+	if err := u.repo.CreateRightsHolder(ctx, holder); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return holder, nil
+}
+
+// GetRightsHolders lists every rights holder (Admin only)
+func (u *RightsHolderUsecase) GetRightsHolders(ctx context.Context) ([]rightsholders.RightsHolder, error) {
+	holders, err := u.repo.FindRightsHolders(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if holders == nil {
+		holders = []rightsholders.RightsHolder{}
+	}
+	return holders, nil
+}
+
+// GetRightsHolder returns a rights holder with its members and titles (Admin only)
+func (u *RightsHolderUsecase) GetRightsHolder(ctx context.Context, holderID int64) (*rightsholders.RightsHolderResponse, error) {
+	holder, err := u.findRightsHolder(ctx, holderID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := u.repo.FindMembers(ctx, holderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	titles, err := u.repo.FindTitles(ctx, holderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if members == nil {
+		members = []rightsholders.Member{}
+	}
+	if titles == nil {
+		titles = []rightsholders.Title{}
+	}
+
+	return &rightsholders.RightsHolderResponse{RightsHolder: *holder, Members: members, Titles: titles}, nil
+}
+
+// AddMember lets an account with the RIGHTS_HOLDER role report for the rights holder (Admin only)
+func (u *RightsHolderUsecase) AddMember(ctx context.Context, adminExtID string, holderID int64, req rightsholders.AddMemberRequest) (*rightsholders.Member, error) {
+	if _, err := u.findRightsHolder(ctx, holderID); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	userExtID, name, role, found, err := u.repo.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !found {
+		return nil, apperr.NotFound("user_not_found", nil)
+	}
+	// The role is granted separately, so removing it cuts off the account without touching the membership
+	if role != constant.RoleRightsHolder {
+		return nil, apperr.Validation("user_not_rights_holder", "the account needs the RIGHTS_HOLDER role")
+	}
+
+	existing, err := u.repo.FindMembership(ctx, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if existing != nil {
+		return nil, apperr.Conflict("user_already_member", "the account already reports for a rights holder")
+	}
+
+	member := &rightsholders.Member{
+		UserExtID:      userExtID,
+		RightsHolderID: holderID,
+		AddedBy:        adminExtID,
+	}
+	if err := u.repo.AddMember(ctx, member); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	member.Name = name
+	member.Email = email
+	return member, nil
+}
+
+// RemoveMember removes an account from the rights holder (Admin only)
+func (u *RightsHolderUsecase) RemoveMember(ctx context.Context, holderID int64, userExtID string) error {
+	removed, err := u.repo.RemoveMember(ctx, holderID, userExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !removed {
+		return apperr.NotFound("member_not_found", nil)
+	}
+	return nil
+}
+
+// AssignTitle gives the rights holder the analytics of a movie, a previous rights holder loses them (Admin only)
+func (u *RightsHolderUsecase) AssignTitle(ctx context.Context, adminExtID string, holderID int64, req rightsholders.AssignTitleRequest) (*rightsholders.Title, error) {
+	if _, err := u.findRightsHolder(ctx, holderID); err != nil {
+		return nil, err
+	}
+
+	movieTitle, found, err := u.repo.FindMovie(ctx, req.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !found {
+		return nil, apperr.NotFound("movie_not_found", nil)
+	}
+
+	title := &rightsholders.Title{
+		MovieID:        req.MovieID,
+		RightsHolderID: holderID,
+		AssignedBy:     adminExtID,
+		CreatedAt:      time.Now(),
+	}
+	if err := u.repo.AssignTitle(ctx, title); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	title.MovieTitle = movieTitle
+	return title, nil
+}
+
+// RemoveTitle takes a movie away from the rights holder (Admin only)
+func (u *RightsHolderUsecase) RemoveTitle(ctx context.Context, holderID, movieID int64) error {
+	removed, err := u.repo.RemoveTitle(ctx, holderID, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !removed {
+		return apperr.NotFound("title_not_found", nil)
+	}
+	return nil
+}
+
+// GetAnalytics returns the analytics of a rights holder's titles on the days [from, to] (Admin only)
+func (u *RightsHolderUsecase) GetAnalytics(ctx context.Context, holderID int64, from, to time.Time) (*rightsholders.AnalyticsReport, error) {
+	holder, err := u.findRightsHolder(ctx, holderID)
+	if err != nil {
+		return nil, err
+	}
+	return u.buildReport(ctx, *holder, from, to)
+}
+
+// GetOwnTitles lists the titles of the rights holder the account reports for
+func (u *RightsHolderUsecase) GetOwnTitles(ctx context.Context, userExtID string) ([]rightsholders.Title, error) {
+	holder, err := u.findOwnRightsHolder(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	titles, err := u.repo.FindTitles(ctx, holder.ID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if titles == nil {
+		titles = []rightsholders.Title{}
+	}
+	return titles, nil
+}
+
+// GetOwnAnalytics returns the analytics of the titles of the rights holder the account reports for
+func (u *RightsHolderUsecase) GetOwnAnalytics(ctx context.Context, userExtID string, from, to time.Time) (*rightsholders.AnalyticsReport, error) {
+	holder, err := u.findOwnRightsHolder(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+	return u.buildReport(ctx, *holder, from, to)
+}
+
+func (u *RightsHolderUsecase) findRightsHolder(ctx context.Context, holderID int64) (*rightsholders.RightsHolder, error) {
+	holder, err := u.repo.FindRightsHolderByID(ctx, holderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if holder == nil {
+		return nil, apperr.NotFound("rights_holder_not_found", nil)
+	}
+	return holder, nil
+}
+
+// findOwnRightsHolder returns the rights holder of the account, accounts without one see nothing
+func (u *RightsHolderUsecase) findOwnRightsHolder(ctx context.Context, userExtID string) (*rightsholders.RightsHolder, error) {
+	member, err := u.repo.FindMembership(ctx, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if member == nil {
+		return nil, apperr.Forbidden("not_a_rights_holder_member", "the account does not report for a rights holder")
+	}
+	return u.findRightsHolder(ctx, member.RightsHolderID)
+}
+
+// buildReport collects the views, watch time and sales of every title of the rights holder on the days [from, to]
+func (u *RightsHolderUsecase) buildReport(ctx context.Context, holder rightsholders.RightsHolder, from, to time.Time) (*rightsholders.AnalyticsReport, error) {
+	if to.Before(from) || to.Sub(from) > rightsholders.MaxReportRange {
+		return nil, apperr.Validation("invalid_report_range", "to must not be before from and the range at most 366 days")
+	}
+	until := to.AddDate(0, 0, 1)
+
+	report := &rightsholders.AnalyticsReport{
+		RightsHolderID:   holder.ID,
+		RightsHolderName: holder.Name,
+		From:             from.Format(rightsholders.DayLayout),
+		To:               to.Format(rightsholders.DayLayout),
+		Titles:           []rightsholders.TitleAnalytics{},
+	}
+
+	titles, err := u.repo.FindTitles(ctx, holder.ID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if len(titles) == 0 {
+		return report, nil
+	}
+
+	movieIDs := make([]int64, len(titles))
+	byMovie := make(map[int64]*rightsholders.TitleAnalytics, len(titles))
+	report.Titles = make([]rightsholders.TitleAnalytics, len(titles))
+	for i, title := range titles {
+		movieIDs[i] = title.MovieID
+		report.Titles[i] = rightsholders.TitleAnalytics{
+			MovieID:     title.MovieID,
+			MovieTitle:  title.MovieTitle,
+			Territories: []rightsholders.TerritoryAnalytics{},
+		}
+		byMovie[title.MovieID] = &report.Titles[i]
+	}
+
+	views, err := u.repo.CountViews(ctx, movieIDs, from, to)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	for _, count := range views {
+		byMovie[count.MovieID].Views = count.Count
+	}
+
+	watched, err := u.repo.SumWatchSeconds(ctx, movieIDs, from, until)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	for _, count := range watched {
+		byMovie[count.MovieID].WatchSeconds = count.Count
+	}
+
+	sales, err := u.repo.FindSales(ctx, movieIDs, from, until)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	for _, s := range sales {
+		title := byMovie[s.MovieID]
+		title.Orders += s.Orders
+		title.Revenue += s.Revenue
+		title.Refunds += s.Refunds
+		title.Territories = append(title.Territories, rightsholders.TerritoryAnalytics{
+			Territory:  s.Territory,
+			Orders:     s.Orders,
+			Revenue:    roundAmount(s.Revenue),
+			Refunds:    roundAmount(s.Refunds),
+			NetRevenue: roundAmount(s.Revenue - s.Refunds),
+		})
+	}
+
+	for i := range report.Titles {
+		title := &report.Titles[i]
+		title.NetRevenue = roundAmount(title.Revenue - title.Refunds)
+		title.Revenue = roundAmount(title.Revenue)
+		title.Refunds = roundAmount(title.Refunds)
+		// Biggest markets first
+		sort.Slice(title.Territories, func(a, b int) bool {
+			if title.Territories[a].Revenue != title.Territories[b].Revenue {
+				return title.Territories[a].Revenue > title.Territories[b].Revenue
+			}
+			return title.Territories[a].Territory < title.Territories[b].Territory
+		})
+	}
+
+	return report, nil
+}
+
+// roundAmount rounds to cents, sums of decimals drift in float64
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
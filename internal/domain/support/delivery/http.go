@@ -0,0 +1,205 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/support"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type SupportUsecase interface {
+	CreateTicket(ctx context.Context, userExtID string, req support.CreateTicketRequest) (*support.TicketResponse, error)
+	GetMyTickets(ctx context.Context, userExtID string, page, limit int) (*support.TicketListWithPagination, error)
+	GetMyTicket(ctx context.Context, userExtID string, ticketID int64) (*support.TicketResponse, error)
+	GetAllTicketsAdmin(ctx context.Context, page, limit int, filter support.TicketFilter) (*support.TicketListWithPagination, error)
+	GetTicketAdmin(ctx context.Context, ticketID int64) (*support.TicketResponse, error)
+	AssignTicket(ctx context.Context, ticketID int64, req support.AssignTicketRequest) (*support.TicketResponse, error)
+	UpdateTicketStatus(ctx context.Context, ticketID int64, req support.UpdateTicketStatusRequest) (*support.TicketResponse, error)
+}
+
+type SupportHandler struct {
+	ctx     context.Context
+	usecase SupportUsecase
+}
+
+func NewSupportHandler(ctx context.Context, usecase SupportUsecase) *SupportHandler {
+	return &SupportHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// CreateTicket opens a help ticket, optionally about an order or a movie
+// POST /api/v1/support/tickets
+func (h *SupportHandler) CreateTicket(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+	var req support.CreateTicketRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.CreateTicket(ctx, userExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, "ticket_created", result)
+}
+
+// GetMyTickets lists the tickets of the current user
+// GET /api/v1/support/tickets?page=1&limit=20
+func (h *SupportHandler) GetMyTickets(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	result, err := h.usecase.GetMyTickets(ctx, userExtID, page, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Tickets,
+		"pagination": result.Pagination,
+	})
+}
+
+// GetMyTicket returns a ticket of the current user
+// GET /api/v1/support/tickets/:id
+func (h *SupportHandler) GetMyTicket(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_ticket_id", err.Error())
+	}
+	result, err := h.usecase.GetMyTicket(ctx, userExtID, ticketID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// GetAllTicketsAdmin lists tickets for the support team (Admin only)
+// GET /api/v1/admin/support/tickets?page=1&status=OPEN&category=BILLING&assigned_to=me
+func (h *SupportHandler) GetAllTicketsAdmin(c echo.Context) error {
+	ctx := h.ctx
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	filter := support.TicketFilter{
+		Status:     c.QueryParam("status"),
+		Category:   c.QueryParam("category"),
+		AssignedTo: c.QueryParam("assigned_to"),
+	}
+	if filter.AssignedTo == "me" {
+		filter.AssignedTo, _ = c.Get(string(constant.CtxKeyUserExtID)).(string)
+	}
+
+	result, err := h.usecase.GetAllTicketsAdmin(ctx, page, limit, filter)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Tickets,
+		"pagination": result.Pagination,
+	})
+}
+
+// GetTicketAdmin returns any ticket (Admin only)
+// GET /api/v1/admin/support/tickets/:id
+func (h *SupportHandler) GetTicketAdmin(c echo.Context) error {
+	ctx := h.ctx
+
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_ticket_id", err.Error())
+	}
+	result, err := h.usecase.GetTicketAdmin(ctx, ticketID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// AssignTicket assigns a ticket to an admin (Admin only)
+// PATCH /api/v1/admin/support/tickets/:id/assign
+func (h *SupportHandler) AssignTicket(c echo.Context) error {
+	ctx := h.ctx
+
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_ticket_id", err.Error())
+	}
+	var req support.AssignTicketRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.AssignTicket(ctx, ticketID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "ticket_assigned", result)
+}
+
+// UpdateTicketStatus changes the status of a ticket (Admin only)
+// PATCH /api/v1/admin/support/tickets/:id/status
+func (h *SupportHandler) UpdateTicketStatus(c echo.Context) error {
+	ctx := h.ctx
+
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_ticket_id", err.Error())
+	}
+	var req support.UpdateTicketStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+	result, err := h.usecase.UpdateTicketStatus(ctx, ticketID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return response.Success(c, http.StatusOK, "ticket_status_updated", result)
+}
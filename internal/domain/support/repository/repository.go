@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/martinmanurung/cinestream/internal/domain/support"
+	"gorm.io/gorm"
+)
+
+type SupportRepository struct {
+	db *gorm.DB
+}
+
+func NewSupportRepository(db *gorm.DB) *SupportRepository {
+	return &SupportRepository{db: db}
+}
+
+// orderOwner is the part of an order needed to link it to a ticket
+type orderOwner struct {
+	UserExtID string
+	MovieID   int64
+}
+
+// FindOrderOwner returns the owner and movie of an order, nil if the order does not exist
+func (r *SupportRepository) FindOrderOwner(ctx context.Context, orderID int64) (string, int64, bool, error) {
+	var owner orderOwner
+	err := r.db.WithContext(ctx).
+		Table("orders").
+		Select("user_ext_id, movie_id").
+		Where("id = ?", orderID).
+		Take(&owner).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, false, nil
+		}
+		return "", 0, false, err
+	}
+	return owner.UserExtID, owner.MovieID, true, nil
+}
+
+// MovieExists checks whether a ticket can reference the movie
+func (r *SupportRepository) MovieExists(ctx context.Context, movieID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("movies").Where("id = ?", movieID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindContact returns the name, email and role of a user, nil if not found
+func (r *SupportRepository) FindContact(ctx context.Context, userExtID string) (*support.Contact, error) {
+	var contact support.Contact
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Select("name, email, role").
+		Where("ext_id = ?", userExtID).
+		Take(&contact).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// WantsEmailNotifications reads the email channel preference of a user, users without saved preferences get emails
+func (r *SupportRepository) WantsEmailNotifications(ctx context.Context, userExtID string) (bool, error) {
+	var enabled []bool
+	err := r.db.WithContext(ctx).
+		Table("user_preferences").
+		Where("user_ext_id = ?", userExtID).
+		Pluck("email_notifications", &enabled).Error
+	if err != nil {
+		return false, err
+	}
+	return len(enabled) == 0 || enabled[0], nil
+}
+
+func (r *SupportRepository) CreateTicket(ctx context.Context, ticket *support.Ticket) error {
+	return r.db.WithContext(ctx).Create(ticket).Error
+}
+
+func (r *SupportRepository) FindTicketByID(ctx context.Context, ticketID int64) (*support.Ticket, error) {
+	var ticket support.Ticket
+	err := r.db.WithContext(ctx).Where("id = ?", ticketID).First(&ticket).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FindTicketDetail returns a ticket with user and movie details
+func (r *SupportRepository) FindTicketDetail(ctx context.Context, ticketID int64) (*support.TicketResponse, error) {
+	var result support.TicketResponse
+	err := r.ticketQuery(ctx).Where("support_tickets.id = ?", ticketID).Take(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindTickets returns tickets matching the filter, newest first
+func (r *SupportRepository) FindTickets(ctx context.Context, page, limit int, filter support.TicketFilter) ([]support.TicketResponse, int64, error) {
+	var results []support.TicketResponse
+	var totalCount int64
+
+	offset := (page - 1) * limit
+
+	query := r.ticketQuery(ctx)
+	if filter.Status != "" {
+		query = query.Where("support_tickets.status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where("support_tickets.category = ?", filter.Category)
+	}
+	if filter.AssignedTo != "" {
+		query = query.Where("support_tickets.assigned_to = ?", filter.AssignedTo)
+	}
+	if filter.UserExtID != "" {
+		query = query.Where("support_tickets.user_ext_id = ?", filter.UserExtID)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("support_tickets.created_at DESC").Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+func (r *SupportRepository) UpdateTicket(ctx context.Context, ticketID int64, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&support.Ticket{}).
+		Where("id = ?", ticketID).
+		Updates(updates).Error
+}
+
+func (r *SupportRepository) ticketQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("support_tickets").
+		Select("support_tickets.*, COALESCE(users.name, '') as user_name, COALESCE(users.email, '') as user_email, movies.title as movie_title").
+		Joins("LEFT JOIN users ON users.ext_id = support_tickets.user_ext_id").
+		Joins("LEFT JOIN movies ON movies.id = support_tickets.movie_id")
+}
@@ -0,0 +1,111 @@
+package support
+
+import "time"
+
+// Ticket categories
+const (
+	CategoryBilling  = "BILLING"
+	CategoryPlayback = "PLAYBACK"
+	CategoryAccount  = "ACCOUNT"
+	CategoryOther    = "OTHER"
+)
+
+// Ticket statuses
+const (
+	StatusOpen       = "OPEN"
+	StatusInProgress = "IN_PROGRESS"
+	StatusResolved   = "RESOLVED"
+	StatusClosed     = "CLOSED"
+)
+
+// Ticket is a help request from a user, optionally about an order or a movie
+type Ticket struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID   string     `json:"user_ext_id" gorm:"not null;column:user_ext_id"`
+	OrderID     *int64     `json:"order_id,omitempty"`
+	MovieID     *int64     `json:"movie_id,omitempty"`
+	Category    string     `json:"category" gorm:"type:enum('BILLING','PLAYBACK','ACCOUNT','OTHER');not null"`
+	Subject     string     `json:"subject" gorm:"type:varchar(255);not null"`
+	Description string     `json:"description" gorm:"type:text;not null"`
+	Status      string     `json:"status" gorm:"type:enum('OPEN','IN_PROGRESS','RESOLVED','CLOSED');default:'OPEN'"`
+	AssignedTo  *string    `json:"assigned_to,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for Ticket
+func (Ticket) TableName() string {
+	return "support_tickets"
+}
+
+// Contact is the name and email of a user, used for notifications
+type Contact struct {
+	Name  string
+	Email string
+	Role  string
+}
+
+// Request DTOs
+
+// CreateTicketRequest represents a new help ticket
+type CreateTicketRequest struct {
+	Category    string `json:"category" validate:"required,oneof=BILLING PLAYBACK ACCOUNT OTHER"`
+	Subject     string `json:"subject" validate:"required,min=3,max=255"`
+	Description string `json:"description" validate:"required,min=10,max=5000"`
+	OrderID     *int64 `json:"order_id" validate:"omitempty,gt=0"`
+	MovieID     *int64 `json:"movie_id" validate:"omitempty,gt=0"`
+}
+
+// AssignTicketRequest assigns a ticket to an admin
+type AssignTicketRequest struct {
+	AssigneeExtID string `json:"assignee_ext_id" validate:"required"`
+}
+
+// UpdateTicketStatusRequest moves a ticket through the workflow
+type UpdateTicketStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=OPEN IN_PROGRESS RESOLVED CLOSED"`
+}
+
+// Response DTOs
+
+// TicketResponse represents a ticket with its related order and movie
+type TicketResponse struct {
+	ID          int64      `json:"id"`
+	UserExtID   string     `json:"user_ext_id"`
+	UserName    string     `json:"user_name,omitempty"`
+	UserEmail   string     `json:"user_email,omitempty"`
+	OrderID     *int64     `json:"order_id,omitempty"`
+	MovieID     *int64     `json:"movie_id,omitempty"`
+	MovieTitle  *string    `json:"movie_title,omitempty"`
+	Category    string     `json:"category"`
+	Subject     string     `json:"subject"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	AssignedTo  *string    `json:"assigned_to,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+// TicketListWithPagination represents a paginated ticket list
+type TicketListWithPagination struct {
+	Tickets    []TicketResponse `json:"tickets"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
+
+// TicketFilter narrows the admin ticket list
+type TicketFilter struct {
+	Status     string
+	Category   string
+	AssignedTo string
+	UserExtID  string
+}
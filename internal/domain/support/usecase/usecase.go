@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/martinmanurung/cinestream/internal/domain/support"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+)
+
+type SupportRepository interface {
+	FindOrderOwner(ctx context.Context, orderID int64) (string, int64, bool, error)
+	MovieExists(ctx context.Context, movieID int64) (bool, error)
+	FindContact(ctx context.Context, userExtID string) (*support.Contact, error)
+	CreateTicket(ctx context.Context, ticket *support.Ticket) error
+	FindTicketByID(ctx context.Context, ticketID int64) (*support.Ticket, error)
+	FindTicketDetail(ctx context.Context, ticketID int64) (*support.TicketResponse, error)
+	FindTickets(ctx context.Context, page, limit int, filter support.TicketFilter) ([]support.TicketResponse, int64, error)
+	UpdateTicket(ctx context.Context, ticketID int64, updates map[string]interface{}) error
+	WantsEmailNotifications(ctx context.Context, userExtID string) (bool, error)
+}
+
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
+
+type SupportUsecase struct {
+	repo         SupportRepository
+	mailer       Mailer
+	supportInbox string
+}
+
+func NewSupportUsecase(repo SupportRepository, mailer Mailer, supportInbox string) *SupportUsecase {
+	return &SupportUsecase{
+		repo:         repo,
+		mailer:       mailer,
+		supportInbox: supportInbox,
+	}
+}
+
+// CreateTicket opens a help ticket, a linked order must belong to the user
+func (u *SupportUsecase) CreateTicket(ctx context.Context, userExtID string, req support.CreateTicketRequest) (*support.TicketResponse, error) {
+	// The subject goes into email headers, a line break there would add headers of the user's choosing
+	if strings.ContainsFunc(req.Subject, unicode.IsControl) {
+		return nil, apperr.Validation("invalid_subject", "subject must not contain control characters")
+	}
+
+	ticket := &support.Ticket{
+		UserExtID:   userExtID,
+		Category:    req.Category,
+		Subject:     strings.TrimSpace(req.Subject),
+		Description: strings.TrimSpace(req.Description),
+		Status:      support.StatusOpen,
+	}
+
+	if req.OrderID != nil {
+		owner, movieID, found, err := u.repo.FindOrderOwner(ctx, *req.OrderID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if !found || owner != userExtID {
+			return nil, apperr.NotFound("order_not_found", nil)
+		}
+		ticket.OrderID = req.OrderID
+		// The order already tells which movie the complaint is about
+		ticket.MovieID = &movieID
+	}
+
+	if req.MovieID != nil {
+		if ticket.MovieID != nil && *ticket.MovieID != *req.MovieID {
+			return nil, apperr.Validation("movie_does_not_match_order", nil)
+		}
+		exists, err := u.repo.MovieExists(ctx, *req.MovieID)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if !exists {
+			return nil, apperr.NotFound("movie_not_found", nil)
+		}
+		ticket.MovieID = req.MovieID
+	}
+
+	if err := u.repo.CreateTicket(ctx, ticket); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	result, err := u.getTicket(ctx, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyUser(ctx, result.UserExtID, result.UserEmail,
+		fmt.Sprintf("[CineStream] Ticket #%d received: %s", result.ID, result.Subject),
+		fmt.Sprintf("Hi %s,\n\nWe received your %s ticket and will get back to you soon.\n\nSubject: %s\n\n%s\n",
+			result.UserName, strings.ToLower(result.Category), result.Subject, result.Description))
+	u.notify([]string{u.supportInbox},
+		fmt.Sprintf("[CineStream] New %s ticket #%d: %s", result.Category, result.ID, result.Subject),
+		fmt.Sprintf("From: %s <%s>\nOrder: %s\nMovie: %s\n\n%s\n",
+			result.UserName, result.UserEmail, optionalID(result.OrderID), optionalID(result.MovieID), result.Description))
+
+	return result, nil
+}
+
+// GetMyTickets lists the tickets of the current user
+func (u *SupportUsecase) GetMyTickets(ctx context.Context, userExtID string, page, limit int) (*support.TicketListWithPagination, error) {
+	return u.listTickets(ctx, page, limit, support.TicketFilter{UserExtID: userExtID})
+}
+
+// GetMyTicket returns a ticket of the current user
+func (u *SupportUsecase) GetMyTicket(ctx context.Context, userExtID string, ticketID int64) (*support.TicketResponse, error) {
+	result, err := u.getTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if result.UserExtID != userExtID {
+		return nil, apperr.NotFound("ticket_not_found", nil)
+	}
+	return result, nil
+}
+
+// GetAllTicketsAdmin lists tickets for the support team (Admin only)
+func (u *SupportUsecase) GetAllTicketsAdmin(ctx context.Context, page, limit int, filter support.TicketFilter) (*support.TicketListWithPagination, error) {
+	return u.listTickets(ctx, page, limit, filter)
+}
+
+// GetTicketAdmin returns any ticket (Admin only)
+func (u *SupportUsecase) GetTicketAdmin(ctx context.Context, ticketID int64) (*support.TicketResponse, error) {
+	return u.getTicket(ctx, ticketID)
+}
+
+// AssignTicket assigns a ticket to an admin and starts working on it (Admin only)
+func (u *SupportUsecase) AssignTicket(ctx context.Context, ticketID int64, req support.AssignTicketRequest) (*support.TicketResponse, error) {
+	ticket, err := u.findTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignee, err := u.repo.FindContact(ctx, req.AssigneeExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if assignee == nil || !constant.HasPermission(assignee.Role, constant.PermManageSupport) {
+		return nil, apperr.Validation("assignee_must_be_admin", nil)
+	}
+
+	updates := map[string]interface{}{"assigned_to": req.AssigneeExtID}
+	if ticket.Status == support.StatusOpen {
+		updates["status"] = support.StatusInProgress
+	}
+	if err := u.repo.UpdateTicket(ctx, ticketID, updates); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	result, err := u.getTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notify([]string{assignee.Email},
+		fmt.Sprintf("[CineStream] Ticket #%d assigned to you: %s", result.ID, result.Subject),
+		fmt.Sprintf("Category: %s\nFrom: %s <%s>\n\n%s\n", result.Category, result.UserName, result.UserEmail, result.Description))
+
+	return result, nil
+}
+
+// UpdateTicketStatus moves a ticket through the workflow and emails the user (Admin only)
+func (u *SupportUsecase) UpdateTicketStatus(ctx context.Context, ticketID int64, req support.UpdateTicketStatusRequest) (*support.TicketResponse, error) {
+	ticket, err := u.findTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == req.Status {
+		return u.getTicket(ctx, ticketID)
+	}
+
+	updates := map[string]interface{}{"status": req.Status}
+	switch req.Status {
+	case support.StatusResolved, support.StatusClosed:
+		if ticket.ResolvedAt == nil {
+			updates["resolved_at"] = time.Now()
+		}
+	default:
+		updates["resolved_at"] = nil // reopened
+	}
+
+	if err := u.repo.UpdateTicket(ctx, ticketID, updates); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	result, err := u.getTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyUser(ctx, result.UserExtID, result.UserEmail,
+		fmt.Sprintf("[CineStream] Ticket #%d is now %s", result.ID, strings.ReplaceAll(strings.ToLower(result.Status), "_", " ")),
+		fmt.Sprintf("Hi %s,\n\nThe status of your ticket \"%s\" changed from %s to %s.\n",
+			result.UserName, result.Subject, ticket.Status, result.Status))
+
+	return result, nil
+}
+
+func (u *SupportUsecase) listTickets(ctx context.Context, page, limit int, filter support.TicketFilter) (*support.TicketListWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	list, totalCount, err := u.repo.FindTickets(ctx, page, limit, filter)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if list == nil {
+		list = []support.TicketResponse{}
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &support.TicketListWithPagination{
+		Tickets: list,
+		Pagination: support.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+func (u *SupportUsecase) getTicket(ctx context.Context, ticketID int64) (*support.TicketResponse, error) {
+	result, err := u.repo.FindTicketDetail(ctx, ticketID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if result == nil {
+		return nil, apperr.NotFound("ticket_not_found", nil)
+	}
+	return result, nil
+}
+
+func (u *SupportUsecase) findTicket(ctx context.Context, ticketID int64) (*support.Ticket, error) {
+	ticket, err := u.repo.FindTicketByID(ctx, ticketID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if ticket == nil {
+		return nil, apperr.NotFound("ticket_not_found", nil)
+	}
+	return ticket, nil
+}
+
+// notify sends an email in the background, a mail failure never fails the request
+func (u *SupportUsecase) notify(to []string, subject, body string) {
+	if len(to) == 0 || to[0] == "" {
+		return
+	}
+	go func() {
+		if err := u.mailer.Send(to, subject, body); err != nil {
+			log.Printf("[SUPPORT] %v", err)
+		}
+	}()
+}
+
+// notifyUser emails a ticket update unless the user turned email notifications off
+func (u *SupportUsecase) notifyUser(ctx context.Context, userExtID, email, subject, body string) {
+	wants, err := u.repo.WantsEmailNotifications(ctx, userExtID)
+	if err != nil {
+		// Better one unwanted email than a lost ticket update
+		log.Printf("[SUPPORT] failed to load preferences of %s: %v", userExtID, err)
+	} else if !wants {
+		return
+	}
+	u.notify([]string{email}, subject, body)
+}
+
+func optionalID(id *int64) string {
+	if id == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *id)
+}
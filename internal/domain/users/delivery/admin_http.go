@@ -0,0 +1,212 @@
+package delivery
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// GetUsersAdmin lists users with role and account status (Admin only)
+// GET /api/v1/admin/users?page=1&role=CONTENT_MANAGER&status=disabled&q=john
+func (h *Handler) GetUsersAdmin(c echo.Context) error {
+	ctx := h.ctx
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	filter := users.UserFilter{
+		Role:   c.QueryParam("role"),
+		Status: c.QueryParam("status"),
+		Query:  c.QueryParam("q"),
+	}
+
+	result, err := h.usecase.GetUsersAdmin(ctx, page, limit, filter)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Users,
+		"pagination": result.Pagination,
+	})
+}
+
+// UpdateUserRole changes a user's role (Admin only)
+// PATCH /api/v1/admin/users/:extID/role
+func (h *Handler) UpdateUserRole(c echo.Context) error {
+	ctx := h.ctx
+
+	actorExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || actorExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req users.UpdateUserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdateUserRole(ctx, actorExtID, c.Param("extID"), req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "user_role_updated", result)
+}
+
+// DisableUser disables an account and revokes its refresh tokens (Admin only)
+// POST /api/v1/admin/users/:extID/disable
+func (h *Handler) DisableUser(c echo.Context) error {
+	ctx := h.ctx
+
+	actorExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || actorExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	result, err := h.usecase.DisableUser(ctx, actorExtID, c.Param("extID"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "user_disabled", result)
+}
+
+// EnableUser re-enables a disabled account (Admin only)
+// POST /api/v1/admin/users/:extID/enable
+func (h *Handler) EnableUser(c echo.Context) error {
+	ctx := h.ctx
+
+	result, err := h.usecase.EnableUser(ctx, c.Param("extID"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "user_enabled", result)
+}
+
+// UnlockUser lifts a login lockout caused by repeated failed logins (Admin only)
+// POST /api/v1/admin/users/:extID/unlock
+func (h *Handler) UnlockUser(c echo.Context) error {
+	ctx := h.ctx
+
+	actorExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || actorExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	result, err := h.usecase.UnlockUser(ctx, actorExtID, c.Param("extID"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "user_unlocked", result)
+}
+
+// ForcePasswordReset signs a user out and requires a new password on the next login (Admin only)
+// POST /api/v1/admin/users/:extID/force-password-reset
+func (h *Handler) ForcePasswordReset(c echo.Context) error {
+	ctx := h.ctx
+
+	actorExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || actorExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	result, err := h.usecase.ForcePasswordReset(ctx, actorExtID, c.Param("extID"))
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "password_reset_forced", result)
+}
+
+// GetUserAuditLogs returns the latest lockout and unlock events of a user (Admin only)
+// GET /api/v1/admin/users/:extID/audit-logs?limit=50
+func (h *Handler) GetUserAuditLogs(c echo.Context) error {
+	ctx := h.ctx
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.usecase.GetUserAuditLogs(ctx, c.Param("extID"), limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// ExportMarketingAudience exports users who opted in to marketing, as JSON or CSV for email marketing tools (Admin only)
+// GET /api/v1/admin/marketing/audience?genre_id=3&format=csv
+func (h *Handler) ExportMarketingAudience(c echo.Context) error {
+	ctx := h.ctx
+
+	var filter users.AudienceFilter
+	if genreID := c.QueryParam("genre_id"); genreID != "" {
+		id, err := strconv.Atoi(genreID)
+		if err != nil || id < 1 {
+			return response.Error(c, http.StatusBadRequest, "invalid_genre_id", nil)
+		}
+		filter.GenreID = id
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return response.Error(c, http.StatusBadRequest, "invalid_format", "format must be json or csv")
+	}
+
+	audience, err := h.usecase.ExportMarketingAudience(ctx, filter)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	// Personal data, proxies and browsers must not keep a copy
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	if format != "csv" {
+		return response.Success(c, http.StatusOK, "success", audience)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="marketing-audience.csv"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	writer.Write([]string{"email", "name", "language", "interests", "consented_at"})
+	for _, member := range audience {
+		writer.Write([]string{
+			csvCell(member.Email),
+			csvCell(member.Name),
+			csvCell(member.Language),
+			csvCell(strings.Join(member.Interests, "|")),
+			member.ConsentedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell keeps spreadsheets from running user input as a formula, such values get a leading quote
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
 	"github.com/martinmanurung/cinestream/pkg/constant"
 	"github.com/martinmanurung/cinestream/pkg/middleware"
 	"github.com/martinmanurung/cinestream/pkg/response"
@@ -13,10 +14,24 @@ import (
 
 type UserUsecase interface {
 	RegisterUser(ctx context.Context, payload users.UserRegisterRequest) (*users.UserRegisterResponse, error)
-	LoginUser(ctx context.Context, payload users.UserLoginRequest) (*users.UserLoginResponse, error)
+	LoginUser(ctx context.Context, payload users.UserLoginRequest, clientIP string) (*users.UserLoginResponse, error)
 	GetUserProfile(ctx context.Context, userExtID string) (*users.UserProfile, error)
 	Logout(ctx context.Context, refreshToken string) error
 	RefreshToken(ctx context.Context, refreshToken string) (*users.RefreshTokenResponse, error)
+	ForgotPassword(ctx context.Context, payload users.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, payload users.ResetPasswordRequest) error
+	ChangeEmail(ctx context.Context, userExtID string, payload users.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, payload users.ConfirmEmailChangeRequest) error
+	GetUsersAdmin(ctx context.Context, page, limit int, filter users.UserFilter) (*users.UserListWithPagination, error)
+	UpdateUserRole(ctx context.Context, actorExtID, userExtID string, req users.UpdateUserRoleRequest) (*users.AdminUserResponse, error)
+	DisableUser(ctx context.Context, actorExtID, userExtID string) (*users.AdminUserResponse, error)
+	EnableUser(ctx context.Context, userExtID string) (*users.AdminUserResponse, error)
+	UnlockUser(ctx context.Context, actorExtID, userExtID string) (*users.AdminUserResponse, error)
+	ForcePasswordReset(ctx context.Context, actorExtID, userExtID string) (*users.AdminUserResponse, error)
+	GetUserAuditLogs(ctx context.Context, userExtID string, limit int) ([]users.UserAuditLog, error)
+	GetPreferences(ctx context.Context, userExtID string) (*users.UserPreferences, error)
+	UpdatePreferences(ctx context.Context, userExtID string, req users.UpdatePreferencesRequest) (*users.UserPreferences, error)
+	ExportMarketingAudience(ctx context.Context, filter users.AudienceFilter) ([]users.AudienceMember, error)
 }
 
 type Handler struct {
@@ -51,16 +66,14 @@ func (h *Handler) RegisterUser(c echo.Context) error {
 
 	result, err := h.usecase.RegisterUser(ctx, req)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
+		if apperr.KindOf(err) == apperr.KindInternal {
+			logger.Error().Err(err).Msg("Internal server error during registration")
+		} else {
 			logger.Error().
 				Err(err).
 				Msg("Failed to register user")
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
 		}
-		logger.Error().Err(err).Msg("Internal server error during registration")
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	logger.Info().
@@ -87,17 +100,15 @@ func (h *Handler) LoginUser(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
 	}
 
-	result, err := h.usecase.LoginUser(ctx, req)
+	result, err := h.usecase.LoginUser(ctx, req, c.RealIP())
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
+		if apperr.KindOf(err) == apperr.KindInternal {
+			logger.Error().Err(err).Msg("Internal server error during login")
+		} else {
 			logger.Warn().
 				Msg("Login failed")
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
 		}
-		logger.Error().Err(err).Msg("Internal server error during login")
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	logger.Info().
@@ -121,12 +132,7 @@ func (h *Handler) GetMe(c echo.Context) error {
 
 	result, err := h.usecase.GetUserProfile(ctx, extID)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "success", result)
@@ -146,12 +152,7 @@ func (h *Handler) Logout(c echo.Context) error {
 
 	err := h.usecase.Logout(ctx, req.RefreshToken)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -171,13 +172,144 @@ func (h *Handler) RefreshToken(c echo.Context) error {
 
 	result, err := h.usecase.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		var apiErr *response.APIError
-		if errors, ok := err.(*response.APIError); ok {
-			apiErr = errors
-			return response.Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
-		}
-		return response.Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
+		return response.HandleError(c, err)
 	}
 
 	return response.Success(c, http.StatusOK, "token_refreshed_successfully", result)
 }
+
+// ForgotPassword emails a password reset link, the response does not tell whether the email is registered
+// POST /api/v1/users/forgot-password
+func (h *Handler) ForgotPassword(c echo.Context) error {
+	ctx := h.ctx
+	var req users.ForgotPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	err := h.usecase.ForgotPassword(ctx, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "password_reset_email_sent_if_account_exists", nil)
+}
+
+// ResetPassword sets a new password with a token from the reset email
+// POST /api/v1/users/reset-password
+func (h *Handler) ResetPassword(c echo.Context) error {
+	ctx := h.ctx
+	var req users.ResetPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	err := h.usecase.ResetPassword(ctx, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "password_reset_successful", nil)
+}
+
+// ChangeEmail sends a confirmation link to the new address and a security notice to the current one
+// POST /api/v1/users/me/email
+func (h *Handler) ChangeEmail(c echo.Context) error {
+	ctx := h.ctx
+
+	extID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || extID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", "invalid token")
+	}
+
+	var req users.ChangeEmailRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	if err := h.usecase.ChangeEmail(ctx, extID, req); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusAccepted, "email_change_confirmation_sent", nil)
+}
+
+// ConfirmEmailChange swaps the email with the token from the confirmation link, all sessions are signed out
+// POST /api/v1/users/confirm-email
+func (h *Handler) ConfirmEmailChange(c echo.Context) error {
+	ctx := h.ctx
+	var req users.ConfirmEmailChangeRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	if err := h.usecase.ConfirmEmailChange(ctx, req); err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "email_changed", nil)
+}
+
+// GetPreferences returns the notification and content preferences of the current user
+// GET /api/v1/users/me/preferences
+func (h *Handler) GetPreferences(c echo.Context) error {
+	ctx := h.ctx
+
+	extID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || extID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", "invalid token")
+	}
+
+	result, err := h.usecase.GetPreferences(ctx, extID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// UpdatePreferences changes the notification and content preferences of the current user
+// PUT /api/v1/users/me/preferences
+func (h *Handler) UpdatePreferences(c echo.Context) error {
+	ctx := h.ctx
+
+	extID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || extID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", "invalid token")
+	}
+
+	var req users.UpdatePreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.UpdatePreferences(ctx, extID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "preferences_updated", result)
+}
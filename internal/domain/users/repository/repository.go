@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/martinmanurung/cinestream/internal/domain/users"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type User struct {
@@ -64,10 +66,11 @@ func (u User) CreateRefreshToken(ctx context.Context, token users.UserRefreshTok
 	return u.db.WithContext(ctx).Create(&token).Error
 }
 
-func (u User) FindRefreshToken(ctx context.Context, tokenHash string) (*users.UserRefreshToken, error) {
+// FindRefreshToken returns a token that did not reach its absolute expiry and was last used after usedAfter
+func (u User) FindRefreshToken(ctx context.Context, tokenHash string, usedAfter time.Time) (*users.UserRefreshToken, error) {
 	var token users.UserRefreshToken
 	err := u.db.WithContext(ctx).
-		Where("token_hash = ? AND expires_at > NOW()", tokenHash).
+		Where("token_hash = ? AND expires_at > NOW() AND last_used_at > ?", tokenHash, usedAfter).
 		First(&token).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -83,3 +86,269 @@ func (u User) DeleteRefreshToken(ctx context.Context, tokenHash string) error {
 		Where("token_hash = ?", tokenHash).
 		Delete(&users.UserRefreshToken{}).Error
 }
+
+// TouchRefreshToken moves the last use of a token forward, restarting its idle expiry
+func (u User) TouchRefreshToken(ctx context.Context, tokenHash string, usedAt time.Time) error {
+	return u.db.WithContext(ctx).
+		Model(&users.UserRefreshToken{}).
+		Where("token_hash = ?", tokenHash).
+		Update("last_used_at", usedAt).Error
+}
+
+// DeleteExpiredRefreshTokens removes tokens past their absolute expiry or last used before idleBefore
+func (u User) DeleteExpiredRefreshTokens(ctx context.Context, now, idleBefore time.Time) (int64, error) {
+	result := u.db.WithContext(ctx).
+		Where("expires_at <= ? OR last_used_at <= ?", now, idleBefore).
+		Delete(&users.UserRefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+func (u User) CreatePasswordResetToken(ctx context.Context, token users.PasswordResetToken) error {
+	return u.db.WithContext(ctx).Create(&token).Error
+}
+
+// FindPasswordResetToken returns the token only while it is unused and not expired
+func (u User) FindPasswordResetToken(ctx context.Context, tokenHash string) (*users.PasswordResetToken, error) {
+	var token users.PasswordResetToken
+	err := u.db.WithContext(ctx).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > NOW()", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ResetPassword marks the token used, sets the new password hash and signs the user out everywhere.
+// Returns false when the token was already used by a concurrent request.
+func (u User) ResetPassword(ctx context.Context, token users.PasswordResetToken, passwordHash string) (bool, error) {
+	consumed := false
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&users.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		consumed = true
+
+		// Other outstanding reset links of the user stop working too
+		if err := tx.Model(&users.PasswordResetToken{}).
+			Where("user_ext_id = ? AND used_at IS NULL", token.UserExtID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&users.User{}).
+			Where("ext_id = ?", token.UserExtID).
+			Updates(map[string]interface{}{"password": passwordHash, "password_reset_required": false, "updated_at": now}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_ext_id = ?", token.UserExtID).
+			Delete(&users.UserRefreshToken{}).Error
+	})
+	return consumed, err
+}
+
+func (u User) CreateEmailChangeToken(ctx context.Context, token users.EmailChangeToken) error {
+	return u.db.WithContext(ctx).Create(&token).Error
+}
+
+// FindEmailChangeToken returns the token only while it is unused and not expired
+func (u User) FindEmailChangeToken(ctx context.Context, tokenHash string) (*users.EmailChangeToken, error) {
+	var token users.EmailChangeToken
+	err := u.db.WithContext(ctx).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > NOW()", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ChangeEmail marks the token used, sets the new email and signs the user out everywhere.
+// Returns false when the token was already used by a concurrent request.
+func (u User) ChangeEmail(ctx context.Context, token users.EmailChangeToken) (bool, error) {
+	consumed := false
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&users.EmailChangeToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		consumed = true
+
+		// Other pending changes of the user stop working too
+		if err := tx.Model(&users.EmailChangeToken{}).
+			Where("user_ext_id = ? AND used_at IS NULL", token.UserExtID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&users.User{}).
+			Where("ext_id = ?", token.UserExtID).
+			Updates(map[string]interface{}{"email": token.NewEmail, "updated_at": now}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_ext_id = ?", token.UserExtID).
+			Delete(&users.UserRefreshToken{}).Error
+	})
+	return consumed, err
+}
+
+// FindUsers returns a page of users for the admin user list
+func (u User) FindUsers(ctx context.Context, page, limit int, filter users.UserFilter) ([]users.User, int64, error) {
+	var results []users.User
+	var totalCount int64
+
+	offset := (page - 1) * limit
+
+	query := u.db.WithContext(ctx).Model(&users.User{})
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case users.StatusActive:
+		query = query.Where("disabled_at IS NULL")
+	case users.StatusDisabled:
+		query = query.Where("disabled_at IS NOT NULL")
+	}
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("name LIKE ? OR email LIKE ?", like, like)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
+
+func (u User) UpdateUser(ctx context.Context, extID string, updates map[string]interface{}) error {
+	return u.db.WithContext(ctx).Model(&users.User{}).
+		Where("ext_id = ?", extID).
+		Updates(updates).Error
+}
+
+// DeleteUserRefreshTokens signs the user out of every session
+func (u User) DeleteUserRefreshTokens(ctx context.Context, userExtID string) error {
+	return u.db.WithContext(ctx).
+		Where("user_ext_id = ?", userExtID).
+		Delete(&users.UserRefreshToken{}).Error
+}
+
+func (u User) CreateAuditLog(ctx context.Context, entry users.UserAuditLog) error {
+	return u.db.WithContext(ctx).Create(&entry).Error
+}
+
+// FindAuditLogs returns the latest audit log entries of a user
+func (u User) FindAuditLogs(ctx context.Context, userExtID string, limit int) ([]users.UserAuditLog, error) {
+	var results []users.UserAuditLog
+	err := u.db.WithContext(ctx).
+		Where("user_ext_id = ?", userExtID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// FindPreferences returns the saved preferences of a user, nil if none were saved
+func (u User) FindPreferences(ctx context.Context, userExtID string) (*users.UserPreferences, error) {
+	var prefs users.UserPreferences
+	err := u.db.WithContext(ctx).Where("user_ext_id = ?", userExtID).First(&prefs).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SavePreferences creates or replaces the preferences of a user
+func (u User) SavePreferences(ctx context.Context, prefs *users.UserPreferences) error {
+	return u.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_ext_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email_notifications", "in_app_notifications", "marketing_opt_in", "marketing_consented_at", "language", "max_maturity_rating", "updated_at"}),
+	}).Create(prefs).Error
+}
+
+// FindMarketingAudience returns active users who opted in to marketing, ordered by id after afterID.
+// Users without saved preferences never opted in.
+func (u User) FindMarketingAudience(ctx context.Context, filter users.AudienceFilter, afterID, limit int) ([]users.AudienceMember, int, error) {
+	var rows []struct {
+		users.AudienceMember
+		ID int
+	}
+
+	query := u.db.WithContext(ctx).
+		Table("users").
+		Select("users.id, users.ext_id, users.name, users.email, user_preferences.language, user_preferences.marketing_consented_at AS consented_at").
+		Joins("JOIN user_preferences ON user_preferences.user_ext_id = users.ext_id").
+		Where("user_preferences.marketing_opt_in = ? AND users.disabled_at IS NULL AND users.id > ?", true, afterID)
+	if filter.GenreID > 0 {
+		query = query.Where(`EXISTS (SELECT 1 FROM orders
+			JOIN movie_genres ON movie_genres.movie_id = orders.movie_id
+			WHERE orders.user_ext_id = users.ext_id AND orders.payment_status = 'PAID' AND movie_genres.genre_id = ?)`, filter.GenreID)
+	}
+
+	if err := query.Order("users.id ASC").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	members := make([]users.AudienceMember, 0, len(rows))
+	lastID := afterID
+	for _, row := range rows {
+		members = append(members, row.AudienceMember)
+		lastID = row.ID
+	}
+	return members, lastID, nil
+}
+
+// FindGenreInterests returns the genres each user rented, most rented first
+func (u User) FindGenreInterests(ctx context.Context, userExtIDs []string) (map[string][]string, error) {
+	var rows []struct {
+		UserExtID string
+		Name      string
+	}
+	err := u.db.WithContext(ctx).
+		Table("orders").
+		Select("orders.user_ext_id, genres.name, COUNT(*) AS rentals").
+		Joins("JOIN movie_genres ON movie_genres.movie_id = orders.movie_id").
+		Joins("JOIN genres ON genres.id = movie_genres.genre_id").
+		Where("orders.payment_status = 'PAID' AND orders.user_ext_id IN ?", userExtIDs).
+		Group("orders.user_ext_id, genres.id, genres.name").
+		Order("rentals DESC, genres.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	interests := make(map[string][]string)
+	for _, row := range rows {
+		interests[row.UserExtID] = append(interests[row.UserExtID], row.Name)
+	}
+	return interests, nil
+}
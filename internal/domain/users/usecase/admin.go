@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+)
+
+// GetUsersAdmin lists users with their role and account status (Admin only)
+func (u Usecase) GetUsersAdmin(ctx context.Context, page, limit int, filter users.UserFilter) (*users.UserListWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	if filter.Role != "" && !constant.IsValidRole(filter.Role) {
+		return nil, apperr.Validation("invalid_role", nil)
+	}
+	if filter.Status != "" && filter.Status != users.StatusActive && filter.Status != users.StatusDisabled {
+		return nil, apperr.Validation("invalid_status", nil)
+	}
+
+	list, totalCount, err := u.repo.FindUsers(ctx, page, limit, filter)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	result := make([]users.AdminUserResponse, 0, len(list))
+	for _, user := range list {
+		result = append(result, toAdminUserResponse(user))
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &users.UserListWithPagination{
+		Users: result,
+		Pagination: users.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
+
+// UpdateUserRole changes the role of a user, it applies from the user's next token refresh (Admin only)
+func (u Usecase) UpdateUserRole(ctx context.Context, actorExtID, userExtID string, req users.UpdateUserRoleRequest) (*users.AdminUserResponse, error) {
+	// Admins cannot demote themselves, so there is always someone left to manage roles
+	if actorExtID == userExtID && req.Role != constant.RoleAdmin {
+		return nil, apperr.Validation("cannot_change_own_role", nil)
+	}
+
+	user, err := u.findUserForAdmin(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.UpdateUser(ctx, userExtID, map[string]interface{}{
+		"role":       req.Role,
+		"updated_at": time.Now(),
+	}); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	user.Role = req.Role
+	result := toAdminUserResponse(*user)
+	return &result, nil
+}
+
+// DisableUser blocks login and token refresh and signs the user out of every session (Admin only)
+func (u Usecase) DisableUser(ctx context.Context, actorExtID, userExtID string) (*users.AdminUserResponse, error) {
+	if actorExtID == userExtID {
+		return nil, apperr.Validation("cannot_disable_own_account", nil)
+	}
+
+	user, err := u.findUserForAdmin(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt == nil {
+		now := time.Now()
+		if err := u.repo.UpdateUser(ctx, userExtID, map[string]interface{}{
+			"disabled_at": now,
+			"updated_at":  now,
+		}); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		user.DisabledAt = &now
+	}
+
+	if err := u.repo.DeleteUserRefreshTokens(ctx, userExtID); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	result := toAdminUserResponse(*user)
+	return &result, nil
+}
+
+// EnableUser lets a disabled user sign in again (Admin only)
+func (u Usecase) EnableUser(ctx context.Context, userExtID string) (*users.AdminUserResponse, error) {
+	user, err := u.findUserForAdmin(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt != nil {
+		if err := u.repo.UpdateUser(ctx, userExtID, map[string]interface{}{
+			"disabled_at": nil,
+			"updated_at":  time.Now(),
+		}); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		user.DisabledAt = nil
+	}
+
+	result := toAdminUserResponse(*user)
+	return &result, nil
+}
+
+// ForcePasswordReset signs the user out everywhere and refuses login until the password is reset (Admin only).
+// The reset link is emailed on the next login attempt, so it has not expired when the user needs it.
+func (u Usecase) ForcePasswordReset(ctx context.Context, actorExtID, userExtID string) (*users.AdminUserResponse, error) {
+	user, err := u.findUserForAdmin(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.PasswordResetRequired {
+		if err := u.repo.UpdateUser(ctx, userExtID, map[string]interface{}{
+			"password_reset_required": true,
+			"updated_at":              time.Now(),
+		}); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		user.PasswordResetRequired = true
+	}
+
+	if err := u.repo.DeleteUserRefreshTokens(ctx, userExtID); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	u.audit(ctx, users.UserAuditLog{
+		UserExtID:  user.ExtID,
+		Event:      users.AuditPasswordResetForced,
+		ActorExtID: actorExtID,
+	})
+
+	result := toAdminUserResponse(*user)
+	return &result, nil
+}
+
+func (u Usecase) findUserForAdmin(ctx context.Context, userExtID string) (*users.User, error) {
+	user, err := u.repo.FindUserByExtID(ctx, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if user == nil {
+		return nil, apperr.NotFound("user_not_found", nil)
+	}
+	return user, nil
+}
+
+func toAdminUserResponse(user users.User) users.AdminUserResponse {
+	return users.AdminUserResponse{
+		ExtID:      user.ExtID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Role:       user.Role,
+		DisabledAt: user.DisabledAt,
+		CreatedAt:  user.CreatedAt,
+
+		PasswordResetRequired: user.PasswordResetRequired,
+		InvitedAt:             user.InvitedAt,
+	}
+}
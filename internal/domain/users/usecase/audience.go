@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+const (
+	audienceBatchSize = 500
+	maxInterests      = 3
+)
+
+// ExportMarketingAudience returns every user who opted in to marketing with their top rented genres (Admin only)
+func (u Usecase) ExportMarketingAudience(ctx context.Context, filter users.AudienceFilter) ([]users.AudienceMember, error) {
+	audience := []users.AudienceMember{}
+
+	afterID := 0
+	for {
+		batch, lastID, err := u.repo.FindMarketingAudience(ctx, filter, afterID, audienceBatchSize)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		extIDs := make([]string, 0, len(batch))
+		for _, member := range batch {
+			extIDs = append(extIDs, member.ExtID)
+		}
+		interests, err := u.repo.FindGenreInterests(ctx, extIDs)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		for i := range batch {
+			genres := interests[batch[i].ExtID]
+			if len(genres) > maxInterests {
+				genres = genres[:maxInterests]
+			}
+			batch[i].Interests = genres
+		}
+
+		audience = append(audience, batch...)
+		if len(batch) < audienceBatchSize {
+			break
+		}
+		afterID = lastID
+	}
+
+	return audience, nil
+}
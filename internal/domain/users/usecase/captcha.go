@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// CaptchaVerifier checks CAPTCHA tokens, e.g. hCaptcha or Cloudflare Turnstile
+type CaptchaVerifier interface {
+	Provider() string
+	SiteKey() string
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// checkLoginCaptcha requires a solved CAPTCHA once the email or IP had repeated failed logins.
+// The answer names the provider and site key, so the frontend can render the challenge.
+// Both the guard and the verifier fail open, the lockout still limits the attempts.
+func (u Usecase) checkLoginCaptcha(ctx context.Context, email, clientIP, token string) error {
+	if u.captcha == nil || u.loginGuard == nil {
+		return nil
+	}
+
+	required, err := u.loginGuard.CaptchaRequired(ctx, email, clientIP)
+	if err != nil {
+		log.Printf("[LOGIN_GUARD] %v", err)
+		return nil
+	}
+	if !required {
+		return nil
+	}
+
+	challenge := map[string]interface{}{
+		"provider": u.captcha.Provider(),
+		"site_key": u.captcha.SiteKey(),
+	}
+	if token == "" {
+		return apperr.Unauthorized("captcha_required", challenge)
+	}
+
+	solved, err := u.captcha.Verify(ctx, token, clientIP)
+	if err != nil {
+		log.Printf("[CAPTCHA] %v", err)
+		return nil
+	}
+	if !solved {
+		return apperr.Unauthorized("invalid_captcha", challenge)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// EmailChangeOptions controls the confirmation link sent to a new email address
+type EmailChangeOptions struct {
+	URL         string        // page of the frontend that submits the token, the token is added as ?token=
+	TokenExpiry time.Duration // how long a confirmation link stays valid
+}
+
+// ChangeEmail starts an email change after checking the password.
+// The new address gets a confirmation link, the current address a security notice, the email stays unchanged until confirmed.
+func (u Usecase) ChangeEmail(ctx context.Context, userExtID string, payload users.ChangeEmailRequest) error {
+	user, err := u.repo.FindUserByExtID(ctx, userExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if user == nil {
+		return apperr.NotFound("user_not_found", nil)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(payload.Password)); err != nil {
+		return apperr.Unauthorized("invalid_password", nil)
+	}
+
+	newEmail := strings.TrimSpace(payload.NewEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return apperr.Validation("email_unchanged", nil)
+	}
+
+	existing, err := u.repo.FindUserByEmail(ctx, newEmail)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if existing != nil {
+		return apperr.Conflict("email_already_exists", nil)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	changeToken := users.EmailChangeToken{
+		UserExtID: user.ExtID,
+		NewEmail:  newEmail,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.emailChange.TokenExpiry),
+		CreatedAt: time.Now(),
+	}
+	if err := u.repo.CreateEmailChangeToken(ctx, changeToken); err != nil {
+		return apperr.Internal(err)
+	}
+
+	u.audit(ctx, users.UserAuditLog{
+		UserExtID: user.ExtID,
+		Event:     users.AuditEmailChangeRequested,
+		Details:   "new email " + newEmail,
+	})
+
+	confirmBody := fmt.Sprintf("Hi %s,\n\nPlease confirm that you want to use this address for your CineStream account.\n"+
+		"Open the link below to confirm. It expires in %s and can only be used once.\n\n%s\n\n"+
+		"If you did not request this, you can ignore this email.",
+		user.Name, u.emailChange.TokenExpiry, tokenLink(u.emailChange.URL, token))
+	noticeBody := fmt.Sprintf("Hi %s,\n\nSomeone asked to change the email of your CineStream account to %s.\n"+
+		"The change only happens once the new address is confirmed.\n\n"+
+		"If this was not you, reset your password right away, the request was made with your current password.",
+		user.Name, newEmail)
+
+	// Security emails ignore the email_notifications preference
+	go func() {
+		if err := u.mailer.Send([]string{newEmail}, "Confirm your new CineStream email", confirmBody); err != nil {
+			log.Printf("[EMAIL_CHANGE] %v", err)
+		}
+		if err := u.mailer.Send([]string{user.Email}, "Your CineStream email is being changed", noticeBody); err != nil {
+			log.Printf("[EMAIL_CHANGE] %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ConfirmEmailChange swaps the email using a token from ChangeEmail and signs the user out everywhere
+func (u Usecase) ConfirmEmailChange(ctx context.Context, payload users.ConfirmEmailChangeRequest) error {
+	token, err := u.repo.FindEmailChangeToken(ctx, hashToken(payload.Token))
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if token == nil {
+		return apperr.Validation("invalid_or_expired_email_change_token", nil)
+	}
+
+	user, err := u.repo.FindUserByExtID(ctx, token.UserExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if user == nil {
+		return apperr.Validation("invalid_or_expired_email_change_token", nil)
+	}
+
+	// The address may have been registered since the change was requested
+	existing, err := u.repo.FindUserByEmail(ctx, token.NewEmail)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if existing != nil {
+		return apperr.Conflict("email_already_exists", nil)
+	}
+
+	changed, err := u.repo.ChangeEmail(ctx, *token)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !changed {
+		return apperr.Validation("invalid_or_expired_email_change_token", nil)
+	}
+
+	u.audit(ctx, users.UserAuditLog{
+		UserExtID: user.ExtID,
+		Event:     users.AuditEmailChanged,
+		Details:   fmt.Sprintf("%s to %s", user.Email, token.NewEmail),
+	})
+
+	body := fmt.Sprintf("Hi %s,\n\nThe email of your CineStream account was changed to %s and you were signed out on all devices.\n\n"+
+		"If this was not you, contact support right away.",
+		user.Name, token.NewEmail)
+	go func() {
+		if err := u.mailer.Send([]string{user.Email}, "Your CineStream email was changed", body); err != nil {
+			log.Printf("[EMAIL_CHANGE] %v", err)
+		}
+	}()
+
+	return nil
+}
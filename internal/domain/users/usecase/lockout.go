@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// LoginGuard tracks failed logins per email and IP
+type LoginGuard interface {
+	Locked(ctx context.Context, email, ip string) (time.Duration, error)
+	RecordFailure(ctx context.Context, email, ip string) (time.Duration, error)
+	CaptchaRequired(ctx context.Context, email, ip string) (bool, error)
+	Reset(ctx context.Context, email string) (bool, error)
+}
+
+// checkLoginLock rejects the login while the email or IP is locked.
+// The guard fails open, a Redis outage must not lock everybody out.
+func (u Usecase) checkLoginLock(ctx context.Context, email, clientIP string) error {
+	if u.loginGuard == nil {
+		return nil
+	}
+
+	remaining, err := u.loginGuard.Locked(ctx, email, clientIP)
+	if err != nil {
+		log.Printf("[LOGIN_GUARD] %v", err)
+		return nil
+	}
+	if remaining > 0 {
+		return apperr.RateLimited("too_many_login_attempts", map[string]interface{}{
+			"retry_after_seconds": int(remaining.Round(time.Second).Seconds()),
+		})
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login and writes an audit log entry when it locked an existing account
+func (u Usecase) recordLoginFailure(ctx context.Context, user *users.User, email, clientIP string) {
+	if u.loginGuard == nil {
+		return
+	}
+
+	lockout, err := u.loginGuard.RecordFailure(ctx, email, clientIP)
+	if err != nil {
+		log.Printf("[LOGIN_GUARD] %v", err)
+		return
+	}
+	if lockout > 0 && user != nil {
+		u.audit(ctx, users.UserAuditLog{
+			UserExtID: user.ExtID,
+			Event:     users.AuditAccountLocked,
+			IPAddress: clientIP,
+			Details:   fmt.Sprintf("locked for %s after repeated failed logins", lockout),
+		})
+	}
+}
+
+// resetLoginGuard clears the failures of an email, it reports whether the account was locked
+func (u Usecase) resetLoginGuard(ctx context.Context, email string) bool {
+	if u.loginGuard == nil {
+		return false
+	}
+
+	wasLocked, err := u.loginGuard.Reset(ctx, email)
+	if err != nil {
+		log.Printf("[LOGIN_GUARD] %v", err)
+	}
+	return wasLocked
+}
+
+// UnlockUser lifts a login lockout before it expires (Admin only)
+func (u Usecase) UnlockUser(ctx context.Context, actorExtID, userExtID string) (*users.AdminUserResponse, error) {
+	user, err := u.findUserForAdmin(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.loginGuard != nil {
+		wasLocked, err := u.loginGuard.Reset(ctx, user.Email)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if !wasLocked {
+			return nil, apperr.Conflict("user_not_locked", nil)
+		}
+	}
+
+	u.audit(ctx, users.UserAuditLog{
+		UserExtID:  user.ExtID,
+		Event:      users.AuditAccountUnlocked,
+		ActorExtID: actorExtID,
+		Details:    "unlocked by admin",
+	})
+
+	result := toAdminUserResponse(*user)
+	return &result, nil
+}
+
+// GetUserAuditLogs returns the latest audit log entries of a user (Admin only)
+func (u Usecase) GetUserAuditLogs(ctx context.Context, userExtID string, limit int) ([]users.UserAuditLog, error) {
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	if _, err := u.findUserForAdmin(ctx, userExtID); err != nil {
+		return nil, err
+	}
+
+	logs, err := u.repo.FindAuditLogs(ctx, userExtID, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return logs, nil
+}
+
+// audit writes an audit log entry, a failure is logged and does not fail the request
+func (u Usecase) audit(ctx context.Context, entry users.UserAuditLog) {
+	entry.CreatedAt = time.Now()
+	if err := u.repo.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("[AUDIT] failed to record %s for %s: %v", entry.Event, entry.UserExtID, err)
+	}
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// BreachChecker looks up passwords in a database of known data breaches
+type BreachChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// checkPasswordBreached rejects passwords that appeared in known data breaches.
+// The check fails open, an outage of the breach API must not block registration.
+func (u Usecase) checkPasswordBreached(ctx context.Context, password string) error {
+	if u.breachChecker == nil {
+		return nil
+	}
+
+	breached, err := u.breachChecker.IsBreached(ctx, password)
+	if err != nil {
+		log.Printf("[PASSWORD_BREACH] %v", err)
+		return nil
+	}
+	if breached {
+		return apperr.Validation("password_breached", "this password appeared in a data breach, please choose another one")
+	}
+	return nil
+}
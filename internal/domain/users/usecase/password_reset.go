@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordResetOptions controls the reset link sent by email
+type PasswordResetOptions struct {
+	URL         string        // page of the frontend that submits the token, the token is added as ?token=
+	TokenExpiry time.Duration // how long a reset link stays valid
+}
+
+// ForgotPassword emails a reset link when the account exists and is not disabled.
+// The result is the same for unknown emails so accounts cannot be enumerated.
+func (u Usecase) ForgotPassword(ctx context.Context, payload users.ForgotPasswordRequest) error {
+	user, err := u.repo.FindUserByEmail(ctx, payload.Email)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	if user == nil || user.DisabledAt != nil {
+		return nil
+	}
+
+	if err := u.sendPasswordResetLink(ctx, user, false); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// sendPasswordResetLink stores a new reset token and emails its link to the user in the background.
+// forced is set when an admin requires the reset, the user did not ask for it then.
+func (u Usecase) sendPasswordResetLink(ctx context.Context, user *users.User, forced bool) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	resetToken := users.PasswordResetToken{
+		UserExtID: user.ExtID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.passwordReset.TokenExpiry),
+		CreatedAt: time.Now(),
+	}
+
+	if err := u.repo.CreatePasswordResetToken(ctx, resetToken); err != nil {
+		return err
+	}
+
+	event := notification.Event{
+		Type: notification.EventPasswordReset,
+		To:   []string{user.Email},
+		Data: map[string]string{
+			"name":       user.Name,
+			"link":       u.resetLink(token),
+			"expires_in": u.passwordReset.TokenExpiry.String(),
+			"forced":     strconv.FormatBool(forced),
+		},
+	}
+
+	// Queued in the background so the response time does not reveal whether the account exists.
+	// Security emails ignore the email_notifications preference.
+	go func() {
+		if err := u.notifier.Notify(context.WithoutCancel(ctx), event); err != nil {
+			log.Printf("[PASSWORD_RESET] %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword, revokes all refresh tokens,
+// lifts a lockout and completes a reset forced by an admin
+func (u Usecase) ResetPassword(ctx context.Context, payload users.ResetPasswordRequest) error {
+	token, err := u.repo.FindPasswordResetToken(ctx, hashToken(payload.Token))
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	if token == nil {
+		return apperr.Validation("invalid_or_expired_reset_token", nil)
+	}
+
+	if err := u.checkPasswordBreached(ctx, payload.NewPassword); err != nil {
+		return err
+	}
+
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	consumed, err := u.repo.ResetPassword(ctx, *token, string(hashPassword))
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	if !consumed {
+		return apperr.Validation("invalid_or_expired_reset_token", nil)
+	}
+
+	// Proving access to the mailbox also lifts a login lockout
+	user, err := u.repo.FindUserByExtID(ctx, token.UserExtID)
+	if err != nil {
+		log.Printf("[PASSWORD_RESET] %v", err)
+	} else if user != nil && u.resetLoginGuard(ctx, user.Email) {
+		u.audit(ctx, users.UserAuditLog{
+			UserExtID: user.ExtID,
+			Event:     users.AuditAccountUnlocked,
+			Details:   "unlocked by password reset",
+		})
+	}
+
+	return nil
+}
+
+func (u Usecase) resetLink(token string) string {
+	return tokenLink(u.passwordReset.URL, token)
+}
+
+// tokenLink adds the token as ?token= to a frontend page, only the token is returned when no page is configured
+func tokenLink(page, token string) string {
+	if page == "" {
+		return token
+	}
+
+	link, err := url.Parse(page)
+	if err != nil {
+		return page + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// newToken returns a random token for an emailed link
+func newToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// hashToken returns the SHA256 hex digest stored instead of the raw token
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
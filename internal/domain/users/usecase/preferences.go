@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// GetPreferences returns the preferences of the current user, defaults when none were saved
+func (u Usecase) GetPreferences(ctx context.Context, userExtID string) (*users.UserPreferences, error) {
+	prefs, err := u.repo.FindPreferences(ctx, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if prefs == nil {
+		defaults := users.DefaultPreferences(userExtID)
+		return &defaults, nil
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences changes the preferences given in the request
+func (u Usecase) UpdatePreferences(ctx context.Context, userExtID string, req users.UpdatePreferencesRequest) (*users.UserPreferences, error) {
+	prefs, err := u.GetPreferences(ctx, userExtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.EmailNotifications != nil {
+		prefs.EmailNotifications = *req.EmailNotifications
+	}
+	if req.InAppNotifications != nil {
+		prefs.InAppNotifications = *req.InAppNotifications
+	}
+	if req.MarketingOptIn != nil && *req.MarketingOptIn != prefs.MarketingOptIn {
+		// The consent time only changes with the opt-in itself, not with other preferences
+		prefs.MarketingOptIn = *req.MarketingOptIn
+		prefs.MarketingConsentedAt = nil
+		if prefs.MarketingOptIn {
+			now := time.Now()
+			prefs.MarketingConsentedAt = &now
+		}
+	}
+	if req.Language != nil {
+		prefs.Language = *req.Language
+	}
+	if req.ClearMaxMaturity {
+		prefs.MaxMaturityRating = nil
+	} else if req.MaxMaturityRating != nil {
+		prefs.MaxMaturityRating = req.MaxMaturityRating
+	}
+
+	if err := u.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return prefs, nil
+}
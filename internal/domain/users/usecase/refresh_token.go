@@ -0,0 +1,14 @@
+package usecase
+
+import "time"
+
+// RefreshTokenOptions controls how long refresh tokens stay valid
+type RefreshTokenOptions struct {
+	Expiry     time.Duration // absolute lifetime counted from login, never extended
+	IdleExpiry time.Duration // a token not used for this long stops working, every refresh restarts it
+}
+
+// idleCutoff returns the oldest last use a refresh token may have to still be valid
+func (o RefreshTokenOptions) idleCutoff(now time.Time) time.Time {
+	return now.Add(-o.IdleExpiry)
+}
@@ -5,12 +5,14 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
-	"net/http"
+	"log"
 	"time"
 
 	"github.com/martinmanurung/cinestream/internal/domain/users"
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+	"github.com/martinmanurung/cinestream/pkg/constant"
 	"github.com/martinmanurung/cinestream/pkg/jwt"
-	"github.com/martinmanurung/cinestream/pkg/response"
 	"github.com/segmentio/ksuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -21,39 +23,98 @@ type UserRepository interface {
 	FindUserByExtID(ctx context.Context, extID string) (*users.User, error)
 	FindUserByID(ctx context.Context, userID int) (*users.User, error)
 	CreateRefreshToken(ctx context.Context, token users.UserRefreshToken) error
-	FindRefreshToken(ctx context.Context, tokenHash string) (*users.UserRefreshToken, error)
+	FindRefreshToken(ctx context.Context, tokenHash string, usedAfter time.Time) (*users.UserRefreshToken, error)
 	DeleteRefreshToken(ctx context.Context, tokenHash string) error
+	TouchRefreshToken(ctx context.Context, tokenHash string, usedAt time.Time) error
+	CreatePasswordResetToken(ctx context.Context, token users.PasswordResetToken) error
+	FindPasswordResetToken(ctx context.Context, tokenHash string) (*users.PasswordResetToken, error)
+	ResetPassword(ctx context.Context, token users.PasswordResetToken, passwordHash string) (bool, error)
+	CreateEmailChangeToken(ctx context.Context, token users.EmailChangeToken) error
+	FindEmailChangeToken(ctx context.Context, tokenHash string) (*users.EmailChangeToken, error)
+	ChangeEmail(ctx context.Context, token users.EmailChangeToken) (bool, error)
+	FindUsers(ctx context.Context, page, limit int, filter users.UserFilter) ([]users.User, int64, error)
+	UpdateUser(ctx context.Context, extID string, updates map[string]interface{}) error
+	DeleteUserRefreshTokens(ctx context.Context, userExtID string) error
+	CreateAuditLog(ctx context.Context, entry users.UserAuditLog) error
+	FindAuditLogs(ctx context.Context, userExtID string, limit int) ([]users.UserAuditLog, error)
+	FindPreferences(ctx context.Context, userExtID string) (*users.UserPreferences, error)
+	SavePreferences(ctx context.Context, prefs *users.UserPreferences) error
+	FindMarketingAudience(ctx context.Context, filter users.AudienceFilter, afterID, limit int) ([]users.AudienceMember, int, error)
+	FindGenreInterests(ctx context.Context, userExtIDs []string) (map[string][]string, error)
+}
+
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
+
+// Notifier queues templated notifications, the worker sends them
+type Notifier interface {
+	Notify(ctx context.Context, event notification.Event) error
 }
 
 type Usecase struct {
-	repo       UserRepository
-	jwtService *jwt.JWTService
+	repo          UserRepository
+	jwtService    *jwt.JWTService
+	mailer        Mailer
+	notifier      Notifier
+	passwordReset PasswordResetOptions
+	emailChange   EmailChangeOptions
+	loginGuard    LoginGuard
+	breachChecker BreachChecker
+	refreshToken  RefreshTokenOptions
+	captcha       CaptchaVerifier
 }
 
-func NewUsecase(repo UserRepository, jwtService *jwt.JWTService) *Usecase {
+// NewUsecase creates the user usecase, breachChecker is nil when breached passwords are accepted
+// and captcha is nil when logins never require a CAPTCHA
+func NewUsecase(repo UserRepository, jwtService *jwt.JWTService, mailer Mailer, notifier Notifier, passwordReset PasswordResetOptions, emailChange EmailChangeOptions, loginGuard LoginGuard, breachChecker BreachChecker, refreshToken RefreshTokenOptions, captcha CaptchaVerifier) *Usecase {
+	if passwordReset.TokenExpiry <= 0 {
+		passwordReset.TokenExpiry = time.Hour
+	}
+	if emailChange.TokenExpiry <= 0 {
+		emailChange.TokenExpiry = 24 * time.Hour
+	}
+	if refreshToken.Expiry <= 0 {
+		refreshToken.Expiry = 90 * 24 * time.Hour
+	}
+	if refreshToken.IdleExpiry <= 0 {
+		refreshToken.IdleExpiry = 30 * 24 * time.Hour
+	}
 	return &Usecase{
-		repo:       repo,
-		jwtService: jwtService,
+		repo:          repo,
+		jwtService:    jwtService,
+		mailer:        mailer,
+		notifier:      notifier,
+		passwordReset: passwordReset,
+		emailChange:   emailChange,
+		loginGuard:    loginGuard,
+		breachChecker: breachChecker,
+		refreshToken:  refreshToken,
+		captcha:       captcha,
 	}
 }
 
 func (u Usecase) RegisterUser(ctx context.Context, payload users.UserRegisterRequest) (*users.UserRegisterResponse, error) {
 	val, err := u.repo.FindUserByEmail(ctx, payload.Email)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	if val != nil {
-		return nil, response.NewError(http.StatusConflict, "email_already_exists", nil)
+		return nil, apperr.Conflict("email_already_exists", nil)
 	}
 
 	if payload.Password == "" {
-		return nil, response.NewError(http.StatusBadRequest, "password_required", nil)
+		return nil, apperr.Validation("password_required", nil)
+	}
+
+	if err := u.checkPasswordBreached(ctx, payload.Password); err != nil {
+		return nil, err
 	}
 
 	hashPassword, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	extID := "user_" + ksuid.New().String()
@@ -63,7 +124,7 @@ func (u Usecase) RegisterUser(ctx context.Context, payload users.UserRegisterReq
 		Name:      payload.Name,
 		Email:     payload.Email,
 		Password:  string(hashPassword),
-		Role:      "USER",
+		Role:      constant.RoleUser,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -79,33 +140,58 @@ func (u Usecase) RegisterUser(ctx context.Context, payload users.UserRegisterReq
 	}, nil
 }
 
-func (u Usecase) LoginUser(ctx context.Context, payload users.UserLoginRequest) (*users.UserLoginResponse, error) {
+func (u Usecase) LoginUser(ctx context.Context, payload users.UserLoginRequest, clientIP string) (*users.UserLoginResponse, error) {
+	// Locked emails and IPs are rejected before the password is checked
+	if err := u.checkLoginLock(ctx, payload.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	// After repeated failures the password is only checked together with a solved CAPTCHA
+	if err := u.checkLoginCaptcha(ctx, payload.Email, clientIP, payload.CaptchaToken); err != nil {
+		return nil, err
+	}
+
 	// Find user by email
 	user, err := u.repo.FindUserByEmail(ctx, payload.Email)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	if user == nil {
-		return nil, response.NewError(http.StatusUnauthorized, "invalid_credentials", nil)
+		u.recordLoginFailure(ctx, nil, payload.Email, clientIP)
+		return nil, apperr.Unauthorized("invalid_credentials", nil)
 	}
 
 	// Compare password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(payload.Password))
 	if err != nil {
-		return nil, response.NewError(http.StatusUnauthorized, "invalid_credentials", nil)
+		u.recordLoginFailure(ctx, user, payload.Email, clientIP)
+		return nil, apperr.Unauthorized("invalid_credentials", nil)
+	}
+	u.resetLoginGuard(ctx, payload.Email)
+
+	if user.DisabledAt != nil {
+		return nil, apperr.Forbidden("account_disabled", nil)
+	}
+
+	// A forced reset is completed through the emailed link, no tokens are issued until then
+	if user.PasswordResetRequired {
+		if err := u.sendPasswordResetLink(ctx, user, true); err != nil {
+			log.Printf("[PASSWORD_RESET] %v", err)
+		}
+		return nil, apperr.Forbidden("password_reset_required", "a link to choose a new password was sent to your email")
 	}
 
 	// Generate JWT access token
 	token, err := u.jwtService.GenerateToken(user.ExtID, user.Role)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	// Generate refresh token (32 bytes random string)
 	refreshTokenBytes := make([]byte, 32)
 	if _, err := rand.Read(refreshTokenBytes); err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 	refreshToken := hex.EncodeToString(refreshTokenBytes)
 
@@ -113,17 +199,18 @@ func (u Usecase) LoginUser(ctx context.Context, payload users.UserLoginRequest)
 	hash := sha256.Sum256([]byte(refreshToken))
 	tokenHash := hex.EncodeToString(hash[:])
 
-	// Store refresh token with 7 days expiry
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
+	// Store refresh token with its absolute expiry, the idle expiry starts now
+	now := time.Now()
 	refreshTokenRecord := users.UserRefreshToken{
-		UserExtID: user.ExtID,
-		TokenHash: tokenHash,
-		ExpiresAt: expiresAt,
-		CreatedAt: time.Now(),
+		UserExtID:  user.ExtID,
+		TokenHash:  tokenHash,
+		ExpiresAt:  now.Add(u.refreshToken.Expiry),
+		CreatedAt:  now,
+		LastUsedAt: now,
 	}
 
 	if err := u.repo.CreateRefreshToken(ctx, refreshTokenRecord); err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	return &users.UserLoginResponse{
@@ -141,11 +228,11 @@ func (u Usecase) LoginUser(ctx context.Context, payload users.UserLoginRequest)
 func (u Usecase) GetUserProfile(ctx context.Context, userExtID string) (*users.UserProfile, error) {
 	user, err := u.repo.FindUserByExtID(ctx, userExtID)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	if user == nil {
-		return nil, response.NewError(http.StatusNotFound, "user_not_found", nil)
+		return nil, apperr.NotFound("user_not_found", nil)
 	}
 
 	return &users.UserProfile{
@@ -162,18 +249,18 @@ func (u Usecase) Logout(ctx context.Context, refreshToken string) error {
 	tokenHash := hex.EncodeToString(hash[:])
 
 	// Verify token exists and not expired
-	storedToken, err := u.repo.FindRefreshToken(ctx, tokenHash)
+	storedToken, err := u.repo.FindRefreshToken(ctx, tokenHash, u.refreshToken.idleCutoff(time.Now()))
 	if err != nil {
-		return response.InternalServerError(err)
+		return apperr.Internal(err)
 	}
 
 	if storedToken == nil {
-		return response.NewError(http.StatusUnauthorized, "invalid_refresh_token", nil)
+		return apperr.Unauthorized("invalid_refresh_token", nil)
 	}
 
 	// Delete the refresh token
 	if err := u.repo.DeleteRefreshToken(ctx, tokenHash); err != nil {
-		return response.InternalServerError(err)
+		return apperr.Internal(err)
 	}
 
 	return nil
@@ -184,30 +271,44 @@ func (u Usecase) RefreshToken(ctx context.Context, refreshToken string) (*users.
 	hash := sha256.Sum256([]byte(refreshToken))
 	tokenHash := hex.EncodeToString(hash[:])
 
-	// Find and verify token exists and not expired
-	storedToken, err := u.repo.FindRefreshToken(ctx, tokenHash)
+	// Find and verify token exists and not expired, neither absolutely nor from inactivity
+	now := time.Now()
+	storedToken, err := u.repo.FindRefreshToken(ctx, tokenHash, u.refreshToken.idleCutoff(now))
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	if storedToken == nil {
-		return nil, response.NewError(http.StatusUnauthorized, "invalid_or_expired_refresh_token", nil)
+		return nil, apperr.Unauthorized("invalid_or_expired_refresh_token", nil)
 	}
 
 	// Get user data to generate new access token
 	user, err := u.repo.FindUserByExtID(ctx, storedToken.UserExtID)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
 	}
 
 	if user == nil {
-		return nil, response.NewError(http.StatusNotFound, "user_not_found", nil)
+		return nil, apperr.NotFound("user_not_found", nil)
+	}
+
+	if user.DisabledAt != nil {
+		return nil, apperr.Forbidden("account_disabled", nil)
+	}
+
+	if user.PasswordResetRequired {
+		return nil, apperr.Forbidden("password_reset_required", nil)
 	}
 
 	// Generate new access token (JWT, 1 hour expiry)
 	accessToken, err := u.jwtService.GenerateToken(user.ExtID, user.Role)
 	if err != nil {
-		return nil, response.InternalServerError(err)
+		return nil, apperr.Internal(err)
+	}
+
+	// Using the token keeps the session alive, the absolute expiry stays as it is
+	if err := u.repo.TouchRefreshToken(ctx, tokenHash, now); err != nil {
+		return nil, apperr.Internal(err)
 	}
 
 	return &users.RefreshTokenResponse{
@@ -3,22 +3,129 @@ package users
 import "time"
 
 type User struct {
-	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
-	ExtID     string    `json:"ext_id" gorm:"ext_id;unique"`
-	Name      string    `json:"name" gorm:"name"`
-	Email     string    `json:"email" gorm:"email;unique"`
-	Password  string    `json:"password" gorm:"password"`
-	Role      string    `json:"role" gorm:"role"`
-	CreatedAt time.Time `json:"created_at" gorm:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"updated_at"`
+	ID         int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ExtID      string     `json:"ext_id" gorm:"ext_id;unique"`
+	Name       string     `json:"name" gorm:"name"`
+	Email      string     `json:"email" gorm:"email;unique"`
+	Password   string     `json:"password" gorm:"password"`
+	Role       string     `json:"role" gorm:"role"`
+	DisabledAt *time.Time `json:"disabled_at" gorm:"disabled_at"` // set while an admin has disabled the account
+	CreatedAt  time.Time  `json:"created_at" gorm:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"updated_at"`
+
+	// Set by an admin, login is refused until the password is reset through an emailed link
+	PasswordResetRequired bool `json:"password_reset_required" gorm:"password_reset_required"`
+
+	// Set when an access grant import created the account instead of a sign up
+	InvitedAt *time.Time `json:"invited_at,omitempty" gorm:"invited_at"`
 }
 
 type UserRefreshToken struct {
 	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
 	UserExtID string    `json:"user_ext_id" gorm:"column:user_ext_id;not null;index"`
 	TokenHash string    `json:"token_hash" gorm:"token_hash;unique"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"expires_at"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"expires_at"` // absolute expiry, see RefreshTokenOptions
 	CreatedAt time.Time `json:"created_at" gorm:"created_at"`
+
+	// Moved forward on every refresh, the token expires once it is unused for the idle expiry
+	LastUsedAt time.Time `json:"last_used_at" gorm:"last_used_at"`
+}
+
+// PasswordResetToken is a single-use token emailed to the user, only its hash is stored
+type PasswordResetToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID string     `json:"user_ext_id" gorm:"column:user_ext_id;not null;index"`
+	TokenHash string     `json:"token_hash" gorm:"token_hash;unique"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"expires_at"`
+	UsedAt    *time.Time `json:"used_at" gorm:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"created_at"`
+}
+
+// EmailChangeToken is a single-use token emailed to the new address, the email is only swapped once it is confirmed
+type EmailChangeToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID string     `json:"user_ext_id" gorm:"column:user_ext_id;not null;index"`
+	NewEmail  string     `json:"new_email" gorm:"column:new_email;not null"`
+	TokenHash string     `json:"token_hash" gorm:"token_hash;unique"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"expires_at"`
+	UsedAt    *time.Time `json:"used_at" gorm:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"created_at"`
+}
+
+// UserAuditLog records security relevant events of an account, e.g. lockouts
+type UserAuditLog struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID  string    `json:"user_ext_id" gorm:"column:user_ext_id;not null;index"`
+	Event      string    `json:"event" gorm:"event"`
+	ActorExtID string    `json:"actor_ext_id,omitempty" gorm:"column:actor_ext_id"` // admin who caused the event, empty for the system
+	IPAddress  string    `json:"ip_address,omitempty" gorm:"column:ip_address"`
+	Details    string    `json:"details,omitempty" gorm:"details"`
+	CreatedAt  time.Time `json:"created_at" gorm:"created_at"`
+}
+
+// Audit log events
+const (
+	AuditAccountLocked        = "account_locked"
+	AuditAccountUnlocked      = "account_unlocked"
+	AuditEmailChangeRequested = "email_change_requested"
+	AuditEmailChanged         = "email_changed"
+	AuditPasswordResetForced  = "password_reset_forced"
+)
+
+// UserPreferences are the notification and content settings of a user, defaults apply until saved
+type UserPreferences struct {
+	UserExtID            string     `json:"-" gorm:"column:user_ext_id;primaryKey"`
+	EmailNotifications   bool       `json:"email_notifications" gorm:"column:email_notifications"` // ticket and account updates by email
+	InAppNotifications   bool       `json:"in_app_notifications" gorm:"column:in_app_notifications"`
+	MarketingOptIn       bool       `json:"marketing_opt_in" gorm:"column:marketing_opt_in"`
+	MarketingConsentedAt *time.Time `json:"marketing_consented_at,omitempty" gorm:"column:marketing_consented_at"` // when the user opted in, nil while opted out
+	Language             string     `json:"language" gorm:"column:language"`                                       // BCP 47 tag, e.g. "id" or "en"
+	MaxMaturityRating    *string    `json:"max_maturity_rating" gorm:"column:max_maturity_rating"`                 // nil means no limit
+	UpdatedAt            time.Time  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName overrides the table name for UserPreferences
+func (UserPreferences) TableName() string {
+	return "user_preferences"
+}
+
+// DefaultLanguage is the preferred language of users who did not choose one
+const DefaultLanguage = "en"
+
+// DefaultPreferences returns the preferences of a user who never saved any.
+// Marketing is opt-in, service notifications are on.
+func DefaultPreferences(userExtID string) UserPreferences {
+	return UserPreferences{
+		UserExtID:          userExtID,
+		EmailNotifications: true,
+		InAppNotifications: true,
+		Language:           DefaultLanguage,
+	}
+}
+
+// UpdatePreferencesRequest changes the given preferences, omitted fields keep their value
+type UpdatePreferencesRequest struct {
+	EmailNotifications *bool   `json:"email_notifications"`
+	InAppNotifications *bool   `json:"in_app_notifications"`
+	MarketingOptIn     *bool   `json:"marketing_opt_in"`
+	Language           *string `json:"language" validate:"omitempty,bcp47_language_tag"`
+	MaxMaturityRating  *string `json:"max_maturity_rating" validate:"omitempty,oneof=G PG PG-13 R NC-17"`
+	ClearMaxMaturity   bool    `json:"clear_max_maturity_rating"` // removes the maturity limit
+}
+
+// AudienceMember is a user who opted in to marketing, exported for email marketing tools
+type AudienceMember struct {
+	ExtID       string    `json:"ext_id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Language    string    `json:"language"`
+	ConsentedAt time.Time `json:"consented_at"`       // when the user opted in to marketing
+	Interests   []string  `json:"interests" gorm:"-"` // most rented genres first
+}
+
+// AudienceFilter narrows the marketing audience to users who rented a genre
+type AudienceFilter struct {
+	GenreID int
 }
 
 type UserRegisterRequest struct {
@@ -30,6 +137,9 @@ type UserRegisterRequest struct {
 type UserLoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+
+	// Required after repeated failed logins, the login answers captcha_required until it is sent
+	CaptchaToken string `json:"captcha_token"`
 }
 
 type LogoutRequest struct {
@@ -40,6 +150,26 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=6"`
+}
+
+// ChangeEmailRequest starts an email change, the current password is required
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ConfirmEmailChangeRequest completes an email change with the token sent to the new address
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type RefreshTokenResponse struct {
 	AccessToken string `json:"access_token"`
 }
@@ -62,3 +192,45 @@ type UserRegisterResponse struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
+
+// Account status filter values of the admin user list
+const (
+	StatusActive   = "active"
+	StatusDisabled = "disabled"
+)
+
+// UserFilter narrows the admin user list
+type UserFilter struct {
+	Role   string
+	Status string // active or disabled
+	Query  string // matches name or email
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=USER ADMIN CONTENT_MANAGER TESTER RIGHTS_HOLDER"`
+}
+
+type AdminUserResponse struct {
+	ExtID      string     `json:"ext_id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	InvitedAt             *time.Time `json:"invited_at,omitempty"`
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+type UserListWithPagination struct {
+	Users      []AdminUserResponse `json:"users"`
+	Pagination PaginationMeta      `json:"pagination"`
+}
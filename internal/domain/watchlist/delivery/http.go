@@ -0,0 +1,108 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/watchlist"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type WatchlistUsecase interface {
+	AddToWatchlist(ctx context.Context, userExtID string, movieID int64) error
+	RemoveFromWatchlist(ctx context.Context, userExtID string, movieID int64) error
+	GetWatchlist(ctx context.Context, userExtID string, page, limit int) (*watchlist.WatchlistWithPagination, error)
+}
+
+type WatchlistHandler struct {
+	ctx     context.Context
+	usecase WatchlistUsecase
+}
+
+func NewWatchlistHandler(ctx context.Context, usecase WatchlistUsecase) *WatchlistHandler {
+	return &WatchlistHandler{
+		ctx:     ctx,
+		usecase: usecase,
+	}
+}
+
+// AddToWatchlist saves a movie to the current user's watchlist
+// POST /api/v1/watchlist/:movieID
+func (h *WatchlistHandler) AddToWatchlist(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("movieID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	err = h.usecase.AddToWatchlist(ctx, userExtID, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "added_to_watchlist", nil)
+}
+
+// RemoveFromWatchlist removes a movie from the current user's watchlist
+// DELETE /api/v1/watchlist/:movieID
+func (h *WatchlistHandler) RemoveFromWatchlist(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	movieID, err := strconv.ParseInt(c.Param("movieID"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_movie_id", err.Error())
+	}
+
+	err = h.usecase.RemoveFromWatchlist(ctx, userExtID, movieID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "removed_from_watchlist", nil)
+}
+
+// GetWatchlist returns the current user's watchlist
+// GET /api/v1/watchlist?page=1&limit=12
+func (h *WatchlistHandler) GetWatchlist(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 12
+	}
+
+	result, err := h.usecase.GetWatchlist(ctx, userExtID, page, limit)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"data":       result.Items,
+		"pagination": result.Pagination,
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/martinmanurung/cinestream/internal/domain/watchlist"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WatchlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchlistRepository(db *gorm.DB) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+// MovieExists checks whether a movie exists
+func (r *WatchlistRepository) MovieExists(ctx context.Context, movieID int64) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("movies").Where("id = ? AND deleted_at IS NULL", movieID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddItem saves a movie to the watchlist, adding the same movie twice is a no-op
+func (r *WatchlistRepository) AddItem(ctx context.Context, item *watchlist.WatchlistItem) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error
+}
+
+// FindItem returns a watchlist entry, nil if the movie is not in the watchlist
+func (r *WatchlistRepository) FindItem(ctx context.Context, userExtID string, movieID int64) (*watchlist.WatchlistItem, error) {
+	var item watchlist.WatchlistItem
+	err := r.db.WithContext(ctx).Where("user_ext_id = ? AND movie_id = ?", userExtID, movieID).First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteItem removes a movie from the watchlist
+func (r *WatchlistRepository) DeleteItem(ctx context.Context, userExtID string, movieID int64) error {
+	return r.db.WithContext(ctx).
+		Where("user_ext_id = ? AND movie_id = ?", userExtID, movieID).
+		Delete(&watchlist.WatchlistItem{}).Error
+}
+
+// FindItemsByUser returns the paginated watchlist of a user, most recently added first
+func (r *WatchlistRepository) FindItemsByUser(ctx context.Context, userExtID string, page, limit int) ([]watchlist.WatchlistItemResponse, int64, error) {
+	var results []watchlist.WatchlistItemResponse
+	var totalCount int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).
+		Table("watchlist_items").
+		Select("movies.id as movie_id, movies.title, movies.poster_url, movies.price, movies.duration_minutes, watchlist_items.created_at as added_at").
+		Joins("JOIN movies ON movies.id = watchlist_items.movie_id AND movies.deleted_at IS NULL").
+		Where("watchlist_items.user_ext_id = ?", userExtID)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("watchlist_items.created_at DESC").Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalCount, nil
+}
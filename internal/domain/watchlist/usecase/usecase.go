@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/martinmanurung/cinestream/internal/domain/watchlist"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+type WatchlistRepository interface {
+	MovieExists(ctx context.Context, movieID int64) (bool, error)
+	AddItem(ctx context.Context, item *watchlist.WatchlistItem) error
+	FindItem(ctx context.Context, userExtID string, movieID int64) (*watchlist.WatchlistItem, error)
+	DeleteItem(ctx context.Context, userExtID string, movieID int64) error
+	FindItemsByUser(ctx context.Context, userExtID string, page, limit int) ([]watchlist.WatchlistItemResponse, int64, error)
+}
+
+type WatchlistUsecase struct {
+	repo WatchlistRepository
+}
+
+func NewWatchlistUsecase(repo WatchlistRepository) *WatchlistUsecase {
+	return &WatchlistUsecase{repo: repo}
+}
+
+// AddToWatchlist saves a movie to the user's watchlist
+func (u *WatchlistUsecase) AddToWatchlist(ctx context.Context, userExtID string, movieID int64) error {
+	exists, err := u.repo.MovieExists(ctx, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !exists {
+		return apperr.NotFound("movie_not_found", nil)
+	}
+
+	item := &watchlist.WatchlistItem{
+		UserExtID: userExtID,
+		MovieID:   movieID,
+	}
+	if err := u.repo.AddItem(ctx, item); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// RemoveFromWatchlist removes a movie from the user's watchlist
+func (u *WatchlistUsecase) RemoveFromWatchlist(ctx context.Context, userExtID string, movieID int64) error {
+	item, err := u.repo.FindItem(ctx, userExtID, movieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if item == nil {
+		return apperr.NotFound("movie_not_in_watchlist", nil)
+	}
+
+	if err := u.repo.DeleteItem(ctx, userExtID, movieID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// GetWatchlist returns the user's paginated watchlist
+func (u *WatchlistUsecase) GetWatchlist(ctx context.Context, userExtID string, page, limit int) (*watchlist.WatchlistWithPagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 12
+	}
+
+	items, totalCount, err := u.repo.FindItemsByUser(ctx, userExtID, page, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit != 0 {
+		totalPages++
+	}
+
+	return &watchlist.WatchlistWithPagination{
+		Items: items,
+		Pagination: watchlist.PaginationMeta{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  totalCount,
+			Limit:       limit,
+		},
+	}, nil
+}
@@ -0,0 +1,42 @@
+package watchlist
+
+import "time"
+
+// WatchlistItem represents a movie saved to a user's watchlist
+type WatchlistItem struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserExtID string    `json:"user_ext_id" gorm:"not null;column:user_ext_id"`
+	MovieID   int64     `json:"movie_id" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for WatchlistItem
+func (WatchlistItem) TableName() string {
+	return "watchlist_items"
+}
+
+// Response DTOs
+
+// WatchlistItemResponse represents a movie in the user's watchlist
+type WatchlistItemResponse struct {
+	MovieID         int64     `json:"movie_id"`
+	Title           string    `json:"title"`
+	PosterURL       string    `json:"poster_url"`
+	Price           float64   `json:"price"`
+	DurationMinutes int       `json:"duration_minutes"`
+	AddedAt         time.Time `json:"added_at"`
+}
+
+// PaginationMeta represents pagination metadata
+type PaginationMeta struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+// WatchlistWithPagination represents a paginated watchlist
+type WatchlistWithPagination struct {
+	Items      []WatchlistItemResponse `json:"items"`
+	Pagination PaginationMeta          `json:"pagination"`
+}
@@ -0,0 +1,216 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/internal/domain/watchparty"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+type WatchPartyUsecase interface {
+	CreateParty(ctx context.Context, hostExtID string, req watchparty.CreatePartyRequest) (*watchparty.PartyResponse, error)
+	GetParty(ctx context.Context, userExtID string, partyID int64) (*watchparty.PartyResponse, error)
+	InviteUser(ctx context.Context, hostExtID string, partyID int64, req watchparty.InviteRequest) error
+	JoinParty(ctx context.Context, userExtID string, partyID int64) (*watchparty.JoinPartyResponse, error)
+	AuthorizeSync(ctx context.Context, userExtID string, partyID int64) error
+	EndParty(ctx context.Context, hostExtID string, partyID int64) error
+}
+
+type WatchPartyHandler struct {
+	ctx      context.Context
+	usecase  WatchPartyUsecase
+	hub      *Hub
+	upgrader websocket.Upgrader
+}
+
+func NewWatchPartyHandler(ctx context.Context, usecase WatchPartyUsecase) *WatchPartyHandler {
+	return &WatchPartyHandler{
+		ctx:     ctx,
+		usecase: usecase,
+		hub:     NewHub(),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// The channel is authenticated with the JWT, not with cookies
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// CreateParty hosts a new watch party
+// POST /api/v1/watch-parties
+func (h *WatchPartyHandler) CreateParty(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req watchparty.CreatePartyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	result, err := h.usecase.CreateParty(ctx, userExtID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "watch_party_created", result)
+}
+
+// GetParty returns the party and its participants
+// GET /api/v1/watch-parties/:id
+func (h *WatchPartyHandler) GetParty(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	partyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_watch_party_id", err.Error())
+	}
+
+	result, err := h.usecase.GetParty(ctx, userExtID, partyID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "success", result)
+}
+
+// InviteUser invites a user to the party (host only)
+// POST /api/v1/watch-parties/:id/invites
+func (h *WatchPartyHandler) InviteUser(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	partyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_watch_party_id", err.Error())
+	}
+
+	var req watchparty.InviteRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_request_body", err.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "validation_failed", err.Error())
+	}
+
+	err = h.usecase.InviteUser(ctx, userExtID, partyID, req)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusCreated, "user_invited", nil)
+}
+
+// JoinParty joins an invited user to the party
+// POST /api/v1/watch-parties/:id/join
+func (h *WatchPartyHandler) JoinParty(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	partyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_watch_party_id", err.Error())
+	}
+
+	result, err := h.usecase.JoinParty(ctx, userExtID, partyID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	return response.Success(c, http.StatusOK, "joined_watch_party", result)
+}
+
+// EndParty ends the party and disconnects everyone (host only)
+// DELETE /api/v1/watch-parties/:id
+func (h *WatchPartyHandler) EndParty(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	partyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_watch_party_id", err.Error())
+	}
+
+	err = h.usecase.EndParty(ctx, userExtID, partyID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	h.hub.CloseParty(partyID)
+
+	return response.Success(c, http.StatusOK, "watch_party_ended", nil)
+}
+
+// Sync upgrades to a WebSocket that synchronizes play/pause/seek across participants
+// GET /api/v1/watch-parties/:id/ws?token=<jwt>
+func (h *WatchPartyHandler) Sync(c echo.Context) error {
+	ctx := h.ctx
+
+	userExtID, ok := c.Get(string(constant.CtxKeyUserExtID)).(string)
+	if !ok || userExtID == "" {
+		return response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	partyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid_watch_party_id", err.Error())
+	}
+
+	err = h.usecase.AuthorizeSync(ctx, userExtID, partyID)
+	if err != nil {
+		return response.HandleError(c, err)
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// Upgrade already wrote the HTTP error
+		return nil
+	}
+
+	cl := &client{
+		partyID:   partyID,
+		userExtID: userExtID,
+		conn:      conn,
+		send:      make(chan watchparty.SyncMessage, sendBufferSize),
+	}
+
+	// New participants start from the current playback state
+	h.hub.register(cl)
+	h.hub.announce(cl, watchparty.SyncJoin)
+
+	go cl.writePump()
+	cl.readPump(h.hub)
+
+	return nil
+}
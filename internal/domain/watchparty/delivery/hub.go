@@ -0,0 +1,214 @@
+package delivery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/martinmanurung/cinestream/internal/domain/watchparty"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 1024
+	sendBufferSize = 16
+)
+
+// Hub keeps the connected participants and the playback state of every party in memory
+type Hub struct {
+	mu    sync.Mutex
+	rooms map[int64]*room
+}
+
+type room struct {
+	clients map[*client]struct{}
+	state   watchparty.SyncMessage
+}
+
+type client struct {
+	partyID   int64
+	userExtID string
+	conn      *websocket.Conn
+	send      chan watchparty.SyncMessage
+}
+
+func NewHub() *Hub {
+	return &Hub{rooms: make(map[int64]*room)}
+}
+
+// register adds a client to its party room and queues the current playback state for it
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[c.partyID]
+	if !ok {
+		r = &room{
+			clients: make(map[*client]struct{}),
+			state:   watchparty.SyncMessage{Type: watchparty.SyncState},
+		}
+		h.rooms[c.partyID] = r
+	}
+	r.clients[c] = struct{}{}
+
+	state := r.state
+	state.Type = watchparty.SyncState
+	state.SentAt = time.Now().UnixMilli()
+	c.send <- state
+}
+
+// unregister removes a client, dropping the room once it is empty
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[c.partyID]
+	if !ok {
+		return
+	}
+	if _, ok := r.clients[c]; !ok {
+		return
+	}
+
+	delete(r.clients, c)
+	close(c.send)
+	if len(r.clients) == 0 {
+		delete(h.rooms, c.partyID)
+	}
+}
+
+// apply records a playback event as the room state and fans it out to the other participants
+func (h *Hub) apply(from *client, msg watchparty.SyncMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[from.partyID]
+	if !ok {
+		return
+	}
+
+	switch msg.Type {
+	case watchparty.SyncPlay:
+		msg.Playing = true
+	case watchparty.SyncPause:
+		msg.Playing = false
+	case watchparty.SyncSeek:
+		msg.Playing = r.state.Playing
+	}
+	msg.UserExtID = from.userExtID
+	msg.SentAt = time.Now().UnixMilli()
+	r.state = msg
+
+	h.broadcastLocked(r, from, msg)
+}
+
+// announce tells the other participants that someone joined or left
+func (h *Hub) announce(from *client, msgType string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[from.partyID]
+	if !ok {
+		return
+	}
+
+	h.broadcastLocked(r, from, watchparty.SyncMessage{
+		Type:      msgType,
+		Position:  r.state.Position,
+		Playing:   r.state.Playing,
+		UserExtID: from.userExtID,
+		SentAt:    time.Now().UnixMilli(),
+	})
+}
+
+// CloseParty disconnects every participant, used when the host ends the party
+func (h *Hub) CloseParty(partyID int64) {
+	h.mu.Lock()
+	r, ok := h.rooms[partyID]
+	if ok {
+		delete(h.rooms, partyID)
+	}
+	h.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	for c := range r.clients {
+		close(c.send)
+	}
+}
+
+func (h *Hub) broadcastLocked(r *room, from *client, msg watchparty.SyncMessage) {
+	for c := range r.clients {
+		if c == from {
+			continue
+		}
+		select {
+		case c.send <- msg:
+		default:
+			// Slow consumer, it will resync from the state message on reconnect
+			delete(r.clients, c)
+			close(c.send)
+		}
+	}
+}
+
+// readPump reads playback events from the participant until the connection closes
+func (c *client) readPump(h *Hub) {
+	defer func() {
+		h.announce(c, watchparty.SyncLeave)
+		h.unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg watchparty.SyncMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case watchparty.SyncPlay, watchparty.SyncPause, watchparty.SyncSeek:
+			if msg.Position < 0 {
+				continue
+			}
+			h.apply(c, msg)
+		}
+	}
+}
+
+// writePump sends queued events and keepalive pings to the participant
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/watchparty"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WatchPartyRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchPartyRepository(db *gorm.DB) *WatchPartyRepository {
+	return &WatchPartyRepository{db: db}
+}
+
+// HasMovieAccess checks whether the user has an active purchase or rental of the movie
+func (r *WatchPartyRepository) HasMovieAccess(ctx context.Context, userExtID string, movieID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("user_movie_access").
+		Where("user_ext_id = ? AND movie_id = ?", userExtID, movieID).
+		Where("access_expires_at IS NULL OR access_expires_at > ?", time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetMovieHLSURL returns the HLS playlist path of a READY movie, empty if not ready
+func (r *WatchPartyRepository) GetMovieHLSURL(ctx context.Context, movieID int64) (string, error) {
+	var hlsURL string
+	err := r.db.WithContext(ctx).
+		Table("movie_videos").
+		Where("movie_id = ? AND upload_status = ?", movieID, "READY").
+		Pluck("hls_playlist_url", &hlsURL).Error
+	if err != nil {
+		return "", err
+	}
+	return hlsURL, nil
+}
+
+func (r *WatchPartyRepository) CreateParty(ctx context.Context, party *watchparty.WatchParty) error {
+	return r.db.WithContext(ctx).Create(party).Error
+}
+
+func (r *WatchPartyRepository) FindPartyByID(ctx context.Context, partyID int64) (*watchparty.WatchParty, error) {
+	var party watchparty.WatchParty
+	err := r.db.WithContext(ctx).Where("id = ?", partyID).First(&party).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (r *WatchPartyRepository) EndParty(ctx context.Context, partyID int64, endedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&watchparty.WatchParty{}).
+		Where("id = ?", partyID).
+		Updates(map[string]interface{}{
+			"status":   watchparty.StatusEnded,
+			"ended_at": endedAt,
+		}).Error
+}
+
+// AddMember invites a user, inviting the same user twice is a no-op
+func (r *WatchPartyRepository) AddMember(ctx context.Context, member *watchparty.WatchPartyMember) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+}
+
+func (r *WatchPartyRepository) FindMember(ctx context.Context, partyID int64, userExtID string) (*watchparty.WatchPartyMember, error) {
+	var member watchparty.WatchPartyMember
+	err := r.db.WithContext(ctx).Where("party_id = ? AND user_ext_id = ?", partyID, userExtID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *WatchPartyRepository) MarkMemberJoined(ctx context.Context, partyID int64, userExtID string, joinedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&watchparty.WatchPartyMember{}).
+		Where("party_id = ? AND user_ext_id = ?", partyID, userExtID).
+		Updates(map[string]interface{}{
+			"status":    watchparty.MemberJoined,
+			"joined_at": joinedAt,
+		}).Error
+}
+
+func (r *WatchPartyRepository) FindMembers(ctx context.Context, partyID int64) ([]watchparty.WatchPartyMember, error) {
+	var members []watchparty.WatchPartyMember
+	err := r.db.WithContext(ctx).Where("party_id = ?", partyID).Order("created_at ASC").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
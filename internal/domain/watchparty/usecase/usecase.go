@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/domain/watchparty"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+type WatchPartyRepository interface {
+	HasMovieAccess(ctx context.Context, userExtID string, movieID int64) (bool, error)
+	GetMovieHLSURL(ctx context.Context, movieID int64) (string, error)
+	CreateParty(ctx context.Context, party *watchparty.WatchParty) error
+	FindPartyByID(ctx context.Context, partyID int64) (*watchparty.WatchParty, error)
+	EndParty(ctx context.Context, partyID int64, endedAt time.Time) error
+	AddMember(ctx context.Context, member *watchparty.WatchPartyMember) error
+	FindMember(ctx context.Context, partyID int64, userExtID string) (*watchparty.WatchPartyMember, error)
+	MarkMemberJoined(ctx context.Context, partyID int64, userExtID string, joinedAt time.Time) error
+	FindMembers(ctx context.Context, partyID int64) ([]watchparty.WatchPartyMember, error)
+}
+
+type WatchPartyUsecase struct {
+	repo WatchPartyRepository
+}
+
+func NewWatchPartyUsecase(repo WatchPartyRepository) *WatchPartyUsecase {
+	return &WatchPartyUsecase{repo: repo}
+}
+
+// CreateParty creates a party for a movie the host has access to
+func (u *WatchPartyUsecase) CreateParty(ctx context.Context, hostExtID string, req watchparty.CreatePartyRequest) (*watchparty.PartyResponse, error) {
+	hasAccess, err := u.repo.HasMovieAccess(ctx, hostExtID, req.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if !hasAccess {
+		return nil, apperr.Forbidden("movie_access_required", nil)
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = watchparty.ModeEntitled
+	}
+
+	party := &watchparty.WatchParty{
+		HostExtID: hostExtID,
+		MovieID:   req.MovieID,
+		Mode:      mode,
+		Status:    watchparty.StatusActive,
+	}
+	if err := u.repo.CreateParty(ctx, party); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	// The host is a participant too
+	now := time.Now()
+	host := &watchparty.WatchPartyMember{
+		PartyID:   party.ID,
+		UserExtID: hostExtID,
+		Status:    watchparty.MemberJoined,
+		JoinedAt:  &now,
+	}
+	if err := u.repo.AddMember(ctx, host); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return u.partyResponse(ctx, party)
+}
+
+// GetParty returns a party to its participants
+func (u *WatchPartyUsecase) GetParty(ctx context.Context, userExtID string, partyID int64) (*watchparty.PartyResponse, error) {
+	party, err := u.findParty(ctx, partyID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := u.repo.FindMember(ctx, partyID, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if member == nil {
+		return nil, apperr.NotFound("watch_party_not_found", nil)
+	}
+
+	return u.partyResponse(ctx, party)
+}
+
+// InviteUser invites a user to an active party (host only)
+func (u *WatchPartyUsecase) InviteUser(ctx context.Context, hostExtID string, partyID int64, req watchparty.InviteRequest) error {
+	party, err := u.findActiveParty(ctx, partyID)
+	if err != nil {
+		return err
+	}
+	if party.HostExtID != hostExtID {
+		return apperr.Forbidden("only_host_can_invite", nil)
+	}
+
+	member := &watchparty.WatchPartyMember{
+		PartyID:   partyID,
+		UserExtID: req.UserExtID,
+		Status:    watchparty.MemberInvited,
+	}
+	if err := u.repo.AddMember(ctx, member); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// JoinParty lets an invited user join, checking entitlement unless the host pays
+func (u *WatchPartyUsecase) JoinParty(ctx context.Context, userExtID string, partyID int64) (*watchparty.JoinPartyResponse, error) {
+	party, err := u.findActiveParty(ctx, partyID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := u.repo.FindMember(ctx, partyID, userExtID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if member == nil {
+		return nil, apperr.Forbidden("not_invited", nil)
+	}
+
+	if err := u.checkEntitlement(ctx, party, userExtID); err != nil {
+		return nil, err
+	}
+
+	hlsURL, err := u.repo.GetMovieHLSURL(ctx, party.MovieID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if hlsURL == "" {
+		return nil, apperr.Conflict("movie_not_ready", nil)
+	}
+
+	if member.Status != watchparty.MemberJoined {
+		if err := u.repo.MarkMemberJoined(ctx, partyID, userExtID, time.Now()); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	}
+
+	return &watchparty.JoinPartyResponse{
+		PartyID:      party.ID,
+		MovieID:      party.MovieID,
+		HLSURL:       hlsURL,
+		WebSocketURL: fmt.Sprintf("/api/v1/watch-parties/%d/ws", party.ID),
+	}, nil
+}
+
+// AuthorizeSync checks that a user may connect to the party sync channel
+func (u *WatchPartyUsecase) AuthorizeSync(ctx context.Context, userExtID string, partyID int64) error {
+	party, err := u.findActiveParty(ctx, partyID)
+	if err != nil {
+		return err
+	}
+
+	member, err := u.repo.FindMember(ctx, partyID, userExtID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if member == nil || member.Status != watchparty.MemberJoined {
+		return apperr.Forbidden("join_party_first", nil)
+	}
+
+	return u.checkEntitlement(ctx, party, userExtID)
+}
+
+// EndParty closes a party (host only)
+func (u *WatchPartyUsecase) EndParty(ctx context.Context, hostExtID string, partyID int64) error {
+	party, err := u.findActiveParty(ctx, partyID)
+	if err != nil {
+		return err
+	}
+	if party.HostExtID != hostExtID {
+		return apperr.Forbidden("only_host_can_end_party", nil)
+	}
+
+	if err := u.repo.EndParty(ctx, partyID, time.Now()); err != nil {
+		return apperr.Internal(err)
+	}
+
+	return nil
+}
+
+// checkEntitlement requires the participant's own access in ENTITLED mode,
+// and the host's (still active) access in HOST_PAYS mode
+func (u *WatchPartyUsecase) checkEntitlement(ctx context.Context, party *watchparty.WatchParty, userExtID string) error {
+	accessHolder := userExtID
+	if party.Mode == watchparty.ModeHostPays {
+		accessHolder = party.HostExtID
+	}
+
+	hasAccess, err := u.repo.HasMovieAccess(ctx, accessHolder, party.MovieID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if !hasAccess {
+		return apperr.Forbidden("movie_access_required", nil)
+	}
+
+	return nil
+}
+
+func (u *WatchPartyUsecase) findParty(ctx context.Context, partyID int64) (*watchparty.WatchParty, error) {
+	party, err := u.repo.FindPartyByID(ctx, partyID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if party == nil {
+		return nil, apperr.NotFound("watch_party_not_found", nil)
+	}
+	return party, nil
+}
+
+func (u *WatchPartyUsecase) findActiveParty(ctx context.Context, partyID int64) (*watchparty.WatchParty, error) {
+	party, err := u.findParty(ctx, partyID)
+	if err != nil {
+		return nil, err
+	}
+	if party.Status != watchparty.StatusActive {
+		return nil, apperr.Conflict("watch_party_ended", nil)
+	}
+	return party, nil
+}
+
+func (u *WatchPartyUsecase) partyResponse(ctx context.Context, party *watchparty.WatchParty) (*watchparty.PartyResponse, error) {
+	members, err := u.repo.FindMembers(ctx, party.ID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	return &watchparty.PartyResponse{
+		ID:        party.ID,
+		HostExtID: party.HostExtID,
+		MovieID:   party.MovieID,
+		Mode:      party.Mode,
+		Status:    party.Status,
+		Members:   members,
+		CreatedAt: party.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package watchparty
+
+import "time"
+
+// Watch party access modes
+const (
+	ModeEntitled = "ENTITLED"  // every participant needs their own access to the movie
+	ModeHostPays = "HOST_PAYS" // the host's access covers invited guests
+)
+
+// Watch party statuses
+const (
+	StatusActive = "ACTIVE"
+	StatusEnded  = "ENDED"
+)
+
+// Member statuses
+const (
+	MemberInvited = "INVITED"
+	MemberJoined  = "JOINED"
+)
+
+// WatchParty represents a shared viewing session hosted by a user
+type WatchParty struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	HostExtID string     `json:"host_ext_id" gorm:"not null;column:host_ext_id"`
+	MovieID   int64      `json:"movie_id" gorm:"not null"`
+	Mode      string     `json:"mode" gorm:"type:enum('ENTITLED','HOST_PAYS');default:'ENTITLED'"`
+	Status    string     `json:"status" gorm:"type:enum('ACTIVE','ENDED');default:'ACTIVE'"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name for WatchParty
+func (WatchParty) TableName() string {
+	return "watch_parties"
+}
+
+// WatchPartyMember represents an invited or joined participant
+type WatchPartyMember struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	PartyID   int64      `json:"party_id" gorm:"not null"`
+	UserExtID string     `json:"user_ext_id" gorm:"not null;column:user_ext_id"`
+	Status    string     `json:"status" gorm:"type:enum('INVITED','JOINED');default:'INVITED'"`
+	JoinedAt  *time.Time `json:"joined_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName overrides the table name for WatchPartyMember
+func (WatchPartyMember) TableName() string {
+	return "watch_party_members"
+}
+
+// Request DTOs
+
+// CreatePartyRequest represents the request to host a watch party
+type CreatePartyRequest struct {
+	MovieID int64  `json:"movie_id" validate:"required,gt=0"`
+	Mode    string `json:"mode" validate:"omitempty,oneof=ENTITLED HOST_PAYS"`
+}
+
+// InviteRequest represents the request to invite a user to a party
+type InviteRequest struct {
+	UserExtID string `json:"user_ext_id" validate:"required"`
+}
+
+// Response DTOs
+
+// PartyResponse represents a watch party with its participants
+type PartyResponse struct {
+	ID        int64              `json:"id"`
+	HostExtID string             `json:"host_ext_id"`
+	MovieID   int64              `json:"movie_id"`
+	Mode      string             `json:"mode"`
+	Status    string             `json:"status"`
+	Members   []WatchPartyMember `json:"members"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// JoinPartyResponse is returned to a participant joining a party
+type JoinPartyResponse struct {
+	PartyID      int64  `json:"party_id"`
+	MovieID      int64  `json:"movie_id"`
+	HLSURL       string `json:"hls_url"`
+	WebSocketURL string `json:"websocket_url"`
+}
+
+// Sync channel
+
+// Sync message types exchanged over the party WebSocket
+const (
+	SyncPlay  = "play"
+	SyncPause = "pause"
+	SyncSeek  = "seek"
+	SyncState = "state" // sent by the server with the current playback state
+	SyncJoin  = "join"
+	SyncLeave = "leave"
+)
+
+// SyncMessage is a playback event synchronized across participants
+type SyncMessage struct {
+	Type      string  `json:"type"`
+	Position  float64 `json:"position"` // seconds
+	Playing   bool    `json:"playing"`
+	UserExtID string  `json:"user_ext_id,omitempty"`
+	SentAt    int64   `json:"sent_at,omitempty"` // server unix millis, lets clients compensate latency
+}
@@ -0,0 +1,129 @@
+package apidocs
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	appMiddleware "github.com/martinmanurung/cinestream/pkg/middleware"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// Route is a registered endpoint with what it requires of the caller
+type Route struct {
+	Method  string               `json:"method"`
+	Path    string               `json:"path"`
+	Handler string               `json:"handler"`
+	Access  appMiddleware.Access `json:"access"`
+}
+
+// Catalog records the routes Echo registers, so integrators can see which endpoints their credentials can call
+type Catalog struct {
+	echo   *echo.Echo
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewCatalog starts recording every route added to e afterwards, so it must be created before the routes are set up
+func NewCatalog(e *echo.Echo) *Catalog {
+	c := &Catalog{echo: e}
+	e.OnAddRouteHandler = c.record
+	return c
+}
+
+// record is the Echo.OnAddRouteHandler, group middleware are part of middleware
+func (c *Catalog) record(_ string, route echo.Route, _ echo.HandlerFunc, middleware []echo.MiddlewareFunc) {
+	// Catch-all routes Echo adds for group middleware are not endpoints
+	if route.Method == echo.RouteNotFound {
+		return
+	}
+
+	entry := Route{
+		Method:  route.Method,
+		Path:    route.Path,
+		Handler: handlerName(route.Name),
+		Access:  appMiddleware.DescribeRoute(c.echo, middleware),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.routes = append(c.routes, entry)
+}
+
+// Routes returns the routes sorted by path and method, all of them when all is set, otherwise those the role may call
+func (c *Catalog) Routes(role string, all bool) []Route {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	routes := make([]Route, 0, len(c.routes))
+	for _, route := range c.routes {
+		if all || route.Access.Allows(role) {
+			routes = append(routes, route)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// ListRoutes lists the registered routes with their required authentication, roles and permissions (Admin only)
+// GET /api/v1/admin/routes?role=CONTENT_MANAGER
+func (c *Catalog) ListRoutes(ctx echo.Context) error {
+	role, all, ok := roleFilter(ctx.QueryParam("role"))
+	if !ok {
+		return response.Error(ctx, http.StatusBadRequest, "invalid_role", "role must be ANONYMOUS or a known role")
+	}
+	return response.Success(ctx, http.StatusOK, "routes retrieved successfully", c.Routes(role, all))
+}
+
+// GetRoleOpenAPI returns the OpenAPI document of the routes a role may call (Admin only)
+// GET /api/v1/admin/routes/openapi.json?role=USER
+func (c *Catalog) GetRoleOpenAPI(ctx echo.Context) error {
+	role, all, ok := roleFilter(ctx.QueryParam("role"))
+	if !ok {
+		return response.Error(ctx, http.StatusBadRequest, "invalid_role", "role must be ANONYMOUS or a known role")
+	}
+	return ctx.JSON(http.StatusOK, c.OpenAPI(role, all))
+}
+
+// GetOwnOpenAPI returns the OpenAPI document of the routes the caller's credentials can call,
+// anonymous callers only see public routes
+// GET /api/v1/openapi.json
+func (c *Catalog) GetOwnOpenAPI(ctx echo.Context) error {
+	role, _ := ctx.Get(string(constant.CtxKeyUserRole)).(string)
+	return ctx.JSON(http.StatusOK, c.OpenAPI(role, false))
+}
+
+// roleFilter parses the role query param, ANONYMOUS selects the public routes and no role selects all routes
+func roleFilter(param string) (role string, all bool, ok bool) {
+	role = strings.ToUpper(strings.TrimSpace(param))
+	switch {
+	case role == "":
+		return "", true, true
+	case role == "ANONYMOUS":
+		return "", false, true
+	case constant.IsValidRole(role):
+		return role, false, true
+	}
+	return "", false, false
+}
+
+// handlerName shortens the function name Echo records, e.g.
+// github.com/.../delivery.(*MovieHandler).GetMovieList-fm becomes MovieHandler.GetMovieList
+func handlerName(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
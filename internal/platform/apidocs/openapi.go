@@ -0,0 +1,92 @@
+package apidocs
+
+import (
+	"regexp"
+	"strings"
+
+	appMiddleware "github.com/martinmanurung/cinestream/pkg/middleware"
+)
+
+// pathParam matches Echo path params (:id) and wildcards (*)
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)|\*`)
+
+// OpenAPI builds an OpenAPI 3 document of the routes, see Routes for role and all.
+// Only the routes and their authentication are described, request and response bodies are not.
+func (c *Catalog) OpenAPI(role string, all bool) map[string]interface{} {
+	paths := map[string]map[string]interface{}{}
+	for _, route := range c.Routes(role, all) {
+		path, params := openAPIPath(route.Path)
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+
+		operation := map[string]interface{}{
+			"operationId": route.Handler + "_" + strings.ToLower(route.Method),
+			"responses": map[string]interface{}{
+				"default": map[string]interface{}{"description": "JSON envelope with status, code, message and data or errors"},
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		switch route.Access.Auth {
+		case appMiddleware.AuthJWT:
+			operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+		case appMiddleware.AuthOptionalJWT:
+			operation["security"] = []map[string][]string{{}, {"bearerAuth": {}}}
+		}
+		if len(route.Access.Roles) > 0 {
+			operation["x-roles"] = route.Access.Roles
+		}
+		if len(route.Access.Permissions) > 0 {
+			operation["x-permissions"] = route.Access.Permissions
+		}
+		paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	title := "CineStream API"
+	switch {
+	case all:
+	case role == "":
+		title += " (anonymous)"
+	default:
+		title += " (" + role + ")"
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+	}
+}
+
+// openAPIPath turns /movies/:id/hls/* into /movies/{id}/hls/{path} with its path parameters
+func openAPIPath(path string) (string, []map[string]interface{}) {
+	var params []map[string]interface{}
+	converted := pathParam.ReplaceAllStringFunc(path, func(match string) string {
+		name := strings.TrimPrefix(match, ":")
+		if match == "*" {
+			name = "path"
+		}
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]string{"type": "string"},
+		})
+		return "{" + name + "}"
+	})
+	return converted, params
+}
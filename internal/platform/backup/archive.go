@@ -0,0 +1,325 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// Entries of an archive, a gzipped tar
+const (
+	tablesDir     = "tables/"       // tables/<name>.jsonl: a header line with the columns, then a JSON array per row
+	objectsEntry  = "objects.jsonl" // a line per object of the snapshotted buckets
+	manifestEntry = "manifest.json" // written last, what the archive holds
+	archiveFormat = 1
+)
+
+// How column values are stored, binary values are base64 and times RFC 3339
+const (
+	kindValue  = "value"
+	kindBinary = "binary"
+	kindTime   = "time"
+)
+
+// Manifest describes an archive
+type Manifest struct {
+	Format    int          `json:"format"`
+	CreatedAt time.Time    `json:"created_at"`
+	Tables    []TableCount `json:"tables"`
+	Objects   int64        `json:"objects"` // objects in the snapshot of the buckets
+}
+
+// TableCount is the number of rows of a table in an archive
+type TableCount struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// tableHeader is the first line of a table entry
+type tableHeader struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	Kinds   []string `json:"kinds"`
+}
+
+// ObjectEntry is the metadata of a stored object, enough to check a restored catalog against the buckets
+type ObjectEntry struct {
+	Bucket       string    `json:"bucket"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// writeTables dumps the tables from one consistent snapshot of the database
+func writeTables(ctx context.Context, db *gorm.DB, tw *tar.Writer, tables []string) ([]TableCount, error) {
+	counts := make([]TableCount, 0, len(tables))
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			var rows int64
+			err := writeSpooled(tw, tablesDir+table+".jsonl", func(w io.Writer) error {
+				var err error
+				rows, err = dumpTable(tx, table, w)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to dump table %s: %w", table, err)
+			}
+			counts = append(counts, TableCount{Name: table, Rows: rows})
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	return counts, err
+}
+
+func dumpTable(tx *gorm.DB, table string, w io.Writer) (int64, error) {
+	rows, err := tx.Table(table).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+	header := tableHeader{Table: table, Columns: make([]string, len(columnTypes)), Kinds: make([]string, len(columnTypes))}
+	for i, columnType := range columnTypes {
+		header.Columns[i] = columnType.Name()
+		header.Kinds[i] = columnKind(columnType.DatabaseTypeName())
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(header); err != nil {
+		return 0, err
+	}
+
+	values := make([]interface{}, len(columnTypes))
+	targets := make([]interface{}, len(columnTypes))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return count, err
+		}
+		row := make([]interface{}, len(values))
+		for i, value := range values {
+			row[i] = encodeValue(value, header.Kinds[i])
+		}
+		if err := encoder.Encode(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+func columnKind(databaseType string) string {
+	switch {
+	case databaseType == "DATETIME" || databaseType == "TIMESTAMP" || databaseType == "DATE":
+		return kindTime
+	case strings.Contains(databaseType, "BLOB") || strings.Contains(databaseType, "BINARY"):
+		return kindBinary
+	default:
+		return kindValue
+	}
+}
+
+func encodeValue(value interface{}, kind string) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		if kind == kindBinary {
+			return base64.StdEncoding.EncodeToString(v)
+		}
+		return string(v)
+	default:
+		return v
+	}
+}
+
+func decodeValue(value interface{}, kind string) (interface{}, error) {
+	text, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	switch kind {
+	case kindTime:
+		return time.Parse(time.RFC3339Nano, text)
+	case kindBinary:
+		return base64.StdEncoding.DecodeString(text)
+	default:
+		return text, nil
+	}
+}
+
+// writeObjects snapshots the metadata of every object in the buckets
+func writeObjects(ctx context.Context, client *minio.Client, tw *tar.Writer, buckets []string) (int64, error) {
+	var count int64
+	err := writeSpooled(tw, objectsEntry, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, bucket := range buckets {
+			for object := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true}) {
+				if object.Err != nil {
+					return fmt.Errorf("failed to list bucket %s: %w", bucket, object.Err)
+				}
+				err := encoder.Encode(ObjectEntry{
+					Bucket:       bucket,
+					Key:          object.Key,
+					Size:         object.Size,
+					ETag:         object.ETag,
+					LastModified: object.LastModified,
+				})
+				if err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// writeSpooled adds an entry whose size is only known once written, it is spooled to a temporary file first
+// so large tables are not held in memory
+func writeSpooled(tw *tar.Writer, name string, write func(w io.Writer) error) error {
+	spool, err := os.CreateTemp("", "cinestream-backup-entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	buffered := bufio.NewWriter(spool)
+	if err := write(buffered); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	header := &tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: time.Now()}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, spool); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), ModTime: time.Now()}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// restoreTable replaces the rows of a table with the rows of its entry.
+// It runs in the restore transaction with foreign key checks off, so the tables can be restored in any order.
+func restoreTable(tx *gorm.DB, r io.Reader, batchSize int) (TableCount, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var header tableHeader
+	if err := decoder.Decode(&header); err != nil {
+		return TableCount{}, fmt.Errorf("failed to read table header: %w", err)
+	}
+	if header.Table == "" || len(header.Columns) == 0 || len(header.Kinds) != len(header.Columns) {
+		return TableCount{}, errors.New("invalid table header")
+	}
+	count := TableCount{Name: header.Table}
+
+	if err := tx.Exec("DELETE FROM " + quoteName(header.Table)).Error; err != nil {
+		return count, fmt.Errorf("failed to clear table %s: %w", header.Table, err)
+	}
+
+	quoted := make([]string, len(header.Columns))
+	for i, column := range header.Columns {
+		quoted[i] = quoteName(column)
+	}
+	insert := "INSERT INTO " + quoteName(header.Table) + " (" + strings.Join(quoted, ", ") + ") VALUES "
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(header.Columns)), ", ") + ")"
+
+	// MySQL takes at most 65535 placeholders per statement
+	if limit := 60000 / len(header.Columns); batchSize > limit {
+		batchSize = limit
+	}
+	batch := make([]string, 0, batchSize)
+	args := make([]interface{}, 0, batchSize*len(header.Columns))
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := tx.Exec(insert+strings.Join(batch, ", "), args...).Error; err != nil {
+			return fmt.Errorf("failed to restore table %s: %w", header.Table, err)
+		}
+		count.Rows += int64(len(batch))
+		batch, args = batch[:0], args[:0]
+		return nil
+	}
+
+	for decoder.More() {
+		var row []interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return count, fmt.Errorf("failed to read a row of table %s: %w", header.Table, err)
+		}
+		if len(row) != len(header.Columns) {
+			return count, fmt.Errorf("row of table %s has %d values, expected %d", header.Table, len(row), len(header.Columns))
+		}
+		for i, value := range row {
+			decoded, err := decodeValue(value, header.Kinds[i])
+			if err != nil {
+				return count, fmt.Errorf("invalid value of %s.%s: %w", header.Table, header.Columns[i], err)
+			}
+			args = append(args, decoded)
+		}
+		batch = append(batch, placeholders)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, flush()
+}
+
+// tableName returns the table of a tar entry, empty for the other entries
+func tableName(entry string) string {
+	if !strings.HasPrefix(entry, tablesDir) || path.Ext(entry) != ".jsonl" {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(entry, tablesDir), ".jsonl")
+}
+
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,348 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// What started a backup
+const (
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+// Outcome of one backup
+const (
+	StatusOK     = "OK"
+	StatusFailed = "FAILED"
+)
+
+const (
+	// Archives are stored under this prefix of the backup bucket, named by their start time
+	objectPrefix = "backups/"
+	objectLayout = "20060102T150405Z"
+	objectSuffix = ".tar.gz.enc"
+
+	// Retention never removes the newest archives, e.g. when the recent backups failed
+	keepNewest = 3
+
+	restoreBatchSize = 500
+)
+
+// DefaultTables are the tables a backup dumps unless configured otherwise:
+// accounts, the catalog, purchases and access, and what finance and rights holders are owed
+var DefaultTables = []string{
+	"users",
+	"movies", "movie_videos", "movie_renditions", "movie_audio_tracks", "movie_alternate_audio", "subtitles",
+	"genres", "movie_genres", "people", "movie_license_windows", "movie_content_keys",
+	"orders", "order_refunds", "invoices", "invoice_sequences", "user_movie_access",
+	"access_grants", "access_grant_recipients", "partner_api_keys",
+	"rights_holders", "rights_holder_members", "rights_holder_titles",
+}
+
+// Run is the outcome of one backup, kept for the ops dashboard
+type Run struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Trigger    string    `json:"trigger" gorm:"type:varchar(20);not null"`
+	Object     *string   `json:"object,omitempty" gorm:"type:varchar(255)"` // key of the archive in the backup bucket
+	Tables     int       `json:"tables" gorm:"not null"`
+	Rows       int64     `json:"rows" gorm:"not null"`
+	Objects    int64     `json:"objects" gorm:"not null"`    // objects in the snapshot of the buckets
+	SizeBytes  int64     `json:"size_bytes" gorm:"not null"` // size of the encrypted archive
+	Pruned     int       `json:"pruned" gorm:"not null"`     // archives removed by retention after the backup
+	Status     string    `json:"status" gorm:"type:varchar(20);not null"`
+	Error      *string   `json:"error,omitempty" gorm:"type:varchar(500)"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null"`
+	FinishedAt time.Time `json:"finished_at" gorm:"not null"`
+}
+
+// TableName overrides the table name for Run
+func (Run) TableName() string {
+	return "backup_runs"
+}
+
+// Status is what the ops dashboard shows of the backups
+type Status struct {
+	LastSuccess *Run  `json:"last_success"` // nil until a backup succeeded
+	Runs        []Run `json:"runs"`         // latest first, failed ones included
+}
+
+// Options configures an engine
+type Options struct {
+	Bucket        string        // bucket the archives are written to
+	Key           []byte        // AES-256 key the archives are encrypted with
+	Tables        []string      // tables to dump, DefaultTables when empty
+	ObjectBuckets []string      // buckets whose object metadata is snapshotted
+	Retention     time.Duration // archives older than this are removed, 0 keeps them
+}
+
+// Engine writes encrypted archives of the database and the stored objects to the backup bucket and restores them
+type Engine struct {
+	db     *gorm.DB
+	client *minio.Client
+	opts   Options
+}
+
+// NewEngine creates an engine, it fails without a bucket or with a key that is not 32 bytes
+func NewEngine(db *gorm.DB, client *minio.Client, opts Options) (*Engine, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("backup bucket is not configured")
+	}
+	if len(opts.Key) != 32 {
+		return nil, errors.New("backup encryption key must be 32 bytes")
+	}
+	if len(opts.Tables) == 0 {
+		opts.Tables = DefaultTables
+	}
+	// The archived rows are kept out of the SQL log
+	db = db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+	return &Engine{db: db, client: client, opts: opts}, nil
+}
+
+// Run writes an archive, removes the archives past retention and stores the outcome.
+// The run is returned also when storing it failed, the error is about storing.
+func (e *Engine) Run(ctx context.Context, trigger string) (Run, error) {
+	run := Run{Trigger: trigger, Status: StatusOK, StartedAt: time.Now()}
+
+	err := e.backup(ctx, &run)
+	if err == nil && e.opts.Retention > 0 {
+		run.Pruned, err = e.prune(ctx, time.Now().Add(-e.opts.Retention))
+		if err != nil {
+			err = fmt.Errorf("backup written, retention failed: %w", err)
+		}
+	}
+	if err != nil {
+		message := err.Error()
+		if len(message) > 500 {
+			message = message[:500]
+		}
+		run.Status = StatusFailed
+		run.Error = &message
+	}
+
+	run.FinishedAt = time.Now()
+	if err := e.db.WithContext(ctx).Create(&run).Error; err != nil {
+		return run, fmt.Errorf("failed to save backup run: %w", err)
+	}
+	return run, nil
+}
+
+// backup builds the archive in a temporary file and uploads it
+func (e *Engine) backup(ctx context.Context, run *Run) error {
+	file, err := os.CreateTemp("", "cinestream-backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	manifest, err := e.writeArchive(ctx, file)
+	if err != nil {
+		return err
+	}
+	run.Tables = len(manifest.Tables)
+	for _, table := range manifest.Tables {
+		run.Rows += table.Rows
+	}
+	run.Objects = manifest.Objects
+
+	if run.SizeBytes, err = file.Seek(0, io.SeekCurrent); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	object := objectPrefix + run.StartedAt.UTC().Format(objectLayout) + objectSuffix
+	_, err = e.client.PutObject(ctx, e.opts.Bucket, object, file, run.SizeBytes, minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	if err != nil {
+		return fmt.Errorf("failed to upload backup: %w", err)
+	}
+	run.Object = &object
+	return nil
+}
+
+// writeArchive writes the tables, the object snapshot and the manifest as an encrypted gzipped tar
+func (e *Engine) writeArchive(ctx context.Context, w io.Writer) (*Manifest, error) {
+	encrypted, err := newEncryptWriter(w, e.opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	compressed := gzip.NewWriter(encrypted)
+	tw := tar.NewWriter(compressed)
+
+	manifest := &Manifest{Format: archiveFormat, CreatedAt: time.Now().UTC()}
+	if manifest.Tables, err = writeTables(ctx, e.db, tw, e.opts.Tables); err != nil {
+		return nil, err
+	}
+	if manifest.Objects, err = writeObjects(ctx, e.client, tw, e.opts.ObjectBuckets); err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestEntry, content); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := compressed.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, encrypted.Close()
+}
+
+// prune removes the archives last modified before the cutoff, except the newest ones
+func (e *Engine) prune(ctx context.Context, cutoff time.Time) (int, error) {
+	var archives []minio.ObjectInfo
+	for object := range e.client.ListObjects(ctx, e.opts.Bucket, minio.ListObjectsOptions{Prefix: objectPrefix, Recursive: true}) {
+		if object.Err != nil {
+			return 0, object.Err
+		}
+		if strings.HasSuffix(object.Key, objectSuffix) {
+			archives = append(archives, object)
+		}
+	}
+	sort.Slice(archives, func(i, j int) bool { return archives[i].LastModified.After(archives[j].LastModified) })
+
+	pruned := 0
+	for i, archive := range archives {
+		if i < keepNewest || !archive.LastModified.Before(cutoff) {
+			continue
+		}
+		if err := e.client.RemoveObject(ctx, e.opts.Bucket, archive.Key, minio.RemoveObjectOptions{}); err != nil {
+			return pruned, fmt.Errorf("failed to remove %s: %w", archive.Key, err)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// Inspect reads an archive through, checking that it decrypts, and returns its manifest
+func (e *Engine) Inspect(ctx context.Context, object string) (*Manifest, error) {
+	var manifest *Manifest
+	err := e.readArchive(ctx, object, func(name string, r io.Reader) error {
+		if name != manifestEntry {
+			return nil
+		}
+		manifest = &Manifest{}
+		return json.NewDecoder(r).Decode(manifest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, errors.New("backup archive has no manifest")
+	}
+	return manifest, nil
+}
+
+// Restore replaces the rows of every table in the archive with the archived rows, in one transaction.
+// The schema has to be migrated first, tables of the database that are not in the archive are left alone.
+// Nothing should write to the database meanwhile, the API and the workers are best stopped.
+func (e *Engine) Restore(ctx context.Context, object string) ([]TableCount, error) {
+	var restored []TableCount
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		// The connection goes back to the pool after the transaction
+		defer tx.Exec("SET FOREIGN_KEY_CHECKS = 1")
+
+		return e.readArchive(ctx, object, func(name string, r io.Reader) error {
+			if tableName(name) == "" {
+				return nil
+			}
+			count, err := restoreTable(tx, r, restoreBatchSize)
+			if err != nil {
+				return err
+			}
+			restored = append(restored, count)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// readArchive downloads, decrypts and unpacks an archive, handing each entry to read
+func (e *Engine) readArchive(ctx context.Context, object string, read func(name string, r io.Reader) error) error {
+	reader, err := e.client.GetObject(ctx, e.opts.Bucket, object, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+	defer reader.Close()
+
+	decrypted, err := newDecryptReader(reader, e.opts.Key)
+	if err != nil {
+		return err
+	}
+	decompressed, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	tr := tar.NewReader(decompressed)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup: %w", err)
+		}
+		if err := read(header.Name, tr); err != nil {
+			return err
+		}
+	}
+	// Reads to the end, so a truncated archive fails
+	_, err = io.Copy(io.Discard, decrypted)
+	return err
+}
+
+// Store reads the stored runs
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore creates a run store
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// FindRuns returns the latest runs, newest first
+func (s *Store) FindRuns(ctx context.Context, limit int) ([]Run, error) {
+	var runs []Run
+	err := s.db.WithContext(ctx).Order("started_at DESC, id DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// FindLastSuccess returns the latest successful run, nil when no backup succeeded yet
+func (s *Store) FindLastSuccess(ctx context.Context) (*Run, error) {
+	var run Run
+	err := s.db.WithContext(ctx).Where("status = ?", StatusOK).Order("started_at DESC, id DESC").First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
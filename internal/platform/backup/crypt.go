@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Archives are sealed in segments with AES-256-GCM, so they are encrypted and checked while streaming.
+// An archive starts with the magic and a random nonce prefix, each segment is its ciphertext length and the ciphertext.
+// The nonce of a segment is the prefix and the segment number, the last segment is marked in its additional data,
+// so reordered, dropped or truncated segments fail to open.
+const (
+	archiveMagic = "CSBK\x01"
+	segmentSize  = 64 * 1024
+	prefixSize   = 8
+)
+
+var errTruncated = errors.New("backup archive is truncated")
+
+// ParseKey decodes a base64 encryption key, keys are 32 bytes
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("backup encryption key is not base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("backup encryption key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptWriter seals what is written to it segment by segment, Close seals the last segment
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix [prefixSize]byte
+	seq    uint32
+	buf    []byte
+}
+
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &encryptWriter{w: w, aead: aead, buf: make([]byte, 0, segmentSize)}
+	if _, err := rand.Read(e.prefix[:]); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, archiveMagic); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(e.prefix[:]); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buf[len(e.buf):segmentSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+		if len(e.buf) == segmentSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	sealed := e.aead.Seal(nil, segmentNonce(e.prefix, e.seq), e.buf, segmentData(last))
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.seq++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// decryptReader opens the segments of an archive, it fails when the archive ends before its last segment
+type decryptReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	prefix [prefixSize]byte
+	seq    uint32
+	plain  []byte
+	done   bool
+}
+
+func newDecryptReader(r io.Reader, key []byte) (*decryptReader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	d := &decryptReader{r: bufio.NewReader(r), aead: aead}
+	magic := make([]byte, len(archiveMagic))
+	if _, err := io.ReadFull(d.r, magic); err != nil || string(magic) != archiveMagic {
+		return nil, errors.New("not a backup archive")
+	}
+	if _, err := io.ReadFull(d.r, d.prefix[:]); err != nil {
+		return nil, errTruncated
+	}
+	return d, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		return errTruncated
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > segmentSize+uint32(d.aead.Overhead()) {
+		return errors.New("backup archive is corrupt")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return errTruncated
+	}
+
+	nonce := segmentNonce(d.prefix, d.seq)
+	plain, err := d.aead.Open(nil, nonce, sealed, segmentData(false))
+	if err != nil {
+		if plain, err = d.aead.Open(nil, nonce, sealed, segmentData(true)); err != nil {
+			return errors.New("backup archive cannot be decrypted, wrong key or corrupt archive")
+		}
+		d.done = true
+	}
+	d.seq++
+	d.plain = plain
+	return nil
+}
+
+func segmentNonce(prefix [prefixSize]byte, seq uint32) []byte {
+	nonce := make([]byte, prefixSize+4)
+	copy(nonce, prefix[:])
+	binary.BigEndian.PutUint32(nonce[prefixSize:], seq)
+	return nonce
+}
+
+func segmentData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
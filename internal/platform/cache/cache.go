@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const keyPrefix = "cache"
+
+// Cache is a cache-aside store in Redis for the read models of one namespace, e.g. the catalog.
+// Values are gob encoded, so fields hidden from JSON responses survive the round trip.
+// Invalidate drops every entry of the namespace at once by starting a new generation,
+// entries of older generations are never read again and expire with their TTL.
+//
+// Redis trouble never fails a request: a failed read is a miss and a failed write is only logged.
+type Cache struct {
+	client    *redis.Client
+	namespace string
+	ttl       time.Duration
+}
+
+// New creates a cache for the namespace, entries expire after ttl
+func New(client *redis.Client, namespace string, ttl time.Duration) *Cache {
+	return &Cache{client: client, namespace: namespace, ttl: ttl}
+}
+
+// Get decodes the entry of key into dest, false on a miss
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) bool {
+	entryKey, err := c.entryKey(ctx, key)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("cache", c.namespace).Msg("Cache unavailable")
+		return false
+	}
+
+	data, err := c.client.Get(ctx, entryKey).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("cache", c.namespace).Msg("Cache read failed")
+		}
+		return false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(dest); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("cache", c.namespace).Str("key", key).Msg("Dropping undecodable cache entry")
+		c.client.Del(ctx, entryKey)
+		return false
+	}
+	return true
+}
+
+// Set stores value under key in the current generation
+func (c *Cache) Set(ctx context.Context, key string, value interface{}) {
+	entryKey, err := c.entryKey(ctx, key)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("cache", c.namespace).Msg("Cache unavailable")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("cache", c.namespace).Str("key", key).Msg("Failed to encode cache entry")
+		return
+	}
+	if err := c.client.Set(ctx, entryKey, buf.Bytes(), c.ttl).Err(); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("cache", c.namespace).Msg("Cache write failed")
+	}
+}
+
+// Invalidate drops every entry of the namespace
+func (c *Cache) Invalidate(ctx context.Context) error {
+	if err := c.client.Incr(ctx, c.generationKey()).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate %s cache: %w", c.namespace, err)
+	}
+	return nil
+}
+
+func (c *Cache) generationKey() string {
+	return keyPrefix + ":" + c.namespace + ":generation"
+}
+
+// entryKey returns the key of an entry in the current generation, a missing generation counter reads as 0
+func (c *Cache) entryKey(ctx context.Context, key string) (string, error) {
+	generation, err := c.client.Get(ctx, c.generationKey()).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s:%d:%s", keyPrefix, c.namespace, generation, key), nil
+}
@@ -0,0 +1,116 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// Supported providers, both share the siteverify API
+const (
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+var defaultVerifyURLs = map[string]string{
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// Verifier checks CAPTCHA tokens solved by the client against the provider
+type Verifier struct {
+	provider  string
+	siteKey   string
+	secretKey string
+	verifyURL string
+	client    *http.Client
+}
+
+// New returns a CAPTCHA verifier, nil when no provider is configured
+func New(cfg config.CaptchaConfig) (*Verifier, error) {
+	provider := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if provider == "" {
+		return nil, nil
+	}
+
+	verifyURL := cfg.VerifyURL
+	if verifyURL == "" {
+		var ok bool
+		if verifyURL, ok = defaultVerifyURLs[provider]; !ok {
+			return nil, fmt.Errorf("unknown captcha provider %q", cfg.Provider)
+		}
+	}
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("captcha provider %q needs a secret key", provider)
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &Verifier{
+		provider:  provider,
+		siteKey:   cfg.SiteKey,
+		secretKey: cfg.SecretKey,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Provider returns the configured provider, the frontend renders its widget
+func (v *Verifier) Provider() string {
+	return v.provider
+}
+
+// SiteKey returns the public key the frontend renders the widget with
+func (v *Verifier) SiteKey() string {
+	return v.siteKey
+}
+
+// verifyResponse is the siteverify answer of hCaptcha and Turnstile
+type verifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Verify reports whether the token was solved, remoteIP is passed on as an additional signal
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{}
+	form.Set("secret", v.secretKey)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	if v.siteKey != "" && v.provider == ProviderHCaptcha {
+		form.Set("sitekey", v.siteKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to call %s: %w", v.provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%s returned status %d", v.provider, resp.StatusCode)
+	}
+
+	var result verifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to decode %s response: %w", v.provider, err)
+	}
+	return result.Success, nil
+}
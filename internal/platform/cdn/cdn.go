@@ -0,0 +1,74 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Purger removes cached API responses so catalog edits are visible before the TTL runs out.
+// A path ending in "*" is a prefix, e.g. "/api/v1/movies*" also covers paginated and filtered lists.
+type Purger interface {
+	Purge(ctx context.Context, paths []string) error
+}
+
+// HTTPPurger calls a purge endpoint with {"paths": [...]} and a bearer token
+type HTTPPurger struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewPurger returns an HTTP purger, or a purger that only logs when no purge URL is configured
+func NewPurger(url, token string) Purger {
+	if url == "" {
+		return &logPurger{}
+	}
+
+	return &HTTPPurger{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Purge invalidates the paths on the CDN
+func (p *HTTPPurger) Purge(ctx context.Context, paths []string) error {
+	body, err := json.Marshal(map[string][]string{"paths": paths})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call purge endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("purge endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// logPurger is used in development when no CDN is in front of the API
+type logPurger struct{}
+
+func (p *logPurger) Purge(ctx context.Context, paths []string) error {
+	zerolog.Ctx(ctx).Info().Strs("paths", paths).Msg("CDN purge not sent, purge URL not configured")
+	return nil
+}
@@ -2,19 +2,64 @@ package config
 
 // Config adalah struct utama yang menampung semua konfigurasi
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Redis     RedisConfig     `mapstructure:"redis"`
-	Queue     QueueConfig     `mapstructure:"queue"`
-	MinIO     MinIOConfig     `mapstructure:"minio"`
-	JWT       JWTConfig       `mapstructure:"jwt"`
-	PaymentGW PaymentGWConfig `mapstructure:"payment_gateway"`
+	Server        ServerConfig       `mapstructure:"server"`
+	Database      DatabaseConfig     `mapstructure:"database"`
+	Redis         RedisConfig        `mapstructure:"redis"`
+	Queue         QueueConfig        `mapstructure:"queue"`
+	MinIO         MinIOConfig        `mapstructure:"minio"`
+	JWT           JWTConfig          `mapstructure:"jwt"`
+	PaymentGW     PaymentGWConfig    `mapstructure:"payment_gateway"`
+	Media         MediaConfig        `mapstructure:"media"`
+	Streaming     StreamingConfig    `mapstructure:"streaming"`
+	Orders        OrdersConfig       `mapstructure:"orders"`
+	Licensing     LicensingConfig    `mapstructure:"licensing"`
+	Mail          MailConfig         `mapstructure:"mail"`
+	Invoices      InvoiceConfig      `mapstructure:"invoices"`
+	Royalties     RoyaltyConfig      `mapstructure:"royalties"`
+	Notifications NotificationConfig `mapstructure:"notifications"`
+	Transcoding   TranscodingConfig  `mapstructure:"transcoding"`
+	SpeechToText  SpeechToTextConfig `mapstructure:"speech_to_text"`
+	TMDB          TMDBConfig         `mapstructure:"tmdb"`
+	SEO           SEOConfig          `mapstructure:"seo"`
+	Moderation    ModerationConfig   `mapstructure:"moderation"`
+	Login         LoginConfig        `mapstructure:"login"`
+	Passwords     PasswordsConfig    `mapstructure:"passwords"`
+	Views         ViewsConfig        `mapstructure:"views"`
+	CDN           CDNConfig          `mapstructure:"cdn"`
+	Retention     RetentionConfig    `mapstructure:"retention"`
+	Backup        BackupConfig       `mapstructure:"backup"`
+	Cleanup       CleanupConfig      `mapstructure:"cleanup"`
+	Popularity    PopularityConfig   `mapstructure:"popularity"`
+	Cache         CacheConfig        `mapstructure:"cache"`
+	Maintenance   MaintenanceConfig  `mapstructure:"maintenance"`
+
+	Log    LogConfig    `mapstructure:"log"`
+	Health HealthConfig `mapstructure:"health"`
+
+	File string `mapstructure:"-"` // config file that was read, empty when configured by environment only
 }
 
+// ServerConfig is the HTTP server of the API.
+// On shutdown /ready fails for DrainDelay before the listener closes, in-flight requests then get
+// up to ShutdownTimeout to finish. Durations are strings, terminationGracePeriodSeconds has to cover both.
 type ServerConfig struct {
-	Port         string `mapstructure:"port"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`
-	WriteTimeout int    `mapstructure:"write_timeout"`
+	Port            string `mapstructure:"port"`
+	ReadTimeout     int    `mapstructure:"read_timeout"`
+	WriteTimeout    int    `mapstructure:"write_timeout"`
+	DrainDelay      string `mapstructure:"drain_delay"`
+	ShutdownTimeout string `mapstructure:"shutdown_timeout"`
+
+	// development, staging or production, development registers testing endpoints such as simulated payments
+	Environment string `mapstructure:"environment"`
+
+	// CIDR ranges of the load balancers in front of the API, only they may set X-Forwarded-For and the region header.
+	// Empty uses the address of the connection as the client address.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// Development reports whether the API runs in a development environment
+func (s ServerConfig) Development() bool {
+	return s.Environment == "development"
 }
 
 type DatabaseConfig struct {
@@ -34,11 +79,22 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// QueueConfig controls the transcoding job stream. A job whose worker sent no heartbeat for ClaimIdle,
+// a duration string, is taken over by another worker, at most MaxRetries times.
+// Chunk, audio and notification jobs go through named queues: Routes moves a job type to another queue,
+// Consumers sets how many jobs of a queue one worker runs at once, 0 leaves the queue to other workers.
+// Jobs of named queues may be delayed and retried with a backoff, see queue.Enqueue.
 type QueueConfig struct {
-	Name       string `mapstructure:"name"`
-	MaxRetries int    `mapstructure:"max_retries"`
+	Name       string            `mapstructure:"name"`
+	MaxRetries int               `mapstructure:"max_retries"`
+	ClaimIdle  string            `mapstructure:"claim_idle"`
+	Routes     map[string]string `mapstructure:"routes"`
+	Consumers  map[string]int    `mapstructure:"consumers"`
 }
 
+// MinIOConfig points at the object storage. With ManageBuckets the services create missing buckets
+// and make the processed bucket public-read at startup. Scoped keys often may not do that, without
+// ManageBuckets the buckets and policies are set up elsewhere and only object access is probed.
 type MinIOConfig struct {
 	Endpoint        string `mapstructure:"endpoint"`
 	AccessKeyID     string `mapstructure:"access_key_id"`
@@ -46,16 +102,351 @@ type MinIOConfig struct {
 	UseSSL          bool   `mapstructure:"use_ssl"`
 	BucketRaw       string `mapstructure:"bucket_raw"`
 	BucketProcessed string `mapstructure:"bucket_processed"`
+	BucketMedia     string `mapstructure:"bucket_media"`
+	ManageBuckets   bool   `mapstructure:"manage_buckets"`
 }
 
+// JWTConfig controls the access and refresh tokens. Durations are strings.
+// A refresh token stops working RefreshTokenExpiry after login, or earlier once it was not used
+// for RefreshTokenIdleExpiry. The worker deletes such tokens every RefreshTokenPurgeInterval.
 type JWTConfig struct {
-	SecretKey          string `mapstructure:"secret_key"`
-	AccessTokenExpiry  string `mapstructure:"access_token_expiry"`
-	RefreshTokenExpiry string `mapstructure:"refresh_token_expiry"`
+	SecretKey                 string `mapstructure:"secret_key"`
+	AccessTokenExpiry         string `mapstructure:"access_token_expiry"`
+	RefreshTokenExpiry        string `mapstructure:"refresh_token_expiry"`
+	RefreshTokenIdleExpiry    string `mapstructure:"refresh_token_idle_expiry"`
+	RefreshTokenPurgeInterval string `mapstructure:"refresh_token_purge_interval"`
 }
 
+// PaymentGWConfig selects the payment provider used for new orders.
+// Providers are "midtrans" (server_key/client_key) and "stripe" (stripe section).
 type PaymentGWConfig struct {
-	ServerKey    string `mapstructure:"server_key"`
-	ClientKey    string `mapstructure:"client_key"`
-	IsProduction bool   `mapstructure:"is_production"`
+	Provider     string       `mapstructure:"provider"`
+	ServerKey    string       `mapstructure:"server_key"`
+	ClientKey    string       `mapstructure:"client_key"`
+	IsProduction bool         `mapstructure:"is_production"`
+	Stripe       StripeConfig `mapstructure:"stripe"`
+}
+
+// StripeConfig configures Stripe Checkout, it is only registered when secret_key is set
+type StripeConfig struct {
+	SecretKey     string `mapstructure:"secret_key"`
+	WebhookSecret string `mapstructure:"webhook_secret"`
+	Currency      string `mapstructure:"currency"`
+	SuccessURL    string `mapstructure:"success_url"`
+	CancelURL     string `mapstructure:"cancel_url"`
+}
+
+// MediaConfig controls how poster/trailer objects stored in the private media bucket are served.
+// Modes are "presigned" (short-lived MinIO URL) or "proxy" (served through the API).
+type MediaConfig struct {
+	PublicMode    string `mapstructure:"public_mode"`
+	PrivateMode   string `mapstructure:"private_mode"`
+	PresignExpiry string `mapstructure:"presign_expiry"`
+	ProxyBaseURL  string `mapstructure:"proxy_base_url"`
+	SigningKey    string `mapstructure:"signing_key"`
+}
+
+// StreamingConfig controls the HLS streaming proxy.
+// Rates are in kilobytes per second per stream session, 0 means unlimited.
+// MaxConcurrentStreams is the number of devices a user can stream on at once, 0 means unlimited.
+// SessionIdleTimeout is a duration string, a device without HLS requests for that long frees its slot.
+// RentalDevices is the number of distinct devices one rental can be streamed on, per plan in PlanRentalDevices.
+type StreamingConfig struct {
+	ThrottleEnabled      bool           `mapstructure:"throttle_enabled"`
+	DefaultRateKBps      int            `mapstructure:"default_rate_kbps"`
+	PlanRatesKBps        map[string]int `mapstructure:"plan_rates_kbps"`
+	MaxConcurrentStreams int            `mapstructure:"max_concurrent_streams"`
+	SessionIdleTimeout   string         `mapstructure:"session_idle_timeout"`
+	RentalDevices        int            `mapstructure:"rental_devices"`
+	PlanRentalDevices    map[string]int `mapstructure:"plan_rental_devices"`
+}
+
+// OrdersConfig controls the worker that expires unpaid orders.
+// ReaperInterval is a duration string, CancelOnGateway also cancels the Midtrans transaction.
+// PaymentExpiry is the default checkout link lifetime (duration string, 30m to 24h), movies can override it.
+// With SettlementEnabled the worker reconciles each UTC day's paid orders against the gateways SettlementDelay
+// after the day ended, it checks for a missing report every SettlementInterval. Durations are strings.
+type OrdersConfig struct {
+	PaymentExpiry   string `mapstructure:"payment_expiry"`
+	ReaperEnabled   bool   `mapstructure:"reaper_enabled"`
+	ReaperInterval  string `mapstructure:"reaper_interval"`
+	ReaperBatchSize int    `mapstructure:"reaper_batch_size"`
+	CancelOnGateway bool   `mapstructure:"cancel_on_gateway"`
+
+	SettlementEnabled  bool   `mapstructure:"settlement_enabled"`
+	SettlementInterval string `mapstructure:"settlement_interval"`
+	SettlementDelay    string `mapstructure:"settlement_delay"`
+}
+
+// LicensingConfig enforces the per-region licensing windows of movies.
+// The client's country is read from RegionHeader on requests from Server.TrustedProxies,
+// DefaultRegion is used when it is missing or the request did not come through a trusted proxy.
+// Orders are refused OrderCutoff before a window closes, the worker unpublishes closed titles every EnforceInterval.
+type LicensingConfig struct {
+	RegionHeader    string `mapstructure:"region_header"`
+	DefaultRegion   string `mapstructure:"default_region"`
+	OrderCutoff     string `mapstructure:"order_cutoff"`
+	EnforceInterval string `mapstructure:"enforce_interval"`
+}
+
+// MailConfig is the SMTP server used for notification emails.
+// When Host is empty emails are only logged.
+// PasswordResetURL is the frontend page receiving ?token=, PasswordResetExpiry is a duration string.
+// EmailChangeURL and EmailChangeExpiry work the same for the link confirming a new email address.
+type MailConfig struct {
+	Host                string `mapstructure:"host"`
+	Port                string `mapstructure:"port"`
+	Username            string `mapstructure:"username"`
+	Password            string `mapstructure:"password"`
+	From                string `mapstructure:"from"`
+	SupportInbox        string `mapstructure:"support_inbox"`
+	PasswordResetURL    string `mapstructure:"password_reset_url"`
+	PasswordResetExpiry string `mapstructure:"password_reset_expiry"`
+	EmailChangeURL      string `mapstructure:"email_change_url"`
+	EmailChangeExpiry   string `mapstructure:"email_change_expiry"`
+	AccessGrantURL      string `mapstructure:"access_grant_url"`
+}
+
+// NotificationConfig controls the notifications the worker sends from the notifications queue.
+// Sender is smtp (the mail settings, logs without a host) or log. Renters are reminded
+// ExpiryReminderBefore the end of a rental, checked every ExpiryReminderInterval. Durations are strings.
+type NotificationConfig struct {
+	Sender                 string `mapstructure:"sender"`
+	ExpiryReminderEnabled  bool   `mapstructure:"expiry_reminder_enabled"`
+	ExpiryReminderBefore   string `mapstructure:"expiry_reminder_before"`
+	ExpiryReminderInterval string `mapstructure:"expiry_reminder_interval"`
+}
+
+// InvoiceConfig is what receipts of paid orders print about the seller.
+// With Email set the receipt is emailed once the payment is confirmed, unless the user opted out of emails.
+type InvoiceConfig struct {
+	SellerName    string `mapstructure:"seller_name"`
+	SellerAddress string `mapstructure:"seller_address"` // lines separated by newlines
+	Currency      string `mapstructure:"currency"`
+	Email         bool   `mapstructure:"email"`
+}
+
+// RoyaltyConfig is what royalty statements deduct from the revenue before it is shared with rights holders.
+// Fees are keyed by payment provider, a provider without an entry keeps nothing.
+type RoyaltyConfig struct {
+	Fees map[string]PaymentFeeConfig `mapstructure:"fees"`
+}
+
+// PaymentFeeConfig is what a payment provider keeps of an order, Percent of the amount plus Fixed
+type PaymentFeeConfig struct {
+	Percent float64 `mapstructure:"percent"`
+	Fixed   float64 `mapstructure:"fixed"`
+}
+
+// TranscodingConfig controls how the worker encodes titles.
+// Sources of at least ChunkedMinDuration are split into chunks of ChunkDuration that any worker can encode,
+// the worker that picked up the title stitches them and gives up after ChunkWaitTimeout. Durations are strings.
+type TranscodingConfig struct {
+	ChunkedEnabled     bool   `mapstructure:"chunked_enabled"`
+	ChunkedMinDuration string `mapstructure:"chunked_min_duration"`
+	ChunkDuration      string `mapstructure:"chunk_duration"`
+	ChunkWaitTimeout   string `mapstructure:"chunk_wait_timeout"`
+	Encoder            string `mapstructure:"encoder"`      // auto, libx264, h264_nvenc, h264_vaapi or h264_qsv
+	VAAPIDevice        string `mapstructure:"vaapi_device"` // render node used by h264_vaapi
+	EncryptHLS         bool   `mapstructure:"encrypt_hls"`  // AES-128 segments, keys served by GET /api/v1/keys/:movieID
+	DASHEnabled        bool   `mapstructure:"dash_enabled"` // also publish a DASH manifest, unencrypted titles only
+
+	// Added to the built-in ladders, an entry with a built-in name replaces it
+	Profiles    []QualityProfileConfig      `mapstructure:"profiles"`
+	ProfileSets map[string]ProfileSetConfig `mapstructure:"profile_sets"`
+}
+
+// QualityProfileConfig is one rendition of a ladder, rates are in kbit/s like "2800k"
+type QualityProfileConfig struct {
+	Name       string `mapstructure:"name"`
+	Resolution string `mapstructure:"resolution"` // WIDTHxHEIGHT
+	Bitrate    string `mapstructure:"bitrate"`
+	MaxRate    string `mapstructure:"max_rate"`
+	BufSize    string `mapstructure:"buf_size"`
+}
+
+// ProfileSetConfig is a ladder that movies can be assigned by name, profiles are referenced by name
+type ProfileSetConfig struct {
+	Profiles       []string `mapstructure:"profiles"`
+	HDRPassthrough bool     `mapstructure:"hdr_passthrough"`
+}
+
+// SpeechToTextConfig enables draft subtitles generated by the worker after a transcode.
+// Provider is "whisper" (runs Command with Model) or "http" (posts the audio to ServiceURL).
+// Drafts are only published after an admin approves them. Timeout is a duration string.
+type SpeechToTextConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Provider   string `mapstructure:"provider"`
+	Command    string `mapstructure:"command"`
+	Model      string `mapstructure:"model"`
+	Language   string `mapstructure:"language"` // empty = detected by the model
+	ServiceURL string `mapstructure:"service_url"`
+	Token      string `mapstructure:"token"`
+	Timeout    string `mapstructure:"timeout"`
+}
+
+// ModerationConfig scans uploaded posters and user comments before they become public.
+// Provider "http" posts the content to ServiceURL, empty disables scanning. Content the service flags
+// or fails to scan waits in the admin moderation queue. Timeout is a duration string.
+type ModerationConfig struct {
+	Provider   string `mapstructure:"provider"`
+	ServiceURL string `mapstructure:"service_url"`
+	Token      string `mapstructure:"token"`
+	Timeout    string `mapstructure:"timeout"`
+}
+
+// TMDBConfig enables metadata enrichment from The Movie Database.
+// APIToken is a v4 read access token, enrichment is disabled when it is empty. Timeout is a duration string.
+type TMDBConfig struct {
+	APIToken     string `mapstructure:"api_token"`
+	BaseURL      string `mapstructure:"base_url"`
+	ImageBaseURL string `mapstructure:"image_base_url"`
+	Language     string `mapstructure:"language"`
+	Timeout      string `mapstructure:"timeout"`
+}
+
+// SEOConfig is the public site search engines index.
+// Movie pages default to SiteURL/movies/{id} as canonical URL, without SiteURL only movies with
+// a canonical URL override have one and are listed in the sitemap.
+type SEOConfig struct {
+	SiteURL string `mapstructure:"site_url"`
+}
+
+// LoginConfig controls the lockout after failed logins.
+// An email is locked after MaxAttempts failures within Window, an IP after IPMaxAttempts.
+// Lockout doubles for every further lockout of the email up to MaxLockout. Durations are strings.
+// With a Captcha provider, logins need a solved CAPTCHA after CaptchaAfter failures within Window.
+type LoginConfig struct {
+	MaxAttempts   int    `mapstructure:"max_attempts"`
+	IPMaxAttempts int    `mapstructure:"ip_max_attempts"`
+	Window        string `mapstructure:"window"`
+	Lockout       string `mapstructure:"lockout"`
+	MaxLockout    string `mapstructure:"max_lockout"`
+	CaptchaAfter  int    `mapstructure:"captcha_after"`
+
+	Captcha CaptchaConfig `mapstructure:"captcha"`
+}
+
+// CaptchaConfig selects the CAPTCHA provider of the login, "hcaptcha" or "turnstile", empty disables it.
+// VerifyURL defaults to the siteverify endpoint of the provider.
+type CaptchaConfig struct {
+	Provider  string `mapstructure:"provider"`
+	SiteKey   string `mapstructure:"site_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	VerifyURL string `mapstructure:"verify_url"`
+	Timeout   string `mapstructure:"timeout"`
+}
+
+// PasswordsConfig controls the breached password check at registration and password reset.
+// When CheckBreached is set, passwords are looked up in the Have I Been Pwned range API by the first
+// 5 characters of their SHA-1 hash, the password itself never leaves the server. Passwords seen fewer
+// than MinBreachCount times are accepted. Timeout is a duration string, the check fails open.
+type PasswordsConfig struct {
+	CheckBreached  bool   `mapstructure:"check_breached"`
+	BreachAPIURL   string `mapstructure:"breach_api_url"`
+	MinBreachCount int    `mapstructure:"min_breach_count"`
+	Timeout        string `mapstructure:"timeout"`
+}
+
+// ViewsConfig controls how often the worker moves the playback counters from Redis to MySQL.
+// FlushInterval is a duration string, the popular movies lag behind by up to one interval.
+type ViewsConfig struct {
+	FlushInterval string `mapstructure:"flush_interval"`
+}
+
+// CDNConfig controls how the worker drains the catalog change log into CDN purges.
+// When PurgeURL is empty purges are only logged. DispatchInterval is a duration string,
+// a change is given up after MaxAttempts failed purges.
+type CDNConfig struct {
+	PurgeURL          string `mapstructure:"purge_url"`
+	PurgeToken        string `mapstructure:"purge_token"`
+	DispatchInterval  string `mapstructure:"dispatch_interval"`
+	DispatchBatchSize int    `mapstructure:"dispatch_batch_size"`
+	MaxAttempts       int    `mapstructure:"max_attempts"`
+}
+
+// RetentionConfig lets the worker delete or anonymize data past its retention period every Interval.
+// Each rule names a built-in target, see the retention package, and the days its rows are kept.
+// Rows are changed BatchSize at a time. A dry-run rule only stores how many rows are due.
+type RetentionConfig struct {
+	Enabled   bool                  `mapstructure:"enabled"`
+	Interval  string                `mapstructure:"interval"`
+	BatchSize int                   `mapstructure:"batch_size"`
+	Rules     []RetentionRuleConfig `mapstructure:"rules"`
+}
+
+// RetentionRuleConfig keeps the rows of a target for AfterDays days
+type RetentionRuleConfig struct {
+	Target    string `mapstructure:"target"`
+	AfterDays int    `mapstructure:"after_days"`
+	DryRun    bool   `mapstructure:"dry_run"`
+}
+
+// BackupConfig lets the worker write an encrypted archive of the critical tables and a snapshot of the
+// stored objects to Bucket every day at Time (HH:MM, UTC). EncryptionKey is 32 bytes in base64, an archive
+// cannot be restored without it. Archives older than RetentionDays are removed, the newest few are always kept.
+// Tables replaces the default tables of the backup package.
+type BackupConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	Bucket        string   `mapstructure:"bucket"`
+	EncryptionKey string   `mapstructure:"encryption_key"`
+	Time          string   `mapstructure:"time"`
+	RetentionDays int      `mapstructure:"retention_days"`
+	Tables        []string `mapstructure:"tables"`
+}
+
+// CleanupConfig lets the worker remove uploaded sources that are no longer needed every Interval:
+// sources of movies deleted, or transcoded and READY, more than RawRetentionDays ago, and sources of failed
+// or replaced uploads that are that old. A dry run only logs what would be removed.
+type CleanupConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	Interval         string `mapstructure:"interval"`
+	RawRetentionDays int    `mapstructure:"raw_retention_days"`
+	DryRun           bool   `mapstructure:"dry_run"`
+}
+
+// PopularityConfig controls how the worker recomputes the popularity scores behind sort=popular and
+// the trending movies. Every Interval the activity of the last WindowDays days is weighted per event,
+// activity counts half as much every HalfLifeDays days. RatingWeight applies to the average editorial score.
+type PopularityConfig struct {
+	Interval         string  `mapstructure:"interval"`
+	WindowDays       int     `mapstructure:"window_days"`
+	HalfLifeDays     float64 `mapstructure:"half_life_days"`
+	ViewWeight       float64 `mapstructure:"view_weight"`
+	CompletionWeight float64 `mapstructure:"completion_weight"`
+	OrderWeight      float64 `mapstructure:"order_weight"`
+	RatingWeight     float64 `mapstructure:"rating_weight"`
+}
+
+// CacheConfig controls the Redis cache of the public catalog reads: the movie list, movie details and genres.
+// Catalog writes drop the cache right away, CatalogTTL bounds how stale changes made outside the API can be.
+// PublicMaxAge is how long browsers and CDNs may reuse anonymous catalog responses before revalidating their ETag.
+type CacheConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	CatalogTTL   string `mapstructure:"catalog_ttl"`
+	PublicMaxAge string `mapstructure:"public_max_age"`
+}
+
+// MaintenanceConfig puts the API in maintenance mode: every request but the admin API, sign-in and
+// payment webhooks gets a 503 with Message and a Retry-After of RetryAfter, a duration string.
+// Like the other reloadable settings it applies without a restart when the config file changes.
+type MaintenanceConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Message    string `mapstructure:"message"`
+	RetryAfter string `mapstructure:"retry_after"`
+}
+
+// LogConfig is the log output shared by the API and the worker.
+// Format "json" (the default) writes one JSON object per line for log aggregation,
+// "console" writes colored lines for local development. Level is a zerolog level, info when empty.
+type LogConfig struct {
+	Level  string `mapstructure:"level"`
+	Format string `mapstructure:"format"`
+}
+
+// HealthConfig controls the readiness probes, /readyz pings MySQL, Redis and MinIO with up to Timeout each.
+// The worker has no HTTP server of its own, it serves /healthz and /readyz on WorkerPort, empty disables it.
+type HealthConfig struct {
+	Timeout    string `mapstructure:"timeout"`
+	WorkerPort string `mapstructure:"worker_port"`
 }
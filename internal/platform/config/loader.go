@@ -1,26 +1,275 @@
 package config
 
 import (
-	"log"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix prefixes the environment variables overriding the config, e.g. CINESTREAM_DATABASE_HOST
+const EnvPrefix = "CINESTREAM"
+
+// PathEnv names the config file when no path is passed to LoadConfig
+const PathEnv = EnvPrefix + "_CONFIG"
+
 var AppConfig Config
 
-func LoadConfig() (*Config, error) {
-	viper.SetConfigName("app-config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
+// LoadConfig reads the config from the YAML file at path, then from the environment.
+// An empty path falls back to CINESTREAM_CONFIG, then to an optional app-config.yaml in the
+// working directory, so containers can be configured with environment variables only.
+// Every key can be overridden by its upper-cased path with dots replaced by underscores,
+// e.g. database.host by CINESTREAM_DATABASE_HOST. Lists and maps can only be set in the file.
+// service is "api" or "worker", see Validate.
+func LoadConfig(path, service string) (*Config, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	// AutomaticEnv only applies to keys viper already knows, Unmarshal needs every key bound
+	if err := bindEnvs(v, reflect.TypeOf(Config{}), ""); err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		path = os.Getenv(PathEnv)
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read config file %s: %w", path, err)
+		}
+	} else {
+		v.SetConfigName("app-config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+		if err := v.ReadInConfig(); err != nil {
+			var notFound viper.ConfigFileNotFoundError
+			if !errors.As(err, &notFound) {
+				return nil, fmt.Errorf("read config file: %w", err)
+			}
+		}
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalf("Error reading config file: %s", err)
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
 	}
+	cfg.File = v.ConfigFileUsed()
 
-	if err := viper.Unmarshal(&AppConfig); err != nil {
-		log.Fatalf("Unable to decode config into struct: %s", err)
+	if err := cfg.Validate(service); err != nil {
+		return nil, err
 	}
 
-	log.Println("Configuration loaded successfully.")
+	AppConfig = cfg
 	return &AppConfig, nil
 }
+
+// bindEnvs binds the environment variable of every scalar key below prefix
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			if err := bindEnvs(v, field.Type, key); err != nil {
+				return err
+			}
+		case reflect.Map, reflect.Slice:
+			// Not expressible as a single variable
+		default:
+			if err := v.BindEnv(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setDefaults gives every setting a deployment rarely changes the value of app-config-example.yaml.
+// Credentials and hosts have no default, Validate reports the missing ones.
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.format", "json")
+	v.SetDefault("health.timeout", "2s")
+	v.SetDefault("health.worker_port", "8081")
+
+	v.SetDefault("server.port", "8080")
+	v.SetDefault("server.drain_delay", "5s")
+	v.SetDefault("server.shutdown_timeout", "10m")
+	v.SetDefault("server.environment", "production")
+
+	v.SetDefault("database.port", "3306")
+	v.SetDefault("database.max_idle_conns", 10)
+	v.SetDefault("database.max_open_conns", 100)
+	v.SetDefault("redis.port", "6379")
+	v.SetDefault("queue.name", "cinestream_transcoding_jobs")
+	v.SetDefault("queue.max_retries", 3)
+	v.SetDefault("queue.claim_idle", "5m")
+	v.SetDefault("queue.consumers", map[string]int{"chunks": 1, "audio": 1, "notifications": 1})
+
+	v.SetDefault("minio.bucket_raw", "raw-videos")
+	v.SetDefault("minio.bucket_processed", "processed-videos")
+	v.SetDefault("minio.bucket_media", "movie-media")
+	v.SetDefault("minio.manage_buckets", true)
+
+	v.SetDefault("jwt.access_token_expiry", "1h")
+	v.SetDefault("jwt.refresh_token_expiry", "2160h")
+	v.SetDefault("jwt.refresh_token_idle_expiry", "720h")
+	v.SetDefault("jwt.refresh_token_purge_interval", "1h")
+
+	v.SetDefault("payment_gateway.provider", "midtrans")
+	v.SetDefault("payment_gateway.stripe.currency", "idr")
+
+	v.SetDefault("invoices.seller_name", "CineStream")
+	v.SetDefault("invoices.currency", "IDR")
+	v.SetDefault("invoices.email", true)
+
+	v.SetDefault("notifications.sender", "smtp")
+	v.SetDefault("notifications.expiry_reminder_enabled", true)
+	v.SetDefault("notifications.expiry_reminder_before", "24h")
+	v.SetDefault("notifications.expiry_reminder_interval", "15m")
+
+	v.SetDefault("media.public_mode", "proxy")
+	v.SetDefault("media.private_mode", "presigned")
+	v.SetDefault("media.presign_expiry", "15m")
+
+	v.SetDefault("orders.payment_expiry", "24h")
+	v.SetDefault("orders.reaper_enabled", true)
+	v.SetDefault("orders.reaper_interval", "1m")
+	v.SetDefault("orders.reaper_batch_size", 100)
+	v.SetDefault("orders.cancel_on_gateway", true)
+	v.SetDefault("orders.settlement_enabled", true)
+	v.SetDefault("orders.settlement_interval", "1h")
+	v.SetDefault("orders.settlement_delay", "6h")
+
+	v.SetDefault("licensing.default_region", "ID")
+	v.SetDefault("licensing.order_cutoff", "72h")
+	v.SetDefault("licensing.enforce_interval", "15m")
+
+	v.SetDefault("transcoding.encoder", "auto")
+	v.SetDefault("transcoding.encrypt_hls", true)
+
+	v.SetDefault("views.flush_interval", "1m")
+	v.SetDefault("cdn.dispatch_interval", "10s")
+	v.SetDefault("cdn.dispatch_batch_size", 100)
+	v.SetDefault("cdn.max_attempts", 5)
+
+	v.SetDefault("backup.bucket", "cinestream-backups")
+	v.SetDefault("backup.time", "02:00")
+	v.SetDefault("backup.retention_days", 30)
+
+	v.SetDefault("cleanup.interval", "6h")
+	v.SetDefault("cleanup.raw_retention_days", 30)
+
+	v.SetDefault("popularity.interval", "15m")
+	v.SetDefault("popularity.window_days", 14)
+	v.SetDefault("popularity.half_life_days", 3)
+	v.SetDefault("popularity.view_weight", 1)
+	v.SetDefault("popularity.completion_weight", 3)
+	v.SetDefault("popularity.order_weight", 5)
+	v.SetDefault("popularity.rating_weight", 2)
+
+	v.SetDefault("cache.enabled", true)
+	v.SetDefault("cache.catalog_ttl", "5m")
+	v.SetDefault("cache.public_max_age", "60s")
+
+	v.SetDefault("moderation.timeout", "10s")
+
+	v.SetDefault("maintenance.enabled", false)
+	v.SetDefault("maintenance.retry_after", "10m")
+}
+
+// Validate reports every missing or invalid setting of the service at once, so a deployment is fixed in one go.
+// The worker serves no requests, it may run without the server, token and media settings.
+func (c *Config) Validate(service string) error {
+	var errs []error
+	required := func(key, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required (%s)", key, envName(key)))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of: %s, got %q", key, strings.Join(allowed, ", "), value))
+	}
+
+	required("database.host", c.Database.Host)
+	required("database.port", c.Database.Port)
+	required("database.user", c.Database.User)
+	required("database.dbname", c.Database.DBName)
+	required("redis.host", c.Redis.Host)
+	required("redis.port", c.Redis.Port)
+	required("queue.name", c.Queue.Name)
+	required("minio.endpoint", c.MinIO.Endpoint)
+	required("minio.access_key_id", c.MinIO.AccessKeyID)
+	required("minio.secret_access_key", c.MinIO.SecretAccessKey)
+	required("minio.bucket_raw", c.MinIO.BucketRaw)
+	required("minio.bucket_processed", c.MinIO.BucketProcessed)
+	oneOf("log.format", c.Log.Format, "json", "console")
+	oneOf("payment_gateway.provider", c.PaymentGW.Provider, "midtrans", "stripe")
+	oneOf("notifications.sender", c.Notifications.Sender, "smtp", "log")
+
+	if service == "api" {
+		required("server.port", c.Server.Port)
+		oneOf("server.environment", c.Server.Environment, "development", "staging", "production")
+		for _, cidr := range c.Server.TrustedProxies {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errs = append(errs, fmt.Errorf("server.trusted_proxies must hold CIDR ranges, got %q", cidr))
+			}
+		}
+		required("jwt.secret_key", c.JWT.SecretKey)
+		oneOf("media.public_mode", c.Media.PublicMode, "proxy", "presigned")
+		oneOf("media.private_mode", c.Media.PrivateMode, "proxy", "presigned")
+		for provider, fee := range c.Royalties.Fees {
+			if fee.Percent < 0 || fee.Percent >= 100 || fee.Fixed < 0 {
+				errs = append(errs, fmt.Errorf("royalties.fees.%s must have a percent in [0, 100) and a fixed fee of at least 0", provider))
+			}
+		}
+	}
+
+	if service == "worker" && c.Cleanup.Enabled && c.Cleanup.RawRetentionDays < 1 {
+		errs = append(errs, fmt.Errorf("cleanup.raw_retention_days must be at least 1, got %d", c.Cleanup.RawRetentionDays))
+	}
+
+	if service == "worker" && c.Backup.Enabled {
+		required("backup.bucket", c.Backup.Bucket)
+		required("backup.encryption_key", c.Backup.EncryptionKey)
+		if key, err := base64.StdEncoding.DecodeString(c.Backup.EncryptionKey); c.Backup.EncryptionKey != "" && (err != nil || len(key) != 32) {
+			errs = append(errs, fmt.Errorf("backup.encryption_key must be 32 bytes in base64, e.g. from: openssl rand -base64 32"))
+		}
+		if _, err := time.Parse("15:04", c.Backup.Time); err != nil {
+			errs = append(errs, fmt.Errorf("backup.time must be HH:MM, got %q", c.Backup.Time))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+}
+
+// envName returns the environment variable of a config key
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	zlog "github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// Watch reloads the config file cfg was read from whenever it changes and passes the new config to apply,
+// which applies the settings of withoutReloadable. Other changes are logged and take effect after a restart.
+// A config that fails to load or validate is logged and skipped, the service keeps the previous one.
+// apply runs on the watcher's goroutine, one reload at a time. Without a config file there is nothing to watch.
+func Watch(cfg *Config, service string, apply func(previous, current *Config)) {
+	if cfg.File == "" {
+		return
+	}
+
+	var mu sync.Mutex
+	previous := *cfg
+	v := viper.New()
+	v.SetConfigFile(cfg.File)
+	v.OnConfigChange(func(event fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		current, err := LoadConfig(cfg.File, service)
+		if err != nil {
+			zlog.Error().Err(err).Str("config_file", cfg.File).Msg("Ignoring changed config, keeping the previous one")
+			return
+		}
+		if reflect.DeepEqual(previous, *current) {
+			return
+		}
+
+		if sections := restartRequired(&previous, current); len(sections) > 0 {
+			zlog.Warn().Strs("sections", sections).Msg("Changed config sections take effect after a restart")
+		}
+		zlog.Info().Str("config_file", cfg.File).Msg("Config changed, applying reloadable settings")
+		apply(&previous, current)
+		previous = *current
+	})
+	v.WatchConfig()
+}
+
+// withoutReloadable clears the settings running services apply on a reload
+func withoutReloadable(c Config) Config {
+	c.Log.Level = ""
+	c.Login = LoginConfig{Captcha: c.Login.Captcha}
+	c.Maintenance = MaintenanceConfig{}
+	c.Queue.Consumers = nil
+	c.Transcoding.Profiles = nil
+	c.Transcoding.ProfileSets = nil
+	return c
+}
+
+// restartRequired returns the top-level sections with changes a running service does not reload
+func restartRequired(previous, current *Config) []string {
+	before, after := reflect.ValueOf(withoutReloadable(*previous)), reflect.ValueOf(withoutReloadable(*current))
+	var sections []string
+	t := before.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if !reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			sections = append(sections, tag)
+		}
+	}
+	return sections
+}
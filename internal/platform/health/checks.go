@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/redis/go-redis/v9"
+)
+
+// MySQL pings the database through the connection pool
+func MySQL(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Redis pings the Redis server
+func Redis(client *redis.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
+
+// MinIO checks that the buckets the service works with can be reached
+func MinIO(client *minio.Client, buckets ...string) Check {
+	return func(ctx context.Context) error {
+		for _, bucket := range buckets {
+			if bucket == "" {
+				continue
+			}
+			exists, err := client.BucketExists(ctx, bucket)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("bucket %s does not exist", bucket)
+			}
+		}
+		return nil
+	}
+}
+
+// BucketPolicy checks that anyone may read the objects of the public bucket, where HLS output is served from,
+// and that the policies of the private buckets grant nothing to anonymous users
+func BucketPolicy(client *minio.Client, public string, private ...string) Check {
+	return func(ctx context.Context) error {
+		policy, err := client.GetBucketPolicy(ctx, public)
+		if err != nil {
+			return fmt.Errorf("failed to read policy of bucket %s: %w", public, err)
+		}
+		readable, err := anonymousRead(policy)
+		if err != nil {
+			return fmt.Errorf("bucket %s: %w", public, err)
+		}
+		if !readable {
+			return fmt.Errorf("bucket %s is not public-read", public)
+		}
+
+		for _, bucket := range private {
+			if bucket == "" {
+				continue
+			}
+			policy, err := client.GetBucketPolicy(ctx, bucket)
+			if err != nil {
+				return fmt.Errorf("failed to read policy of bucket %s: %w", bucket, err)
+			}
+			readable, err := anonymousRead(policy)
+			if err != nil {
+				return fmt.Errorf("bucket %s: %w", bucket, err)
+			}
+			if readable {
+				return fmt.Errorf("bucket %s allows anonymous reads", bucket)
+			}
+		}
+		return nil
+	}
+}
+
+// bucketPolicy is the part of an S3 bucket policy the check looks at,
+// Principal and Action can be a string or a list
+type bucketPolicy struct {
+	Statement []struct {
+		Effect    string          `json:"Effect"`
+		Principal json.RawMessage `json:"Principal"`
+		Action    json.RawMessage `json:"Action"`
+	} `json:"Statement"`
+}
+
+// anonymousRead reports whether a bucket policy allows anyone to get objects, an empty policy allows nothing
+func anonymousRead(policy string) (bool, error) {
+	if policy == "" {
+		return false, nil
+	}
+	var parsed bucketPolicy
+	if err := json.Unmarshal([]byte(policy), &parsed); err != nil {
+		return false, fmt.Errorf("invalid bucket policy: %w", err)
+	}
+	for _, statement := range parsed.Statement {
+		if statement.Effect != "Allow" || !anyone(statement.Principal) {
+			continue
+		}
+		for _, action := range stringList(statement.Action) {
+			if action == "s3:GetObject" || action == "s3:*" || action == "*" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// anyone reports whether a policy principal is every user, "*" or {"AWS": "*"}
+func anyone(principal json.RawMessage) bool {
+	var aws struct {
+		AWS json.RawMessage `json:"AWS"`
+	}
+	if json.Unmarshal(principal, &aws) == nil && aws.AWS != nil {
+		principal = aws.AWS
+	}
+	for _, p := range stringList(principal) {
+		if p == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList decodes a policy value that is either a string or a list of strings
+func stringList(raw json.RawMessage) []string {
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return []string{one}
+	}
+	var list []string
+	_ = json.Unmarshal(raw, &list)
+	return list
+}
@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status of a dependency and of the whole report
+const (
+	StatusOK   = "ok"
+	StatusDown = "down"
+)
+
+// Check pings one dependency, it has to give up once the context is done
+type Check func(ctx context.Context) error
+
+// DependencyStatus is the outcome of one check
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the readiness of a service, Status is down when any dependency is down
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// Checker runs the dependency checks of a service in parallel, each with its own timeout
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Check
+}
+
+// NewChecker returns a checker giving every check up to timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: map[string]Check{}}
+}
+
+// Add registers the check of a dependency, e.g. "mysql"
+func (c *Checker) Add(name string, check Check) {
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Check runs all checks, a slow dependency is reported down after the timeout
+func (c *Checker) Check(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Dependencies: make(map[string]DependencyStatus, len(c.names))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			status := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[name] = status
+			if status.Status != StatusOK {
+				report.Status = StatusDown
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check(ctx)
+	status := DependencyStatus{Status: StatusOK, LatencyMS: time.Since(started).Milliseconds()}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			status.Error = "timed out after " + c.timeout.String()
+		}
+	}
+	return status
+}
+
+// Liveness serves the liveness probe, it only tells that the process is serving requests.
+// Dependencies are left out on purpose, a database outage must not get every instance restarted.
+func Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// Readiness serves the readiness probe, 503 with the report while a dependency is down
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+	code := http.StatusOK
+	if report.Status != StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
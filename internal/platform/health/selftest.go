@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// SelfTest runs every check once and writes a PASS or FAIL line per check to w, in the order they were added.
+// It reports whether all checks passed, for the --check mode deployment pipelines run before switching traffic.
+func (c *Checker) SelfTest(ctx context.Context, w io.Writer) bool {
+	report := c.Check(ctx)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range c.names {
+		status := report.Dependencies[name]
+		if status.Status == StatusOK {
+			fmt.Fprintf(tw, "PASS\t%s\t%dms\n", name, status.LatencyMS)
+		} else {
+			fmt.Fprintf(tw, "FAIL\t%s\t%dms\t%s\n", name, status.LatencyMS, status.Error)
+		}
+	}
+	tw.Flush()
+
+	if report.Status != StatusOK {
+		fmt.Fprintln(w, "Self-test failed")
+		return false
+	}
+	fmt.Fprintln(w, "Self-test passed")
+	return true
+}
+
+// Detached runs a check that cannot be cancelled, e.g. a connection set up without a context,
+// in the background so the checker still gives up on it after the timeout
+func Detached(check Check) Check {
+	return func(ctx context.Context) error {
+		done := make(chan error, 1)
+		go func() { done <- check(ctx) }()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
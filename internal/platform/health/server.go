@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Serve runs a probe listener with /healthz and /readyz until the context is cancelled,
+// for binaries such as the worker that have no HTTP server of their own
+func Serve(ctx context.Context, addr string, checker *Checker) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", Liveness)
+	mux.HandleFunc("/readyz", checker.Readiness)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// tailLines is how many of the last lines a LineWriter keeps for the error entry of a failed command
+const tailLines = 20
+
+// LineWriter turns the output of an external tool such as ffmpeg into log entries, one per line.
+// Progress updates ended by a carriage return count as lines too. The last lines are kept,
+// so a failed command can be logged with the output that explains it.
+type LineWriter struct {
+	logger  zerolog.Logger
+	level   zerolog.Level
+	pending []byte
+	tail    []string
+}
+
+// NewLineWriter returns a writer logging each line with the logger at the level
+func NewLineWriter(logger zerolog.Logger, level zerolog.Level) *LineWriter {
+	return &LineWriter{logger: logger, level: level}
+}
+
+// Write logs every complete line and keeps an unfinished one for the next write
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		end := bytes.IndexAny(w.pending, "\r\n")
+		if end < 0 {
+			break
+		}
+		w.line(string(w.pending[:end]))
+		w.pending = w.pending[end+1:]
+	}
+	return len(p), nil
+}
+
+// Flush logs an unfinished last line, call it once the command exited
+func (w *LineWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.line(string(w.pending))
+		w.pending = nil
+	}
+}
+
+// Tail returns the last lines written, joined by newlines
+func (w *LineWriter) Tail() string {
+	return strings.Join(w.tail, "\n")
+}
+
+func (w *LineWriter) line(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	w.logger.WithLevel(w.level).Msg(text)
+
+	w.tail = append(w.tail, text)
+	if len(w.tail) > tailLines {
+		w.tail = w.tail[len(w.tail)-tailLines:]
+	}
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Setup configures the global zerolog logger of a service, every entry carries the service name.
+// Contexts without a logger of their own fall back to it, so zerolog.Ctx is safe everywhere.
+func Setup(cfg config.LogConfig, service string) {
+	SetLevel(cfg)
+	zerolog.TimeFieldFormat = time.RFC3339Nano
+
+	logger := zerolog.New(os.Stdout)
+	if cfg.Format == "console" {
+		logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
+	}
+	zlog.Logger = logger.With().Timestamp().Str("service", service).Logger()
+	zerolog.DefaultContextLogger = &zlog.Logger
+}
+
+// SetLevel applies the log level of cfg, it is safe to call while the service runs
+func SetLevel(cfg config.LogConfig) {
+	level, err := zerolog.ParseLevel(strings.ToLower(cfg.Level))
+	if err != nil || cfg.Level == "" {
+		level = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(level)
+}
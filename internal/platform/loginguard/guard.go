@@ -0,0 +1,178 @@
+package loginguard
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "login_guard"
+
+// Options controls when failed logins lock an email or a client IP
+type Options struct {
+	MaxAttempts   int           // failures of one email within Window before it is locked
+	IPMaxAttempts int           // failures from one IP within Window before it is locked
+	Window        time.Duration // failures older than this are forgotten
+	Lockout       time.Duration // first lockout, doubled for every further lockout of the email
+	MaxLockout    time.Duration // longest lockout
+	CaptchaAfter  int           // failures of one email or IP within Window before a CAPTCHA is required
+}
+
+// Guard counts failed logins in Redis and locks emails and IPs with an exponential backoff
+type Guard struct {
+	client *redis.Client
+	opts   atomic.Pointer[Options]
+}
+
+// NewGuard creates a new Redis backed login guard, unset options get a default
+func NewGuard(client *redis.Client, opts Options) *Guard {
+	g := &Guard{client: client}
+	g.SetOptions(opts)
+	return g
+}
+
+// SetOptions replaces the options, e.g. after a config reload. Counted failures and running lockouts stay.
+func (g *Guard) SetOptions(opts Options) {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.IPMaxAttempts <= 0 {
+		opts.IPMaxAttempts = 20
+	}
+	if opts.Window <= 0 {
+		opts.Window = 15 * time.Minute
+	}
+	if opts.Lockout <= 0 {
+		opts.Lockout = 15 * time.Minute
+	}
+	if opts.MaxLockout <= 0 {
+		opts.MaxLockout = 24 * time.Hour
+	}
+	if opts.CaptchaAfter <= 0 {
+		opts.CaptchaAfter = 3
+	}
+	if opts.MaxLockout < opts.Lockout {
+		opts.MaxLockout = opts.Lockout
+	}
+	g.opts.Store(&opts)
+}
+
+// Locked returns how long the email or IP is still locked, 0 when login may be attempted
+func (g *Guard) Locked(ctx context.Context, email, ip string) (time.Duration, error) {
+	var remaining time.Duration
+	for _, key := range []string{lockKey("email", normalizeEmail(email)), lockKey("ip", ip)} {
+		ttl, err := g.client.PTTL(ctx, key).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read login lock: %w", err)
+		}
+		if ttl > remaining {
+			remaining = ttl
+		}
+	}
+	return remaining, nil
+}
+
+// RecordFailure counts a failed login. It returns the lockout when this failure locked the email.
+func (g *Guard) RecordFailure(ctx context.Context, email, ip string) (time.Duration, error) {
+	opts := g.opts.Load()
+	email = normalizeEmail(email)
+
+	pipe := g.client.TxPipeline()
+	emailFailures := pipe.Incr(ctx, failKey("email", email))
+	pipe.ExpireNX(ctx, failKey("email", email), opts.Window)
+	ipFailures := pipe.Incr(ctx, failKey("ip", ip))
+	pipe.ExpireNX(ctx, failKey("ip", ip), opts.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count login failure: %w", err)
+	}
+
+	if ipFailures.Val() >= int64(opts.IPMaxAttempts) {
+		pipe := g.client.TxPipeline()
+		pipe.Set(ctx, lockKey("ip", ip), 1, opts.Lockout)
+		pipe.Del(ctx, failKey("ip", ip))
+		if _, err := pipe.Exec(ctx); err != nil {
+			return 0, fmt.Errorf("failed to lock ip: %w", err)
+		}
+	}
+
+	if emailFailures.Val() < int64(opts.MaxAttempts) {
+		return 0, nil
+	}
+
+	// Every lockout within MaxLockout of the previous one doubles the lockout
+	strikes, err := g.client.Incr(ctx, strikeKey(email)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count lockout: %w", err)
+	}
+	lockout := opts.Lockout
+	for i := int64(1); i < strikes && lockout < opts.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > opts.MaxLockout {
+		lockout = opts.MaxLockout
+	}
+
+	pipe = g.client.TxPipeline()
+	pipe.Expire(ctx, strikeKey(email), lockout+opts.MaxLockout)
+	pipe.Set(ctx, lockKey("email", email), 1, lockout)
+	pipe.Del(ctx, failKey("email", email))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to lock email: %w", err)
+	}
+	return lockout, nil
+}
+
+// CaptchaRequired reports whether the email or IP failed often enough that the next login needs a CAPTCHA.
+// A lockout clears the failures, so after it ends a CAPTCHA is only required again after CaptchaAfter failures.
+func (g *Guard) CaptchaRequired(ctx context.Context, email, ip string) (bool, error) {
+	opts := g.opts.Load()
+	failures, err := g.client.MGet(ctx, failKey("email", normalizeEmail(email)), failKey("ip", ip)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to read login failures: %w", err)
+	}
+	for _, value := range failures {
+		count, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(count); err == nil && n >= opts.CaptchaAfter {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Reset forgets the failures and lockouts of an email after a successful login or an unlock.
+// It reports whether the email was locked.
+func (g *Guard) Reset(ctx context.Context, email string) (bool, error) {
+	email = normalizeEmail(email)
+
+	pipe := g.client.TxPipeline()
+	locked := pipe.Exists(ctx, lockKey("email", email))
+	pipe.Del(ctx, lockKey("email", email), failKey("email", email), strikeKey(email))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("failed to reset login guard: %w", err)
+	}
+	return locked.Val() > 0, nil
+}
+
+func failKey(kind, value string) string {
+	return fmt.Sprintf("%s:fail:%s:%s", keyPrefix, kind, value)
+}
+
+func lockKey(kind, value string) string {
+	return fmt.Sprintf("%s:lock:%s:%s", keyPrefix, kind, value)
+}
+
+func strikeKey(email string) string {
+	return fmt.Sprintf("%s:strikes:%s", keyPrefix, email)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,232 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix = "ops_metrics"
+	// Counters are kept in hourly buckets, the oldest bucket still readable is 7 days old
+	bucketTTL = 8 * 24 * time.Hour
+	// maxPanics is how many recent panics are kept
+	maxPanics = 50
+)
+
+// Counter names
+const (
+	counterAuthFailures    = "auth_failures"     // member: client IP
+	counterWebhookSigFails = "webhook_sig_fails" // member: payment provider
+	counterErrorRoutes     = "error_routes"      // member: "<status class> <method> <route>"
+	counterStatusClasses   = "status_classes"    // member: "4xx" / "5xx"
+	listRecentPanics       = "recent_panics"
+)
+
+// PanicEvent is a recovered panic
+type PanicEvent struct {
+	OccurredAt time.Time `json:"occurred_at"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Error      string    `json:"error"`
+}
+
+// CountEntry is one aggregated counter member
+type CountEntry struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// RouteErrorEntry is an error-producing route
+type RouteErrorEntry struct {
+	Method      string `json:"method"`
+	Route       string `json:"route"`
+	StatusClass string `json:"status_class"`
+	Count       int64  `json:"count"`
+}
+
+// Summary aggregates the counters of a time window
+type Summary struct {
+	WindowStart       time.Time         `json:"window_start"`
+	WindowEnd         time.Time         `json:"window_end"`
+	AuthFailuresByIP  []CountEntry      `json:"auth_failures_by_ip"`
+	WebhookSigFailure []CountEntry      `json:"webhook_signature_failures"`
+	TopErrorRoutes    []RouteErrorEntry `json:"top_error_routes"`
+	Total4xx          int64             `json:"total_4xx"`
+	Total5xx          int64             `json:"total_5xx"`
+	RecentPanics      []PanicEvent      `json:"recent_panics"`
+}
+
+// Recorder stores lightweight ops counters in Redis
+// Recording is best effort, a Redis error must never fail the request being measured
+type Recorder struct {
+	client *redis.Client
+}
+
+// NewRecorder creates a new Redis backed recorder
+func NewRecorder(client *redis.Client) *Recorder {
+	return &Recorder{client: client}
+}
+
+// RecordAuthFailure counts a failed authentication from an IP
+func (r *Recorder) RecordAuthFailure(ctx context.Context, ip string) {
+	r.incr(ctx, counterAuthFailures, ip)
+}
+
+// RecordWebhookSignatureFailure counts a webhook that failed signature verification
+func (r *Recorder) RecordWebhookSignatureFailure(ctx context.Context, provider string) {
+	r.incr(ctx, counterWebhookSigFails, provider)
+}
+
+// RecordHTTPError counts a 4xx/5xx response of a route
+func (r *Recorder) RecordHTTPError(ctx context.Context, method, route string, status int) {
+	if status < 400 {
+		return
+	}
+	class := statusClass(status)
+	r.incr(ctx, counterStatusClasses, class)
+	r.incr(ctx, counterErrorRoutes, class+" "+method+" "+route)
+}
+
+// RecordPanic keeps a recovered panic in the recent panics list
+func (r *Recorder) RecordPanic(ctx context.Context, event PanicEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	key := keyPrefix + ":" + listRecentPanics
+	pipe := r.client.Pipeline()
+	pipe.LPush(ctx, key, payload)
+	pipe.LTrim(ctx, key, 0, maxPanics-1)
+	pipe.Exec(ctx)
+}
+
+// Summary aggregates the counters of the last window, keeping the top entries of each counter
+func (r *Recorder) Summary(ctx context.Context, window time.Duration, top int) (*Summary, error) {
+	end := time.Now().UTC()
+	start := end.Add(-window)
+
+	authFailures, err := r.aggregate(ctx, counterAuthFailures, start, end)
+	if err != nil {
+		return nil, err
+	}
+	webhookFailures, err := r.aggregate(ctx, counterWebhookSigFails, start, end)
+	if err != nil {
+		return nil, err
+	}
+	errorRoutes, err := r.aggregate(ctx, counterErrorRoutes, start, end)
+	if err != nil {
+		return nil, err
+	}
+	statusClasses, err := r.aggregate(ctx, counterStatusClasses, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		WindowStart:       start,
+		WindowEnd:         end,
+		AuthFailuresByIP:  topEntries(authFailures, top),
+		WebhookSigFailure: topEntries(webhookFailures, top),
+		TopErrorRoutes:    []RouteErrorEntry{},
+		Total4xx:          statusClasses["4xx"],
+		Total5xx:          statusClasses["5xx"],
+		RecentPanics:      []PanicEvent{},
+	}
+
+	for _, entry := range topEntries(errorRoutes, top) {
+		parts := strings.SplitN(entry.Key, " ", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		summary.TopErrorRoutes = append(summary.TopErrorRoutes, RouteErrorEntry{
+			StatusClass: parts[0],
+			Method:      parts[1],
+			Route:       parts[2],
+			Count:       entry.Count,
+		})
+	}
+
+	panics, err := r.client.LRange(ctx, keyPrefix+":"+listRecentPanics, 0, maxPanics-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recent panics: %w", err)
+	}
+	for _, raw := range panics {
+		var event PanicEvent
+		if json.Unmarshal([]byte(raw), &event) != nil || event.OccurredAt.Before(start) {
+			continue
+		}
+		summary.RecentPanics = append(summary.RecentPanics, event)
+	}
+
+	return summary, nil
+}
+
+// incr increments a member of the current hourly bucket of a counter
+func (r *Recorder) incr(ctx context.Context, counter, member string) {
+	if member == "" {
+		member = "unknown"
+	}
+
+	key := bucketKey(counter, time.Now().UTC())
+	pipe := r.client.Pipeline()
+	pipe.ZIncrBy(ctx, key, 1, member)
+	pipe.Expire(ctx, key, bucketTTL)
+	pipe.Exec(ctx)
+}
+
+// aggregate sums the hourly buckets of a counter between start and end
+func (r *Recorder) aggregate(ctx context.Context, counter string, start, end time.Time) (map[string]int64, error) {
+	totals := make(map[string]int64)
+
+	for hour := start.Truncate(time.Hour); !hour.After(end); hour = hour.Add(time.Hour) {
+		members, err := r.client.ZRangeWithScores(ctx, bucketKey(counter, hour), 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s counters: %w", counter, err)
+		}
+		for _, m := range members {
+			name, _ := m.Member.(string)
+			totals[name] += int64(m.Score)
+		}
+	}
+
+	return totals, nil
+}
+
+// bucketKey is the Redis key of the hourly bucket containing t
+func bucketKey(counter string, t time.Time) string {
+	return keyPrefix + ":" + counter + ":" + t.Format("2006010215")
+}
+
+// topEntries returns the members with the highest counts
+func topEntries(totals map[string]int64, top int) []CountEntry {
+	entries := make([]CountEntry, 0, len(totals))
+	for key, count := range totals {
+		entries = append(entries, CountEntry{Key: key, Count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Key < entries[j].Key
+	})
+
+	if top > 0 && len(entries) > top {
+		entries = entries[:top]
+	}
+	return entries
+}
+
+// statusClass returns "4xx" or "5xx"
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,107 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// ProviderHTTP is the provider accepted in moderation.provider
+const ProviderHTTP = "http"
+
+// Verdict is the outcome of a scan, flagged content waits for an admin before it becomes public
+type Verdict struct {
+	Flagged bool   `json:"flagged"`
+	Reason  string `json:"reason"` // shown to admins, e.g. "nudity" or "malware"
+}
+
+// Moderator scans user and staff content for prohibited material before it is published
+type Moderator interface {
+	Name() string
+	CheckText(ctx context.Context, text string) (Verdict, error)
+	CheckImage(ctx context.Context, image io.Reader, contentType string) (Verdict, error)
+}
+
+// New returns the configured moderator, nil when moderation is disabled
+func New(cfg config.ModerationConfig) (Moderator, error) {
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "":
+		return nil, nil
+	case ProviderHTTP:
+		if cfg.ServiceURL == "" {
+			return nil, fmt.Errorf("moderation.service_url is required for the http provider")
+		}
+		return &HTTPService{
+			url:    cfg.ServiceURL,
+			token:  cfg.Token,
+			client: &http.Client{Timeout: timeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown moderation provider %q", cfg.Provider)
+	}
+}
+
+// HTTPService posts the content to a moderation endpoint that answers with a Verdict as JSON.
+// Text is sent as text/plain, images with their own content type, the token as Bearer token.
+type HTTPService struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// Name identifies the provider on held content
+func (s *HTTPService) Name() string {
+	return ProviderHTTP
+}
+
+// CheckText scans a comment or review
+func (s *HTTPService) CheckText(ctx context.Context, text string) (Verdict, error) {
+	return s.check(ctx, strings.NewReader(text), "text/plain; charset=utf-8")
+}
+
+// CheckImage scans an uploaded image, e.g. a poster
+func (s *HTTPService) CheckImage(ctx context.Context, image io.Reader, contentType string) (Verdict, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return s.check(ctx, image, contentType)
+}
+
+func (s *HTTPService) check(ctx context.Context, body io.Reader, contentType string) (Verdict, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, body)
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to call moderation service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return Verdict{}, fmt.Errorf("moderation service returned status %d", resp.StatusCode)
+	}
+
+	var verdict Verdict
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&verdict); err != nil {
+		return Verdict{}, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+	return verdict, nil
+}
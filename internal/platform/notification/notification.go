@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// Event types, each is rendered with its template in templates.go
+const (
+	EventPaymentConfirmed     = "payment_confirmed"     // receipt of a paid order, to the customer
+	EventAccessExpiring       = "access_expiring"       // a rental or complimentary access ends soon, to the viewer
+	EventTranscodingCompleted = "transcoding_completed" // a movie is ready to stream, to the admin who uploaded it
+	EventPasswordReset        = "password_reset"        // reset link, to the account owner
+)
+
+// Event is a notification queued for delivery by the worker, Data fills the template of its type
+type Event struct {
+	ID   string            `json:"id,omitempty"`
+	Type string            `json:"type"`
+	To   []string          `json:"to"`
+	Data map[string]string `json:"data"`
+}
+
+// Sender delivers a rendered notification. The SMTP mailer is the default sender,
+// other channels plug in by implementing the same method.
+type Sender interface {
+	Send(to []string, subject, body string) error
+}
+
+// Publisher queues events for the worker, implemented by the Redis queue
+type Publisher interface {
+	PublishNotification(ctx context.Context, event Event) error
+}
+
+// Notifier queues notifications, the request that triggers one does not wait for the mail server
+type Notifier struct {
+	publisher Publisher
+}
+
+// NewNotifier creates a notifier that queues events with the publisher
+func NewNotifier(publisher Publisher) *Notifier {
+	return &Notifier{publisher: publisher}
+}
+
+// Notify queues an event. It is rendered once here so an event missing template data
+// fails in the caller instead of in the worker.
+func (n *Notifier) Notify(ctx context.Context, event Event) error {
+	if len(event.To) == 0 {
+		return errors.New("notification has no recipients")
+	}
+	if _, _, err := Render(event); err != nil {
+		return err
+	}
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	if err := n.publisher.PublishNotification(ctx, event); err != nil {
+		return fmt.Errorf("failed to queue %s notification: %w", event.Type, err)
+	}
+	return nil
+}
+
+// Dispatcher renders queued events and hands them to the sender, run by the worker
+type Dispatcher struct {
+	sender Sender
+}
+
+// NewDispatcher creates a dispatcher delivering through the sender
+func NewDispatcher(sender Sender) *Dispatcher {
+	return &Dispatcher{sender: sender}
+}
+
+// Deliver renders an event with the template of its type and sends it
+func (d *Dispatcher) Deliver(ctx context.Context, event Event) error {
+	subject, body, err := Render(event)
+	if err != nil {
+		return err
+	}
+	if err := d.sender.Send(event.To, subject, body); err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", event.Type, err)
+	}
+	return nil
+}
+
+// LogSender only logs notifications, for environments that must not send emails
+type LogSender struct {
+	logger zerolog.Logger
+}
+
+// NewLogSender creates a sender writing notifications to logger
+func NewLogSender(logger zerolog.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Send logs the recipients and subject of a notification
+func (s *LogSender) Send(to []string, subject, body string) error {
+	s.logger.Info().Strs("to", to).Str("subject", subject).Msg("Notification logged, not sent (log sender)")
+	return nil
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// eventTemplate is the subject and plain text body of an event type.
+// Data keys are lowercase, a key missing from the event data is an error.
+type eventTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var templates = map[string]eventTemplate{
+	EventPaymentConfirmed: newEventTemplate(EventPaymentConfirmed,
+		`Your CineStream receipt {{.invoice_number}}`,
+		`Hi {{.name}},
+
+Thank you for your payment. Your receipt {{.invoice_number}}:
+
+{{.movie_title}} ({{.order_type}})
+Total paid: {{.amount}}
+
+You can download the receipt from your order history at any time.`),
+
+	EventAccessExpiring: newEventTemplate(EventAccessExpiring,
+		`Your access to {{.movie_title}} ends soon`,
+		`Hi {{.name}},
+
+Your access to {{.movie_title}} ends on {{.expires_at}}.
+Finish watching before then, afterwards the movie has to be rented again.`),
+
+	EventTranscodingCompleted: newEventTemplate(EventTranscodingCompleted,
+		`{{.movie_title}} is ready to stream`,
+		`Hi {{.name}},
+
+The video you uploaded for {{.movie_title}} (movie #{{.movie_id}}) finished processing and is ready to stream.`),
+
+	EventPasswordReset: newEventTemplate(EventPasswordReset,
+		`Reset your CineStream password`,
+		`Hi {{.name}},
+
+{{if eq .forced "true"}}For your security you need to choose a new CineStream password before you can sign in again.{{else}}We received a request to reset your CineStream password.{{end}}
+Open the link below to choose a new password. It expires in {{.expires_in}} and can only be used once.
+
+{{.link}}
+
+{{if eq .forced "true"}}Until then signing in is not possible.{{else}}If you did not request this, you can ignore this email.{{end}}`),
+}
+
+func newEventTemplate(eventType, subject, body string) eventTemplate {
+	return eventTemplate{
+		subject: template.Must(template.New(eventType + "_subject").Option("missingkey=error").Parse(subject)),
+		body:    template.Must(template.New(eventType + "_body").Option("missingkey=error").Parse(body)),
+	}
+}
+
+// Render returns the subject and body of an event
+func Render(event Event) (string, string, error) {
+	tmpl, ok := templates[event.Type]
+	if !ok {
+		return "", "", fmt.Errorf("unknown notification type %q", event.Type)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, event.Data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s subject: %w", event.Type, err)
+	}
+	if err := tmpl.body.Execute(&body, event.Data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s body: %w", event.Type, err)
+	}
+	// Headers cannot span lines
+	return strings.TrimSpace(strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(subject.String())), body.String(), nil
+}
@@ -1,46 +1,81 @@
 package payment
 
 import (
+	"context"
 	"crypto/sha512"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/martinmanurung/cinestream/pkg/tracing"
 	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
 )
 
-// PaymentService defines the interface for payment operations
-type PaymentService interface {
-	CreateTransaction(orderID int64, amount float64, userEmail, userName string) (string, string, error)
-	VerifySignature(orderID, statusCode, grossAmount, serverKey string, signatureKey string) bool
-}
+// ProviderMidtrans is the provider key of Midtrans Snap
+const ProviderMidtrans = "midtrans"
 
 type midtransService struct {
 	client       snap.Client
+	coreClient   coreapi.Client
 	serverKey    string
 	isProduction bool
 }
 
+// midtransNotification represents the webhook payload from Midtrans
+type midtransNotification struct {
+	TransactionStatus string `json:"transaction_status"`
+	OrderID           string `json:"order_id"`
+	GrossAmount       string `json:"gross_amount"`
+	StatusCode        string `json:"status_code"`
+	SignatureKey      string `json:"signature_key"`
+	PaymentType       string `json:"payment_type"`
+	TransactionID     string `json:"transaction_id"`
+	FraudStatus       string `json:"fraud_status"`
+	TransactionTime   string `json:"transaction_time"`
+}
+
 // NewMidtransService creates a new Midtrans payment service
 func NewMidtransService(serverKey, clientKey string, isProduction bool) PaymentService {
 	var client snap.Client
+	var coreClient coreapi.Client
 	client.New(serverKey, midtrans.Sandbox)
+	coreClient.New(serverKey, midtrans.Sandbox)
 
 	if isProduction {
 		client.New(serverKey, midtrans.Production)
+		coreClient.New(serverKey, midtrans.Production)
 	}
 
 	return &midtransService{
 		client:       client,
+		coreClient:   coreClient,
 		serverKey:    serverKey,
 		isProduction: isProduction,
 	}
 }
 
+// Name returns the provider key
+func (s *midtransService) Name() string {
+	return ProviderMidtrans
+}
+
 // CreateTransaction creates a new payment transaction with Midtrans
-func (s *midtransService) CreateTransaction(orderID int64, amount float64, userEmail, userName string) (string, string, error) {
+func (s *midtransService) CreateTransaction(ctx context.Context, orderID int64, amount float64, userEmail, userName string, expiresAt time.Time) (string, string, error) {
 	// Generate unique order ID for Midtrans
-	orderIDStr := fmt.Sprintf("ORD-%d", orderID)
+	orderIDStr := midtransOrderID(orderID)
+
+	// Snap expiry is a start time plus whole minutes, the start is chosen so it ends exactly at expiresAt
+	expiryMinutes := int64(time.Until(expiresAt).Round(time.Minute) / time.Minute)
+	if expiryMinutes < 1 {
+		expiryMinutes = 1
+	}
+	expiryStart := expiresAt.Add(-time.Duration(expiryMinutes) * time.Minute)
 
 	// Create Snap request
 	req := &snap.Request{
@@ -53,6 +88,11 @@ func (s *midtransService) CreateTransaction(orderID int64, amount float64, userE
 			FName: userName,
 		},
 		EnabledPayments: snap.AllSnapPaymentType,
+		Expiry: &snap.ExpiryDetails{
+			StartTime: expiryStart.Format("2006-01-02 15:04:05 -0700"),
+			Unit:      "minute",
+			Duration:  expiryMinutes,
+		},
 		Items: &[]midtrans.ItemDetails{
 			{
 				ID:    orderIDStr,
@@ -64,10 +104,11 @@ func (s *midtransService) CreateTransaction(orderID int64, amount float64, userE
 	}
 
 	// Create transaction
-	snapResp, midtransErr := s.client.CreateTransaction(req)
+	snapClient, _ := s.tracedClients(ctx)
+	snapResp, midtransErr := snapClient.CreateTransaction(req)
 
 	if midtransErr != nil {
-		return "", "", fmt.Errorf("failed to create midtrans transaction: %w", midtransErr)
+		return "", "", fmt.Errorf("failed to create midtrans transaction: %w", midtransError(midtransErr))
 	}
 
 	if snapResp == nil {
@@ -85,11 +126,48 @@ func (s *midtransService) CreateTransaction(orderID int64, amount float64, userE
 	return snapResp.RedirectURL, snapResp.Token, nil
 }
 
-// VerifySignature verifies the webhook signature from Midtrans
+// ParseNotification verifies the signature of a Midtrans notification and maps its status
+func (s *midtransService) ParseNotification(header http.Header, body []byte) (*Notification, error) {
+	var n midtransNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("invalid midtrans notification: %w", err)
+	}
+
+	if !s.verifySignature(n.OrderID, n.StatusCode, n.GrossAmount, n.SignatureKey) {
+		return nil, ErrInvalidSignature
+	}
+
+	orderID, err := strconv.ParseInt(strings.TrimPrefix(n.OrderID, "ORD-"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid midtrans order id %q", n.OrderID)
+	}
+
+	outcome := OutcomePending
+	switch n.TransactionStatus {
+	case "capture", "settlement":
+		// Challenged captures stay pending until Midtrans sends the final status
+		if n.FraudStatus == "accept" || n.FraudStatus == "" {
+			outcome = OutcomePaid
+		}
+	case "deny", "cancel", "expire":
+		outcome = OutcomeFailed
+	}
+
+	return &Notification{
+		OrderID:           orderID,
+		TransactionID:     n.TransactionID,
+		TransactionStatus: n.TransactionStatus,
+		FraudStatus:       n.FraudStatus,
+		StatusCode:        n.StatusCode,
+		Outcome:           outcome,
+	}, nil
+}
+
+// verifySignature verifies the webhook signature from Midtrans
 // Formula: SHA512(order_id+status_code+gross_amount+ServerKey)
-func (s *midtransService) VerifySignature(orderID, statusCode, grossAmount, serverKey string, signatureKey string) bool {
+func (s *midtransService) verifySignature(orderID, statusCode, grossAmount, signatureKey string) bool {
 	// Create signature string
-	signatureString := orderID + statusCode + grossAmount + serverKey
+	signatureString := orderID + statusCode + grossAmount + s.serverKey
 
 	// Hash with SHA512
 	hash := sha512.New()
@@ -98,3 +176,112 @@ func (s *midtransService) VerifySignature(orderID, statusCode, grossAmount, serv
 
 	return expectedSignature == signatureKey
 }
+
+// CancelTransaction cancels a pending transaction on Midtrans
+// Orders whose checkout page was never used have no Midtrans transaction, which is not an error
+func (s *midtransService) CancelTransaction(ctx context.Context, orderID int64, paymentRef string) error {
+	_, coreClient := s.tracedClients(ctx)
+	_, midtransErr := coreClient.CancelTransaction(midtransOrderID(orderID))
+	if midtransErr != nil {
+		if midtransErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to cancel midtrans transaction: %w", midtransError(midtransErr))
+	}
+
+	return nil
+}
+
+// CheckTransaction reads the status of the transaction of an order from the Midtrans status API.
+// Midtrans has no transaction list in its Core API, so settlements are checked per order.
+func (s *midtransService) CheckTransaction(ctx context.Context, orderID int64, paymentRef string) (*GatewayTransaction, error) {
+	_, coreClient := s.tracedClients(ctx)
+	resp, midtransErr := coreClient.CheckTransaction(midtransOrderID(orderID))
+	if midtransErr != nil {
+		if midtransErr.StatusCode == http.StatusNotFound {
+			return &GatewayTransaction{}, nil
+		}
+		return nil, fmt.Errorf("failed to check midtrans transaction: %w", midtransError(midtransErr))
+	}
+	// The status API answers unknown orders with HTTP 200 and status_code 404 in the body
+	if resp.StatusCode == "404" {
+		return &GatewayTransaction{}, nil
+	}
+
+	amount, _ := strconv.ParseFloat(resp.GrossAmount, 64)
+	return &GatewayTransaction{
+		Found:    true,
+		Status:   resp.TransactionStatus,
+		Settled:  resp.TransactionStatus == "settlement",
+		Refunded: resp.TransactionStatus == "refund" || resp.TransactionStatus == "partial_refund",
+		Amount:   amount,
+	}, nil
+}
+
+// CheckCredentials asks the status API for an order that does not exist,
+// Midtrans answers 404 to a valid server key and 401 to an invalid one
+func (s *midtransService) CheckCredentials(ctx context.Context) error {
+	_, coreClient := s.tracedClients(ctx)
+	resp, midtransErr := coreClient.CheckTransaction("CREDENTIAL-CHECK")
+	if midtransErr != nil {
+		if midtransErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("midtrans rejected the server key: %w", midtransError(midtransErr))
+	}
+	if resp.StatusCode != "404" && resp.StatusCode != "200" {
+		return fmt.Errorf("midtrans rejected the server key (%s): %s", resp.StatusCode, resp.StatusMessage)
+	}
+	return nil
+}
+
+// RefundTransaction refunds a settled transaction on Midtrans and returns the refund reference
+func (s *midtransService) RefundTransaction(ctx context.Context, orderID int64, paymentRef string, amount float64, reason string) (string, error) {
+	req := &coreapi.RefundReq{
+		RefundKey: fmt.Sprintf("%s-REFUND", midtransOrderID(orderID)),
+		Amount:    int64(amount),
+		Reason:    reason,
+	}
+
+	_, coreClient := s.tracedClients(ctx)
+	resp, midtransErr := coreClient.RefundTransaction(midtransOrderID(orderID), req)
+	if midtransErr != nil {
+		return "", fmt.Errorf("failed to refund midtrans transaction: %w", midtransError(midtransErr))
+	}
+
+	if resp.RefundChargebackUUID != "" {
+		return resp.RefundChargebackUUID, nil
+	}
+	return req.RefundKey, nil
+}
+
+// tracedClients returns copies of the Midtrans clients whose requests carry the trace headers of ctx
+// The SDK does not pass a request context to its HTTP client, so the headers are added by the transport
+func (s *midtransService) tracedClients(ctx context.Context) (snap.Client, coreapi.Client) {
+	httpClient := &midtrans.HttpClientImplementation{
+		HttpClient: &http.Client{
+			Timeout:   midtrans.DefaultHttpTimeout,
+			Transport: tracing.NewTransport(ctx, nil),
+		},
+		Logger: midtrans.GetDefaultLogger(s.client.Env),
+	}
+
+	snapClient := s.client
+	snapClient.HttpClient = httpClient
+	coreClient := s.coreClient
+	coreClient.HttpClient = httpClient
+	return snapClient, coreClient
+}
+
+// midtransError marks connection failures and 5xx responses as gateway outages
+func midtransError(err *midtrans.Error) error {
+	if err.StatusCode == 0 || err.StatusCode >= http.StatusInternalServerError {
+		return unavailable(err)
+	}
+	return err
+}
+
+// midtransOrderID is the order ID sent to Midtrans for an order
+func midtransOrderID(orderID int64) string {
+	return fmt.Sprintf("ORD-%d", orderID)
+}
@@ -0,0 +1,171 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// ErrInvalidSignature is returned when a webhook cannot be authenticated
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrGatewayUnavailable wraps errors caused by the gateway being unreachable or failing on its side,
+// the same request can succeed later
+var ErrGatewayUnavailable = errors.New("payment gateway unavailable")
+
+// unavailable marks err as a gateway outage
+func unavailable(err error) error {
+	return fmt.Errorf("%w: %v", ErrGatewayUnavailable, err)
+}
+
+// Bounds of the checkout link lifetime, Stripe Checkout accepts 30 minutes to 24 hours
+const (
+	MinCheckoutExpiry = 30 * time.Minute
+	MaxCheckoutExpiry = 24 * time.Hour
+
+	// checkoutExpiryMargin covers the time between computing the expiry and the gateway creating the link,
+	// Stripe measures from the session creation and refuses an expiry that arrives as less than 30 minutes
+	checkoutExpiryMargin = time.Minute
+)
+
+// ClampCheckoutExpiry keeps a checkout link lifetime within the bounds every gateway accepts,
+// the shortest lifetime is MinCheckoutExpiry plus a minute
+func ClampCheckoutExpiry(d time.Duration) time.Duration {
+	if d < MinCheckoutExpiry+checkoutExpiryMargin {
+		return MinCheckoutExpiry + checkoutExpiryMargin
+	}
+	if d > MaxCheckoutExpiry {
+		return MaxCheckoutExpiry
+	}
+	return d
+}
+
+// PaymentService defines the interface for payment operations
+// Every payment gateway (Midtrans, Stripe, ...) implements it
+type PaymentService interface {
+	// Name is the provider key used in config, orders.payment_provider and /webhooks/:provider
+	Name() string
+	// CreateTransaction returns the checkout URL and the gateway reference of the transaction
+	// ctx carries the trace headers sent to the gateway, the checkout link stops accepting payments at expiresAt
+	CreateTransaction(ctx context.Context, orderID int64, amount float64, userEmail, userName string, expiresAt time.Time) (string, string, error)
+	CancelTransaction(ctx context.Context, orderID int64, paymentRef string) error
+	RefundTransaction(ctx context.Context, orderID int64, paymentRef string, amount float64, reason string) (string, error)
+	// ParseNotification verifies and decodes a webhook request
+	ParseNotification(header http.Header, body []byte) (*Notification, error)
+}
+
+// GatewayTransaction is the state of the transaction of an order on the gateway
+type GatewayTransaction struct {
+	Found    bool    // false when the order never got a transaction, e.g. the checkout page was not opened
+	Status   string  // provider status, e.g. settlement
+	Settled  bool    // the money was settled to the merchant
+	Refunded bool    // fully or partially refunded after the settlement
+	Amount   float64 // gross amount
+}
+
+// SettlementChecker is implemented by gateways that report the settlement of a transaction,
+// the settlement reconciliation skips orders of other gateways
+type SettlementChecker interface {
+	CheckTransaction(ctx context.Context, orderID int64, paymentRef string) (*GatewayTransaction, error)
+}
+
+// CredentialChecker is implemented by gateways that can verify their credentials without a payment,
+// used by the startup self-test
+type CredentialChecker interface {
+	CheckCredentials(ctx context.Context) error
+}
+
+// Outcome is the provider independent result of a payment notification
+type Outcome string
+
+const (
+	OutcomePaid    Outcome = "PAID"
+	OutcomeFailed  Outcome = "FAILED"
+	OutcomePending Outcome = "PENDING" // nothing to apply, only recorded
+)
+
+// Notification is a verified webhook notification
+type Notification struct {
+	OrderID           int64
+	TransactionID     string
+	TransactionStatus string // provider status, part of the idempotency key
+	FraudStatus       string
+	StatusCode        string
+	Outcome           Outcome
+}
+
+// Registry holds the configured payment providers
+// New orders use the default provider, existing orders keep the provider they were created with
+type Registry struct {
+	providers       map[string]PaymentService
+	defaultProvider string
+}
+
+// NewRegistry creates a registry, defaultProvider must be one of the given providers
+func NewRegistry(defaultProvider string, providers ...PaymentService) (*Registry, error) {
+	r := &Registry{
+		providers:       make(map[string]PaymentService, len(providers)),
+		defaultProvider: defaultProvider,
+	}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+
+	if _, ok := r.providers[defaultProvider]; !ok {
+		return nil, fmt.Errorf("payment provider %q is not configured", defaultProvider)
+	}
+	return r, nil
+}
+
+// Default returns the provider used for new orders
+func (r *Registry) Default() PaymentService {
+	return r.providers[r.defaultProvider]
+}
+
+// Get returns a provider by name
+func (r *Registry) Get(name string) (PaymentService, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+	return p, nil
+}
+
+// Providers returns the configured providers, sorted by name
+func (r *Registry) Providers() []PaymentService {
+	providers := make([]PaymentService, 0, len(r.providers))
+	for _, p := range r.providers {
+		providers = append(providers, p)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name() < providers[j].Name() })
+	return providers
+}
+
+// NewRegistryFromConfig registers Midtrans and, when configured, Stripe
+// An empty provider defaults to Midtrans
+func NewRegistryFromConfig(cfg config.PaymentGWConfig) (*Registry, error) {
+	providers := []PaymentService{
+		NewMidtransService(cfg.ServerKey, cfg.ClientKey, cfg.IsProduction),
+	}
+	if cfg.Stripe.SecretKey != "" {
+		providers = append(providers, NewStripeService(
+			cfg.Stripe.SecretKey,
+			cfg.Stripe.WebhookSecret,
+			cfg.Stripe.Currency,
+			cfg.Stripe.SuccessURL,
+			cfg.Stripe.CancelURL,
+		))
+	}
+
+	defaultProvider := cfg.Provider
+	if defaultProvider == "" {
+		defaultProvider = ProviderMidtrans
+	}
+
+	return NewRegistry(defaultProvider, providers...)
+}
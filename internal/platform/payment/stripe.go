@@ -0,0 +1,324 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/pkg/tracing"
+)
+
+// ProviderStripe is the provider key of Stripe Checkout
+const ProviderStripe = "stripe"
+
+const (
+	stripeAPIBaseURL = "https://api.stripe.com/v1"
+	// stripeSignatureTolerance rejects replayed webhooks with an old timestamp
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+type stripeService struct {
+	httpClient    *http.Client
+	secretKey     string
+	webhookSecret string
+	currency      string
+	successURL    string
+	cancelURL     string
+}
+
+// stripeSession is the part of a Checkout Session used by CineStream
+type stripeSession struct {
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	ClientReferenceID string `json:"client_reference_id"`
+	PaymentStatus     string `json:"payment_status"`
+	PaymentIntent     string `json:"payment_intent"`
+	AmountTotal       int64  `json:"amount_total"`
+}
+
+// stripeEvent represents a Stripe webhook event
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object stripeSession `json:"object"`
+	} `json:"data"`
+}
+
+// stripeError is the error body returned by the Stripe API
+type stripeError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewStripeService creates a new Stripe Checkout payment service
+func NewStripeService(secretKey, webhookSecret, currency, successURL, cancelURL string) PaymentService {
+	if currency == "" {
+		currency = "idr"
+	}
+
+	return &stripeService{
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		currency:      strings.ToLower(currency),
+		successURL:    successURL,
+		cancelURL:     cancelURL,
+	}
+}
+
+// Name returns the provider key
+func (s *stripeService) Name() string {
+	return ProviderStripe
+}
+
+// CreateTransaction creates a Stripe Checkout Session and returns its URL and session ID
+func (s *stripeService) CreateTransaction(ctx context.Context, orderID int64, amount float64, userEmail, userName string, expiresAt time.Time) (string, string, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", s.successURL)
+	form.Set("cancel_url", s.cancelURL)
+	form.Set("client_reference_id", strconv.FormatInt(orderID, 10))
+	form.Set("metadata[order_id]", strconv.FormatInt(orderID, 10))
+	form.Set("expires_at", strconv.FormatInt(expiresAt.Unix(), 10))
+	if userEmail != "" {
+		form.Set("customer_email", userEmail)
+	}
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", s.currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(stripeAmount(amount), 10))
+	form.Set("line_items[0][price_data][product_data][name]", "Movie Rental")
+
+	var session stripeSession
+	if err := s.do(ctx, http.MethodPost, "/checkout/sessions", form, &session); err != nil {
+		return "", "", fmt.Errorf("failed to create stripe checkout session: %w", err)
+	}
+
+	if session.ID == "" || session.URL == "" {
+		return "", "", fmt.Errorf("stripe returned an incomplete checkout session")
+	}
+
+	return session.URL, session.ID, nil
+}
+
+// CheckTransaction reads the payment status of the Checkout Session of an order.
+// Stripe pays out in batches, a paid session counts as settled.
+func (s *stripeService) CheckTransaction(ctx context.Context, orderID int64, paymentRef string) (*GatewayTransaction, error) {
+	if paymentRef == "" {
+		return &GatewayTransaction{}, nil
+	}
+
+	var session stripeSession
+	if err := s.do(ctx, http.MethodGet, "/checkout/sessions/"+url.PathEscape(paymentRef), nil, &session); err != nil {
+		return nil, fmt.Errorf("failed to get stripe checkout session: %w", err)
+	}
+
+	return &GatewayTransaction{
+		Found:   true,
+		Status:  session.PaymentStatus,
+		Settled: session.PaymentStatus == "paid",
+		Amount:  float64(session.AmountTotal) / 100,
+	}, nil
+}
+
+// CancelTransaction expires an open Checkout Session so it can no longer be paid
+// Sessions that are already expired or complete are left as they are
+func (s *stripeService) CancelTransaction(ctx context.Context, orderID int64, paymentRef string) error {
+	if paymentRef == "" {
+		return nil
+	}
+
+	var session stripeSession
+	if err := s.do(ctx, http.MethodGet, "/checkout/sessions/"+url.PathEscape(paymentRef), nil, &session); err != nil {
+		return fmt.Errorf("failed to get stripe checkout session: %w", err)
+	}
+	if session.PaymentStatus == "paid" {
+		return fmt.Errorf("stripe checkout session for order %d is already paid", orderID)
+	}
+
+	err := s.do(ctx, http.MethodPost, "/checkout/sessions/"+url.PathEscape(paymentRef)+"/expire", url.Values{}, &session)
+	if err != nil && !strings.Contains(err.Error(), "status is not open") {
+		return fmt.Errorf("failed to expire stripe checkout session: %w", err)
+	}
+
+	return nil
+}
+
+// RefundTransaction refunds the payment of a Checkout Session and returns the refund ID
+func (s *stripeService) RefundTransaction(ctx context.Context, orderID int64, paymentRef string, amount float64, reason string) (string, error) {
+	var session stripeSession
+	if err := s.do(ctx, http.MethodGet, "/checkout/sessions/"+url.PathEscape(paymentRef), nil, &session); err != nil {
+		return "", fmt.Errorf("failed to get stripe checkout session: %w", err)
+	}
+	if session.PaymentIntent == "" {
+		return "", fmt.Errorf("stripe checkout session for order %d has no payment", orderID)
+	}
+
+	form := url.Values{}
+	form.Set("payment_intent", session.PaymentIntent)
+	form.Set("amount", strconv.FormatInt(stripeAmount(amount), 10))
+	form.Set("metadata[order_id]", strconv.FormatInt(orderID, 10))
+	form.Set("metadata[reason]", reason)
+
+	var refund struct {
+		ID string `json:"id"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/refunds", form, &refund); err != nil {
+		return "", fmt.Errorf("failed to refund stripe payment: %w", err)
+	}
+
+	return refund.ID, nil
+}
+
+// ParseNotification verifies the Stripe-Signature header and maps Checkout Session events
+// Signature: HMAC-SHA256(webhook_secret, timestamp + "." + body)
+func (s *stripeService) ParseNotification(header http.Header, body []byte) (*Notification, error) {
+	if !s.verifySignature(header.Get("Stripe-Signature"), body, time.Now()) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid stripe event: %w", err)
+	}
+
+	session := event.Data.Object
+	orderID, err := strconv.ParseInt(session.ClientReferenceID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("stripe event %s has no order reference", event.ID)
+	}
+
+	outcome := OutcomePending
+	switch event.Type {
+	case "checkout.session.completed":
+		// Delayed payment methods complete the session before the money arrives
+		if session.PaymentStatus == "paid" {
+			outcome = OutcomePaid
+		}
+	case "checkout.session.async_payment_succeeded":
+		outcome = OutcomePaid
+	case "checkout.session.async_payment_failed", "checkout.session.expired":
+		outcome = OutcomeFailed
+	}
+
+	return &Notification{
+		OrderID:           orderID,
+		TransactionID:     session.ID,
+		TransactionStatus: event.Type,
+		FraudStatus:       "",
+		StatusCode:        session.PaymentStatus,
+		Outcome:           outcome,
+	}, nil
+}
+
+// verifySignature checks a "t=<timestamp>,v1=<signature>" Stripe-Signature header
+func (s *stripeService) verifySignature(signatureHeader string, body []byte, now time.Time) bool {
+	if s.webhookSecret == "" || signatureHeader == "" {
+		return false
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if now.Sub(time.Unix(ts, 0)).Abs() > stripeSignatureTolerance {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCredentials reads the account balance, which any valid secret key may do
+func (s *stripeService) CheckCredentials(ctx context.Context) error {
+	var balance struct {
+		Object string `json:"object"`
+	}
+	if err := s.do(ctx, http.MethodGet, "/balance", nil, &balance); err != nil {
+		return fmt.Errorf("stripe rejected the secret key: %w", err)
+	}
+	return nil
+}
+
+// do sends a form encoded request to the Stripe API and decodes the JSON response into out
+func (s *stripeService) do(ctx context.Context, method, path string, form url.Values, out interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, stripeAPIBaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.secretKey, "")
+	tracing.Inject(ctx, req.Header)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return unavailable(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return unavailable(fmt.Errorf("stripe api error (%d)", resp.StatusCode))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr stripeError
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe api error (%d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe api error (%d)", resp.StatusCode)
+	}
+
+	return json.Unmarshal(respBody, out)
+}
+
+// stripeAmount converts an amount to the smallest currency unit, Stripe treats IDR as a two-decimal currency
+func stripeAmount(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,94 @@
+package pwned
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// defaultBaseURL is the public Pwned Passwords range API
+const defaultBaseURL = "https://api.pwnedpasswords.com/range"
+
+// Client checks passwords against Have I Been Pwned with k-anonymity:
+// only the first 5 hex characters of the SHA-1 hash are sent, the match is done locally
+type Client struct {
+	baseURL  string
+	minCount int
+	client   *http.Client
+}
+
+// New returns a Pwned Passwords client, nil when the breach check is disabled
+func New(cfg config.PasswordsConfig) *Client {
+	if !cfg.CheckBreached {
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	baseURL := cfg.BreachAPIURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	minCount := cfg.MinBreachCount
+	if minCount < 1 {
+		minCount = 1
+	}
+
+	return &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		minCount: minCount,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// IsBreached reports whether the password appeared in known data breaches at least the configured number of times
+func (c *Client) IsBreached(ctx context.Context, password string) (bool, error) {
+	hash := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(hash[:]))
+	prefix, suffix := digest[:5], digest[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padded answers all have a similar size, so the prefix cannot be guessed from the traffic
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to call Pwned Passwords: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("Pwned Passwords returned status %d", resp.StatusCode)
+	}
+
+	// Every line is SUFFIX:COUNT, padding lines have a count of 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || candidate != suffix {
+			continue
+		}
+		seen, err := strconv.Atoi(count)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse Pwned Passwords count: %w", err)
+		}
+		return seen >= c.minCount, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read Pwned Passwords response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,28 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
+)
+
+// AudioJob asks a worker to package an uploaded alternate audio track as an HLS audio rendition
+type AudioJob struct {
+	ID      string           `json:"id,omitempty"`
+	MovieID int64            `json:"movie_id"`
+	TrackID int64            `json:"track_id"`
+	Trace   *tracing.Context `json:"trace,omitempty"`
+}
+
+// PublishAudioJob publishes an alternate audio job to the queue of JobTypeAudio
+func (q *RedisQueue) PublishAudioJob(ctx context.Context, movieID, trackID int64) error {
+	job := AudioJob{ID: uuid.New().String(), MovieID: movieID, TrackID: trackID}
+	if trace, ok := tracing.FromContext(ctx); ok {
+		child := trace.Child()
+		job.Trace = &child
+	}
+
+	_, err := q.Enqueue(ctx, JobTypeAudio, job, JobOptions{})
+	return err
+}
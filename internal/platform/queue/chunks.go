@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
+)
+
+// chunkStateTTL bounds how long progress of an abandoned chunked encode is kept
+const chunkStateTTL = 24 * time.Hour
+
+// ChunkJob asks a worker to encode one chunk of a movie split for chunked encoding
+type ChunkJob struct {
+	ID               string           `json:"id,omitempty"` // kept when a failed chunk is queued again
+	MovieID          int64            `json:"movie_id"`
+	Version          int              `json:"version"`
+	Index            int              `json:"index"`
+	Total            int              `json:"total"`
+	SourceObject     string           `json:"source_object"`
+	ProfileSet       string           `json:"profile_set"`
+	ComplexityFactor float64          `json:"complexity_factor"`
+	ToneMap          bool             `json:"tone_map"`
+	SourceHeight     int              `json:"source_height,omitempty"`
+	SourceKbps       int              `json:"source_kbps,omitempty"`
+	Attempt          int              `json:"attempt"`
+	Trace            *tracing.Context `json:"trace,omitempty"`
+}
+
+// ChunkProgress is the state of the chunks of one output version
+type ChunkProgress struct {
+	Done    int
+	Failure string // error of a chunk that ran out of attempts, empty while all chunks can still finish
+}
+
+// chunkStateKey is the hash holding the finished chunks and the failure of one output version
+func chunkStateKey(movieID int64, version int) string {
+	return fmt.Sprintf("transcoding:chunks:movie-%d:v%d", movieID, version)
+}
+
+// PublishChunkJob publishes a chunk job to the queue of JobTypeChunk
+func (q *RedisQueue) PublishChunkJob(ctx context.Context, job ChunkJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.Trace == nil {
+		if trace, ok := tracing.FromContext(ctx); ok {
+			child := trace.Child()
+			job.Trace = &child
+		}
+	}
+
+	// Chunks count their own attempts, see ChunkJob.Attempt
+	_, err := q.Enqueue(ctx, JobTypeChunk, job, JobOptions{})
+	return err
+}
+
+// MarkChunkDone records a finished chunk, a chunk encoded twice is counted once
+func (q *RedisQueue) MarkChunkDone(ctx context.Context, movieID int64, version int, index int) error {
+	key := chunkStateKey(movieID, version)
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, key, "chunk:"+strconv.Itoa(index), 1)
+	pipe.Expire(ctx, key, chunkStateTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to mark chunk done: %w", err)
+	}
+	return nil
+}
+
+// MarkChunkFailed records that a chunk ran out of attempts so the coordinator stops waiting
+func (q *RedisQueue) MarkChunkFailed(ctx context.Context, movieID int64, version int, index int, reason string) error {
+	key := chunkStateKey(movieID, version)
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, key, "failure", fmt.Sprintf("chunk %d: %s", index, reason))
+	pipe.Expire(ctx, key, chunkStateTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to mark chunk failed: %w", err)
+	}
+	return nil
+}
+
+// GetChunkProgress returns how many chunks of the output version are done
+func (q *RedisQueue) GetChunkProgress(ctx context.Context, movieID int64, version int) (*ChunkProgress, error) {
+	state, err := q.client.HGetAll(ctx, chunkStateKey(movieID, version)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk progress: %w", err)
+	}
+
+	progress := &ChunkProgress{Failure: state["failure"]}
+	for field := range state {
+		if field != "failure" {
+			progress.Done++
+		}
+	}
+	return progress, nil
+}
+
+// ClearChunkState removes the progress of the output version once it is stitched or abandoned
+func (q *RedisQueue) ClearChunkState(ctx context.Context, movieID int64, version int) error {
+	return q.client.Del(ctx, chunkStateKey(movieID, version)).Err()
+}
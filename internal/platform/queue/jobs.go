@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Delayed jobs and retries wait in a sorted set per queue, scored by the time they run
+	scheduledSuffix = ":scheduled"
+	// Jobs out of attempts are kept in a list per queue for inspection, the newest first
+	deadSuffix = ":dead"
+	deadLimit  = 1000
+
+	// A failed job runs again after retryBackoff, doubled per attempt up to maxRetryBackoff
+	retryBackoff    = 30 * time.Second
+	maxRetryBackoff = 30 * time.Minute
+
+	maxLastErrorLength = 500
+)
+
+// promoteScheduledJobs moves the jobs of a schedule that are due to the queue
+var promoteScheduledJobs = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, entry in ipairs(due) do
+	redis.call('ZREM', KEYS[1], entry)
+	redis.call('LPUSH', KEYS[2], entry)
+end
+return #due
+`)
+
+// JobOptions controls when a job runs and how often it is tried
+type JobOptions struct {
+	RunAt       time.Time // zero or past runs the job right away
+	MaxAttempts int       // runs before the job is given up, 0 runs it once
+}
+
+// NamedQueueState is the backlog of a named queue
+type NamedQueueState struct {
+	Name      string `json:"name"`
+	Pending   int64  `json:"pending"`   // ready to run
+	Scheduled int64  `json:"scheduled"` // delayed or waiting for a retry
+	Dead      int64  `json:"dead"`      // out of attempts, the last deadLimit are kept
+}
+
+// Enqueue adds a job of the type to the queue it is routed to and returns it.
+// A job with RunAt in the future waits in the queue's schedule until PromoteScheduledJobs moves it.
+func (q *RedisQueue) Enqueue(ctx context.Context, jobType string, payload interface{}, opts JobOptions) (*Job, error) {
+	if q.QueueOf(jobType) == "" {
+		return nil, fmt.Errorf("unknown job type %q", jobType)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s job: %w", jobType, err)
+	}
+
+	job := &Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: opts.MaxAttempts,
+	}
+	if opts.RunAt.After(time.Now()) {
+		runAt := opts.RunAt.UTC()
+		job.ScheduledAt = &runAt
+	}
+
+	if err := q.push(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Retry queues a failed job again after a backoff, false when the job used up its attempts.
+// A job out of attempts goes to the dead letter list of its queue.
+func (q *RedisQueue) Retry(ctx context.Context, job Job, cause error) (bool, error) {
+	job.Attempt++
+	job.LastError = cause.Error()
+	if len(job.LastError) > maxLastErrorLength {
+		job.LastError = job.LastError[:maxLastErrorLength]
+	}
+
+	if job.Attempt >= job.MaxAttempts {
+		entry, err := json.Marshal(job)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal %s job: %w", job.Type, err)
+		}
+		key := namedQueuePrefix + q.QueueOf(job.Type) + deadSuffix
+		pipe := q.client.TxPipeline()
+		pipe.LPush(ctx, key, entry)
+		pipe.LTrim(ctx, key, 0, deadLimit-1)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return false, fmt.Errorf("failed to keep dead %s job: %w", job.Type, err)
+		}
+		return false, nil
+	}
+
+	backoff := retryBackoff << (job.Attempt - 1)
+	if backoff > maxRetryBackoff || backoff <= 0 {
+		backoff = maxRetryBackoff
+	}
+	runAt := time.Now().Add(backoff).UTC()
+	job.ScheduledAt = &runAt
+	return true, q.push(ctx, &job)
+}
+
+// Requeue puts a job interrupted by a shutdown back at the head of its queue, it keeps its attempts
+func (q *RedisQueue) Requeue(ctx context.Context, job Job) error {
+	job.ScheduledAt = nil
+	entry, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s job: %w", job.Type, err)
+	}
+	if err := q.client.RPush(ctx, namedQueuePrefix+q.QueueOf(job.Type), entry).Err(); err != nil {
+		return fmt.Errorf("failed to requeue %s job: %w", job.Type, err)
+	}
+	return nil
+}
+
+// PromoteScheduledJobs moves up to limit due jobs per queue from the schedules to the queues, it returns how many.
+// Every worker may call it, a job is moved once.
+func (q *RedisQueue) PromoteScheduledJobs(ctx context.Context, limit int) (int, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	moved := 0
+	for _, name := range q.routes.Names() {
+		key := namedQueuePrefix + name
+		count, err := promoteScheduledJobs.Run(ctx, q.client, []string{key + scheduledSuffix, key}, now, limit).Int()
+		if err != nil {
+			return moved, fmt.Errorf("failed to promote scheduled jobs of queue %s: %w", name, err)
+		}
+		moved += count
+	}
+	return moved, nil
+}
+
+// GetNamedQueueStates returns the backlog of every named queue
+func (q *RedisQueue) GetNamedQueueStates(ctx context.Context) ([]NamedQueueState, error) {
+	names := q.routes.Names()
+	pipe := q.client.Pipeline()
+	pending := make([]*redis.IntCmd, len(names))
+	scheduled := make([]*redis.IntCmd, len(names))
+	dead := make([]*redis.IntCmd, len(names))
+	for i, name := range names {
+		key := namedQueuePrefix + name
+		pending[i] = pipe.LLen(ctx, key)
+		scheduled[i] = pipe.ZCard(ctx, key+scheduledSuffix)
+		dead[i] = pipe.LLen(ctx, key+deadSuffix)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read named queues: %w", err)
+	}
+
+	states := make([]NamedQueueState, len(names))
+	for i, name := range names {
+		states[i] = NamedQueueState{
+			Name:      name,
+			Pending:   pending[i].Val(),
+			Scheduled: scheduled[i].Val(),
+			Dead:      dead[i].Val(),
+		}
+	}
+	return states, nil
+}
+
+// push adds the job to its queue, or to the queue's schedule when it runs later
+func (q *RedisQueue) push(ctx context.Context, job *Job) error {
+	entry, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s job: %w", job.Type, err)
+	}
+
+	key := namedQueuePrefix + q.QueueOf(job.Type)
+	if job.ScheduledAt != nil && job.ScheduledAt.After(time.Now()) {
+		err = q.client.ZAdd(ctx, key+scheduledSuffix, redis.Z{Score: float64(job.ScheduledAt.UnixMilli()), Member: entry}).Err()
+	} else {
+		err = q.client.LPush(ctx, key, entry).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to push %s job to queue: %w", job.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// Short jobs besides transcoding go through named queues, Redis lists each consumed by its own workers
+// with their own concurrency. Every job type is routed to one queue, see Routes. New kinds of work
+// add a job type with its route here and a handler in the worker, see Enqueue and jobs.go.
+const (
+	JobTypeChunk = "chunk" // one chunk of a title split for chunked encoding, see chunks.go
+	JobTypeAudio = "audio" // an uploaded alternate audio track, see audio.go
+	// an email or other notification, see notifications.go
+	JobTypeNotification = "notification"
+
+	QueueChunks        = "chunks"
+	QueueAudio         = "audio"
+	QueueNotifications = "notifications"
+
+	namedQueuePrefix = "queue:"
+)
+
+// Routes maps job types to the named queue they are published to
+type Routes map[string]string
+
+// DefaultRoutes gives every job type a queue of its own
+var DefaultRoutes = Routes{
+	JobTypeChunk:        QueueChunks,
+	JobTypeAudio:        QueueAudio,
+	JobTypeNotification: QueueNotifications,
+}
+
+// legacyQueues are the lists chunk and audio jobs were queued in before the named queues, see SetupConsumer
+var legacyQueues = map[string]string{
+	"transcoding:chunks": JobTypeChunk,
+	"transcoding:audio":  JobTypeAudio,
+}
+
+// moveLegacyTypedJob moves the oldest job of a legacy list to a named queue, wrapped in its envelope
+var moveLegacyTypedJob = redis.NewScript(`
+local payload = redis.call('RPOP', KEYS[1])
+if payload then
+	redis.call('LPUSH', KEYS[2], '{"type":"' .. ARGV[1] .. '","payload":' .. payload .. '}')
+end
+return payload
+`)
+
+// Job is an entry of a named queue, Payload is the job of its type, e.g. a ChunkJob.
+// Jobs queued before retries existed have no ID and run once.
+type Job struct {
+	ID          string          `json:"id,omitempty"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempt     int             `json:"attempt,omitempty"`      // failed runs so far
+	MaxAttempts int             `json:"max_attempts,omitempty"` // runs before the job is given up, 0 runs it once
+	ScheduledAt *time.Time      `json:"scheduled_at,omitempty"` // the job does not run before, set for delayed jobs and retries
+	LastError   string          `json:"last_error,omitempty"`   // error of the last failed run
+}
+
+// Queue returns the named queue jobs of the type are published to
+func (r Routes) Queue(jobType string) string {
+	if name, ok := r[jobType]; ok {
+		return name
+	}
+	return DefaultRoutes[jobType]
+}
+
+// Names returns the queues of all routes, sorted
+func (r Routes) Names() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for jobType := range DefaultRoutes {
+		if name := r.Queue(jobType); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetRoutes overrides the queue of job types, types not in routes keep their default queue.
+// Publishers and consumers of a job type have to use the same routes.
+func (q *RedisQueue) SetRoutes(routes Routes) error {
+	merged := make(Routes, len(DefaultRoutes))
+	for jobType, name := range DefaultRoutes {
+		merged[jobType] = name
+	}
+	for jobType, name := range routes {
+		if _, ok := DefaultRoutes[jobType]; !ok {
+			return fmt.Errorf("unknown job type %q", jobType)
+		}
+		if name == "" {
+			return fmt.Errorf("job type %q has no queue", jobType)
+		}
+		merged[jobType] = name
+	}
+	q.routes = merged
+	return nil
+}
+
+// QueueOf returns the named queue jobs of the type are published to
+func (q *RedisQueue) QueueOf(jobType string) string {
+	return q.routes.Queue(jobType)
+}
+
+// PopJob takes the oldest job of a named queue, nil when none arrived within block.
+// A negative block returns right away.
+func (q *RedisQueue) PopJob(ctx context.Context, queueName string, block time.Duration) (*Job, error) {
+	key := namedQueuePrefix + queueName
+	var data string
+	var err error
+	if block < 0 {
+		data, err = q.client.RPop(ctx, key).Result()
+	} else {
+		var result []string
+		result, err = q.client.BRPop(ctx, block, key).Result()
+		if err == nil {
+			data = result[1]
+		}
+	}
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to pop job from queue %s: %w", queueName, err)
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job of queue %s: %w", queueName, err)
+	}
+	return &job, nil
+}
+
+// Decode unmarshals the payload into the job of its type
+func (j *Job) Decode(dest interface{}) error {
+	if err := json.Unmarshal(j.Payload, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal %s job: %w", j.Type, err)
+	}
+	return nil
+}
+
+// moveLegacyJobs moves chunk and audio jobs left in their old lists to the named queues
+func (q *RedisQueue) moveLegacyJobs(ctx context.Context) error {
+	for legacy, jobType := range legacyQueues {
+		moved := 0
+		for {
+			err := moveLegacyTypedJob.Run(ctx, q.client, []string{legacy, namedQueuePrefix + q.QueueOf(jobType)}, jobType).Err()
+			if errors.Is(err, redis.Nil) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to move legacy %s jobs: %w", jobType, err)
+			}
+			moved++
+		}
+		if moved > 0 {
+			zerolog.Ctx(ctx).Info().Int("jobs", moved).Str("job_type", jobType).Msg("Moved queued jobs from the legacy list to the named queue")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+)
+
+// notificationAttempts is how often the worker tries to send a notification, the mail server may be down for a while
+const notificationAttempts = 4
+
+// PublishNotification publishes a notification to the queue of JobTypeNotification, the worker renders and sends it
+func (q *RedisQueue) PublishNotification(ctx context.Context, event notification.Event) error {
+	_, err := q.Enqueue(ctx, JobTypeNotification, event, JobOptions{MaxAttempts: notificationAttempts})
+	return err
+}
@@ -4,83 +4,253 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/martinmanurung/cinestream/internal/platform/notification"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
 	"github.com/redis/go-redis/v9"
+	zlog "github.com/rs/zerolog/log"
 )
 
 // QueueService defines the interface for queue operations
 type QueueService interface {
 	PublishTranscodingJob(ctx context.Context, movieID int64, rawFilePath string) error
+
+	// Consuming transcoding jobs, see stream.go. A job stays claimed by its worker until it is acknowledged.
 	ConsumeTranscodingJob(ctx context.Context) (*TranscodingJob, error)
+	HoldTranscodingJob(ctx context.Context, job *TranscodingJob) (release func())
+	AckTranscodingJob(ctx context.Context, job *TranscodingJob) error
+
+	// Inspection and manual jobs for the admin API
+	EnqueueTranscodingJob(ctx context.Context, job TranscodingJob, next bool) (*TranscodingJob, error)
+	PeekTranscodingJobs(ctx context.Context, limit int) (*PendingJobs, error)
+	RemoveTranscodingJob(ctx context.Context, jobID string) (bool, error)
+
+	// Named queues of the other job types, see named.go and jobs.go
+	QueueOf(jobType string) string
+	Enqueue(ctx context.Context, jobType string, payload interface{}, opts JobOptions) (*Job, error)
+	PopJob(ctx context.Context, queueName string, block time.Duration) (*Job, error)
+	Retry(ctx context.Context, job Job, cause error) (bool, error)
+	Requeue(ctx context.Context, job Job) error
+	PromoteScheduledJobs(ctx context.Context, limit int) (int, error)
+	GetNamedQueueStates(ctx context.Context) ([]NamedQueueState, error)
+
+	// Chunked encoding, see chunks.go
+	PublishChunkJob(ctx context.Context, job ChunkJob) error
+	MarkChunkDone(ctx context.Context, movieID int64, version int, index int) error
+	MarkChunkFailed(ctx context.Context, movieID int64, version int, index int, reason string) error
+	GetChunkProgress(ctx context.Context, movieID int64, version int) (*ChunkProgress, error)
+	ClearChunkState(ctx context.Context, movieID int64, version int) error
+
+	// Alternate audio tracks, see audio.go
+	PublishAudioJob(ctx context.Context, movieID, trackID int64) error
+
+	// Notifications, see notifications.go
+	PublishNotification(ctx context.Context, event notification.Event) error
 }
 
 type RedisQueue struct {
-	client *redis.Client
+	client   *redis.Client
+	consumer ConsumerOptions
+	routes   Routes
+
+	lastClaim time.Time // last time ConsumeTranscodingJob looked for abandoned jobs
 }
 
+// NewRedisQueue creates a queue that can publish and inspect jobs.
+// Workers call SetupConsumer before consuming transcoding jobs.
 func NewRedisQueue(client *redis.Client) *RedisQueue {
-	return &RedisQueue{client: client}
+	return &RedisQueue{client: client, routes: DefaultRoutes}
 }
 
 // TranscodingJob represents a transcoding job message
 type TranscodingJob struct {
+	// ID identifies the job in the worker's log entries
+	ID          string `json:"id,omitempty"`
 	MovieID     int64  `json:"movie_id"`
 	RawFilePath string `json:"raw_file_path"`
+	// ProfileSet overrides the profile set of the movie, empty uses the movie's
+	ProfileSet string `json:"profile_set,omitempty"`
+	// Profiles narrows the ladder to these quality profiles of the set, empty encodes all of them
+	Profiles []string `json:"profiles,omitempty"`
+	// RequestedBy is the staff member who enqueued the job by hand, empty for uploads
+	RequestedBy string `json:"requested_by,omitempty"`
+	// Trace carries the traceparent/request id of the request that enqueued the job
+	Trace *tracing.Context `json:"trace,omitempty"`
+
+	// Set by ConsumeTranscodingJob: the stream entry to acknowledge and how often it was delivered,
+	// more than once when a worker died while holding it
+	stream     string
+	entryID    string
+	Deliveries int64 `json:"-"`
 }
 
 // PublishTranscodingJob publishes a transcoding job to Redis queue
 func (q *RedisQueue) PublishTranscodingJob(ctx context.Context, movieID int64, rawFilePath string) error {
-	job := TranscodingJob{
-		MovieID:     movieID,
-		RawFilePath: rawFilePath,
+	_, err := q.EnqueueTranscodingJob(ctx, TranscodingJob{MovieID: movieID, RawFilePath: rawFilePath}, false)
+	return err
+}
+
+// EnqueueTranscodingJob adds a job with a new ID to the queue and returns it.
+// With next the job skips the queue and is consumed before every pending job.
+func (q *RedisQueue) EnqueueTranscodingJob(ctx context.Context, job TranscodingJob, next bool) (*TranscodingJob, error) {
+	job.ID = uuid.New().String()
+	job.Trace = nil
+	if trace, ok := tracing.FromContext(ctx); ok {
+		child := trace.Child()
+		job.Trace = &child
 	}
 
 	jobData, err := json.Marshal(job)
 	if err != nil {
-		return fmt.Errorf("failed to marshal job: %w", err)
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
 	}
 
-	// Push to Redis list (queue)
-	queueName := "transcoding:jobs"
-	err = q.client.LPush(ctx, queueName, jobData).Err()
-	if err != nil {
-		return fmt.Errorf("failed to push job to queue: %w", err)
+	stream := transcodingStream
+	if next {
+		stream = transcodingNextStream
+	}
+	if err := q.client.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: map[string]interface{}{jobField: jobData}}).Err(); err != nil {
+		return nil, fmt.Errorf("failed to add job to queue: %w", err)
 	}
 
-	log.Printf("Published transcoding job for movie_id=%d to queue", movieID)
-	return nil
+	event := zlog.Info().Str("job_id", job.ID).Int64("movie_id", job.MovieID)
+	if job.RequestedBy != "" {
+		event = event.Str("requested_by", job.RequestedBy).Bool("next", next)
+	}
+	if job.Trace != nil {
+		event = event.Str("trace_id", job.Trace.TraceID()).Str("request_id", job.Trace.RequestID)
+	}
+	event.Msg("Published transcoding job")
+	return &job, nil
 }
 
-// ConsumeTranscodingJob consumes transcoding jobs from Redis queue (for worker)
-func (q *RedisQueue) ConsumeTranscodingJob(ctx context.Context) (*TranscodingJob, error) {
-	queueName := "transcoding:jobs"
+// PendingJobs is the state of the transcoding queue
+type PendingJobs struct {
+	Total     int64            `json:"total"`     // jobs no worker took yet, also those not listed
+	Jobs      []TranscodingJob `json:"jobs"`      // in the order the workers take them
+	InFlight  []InFlightJob    `json:"in_flight"` // taken but not acknowledged yet
+	Consumers []ConsumerState  `json:"consumers"`
+}
 
-	// Use shorter timeout (5 seconds) instead of blocking forever
-	// This allows the context cancellation to be checked more frequently
-	result, err := q.client.BRPop(ctx, 5*time.Second, queueName).Result()
-	if err != nil {
-		// Check if it's just a timeout (no job available)
-		if err == redis.Nil {
-			return nil, nil // No job available, return nil
+// InFlightJob is a job a worker took and did not acknowledge yet.
+// A job idle for longer than the claim timeout belongs to a worker that died, the next free worker takes it over.
+type InFlightJob struct {
+	Job         TranscodingJob `json:"job"`
+	Consumer    string         `json:"consumer"`
+	IdleSeconds int64          `json:"idle_seconds"` // since the worker's last heartbeat
+	Deliveries  int64          `json:"deliveries"`
+}
+
+// ConsumerState is the backlog of one worker, a worker without heartbeat shows a growing idle time
+type ConsumerState struct {
+	Name        string `json:"name"`
+	Pending     int64  `json:"pending"` // taken but not acknowledged
+	IdleSeconds int64  `json:"idle_seconds"`
+}
+
+// PeekTranscodingJobs returns up to limit jobs no worker took yet, the jobs stay in the queue,
+// along with the jobs in flight and the workers of the consumer group
+func (q *RedisQueue) PeekTranscodingJobs(ctx context.Context, limit int) (*PendingJobs, error) {
+	if err := q.ensureGroups(ctx); err != nil {
+		return nil, err
+	}
+
+	pending := &PendingJobs{Jobs: []TranscodingJob{}, InFlight: []InFlightJob{}, Consumers: []ConsumerState{}}
+	consumers := make(map[string]*ConsumerState)
+	var names []string
+	for _, stream := range transcodingStreams {
+		undelivered, err := q.undelivered(ctx, stream)
+		if err != nil {
+			return nil, err
 		}
-		// Check if context was cancelled
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
+		pending.Total += int64(len(undelivered))
+		for _, message := range undelivered {
+			if len(pending.Jobs) == limit {
+				break
+			}
+			if job, err := decodeJob(message); err == nil {
+				pending.Jobs = append(pending.Jobs, *job)
+			}
+		}
+
+		inFlight, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: stream, Group: transcodingGroup, Start: "-", End: "+", Count: int64(limit),
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jobs in flight: %w", err)
+		}
+		for _, entry := range inFlight {
+			messages, err := q.client.XRangeN(ctx, stream, entry.ID, entry.ID, 1).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read job in flight: %w", err)
+			}
+			if len(messages) == 0 {
+				continue
+			}
+			job, err := decodeJob(messages[0])
+			if err != nil {
+				continue
+			}
+			pending.InFlight = append(pending.InFlight, InFlightJob{
+				Job:         *job,
+				Consumer:    entry.Consumer,
+				IdleSeconds: int64(entry.Idle.Seconds()),
+				Deliveries:  entry.RetryCount,
+			})
 		}
-		return nil, fmt.Errorf("failed to pop job from queue: %w", err)
-	}
 
-	if len(result) < 2 {
-		return nil, fmt.Errorf("invalid queue response")
+		infos, err := q.client.XInfoConsumers(ctx, stream, transcodingGroup).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read consumers: %w", err)
+		}
+		for _, info := range infos {
+			state, ok := consumers[info.Name]
+			if !ok {
+				state = &ConsumerState{Name: info.Name, IdleSeconds: int64(info.Idle.Seconds())}
+				consumers[info.Name] = state
+				names = append(names, info.Name)
+			}
+			state.Pending += info.Pending
+			// A consumer reads both streams, the most recent read counts
+			if idle := int64(info.Idle.Seconds()); idle < state.IdleSeconds {
+				state.IdleSeconds = idle
+			}
+		}
 	}
+	for _, name := range names {
+		pending.Consumers = append(pending.Consumers, *consumers[name])
+	}
+	return pending, nil
+}
 
-	jobData := result[1]
-	var job TranscodingJob
-	if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+// RemoveTranscodingJob deletes a job no worker took yet, false when there is no such job.
+// A job a worker already took keeps running.
+func (q *RedisQueue) RemoveTranscodingJob(ctx context.Context, jobID string) (bool, error) {
+	if err := q.ensureGroups(ctx); err != nil {
+		return false, err
 	}
 
-	return &job, nil
+	for _, stream := range transcodingStreams {
+		undelivered, err := q.undelivered(ctx, stream)
+		if err != nil {
+			return false, err
+		}
+		for _, message := range undelivered {
+			job, err := decodeJob(message)
+			if err != nil || job.ID != jobID {
+				continue
+			}
+			removed, err := q.client.XDel(ctx, stream, message.ID).Result()
+			if err != nil {
+				return false, fmt.Errorf("failed to remove job: %w", err)
+			}
+			if removed > 0 {
+				zlog.Info().Str("job_id", job.ID).Int64("movie_id", job.MovieID).Msg("Removed transcoding job")
+			}
+			return removed > 0, nil
+		}
+	}
+	return false, nil
 }
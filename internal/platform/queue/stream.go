@@ -0,0 +1,295 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// Transcoding jobs are entries of Redis streams read by one consumer group, so a job a worker
+// took stays pending until the worker acknowledges it. Jobs of a worker that died are taken
+// over by the next free worker once their heartbeat stopped for the claim timeout.
+const (
+	transcodingStream     = "transcoding:stream"
+	transcodingNextStream = "transcoding:stream:next" // jobs enqueued to run before all others
+	transcodingGroup      = "transcoding-workers"
+	jobField              = "job"
+
+	// staleConsumerIdle is how long a worker without jobs has to be gone before it is removed from the group
+	staleConsumerIdle = 24 * time.Hour
+
+	// legacyTranscodingQueue is the list jobs were queued in before the streams, see SetupConsumer
+	legacyTranscodingQueue = "transcoding:jobs"
+)
+
+// transcodingStreams are read in this order
+var transcodingStreams = []string{transcodingNextStream, transcodingStream}
+
+// moveLegacyJob moves the oldest job of the legacy list to the stream in one step
+var moveLegacyJob = redis.NewScript(`
+local job = redis.call('RPOP', KEYS[1])
+if job then
+	redis.call('XADD', KEYS[2], '*', ARGV[1], job)
+end
+return job
+`)
+
+// ConsumerOptions identify a worker in the consumer group
+type ConsumerOptions struct {
+	Name string // unique per worker process, e.g. host name and pid
+	// ClaimIdle is how long a job may go without heartbeat before another worker takes it over.
+	// Workers send a heartbeat every third of it while they hold a job.
+	ClaimIdle time.Duration
+}
+
+// SetupConsumer creates the consumer group and moves jobs left in the legacy lists to the stream
+// and the named queues. It must be called before ConsumeTranscodingJob.
+func (q *RedisQueue) SetupConsumer(ctx context.Context, options ConsumerOptions) error {
+	if options.Name == "" || options.ClaimIdle <= 0 {
+		return fmt.Errorf("consumer needs a name and a positive claim timeout")
+	}
+	q.consumer = options
+
+	if err := q.ensureGroups(ctx); err != nil {
+		return err
+	}
+
+	moved := 0
+	for {
+		err := moveLegacyJob.Run(ctx, q.client, []string{legacyTranscodingQueue, transcodingStream}, jobField).Err()
+		if errors.Is(err, redis.Nil) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to move legacy jobs: %w", err)
+		}
+		moved++
+	}
+	if moved > 0 {
+		zerolog.Ctx(ctx).Info().Int("jobs", moved).Msg("Moved queued jobs from the legacy list to the stream")
+	}
+	if err := q.moveLegacyJobs(ctx); err != nil {
+		return err
+	}
+
+	// Every worker process joins under a new name, names of long gone workers without jobs are dropped
+	for _, stream := range transcodingStreams {
+		consumers, err := q.client.XInfoConsumers(ctx, stream, transcodingGroup).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read consumers: %w", err)
+		}
+		for _, consumer := range consumers {
+			if consumer.Pending == 0 && consumer.Idle > staleConsumerIdle {
+				if err := q.client.XGroupDelConsumer(ctx, stream, transcodingGroup, consumer.Name).Err(); err != nil {
+					return fmt.Errorf("failed to remove consumer %s: %w", consumer.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ensureGroups creates the streams and their consumer group, jobs added before the group are delivered too
+func (q *RedisQueue) ensureGroups(ctx context.Context) error {
+	for _, stream := range transcodingStreams {
+		err := q.client.XGroupCreateMkStream(ctx, stream, transcodingGroup, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create consumer group: %w", err)
+		}
+	}
+	return nil
+}
+
+// ConsumeTranscodingJob takes the next job, nil when none arrived within 5 seconds.
+// Jobs abandoned by dead workers go first once found, then jobs enqueued as next, then the others.
+// The job has to be acknowledged with AckTranscodingJob once it is done.
+func (q *RedisQueue) ConsumeTranscodingJob(ctx context.Context) (*TranscodingJob, error) {
+	if q.consumer.Name == "" {
+		return nil, fmt.Errorf("consumer not set up")
+	}
+
+	// Looking for abandoned jobs twice per claim timeout is enough to find each in time
+	if time.Since(q.lastClaim) >= q.consumer.ClaimIdle/2 {
+		job, err := q.claimAbandoned(ctx)
+		if err != nil || job != nil {
+			return job, err
+		}
+		q.lastClaim = time.Now()
+	}
+
+	job, err := q.readNew(ctx, transcodingNextStream, -1)
+	if err != nil || job != nil {
+		return job, err
+	}
+	// Use shorter timeout (5 seconds) instead of blocking forever
+	// This allows the context cancellation to be checked more frequently
+	return q.readNew(ctx, transcodingStream, 5*time.Second)
+}
+
+// readNew reads a job no worker took yet, a negative block returns right away
+func (q *RedisQueue) readNew(ctx context.Context, stream string, block time.Duration) (*TranscodingJob, error) {
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    transcodingGroup,
+		Consumer: q.consumer.Name,
+		Streams:  []string{stream, ">"},
+		Count:    1,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		// Check if it's just a timeout (no job available)
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		// Check if context was cancelled
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to read job from queue: %w", err)
+	}
+	if len(streams) == 0 || len(streams[0].Messages) == 0 {
+		return nil, nil
+	}
+
+	return q.takeMessage(ctx, stream, streams[0].Messages[0], 1)
+}
+
+// claimAbandoned takes over a job whose worker stopped sending heartbeats
+func (q *RedisQueue) claimAbandoned(ctx context.Context) (*TranscodingJob, error) {
+	for _, stream := range transcodingStreams {
+		messages, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    transcodingGroup,
+			Consumer: q.consumer.Name,
+			MinIdle:  q.consumer.ClaimIdle,
+			Start:    "0-0",
+			Count:    1,
+		}).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to claim abandoned jobs: %w", err)
+		}
+		if len(messages) == 0 {
+			continue
+		}
+
+		deliveries := int64(1)
+		entries, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: stream, Group: transcodingGroup, Start: messages[0].ID, End: messages[0].ID, Count: 1,
+		}).Result()
+		if err == nil && len(entries) > 0 {
+			deliveries = entries[0].RetryCount
+		}
+
+		job, err := q.takeMessage(ctx, stream, messages[0], deliveries)
+		if job != nil {
+			zerolog.Ctx(ctx).Warn().Str("job_id", job.ID).Int64("movie_id", job.MovieID).Int64("deliveries", deliveries).Msg("Claimed transcoding job of a dead worker")
+		}
+		return job, err
+	}
+	return nil, nil
+}
+
+// takeMessage decodes a delivered message, undecodable messages are acknowledged and dropped
+func (q *RedisQueue) takeMessage(ctx context.Context, stream string, message redis.XMessage, deliveries int64) (*TranscodingJob, error) {
+	job, err := decodeJob(message)
+	if err != nil {
+		if ackErr := q.ack(ctx, stream, message.ID); ackErr != nil {
+			zerolog.Ctx(ctx).Error().Err(ackErr).Str("entry_id", message.ID).Msg("Failed to drop undecodable job")
+		}
+		return nil, err
+	}
+	job.stream = stream
+	job.entryID = message.ID
+	job.Deliveries = deliveries
+	return job, nil
+}
+
+// HoldTranscodingJob sends heartbeats for a job until release is called,
+// so no other worker takes over a job that is still being worked on
+func (q *RedisQueue) HoldTranscodingJob(ctx context.Context, job *TranscodingJob) (release func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(q.consumer.ClaimIdle / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Claiming its own entry resets the idle time without counting a delivery
+				err := q.client.XClaimJustID(ctx, &redis.XClaimArgs{
+					Stream:   job.stream,
+					Group:    transcodingGroup,
+					Consumer: q.consumer.Name,
+					Messages: []string{job.entryID},
+				}).Err()
+				if err != nil && ctx.Err() == nil {
+					zerolog.Ctx(ctx).Warn().Err(err).Str("job_id", job.ID).Msg("Failed to send job heartbeat")
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// AckTranscodingJob marks a job as done and removes it from the stream
+func (q *RedisQueue) AckTranscodingJob(ctx context.Context, job *TranscodingJob) error {
+	if err := q.ack(ctx, job.stream, job.entryID); err != nil {
+		return fmt.Errorf("failed to acknowledge job: %w", err)
+	}
+	return nil
+}
+
+func (q *RedisQueue) ack(ctx context.Context, stream, entryID string) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, stream, transcodingGroup, entryID)
+		pipe.XDel(ctx, stream, entryID)
+		return nil
+	})
+	return err
+}
+
+// undelivered returns the entries of a stream no worker took yet, oldest first
+func (q *RedisQueue) undelivered(ctx context.Context, stream string) ([]redis.XMessage, error) {
+	groups, err := q.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consumer group: %w", err)
+	}
+	lastDelivered := "0-0"
+	for _, group := range groups {
+		if group.Name == transcodingGroup {
+			lastDelivered = group.LastDeliveredID
+		}
+	}
+
+	messages, err := q.client.XRange(ctx, stream, "("+lastDelivered, "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queued jobs: %w", err)
+	}
+	return messages, nil
+}
+
+func decodeJob(message redis.XMessage) (*TranscodingJob, error) {
+	data, ok := message.Values[jobField].(string)
+	if !ok {
+		return nil, fmt.Errorf("queue entry %s has no job", message.ID)
+	}
+	var job TranscodingJob
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	return &job, nil
+}
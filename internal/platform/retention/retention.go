@@ -0,0 +1,178 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// What a rule does with the rows past their retention period
+const (
+	ActionDelete    = "delete"
+	ActionAnonymize = "anonymize"
+)
+
+// Outcome of one rule run
+const (
+	StatusOK     = "OK"
+	StatusFailed = "FAILED"
+)
+
+// Rule applies a target's retention action to its rows older than After.
+// A dry run only counts the rows, so a new rule can be checked against production data first.
+type Rule struct {
+	Target string
+	After  time.Duration
+	DryRun bool
+}
+
+// Report is the outcome of one rule run, kept for privacy and finance audits
+type Report struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Target     string    `json:"target" gorm:"type:varchar(50);not null"`
+	Action     string    `json:"action" gorm:"type:varchar(20);not null"`
+	DryRun     bool      `json:"dry_run" gorm:"not null"`
+	Cutoff     time.Time `json:"cutoff" gorm:"not null"`                  // rows older than this were due
+	Matched    int64     `json:"matched" gorm:"not null"`                 // rows due when the run started
+	Affected   int64     `json:"affected" gorm:"not null"`                // rows deleted or anonymized, 0 for dry runs
+	Status     string    `json:"status" gorm:"type:varchar(20);not null"` // OK or FAILED
+	Error      *string   `json:"error,omitempty" gorm:"type:varchar(500)"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null"`
+	FinishedAt time.Time `json:"finished_at" gorm:"not null"`
+}
+
+// TableName overrides the table name for Report
+func (Report) TableName() string {
+	return "retention_reports"
+}
+
+// Targets returns the names of the built-in targets, sorted
+func Targets() []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateRules rejects unknown targets, rules without a period and targets configured twice
+func ValidateRules(rules []Rule) error {
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if _, ok := targets[rule.Target]; !ok {
+			return fmt.Errorf("unknown retention target %q, must be one of: %s", rule.Target, strings.Join(Targets(), ", "))
+		}
+		if rule.After <= 0 {
+			return fmt.Errorf("retention target %q needs a positive period", rule.Target)
+		}
+		if seen[rule.Target] {
+			return fmt.Errorf("retention target %q is configured twice", rule.Target)
+		}
+		seen[rule.Target] = true
+	}
+	return nil
+}
+
+// Engine runs the retention rules against the database and stores a report per rule
+type Engine struct {
+	db        *gorm.DB
+	rules     []Rule
+	batchSize int
+}
+
+// NewEngine creates an engine, rows are deleted or anonymized batchSize at a time to keep locks short
+func NewEngine(db *gorm.DB, rules []Rule, batchSize int) *Engine {
+	return &Engine{db: db, rules: rules, batchSize: batchSize}
+}
+
+// Rules returns the configured rules
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Run applies every rule once, a failing rule does not stop the others.
+// The reports are returned in rule order, also when storing one of them failed.
+func (e *Engine) Run(ctx context.Context, now time.Time) ([]Report, error) {
+	reports := make([]Report, 0, len(e.rules))
+	var saveErr error
+	for _, rule := range e.rules {
+		if ctx.Err() != nil {
+			break
+		}
+		report := e.runRule(ctx, rule, now)
+		if err := e.db.WithContext(ctx).Create(&report).Error; err != nil && saveErr == nil {
+			saveErr = fmt.Errorf("failed to save %s report: %w", rule.Target, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, saveErr
+}
+
+func (e *Engine) runRule(ctx context.Context, rule Rule, now time.Time) Report {
+	target := targets[rule.Target]
+	report := Report{
+		Target:    rule.Target,
+		Action:    target.action,
+		DryRun:    rule.DryRun,
+		Cutoff:    now.Add(-rule.After).UTC().Truncate(time.Second),
+		Status:    StatusOK,
+		StartedAt: time.Now(),
+	}
+
+	err := func() error {
+		var err error
+		report.Matched, err = target.count(e.db.WithContext(ctx), report.Cutoff, now)
+		if err != nil || rule.DryRun {
+			return err
+		}
+
+		for report.Affected < report.Matched && ctx.Err() == nil {
+			affected, err := target.apply(e.db.WithContext(ctx), report.Cutoff, now, e.batchSize)
+			report.Affected += affected
+			if err != nil {
+				return err
+			}
+			if affected < int64(e.batchSize) {
+				break
+			}
+		}
+		return ctx.Err()
+	}()
+	if err != nil {
+		message := err.Error()
+		if len(message) > 500 {
+			message = message[:500]
+		}
+		report.Status = StatusFailed
+		report.Error = &message
+	}
+
+	report.FinishedAt = time.Now()
+	return report
+}
+
+// Store reads the stored reports
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore creates a report store
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// FindReports returns the latest reports, of one target when target is set
+func (s *Store) FindReports(ctx context.Context, target string, limit int) ([]Report, error) {
+	query := s.db.WithContext(ctx).Order("started_at DESC, id DESC").Limit(limit)
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+	var reports []Report
+	err := query.Find(&reports).Error
+	return reports, err
+}
@@ -0,0 +1,120 @@
+package retention
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AnonymizedUserExtID replaces the user of anonymized orders, amounts and dates stay for the books
+const AnonymizedUserExtID = "anonymized"
+
+// Built-in targets, rules refer to them by name
+const (
+	TargetOrders            = "orders"             // anonymize orders once finance no longer needs the buyer
+	TargetPlaybackTelemetry = "playback_telemetry" // delete stream sessions and rental devices
+	TargetWebhookPayloads   = "webhook_payloads"   // delete processed payment notifications and the payment event log
+)
+
+// target is a data set with a retention action, rows are due once they are older than the cutoff
+type target struct {
+	action string
+	count  func(db *gorm.DB, cutoff, now time.Time) (int64, error)
+	apply  func(db *gorm.DB, cutoff, now time.Time, limit int) (int64, error)
+}
+
+var targets = map[string]target{
+	TargetOrders: {
+		action: ActionAnonymize,
+		count: func(db *gorm.DB, cutoff, now time.Time) (int64, error) {
+			var count int64
+			err := dueOrders(db, cutoff, now).Count(&count).Error
+			return count, err
+		},
+		apply: anonymizeOrders,
+	},
+	TargetPlaybackTelemetry: {
+		action: ActionDelete,
+		count: func(db *gorm.DB, cutoff, now time.Time) (int64, error) {
+			sessions, err := countOlder(db, "stream_sessions", "last_seen_at", cutoff)
+			if err != nil {
+				return 0, err
+			}
+			devices, err := countOlder(db, "access_devices", "last_seen_at", cutoff)
+			return sessions + devices, err
+		},
+		apply: func(db *gorm.DB, cutoff, now time.Time, limit int) (int64, error) {
+			deleted, err := deleteOlder(db, "stream_sessions", "last_seen_at", cutoff, limit)
+			if err != nil || deleted == int64(limit) {
+				return deleted, err
+			}
+			devices, err := deleteOlder(db, "access_devices", "last_seen_at", cutoff, limit-int(deleted))
+			return deleted + devices, err
+		},
+	},
+	TargetWebhookPayloads: {
+		action: ActionDelete,
+		count: func(db *gorm.DB, cutoff, now time.Time) (int64, error) {
+			notifications, err := countOlder(db, "payment_notifications", "processed_at", cutoff)
+			if err != nil {
+				return 0, err
+			}
+			events, err := countOlder(db, "payment_events", "received_at", cutoff)
+			return notifications + events, err
+		},
+		apply: func(db *gorm.DB, cutoff, now time.Time, limit int) (int64, error) {
+			notifications, err := deleteOlder(db, "payment_notifications", "processed_at", cutoff, limit)
+			if err != nil {
+				return 0, err
+			}
+			events, err := deleteOlder(db, "payment_events", "received_at", cutoff, limit)
+			return notifications + events, err
+		},
+	},
+}
+
+// dueOrders are settled orders created before the cutoff that still name their buyer.
+// Orders behind access that has not ended yet, e.g. purchases, keep their buyer.
+func dueOrders(db *gorm.DB, cutoff, now time.Time) *gorm.DB {
+	return db.Table("orders").
+		Where("orders.created_at < ? AND orders.user_ext_id <> ? AND orders.payment_status <> ?", cutoff, AnonymizedUserExtID, "PENDING").
+		Where("NOT EXISTS (SELECT 1 FROM user_movie_access a WHERE a.order_id = orders.id AND (a.access_expires_at IS NULL OR a.access_expires_at > ?))", now)
+}
+
+// anonymizeOrders drops the buyer and checkout link of up to limit due orders, and the buyer of their ended access
+func anonymizeOrders(db *gorm.DB, cutoff, now time.Time, limit int) (int64, error) {
+	var ids []int64
+	if err := dueOrders(db, cutoff, now).Order("orders.id").Limit(limit).Pluck("orders.id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var affected int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("orders").Where("id IN ?", ids).Updates(map[string]interface{}{
+			"user_ext_id":  AnonymizedUserExtID,
+			"checkout_url": nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		return tx.Table("user_movie_access").Where("order_id IN ?", ids).Update("user_ext_id", AnonymizedUserExtID).Error
+	})
+	return affected, err
+}
+
+func countOlder(db *gorm.DB, table, column string, cutoff time.Time) (int64, error) {
+	var count int64
+	err := db.Table(table).Where(column+" < ?", cutoff).Count(&count).Error
+	return count, err
+}
+
+// deleteOlder deletes up to limit rows older than the cutoff, oldest first
+func deleteOlder(db *gorm.DB, table, column string, cutoff time.Time, limit int) (int64, error) {
+	result := db.Exec("DELETE FROM "+table+" WHERE "+column+" < ? ORDER BY "+column+" LIMIT ?", cutoff, limit)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// AlternateAudioPrefix is where the HLS rendition of an uploaded audio track lives in the processed bucket.
+// Like subtitles it sits next to the output versions (movie-{id}/audio/{trackID}/) so re-transcodes keep it.
+func AlternateAudioPrefix(movieID, trackID int64) string {
+	return fmt.Sprintf("movie-%d/audio/%d/", movieID, trackID)
+}
+
+// UploadAlternateAudio stores an uploaded dub or audio description in the raw bucket for the worker
+func (s *StorageService) UploadAlternateAudio(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64) (string, error) {
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	objectName := fmt.Sprintf("alternate-audio/movie-%d/%d%s", movieID, time.Now().UnixNano(), ext)
+
+	if _, err := s.client.PutObject(ctx, s.bucketRaw, objectName, file, fileHeader.Size, minio.PutObjectOptions{
+		ContentType: fileHeader.Header.Get("Content-Type"),
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload audio to MinIO: %w", err)
+	}
+
+	return objectName, nil
+}
+
+// DeleteAlternateAudio removes the uploaded file and the packaged rendition of an audio track
+func (s *StorageService) DeleteAlternateAudio(ctx context.Context, movieID, trackID int64, sourceObject string) error {
+	for object := range s.client.ListObjects(ctx, s.bucketProcessed, minio.ListObjectsOptions{
+		Prefix:    AlternateAudioPrefix(movieID, trackID),
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list audio rendition: %w", object.Err)
+		}
+		if err := s.client.RemoveObject(ctx, s.bucketProcessed, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to delete audio rendition from MinIO: %w", err)
+		}
+	}
+
+	if err := s.client.RemoveObject(ctx, s.bucketRaw, sourceObject, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete uploaded audio from MinIO: %w", err)
+	}
+	return nil
+}
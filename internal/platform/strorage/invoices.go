@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// InvoiceObjectName returns the object name of a receipt in the private media bucket
+func InvoiceObjectName(invoiceNumber, ext string) string {
+	return fmt.Sprintf("invoices/%s.%s", invoiceNumber, ext)
+}
+
+// PutInvoice stores a receipt in the private media bucket, replacing an earlier one of the same name
+func (s *StorageService) PutInvoice(ctx context.Context, objectName, contentType string, data []byte) error {
+	if _, err := s.client.PutObject(ctx, s.bucketMedia, objectName, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	}); err != nil {
+		return fmt.Errorf("failed to upload invoice to MinIO: %w", err)
+	}
+	return nil
+}
+
+// GetInvoice opens a stored receipt
+func (s *StorageService) GetInvoice(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucketMedia, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	// GetObject is lazy, Stat surfaces a missing object before the response starts
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, fmt.Errorf("invoice not found: %w", err)
+	}
+	return object, nil
+}
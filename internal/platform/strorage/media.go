@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// MediaObject describes a poster/trailer object stored in the media bucket
+type MediaObject struct {
+	ContentType  string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// UploadMovieMedia uploads a poster or trailer to the private media bucket
+// Returns the object name, which is stored in place of an absolute URL
+func (s *StorageService) UploadMovieMedia(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64, kind string) (string, error) {
+	// Object name: {kind}s/movie-{id}.ext, e.g. posters/movie-1.jpg
+	ext := filepath.Ext(fileHeader.Filename)
+	objectName := fmt.Sprintf("%ss/movie-%d%s", kind, movieID, ext)
+	return s.putMediaObject(ctx, file, fileHeader, objectName)
+}
+
+// UploadHeldMovieMedia uploads a poster or trailer held back by content moderation next to the live one,
+// so the live file keeps being served until an admin approves the upload
+func (s *StorageService) UploadHeldMovieMedia(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64, kind string) (string, error) {
+	// Object name: held/{kind}s/movie-{id}-{unix nano}.ext, e.g. held/posters/movie-1-1767225600000000000.jpg
+	ext := filepath.Ext(fileHeader.Filename)
+	objectName := fmt.Sprintf("held/%ss/movie-%d-%d%s", kind, movieID, time.Now().UnixNano(), ext)
+	return s.putMediaObject(ctx, file, fileHeader, objectName)
+}
+
+// DeleteMediaObject removes a media object, e.g. a rejected upload
+func (s *StorageService) DeleteMediaObject(ctx context.Context, objectName string) error {
+	if err := s.client.RemoveObject(ctx, s.bucketMedia, objectName, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete media from MinIO: %w", err)
+	}
+	return nil
+}
+
+func (s *StorageService) putMediaObject(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, objectName string) (string, error) {
+	_, err := s.client.PutObject(
+		ctx,
+		s.bucketMedia,
+		objectName,
+		file,
+		fileHeader.Size,
+		minio.PutObjectOptions{
+			ContentType: fileHeader.Header.Get("Content-Type"),
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media to MinIO: %w", err)
+	}
+
+	return objectName, nil
+}
+
+// PresignMediaURL returns a short-lived GET URL for a media object
+func (s *StorageService) PresignMediaURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	presignedURL, err := s.client.PresignedGetObject(ctx, s.bucketMedia, objectName, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign media URL: %w", err)
+	}
+	return presignedURL.String(), nil
+}
+
+// GetMediaObject opens a media object for streaming through the API
+func (s *StorageService) GetMediaObject(ctx context.Context, objectName string) (io.ReadCloser, *MediaObject, error) {
+	object, err := s.client.GetObject(ctx, s.bucketMedia, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get media object: %w", err)
+	}
+
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, nil, fmt.Errorf("media object not found: %w", err)
+	}
+
+	return object, &MediaObject{
+		ContentType:  info.ContentType,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
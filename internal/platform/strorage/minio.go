@@ -1,9 +1,11 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/martinmanurung/cinestream/internal/platform/config" // Sesuaikan path ini
 	"github.com/minio/minio-go/v7"
@@ -13,12 +15,18 @@ import (
 // Initialize minio
 func InitMinIO(cfg config.MinIOConfig) (*minio.Client, error) {
 	// 1. Init minio client
-	minioClient, err := minio.New(cfg.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
-		Secure: cfg.UseSSL,
-	})
+	minioClient, err := NewMinIOClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("error initializing minio client: %w", err)
+		return nil, err
+	}
+
+	// Buckets and policies set up elsewhere, e.g. for scoped keys that cannot manage them
+	if !cfg.ManageBuckets {
+		if err := ProbeBuckets(context.Background(), minioClient, cfg.BucketRaw, cfg.BucketProcessed, cfg.BucketMedia); err != nil {
+			return nil, err
+		}
+		log.Printf("Bucket management disabled, the '%s' bucket has to be public-read.", cfg.BucketProcessed)
+		return minioClient, nil
 	}
 
 	// 2. trying to connect minio
@@ -39,9 +47,63 @@ func InitMinIO(cfg config.MinIOConfig) (*minio.Client, error) {
 		return nil, err
 	}
 
+	// Posters/trailers bucket stays private, served via presigned URLs or the media proxy
+	if cfg.BucketMedia != "" {
+		err = checkAndCreateBucket(minioClient, cfg.BucketMedia, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return minioClient, nil
+}
+
+// NewMinIOClient creates a client without connecting or touching the buckets, e.g. for the self-test
+func NewMinIOClient(cfg config.MinIOConfig) (*minio.Client, error) {
+	minioClient, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing minio client: %w", err)
+	}
 	return minioClient, nil
 }
 
+// ProbeBuckets checks that the credentials can write, read and delete objects in every bucket,
+// which is all the services need once the buckets exist. Empty bucket names are skipped.
+func ProbeBuckets(ctx context.Context, client *minio.Client, buckets ...string) error {
+	for _, bucket := range buckets {
+		if bucket == "" {
+			continue
+		}
+		if err := probeBucket(ctx, client, bucket); err != nil {
+			return fmt.Errorf("error probing access to bucket '%s': %w", bucket, err)
+		}
+	}
+	return nil
+}
+
+// probeBucket writes, reads back and deletes a small object
+func probeBucket(ctx context.Context, client *minio.Client, bucket string) error {
+	objectName := fmt.Sprintf(".probe/%d", time.Now().UnixNano())
+	content := []byte("cinestream")
+
+	if _, err := client.PutObject(ctx, bucket, objectName, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{ContentType: "text/plain"}); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	// The probe is removed even when reading it fails
+	_, statErr := client.StatObject(ctx, bucket, objectName, minio.StatObjectOptions{})
+	removeErr := client.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{})
+	if statErr != nil {
+		return fmt.Errorf("read: %w", statErr)
+	}
+	if removeErr != nil {
+		return fmt.Errorf("delete: %w", removeErr)
+	}
+	return nil
+}
+
 // helper function to create bucket if not ready
 func checkAndCreateBucket(client *minio.Client, bucketName string, isPublic bool) error {
 	ctx := context.Background()
@@ -80,3 +142,8 @@ func checkAndCreateBucket(client *minio.Client, bucketName string, isPublic bool
 	}
 	return nil
 }
+
+// EnsurePrivateBucket creates a private bucket if it does not exist yet, e.g. the backup bucket of the worker
+func EnsurePrivateBucket(client *minio.Client, bucketName string) error {
+	return checkAndCreateBucket(client, bucketName, false)
+}
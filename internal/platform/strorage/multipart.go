@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// UploadedPart describes a part that has already been stored in a multipart upload
+type UploadedPart struct {
+	PartNumber int
+	ETag       string
+	Size       int64
+}
+
+// NewRawVideoMultipartUpload starts a multipart upload in the raw bucket
+// Returns the object name and the MinIO upload ID
+func (s *StorageService) NewRawVideoMultipartUpload(ctx context.Context, movieID int64, fileName, contentType string) (string, string, error) {
+	// Same naming scheme as UploadRawVideo: raw-videos/movie-{id}.ext
+	ext := filepath.Ext(fileName)
+	objectName := fmt.Sprintf(RawVideoPrefix+"movie-%d%s", movieID, ext)
+
+	uploadID, err := s.core.NewMultipartUpload(ctx, s.bucketRaw, objectName, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+
+	return objectName, uploadID, nil
+}
+
+// UploadRawVideoPart streams a single part of a multipart upload directly into MinIO
+func (s *StorageService) UploadRawVideoPart(ctx context.Context, objectName, uploadID string, partNumber int, data io.Reader, size int64) (*UploadedPart, error) {
+	part, err := s.core.PutObjectPart(ctx, s.bucketRaw, objectName, uploadID, partNumber, data, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	return &UploadedPart{
+		PartNumber: part.PartNumber,
+		ETag:       part.ETag,
+		Size:       part.Size,
+	}, nil
+}
+
+// ListRawVideoParts returns all parts already uploaded for a multipart upload
+func (s *StorageService) ListRawVideoParts(ctx context.Context, objectName, uploadID string) ([]UploadedPart, error) {
+	var parts []UploadedPart
+
+	marker := 0
+	for {
+		result, err := s.core.ListObjectParts(ctx, s.bucketRaw, objectName, uploadID, marker, 1000)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list uploaded parts: %w", err)
+		}
+
+		for _, part := range result.ObjectParts {
+			parts = append(parts, UploadedPart{
+				PartNumber: part.PartNumber,
+				ETag:       part.ETag,
+				Size:       part.Size,
+			})
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	return parts, nil
+}
+
+// CompleteRawVideoMultipartUpload assembles all uploaded parts into the final object
+func (s *StorageService) CompleteRawVideoMultipartUpload(ctx context.Context, objectName, uploadID string) error {
+	// Parts are read back from MinIO so clients don't have to track ETags themselves
+	uploaded, err := s.ListRawVideoParts(ctx, objectName, uploadID)
+	if err != nil {
+		return err
+	}
+	if len(uploaded) == 0 {
+		return fmt.Errorf("no parts uploaded")
+	}
+
+	parts := make([]minio.CompletePart, len(uploaded))
+	for i, part := range uploaded {
+		parts[i] = minio.CompletePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		}
+	}
+
+	if _, err := s.core.CompleteMultipartUpload(ctx, s.bucketRaw, objectName, uploadID, parts, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+// AbortRawVideoMultipartUpload aborts a multipart upload and discards its parts
+func (s *StorageService) AbortRawVideoMultipartUpload(ctx context.Context, objectName, uploadID string) error {
+	return s.core.AbortMultipartUpload(ctx, s.bucketRaw, objectName, uploadID)
+}
@@ -6,21 +6,29 @@ import (
 	"io"
 	"mime/multipart"
 	"path/filepath"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 )
 
+// RawVideoPrefix is where the uploaded sources of the movies are stored in the raw bucket, as movie-{id}.ext
+const RawVideoPrefix = "raw-videos/"
+
 type StorageService struct {
 	client          *minio.Client
+	core            *minio.Core
 	bucketRaw       string
 	bucketProcessed string
+	bucketMedia     string
 }
 
-func NewStorageService(client *minio.Client, bucketRaw, bucketProcessed string) *StorageService {
+func NewStorageService(client *minio.Client, bucketRaw, bucketProcessed, bucketMedia string) *StorageService {
 	return &StorageService{
 		client:          client,
+		core:            &minio.Core{Client: client},
 		bucketRaw:       bucketRaw,
 		bucketProcessed: bucketProcessed,
+		bucketMedia:     bucketMedia,
 	}
 }
 
@@ -28,7 +36,7 @@ func NewStorageService(client *minio.Client, bucketRaw, bucketProcessed string)
 func (s *StorageService) UploadRawVideo(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, movieID int64) (string, error) {
 	// Generate object name: raw-videos/movie-{id}.ext
 	ext := filepath.Ext(fileHeader.Filename)
-	objectName := fmt.Sprintf("raw-videos/movie-%d%s", movieID, ext)
+	objectName := fmt.Sprintf(RawVideoPrefix+"movie-%d%s", movieID, ext)
 
 	// Upload to MinIO
 	_, err := s.client.PutObject(
@@ -48,6 +56,32 @@ func (s *StorageService) UploadRawVideo(ctx context.Context, file multipart.File
 	return objectName, nil
 }
 
+// PresignRawVideoUpload returns a presigned PUT URL so clients can upload directly to the raw bucket
+func (s *StorageService) PresignRawVideoUpload(ctx context.Context, movieID int64, fileName string, expiry time.Duration) (string, string, error) {
+	// Same naming scheme as UploadRawVideo: raw-videos/movie-{id}.ext
+	ext := filepath.Ext(fileName)
+	objectName := fmt.Sprintf(RawVideoPrefix+"movie-%d%s", movieID, ext)
+
+	presignedURL, err := s.client.PresignedPutObject(ctx, s.bucketRaw, objectName, expiry)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to presign upload URL: %w", err)
+	}
+
+	return objectName, presignedURL.String(), nil
+}
+
+// RawVideoExists checks whether an object has been uploaded to the raw bucket
+func (s *StorageService) RawVideoExists(ctx context.Context, objectName string) (bool, error) {
+	_, err := s.client.StatObject(ctx, s.bucketRaw, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat raw video: %w", err)
+	}
+	return true, nil
+}
+
 // GetRawVideoURL returns the internal URL for raw video (for worker processing)
 func (s *StorageService) GetRawVideoURL(objectName string) string {
 	return fmt.Sprintf("%s/%s", s.bucketRaw, objectName)
@@ -98,6 +132,22 @@ func (s *StorageService) DeleteProcessedVideo(ctx context.Context, movieID int64
 	return nil
 }
 
+// StreamProcessedFile streams an HLS playlist or segment from the processed bucket
+func (s *StorageService) StreamProcessedFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucketProcessed, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from MinIO: %w", err)
+	}
+
+	// GetObject is lazy, stat it so missing objects surface here instead of mid-stream
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, fmt.Errorf("object not found: %w", err)
+	}
+
+	return object, nil
+}
+
 // StreamFile streams a file from MinIO
 func (s *StorageService) StreamFile(ctx context.Context, bucket, objectName string) (io.ReadCloser, error) {
 	object, err := s.client.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// SubtitleObjectName returns the object name of a subtitle in the processed bucket
+// Subtitles live next to the output versions (movie-{id}/subtitles/{lang}.vtt) so re-transcodes keep them
+func SubtitleObjectName(movieID int64, language, ext string) string {
+	return fmt.Sprintf("movie-%d/subtitles/%s.%s", movieID, language, ext)
+}
+
+// UploadSubtitle stores a WebVTT file and its HLS media playlist in the processed bucket
+// Returns the object name of the WebVTT file
+func (s *StorageService) UploadSubtitle(ctx context.Context, movieID int64, language string, vtt, playlist []byte) (string, error) {
+	vttObject := SubtitleObjectName(movieID, language, "vtt")
+	if _, err := s.client.PutObject(ctx, s.bucketProcessed, vttObject, bytes.NewReader(vtt), int64(len(vtt)), minio.PutObjectOptions{
+		ContentType: "text/vtt",
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload subtitle to MinIO: %w", err)
+	}
+
+	playlistObject := SubtitleObjectName(movieID, language, "m3u8")
+	if _, err := s.client.PutObject(ctx, s.bucketProcessed, playlistObject, bytes.NewReader(playlist), int64(len(playlist)), minio.PutObjectOptions{
+		ContentType: "application/vnd.apple.mpegurl",
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload subtitle playlist to MinIO: %w", err)
+	}
+
+	return vttObject, nil
+}
+
+// DeleteSubtitle removes a subtitle and its playlist from the processed bucket
+func (s *StorageService) DeleteSubtitle(ctx context.Context, movieID int64, language string) error {
+	for _, ext := range []string{"vtt", "m3u8"} {
+		if err := s.client.RemoveObject(ctx, s.bucketProcessed, SubtitleObjectName(movieID, language, ext), minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to delete subtitle from MinIO: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetSubtitleDraft reads a generated subtitle draft from the raw bucket
+func (s *StorageService) GetSubtitleDraft(ctx context.Context, objectName string, maxSize int64) ([]byte, error) {
+	object, err := s.client.GetObject(ctx, s.bucketRaw, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtitle draft: %w", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, maxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subtitle draft: %w", err)
+	}
+	return data, nil
+}
+
+// DeleteSubtitleDraft removes a subtitle draft from the raw bucket
+func (s *StorageService) DeleteSubtitleDraft(ctx context.Context, objectName string) error {
+	if err := s.client.RemoveObject(ctx, s.bucketRaw, objectName, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete subtitle draft from MinIO: %w", err)
+	}
+	return nil
+}
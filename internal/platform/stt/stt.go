@@ -0,0 +1,185 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// Providers accepted in speech_to_text.provider
+const (
+	ProviderWhisper = "whisper"
+	ProviderHTTP    = "http"
+)
+
+// maxTranscriptSize caps the WebVTT read back from a transcriber
+const maxTranscriptSize = 5 << 20
+
+// Transcriber turns speech into WebVTT cues.
+// The audio is a 16 kHz mono WAV file, an empty language lets the model detect it.
+type Transcriber interface {
+	Name() string
+	Transcribe(ctx context.Context, audioPath, language string) ([]byte, error)
+}
+
+// New returns the configured transcriber, nil when speech-to-text is disabled
+func New(cfg config.SpeechToTextConfig) (Transcriber, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 2 * time.Hour
+	}
+
+	switch cfg.Provider {
+	case "", ProviderWhisper:
+		command := cfg.Command
+		if command == "" {
+			command = "whisper"
+		}
+		model := cfg.Model
+		if model == "" {
+			model = "small"
+		}
+		if _, err := exec.LookPath(command); err != nil {
+			return nil, fmt.Errorf("whisper command %q not found: %w", command, err)
+		}
+		return &WhisperCLI{command: command, model: model, timeout: timeout}, nil
+	case ProviderHTTP:
+		if cfg.ServiceURL == "" {
+			return nil, fmt.Errorf("speech_to_text.service_url is required for the http provider")
+		}
+		return &HTTPService{
+			url:    cfg.ServiceURL,
+			token:  cfg.Token,
+			client: &http.Client{Timeout: timeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown speech-to-text provider %q", cfg.Provider)
+	}
+}
+
+// WhisperCLI runs the openai-whisper command line tool on the worker
+type WhisperCLI struct {
+	command string
+	model   string
+	timeout time.Duration
+}
+
+// Name identifies the provider on stored drafts
+func (w *WhisperCLI) Name() string {
+	return ProviderWhisper + ":" + w.model
+}
+
+// Transcribe writes {audio}.vtt into a temporary directory and returns it
+func (w *WhisperCLI) Transcribe(ctx context.Context, audioPath, language string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	outputDir, err := os.MkdirTemp("", "whisper-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outputDir)
+
+	args := []string{audioPath,
+		"--model", w.model,
+		"--task", "transcribe",
+		"--output_format", "vtt",
+		"--output_dir", outputDir,
+		"--verbose", "False",
+	}
+	if language != "" {
+		args = append(args, "--language", language)
+	}
+
+	output, err := exec.CommandContext(ctx, w.command, args...).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("whisper failed: %w, output: %s", err, lastLine(output))
+	}
+
+	name := strings.TrimSuffix(filepath.Base(audioPath), filepath.Ext(audioPath)) + ".vtt"
+	vtt, err := os.ReadFile(filepath.Join(outputDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("whisper produced no subtitles: %w", err)
+	}
+	return vtt, nil
+}
+
+// HTTPService posts the audio to a transcription endpoint that answers with WebVTT.
+// The language is sent as ?language=, the token as Bearer token.
+type HTTPService struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// Name identifies the provider on stored drafts
+func (s *HTTPService) Name() string {
+	return ProviderHTTP
+}
+
+// Transcribe uploads the WAV file and returns the response body
+func (s *HTTPService) Transcribe(ctx context.Context, audioPath, language string) ([]byte, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	endpoint, err := url.Parse(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid speech-to-text service URL: %w", err)
+	}
+	if language != "" {
+		query := endpoint.Query()
+		query.Set("language", language)
+		endpoint.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), file)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "audio/wav")
+	req.Header.Set("Accept", "text/vtt")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call speech-to-text service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTranscriptSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read speech-to-text response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("speech-to-text service returned status %d: %s", resp.StatusCode, lastLine(body))
+	}
+	return body, nil
+}
+
+// lastLine keeps error messages short, tools print their reason last
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(bytes.ToValidUTF8(output, nil))), "\n")
+	return lines[len(lines)-1]
+}
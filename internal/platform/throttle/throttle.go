@@ -0,0 +1,110 @@
+package throttle
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is how long an unused session bucket is kept before it is dropped
+const idleTimeout = 10 * time.Minute
+
+// Limiter keeps one token bucket per stream session so parallel requests
+// from the same session (e.g. all renditions at once) share the same budget
+type Limiter struct {
+	mu          sync.Mutex
+	sessions    map[string]*session
+	planRates   map[string]int
+	defaultRate int
+	lastSweep   time.Time
+}
+
+type session struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLimiter creates a limiter with per-plan rates in kilobytes per second
+// A rate of 0 means unlimited for that plan. Plan names are case-insensitive.
+func NewLimiter(planRates map[string]int, defaultRate int) *Limiter {
+	rates := make(map[string]int, len(planRates))
+	for plan, kbps := range planRates {
+		rates[strings.ToLower(plan)] = kbps
+	}
+
+	return &Limiter{
+		sessions:    make(map[string]*session),
+		planRates:   rates,
+		defaultRate: defaultRate,
+		lastSweep:   time.Now(),
+	}
+}
+
+// Reader wraps r so reads are throttled by the token bucket of the session
+func (l *Limiter) Reader(ctx context.Context, sessionKey, plan string, r io.Reader) io.Reader {
+	limiter := l.sessionLimiter(sessionKey, plan)
+	if limiter == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, reader: r, limiter: limiter}
+}
+
+// sessionLimiter returns the token bucket of a session, creating it on first use
+func (l *Limiter) sessionLimiter(sessionKey, plan string) *rate.Limiter {
+	kbps, ok := l.planRates[strings.ToLower(plan)]
+	if !ok {
+		kbps = l.defaultRate
+	}
+	if kbps <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > idleTimeout {
+		for key, s := range l.sessions {
+			if now.Sub(s.lastSeen) > idleTimeout {
+				delete(l.sessions, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	s, ok := l.sessions[sessionKey]
+	if !ok {
+		bytesPerSecond := kbps * 1024
+		// Burst of one second worth of data
+		s = &session{limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)}
+		l.sessions[sessionKey] = s
+	}
+	s.lastSeen = now
+
+	return s.limiter
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	// Never ask for more tokens than the bucket can hold
+	if burst := t.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err := t.reader.Read(p)
+	if n > 0 {
+		if waitErr := t.limiter.WaitN(t.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
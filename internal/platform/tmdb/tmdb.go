@@ -0,0 +1,171 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// Defaults of the public TMDB API
+const (
+	defaultBaseURL      = "https://api.themoviedb.org/3"
+	defaultImageBaseURL = "https://image.tmdb.org/t/p/w500"
+)
+
+// ErrNotFound is returned when a search has no result
+var ErrNotFound = errors.New("tmdb: movie not found")
+
+// Movie is the metadata of a TMDB title used to fill in the catalog
+type Movie struct {
+	ID          int64
+	Title       string
+	Overview    string
+	PosterURL   string    // empty when TMDB has no poster
+	ReleaseDate time.Time // zero when unknown
+	Director    string    // first crew member with the Director job
+	Genres      []string
+}
+
+// Client looks up movies on TMDB with a v4 read access token
+type Client struct {
+	token        string
+	baseURL      string
+	imageBaseURL string
+	language     string
+	client       *http.Client
+}
+
+// New returns a TMDB client, nil when no API token is configured
+func New(cfg config.TMDBConfig) *Client {
+	if cfg.APIToken == "" {
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	imageBaseURL := cfg.ImageBaseURL
+	if imageBaseURL == "" {
+		imageBaseURL = defaultImageBaseURL
+	}
+
+	return &Client{
+		token:        cfg.APIToken,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		imageBaseURL: strings.TrimRight(imageBaseURL, "/"),
+		language:     cfg.Language,
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+type searchResponse struct {
+	Results []struct {
+		ID int64 `json:"id"`
+	} `json:"results"`
+}
+
+type movieResponse struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Overview    string `json:"overview"`
+	PosterPath  string `json:"poster_path"`
+	ReleaseDate string `json:"release_date"`
+	Genres      []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Credits struct {
+		Crew []struct {
+			Job  string `json:"job"`
+			Name string `json:"name"`
+		} `json:"crew"`
+	} `json:"credits"`
+}
+
+// LookupMovie searches a title, narrowed to the release year when it is not zero, and returns the best match
+func (c *Client) LookupMovie(ctx context.Context, title string, year int) (*Movie, error) {
+	query := url.Values{"query": {title}}
+	if year > 0 {
+		query.Set("year", strconv.Itoa(year))
+	}
+
+	var search searchResponse
+	if err := c.get(ctx, "/search/movie", query, &search); err != nil {
+		return nil, err
+	}
+	if len(search.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	var details movieResponse
+	path := fmt.Sprintf("/movie/%d", search.Results[0].ID)
+	if err := c.get(ctx, path, url.Values{"append_to_response": {"credits"}}, &details); err != nil {
+		return nil, err
+	}
+
+	movie := &Movie{
+		ID:       details.ID,
+		Title:    details.Title,
+		Overview: details.Overview,
+	}
+	if details.PosterPath != "" {
+		movie.PosterURL = c.imageBaseURL + details.PosterPath
+	}
+	if releaseDate, err := time.Parse("2006-01-02", details.ReleaseDate); err == nil {
+		movie.ReleaseDate = releaseDate
+	}
+	for _, member := range details.Credits.Crew {
+		if member.Job == "Director" {
+			movie.Director = member.Name
+			break
+		}
+	}
+	for _, genre := range details.Genres {
+		movie.Genres = append(movie.Genres, genre.Name)
+	}
+	return movie, nil
+}
+
+// get calls an API path and decodes the JSON answer into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if c.language != "" {
+		query.Set("language", c.language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call TMDB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("TMDB returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode TMDB response: %w", err)
+	}
+	return nil
+}
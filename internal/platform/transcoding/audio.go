@@ -0,0 +1,194 @@
+package transcoding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	storage "github.com/martinmanurung/cinestream/internal/platform/strorage"
+	"github.com/rs/zerolog"
+)
+
+// audioGroup is the GROUP-ID of the audio renditions in the master playlist
+const audioGroup = "audio"
+
+// AudioTrack is an audio language available in the HLS output
+type AudioTrack struct {
+	Language string
+	Name     string
+	Channels int
+	Default  bool
+}
+
+// audioRendition is an audio track listed as #EXT-X-MEDIA in the master playlist
+type audioRendition struct {
+	Stream AudioStream
+	Name   string // unique within the audio group
+	// Playlist is empty for the main track, which is muxed into the video renditions
+	Playlist string
+}
+
+// transcodeAudioRenditions encodes every audio stream after the first into an audio-only HLS rendition.
+// The first stream stays muxed into the video renditions and is returned as the main track, so sources
+// with a single audio stream produce the same output as before.
+func (s *transcodingService) transcodeAudioRenditions(ctx context.Context, inputPath, outputDir string) []audioRendition {
+	streams, err := probeAudio(ctx, inputPath)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to probe audio streams, keeping the main track only")
+		return nil
+	}
+	if len(streams) == 0 {
+		return nil
+	}
+
+	renditions := []audioRendition{{Stream: streams[0]}}
+	for _, stream := range streams[1:] {
+		playlist, err := transcodeAudio(ctx, inputPath, outputDir, stream)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Int("stream", stream.Index).Str("language", stream.Language()).Msg("Failed to transcode audio track")
+			continue
+		}
+		renditions = append(renditions, audioRendition{Stream: stream, Playlist: playlist})
+	}
+
+	// NAME has to be unique within the group, untitled tracks often share a language
+	seen := make(map[string]int)
+	for i := range renditions {
+		name := renditions[i].Stream.Name()
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s (%d)", name, seen[name])
+		}
+		renditions[i].Name = name
+	}
+
+	return renditions
+}
+
+// transcodeAudio encodes one audio stream to a stereo AAC HLS rendition without video
+func transcodeAudio(ctx context.Context, inputPath, outputDir string, stream AudioStream) (string, error) {
+	name := fmt.Sprintf("audio_%d", stream.Index)
+	playlistName := name + ".m3u8"
+
+	args := []string{
+		"-i", inputPath,
+		"-map", fmt.Sprintf("0:a:%d", stream.Index),
+		"-vn",
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-ac", "2",
+		"-f", "hls",
+		"-hls_time", "10",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_type", "mpegts",
+		"-hls_segment_filename", filepath.Join(outputDir, name+"_%03d.ts"),
+	}
+	args = append(args, hlsEncryptionArgs(outputDir)...)
+	args = append(args, filepath.Join(outputDir, playlistName))
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	if err := runFFmpeg(ctx, cmd); err != nil {
+		return "", fmt.Errorf("ffmpeg command failed: %w", err)
+	}
+	return playlistName, nil
+}
+
+// PackageAlternateAudio encodes an uploaded dub or audio description into a stereo AAC HLS rendition under
+// movie-{id}/audio/{trackID}/, encrypted with the movie's key when it has one. The video is not touched,
+// the API adds the rendition to the master playlist when serving it. Returns the media playlist object.
+func (s *transcodingService) PackageAlternateAudio(ctx context.Context, movieID, trackID int64, sourceObject string, key *ContentKey) (string, error) {
+	workDir := filepath.Join(s.tempDir, fmt.Sprintf("movie-%d-audio-%d", movieID, trackID))
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := writeKeyInfo(workDir, key); err != nil {
+		return "", err
+	}
+
+	inputPath := filepath.Join(workDir, "input"+filepath.Ext(sourceObject))
+	if err := s.downloadFromMinIO(ctx, sourceObject, inputPath); err != nil {
+		return "", fmt.Errorf("failed to download audio: %w", err)
+	}
+
+	outputDir := filepath.Join(workDir, "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	args := []string{
+		"-i", inputPath,
+		"-map", "0:a:0",
+		"-vn",
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-ac", "2",
+		"-f", "hls",
+		"-hls_time", "10",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_type", "mpegts",
+		"-hls_segment_filename", filepath.Join(outputDir, "audio_%03d.ts"),
+	}
+	args = append(args, hlsEncryptionArgs(outputDir)...)
+	args = append(args, filepath.Join(outputDir, "index.m3u8"))
+
+	output, err := exec.CommandContext(ctx, "ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("ffmpeg failed: %s", firstLine(output))
+	}
+
+	basePath := strings.TrimSuffix(storage.AlternateAudioPrefix(movieID, trackID), "/")
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read audio output: %w", err)
+	}
+	// Segments first, the playlist is only visible once everything it references is uploaded
+	for _, entry := range entries {
+		if entry.Name() == "index.m3u8" {
+			continue
+		}
+		if err := s.uploadHLSFile(ctx, basePath, outputDir, entry.Name()); err != nil {
+			return "", err
+		}
+	}
+	if err := s.uploadHLSFile(ctx, basePath, outputDir, "index.m3u8"); err != nil {
+		return "", err
+	}
+
+	return basePath + "/index.m3u8", nil
+}
+
+// audioMediaTag renders the #EXT-X-MEDIA tag of an audio rendition, the main track is the default
+func audioMediaTag(rendition audioRendition) string {
+	attrs := fmt.Sprintf(`TYPE=AUDIO,GROUP-ID="%s",NAME="%s"`, audioGroup, strings.ReplaceAll(rendition.Name, `"`, "'"))
+	if language := rendition.Stream.Language(); language != "und" {
+		attrs += fmt.Sprintf(`,LANGUAGE="%s"`, language)
+	}
+	if rendition.Playlist == "" {
+		attrs += ",DEFAULT=YES,AUTOSELECT=YES"
+	} else {
+		attrs += fmt.Sprintf(`,DEFAULT=NO,AUTOSELECT=YES,URI="%s"`, rendition.Playlist)
+	}
+	return "#EXT-X-MEDIA:" + attrs
+}
+
+// audioTracks returns the tracks of the renditions for the transcode result
+func audioTracks(renditions []audioRendition) []AudioTrack {
+	tracks := make([]AudioTrack, 0, len(renditions))
+	for _, rendition := range renditions {
+		tracks = append(tracks, AudioTrack{
+			Language: rendition.Stream.Language(),
+			Name:     rendition.Name,
+			Channels: rendition.Stream.Channels,
+			Default:  rendition.Playlist == "",
+		})
+	}
+	return tracks
+}
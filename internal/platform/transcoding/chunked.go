@@ -0,0 +1,364 @@
+package transcoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog"
+)
+
+// ErrChunkingUnsupported is returned by PrepareChunks when the title has to be encoded on one machine.
+// HDR renditions use fMP4 segments that cannot be stitched by stream copy yet.
+var ErrChunkingUnsupported = errors.New("chunked encoding not supported for this title")
+
+// ChunkPlan is kept by the coordinating worker between PrepareChunks and StitchChunks
+type ChunkPlan struct {
+	MovieID          int64
+	Version          int
+	ProfileSet       string
+	ComplexityFactor float64
+	ToneMap          bool
+	SourceHeight     int      // renditions taller than the source are skipped
+	SourceKbps       int      // caps the rendition bitrates, 0 when unknown
+	Chunks           []string // source chunk objects in the raw bucket, in playback order
+
+	workDir   string
+	inputPath string
+	profiles  []QualityProfile
+}
+
+// Task returns the encode task of chunk index
+func (p *ChunkPlan) Task(index int) ChunkTask {
+	return ChunkTask{
+		MovieID:          p.MovieID,
+		Version:          p.Version,
+		Index:            index,
+		SourceObject:     p.Chunks[index],
+		ProfileSet:       p.ProfileSet,
+		ComplexityFactor: p.ComplexityFactor,
+		ToneMap:          p.ToneMap,
+		SourceHeight:     p.SourceHeight,
+		SourceKbps:       p.SourceKbps,
+	}
+}
+
+// ChunkTask is the work a worker does for one chunk, it can run on any machine
+type ChunkTask struct {
+	MovieID          int64
+	Version          int
+	Index            int
+	SourceObject     string
+	ProfileSet       string
+	ComplexityFactor float64
+	ToneMap          bool
+	SourceHeight     int
+	SourceKbps       int
+}
+
+// chunkPrefix holds the split source and the encoded parts of one output version in the raw bucket
+func chunkPrefix(movieID int64, version int) string {
+	return fmt.Sprintf("chunks/movie-%d/v%d", movieID, version)
+}
+
+// chunkPartObject is the encoded part of a chunk for one rendition
+func chunkPartObject(movieID int64, version int, rendition string, index int) string {
+	return fmt.Sprintf("%s/%s/part_%04d.ts", chunkPrefix(movieID, version), rendition, index)
+}
+
+// SourceDuration probes the raw video through a presigned URL, without downloading it
+func (s *transcodingService) SourceDuration(ctx context.Context, rawFilePath string) (float64, error) {
+	sourceURL, err := s.minioClient.PresignedGetObject(ctx, s.bucketRaw, rawFilePath, 15*time.Minute, url.Values{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to presign raw video: %w", err)
+	}
+	return probeDuration(ctx, sourceURL.String())
+}
+
+// PrepareChunks downloads the source, picks the bitrates like TranscodeToHLS does and splits the source
+// into keyframe-aligned chunks of about chunkSeconds, uploaded for the other workers.
+// Chunk parts stay unencrypted, key is applied when StitchChunks segments the renditions.
+func (s *transcodingService) PrepareChunks(ctx context.Context, movieID int64, version int, rawFilePath string, profileSet ProfileSet, chunkSeconds float64, key *ContentKey) (*ChunkPlan, error) {
+	workDir := filepath.Join(s.tempDir, fmt.Sprintf("movie-%d-v%d", movieID, version))
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	plan := &ChunkPlan{
+		MovieID:    movieID,
+		Version:    version,
+		ProfileSet: profileSet.Name,
+		workDir:    workDir,
+		inputPath:  filepath.Join(workDir, "input.mp4"),
+	}
+
+	if err := writeKeyInfo(workDir, key); err != nil {
+		os.RemoveAll(workDir)
+		return nil, err
+	}
+
+	if err := s.downloadFromMinIO(ctx, rawFilePath, plan.inputPath); err != nil {
+		os.RemoveAll(workDir)
+		return nil, fmt.Errorf("failed to download raw video: %w", err)
+	}
+
+	sourceRange := RangeSDR
+	if info, err := probeVideo(ctx, plan.inputPath); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to probe source, assuming SDR")
+	} else {
+		sourceRange = info.DynamicRange()
+		plan.SourceHeight = info.Height
+		plan.SourceKbps = info.BitrateKbps()
+	}
+	if sourceRange != RangeSDR {
+		if profileSet.HDRPassthrough {
+			os.RemoveAll(workDir)
+			return nil, ErrChunkingUnsupported
+		}
+		plan.ToneMap = toneMapAvailable()
+	}
+
+	complexity, err := analyzeComplexity(ctx, plan.inputPath, workDir)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Complexity analysis failed, using static bitrates")
+	}
+	plan.ComplexityFactor = complexity
+	plan.profiles = buildLadder(ctx, profileSet.Profiles, plan.SourceHeight, plan.SourceKbps, complexity)
+
+	// The segment muxer only cuts on keyframes, so chunks decode on their own
+	splitDir := filepath.Join(workDir, "split")
+	if err := os.MkdirAll(splitDir, 0755); err != nil {
+		s.CleanupChunks(ctx, plan)
+		return nil, fmt.Errorf("failed to create split directory: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-i", plan.inputPath,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-c", "copy",
+		"-f", "segment",
+		"-segment_time", strconv.FormatFloat(chunkSeconds, 'f', 0, 64),
+		"-reset_timestamps", "1",
+		filepath.Join(splitDir, "source_%04d.mkv"),
+	)
+	if err := runFFmpeg(ctx, cmd); err != nil {
+		s.CleanupChunks(ctx, plan)
+		return nil, fmt.Errorf("failed to split source: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(splitDir, "source_*.mkv"))
+	if err != nil || len(files) == 0 {
+		s.CleanupChunks(ctx, plan)
+		return nil, fmt.Errorf("source split produced no chunks")
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		objectName := fmt.Sprintf("%s/source/%s", chunkPrefix(movieID, version), filepath.Base(file))
+		if _, err := s.minioClient.FPutObject(ctx, s.bucketRaw, objectName, file, minio.PutObjectOptions{
+			ContentType: "video/x-matroska",
+		}); err != nil {
+			s.CleanupChunks(ctx, plan)
+			return nil, fmt.Errorf("failed to upload chunk %s: %w", objectName, err)
+		}
+		plan.Chunks = append(plan.Chunks, objectName)
+		os.Remove(file)
+	}
+
+	return plan, nil
+}
+
+// EncodeChunk encodes one source chunk into an MPEG-TS part per rendition
+func (s *transcodingService) EncodeChunk(ctx context.Context, task ChunkTask) error {
+	workDir := filepath.Join(s.tempDir, fmt.Sprintf("chunk-%d-v%d-%d", task.MovieID, task.Version, task.Index))
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	inputPath := filepath.Join(workDir, filepath.Base(task.SourceObject))
+	if err := s.downloadFromMinIO(ctx, task.SourceObject, inputPath); err != nil {
+		return fmt.Errorf("failed to download chunk: %w", err)
+	}
+
+	filterPrefix := ""
+	if task.ToneMap {
+		filterPrefix = toneMapFilter + ","
+	}
+	encoder := detectH264Encoder()
+
+	profiles := capProfilesToSourceBitrate(
+		scaleProfiles(fitProfilesToSource(LookupProfileSet(task.ProfileSet).Profiles, task.SourceHeight), task.ComplexityFactor),
+		task.SourceKbps,
+	)
+	for _, profile := range profiles {
+		partPath := filepath.Join(workDir, profile.Name+".ts")
+		pre, filter, codec := h264EncoderArgs(encoder, filterPrefix, profile.Resolution)
+		args := append(pre,
+			"-i", inputPath,
+			"-vf", filter,
+		)
+		args = append(args, codec...)
+		args = append(args,
+			"-b:v", profile.Bitrate,
+			"-maxrate", profile.MaxRate,
+			"-bufsize", profile.BufSize,
+			// Keyframes every 10s so the stitched stream can be segmented by stream copy
+			"-force_key_frames", "expr:gte(t,n_forced*10)",
+			"-c:a", "aac",
+			"-b:a", "128k",
+			"-ac", "2",
+			"-f", "mpegts",
+			partPath,
+		)
+
+		cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+		if err := runFFmpeg(ctx, cmd); err != nil {
+			return fmt.Errorf("ffmpeg failed for %s: %w", profile.Name, err)
+		}
+
+		objectName := chunkPartObject(task.MovieID, task.Version, profile.Name, task.Index)
+		if _, err := s.minioClient.FPutObject(ctx, s.bucketRaw, objectName, partPath, minio.PutObjectOptions{
+			ContentType: "video/mp2t",
+		}); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", objectName, err)
+		}
+	}
+
+	return nil
+}
+
+// StitchChunks concatenates the encoded parts of every rendition, segments them into HLS
+// and publishes the output version like TranscodeToHLS
+func (s *transcodingService) StitchChunks(ctx context.Context, plan *ChunkPlan) (*TranscodeResult, error) {
+	outputDir := filepath.Join(plan.workDir, "output")
+	partsDir := filepath.Join(plan.workDir, "parts")
+	for _, dir := range []string{outputDir, partsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	variants := []hlsVariant{}
+	for _, profile := range plan.profiles {
+		playlist, err := s.stitchRendition(ctx, plan, profile, partsDir, outputDir)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("rendition", profile.Name).Msg("Failed to stitch rendition")
+			continue
+		}
+		variants = append(variants, hlsVariant{Playlist: playlist, Profile: profile, VideoRange: RangeSDR, ToneMap: plan.ToneMap})
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("failed to stitch any quality level")
+	}
+
+	// Audio-only renditions are short to encode, the coordinator does them from its local source
+	audio := s.transcodeAudioRenditions(ctx, plan.inputPath, outputDir)
+	manifest := s.packageDASH(ctx, outputDir, variants, audio)
+
+	if err := s.createMasterPlaylist(filepath.Join(outputDir, "master.m3u8"), variants, audio); err != nil {
+		return nil, fmt.Errorf("failed to create master playlist: %w", err)
+	}
+
+	basePath := outputPrefix(plan.MovieID, plan.Version)
+	hlsBaseURL, err := s.uploadHLSFiles(ctx, basePath, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload HLS files: %w", err)
+	}
+
+	// Broken renditions are re-encoded from the full source the coordinator still has locally
+	if err := s.verifyAndRepair(ctx, basePath, plan.inputPath, outputDir, variants, audio); err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+	if manifest != "" && !s.verifyDASH(ctx, basePath, outputDir) {
+		manifest = ""
+	}
+
+	result := &TranscodeResult{
+		HLSURL:           hlsBaseURL,
+		ComplexityFactor: plan.ComplexityFactor,
+		AudioTracks:      audioTracks(audio),
+		DASHURL:          dashURL(basePath, manifest),
+	}
+	for _, variant := range variants {
+		result.Renditions = append(result.Renditions, Rendition{
+			Name:        strings.TrimSuffix(variant.Playlist, ".m3u8"),
+			Resolution:  variant.Profile.Resolution,
+			BitrateKbps: bitrateKbps(variant.Profile.Bitrate),
+			VideoRange:  variant.VideoRange,
+		})
+	}
+	return result, nil
+}
+
+// stitchRendition downloads the parts of one rendition and segments them into an HLS playlist
+func (s *transcodingService) stitchRendition(ctx context.Context, plan *ChunkPlan, profile QualityProfile, partsDir, outputDir string) (string, error) {
+	var list strings.Builder
+	for index := range plan.Chunks {
+		objectName := chunkPartObject(plan.MovieID, plan.Version, profile.Name, index)
+		partPath := filepath.Join(partsDir, fmt.Sprintf("%s_%04d.ts", profile.Name, index))
+		if err := s.downloadFromMinIO(ctx, objectName, partPath); err != nil {
+			return "", fmt.Errorf("failed to download part %d: %w", index, err)
+		}
+		fmt.Fprintf(&list, "file '%s'\n", partPath)
+	}
+
+	listPath := filepath.Join(partsDir, profile.Name+".txt")
+	if err := os.WriteFile(listPath, []byte(list.String()), 0644); err != nil {
+		return "", err
+	}
+
+	playlistName := fmt.Sprintf("%s.m3u8", profile.Name)
+	args := []string{
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
+		"-c", "copy",
+		"-f", "hls",
+		"-hls_time", "10",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_type", "mpegts",
+		"-hls_segment_filename", filepath.Join(outputDir, fmt.Sprintf("%s_%%03d.ts", profile.Name)),
+	}
+	args = append(args, hlsEncryptionArgs(outputDir)...)
+	args = append(args, filepath.Join(outputDir, playlistName))
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	if err := runFFmpeg(ctx, cmd); err != nil {
+		return "", fmt.Errorf("ffmpeg concat failed: %w", err)
+	}
+
+	// Parts of finished renditions are not needed locally anymore
+	matches, _ := filepath.Glob(filepath.Join(partsDir, profile.Name+"_*.ts"))
+	for _, match := range matches {
+		os.Remove(match)
+	}
+
+	return playlistName, nil
+}
+
+// CleanupChunks removes the local work directory and the chunk objects of the plan
+func (s *transcodingService) CleanupChunks(ctx context.Context, plan *ChunkPlan) {
+	os.RemoveAll(plan.workDir)
+
+	for object := range s.minioClient.ListObjects(ctx, s.bucketRaw, minio.ListObjectsOptions{
+		Prefix:    chunkPrefix(plan.MovieID, plan.Version) + "/",
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			zerolog.Ctx(ctx).Warn().Err(object.Err).Msg("Failed to list chunk objects")
+			return
+		}
+		if err := s.minioClient.RemoveObject(ctx, s.bucketRaw, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("object", object.Key).Msg("Failed to remove chunk object")
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package transcoding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	complexitySamples       = 3 // samples spread over the title
+	complexitySampleSeconds = 8
+	minComplexityFactor     = 0.5
+	maxComplexityFactor     = 1.6
+)
+
+// crfProbe is the constant quality encode used to measure how hard a title is to compress
+type crfProbe struct {
+	encoder string
+	crf     string
+	// referenceKbps is the 720p bitrate of typical live action at this CRF, it maps to factor 1.0
+	referenceKbps float64
+}
+
+var crfProbes = []crfProbe{
+	{encoder: "libx264", crf: "23", referenceKbps: 2000},
+	{encoder: "libx265", crf: "28", referenceKbps: 1000},
+}
+
+// analyzeComplexity encodes short samples at constant quality and compares their bitrate to the reference.
+// Simple content such as animation ends below 1.0, grainy or high motion content above it.
+func analyzeComplexity(ctx context.Context, inputPath, workDir string) (float64, error) {
+	probe, ok := detectCRFProbe()
+	if !ok {
+		return 1, fmt.Errorf("no CRF capable encoder available")
+	}
+
+	duration, err := probeDuration(ctx, inputPath)
+	if err != nil {
+		return 1, err
+	}
+
+	sampleSeconds := math.Min(complexitySampleSeconds, duration)
+	var totalKbps float64
+	measured := 0
+	for i := 0; i < complexitySamples; i++ {
+		start := duration * float64(i+1) / float64(complexitySamples+1)
+		if start+sampleSeconds > duration {
+			start = math.Max(0, duration-sampleSeconds)
+		}
+
+		samplePath := filepath.Join(workDir, fmt.Sprintf("complexity_%d.mp4", i))
+		args := []string{
+			"-y",
+			"-ss", strconv.FormatFloat(start, 'f', 2, 64),
+			"-t", strconv.FormatFloat(sampleSeconds, 'f', 2, 64),
+			"-i", inputPath,
+			"-an",
+			"-vf", "scale=-2:720",
+			"-c:v", probe.encoder,
+			"-preset", "veryfast",
+			"-crf", probe.crf,
+			samplePath,
+		}
+		if err := exec.CommandContext(ctx, "ffmpeg", args...).Run(); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Int("sample", i).Msg("Complexity sample failed")
+			continue
+		}
+
+		stat, err := os.Stat(samplePath)
+		os.Remove(samplePath)
+		if err != nil {
+			continue
+		}
+		totalKbps += float64(stat.Size()) * 8 / 1000 / sampleSeconds
+		measured++
+	}
+
+	if measured == 0 {
+		return 1, fmt.Errorf("all complexity samples failed")
+	}
+
+	factor := (totalKbps / float64(measured)) / probe.referenceKbps
+	factor = math.Max(minComplexityFactor, math.Min(maxComplexityFactor, factor))
+	return math.Round(factor*100) / 100, nil
+}
+
+// detectCRFProbe picks the first CRF encoder ffmpeg was built with
+func detectCRFProbe() (crfProbe, bool) {
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-encoders").CombinedOutput()
+	if err != nil {
+		return crfProbe{}, false
+	}
+	for _, probe := range crfProbes {
+		if strings.Contains(string(output), probe.encoder) {
+			return probe, true
+		}
+	}
+	return crfProbe{}, false
+}
+
+// probeDuration returns the container duration in seconds
+func probeDuration(ctx context.Context, inputPath string) (float64, error) {
+	output, err := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		inputPath,
+	).Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", strings.TrimSpace(string(output)))
+	}
+	return duration, nil
+}
+
+// scaleProfiles returns the profiles with bitrate, max rate and buffer size multiplied by factor
+func scaleProfiles(profiles []QualityProfile, factor float64) []QualityProfile {
+	scaled := make([]QualityProfile, len(profiles))
+	for i, profile := range profiles {
+		scaled[i] = profile
+		scaled[i].Bitrate = scaleKbps(profile.Bitrate, factor)
+		scaled[i].MaxRate = scaleKbps(profile.MaxRate, factor)
+		scaled[i].BufSize = scaleKbps(profile.BufSize, factor)
+	}
+	return scaled
+}
+
+// scaleKbps scales a value such as "5000k", values in another format are kept as is
+func scaleKbps(value string, factor float64) string {
+	kbps, err := strconv.Atoi(strings.TrimSuffix(value, "k"))
+	if err != nil {
+		return value
+	}
+	return fmt.Sprintf("%dk", int(math.Round(float64(kbps)*factor)))
+}
+
+// bitrateKbps parses a value such as "5000k"
+func bitrateKbps(value string) int {
+	kbps, _ := strconv.Atoi(strings.TrimSuffix(value, "k"))
+	return kbps
+}
@@ -0,0 +1,143 @@
+package transcoding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+)
+
+// dashManifest is the DASH manifest written next to master.m3u8
+const dashManifest = "manifest.mpd"
+
+// dashEnabled is set by EnableDASH, the worker reads it from transcoding.dash_enabled
+var dashEnabled bool
+
+// EnableDASH makes every transcode also publish a DASH manifest for devices that prefer MPEG-DASH
+func EnableDASH(enabled bool) {
+	dashEnabled = enabled
+}
+
+// packageDASH repackages the SDR renditions and audio tracks into fMP4 DASH segments by stream copy,
+// so no rendition is encoded twice. It returns the manifest name, empty when DASH is disabled or failed.
+// Encrypted titles are skipped, DASH players cannot use the AES-128 key of the HLS segments.
+func (s *transcodingService) packageDASH(ctx context.Context, outputDir string, variants []hlsVariant, audio []audioRendition) string {
+	if !dashEnabled {
+		return ""
+	}
+	if hlsEncryptionArgs(outputDir) != nil {
+		zerolog.Ctx(ctx).Warn().Msg("Skipping DASH output, encrypted titles are only published as HLS")
+		return ""
+	}
+
+	var args []string
+	var maps []string
+	inputs := 0
+	for _, variant := range variants {
+		// HDR renditions use a different codec, mixing them into the video adaptation set breaks switching
+		if variant.VideoRange != RangeSDR {
+			continue
+		}
+		args = append(args, "-i", filepath.Join(outputDir, variant.Playlist))
+		maps = append(maps, "-map", fmt.Sprintf("%d:v:0", inputs))
+		inputs++
+	}
+	if inputs == 0 {
+		return ""
+	}
+
+	// The main audio track is muxed into the video renditions, alternate tracks have own playlists
+	audioStreams := 0
+	var metadata []string
+	for _, rendition := range audio {
+		if rendition.Playlist == "" {
+			maps = append(maps, "-map", "0:a:0?")
+		} else {
+			args = append(args, "-i", filepath.Join(outputDir, rendition.Playlist))
+			maps = append(maps, "-map", fmt.Sprintf("%d:a:0", inputs))
+			inputs++
+		}
+		if language := rendition.Stream.Language(); language != "und" {
+			metadata = append(metadata, fmt.Sprintf("-metadata:s:a:%d", audioStreams), "language="+language)
+		}
+		audioStreams++
+	}
+	if len(audio) == 0 {
+		maps = append(maps, "-map", "0:a:0?")
+	}
+
+	args = append(args, maps...)
+	args = append(args, metadata...)
+	args = append(args,
+		"-c", "copy",
+		"-f", "dash",
+		"-seg_duration", "10",
+		"-use_template", "1",
+		"-use_timeline", "1",
+		"-init_seg_name", "dash_init_$RepresentationID$.m4s",
+		"-media_seg_name", "dash_$RepresentationID$_$Number%05d$.m4s",
+		"-adaptation_sets", "id=0,streams=v id=1,streams=a",
+		filepath.Join(outputDir, dashManifest),
+	)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	if err := runFFmpeg(ctx, cmd); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to package DASH output, publishing HLS only")
+		removeDASHFiles(outputDir)
+		return ""
+	}
+	return dashManifest
+}
+
+// verifyDASH uploads missing DASH files again. DASH is optional, so a manifest that still has
+// missing files is dropped from the result instead of failing the title.
+func (s *transcodingService) verifyDASH(ctx context.Context, basePath, outputDir string) bool {
+	files := dashFiles(outputDir)
+	for attempt := 0; ; attempt++ {
+		missing, err := s.missingFromBucket(ctx, basePath, files)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to verify DASH output")
+			return false
+		}
+		if len(missing) == 0 {
+			return true
+		}
+		if attempt == maxRepairAttempts {
+			zerolog.Ctx(ctx).Warn().Int("missing", len(missing)).Msg("DASH output still has missing objects, publishing HLS only")
+			return false
+		}
+		for _, relPath := range missing {
+			if err := s.uploadHLSFile(ctx, basePath, outputDir, relPath); err != nil {
+				zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to upload DASH file")
+			}
+		}
+	}
+}
+
+// dashFiles lists the manifest and segments of the DASH output, relative to outputDir
+func dashFiles(outputDir string) []string {
+	files := []string{dashManifest}
+	matches, _ := filepath.Glob(filepath.Join(outputDir, "dash_*.m4s"))
+	for _, match := range matches {
+		files = append(files, filepath.Base(match))
+	}
+	return files
+}
+
+// removeDASHFiles deletes partial DASH output so it is not uploaded
+func removeDASHFiles(outputDir string) {
+	for _, relPath := range dashFiles(outputDir) {
+		os.Remove(filepath.Join(outputDir, relPath))
+	}
+}
+
+// dashURL returns the object path of the manifest, empty when there is none
+func dashURL(basePath, manifest string) string {
+	if manifest == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", basePath, manifest)
+}
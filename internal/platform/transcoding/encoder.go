@@ -0,0 +1,124 @@
+package transcoding
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// H.264 encoders that can be selected with transcoding.encoder
+const (
+	EncoderAuto     = "auto"
+	EncoderLibx264  = "libx264"
+	EncoderNVENC    = "h264_nvenc"
+	EncoderVAAPI    = "h264_vaapi"
+	EncoderQSV      = "h264_qsv"
+	defaultVAAPIDev = "/dev/dri/renderD128"
+)
+
+// autoEncoders is the order auto mode tries, hardware first, mpeg4 is the last resort every ffmpeg has
+var autoEncoders = []string{EncoderNVENC, EncoderVAAPI, EncoderQSV, EncoderLibx264, "libopenh264", "mpeg4"}
+
+var (
+	// selectedEncoder is set by ConfigureEncoder, empty means software detection
+	selectedEncoder string
+	vaapiDevice     = defaultVAAPIDev
+)
+
+// ConfigureEncoder picks the H.264 encoder and verifies it by encoding a 1 second test pattern.
+// An explicitly configured encoder that fails the test is an error, so a worker without a working
+// GPU does not accept jobs. Auto mode uses the first encoder that passes.
+func ConfigureEncoder(ctx context.Context, name, device string) (string, error) {
+	if device != "" {
+		vaapiDevice = device
+	}
+	if name == "" {
+		name = EncoderAuto
+	}
+
+	available, err := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-encoders").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to list ffmpeg encoders: %w", err)
+	}
+
+	if name != EncoderAuto {
+		switch name {
+		case EncoderLibx264, EncoderNVENC, EncoderVAAPI, EncoderQSV:
+		default:
+			return "", fmt.Errorf("unknown encoder %q, use auto, libx264, h264_nvenc, h264_vaapi or h264_qsv", name)
+		}
+		if !strings.Contains(string(available), " "+name+" ") {
+			return "", fmt.Errorf("encoder %s is not built into ffmpeg", name)
+		}
+		if err := testEncoder(ctx, name); err != nil {
+			return "", fmt.Errorf("encoder %s failed the self-test: %w", name, err)
+		}
+		selectedEncoder = name
+		return name, nil
+	}
+
+	for _, encoder := range autoEncoders {
+		if !strings.Contains(string(available), " "+encoder+" ") {
+			continue
+		}
+		if err := testEncoder(ctx, encoder); err != nil {
+			zerolog.Ctx(ctx).Info().Err(err).Str("encoder", encoder).Msg("Encoder skipped")
+			continue
+		}
+		selectedEncoder = encoder
+		return encoder, nil
+	}
+	return "", fmt.Errorf("no working H.264 encoder found")
+}
+
+// testEncoder encodes a 1 second test pattern with the same arguments a rendition uses
+func testEncoder(ctx context.Context, encoder string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pre, filter, codec := h264EncoderArgs(encoder, "", "640x360")
+	args := append([]string{"-hide_banner", "-v", "error"}, pre...)
+	args = append(args,
+		"-f", "lavfi", "-i", "testsrc2=size=1280x720:rate=30",
+		"-t", "1",
+		"-vf", filter,
+	)
+	args = append(args, codec...)
+	args = append(args, "-b:v", "800k", "-f", "null", "-")
+
+	output, err := exec.CommandContext(ctx, "ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, firstLine(output))
+	}
+	return nil
+}
+
+// h264EncoderArgs returns the arguments before -i, the -vf filter chain scaling to resolution
+// and the codec options of an H.264 encoder. filterPrefix runs first in software, e.g. tone mapping.
+func h264EncoderArgs(encoder, filterPrefix, resolution string) (pre []string, filter string, codec []string) {
+	switch encoder {
+	case EncoderVAAPI:
+		// Upload to the GPU and scale there
+		return []string{"-vaapi_device", vaapiDevice},
+			fmt.Sprintf("%sformat=nv12,hwupload,scale_vaapi=w=%s:h=%s", filterPrefix, getWidth(resolution), getHeight(resolution)),
+			[]string{"-c:v", EncoderVAAPI}
+	case EncoderNVENC:
+		// Decoding on the GPU, frames come back for the software filters
+		return []string{"-hwaccel", "cuda"},
+			fmt.Sprintf("%sscale=%s", filterPrefix, resolution),
+			[]string{"-c:v", EncoderNVENC, "-preset", "p4"} // Medium preset for good quality/speed balance
+	case EncoderQSV:
+		// h264_qsv takes nv12 frames from system memory
+		return nil,
+			fmt.Sprintf("%sscale=%s,format=nv12", filterPrefix, resolution),
+			[]string{"-c:v", EncoderQSV, "-preset", "medium"}
+	default:
+		return nil,
+			fmt.Sprintf("%sscale=%s", filterPrefix, resolution),
+			append([]string{"-c:v", encoder}, softwareEncoderOptions(encoder)...)
+	}
+}
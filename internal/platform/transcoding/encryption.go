@@ -0,0 +1,62 @@
+package transcoding
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// contentKeySize is the key length of AES-128
+const contentKeySize = 16
+
+// keyInfoFile is the ffmpeg key info file, written to the work directory of an encrypted title
+const keyInfoFile = "hls.keyinfo"
+
+// ContentKey encrypts the HLS segments of a title with AES-128
+type ContentKey struct {
+	Key []byte
+	URI string // written to the playlists, players fetch the key from the API there
+}
+
+// GenerateContentKey returns a random AES-128 key
+func GenerateContentKey() ([]byte, error) {
+	key := make([]byte, contentKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate content key: %w", err)
+	}
+	return key, nil
+}
+
+// writeKeyInfo writes the key and the ffmpeg key info file to the work directory, nothing when key is nil.
+// Both stay outside the output directory so the key is never uploaded next to the segments.
+func writeKeyInfo(workDir string, key *ContentKey) error {
+	if key == nil {
+		return nil
+	}
+	if len(key.Key) != contentKeySize {
+		return fmt.Errorf("content key must be %d bytes, got %d", contentKeySize, len(key.Key))
+	}
+
+	keyPath := filepath.Join(workDir, "hls.key")
+	if err := os.WriteFile(keyPath, key.Key, 0600); err != nil {
+		return fmt.Errorf("failed to write content key: %w", err)
+	}
+
+	// Without an IV line ffmpeg uses the media sequence number, as the HLS spec expects
+	info := fmt.Sprintf("%s\n%s\n", key.URI, keyPath)
+	if err := os.WriteFile(filepath.Join(workDir, keyInfoFile), []byte(info), 0600); err != nil {
+		return fmt.Errorf("failed to write key info file: %w", err)
+	}
+	return nil
+}
+
+// hlsEncryptionArgs returns the ffmpeg options encrypting the segments written to outputDir.
+// Re-encodes during repair pick the key up the same way, unencrypted titles get no options.
+func hlsEncryptionArgs(outputDir string) []string {
+	path := filepath.Join(filepath.Dir(outputDir), keyInfoFile)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	return []string{"-hls_key_info_file", path}
+}
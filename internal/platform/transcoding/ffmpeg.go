@@ -6,16 +6,57 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
 )
 
 // TranscodingService handles video transcoding to HLS format
 type TranscodingService interface {
-	TranscodeToHLS(ctx context.Context, movieID int64, rawFilePath string) (string, error)
+	TranscodeToHLS(ctx context.Context, movieID int64, version int, rawFilePath string, profileSet ProfileSet, key *ContentKey, keep *KeptOutput) (*TranscodeResult, error)
+	RemoveSupersededVersions(ctx context.Context, movieID int64, liveVersion int) error
+	ValidateSource(ctx context.Context, rawFilePath string) (*SourceInfo, error)
+
+	// Chunked mode, see chunked.go
+	SourceDuration(ctx context.Context, rawFilePath string) (float64, error)
+	PrepareChunks(ctx context.Context, movieID int64, version int, rawFilePath string, profileSet ProfileSet, chunkSeconds float64, key *ContentKey) (*ChunkPlan, error)
+	EncodeChunk(ctx context.Context, task ChunkTask) error
+	StitchChunks(ctx context.Context, plan *ChunkPlan) (*TranscodeResult, error)
+	CleanupChunks(ctx context.Context, plan *ChunkPlan)
+
+	// Speech-to-text drafts, see speech.go
+	ExtractSpeechAudio(ctx context.Context, rawFilePath, destPath string) error
+	UploadSubtitleDraft(ctx context.Context, movieID int64, vtt []byte) (string, error)
+
+	// Uploaded dubs and audio descriptions, see audio.go
+	PackageAlternateAudio(ctx context.Context, movieID, trackID int64, sourceObject string, key *ContentKey) (string, error)
 }
 
+// TranscodeResult describes the HLS output of a title
+type TranscodeResult struct {
+	HLSURL string
+	// ComplexityFactor scaled the bitrates of the profile set, 1.0 when analysis was not possible
+	ComplexityFactor float64
+	Renditions       []Rendition
+	AudioTracks      []AudioTrack // empty when the source has no audio
+	DASHURL          string       // empty when no DASH manifest was published
+}
+
+// Rendition is one variant listed in the master playlist
+type Rendition struct {
+	Name        string
+	Resolution  string
+	BitrateKbps int
+	VideoRange  string
+}
+
+// TempDir holds a movie-{id}-* work directory per running transcode, removed once it finishes
+const TempDir = "/tmp/transcoding"
+
 type transcodingService struct {
 	minioClient     *minio.Client
 	bucketRaw       string
@@ -32,104 +73,243 @@ type QualityProfile struct {
 	BufSize    string
 }
 
+// Profile set names stored on movies.profile_set
+const (
+	ProfileSetStandard  = "standard"
+	ProfileSetLowCostSD = "low_cost_sd"
+	ProfileSetPremium   = "premium"
+)
+
+// ProfileSet is a named rendition ladder assigned per movie
+type ProfileSet struct {
+	Name     string
+	Profiles []QualityProfile
+	// HDRPassthrough adds 10-bit HEVC renditions keeping the HDR metadata of HDR sources,
+	// next to the tone-mapped SDR renditions every set gets
+	HDRPassthrough bool
+}
+
 var (
+	profile2160p = QualityProfile{Name: "2160p", Resolution: "3840x2160", Bitrate: "16000k", MaxRate: "17120k", BufSize: "24000k"}
+	profile1080p = QualityProfile{Name: "1080p", Resolution: "1920x1080", Bitrate: "5000k", MaxRate: "5350k", BufSize: "7500k"}
+	profile720p  = QualityProfile{Name: "720p", Resolution: "1280x720", Bitrate: "2800k", MaxRate: "2996k", BufSize: "4200k"}
+	profile480p  = QualityProfile{Name: "480p", Resolution: "854x480", Bitrate: "1400k", MaxRate: "1498k", BufSize: "2100k"}
+	profile360p  = QualityProfile{Name: "360p", Resolution: "640x360", Bitrate: "800k", MaxRate: "856k", BufSize: "1200k"}
+
 	// Quality profiles for adaptive bitrate streaming
-	qualityProfiles = []QualityProfile{
-		{
-			Name:       "1080p",
-			Resolution: "1920x1080",
-			Bitrate:    "5000k",
-			MaxRate:    "5350k",
-			BufSize:    "7500k",
-		},
-		{
-			Name:       "720p",
-			Resolution: "1280x720",
-			Bitrate:    "2800k",
-			MaxRate:    "2996k",
-			BufSize:    "4200k",
-		},
-		{
-			Name:       "480p",
-			Resolution: "854x480",
-			Bitrate:    "1400k",
-			MaxRate:    "1498k",
-			BufSize:    "2100k",
-		},
-		{
-			Name:       "360p",
-			Resolution: "640x360",
-			Bitrate:    "800k",
-			MaxRate:    "856k",
-			BufSize:    "1200k",
+	qualityProfiles = []QualityProfile{profile1080p, profile720p, profile480p, profile360p}
+
+	builtinProfileSets = map[string]ProfileSet{
+		// Default ladder for regular catalog titles
+		ProfileSetStandard: {Name: ProfileSetStandard, Profiles: qualityProfiles},
+		// Archive content, SD only to save storage and encoding time
+		ProfileSetLowCostSD: {Name: ProfileSetLowCostSD, Profiles: []QualityProfile{profile480p, profile360p}},
+		// Flagship titles, 4K on top of the standard ladder with HDR kept
+		ProfileSetPremium: {
+			Name:           ProfileSetPremium,
+			Profiles:       []QualityProfile{profile2160p, profile1080p, profile720p, profile480p},
+			HDRPassthrough: true,
 		},
 	}
 )
 
+// LookupProfileSet returns a profile set by name, unknown or empty names use the standard set
+func LookupProfileSet(name string) ProfileSet {
+	profileSetsMu.RLock()
+	defer profileSetsMu.RUnlock()
+	if set, ok := profileSets[name]; ok {
+		return set
+	}
+	return profileSets[ProfileSetStandard]
+}
+
+// Only narrows the set to the named profiles, in the order of the set.
+// Names the set does not have are ignored.
+func (s ProfileSet) Only(names []string) ProfileSet {
+	narrowed := ProfileSet{Name: s.Name, HDRPassthrough: s.HDRPassthrough}
+	for _, profile := range s.Profiles {
+		if slices.Contains(names, profile.Name) {
+			narrowed.Profiles = append(narrowed.Profiles, profile)
+		}
+	}
+	return narrowed
+}
+
 // NewTranscodingService creates a new transcoding service
 func NewTranscodingService(minioClient *minio.Client, bucketRaw, bucketProcessed string) TranscodingService {
 	return &transcodingService{
 		minioClient:     minioClient,
 		bucketRaw:       bucketRaw,
 		bucketProcessed: bucketProcessed,
-		tempDir:         "/tmp/transcoding",
+		tempDir:         TempDir,
 	}
 }
 
-// TranscodeToHLS transcodes a raw video file to HLS format with multiple quality levels
-func (s *transcodingService) TranscodeToHLS(ctx context.Context, movieID int64, rawFilePath string) (string, error) {
+// TranscodeToHLS transcodes a raw video file to HLS format with the quality levels of the profile set.
+// Output goes to the movie-{id}/v{version}/ prefix so the live version is never overwritten.
+// Segments are encrypted with AES-128 when key is set.
+// With keep set the renditions of the live version outside the profile set are copied into the new version.
+func (s *transcodingService) TranscodeToHLS(ctx context.Context, movieID int64, version int, rawFilePath string, profileSet ProfileSet, key *ContentKey, keep *KeptOutput) (*TranscodeResult, error) {
+	logger := zerolog.Ctx(ctx)
+
 	// Create temp directory for transcoding
-	workDir := filepath.Join(s.tempDir, fmt.Sprintf("movie-%d", movieID))
+	workDir := filepath.Join(s.tempDir, fmt.Sprintf("movie-%d-v%d", movieID, version))
 	if err := os.MkdirAll(workDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create work directory: %w", err)
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
 	}
 	defer os.RemoveAll(workDir) // Cleanup after transcoding
 
+	if err := writeKeyInfo(workDir, key); err != nil {
+		return nil, err
+	}
+
 	// Download raw video from MinIO
 	inputPath := filepath.Join(workDir, "input.mp4")
 	if err := s.downloadFromMinIO(ctx, rawFilePath, inputPath); err != nil {
-		return "", fmt.Errorf("failed to download raw video: %w", err)
+		return nil, fmt.Errorf("failed to download raw video: %w", err)
 	}
 
 	// Create output directory for HLS files
 	outputDir := filepath.Join(workDir, "output")
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create output directory: %w", err)
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Detect HDR sources, a failed probe is treated as SDR
+	sourceRange := RangeSDR
+	sourceHeight := 0
+	sourceKbps := 0
+	if info, err := probeVideo(ctx, inputPath); err != nil {
+		logger.Warn().Err(err).Msg("Failed to probe source, assuming SDR")
+	} else {
+		sourceRange = info.DynamicRange()
+		sourceHeight = info.Height
+		sourceKbps = info.BitrateKbps()
+	}
+
+	// HDR sources are tone-mapped for the SDR renditions so colors do not look washed out
+	toneMap := false
+	if sourceRange != RangeSDR {
+		toneMap = toneMapAvailable()
+		if !toneMap {
+			logger.Warn().Str("video_range", sourceRange).Msg("zscale/tonemap filters not available, SDR renditions are not tone-mapped")
+		}
+	}
+
+	// HDR renditions need an HDR source, a set that allows them and a 10-bit HEVC encoder
+	hdr := sourceRange != RangeSDR && profileSet.HDRPassthrough && hevcEncoderAvailable()
+	if sourceRange != RangeSDR && profileSet.HDRPassthrough && !hdr {
+		logger.Warn().Str("profile_set", profileSet.Name).Msg("libx265 not available, encoding without HDR passthrough")
+	}
+
+	// Per-title encoding, the ladder follows the source size and bitrate and how hard the title is to compress
+	complexity, err := analyzeComplexity(ctx, inputPath, workDir)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Complexity analysis failed, using static bitrates")
+	} else {
+		logger.Info().Float64("complexity", complexity).Msg("Complexity analyzed")
 	}
+	profiles := buildLadder(ctx, profileSet.Profiles, sourceHeight, sourceKbps, complexity)
 
 	// Transcode to multiple quality levels
-	variantPlaylists := []string{}
-	for _, profile := range qualityProfiles {
-		playlistPath, err := s.transcodeQuality(ctx, inputPath, outputDir, profile)
+	variants := []hlsVariant{}
+	for _, profile := range profiles {
+		playlistPath, err := s.transcodeQuality(ctx, inputPath, outputDir, profile, toneMap)
 		if err != nil {
 			// Log error but continue with other qualities
-			fmt.Printf("Warning: Failed to transcode %s: %v\n", profile.Name, err)
+			logger.Warn().Err(err).Str("rendition", profile.Name).Msg("Failed to transcode rendition")
 			continue
 		}
-		variantPlaylists = append(variantPlaylists, playlistPath)
+		variants = append(variants, hlsVariant{Playlist: playlistPath, Profile: profile, VideoRange: RangeSDR, ToneMap: toneMap})
 	}
 
-	if len(variantPlaylists) == 0 {
-		return "", fmt.Errorf("failed to transcode any quality level")
+	// Dedicated HDR rendition group, listed next to the SDR ladder in the master playlist
+	if hdr {
+		for _, profile := range profiles {
+			playlistPath, err := s.transcodeQualityHDR(ctx, inputPath, outputDir, profile, sourceRange)
+			if err != nil {
+				logger.Warn().Err(err).Str("rendition", profile.Name).Msg("Failed to transcode HDR rendition")
+				continue
+			}
+			variants = append(variants, hlsVariant{
+				Playlist:   playlistPath,
+				Profile:    profile,
+				VideoRange: sourceRange,
+				Codecs:     fmt.Sprintf("%s,mp4a.40.2", hevcMain10Codec(profile)),
+			})
+		}
 	}
 
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("failed to transcode any quality level")
+	}
+
+	// A job narrowed to some profiles publishes the other renditions of the live version unchanged
+	if keep != nil {
+		kept, err := s.downloadKeptRenditions(ctx, keep, outputDir, variants, toneMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy kept renditions: %w", err)
+		}
+		variants = append(variants, kept...)
+		sortVariants(variants)
+	}
+
+	// Additional audio languages become audio-only renditions
+	audio := s.transcodeAudioRenditions(ctx, inputPath, outputDir)
+
+	// Optional DASH manifest, repackaged from the HLS renditions
+	manifest := s.packageDASH(ctx, outputDir, variants, audio)
+
 	// Create master playlist
 	masterPlaylistPath := filepath.Join(outputDir, "master.m3u8")
-	if err := s.createMasterPlaylist(masterPlaylistPath, variantPlaylists); err != nil {
-		return "", fmt.Errorf("failed to create master playlist: %w", err)
+	if err := s.createMasterPlaylist(masterPlaylistPath, variants, audio); err != nil {
+		return nil, fmt.Errorf("failed to create master playlist: %w", err)
 	}
 
 	// Upload all HLS files to MinIO
-	hlsBaseURL, err := s.uploadHLSFiles(ctx, movieID, outputDir)
+	basePath := outputPrefix(movieID, version)
+	hlsBaseURL, err := s.uploadHLSFiles(ctx, basePath, outputDir)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload HLS files: %w", err)
+		return nil, fmt.Errorf("failed to upload HLS files: %w", err)
+	}
+
+	// Make sure every referenced segment made it to the bucket, repairing only broken renditions
+	if err := s.verifyAndRepair(ctx, basePath, inputPath, outputDir, variants, audio); err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+	if manifest != "" && !s.verifyDASH(ctx, basePath, outputDir) {
+		manifest = ""
+	}
+
+	result := &TranscodeResult{
+		HLSURL:           hlsBaseURL,
+		ComplexityFactor: complexity,
+		AudioTracks:      audioTracks(audio),
+		DASHURL:          dashURL(basePath, manifest),
+	}
+	for _, variant := range variants {
+		result.Renditions = append(result.Renditions, Rendition{
+			Name:        strings.TrimSuffix(variant.Playlist, ".m3u8"),
+			Resolution:  variant.Profile.Resolution,
+			BitrateKbps: bitrateKbps(variant.Profile.Bitrate),
+			VideoRange:  variant.VideoRange,
+		})
 	}
 
-	return hlsBaseURL, nil
+	return result, nil
 }
 
-// transcodeQuality transcodes video to a specific quality level
-func (s *transcodingService) transcodeQuality(ctx context.Context, inputPath, outputDir string, profile QualityProfile) (string, error) {
+// hlsVariant is one rendition listed in the master playlist
+type hlsVariant struct {
+	Playlist   string
+	Profile    QualityProfile
+	VideoRange string
+	Codecs     string // empty when the encoder output is not described
+	ToneMap    bool   // SDR rendition of an HDR source
+}
+
+// transcodeQuality transcodes video to a specific SDR quality level, tone-mapping HDR sources when toneMap is set
+func (s *transcodingService) transcodeQuality(ctx context.Context, inputPath, outputDir string, profile QualityProfile, toneMap bool) (string, error) {
 	// Output playlist name
 	playlistName := fmt.Sprintf("%s.m3u8", profile.Name)
 	playlistPath := filepath.Join(outputDir, playlistName)
@@ -137,128 +317,154 @@ func (s *transcodingService) transcodeQuality(ctx context.Context, inputPath, ou
 
 	// Detect available H.264 encoder
 	encoder := detectH264Encoder()
-	fmt.Printf("Using encoder: %s for %s\n", encoder, profile.Name)
-
-	// Build ffmpeg command based on encoder type
-	var args []string
-
-	if encoder == "h264_vaapi" {
-		// VAAPI hardware encoding (Intel/AMD)
-		// Upload to GPU and convert format properly
-		args = []string{
-			"-vaapi_device", "/dev/dri/renderD128",
-			"-i", inputPath,
-			"-vf", fmt.Sprintf("format=nv12,hwupload,scale_vaapi=w=%s:h=%s", getWidth(profile.Resolution), getHeight(profile.Resolution)),
-			"-c:v", "h264_vaapi",
-			"-b:v", profile.Bitrate,
-			"-maxrate", profile.MaxRate,
-			"-bufsize", profile.BufSize,
-			"-c:a", "aac",
-			"-b:a", "128k",
-			"-ac", "2",
-			"-f", "hls",
-			"-hls_time", "10",
-			"-hls_playlist_type", "vod",
-			"-hls_segment_type", "mpegts",
-			"-hls_segment_filename", segmentPattern,
-			playlistPath,
-		}
-	} else if encoder == "h264_nvenc" {
-		// NVIDIA NVENC hardware encoding
-		args = []string{
-			"-hwaccel", "cuda",
-			"-i", inputPath,
-			"-vf", fmt.Sprintf("scale=%s", profile.Resolution),
-			"-c:v", "h264_nvenc",
-			"-preset", "p4", // Medium preset for good quality/speed balance
-			"-b:v", profile.Bitrate,
-			"-maxrate", profile.MaxRate,
-			"-bufsize", profile.BufSize,
-			"-c:a", "aac",
-			"-b:a", "128k",
-			"-ac", "2",
-			"-f", "hls",
-			"-hls_time", "10",
-			"-hls_playlist_type", "vod",
-			"-hls_segment_type", "mpegts",
-			"-hls_segment_filename", segmentPattern,
-			playlistPath,
-		}
-	} else {
-		// Software encoding fallback (using available encoders)
-		args = []string{
-			"-i", inputPath,
-			"-vf", fmt.Sprintf("scale=%s", profile.Resolution),
-			"-c:v", encoder,
-		}
+	zerolog.Ctx(ctx).Info().Str("encoder", encoder).Str("rendition", profile.Name).Msg("Transcoding rendition")
 
-		// Add preset/options for specific encoders
-		if encoder == "h264" || encoder == "libx264" {
-			args = append(args, "-preset", "fast")
-		} else if encoder == "libopenh264" {
-			// OpenH264 doesn't need extra options - just use default settings
-			// The encoder will handle profile automatically
-		} else if encoder == "mpeg4" {
-			// MPEG-4 specific options
-			args = append(args, "-qscale:v", "5") // Good quality for MPEG-4
-		}
-
-		args = append(args,
-			"-b:v", profile.Bitrate,
-			"-maxrate", profile.MaxRate,
-			"-bufsize", profile.BufSize,
-			"-c:a", "aac",
-			"-b:a", "128k",
-			"-ac", "2",
-			"-f", "hls",
-			"-hls_time", "10",
-			"-hls_playlist_type", "vod",
-			"-hls_segment_type", "mpegts",
-			"-hls_segment_filename", segmentPattern,
-			playlistPath,
-		)
+	// Tone mapping runs in software before scaling
+	filterPrefix := ""
+	if toneMap {
+		filterPrefix = toneMapFilter + ","
 	}
 
+	// Only the first audio stream is muxed in, without -map ffmpeg picks the one with the most channels
+	pre, filter, codec := h264EncoderArgs(encoder, filterPrefix, profile.Resolution)
+	args := append(pre,
+		"-i", inputPath,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", filter,
+	)
+	args = append(args, codec...)
+	args = append(args,
+		"-b:v", profile.Bitrate,
+		"-maxrate", profile.MaxRate,
+		"-bufsize", profile.BufSize,
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-ac", "2",
+		"-f", "hls",
+		"-hls_time", "10",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_type", "mpegts",
+		"-hls_segment_filename", segmentPattern,
+	)
+	args = append(args, hlsEncryptionArgs(outputDir)...)
+	args = append(args, playlistPath)
+
 	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if err := runFFmpeg(ctx, cmd); err != nil {
+		return "", fmt.Errorf("ffmpeg command failed: %w", err)
+	}
+
+	return playlistName, nil
+}
+
+// softwareEncoderOptions returns the preset/options for a software encoder
+func softwareEncoderOptions(encoder string) []string {
+	switch encoder {
+	case "h264", "libx264":
+		return []string{"-preset", "fast"}
+	case "mpeg4":
+		// MPEG-4 specific options
+		return []string{"-qscale:v", "5"} // Good quality for MPEG-4
+	default:
+		// OpenH264 doesn't need extra options - just use default settings
+		// The encoder will handle profile automatically
+		return nil
+	}
+}
+
+// transcodeQualityHDR encodes a quality level as 10-bit HEVC in fMP4 segments
+// ffmpeg carries the source mastering display and content light metadata to libx265
+func (s *transcodingService) transcodeQualityHDR(ctx context.Context, inputPath, outputDir string, profile QualityProfile, videoRange string) (string, error) {
+	name := profile.Name + "_hdr"
+	playlistName := fmt.Sprintf("%s.m3u8", name)
+	playlistPath := filepath.Join(outputDir, playlistName)
+	segmentPattern := filepath.Join(outputDir, fmt.Sprintf("%s_%%03d.m4s", name))
+
+	transfer := "smpte2084"
+	x265Params := "hdr10=1:hdr10-opt=1:repeat-headers=1"
+	if videoRange == RangeHLG {
+		transfer = "arib-std-b67"
+		x265Params = "repeat-headers=1"
+	}
 
-	if err := cmd.Run(); err != nil {
+	args := []string{
+		"-i", inputPath,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", fmt.Sprintf("scale=%s", profile.Resolution),
+		"-c:v", "libx265",
+		"-preset", "medium",
+		"-pix_fmt", "yuv420p10le",
+		"-color_primaries", "bt2020",
+		"-color_trc", transfer,
+		"-colorspace", "bt2020nc",
+		"-x265-params", x265Params,
+		"-tag:v", "hvc1",
+		"-b:v", profile.Bitrate,
+		"-maxrate", profile.MaxRate,
+		"-bufsize", profile.BufSize,
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-ac", "2",
+		"-f", "hls",
+		"-hls_time", "10",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_type", "fmp4",
+		"-hls_fmp4_init_filename", fmt.Sprintf("%s_init.mp4", name),
+		"-hls_segment_filename", segmentPattern,
+	}
+	args = append(args, hlsEncryptionArgs(outputDir)...)
+	args = append(args, playlistPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	if err := runFFmpeg(ctx, cmd); err != nil {
 		return "", fmt.Errorf("ffmpeg command failed: %w", err)
 	}
 
 	return playlistName, nil
 }
 
-// detectH264Encoder detects the best available H.264 encoder with hardware support verification
-func detectH264Encoder() string {
-	// Check encoders
-	cmd := exec.Command("ffmpeg", "-hide_banner", "-encoders")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Printf("Warning: Failed to detect encoders, using mpeg4 fallback: %v\n", err)
-		return "mpeg4"
+// hevcMain10Codec returns the RFC 6381 codec string of an HEVC Main 10 rendition
+func hevcMain10Codec(profile QualityProfile) string {
+	height, _ := strconv.Atoi(getHeight(profile.Resolution))
+	switch {
+	case height >= 2160:
+		return "hvc1.2.4.L153.B0"
+	case height >= 1080:
+		return "hvc1.2.4.L123.B0"
+	default:
+		return "hvc1.2.4.L93.B0"
 	}
-	outputStr := string(output)
+}
 
-	// Skip all hardware encoders for now - they're causing issues
-	// Intel QSV parameters are incompatible
-	// VAAPI processing fails
-	// NVENC requires NVIDIA GPU
+// hevcEncoderAvailable reports whether ffmpeg was built with libx265
+func hevcEncoderAvailable() bool {
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-encoders").CombinedOutput()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(output), "libx265")
+}
 
-	fmt.Println("Skipping hardware encoders, using software encoding for compatibility")
+// detectH264Encoder returns the encoder chosen by ConfigureEncoder, or the first software encoder
+// ffmpeg has when it was not called
+func detectH264Encoder() string {
+	if selectedEncoder != "" {
+		return selectedEncoder
+	}
 
-	// Use software encoders directly - they work reliably
-	swEncoders := []string{"libopenh264", "mpeg4"}
-	for _, encoder := range swEncoders {
-		if strings.Contains(outputStr, encoder) {
-			fmt.Printf("Using software encoder: %s\n", encoder)
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-encoders").CombinedOutput()
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to detect encoders, using mpeg4 fallback")
+		return "mpeg4"
+	}
+	for _, encoder := range []string{"libopenh264", "mpeg4"} {
+		if strings.Contains(string(output), encoder) {
 			return encoder
 		}
 	}
 
 	// Ultimate fallback
-	fmt.Println("Warning: No preferred encoder found, using mpeg4")
+	zlog.Warn().Msg("No preferred encoder found, using mpeg4")
 	return "mpeg4"
 }
 
@@ -281,40 +487,51 @@ func getHeight(resolution string) string {
 }
 
 // createMasterPlaylist creates an HLS master playlist with all quality variants
-func (s *transcodingService) createMasterPlaylist(masterPath string, variantPlaylists []string) error {
+// VIDEO-RANGE lets players pick the HDR group only on HDR capable displays,
+// fMP4 segments of the HDR group need playlist version 7
+// Alternate audio tracks are listed as an audio group referenced by every variant
+func (s *transcodingService) createMasterPlaylist(masterPath string, variants []hlsVariant, audio []audioRendition) error {
+	hasHDR := false
+	for _, variant := range variants {
+		if variant.VideoRange != RangeSDR {
+			hasHDR = true
+			break
+		}
+	}
+
 	var content strings.Builder
 	content.WriteString("#EXTM3U\n")
-	content.WriteString("#EXT-X-VERSION:3\n")
+	if hasHDR {
+		content.WriteString("#EXT-X-VERSION:7\n")
+	} else {
+		content.WriteString("#EXT-X-VERSION:3\n")
+	}
 
-	// Add each variant playlist with its metadata
-	for i, playlist := range variantPlaylists {
-		// Extract quality name from playlist filename (e.g., "1080p.m3u8" -> "1080p")
-		qualityName := strings.TrimSuffix(filepath.Base(playlist), ".m3u8")
-
-		// Find matching quality profile
-		var profile *QualityProfile
-		for j := range qualityProfiles {
-			if qualityProfiles[j].Name == qualityName {
-				profile = &qualityProfiles[j]
-				break
-			}
+	multiAudio := len(audio) > 1
+	if multiAudio {
+		for _, rendition := range audio {
+			content.WriteString(audioMediaTag(rendition) + "\n")
 		}
+	}
 
-		if profile != nil {
-			// Parse resolution
-			parts := strings.Split(profile.Resolution, "x")
-			if len(parts) == 2 {
-				// Parse bitrate (remove 'k' suffix and convert to bits/sec)
-				bitrate := strings.TrimSuffix(profile.Bitrate, "k")
+	// Add each variant playlist with its metadata
+	for _, variant := range variants {
+		// Parse bitrate (remove 'k' suffix and convert to bits/sec)
+		bitrate := strings.TrimSuffix(variant.Profile.Bitrate, "k")
 
-				content.WriteString(fmt.Sprintf("#EXT-X-STREAM-INF:BANDWIDTH=%s000,RESOLUTION=%s\n", bitrate, profile.Resolution))
-				content.WriteString(fmt.Sprintf("%s\n", playlist))
-			}
-		} else {
-			// Fallback if profile not found
-			content.WriteString(fmt.Sprintf("#EXT-X-STREAM-INF:BANDWIDTH=%d\n", (len(variantPlaylists)-i)*1000000))
-			content.WriteString(fmt.Sprintf("%s\n", playlist))
+		attrs := fmt.Sprintf("BANDWIDTH=%s000,RESOLUTION=%s", bitrate, variant.Profile.Resolution)
+		if variant.Codecs != "" {
+			attrs += fmt.Sprintf(",CODECS=\"%s\"", variant.Codecs)
+		}
+		if hasHDR {
+			attrs += ",VIDEO-RANGE=" + variant.VideoRange
+		}
+		if multiAudio {
+			attrs += fmt.Sprintf(",AUDIO=\"%s\"", audioGroup)
 		}
+
+		content.WriteString(fmt.Sprintf("#EXT-X-STREAM-INF:%s\n", attrs))
+		content.WriteString(fmt.Sprintf("%s\n", variant.Playlist))
 	}
 
 	return os.WriteFile(masterPath, []byte(content.String()), 0644)
@@ -345,11 +562,9 @@ func (s *transcodingService) downloadFromMinIO(ctx context.Context, objectName,
 }
 
 // uploadHLSFiles uploads all HLS files from output directory to MinIO
-func (s *transcodingService) uploadHLSFiles(ctx context.Context, movieID int64, outputDir string) (string, error) {
-	// Base path in MinIO for this movie's HLS files
-	basePath := fmt.Sprintf("movie-%d", movieID)
-
+func (s *transcodingService) uploadHLSFiles(ctx context.Context, basePath, outputDir string) (string, error) {
 	// Walk through output directory and upload all files
+	// Failed uploads are only logged, verifyAndRepair uploads them again afterwards
 	err := filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -366,26 +581,9 @@ func (s *transcodingService) uploadHLSFiles(ctx context.Context, movieID int64,
 			return err
 		}
 
-		// MinIO object name
-		objectName := filepath.Join(basePath, relPath)
-
-		// Determine content type
-		contentType := "application/octet-stream"
-		if strings.HasSuffix(path, ".m3u8") {
-			contentType = "application/vnd.apple.mpegurl"
-		} else if strings.HasSuffix(path, ".ts") {
-			contentType = "video/mp2t"
+		if err := s.uploadHLSFile(ctx, basePath, outputDir, relPath); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to upload HLS file")
 		}
-
-		// Upload file to MinIO
-		_, err = s.minioClient.FPutObject(ctx, s.bucketProcessed, objectName, path, minio.PutObjectOptions{
-			ContentType: contentType,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to upload %s: %w", objectName, err)
-		}
-
-		fmt.Printf("Uploaded: %s\n", objectName)
 		return nil
 	})
 
@@ -397,3 +595,32 @@ func (s *transcodingService) uploadHLSFiles(ctx context.Context, movieID int64,
 	masterPlaylistURL := fmt.Sprintf("%s/master.m3u8", basePath)
 	return masterPlaylistURL, nil
 }
+
+// uploadHLSFile uploads a single file of the output directory to the processed bucket
+func (s *transcodingService) uploadHLSFile(ctx context.Context, basePath, outputDir, relPath string) error {
+	// MinIO object name
+	objectName := filepath.Join(basePath, relPath)
+
+	// Determine content type
+	contentType := "application/octet-stream"
+	if strings.HasSuffix(relPath, ".m3u8") {
+		contentType = "application/vnd.apple.mpegurl"
+	} else if strings.HasSuffix(relPath, ".mpd") {
+		contentType = "application/dash+xml"
+	} else if strings.HasSuffix(relPath, ".ts") {
+		contentType = "video/mp2t"
+	} else if strings.HasSuffix(relPath, ".m4s") || strings.HasSuffix(relPath, ".mp4") {
+		contentType = "video/mp4"
+	}
+
+	// Upload file to MinIO
+	_, err := s.minioClient.FPutObject(ctx, s.bucketProcessed, objectName, filepath.Join(outputDir, relPath), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", objectName, err)
+	}
+
+	zerolog.Ctx(ctx).Debug().Str("object", objectName).Msg("Uploaded")
+	return nil
+}
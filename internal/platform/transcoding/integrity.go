@@ -0,0 +1,212 @@
+package transcoding
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog"
+)
+
+// maxRepairAttempts bounds how often a broken rendition is re-uploaded or re-encoded
+const maxRepairAttempts = 2
+
+var mapURIPattern = regexp.MustCompile(`#EXT-X-MAP:URI="([^"]+)"`)
+
+// verifyAndRepair checks that each rendition playlist and every segment it references exists in the
+// processed bucket with a non-zero size. Missing objects are uploaded again when the local copy is fine,
+// otherwise only the affected rendition is re-encoded.
+func (s *transcodingService) verifyAndRepair(ctx context.Context, basePath, inputPath, outputDir string, variants []hlsVariant, audio []audioRendition) error {
+	for _, variant := range variants {
+		if err := s.repairPlaylist(ctx, basePath, outputDir, variant.Playlist, func() error {
+			return s.reencodeRendition(ctx, basePath, inputPath, outputDir, variant)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// The main audio track is part of the video renditions, only alternate tracks have own playlists
+	for _, rendition := range audio {
+		if rendition.Playlist == "" {
+			continue
+		}
+		if err := s.repairPlaylist(ctx, basePath, outputDir, rendition.Playlist, func() error {
+			return s.reencodeAudio(ctx, basePath, inputPath, outputDir, rendition)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// The master playlist is written last, check it the same way
+	for attempt := 0; ; attempt++ {
+		missing, err := s.missingFromBucket(ctx, basePath, []string{"master.m3u8"})
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if attempt == maxRepairAttempts {
+			return fmt.Errorf("master playlist is missing from the processed bucket")
+		}
+		if err := s.uploadHLSFile(ctx, basePath, outputDir, "master.m3u8"); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to upload HLS file")
+		}
+	}
+}
+
+// repairPlaylist verifies one rendition playlist, uploading missing objects again or calling reencode
+// when the local copy is broken as well
+func (s *transcodingService) repairPlaylist(ctx context.Context, basePath, outputDir, playlist string, reencode func() error) error {
+	for attempt := 0; ; attempt++ {
+		missing, err := s.missingObjects(ctx, basePath, outputDir, playlist)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if attempt == maxRepairAttempts {
+			return fmt.Errorf("rendition %s still has %d missing or empty objects", playlist, len(missing))
+		}
+
+		zerolog.Ctx(ctx).Warn().Str("playlist", playlist).Int("missing", len(missing)).Msg("Rendition has missing or empty objects, repairing")
+		if localFilesIntact(outputDir, missing) {
+			for _, relPath := range missing {
+				if err := s.uploadHLSFile(ctx, basePath, outputDir, relPath); err != nil {
+					zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to upload HLS file")
+				}
+			}
+			continue
+		}
+
+		if err := reencode(); err != nil {
+			return fmt.Errorf("failed to re-encode rendition %s: %w", playlist, err)
+		}
+	}
+}
+
+// reencodeAudio transcodes an alternate audio track again and uploads its files
+func (s *transcodingService) reencodeAudio(ctx context.Context, basePath, inputPath, outputDir string, rendition audioRendition) error {
+	name := strings.TrimSuffix(rendition.Playlist, ".m3u8")
+	matches, _ := filepath.Glob(filepath.Join(outputDir, name+"*"))
+	for _, match := range matches {
+		os.Remove(match)
+	}
+
+	if _, err := transcodeAudio(ctx, inputPath, outputDir, rendition.Stream); err != nil {
+		return err
+	}
+	return s.uploadPlaylistFiles(ctx, basePath, outputDir, rendition.Playlist)
+}
+
+// reencodeRendition transcodes a single rendition again and uploads its files
+func (s *transcodingService) reencodeRendition(ctx context.Context, basePath, inputPath, outputDir string, variant hlsVariant) error {
+	name := strings.TrimSuffix(variant.Playlist, ".m3u8")
+
+	// ffmpeg does not overwrite existing outputs, the "_[0-9]*" pattern keeps 1080p from matching 1080p_hdr
+	patterns := []string{variant.Playlist, name + "_[0-9]*", name + "_init.mp4"}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(outputDir, pattern))
+		for _, match := range matches {
+			os.Remove(match)
+		}
+	}
+
+	var err error
+	if variant.VideoRange == RangeSDR {
+		_, err = s.transcodeQuality(ctx, inputPath, outputDir, variant.Profile, variant.ToneMap)
+	} else {
+		_, err = s.transcodeQualityHDR(ctx, inputPath, outputDir, variant.Profile, variant.VideoRange)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.uploadPlaylistFiles(ctx, basePath, outputDir, variant.Playlist)
+}
+
+// uploadPlaylistFiles uploads a rendition playlist and the files it references
+func (s *transcodingService) uploadPlaylistFiles(ctx context.Context, basePath, outputDir, playlist string) error {
+	refs, err := playlistObjects(outputDir, playlist)
+	if err != nil {
+		return err
+	}
+	for _, relPath := range refs {
+		if err := s.uploadHLSFile(ctx, basePath, outputDir, relPath); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to upload HLS file")
+		}
+	}
+	return nil
+}
+
+// missingObjects returns the playlist and the files it references that are missing or empty in the bucket
+func (s *transcodingService) missingObjects(ctx context.Context, basePath, outputDir, playlist string) ([]string, error) {
+	refs, err := playlistObjects(outputDir, playlist)
+	if err != nil {
+		// A rendition whose local playlist is gone is re-encoded by the caller
+		return []string{playlist}, nil
+	}
+	return s.missingFromBucket(ctx, basePath, refs)
+}
+
+// missingFromBucket stats each object in the processed bucket
+func (s *transcodingService) missingFromBucket(ctx context.Context, basePath string, relPaths []string) ([]string, error) {
+	var missing []string
+	for _, relPath := range relPaths {
+		info, err := s.minioClient.StatObject(ctx, s.bucketProcessed, filepath.Join(basePath, relPath), minio.StatObjectOptions{})
+		if err != nil {
+			if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+				missing = append(missing, relPath)
+				continue
+			}
+			return nil, fmt.Errorf("failed to stat %s: %w", relPath, err)
+		}
+		if info.Size == 0 {
+			missing = append(missing, relPath)
+		}
+	}
+	return missing, nil
+}
+
+// playlistObjects lists a media playlist and every segment or init section it references
+func playlistObjects(outputDir, playlist string) ([]string, error) {
+	file, err := os.Open(filepath.Join(outputDir, playlist))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	objects := []string{playlist}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if match := mapURIPattern.FindStringSubmatch(line); match != nil {
+				objects = append(objects, match[1])
+			}
+			continue
+		}
+		objects = append(objects, line)
+	}
+	return objects, scanner.Err()
+}
+
+// localFilesIntact reports whether every file still exists locally with content, so uploading again is enough
+func localFilesIntact(outputDir string, relPaths []string) bool {
+	for _, relPath := range relPaths {
+		info, err := os.Stat(filepath.Join(outputDir, relPath))
+		if err != nil || info.Size() == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package transcoding
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog"
+)
+
+// KeptOutput is the live output of a title whose job was narrowed to some profiles.
+// The renditions the job does not encode are copied from it, so regenerating one quality keeps the others.
+type KeptOutput struct {
+	Prefix     string      // processed bucket prefix of the live version, e.g. movie-1/v3
+	Renditions []Rendition // renditions of the live version
+	ProfileSet ProfileSet  // the set before it was narrowed, its profiles describe the kept renditions
+}
+
+// downloadKeptRenditions copies the renditions of the live version that were not encoded again into outputDir.
+// They are published with the new version like encoded ones, their bitrates stay as they were.
+func (s *transcodingService) downloadKeptRenditions(ctx context.Context, keep *KeptOutput, outputDir string, encoded []hlsVariant, toneMap bool) ([]hlsVariant, error) {
+	var kept []hlsVariant
+	for _, rendition := range keep.Renditions {
+		playlist := rendition.Name + ".m3u8"
+		if slices.ContainsFunc(encoded, func(variant hlsVariant) bool { return variant.Playlist == playlist }) {
+			continue
+		}
+
+		if err := s.downloadProcessedFile(ctx, keep.Prefix, outputDir, playlist); err != nil {
+			return nil, err
+		}
+		refs, err := playlistObjects(outputDir, playlist)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", playlist, err)
+		}
+		for _, relPath := range refs[1:] {
+			if err := s.downloadProcessedFile(ctx, keep.Prefix, outputDir, relPath); err != nil {
+				return nil, err
+			}
+		}
+
+		variant := hlsVariant{Playlist: playlist, Profile: keptProfile(keep.ProfileSet, rendition), VideoRange: rendition.VideoRange}
+		if variant.VideoRange == "" || variant.VideoRange == RangeSDR {
+			// A repair re-encodes it like the encoded SDR renditions
+			variant.VideoRange = RangeSDR
+			variant.ToneMap = toneMap
+		} else {
+			variant.Codecs = fmt.Sprintf("%s,mp4a.40.2", hevcMain10Codec(variant.Profile))
+		}
+		kept = append(kept, variant)
+	}
+
+	if len(kept) > 0 {
+		zerolog.Ctx(ctx).Info().Int("kept", len(kept)).Str("from", keep.Prefix).Msg("Copied renditions that were not encoded again")
+	}
+	return kept, nil
+}
+
+// keptProfile describes a kept rendition by its profile in the set, at the bitrate it was encoded with
+func keptProfile(set ProfileSet, rendition Rendition) QualityProfile {
+	name := strings.TrimSuffix(rendition.Name, "_hdr")
+	for _, profile := range set.Profiles {
+		if profile.Name != name {
+			continue
+		}
+		if kbps := bitrateKbps(profile.Bitrate); kbps > 0 && rendition.BitrateKbps > 0 {
+			profile = scaleProfiles([]QualityProfile{profile}, float64(rendition.BitrateKbps)/float64(kbps))[0]
+		}
+		profile.Resolution = rendition.Resolution
+		return profile
+	}
+
+	// The set no longer has the profile, the rendition keeps its bitrate with the usual buffer
+	rate := fmt.Sprintf("%dk", rendition.BitrateKbps)
+	return QualityProfile{Name: name, Resolution: rendition.Resolution, Bitrate: rate, MaxRate: rate, BufSize: fmt.Sprintf("%dk", rendition.BitrateKbps*3/2)}
+}
+
+// sortVariants orders the master playlist like a full ladder, SDR before HDR and the largest picture first
+func sortVariants(variants []hlsVariant) {
+	sort.SliceStable(variants, func(a, b int) bool {
+		hdrA, hdrB := variants[a].VideoRange != RangeSDR, variants[b].VideoRange != RangeSDR
+		if hdrA != hdrB {
+			return !hdrA
+		}
+		return profileHeight(variants[a].Profile) > profileHeight(variants[b].Profile)
+	})
+}
+
+// downloadProcessedFile downloads a file of an output version from the processed bucket into outputDir
+func (s *transcodingService) downloadProcessedFile(ctx context.Context, basePath, outputDir, relPath string) error {
+	objectName := path.Join(basePath, relPath)
+	if err := s.minioClient.FGetObject(ctx, s.bucketProcessed, objectName, filepath.Join(outputDir, relPath), minio.GetObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to download %s: %w", objectName, err)
+	}
+	return nil
+}
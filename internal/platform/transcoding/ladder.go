@@ -0,0 +1,81 @@
+package transcoding
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// minLadderStep is how much more bitrate a rendition needs than the next smaller one to be worth encoding.
+// A bigger picture at nearly the same bitrate only looks softer and costs encode time and storage.
+const minLadderStep = 1.3
+
+// buildLadder fits the profile set to the analyzed source: renditions taller than the source are dropped,
+// bitrates follow the complexity factor and are capped at the source bitrate.
+// sourceKbps is 0 when the source bitrate is unknown.
+func buildLadder(ctx context.Context, profiles []QualityProfile, sourceHeight, sourceKbps int, complexity float64) []QualityProfile {
+	ladder := capProfilesToSourceBitrate(scaleProfiles(fitProfilesToSource(profiles, sourceHeight), complexity), sourceKbps)
+	names := make([]string, 0, len(ladder))
+	for _, profile := range ladder {
+		names = append(names, profile.Name+"@"+profile.Bitrate)
+	}
+	zerolog.Ctx(ctx).Info().
+		Int("source_height", sourceHeight).
+		Int("source_kbps", sourceKbps).
+		Float64("complexity", complexity).
+		Strs("renditions", names).
+		Msg("Bitrate ladder built")
+	return ladder
+}
+
+// capProfilesToSourceBitrate keeps renditions from spending more bits than the source has, max rate and
+// buffer size shrink by the same ratio. Renditions left too close to a smaller one are dropped,
+// the smallest rendition is always kept.
+func capProfilesToSourceBitrate(profiles []QualityProfile, sourceKbps int) []QualityProfile {
+	if sourceKbps <= 0 || len(profiles) == 0 {
+		return profiles
+	}
+
+	capped := make([]QualityProfile, len(profiles))
+	for i, profile := range profiles {
+		capped[i] = profile
+		if bitrate := bitrateKbps(profile.Bitrate); bitrate > sourceKbps {
+			capped[i] = scaleProfiles([]QualityProfile{profile}, float64(sourceKbps)/float64(bitrate))[0]
+		}
+	}
+
+	// Walk from the smallest picture up, profile sets list the largest first
+	order := make([]int, len(capped))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return profileHeight(capped[order[a]]) < profileHeight(capped[order[b]])
+	})
+
+	keep := make([]bool, len(capped))
+	lastKbps := 0
+	for _, i := range order {
+		bitrate := bitrateKbps(capped[i].Bitrate)
+		if lastKbps == 0 || float64(bitrate) >= math.Round(float64(lastKbps)*minLadderStep) {
+			keep[i] = true
+			lastKbps = bitrate
+		}
+	}
+
+	ladder := make([]QualityProfile, 0, len(capped))
+	for i, profile := range capped {
+		if keep[i] {
+			ladder = append(ladder, profile)
+		}
+	}
+	return ladder
+}
+
+func profileHeight(profile QualityProfile) int {
+	height, _ := strconv.Atoi(getHeight(profile.Resolution))
+	return height
+}
@@ -0,0 +1,162 @@
+package transcoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Dynamic ranges as written to the VIDEO-RANGE attribute of the master playlist
+const (
+	RangeSDR = "SDR"
+	RangePQ  = "PQ"  // HDR10, SMPTE ST 2084 transfer
+	RangeHLG = "HLG" // ARIB STD-B67 transfer
+)
+
+// VideoInfo describes the first video stream of a source file
+type VideoInfo struct {
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	PixFmt         string `json:"pix_fmt"`
+	ColorTransfer  string `json:"color_transfer"`
+	ColorPrimaries string `json:"color_primaries"`
+	ColorSpace     string `json:"color_space"`
+	BitRate        string `json:"bit_rate"` // bit/s, often missing for MKV streams
+}
+
+// BitrateKbps returns the bitrate of the video stream in kbit/s, 0 when unknown
+func (v VideoInfo) BitrateKbps() int {
+	bitRate, err := strconv.Atoi(v.BitRate)
+	if err != nil || bitRate <= 0 {
+		return 0
+	}
+	return bitRate / 1000
+}
+
+// DynamicRange returns PQ or HLG for HDR sources and SDR otherwise
+func (v VideoInfo) DynamicRange() string {
+	switch v.ColorTransfer {
+	case "smpte2084":
+		return RangePQ
+	case "arib-std-b67":
+		return RangeHLG
+	default:
+		return RangeSDR
+	}
+}
+
+// IsHDR reports whether the source uses an HDR transfer function
+func (v VideoInfo) IsHDR() bool {
+	return v.DynamicRange() != RangeSDR
+}
+
+// probeVideo reads the video stream properties with ffprobe
+func probeVideo(ctx context.Context, inputPath string) (*VideoInfo, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height,pix_fmt,color_transfer,color_primaries,color_space,bit_rate:format=bit_rate",
+		"-of", "json",
+		inputPath,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var result struct {
+		Streams []VideoInfo `json:"streams"`
+		Format  struct {
+			BitRate string `json:"bit_rate"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(result.Streams) == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+
+	// The container bitrate includes audio, as an upper bound for the video it is still good enough to cap the ladder
+	info := &result.Streams[0]
+	if info.BitrateKbps() == 0 {
+		info.BitRate = result.Format.BitRate
+	}
+	return info, nil
+}
+
+// AudioStream describes an audio stream of a source file
+type AudioStream struct {
+	Index    int    `json:"-"` // position among the audio streams, used as -map 0:a:{Index}
+	Codec    string `json:"codec_name"`
+	Channels int    `json:"channels"`
+	Tags     struct {
+		Language string `json:"language"`
+		Title    string `json:"title"`
+	} `json:"tags"`
+	Disposition struct {
+		Default int `json:"default"`
+	} `json:"disposition"`
+}
+
+// Language returns the ISO 639 language tag of the stream, "und" when it is not tagged
+func (a AudioStream) Language() string {
+	if a.Tags.Language == "" {
+		return "und"
+	}
+	return a.Tags.Language
+}
+
+// Name returns the title of the stream, falling back to its language
+func (a AudioStream) Name() string {
+	if a.Tags.Title != "" {
+		return a.Tags.Title
+	}
+	if a.Tags.Language != "" {
+		return a.Tags.Language
+	}
+	return fmt.Sprintf("Track %d", a.Index+1)
+}
+
+// probeAudio lists the audio streams of a source file with ffprobe
+func probeAudio(ctx context.Context, inputPath string) ([]AudioStream, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "a",
+		"-show_entries", "stream=codec_name,channels:stream_tags=language,title:stream_disposition=default",
+		"-of", "json",
+		inputPath,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var result struct {
+		Streams []AudioStream `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	for i := range result.Streams {
+		result.Streams[i].Index = i
+	}
+
+	return result.Streams, nil
+}
+
+// toneMapAvailable reports whether ffmpeg has the zscale and tonemap filters needed for HDR to SDR
+func toneMapAvailable() bool {
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-filters").CombinedOutput()
+	if err != nil {
+		return false
+	}
+	filters := string(output)
+	return strings.Contains(filters, " zscale ") && strings.Contains(filters, " tonemap ")
+}
+
+// toneMapFilter converts HDR frames to BT.709 SDR before scaling
+const toneMapFilter = "zscale=t=linear:npl=100,format=gbrpf32le,zscale=p=bt709,tonemap=tonemap=hable:desat=0,zscale=t=bt709:m=bt709:r=tv,format=yuv420p"
@@ -0,0 +1,118 @@
+package transcoding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/martinmanurung/cinestream/internal/platform/config"
+)
+
+// profileSets are the built-in sets with the configured ones, see ConfigureProfileSets
+var (
+	profileSetsMu sync.RWMutex
+	profileSets   = builtinProfileSets
+)
+
+// ConfigureProfileSets adds the quality profiles and profile sets from the config to the built-in ones.
+// A configured profile or set with a built-in name replaces it. It must be called at startup,
+// before any transcoding, by every process that uses profile sets. Calling it again replaces the
+// configured ladders, jobs already running keep the ladder they started with.
+func ConfigureProfileSets(cfg config.TranscodingConfig) error {
+	profiles := make(map[string]QualityProfile)
+	for _, set := range builtinProfileSets {
+		for _, profile := range set.Profiles {
+			profiles[profile.Name] = profile
+		}
+	}
+
+	for _, p := range cfg.Profiles {
+		profile := QualityProfile{Name: p.Name, Resolution: p.Resolution, Bitrate: p.Bitrate, MaxRate: p.MaxRate, BufSize: p.BufSize}
+		if err := validateProfile(profile); err != nil {
+			return err
+		}
+		profiles[profile.Name] = profile
+	}
+
+	// Built-in sets use the configured profile when one replaces a built-in profile
+	sets := make(map[string]ProfileSet, len(builtinProfileSets)+len(cfg.ProfileSets))
+	for name, builtin := range builtinProfileSets {
+		set := ProfileSet{Name: builtin.Name, HDRPassthrough: builtin.HDRPassthrough}
+		for _, profile := range builtin.Profiles {
+			set.Profiles = append(set.Profiles, profiles[profile.Name])
+		}
+		sets[name] = set
+	}
+	for name, s := range cfg.ProfileSets {
+		if len(s.Profiles) == 0 {
+			return fmt.Errorf("profile set %s has no profiles", name)
+		}
+		set := ProfileSet{Name: name, HDRPassthrough: s.HDRPassthrough}
+		for _, profileName := range s.Profiles {
+			profile, ok := profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile set %s uses unknown profile %s", name, profileName)
+			}
+			set.Profiles = append(set.Profiles, profile)
+		}
+		sets[name] = set
+	}
+
+	profileSetsMu.Lock()
+	profileSets = sets
+	profileSetsMu.Unlock()
+	return nil
+}
+
+// HasProfileSet reports whether a profile set with the name is known
+func HasProfileSet(name string) bool {
+	profileSetsMu.RLock()
+	defer profileSetsMu.RUnlock()
+	_, ok := profileSets[name]
+	return ok
+}
+
+// validateProfile checks the values ffmpeg gets, so a typo fails at startup instead of in every job
+func validateProfile(profile QualityProfile) error {
+	if profile.Name == "" {
+		return fmt.Errorf("quality profile without name")
+	}
+	width, errW := strconv.Atoi(getWidth(profile.Resolution))
+	height, errH := strconv.Atoi(getHeight(profile.Resolution))
+	if errW != nil || errH != nil || width <= 0 || height <= 0 || width%2 != 0 || height%2 != 0 {
+		return fmt.Errorf("quality profile %s: resolution %q must be WIDTHxHEIGHT with even sizes", profile.Name, profile.Resolution)
+	}
+	for _, rate := range []string{profile.Bitrate, profile.MaxRate, profile.BufSize} {
+		if bitrateKbps(rate) <= 0 || !strings.HasSuffix(rate, "k") {
+			return fmt.Errorf("quality profile %s: rate %q must be in kbit/s like 2800k", profile.Name, rate)
+		}
+	}
+	return nil
+}
+
+// fitProfilesToSource drops profiles taller than the source, upscaling only costs storage.
+// The smallest profile is kept when the source is below all of them.
+func fitProfilesToSource(profiles []QualityProfile, sourceHeight int) []QualityProfile {
+	if sourceHeight <= 0 {
+		return profiles
+	}
+
+	var fitted []QualityProfile
+	smallest := -1
+	smallestHeight := 0
+	for i, profile := range profiles {
+		height, _ := strconv.Atoi(getHeight(profile.Resolution))
+		if height <= sourceHeight {
+			fitted = append(fitted, profile)
+		}
+		if smallest < 0 || height < smallestHeight {
+			smallest, smallestHeight = i, height
+		}
+	}
+
+	if len(fitted) == 0 && smallest >= 0 {
+		return []QualityProfile{profiles[smallest]}
+	}
+	return fitted
+}
@@ -0,0 +1,26 @@
+package transcoding
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/martinmanurung/cinestream/internal/platform/logging"
+	"github.com/rs/zerolog"
+)
+
+// runFFmpeg runs an ffmpeg command with its output going to the job's logger instead of the worker's stderr.
+// Every line is logged at debug level, a failed command logs its last lines as an error.
+func runFFmpeg(ctx context.Context, cmd *exec.Cmd) error {
+	logger := zerolog.Ctx(ctx).With().Str("tool", filepath.Base(cmd.Path)).Logger()
+	output := logging.NewLineWriter(logger, zerolog.DebugLevel)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	output.Flush()
+	if err != nil && ctx.Err() == nil {
+		logger.Error().Err(err).Str("output", output.Tail()).Msg("Command failed")
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package transcoding
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ExtractSpeechAudio writes the first audio stream of the raw video as 16 kHz mono WAV,
+// the input speech-to-text models expect
+func (s *transcodingService) ExtractSpeechAudio(ctx context.Context, rawFilePath, destPath string) error {
+	sourceURL, err := s.minioClient.PresignedGetObject(ctx, s.bucketRaw, rawFilePath, 6*time.Hour, url.Values{})
+	if err != nil {
+		return fmt.Errorf("failed to presign raw video: %w", err)
+	}
+
+	output, err := exec.CommandContext(ctx, "ffmpeg",
+		"-v", "error",
+		"-i", sourceURL.String(),
+		"-map", "0:a:0",
+		"-vn",
+		"-ac", "1",
+		"-ar", "16000",
+		"-c:a", "pcm_s16le",
+		"-y", destPath,
+	).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to extract audio: %s", firstLine(output))
+	}
+	return nil
+}
+
+// UploadSubtitleDraft stores a generated WebVTT file in the raw bucket, which the HLS proxy does not serve.
+// Returns the object name.
+func (s *transcodingService) UploadSubtitleDraft(ctx context.Context, movieID int64, vtt []byte) (string, error) {
+	objectName := fmt.Sprintf("subtitle-drafts/movie-%d/%d.vtt", movieID, time.Now().UnixNano())
+	if _, err := s.minioClient.PutObject(ctx, s.bucketRaw, objectName, bytes.NewReader(vtt), int64(len(vtt)), minio.PutObjectOptions{
+		ContentType: "text/vtt",
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload subtitle draft: %w", err)
+	}
+	return objectName, nil
+}
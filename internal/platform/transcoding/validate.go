@@ -0,0 +1,195 @@
+package transcoding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrUnsupportedSource is returned by ValidateSource for files that cannot be transcoded
+var ErrUnsupportedSource = errors.New("unsupported source")
+
+// readErrorMarkers are ffmpeg messages of a source that could not be fetched, which says nothing about the file
+var readErrorMarkers = []string{
+	"Server returned",
+	"HTTP error",
+	"Connection refused",
+	"Connection reset",
+	"Connection timed out",
+	"Network is unreachable",
+	"Failed to resolve hostname",
+	"Input/output error",
+}
+
+// minSourceHeight rejects thumbnails and broken streams that would produce no usable rendition
+const minSourceHeight = 144
+
+// stillImageCodecs are "video" streams that only hold cover art or a single picture
+var stillImageCodecs = map[string]bool{
+	"mjpeg": true,
+	"png":   true,
+	"bmp":   true,
+	"gif":   true,
+	"webp":  true,
+}
+
+// SourceInfo describes a raw video that passed validation
+type SourceInfo struct {
+	DurationSeconds float64
+	Width           int
+	Height          int
+	VideoCodec      string
+	AudioCodec      string // empty when the source has no audio
+	Container       string
+}
+
+// Resolution returns the source size as WIDTHxHEIGHT
+func (i SourceInfo) Resolution() string {
+	return fmt.Sprintf("%dx%d", i.Width, i.Height)
+}
+
+// DurationMinutes returns the duration rounded up to whole minutes
+func (i SourceInfo) DurationMinutes() int {
+	return int((i.DurationSeconds + 59) / 60)
+}
+
+// ValidateSource probes the raw video through a presigned URL and decodes its first seconds.
+// Files that are no decodable video fail with an error wrapping ErrUnsupportedSource, a source that
+// could not be read, e.g. while MinIO is down, fails with a plain error so the job is tried again.
+func (s *transcodingService) ValidateSource(ctx context.Context, rawFilePath string) (*SourceInfo, error) {
+	sourceURL, err := s.minioClient.PresignedGetObject(ctx, s.bucketRaw, rawFilePath, 15*time.Minute, url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign raw video: %w", err)
+	}
+
+	info, err := probeSource(ctx, sourceURL.String())
+	if err == nil {
+		err = decodeSource(ctx, sourceURL.String())
+	}
+	if errors.Is(err, ErrUnsupportedSource) {
+		// The rejection only stands when the raw video is still there to read
+		if _, statErr := s.minioClient.StatObject(ctx, s.bucketRaw, rawFilePath, minio.StatObjectOptions{}); statErr != nil {
+			return nil, fmt.Errorf("failed to read raw video: %w", statErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// decodeSource decodes the first seconds of the video stream,
+// ffprobe only reads headers, a corrupt stream shows up when frames are decoded
+func decodeSource(ctx context.Context, sourceURL string) error {
+	output, err := exec.CommandContext(ctx, "ffmpeg",
+		"-v", "error",
+		"-xerror",
+		"-t", "2",
+		"-i", sourceURL,
+		"-map", "0:v:0",
+		"-f", "null", "-",
+	).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return sourceError(err, output, "video stream cannot be decoded: "+firstLine(output))
+	}
+	return nil
+}
+
+// sourceError wraps ErrUnsupportedSource only when ffmpeg read the file and found it invalid.
+// A source it could not fetch or an ffmpeg that did not run is not the file's fault.
+func sourceError(err error, output []byte, reason string) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to run ffmpeg: %w", err)
+	}
+	for _, marker := range readErrorMarkers {
+		if bytes.Contains(output, []byte(marker)) {
+			return fmt.Errorf("failed to read source: %s", firstLine(output))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedSource, reason)
+}
+
+// probeSource reads container and stream properties and rejects sources the ladder cannot be built from
+func probeSource(ctx context.Context, inputPath string) (*SourceInfo, error) {
+	output, err := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration,format_name:stream=codec_type,codec_name,width,height",
+		"-of", "json",
+		inputPath,
+	).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var stderr []byte
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			stderr = exitErr.Stderr
+		}
+		return nil, sourceError(err, stderr, "file is not a readable media file")
+	}
+
+	var result struct {
+		Format struct {
+			Duration   string `json:"duration"`
+			FormatName string `json:"format_name"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			CodecName string `json:"codec_name"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	info := &SourceInfo{Container: result.Format.FormatName}
+	for _, stream := range result.Streams {
+		switch {
+		case stream.CodecType == "video" && info.VideoCodec == "" && !stillImageCodecs[stream.CodecName]:
+			info.VideoCodec = stream.CodecName
+			info.Width = stream.Width
+			info.Height = stream.Height
+		case stream.CodecType == "audio" && info.AudioCodec == "":
+			info.AudioCodec = stream.CodecName
+		}
+	}
+
+	if info.VideoCodec == "" {
+		return nil, fmt.Errorf("%w: no video stream found", ErrUnsupportedSource)
+	}
+	if info.Height < minSourceHeight || info.Width <= 0 {
+		return nil, fmt.Errorf("%w: resolution %s is below %dp", ErrUnsupportedSource, info.Resolution(), minSourceHeight)
+	}
+
+	info.DurationSeconds, _ = strconv.ParseFloat(result.Format.Duration, 64)
+	if info.DurationSeconds <= 0 {
+		return nil, fmt.Errorf("%w: duration is unknown", ErrUnsupportedSource)
+	}
+
+	return info, nil
+}
+
+// firstLine returns the first line of ffmpeg output for error messages
+func firstLine(output []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if line == "" {
+		return "no frames decoded"
+	}
+	return line
+}
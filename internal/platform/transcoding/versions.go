@@ -0,0 +1,62 @@
+package transcoding
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog"
+)
+
+// versionDirPattern matches the v{n} directory under movie-{id}/
+var versionDirPattern = regexp.MustCompile(`^v(\d+)$`)
+
+// outputPrefix is the processed bucket prefix of one output version
+func outputPrefix(movieID int64, version int) string {
+	return fmt.Sprintf("movie-%d/v%d", movieID, version)
+}
+
+// RemoveSupersededVersions deletes processed output older than the version before the live one.
+// The previous version is kept so viewers that loaded it before the switch can finish their session,
+// newer versions still being transcoded are never touched. Unversioned legacy output counts as version 0,
+// other directories such as subtitles/ and audio/ are shared by all versions and kept.
+func (s *transcodingService) RemoveSupersededVersions(ctx context.Context, movieID int64, liveVersion int) error {
+	moviePrefix := fmt.Sprintf("movie-%d/", movieID)
+
+	removed := 0
+	for object := range s.minioClient.ListObjects(ctx, s.bucketProcessed, minio.ListObjectsOptions{
+		Prefix:    moviePrefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list processed files: %w", object.Err)
+		}
+
+		version := 0
+		relPath := strings.TrimPrefix(object.Key, moviePrefix)
+		if dir, _, found := strings.Cut(relPath, "/"); found {
+			match := versionDirPattern.FindStringSubmatch(dir)
+			if match == nil {
+				continue
+			}
+			version, _ = strconv.Atoi(match[1])
+		}
+
+		if version >= liveVersion-1 {
+			continue
+		}
+
+		if err := s.minioClient.RemoveObject(ctx, s.bucketProcessed, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", object.Key, err)
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		zerolog.Ctx(ctx).Info().Int("removed", removed).Msg("Removed superseded HLS files")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix = "partner_usage"
+	// Monthly counters are kept for a bit more than a year of reports
+	counterTTL = 400 * 24 * time.Hour
+
+	fieldRequests = "requests"
+	fieldBytes    = "bytes"
+)
+
+// MonthLayout is the format of a usage month, e.g. "2025-12"
+const MonthLayout = "2006-01"
+
+// Totals is the traffic of an API key in one month
+type Totals struct {
+	Requests int64
+	Bytes    int64
+}
+
+// Meter counts requests and bytes per partner API key in monthly Redis hashes.
+// Counters are shared by every API instance, so quotas hold across the cluster.
+type Meter struct {
+	client *redis.Client
+}
+
+// NewMeter creates a new Redis backed meter
+func NewMeter(client *redis.Client) *Meter {
+	return &Meter{client: client}
+}
+
+// Record adds one request with its response size to the current month of the key
+func (m *Meter) Record(ctx context.Context, keyID int64, bytes int64) error {
+	key := counterKey(keyID, time.Now().UTC())
+	pipe := m.client.Pipeline()
+	pipe.HIncrBy(ctx, key, fieldRequests, 1)
+	pipe.HIncrBy(ctx, key, fieldBytes, bytes)
+	pipe.Expire(ctx, key, counterTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Month returns the totals of the key in the month containing at, zero when nothing was recorded
+func (m *Meter) Month(ctx context.Context, keyID int64, at time.Time) (Totals, error) {
+	values, err := m.client.HMGet(ctx, counterKey(keyID, at.UTC()), fieldRequests, fieldBytes).Result()
+	if err != nil {
+		return Totals{}, fmt.Errorf("failed to read usage counters: %w", err)
+	}
+	return Totals{Requests: parseCounter(values[0]), Bytes: parseCounter(values[1])}, nil
+}
+
+// counterKey returns the hash of a key's month, e.g. partner_usage:7:2025-12
+func counterKey(keyID int64, at time.Time) string {
+	return fmt.Sprintf("%s:%d:%s", keyPrefix, keyID, at.Format(MonthLayout))
+}
+
+func parseCounter(value interface{}) int64 {
+	s, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
@@ -0,0 +1,159 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	keyPrefix = "movie_views"
+	// Set of days with counters that were not flushed yet
+	pendingDaysKey = keyPrefix + ":pending_days"
+	// Held while a worker flushes, so several workers do not store the same counters twice
+	flushLockKey = keyPrefix + ":flush_lock"
+	flushLockTTL = time.Minute
+	// Unflushed counters survive a worker outage of a few days
+	counterTTL = 7 * 24 * time.Hour
+	// A viewer starting the same movie again on the same day is not counted twice
+	seenTTL = 25 * time.Hour
+)
+
+// DayLayout is the format of a counting day, days are in UTC
+const DayLayout = "2006-01-02"
+
+// Counter counts playback starts per movie and day in Redis hashes.
+// Counting is cheap on the streaming path, the worker flushes the hashes to MySQL with Flush.
+type Counter struct {
+	client *redis.Client
+}
+
+// NewCounter creates a new Redis backed view counter
+func NewCounter(client *redis.Client) *Counter {
+	return &Counter{client: client}
+}
+
+// RecordStart counts a playback start of the movie, repeated starts of the same viewer on the same day count once
+func (c *Counter) RecordStart(ctx context.Context, movieID int64, viewerID string) error {
+	day := time.Now().UTC().Format(DayLayout)
+
+	first, err := c.client.SetNX(ctx, fmt.Sprintf("%s:seen:%s:%d:%s", keyPrefix, day, movieID, viewerID), 1, seenTTL).Result()
+	if err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+	if !first {
+		return nil
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(ctx, counterKey(day), strconv.FormatInt(movieID, 10), 1)
+	pipe.Expire(ctx, counterKey(day), counterTTL)
+	pipe.SAdd(ctx, pendingDaysKey, day)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+	return nil
+}
+
+// Flush hands the counters of every pending day to store and removes them from Redis once stored.
+// Counters are renamed before they are read, so views recorded during a flush go to the next one.
+// A failed store is retried with the same counters on the next flush.
+func (c *Counter) Flush(ctx context.Context, store func(ctx context.Context, day time.Time, counts map[int64]int64) error) error {
+	locked, err := c.client.SetNX(ctx, flushLockKey, 1, flushLockTTL).Result()
+	if err != nil {
+		return fmt.Errorf("failed to lock view flush: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer c.client.Del(ctx, flushLockKey)
+
+	days, err := c.client.SMembers(ctx, pendingDaysKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list pending view days: %w", err)
+	}
+
+	today := time.Now().UTC().Format(DayLayout)
+	for _, day := range days {
+		date, err := time.Parse(DayLayout, day)
+		if err != nil {
+			c.client.SRem(ctx, pendingDaysKey, day)
+			continue
+		}
+
+		if err := c.flushDay(ctx, day, date, store); err != nil {
+			return err
+		}
+
+		// Past days get no new views, so they are done once their counters are gone
+		if day != today {
+			exists, err := c.client.Exists(ctx, counterKey(day), flushingKey(day)).Result()
+			if err != nil {
+				return fmt.Errorf("failed to check view counters of %s: %w", day, err)
+			}
+			if exists == 0 {
+				c.client.SRem(ctx, pendingDaysKey, day)
+			}
+		}
+	}
+	return nil
+}
+
+// flushDay stores the counters of one day, a leftover of a failed flush is stored first
+func (c *Counter) flushDay(ctx context.Context, day string, date time.Time, store func(ctx context.Context, day time.Time, counts map[int64]int64) error) error {
+	leftover, err := c.client.Exists(ctx, flushingKey(day)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check view counters of %s: %w", day, err)
+	}
+	if leftover == 0 {
+		pending, err := c.client.Exists(ctx, counterKey(day)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check view counters of %s: %w", day, err)
+		}
+		if pending == 0 {
+			return nil
+		}
+		if err := c.client.Rename(ctx, counterKey(day), flushingKey(day)).Err(); err != nil {
+			return fmt.Errorf("failed to claim view counters of %s: %w", day, err)
+		}
+	}
+
+	values, err := c.client.HGetAll(ctx, flushingKey(day)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read view counters of %s: %w", day, err)
+	}
+
+	counts := make(map[int64]int64, len(values))
+	for field, value := range values {
+		movieID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Str("movie_id", field).Str("day", day).Msg("Skipping invalid view counter")
+			continue
+		}
+		views, _ := strconv.ParseInt(value, 10, 64)
+		if views > 0 {
+			counts[movieID] = views
+		}
+	}
+
+	if len(counts) > 0 {
+		if err := store(ctx, date, counts); err != nil {
+			return fmt.Errorf("failed to store view counters of %s: %w", day, err)
+		}
+	}
+	return c.client.Del(ctx, flushingKey(day)).Err()
+}
+
+// counterKey returns the hash of a day, e.g. movie_views:2025-12-27
+func counterKey(day string) string {
+	return keyPrefix + ":" + day
+}
+
+// flushingKey returns the hash of a day while it is being flushed
+func flushingKey(day string) string {
+	return keyPrefix + ":flushing:" + day
+}
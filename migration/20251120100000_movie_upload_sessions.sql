@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movie_upload_sessions (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    upload_id VARCHAR(255) NOT NULL COMMENT 'Multipart upload ID dari MinIO',
+    object_name VARCHAR(255) NOT NULL COMMENT 'Path object di bucket raw-videos',
+    file_name VARCHAR(255) NOT NULL,
+    file_size BIGINT NOT NULL,
+    status ENUM('IN_PROGRESS', 'COMPLETED', 'ABORTED') NOT NULL DEFAULT 'IN_PROGRESS',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_movie_upload_sessions_movie_id (movie_id),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_upload_sessions;
+-- +goose StatementEnd
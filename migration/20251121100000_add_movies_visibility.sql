@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+  ADD COLUMN visibility ENUM('PUBLIC', 'PRIVATE') NOT NULL DEFAULT 'PUBLIC' COMMENT 'PRIVATE untuk judul pre-release, poster/trailer hanya via signed URL' AFTER price;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN visibility;
+-- +goose StatementEnd
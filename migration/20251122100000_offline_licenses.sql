@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE offline_licenses (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    license_id VARCHAR(36) NOT NULL UNIQUE,
+    user_ext_id VARCHAR(100) NOT NULL,
+    movie_id BIGINT NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL COMMENT 'Batas waktu konten offline bisa diputar tanpa koneksi',
+    renewed_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL COMMENT 'NOT NULL berarti lisensi masuk revocation list',
+    revoke_reason VARCHAR(255) NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_offline_licenses_user_revoked (user_ext_id, revoked_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS offline_licenses;
+-- +goose StatementEnd
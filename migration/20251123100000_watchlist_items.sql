@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE watchlist_items (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_ext_id VARCHAR(100) NOT NULL,
+    movie_id BIGINT NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+
+    -- Satu film hanya sekali per watchlist user
+    UNIQUE KEY uk_watchlist_user_movie (user_ext_id, movie_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watchlist_items;
+-- +goose StatementEnd
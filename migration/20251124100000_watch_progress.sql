@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE watch_progress (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_ext_id VARCHAR(100) NOT NULL,
+    movie_id BIGINT NOT NULL,
+    position_seconds INT NOT NULL DEFAULT 0 COMMENT 'Posisi terakhir pemutaran',
+    duration_seconds INT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_watch_progress_user_movie (user_ext_id, movie_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watch_progress;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE watch_parties (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    host_ext_id VARCHAR(100) NOT NULL,
+    movie_id BIGINT NOT NULL,
+    mode ENUM('ENTITLED', 'HOST_PAYS') NOT NULL DEFAULT 'ENTITLED' COMMENT 'ENTITLED: setiap peserta harus punya akses, HOST_PAYS: akses host berlaku untuk tamu',
+    status ENUM('ACTIVE', 'ENDED') NOT NULL DEFAULT 'ACTIVE',
+    ended_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_watch_parties_host (host_ext_id)
+) ENGINE=InnoDB;
+
+CREATE TABLE watch_party_members (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    party_id BIGINT NOT NULL,
+    user_ext_id VARCHAR(100) NOT NULL,
+    status ENUM('INVITED', 'JOINED') NOT NULL DEFAULT 'INVITED',
+    joined_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (party_id) REFERENCES watch_parties(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_watch_party_member (party_id, user_ext_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watch_party_members;
+DROP TABLE IF EXISTS watch_parties;
+-- +goose StatementEnd
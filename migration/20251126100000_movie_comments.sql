@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movie_comments (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    user_ext_id VARCHAR(100) NOT NULL,
+    timestamp_seconds INT NOT NULL COMMENT 'Posisi di film (detik) tempat komentar ditampilkan',
+    body VARCHAR(500) NOT NULL,
+    status ENUM('VISIBLE', 'HIDDEN') NOT NULL DEFAULT 'VISIBLE',
+    moderated_by VARCHAR(100) NULL,
+    moderated_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_movie_comments_overlay (movie_id, status, timestamp_seconds),
+    INDEX idx_movie_comments_user_created (user_ext_id, created_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_comments;
+-- +goose StatementEnd
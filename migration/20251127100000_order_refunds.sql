@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+  MODIFY COLUMN payment_status ENUM('PENDING', 'PAID', 'FAILED', 'EXPIRED', 'CANCELLED', 'REFUNDED') NOT NULL DEFAULT 'PENDING';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE order_refunds (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    order_id BIGINT NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL COMMENT 'Jumlah yang dikembalikan ke user',
+    reason VARCHAR(255) NULL,
+    gateway_ref VARCHAR(255) NULL COMMENT 'ID refund dari Midtrans',
+    refunded_by VARCHAR(100) NOT NULL COMMENT 'ext_id admin yang memproses refund',
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE RESTRICT,
+    INDEX idx_order_refunds_order (order_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_refunds;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE orders
+  MODIFY COLUMN payment_status ENUM('PENDING', 'PAID', 'FAILED', 'EXPIRED') NOT NULL DEFAULT 'PENDING';
+-- +goose StatementEnd
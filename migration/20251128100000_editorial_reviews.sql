@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('USER', 'ADMIN', 'CONTENT_MANAGER') NOT NULL DEFAULT 'USER';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE editorial_reviews (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    author_ext_id VARCHAR(100) NOT NULL COMMENT 'Staff (ADMIN/CONTENT_MANAGER) penulis review',
+    headline VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL COMMENT 'Isi review dalam format Markdown',
+    score DECIMAL(3,1) NOT NULL COMMENT 'Skor editorial 0.0 - 10.0',
+    status ENUM('DRAFT', 'PUBLISHED') NOT NULL DEFAULT 'DRAFT',
+    published_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_editorial_reviews_movie_status (movie_id, status, published_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS editorial_reviews;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE users SET role = 'USER' WHERE role = 'CONTENT_MANAGER';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('USER', 'ADMIN') NOT NULL DEFAULT 'USER';
+-- +goose StatementEnd
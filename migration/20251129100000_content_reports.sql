@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN taken_down_at TIMESTAMP NULL COMMENT 'Diisi saat takedown darurat, film disembunyikan dan tidak bisa diputar',
+    ADD COLUMN takedown_reason VARCHAR(255) NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE content_reports (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    target_type ENUM('MOVIE', 'REVIEW') NOT NULL,
+    target_id BIGINT NOT NULL COMMENT 'ID film atau editorial review yang dilaporkan',
+    movie_id BIGINT NOT NULL COMMENT 'Film terkait, untuk filter antrian admin',
+    category ENUM('COPYRIGHT', 'ABUSE', 'OTHER') NOT NULL,
+    reporter_ext_id VARCHAR(100) NULL COMMENT 'NULL jika pelapor tidak login',
+    reporter_name VARCHAR(255) NOT NULL,
+    reporter_email VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    status ENUM('OPEN', 'IN_REVIEW', 'RESOLVED', 'REJECTED') NOT NULL DEFAULT 'OPEN',
+    resolution_note VARCHAR(500) NULL,
+    handled_by VARCHAR(100) NULL,
+    handled_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_content_reports_status_created (status, created_at),
+    INDEX idx_content_reports_movie (movie_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS content_reports;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP COLUMN taken_down_at,
+    DROP COLUMN takedown_reason;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE payment_notifications (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    order_id BIGINT NOT NULL,
+    transaction_id VARCHAR(255) NOT NULL COMMENT 'transaction_id dari Midtrans',
+    transaction_status VARCHAR(50) NOT NULL,
+    fraud_status VARCHAR(50) NOT NULL DEFAULT '',
+    status_code VARCHAR(10) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+
+    -- Retry notifikasi yang sama dari Midtrans tidak diproses dua kali
+    UNIQUE KEY uk_payment_notification (transaction_id, transaction_status, fraud_status)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payment_notifications;
+-- +goose StatementEnd
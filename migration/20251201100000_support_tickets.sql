@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE support_tickets (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_ext_id VARCHAR(100) NOT NULL,
+    order_id BIGINT NULL COMMENT 'Order terkait (keluhan billing)',
+    movie_id BIGINT NULL COMMENT 'Film terkait (keluhan playback)',
+    category ENUM('BILLING', 'PLAYBACK', 'ACCOUNT', 'OTHER') NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    status ENUM('OPEN', 'IN_PROGRESS', 'RESOLVED', 'CLOSED') NOT NULL DEFAULT 'OPEN',
+    assigned_to VARCHAR(100) NULL COMMENT 'ext_id admin yang menangani',
+    resolved_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE SET NULL,
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE SET NULL,
+    INDEX idx_support_tickets_user (user_ext_id, created_at),
+    INDEX idx_support_tickets_status (status, created_at),
+    INDEX idx_support_tickets_assignee (assigned_to)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS support_tickets;
+-- +goose StatementEnd
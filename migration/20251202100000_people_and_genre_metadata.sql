@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE genres
+    ADD COLUMN description VARCHAR(1000) NOT NULL DEFAULT '',
+    ADD COLUMN artwork_url VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE people (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    bio TEXT NULL,
+    photo_url VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Sutradara yang sudah ada diambil dari kolom movies.director
+INSERT INTO people (name)
+SELECT DISTINCT TRIM(director) FROM movies
+WHERE director IS NOT NULL AND TRIM(director) <> '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN director_id BIGINT NULL COMMENT 'Referensi ke people, movies.director tetap disimpan sebagai nama tampilan',
+    ADD CONSTRAINT fk_movies_director FOREIGN KEY (director_id) REFERENCES people(id) ON DELETE SET NULL,
+    ADD INDEX idx_movies_director_id (director_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE movies
+JOIN people ON people.name = TRIM(movies.director)
+SET movies.director_id = people.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP FOREIGN KEY fk_movies_director,
+    DROP INDEX idx_movies_director_id,
+    DROP COLUMN director_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS people;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE genres
+    DROP COLUMN description,
+    DROP COLUMN artwork_url;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN payment_provider VARCHAR(32) NOT NULL DEFAULT 'midtrans' COMMENT 'Payment gateway yang membuat transaksi (midtrans, stripe)' AFTER payment_status;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN payment_provider;
+-- +goose StatementEnd
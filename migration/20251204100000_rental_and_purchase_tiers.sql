@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN purchase_price DECIMAL(10, 2) NULL COMMENT 'Harga beli permanen, NULL jika film hanya bisa disewa' AFTER price;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN order_type ENUM('rental', 'purchase') NOT NULL DEFAULT 'rental' COMMENT 'rental = akses terbatas waktu, purchase = akses permanen' AFTER amount;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN order_type;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN purchase_price;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN profile_set VARCHAR(32) NOT NULL DEFAULT 'standard' COMMENT 'Set profil transcoding: standard, low_cost_sd, premium' AFTER visibility;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN profile_set;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE password_reset_tokens (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_ext_id VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(255) NOT NULL UNIQUE COMMENT 'SHA256 dari token, token asli hanya dikirim lewat email',
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Diisi saat token dipakai, token hanya berlaku sekali',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_password_reset_user_ext_id (user_ext_id),
+    INDEX idx_password_reset_expires_at (expires_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_reset_tokens;
+-- +goose StatementEnd
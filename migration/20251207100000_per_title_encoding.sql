@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    ADD COLUMN complexity_factor DECIMAL(4, 2) NULL COMMENT 'Faktor kompleksitas dari analisis CRF, 1.00 = bitrate standar' AFTER hls_playlist_url;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE movie_renditions (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    name VARCHAR(32) NOT NULL COMMENT 'Nama rendition, contoh 1080p atau 1080p_hdr',
+    resolution VARCHAR(16) NOT NULL,
+    bitrate_kbps INT NOT NULL COMMENT 'Bitrate video yang dipilih untuk judul ini',
+    video_range VARCHAR(8) NOT NULL DEFAULT 'SDR' COMMENT 'SDR, PQ atau HLG',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_movie_renditions_movie_id (movie_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_renditions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movie_videos DROP COLUMN complexity_factor;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    ADD COLUMN output_version INT NOT NULL DEFAULT 0 COMMENT 'Versi output HLS yang sedang live, 0 = output lama tanpa versi' AFTER hls_playlist_url,
+    ADD COLUMN latest_output_version INT NOT NULL DEFAULT 0 COMMENT 'Versi terakhir yang dipesan worker, bisa lebih besar dari output_version saat transcoding' AFTER output_version;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    DROP COLUMN latest_output_version,
+    DROP COLUMN output_version;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN disabled_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Diisi saat akun dinonaktifkan admin, login dan refresh ditolak' AFTER role;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN disabled_at;
+-- +goose StatementEnd
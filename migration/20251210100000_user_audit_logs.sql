@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_audit_logs (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_ext_id VARCHAR(255) NOT NULL,
+    event VARCHAR(50) NOT NULL COMMENT 'account_locked, account_unlocked',
+    actor_ext_id VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Admin yang memicu event, kosong jika oleh sistem',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    details VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_audit_logs_user_created (user_ext_id, created_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_audit_logs;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE stream_sessions (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    session_id VARCHAR(36) NOT NULL UNIQUE,
+    user_ext_id VARCHAR(100) NOT NULL,
+    movie_id BIGINT NOT NULL COMMENT 'Film terakhir yang diputar perangkat',
+    device_fingerprint VARCHAR(64) NOT NULL COMMENT 'Header X-Device-ID atau hash user agent',
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255) NOT NULL,
+
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Diperbarui oleh request HLS, sesi idle tidak dihitung ke batas stream',
+    ended_at TIMESTAMP NULL COMMENT 'Diisi saat user mengeluarkan perangkat',
+    end_reason VARCHAR(50) NULL,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_stream_sessions_user_device (user_ext_id, device_fingerprint),
+    INDEX idx_stream_sessions_user_seen (user_ext_id, last_seen_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stream_sessions;
+-- +goose StatementEnd
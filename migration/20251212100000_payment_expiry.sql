@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN payment_expiry_minutes INT NULL COMMENT 'Masa berlaku link pembayaran dalam menit, NULL memakai default konfigurasi' AFTER purchase_price;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN payment_expiry_minutes;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subtitles (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    language VARCHAR(16) NOT NULL COMMENT 'Tag bahasa BCP 47, misalnya en atau pt-BR',
+    label VARCHAR(64) NOT NULL COMMENT 'Nama track yang ditampilkan di player',
+    object_name VARCHAR(255) NOT NULL COMMENT 'File WebVTT di bucket processed',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Dipilih otomatis jika player tidak punya preferensi bahasa',
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_subtitles_movie_language (movie_id, language)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subtitles;
+-- +goose StatementEnd
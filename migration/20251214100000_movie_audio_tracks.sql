@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movie_audio_tracks (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    language VARCHAR(16) NOT NULL COMMENT 'Kode bahasa dari tag stream, und jika tidak ada',
+    name VARCHAR(100) NOT NULL COMMENT 'Nama track yang ditampilkan di player',
+    channels INT NOT NULL DEFAULT 2,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Track utama yang ikut di rendition video',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_movie_audio_tracks_movie_id (movie_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_audio_tracks;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE catalog_changes (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL COMMENT 'Tanpa foreign key, entri DELETED tetap ada setelah film dihapus',
+    change_type VARCHAR(32) NOT NULL COMMENT 'PUBLISHED, UPDATED, PRICE_CHANGED, VISIBILITY_CHANGED, AVAILABILITY_CHANGED, POSTER_UPDATED, TRAILER_UPDATED, DELETED',
+    attempts INT NOT NULL DEFAULT 0 COMMENT 'Jumlah purge cache/CDN yang gagal',
+    last_error VARCHAR(500) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP NULL COMMENT 'Diisi worker setelah purge berhasil',
+    INDEX idx_catalog_changes_pending (processed_at, id),
+    INDEX idx_catalog_changes_movie_id (movie_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS catalog_changes;
+-- +goose StatementEnd
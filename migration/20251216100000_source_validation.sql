@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    ADD COLUMN source_resolution VARCHAR(16) NULL COMMENT 'Resolusi file mentah dari ffprobe, contoh 1920x1080' AFTER complexity_factor,
+    ADD COLUMN source_video_codec VARCHAR(32) NULL COMMENT 'Codec video file mentah dari ffprobe' AFTER source_resolution,
+    ADD COLUMN source_duration_seconds INT NULL COMMENT 'Durasi sebenarnya, juga mengisi movies.duration_minutes' AFTER source_video_codec;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    DROP COLUMN source_resolution,
+    DROP COLUMN source_video_codec,
+    DROP COLUMN source_duration_seconds;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_preferences (
+    user_ext_id VARCHAR(255) PRIMARY KEY,
+    email_notifications BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'Notifikasi tiket dan akun lewat email, email reset password tetap dikirim',
+    in_app_notifications BOOLEAN NOT NULL DEFAULT TRUE,
+    marketing_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    language VARCHAR(35) NOT NULL DEFAULT 'en' COMMENT 'Tag bahasa BCP 47',
+    max_maturity_rating VARCHAR(10) NULL COMMENT 'G, PG, PG-13, R atau NC-17, NULL berarti tanpa batas',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_preferences;
+-- +goose StatementEnd
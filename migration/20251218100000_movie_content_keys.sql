@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movie_content_keys (
+    movie_id BIGINT PRIMARY KEY,
+    content_key VARBINARY(16) NOT NULL COMMENT 'Kunci AES-128 untuk segmen HLS, dipakai ulang di setiap versi output',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_content_keys;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    ADD COLUMN dash_manifest_url VARCHAR(255) NULL COMMENT 'Manifest DASH versi aktif, NULL jika hanya HLS' AFTER hls_playlist_url;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    DROP COLUMN dash_manifest_url;
+-- +goose StatementEnd
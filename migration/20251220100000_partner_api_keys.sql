@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE partner_api_keys (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL COMMENT 'Nama partner yang tampil di laporan pemakaian',
+    key_prefix VARCHAR(16) NOT NULL COMMENT 'Awal key untuk membedakan key tanpa menyimpan key asli',
+    key_hash CHAR(64) NOT NULL COMMENT 'SHA-256 dari key, key asli hanya ditampilkan sekali',
+    soft_request_quota BIGINT NULL COMMENT 'Kuota request per bulan, lewat batas hanya diberi peringatan',
+    hard_request_quota BIGINT NULL COMMENT 'Kuota request per bulan, lewat batas dijawab 429',
+    soft_byte_quota BIGINT NULL,
+    hard_byte_quota BIGINT NULL,
+    created_by_ext_id VARCHAR(255) NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_partner_api_keys_key_hash (key_hash)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS partner_api_keys;
+-- +goose StatementEnd
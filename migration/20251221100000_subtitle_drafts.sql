@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subtitle_drafts (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    language VARCHAR(16) NOT NULL COMMENT 'Bahasa yang diminta ke model, und jika dideteksi otomatis',
+    provider VARCHAR(64) NOT NULL COMMENT 'Layanan speech-to-text, misalnya whisper:small',
+    object_name VARCHAR(255) NOT NULL COMMENT 'File WebVTT di bucket raw, tidak disajikan ke player',
+    status VARCHAR(16) NOT NULL DEFAULT 'DRAFT' COMMENT 'DRAFT, APPROVED atau REJECTED',
+    reviewed_by_ext_id VARCHAR(255) NULL COMMENT 'Admin yang menyetujui atau menolak draft',
+    reviewed_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_subtitle_drafts_movie_status (movie_id, status)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subtitle_drafts;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movie_alternate_audio (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    language VARCHAR(16) NOT NULL COMMENT 'Tag bahasa BCP 47, misalnya en atau pt-BR',
+    name VARCHAR(100) NOT NULL COMMENT 'Nama track yang ditampilkan di player',
+    kind VARCHAR(24) NOT NULL COMMENT 'DUB atau AUDIO_DESCRIPTION',
+    source_object VARCHAR(255) NOT NULL COMMENT 'File audio yang diupload di bucket raw',
+    playlist_object VARCHAR(255) NULL COMMENT 'Playlist HLS audio di bucket processed, terisi setelah READY',
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING, PROCESSING, READY atau FAILED',
+    error_message TEXT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_movie_alternate_audio_movie_status (movie_id, status)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_alternate_audio;
+-- +goose StatementEnd
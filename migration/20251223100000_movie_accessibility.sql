@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Metadata aksesibilitas per film, diisi admin untuk kepatuhan regulasi aksesibilitas
+ALTER TABLE movies
+    ADD COLUMN closed_captions BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Tersedia closed caption',
+    ADD COLUMN audio_description BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Tersedia audio description',
+    ADD COLUMN flashing_content_warning BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Mengandung cahaya berkedip yang dapat memicu kejang fotosensitif',
+    ADD INDEX idx_movies_accessibility (closed_captions, audio_description);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP INDEX idx_movies_accessibility,
+    DROP COLUMN flashing_content_warning,
+    DROP COLUMN audio_description,
+    DROP COLUMN closed_captions;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movie_license_windows (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    region CHAR(2) NOT NULL COMMENT 'Kode negara ISO 3166-1 alpha-2, WW untuk seluruh dunia',
+    starts_at TIMESTAMP NOT NULL COMMENT 'Awal masa lisensi',
+    ends_at TIMESTAMP NOT NULL COMMENT 'Akhir masa lisensi (eksklusif), film tidak bisa dipesan lagi setelahnya',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_movie_license_windows_movie_region (movie_id, region, starts_at),
+    INDEX idx_movie_license_windows_ends_at (ends_at),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_license_windows;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN rental_device_limit INT NULL COMMENT 'Jumlah perangkat berbeda per sewa, NULL memakai batas paket user' AFTER payment_expiry_minutes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Perangkat yang sudah memutar satu akses sewa, dihitung terhadap batas perangkat
+CREATE TABLE access_devices (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    access_id BIGINT NOT NULL,
+    device_fingerprint VARCHAR(64) NOT NULL COMMENT 'Header X-Device-ID atau hash user agent',
+    user_agent VARCHAR(255) NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+
+    UNIQUE KEY idx_access_devices_access_device (access_id, device_fingerprint),
+    FOREIGN KEY (access_id) REFERENCES user_movie_access(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS access_devices;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN rental_device_limit;
+-- +goose StatementEnd
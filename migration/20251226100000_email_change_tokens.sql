@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE email_change_tokens (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_ext_id VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL COMMENT 'Alamat baru, baru dipakai setelah dikonfirmasi',
+    token_hash VARCHAR(255) NOT NULL UNIQUE COMMENT 'SHA256 dari token, token asli hanya dikirim ke alamat baru',
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Diisi saat token dipakai, token hanya berlaku sekali',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_email_change_user_ext_id (user_ext_id),
+    INDEX idx_email_change_expires_at (expires_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_change_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Soft delete dan flag publish, riwayat pembelian dan akses yang sudah dibayar tetap utuh
+ALTER TABLE movies
+    ADD COLUMN is_published BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'FALSE menyembunyikan film dari katalog dan menolak order baru' AFTER visibility,
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Diisi saat film dihapus, baris dan file tidak dihapus' AFTER updated_at,
+    ADD INDEX idx_movies_deleted_at (deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP INDEX idx_movies_deleted_at,
+    DROP COLUMN deleted_at,
+    DROP COLUMN is_published;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0 COMMENT 'Total pemutaran, dipakai untuk sort=views' AFTER rental_device_limit,
+    ADD INDEX idx_movies_view_count (view_count);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Jumlah pemutaran per film per hari (UTC), dihitung di Redis lalu di-flush oleh worker
+CREATE TABLE movie_daily_views (
+    movie_id BIGINT NOT NULL,
+    view_date DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (movie_id, view_date),
+    INDEX idx_movie_daily_views_date (view_date),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_daily_views;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP INDEX idx_movies_view_count,
+    DROP COLUMN view_count;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_refresh_tokens
+    ADD COLUMN last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Diperbarui setiap refresh, token yang lama tidak dipakai kedaluwarsa' AFTER expires_at,
+    ADD INDEX idx_last_used_at (last_used_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE user_refresh_tokens SET last_used_at = created_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_refresh_tokens
+    DROP INDEX idx_last_used_at,
+    DROP COLUMN last_used_at;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE settlement_reports (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    report_date DATE NOT NULL COMMENT 'Hari yang direkonsiliasi',
+    paid_orders INT NOT NULL COMMENT 'Order yang dibayar pada hari itu menurut database',
+    paid_amount DECIMAL(14,2) NOT NULL,
+    settled_orders INT NOT NULL COMMENT 'Order yang sudah settlement di payment gateway',
+    settled_amount DECIMAL(14,2) NOT NULL,
+    mismatches INT NOT NULL,
+    unchecked INT NOT NULL COMMENT 'Order dari gateway yang tidak bisa dicek',
+    status ENUM('BALANCED', 'MISMATCH') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_report_date (report_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE settlement_mismatches (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    report_id BIGINT NOT NULL,
+    order_id BIGINT NOT NULL,
+    payment_provider VARCHAR(32) NOT NULL,
+    kind ENUM('NOT_SETTLED', 'MISSING_LOCALLY', 'AMOUNT_MISMATCH') NOT NULL COMMENT 'Dibayar tapi belum settlement, settlement tapi tidak dibayar, atau nominal berbeda',
+    local_status VARCHAR(20) NOT NULL,
+    gateway_status VARCHAR(50) NOT NULL COMMENT 'Kosong jika transaksi tidak ada di gateway',
+    local_amount DECIMAL(10,2) NOT NULL,
+    gateway_amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_report_id (report_id),
+    INDEX idx_order_id (order_id),
+    CONSTRAINT fk_settlement_mismatches_report FOREIGN KEY (report_id) REFERENCES settlement_reports(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_orders_paid_at ON orders (paid_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_orders_paid_at ON orders;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS settlement_mismatches;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS settlement_reports;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('USER', 'ADMIN', 'CONTENT_MANAGER', 'TESTER') NOT NULL DEFAULT 'USER';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN beta_access BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Soft launch: hanya tester dan user di movie_beta_viewers yang bisa melihat' AFTER is_published;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE movie_beta_viewers (
+    movie_id BIGINT NOT NULL,
+    user_ext_id VARCHAR(100) NOT NULL,
+    added_by VARCHAR(100) NOT NULL COMMENT 'Staff yang menambahkan user ke allowlist',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (movie_id, user_ext_id),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_movie_beta_viewers_user (user_ext_id)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_beta_viewers;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies DROP COLUMN beta_access;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE users SET role = 'USER' WHERE role = 'TESTER';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('USER', 'ADMIN', 'CONTENT_MANAGER') NOT NULL DEFAULT 'USER';
+-- +goose StatementEnd
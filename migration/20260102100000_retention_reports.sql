@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE retention_reports (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    target VARCHAR(50) NOT NULL COMMENT 'Data yang diproses: orders, playback_telemetry, webhook_payloads',
+    action VARCHAR(20) NOT NULL COMMENT 'delete atau anonymize',
+    dry_run BOOLEAN NOT NULL COMMENT 'Dry run hanya menghitung baris, tidak mengubah data',
+    cutoff TIMESTAMP NOT NULL COMMENT 'Baris yang lebih lama dari ini sudah melewati masa retensi',
+    matched BIGINT NOT NULL,
+    affected BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error VARCHAR(500) NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    INDEX idx_retention_reports_target (target, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_stream_sessions_last_seen ON stream_sessions (last_seen_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_payment_notifications_processed ON payment_notifications (processed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_payment_notifications_processed ON payment_notifications;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX idx_stream_sessions_last_seen ON stream_sessions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS retention_reports;
+-- +goose StatementEnd
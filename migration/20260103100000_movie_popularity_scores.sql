@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN popularity_score DECIMAL(12,4) NOT NULL DEFAULT 0 COMMENT 'Dihitung ulang worker dari views, tontonan selesai, order dan skor review terbaru' AFTER view_count,
+    ADD INDEX idx_movies_popularity (popularity_score);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_watch_progress_updated ON watch_progress (updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_watch_progress_updated ON watch_progress;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP INDEX idx_movies_popularity,
+    DROP COLUMN popularity_score;
+-- +goose StatementEnd
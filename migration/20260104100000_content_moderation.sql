@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movie_comments
+    MODIFY COLUMN status ENUM('VISIBLE', 'HIDDEN', 'PENDING') NOT NULL DEFAULT 'VISIBLE',
+    ADD COLUMN moderation_reason VARCHAR(255) NULL COMMENT 'Alasan komentar ditahan oleh moderasi konten' AFTER status;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE media_reviews (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    kind VARCHAR(16) NOT NULL COMMENT 'poster atau trailer',
+    object_name VARCHAR(255) NOT NULL COMMENT 'File yang ditahan di bucket media',
+    reason VARCHAR(255) NOT NULL COMMENT 'Alasan dari layanan moderasi',
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING, APPROVED atau REJECTED',
+    reviewed_by_ext_id VARCHAR(255) NULL,
+    reviewed_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+    INDEX idx_media_reviews_status_created (status, created_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS media_reviews;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE movie_comments SET status = 'HIDDEN' WHERE status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movie_comments
+    DROP COLUMN moderation_reason,
+    MODIFY COLUMN status ENUM('VISIBLE', 'HIDDEN') NOT NULL DEFAULT 'VISIBLE';
+-- +goose StatementEnd
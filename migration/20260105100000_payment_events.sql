@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE payment_events (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    provider VARCHAR(32) NOT NULL,
+    order_id BIGINT NULL COMMENT 'Tanpa foreign key: webhook untuk order yang tidak dikenal juga disimpan',
+    transaction_status VARCHAR(50) NULL,
+    signature_valid BOOLEAN NOT NULL,
+    action VARCHAR(32) NOT NULL COMMENT 'Hasil pemrosesan, mis. PAID, DUPLICATE, INVALID_SIGNATURE',
+    error TEXT NULL,
+    payload MEDIUMTEXT NULL COMMENT 'Body webhook apa adanya, maksimal 64 KB',
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_payment_events_order (order_id, received_at),
+    INDEX idx_payment_events_received (received_at)
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payment_events;
+-- +goose StatementEnd
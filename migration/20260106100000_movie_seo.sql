@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movies
+    ADD COLUMN seo_title VARCHAR(255) NULL COMMENT 'Judul untuk mesin pencari, NULL = judul film',
+    ADD COLUMN meta_description VARCHAR(500) NULL COMMENT 'Deskripsi untuk mesin pencari, NULL = awal deskripsi film',
+    ADD COLUMN canonical_url VARCHAR(512) NULL COMMENT 'URL kanonis, NULL = halaman film di situs';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE movies
+    DROP COLUMN canonical_url,
+    DROP COLUMN meta_description,
+    DROP COLUMN seo_title;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE invoice_sequences (
+    year INT PRIMARY KEY,
+    last_number BIGINT NOT NULL COMMENT 'Nomor faktur terakhir yang diberikan pada tahun ini'
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN invoice_number VARCHAR(32) NULL COMMENT 'Nomor faktur berurutan tanpa celah, diberikan saat pembayaran berhasil' AFTER paid_at,
+    ADD UNIQUE INDEX idx_orders_invoice_number (invoice_number);
+-- +goose StatementEnd
+
+-- Pesanan yang sudah dibayar diberi nomor faktur menurut urutan pembayaran
+-- +goose StatementBegin
+UPDATE orders
+JOIN (
+    SELECT id, YEAR(paid_at) AS invoice_year,
+           ROW_NUMBER() OVER (PARTITION BY YEAR(paid_at) ORDER BY paid_at, id) AS invoice_seq
+    FROM orders
+    WHERE paid_at IS NOT NULL AND payment_status IN ('PAID', 'REFUNDED')
+) numbered ON numbered.id = orders.id
+SET orders.invoice_number = CONCAT('INV-', numbered.invoice_year, '-', LPAD(numbered.invoice_seq, 6, '0'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO invoice_sequences (year, last_number)
+SELECT YEAR(paid_at), COUNT(*) FROM orders WHERE invoice_number IS NOT NULL GROUP BY YEAR(paid_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP INDEX idx_orders_invoice_number,
+    DROP COLUMN invoice_number;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invoice_sequences;
+-- +goose StatementEnd
@@ -0,0 +1,72 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE access_grants (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    movie_id BIGINT NOT NULL,
+    campaign VARCHAR(100) NOT NULL COMMENT 'Nama kampanye, misalnya screener pers atau pemenang giveaway',
+    access_days INT NOT NULL COMMENT 'Lama akses gratis setelah tautan ditukarkan',
+    redeem_by TIMESTAMP NOT NULL COMMENT 'Batas waktu penukaran tautan',
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_access_grants_movie (movie_id),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE access_grant_recipients (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    grant_id BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    user_ext_id VARCHAR(50) NOT NULL,
+    token_hash CHAR(64) NOT NULL COMMENT 'Hash SHA256 dari token tautan penukaran',
+    status ENUM('INVITED', 'REDEEMED') NOT NULL DEFAULT 'INVITED',
+    new_account BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Akun dibuat oleh impor dan belum punya kata sandi',
+    redeemed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_access_grant_recipients_token (token_hash),
+    UNIQUE KEY uk_access_grant_recipients_user (grant_id, user_ext_id),
+    FOREIGN KEY (grant_id) REFERENCES access_grants(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- Akses dari kampanye tidak punya pesanan, akses tersebut menunjuk ke penerima kampanye
+-- +goose StatementBegin
+ALTER TABLE user_movie_access
+    MODIFY COLUMN order_id BIGINT NULL,
+    ADD COLUMN grant_recipient_id BIGINT NULL AFTER order_id,
+    ADD UNIQUE INDEX idx_user_movie_access_grant_recipient (grant_recipient_id),
+    ADD CONSTRAINT fk_user_movie_access_grant_recipient FOREIGN KEY (grant_recipient_id) REFERENCES access_grant_recipients(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN invited_at TIMESTAMP NULL COMMENT 'Akun dibuat oleh impor kampanye akses, bukan oleh pendaftaran';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN invited_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM user_movie_access WHERE grant_recipient_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE user_movie_access
+    DROP FOREIGN KEY fk_user_movie_access_grant_recipient,
+    DROP INDEX idx_user_movie_access_grant_recipient,
+    DROP COLUMN grant_recipient_id,
+    MODIFY COLUMN order_id BIGINT NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS access_grant_recipients;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS access_grants;
+-- +goose StatementEnd
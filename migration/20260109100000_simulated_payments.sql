@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN simulated_by VARCHAR(50) NULL COMMENT 'Admin yang mensimulasikan pembayaran di lingkungan development, NULL untuk pembayaran asli' AFTER invoice_number;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN simulated_by;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE invoices (
+    order_id BIGINT PRIMARY KEY,
+    invoice_number VARCHAR(32) NOT NULL,
+    object_name VARCHAR(255) NOT NULL COMMENT 'Kuitansi HTML di bucket media',
+    emailed_at TIMESTAMP NULL COMMENT 'Diisi setelah kuitansi dikirim lewat email',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_invoices_invoice_number (invoice_number),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invoices;
+-- +goose StatementEnd
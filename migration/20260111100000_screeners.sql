@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE screeners (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    movie_id BIGINT NOT NULL,
+    label VARCHAR(255) NOT NULL COMMENT 'Penerima tautan, mis. nama distributor',
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA256 dari token, token asli hanya ditampilkan sekali',
+    max_views INT NOT NULL,
+    views_used INT NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP NOT NULL,
+    valid_until TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    revoked_by VARCHAR(50) NULL,
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_screeners_token_hash (token_hash),
+    INDEX idx_screeners_movie_id (movie_id),
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE screener_views (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    screener_id BIGINT NOT NULL,
+    view_id VARCHAR(36) NOT NULL COMMENT 'Bagian dari URL pemutaran',
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL COMMENT 'Permintaan playlist atau segmen terakhir',
+
+    UNIQUE KEY uk_screener_views_view_id (view_id),
+    INDEX idx_screener_views_screener_id (screener_id),
+    FOREIGN KEY (screener_id) REFERENCES screeners(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS screener_views;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS screeners;
+-- +goose StatementEnd
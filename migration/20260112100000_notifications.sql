@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE movie_videos
+    ADD COLUMN uploaded_by VARCHAR(50) NULL COMMENT 'Admin yang mengunggah video, menerima notifikasi saat transcoding selesai';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE user_movie_access
+    ADD COLUMN expiry_reminded_at TIMESTAMP NULL COMMENT 'Waktu pengingat akses akan berakhir dikirim, NULL jika belum',
+    ADD INDEX idx_user_movie_access_expires_at (access_expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_movie_access
+    DROP INDEX idx_user_movie_access_expires_at,
+    DROP COLUMN expiry_reminded_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE movie_videos DROP COLUMN uploaded_by;
+-- +goose StatementEnd
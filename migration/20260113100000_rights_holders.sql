@@ -0,0 +1,74 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('USER', 'ADMIN', 'CONTENT_MANAGER', 'TESTER', 'RIGHTS_HOLDER') NOT NULL DEFAULT 'USER';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE rights_holders (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL COMMENT 'Studio atau distributor pemilik hak film',
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE rights_holder_members (
+    user_ext_id VARCHAR(100) NOT NULL PRIMARY KEY COMMENT 'Akun dengan role RIGHTS_HOLDER, satu akun hanya untuk satu pemilik hak',
+    rights_holder_id BIGINT NOT NULL,
+    added_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_rights_holder_members_holder (rights_holder_id),
+    FOREIGN KEY (rights_holder_id) REFERENCES rights_holders(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE rights_holder_titles (
+    movie_id BIGINT NOT NULL PRIMARY KEY COMMENT 'Satu film hanya dimiliki satu pemilik hak',
+    rights_holder_id BIGINT NOT NULL,
+    assigned_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_rights_holder_titles_holder (rights_holder_id),
+    FOREIGN KEY (rights_holder_id) REFERENCES rights_holders(id) ON DELETE CASCADE,
+    FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN region CHAR(2) NULL COMMENT 'Negara user saat memesan, untuk laporan per wilayah ke pemilik hak' AFTER simulated_by,
+    ADD INDEX idx_orders_movie_paid (movie_id, paid_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP INDEX idx_orders_movie_paid,
+    DROP COLUMN region;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rights_holder_titles;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rights_holder_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rights_holders;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE users SET role = 'USER' WHERE role = 'RIGHTS_HOLDER';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users
+    MODIFY COLUMN role ENUM('USER', 'ADMIN', 'CONTENT_MANAGER', 'TESTER') NOT NULL DEFAULT 'USER';
+-- +goose StatementEnd
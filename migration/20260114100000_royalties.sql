@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rights_holders
+    ADD COLUMN share_percent DECIMAL(5,2) NOT NULL DEFAULT 0 COMMENT 'Bagian pendapatan bersih untuk pemilik hak, dipakai film tanpa bagian sendiri' AFTER name;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE rights_holder_titles
+    ADD COLUMN share_percent DECIMAL(5,2) NULL COMMENT 'Bagian khusus film ini, NULL memakai bagian pemilik hak' AFTER rights_holder_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rights_holder_titles DROP COLUMN share_percent;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE rights_holders DROP COLUMN share_percent;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE backup_runs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `trigger` VARCHAR(20) NOT NULL COMMENT 'scheduled dari worker atau manual dari perintah --backup',
+    object VARCHAR(255) NULL COMMENT 'Key arsip terenkripsi di bucket backup, NULL jika gagal sebelum diunggah',
+    tables INT NOT NULL,
+    `rows` BIGINT NOT NULL,
+    objects BIGINT NOT NULL COMMENT 'Jumlah objek pada snapshot metadata bucket',
+    size_bytes BIGINT NOT NULL,
+    pruned INT NOT NULL COMMENT 'Arsip lama yang dihapus karena melewati masa retensi',
+    status VARCHAR(20) NOT NULL,
+    error VARCHAR(500) NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    INDEX idx_backup_runs_started (started_at),
+    INDEX idx_backup_runs_status (status, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS backup_runs;
+-- +goose StatementEnd
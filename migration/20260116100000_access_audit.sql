@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_movie_access
+    ADD COLUMN granted_by VARCHAR(50) NULL COMMENT 'Admin yang memberi akses gratis secara manual' AFTER grant_recipient_id,
+    ADD COLUMN revoked_at TIMESTAMP NULL COMMENT 'Diisi jika akses dicabut admin sebelum habis, access_expires_at ikut diisi waktu ini' AFTER access_expires_at,
+    ADD COLUMN revoked_by VARCHAR(50) NULL AFTER revoked_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE user_movie_access_audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    access_id BIGINT NOT NULL,
+    user_ext_id VARCHAR(100) NOT NULL,
+    movie_id BIGINT NOT NULL,
+    event VARCHAR(20) NOT NULL COMMENT 'granted atau revoked',
+    actor_ext_id VARCHAR(50) NOT NULL COMMENT 'Admin yang memberi atau mencabut akses',
+    reason VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_access_audit_logs_access (access_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_movie_access_audit_logs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Akses gratis dari admin tidak punya order maupun penerima kampanye
+DELETE FROM user_movie_access WHERE granted_by IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE user_movie_access
+    DROP COLUMN revoked_by,
+    DROP COLUMN revoked_at,
+    DROP COLUMN granted_by;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Worker belum pernah mengisi processed_at, video READY lama memakai waktu upload agar pembersihan sumber mentah berlaku
+UPDATE movie_videos SET processed_at = uploaded_at WHERE upload_status = 'READY' AND processed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Perbaikan data, tidak perlu dikembalikan
+-- +goose StatementEnd
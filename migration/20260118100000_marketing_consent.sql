@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_preferences
+    ADD COLUMN marketing_consented_at TIMESTAMP NULL COMMENT 'Waktu user menyetujui email marketing, tidak berubah saat preferensi lain diubah' AFTER marketing_opt_in;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Waktu persetujuan lama tidak tercatat, perubahan preferensi terakhir adalah perkiraan terbaik
+UPDATE user_preferences SET marketing_consented_at = updated_at, updated_at = updated_at WHERE marketing_opt_in = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_preferences DROP COLUMN marketing_consented_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE offline_licenses
+    ADD COLUMN access_id BIGINT NULL COMMENT 'Akses (order atau grant) yang mendasari lisensi, diperbarui saat renew' AFTER movie_id,
+    ADD INDEX idx_offline_licenses_access (access_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Lisensi lama dikaitkan ke akses terakhir yang diberikan sebelum lisensi diterbitkan
+UPDATE offline_licenses ol
+SET ol.access_id = (
+        SELECT a.id FROM user_movie_access a
+        WHERE a.user_ext_id = ol.user_ext_id
+          AND a.movie_id = ol.movie_id
+          AND a.access_granted_at <= ol.issued_at
+        ORDER BY a.access_granted_at DESC, a.id DESC
+        LIMIT 1
+    ),
+    ol.updated_at = ol.updated_at
+WHERE ol.access_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE offline_licenses
+    DROP INDEX idx_offline_licenses_access,
+    DROP COLUMN access_id;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE payment_events
+    ADD COLUMN payload_truncated BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'TRUE jika body webhook lebih dari 64 KB dan dipotong' AFTER payload;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Payload lama yang tersimpan tepat 64 KB berarti sudah terpotong
+UPDATE payment_events SET payload_truncated = TRUE WHERE LENGTH(payload) >= 65536;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payment_events DROP COLUMN payload_truncated;
+-- +goose StatementEnd
@@ -0,0 +1,95 @@
+package apperr
+
+import "errors"
+
+// Kind categorizes an error independently of the transport, see response.StatusCode for the HTTP mapping
+type Kind string
+
+const (
+	KindInternal     Kind = "internal"
+	KindValidation   Kind = "validation"
+	KindNotFound     Kind = "not_found"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindConflict     Kind = "conflict"
+	KindRateLimited  Kind = "rate_limited"
+	KindUnavailable  Kind = "unavailable" // a dependency is down, the request can be retried later
+)
+
+// Error is the error returned by usecases
+type Error struct {
+	Kind    Kind
+	Code    string      // machine readable, e.g. "movie_not_found"
+	Details interface{} // optional context for the client
+	Err     error       // cause of an internal error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors of the same kind and code, so a sentinel still matches after details were added
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// WithDetails returns a copy of the error carrying details
+func (e *Error) WithDetails(details interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+func New(kind Kind, code string, details interface{}) *Error {
+	return &Error{Kind: kind, Code: code, Details: details}
+}
+
+func Validation(code string, details interface{}) *Error {
+	return New(KindValidation, code, details)
+}
+
+func NotFound(code string, details interface{}) *Error {
+	return New(KindNotFound, code, details)
+}
+
+func Unauthorized(code string, details interface{}) *Error {
+	return New(KindUnauthorized, code, details)
+}
+
+func Forbidden(code string, details interface{}) *Error {
+	return New(KindForbidden, code, details)
+}
+
+func Conflict(code string, details interface{}) *Error {
+	return New(KindConflict, code, details)
+}
+
+func RateLimited(code string, details interface{}) *Error {
+	return New(KindRateLimited, code, details)
+}
+
+func Unavailable(code string, details interface{}) *Error {
+	return New(KindUnavailable, code, details)
+}
+
+// Internal wraps an unexpected error, e.g. from the database
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_server_error", Err: err}
+}
+
+// KindOf returns the kind of err, errors that are not an *Error are internal
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrNotSignedIn is returned by Refresh without a refresh token
+var ErrNotSignedIn = errors.New("cinestream: not signed in")
+
+// LoginRequest signs a user in. CaptchaToken is needed once the API answered captcha_required.
+type LoginRequest struct {
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	CaptchaToken string `json:"captcha_token,omitempty"`
+}
+
+// LoginResponse holds the tokens of a new session
+type LoginResponse struct {
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"`
+	User         UserProfile `json:"user"`
+}
+
+// UserProfile is the signed in user
+type UserProfile struct {
+	ExtID string `json:"ext_id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// Login signs in and keeps the tokens for the following calls
+// POST /api/v1/users/login
+func (c *Client) Login(ctx context.Context, payload LoginRequest) (*LoginResponse, error) {
+	var result LoginResponse
+	if _, err := c.send(ctx, request{method: http.MethodPost, path: "/users/login", body: payload}, &result); err != nil {
+		return nil, err
+	}
+	c.setTokens(result.Token, result.RefreshToken)
+	return &result, nil
+}
+
+// Refresh gets a new access token with the refresh token, authenticated calls do this on their own after a 401
+// POST /api/v1/users/refresh
+func (c *Client) Refresh(ctx context.Context) error {
+	_, refreshToken := c.Tokens()
+	if refreshToken == "" {
+		return ErrNotSignedIn
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	body := map[string]string{"refresh_token": refreshToken}
+	if _, err := c.send(ctx, request{method: http.MethodPost, path: "/users/refresh", body: body}, &result); err != nil {
+		return err
+	}
+	c.setTokens(result.AccessToken, "")
+	return nil
+}
+
+// Logout ends the session on the server and forgets the tokens
+// POST /api/v1/users/logout
+func (c *Client) Logout(ctx context.Context) error {
+	_, refreshToken := c.Tokens()
+	if refreshToken == "" {
+		return ErrNotSignedIn
+	}
+
+	body := map[string]string{"refresh_token": refreshToken}
+	if _, err := c.send(ctx, request{method: http.MethodPost, path: "/users/logout", body: body}, nil); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken, c.refreshToken = "", ""
+	return nil
+}
+
+// Me returns the signed in user
+// GET /api/v1/users/me
+func (c *Client) Me(ctx context.Context) (*UserProfile, error) {
+	var result UserProfile
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/me", auth: true}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,187 @@
+// Package client is a Go SDK for the CineStream API v1.
+// It signs in, refreshes the access token when it expired and wraps the catalog, order and streaming endpoints,
+// so internal tools and partners do not hand-roll HTTP calls.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client calls the CineStream API, it is safe for concurrent use
+type Client struct {
+	baseURL  string
+	http     *http.Client
+	apiKey   string
+	deviceID string
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client with a 30 second timeout
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithAPIKey sends the X-API-Key of a partner integration with every request
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) { c.apiKey = apiKey }
+}
+
+// WithDeviceID sends the X-Device-ID the stream sessions of the user are tracked by
+func WithDeviceID(deviceID string) Option {
+	return func(c *Client) { c.deviceID = deviceID }
+}
+
+// WithTokens resumes a session signed in before, e.g. with tokens kept from Login
+func WithTokens(accessToken, refreshToken string) Option {
+	return func(c *Client) {
+		c.accessToken = accessToken
+		c.refreshToken = refreshToken
+	}
+}
+
+// New creates a client for the API at baseURL, e.g. https://api.cinestream.id (without /api/v1)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/") + "/api/v1",
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Tokens returns the current access and refresh token, e.g. to store them after a refresh
+func (c *Client) Tokens() (accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accessToken, c.refreshToken
+}
+
+func (c *Client) setTokens(accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = accessToken
+	if refreshToken != "" {
+		c.refreshToken = refreshToken
+	}
+}
+
+// APIError is an error answer of the API
+type APIError struct {
+	StatusCode int             `json:"-"`
+	Code       string          `json:"message"` // machine readable, e.g. movie_not_found or captcha_required
+	Details    json.RawMessage `json:"errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if len(e.Details) > 0 && string(e.Details) != "null" {
+		return fmt.Sprintf("cinestream: %d %s: %s", e.StatusCode, e.Code, e.Details)
+	}
+	return fmt.Sprintf("cinestream: %d %s", e.StatusCode, e.Code)
+}
+
+// envelope is the JSON answer of every endpoint
+type envelope struct {
+	Status     string          `json:"status"`
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// request describes a call, auth requests send the access token and refresh it once on a 401
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	auth   bool
+}
+
+// do sends the request and decodes the data of the answer into out
+func (c *Client) do(ctx context.Context, req request, out interface{}) (*envelope, error) {
+	env, err := c.send(ctx, req, out)
+	var apiErr *APIError
+	if req.auth && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		if refreshErr := c.Refresh(ctx); refreshErr != nil {
+			return nil, err
+		}
+		return c.send(ctx, req, out)
+	}
+	return env, err
+}
+
+func (c *Client) send(ctx context.Context, req request, out interface{}) (*envelope, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		payload, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.deviceID != "" {
+		httpReq.Header.Set("X-Device-ID", c.deviceID)
+	}
+	if accessToken, _ := c.Tokens(); accessToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("cinestream: %s %s: %w", req.method, req.path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Code == "" {
+			apiErr.Code = http.StatusText(resp.StatusCode)
+		}
+		return nil, apiErr
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("cinestream: failed to decode %s %s: %w", req.method, req.path, err)
+	}
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return nil, fmt.Errorf("cinestream: failed to decode %s %s: %w", req.method, req.path, err)
+		}
+	}
+	return &env, nil
+}
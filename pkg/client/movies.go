@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Movie is a title of a catalog list
+type Movie struct {
+	ID              int64         `json:"id"`
+	Title           string        `json:"title"`
+	PosterURL       string        `json:"poster_url"`
+	Price           float64       `json:"price"`
+	PurchasePrice   *float64      `json:"purchase_price,omitempty"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Visibility      string        `json:"visibility"`
+	Available       bool          `json:"available"`
+	InWatchlist     *bool         `json:"in_watchlist,omitempty"` // only set when signed in
+	Views           int64         `json:"views,omitempty"`        // only set for popular movies
+	Accessibility   Accessibility `json:"accessibility"`
+}
+
+// MovieDetail is the detail page of a title
+type MovieDetail struct {
+	ID              int64         `json:"id"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	ReleaseDate     string        `json:"release_date"`
+	Director        string        `json:"director"`
+	DirectorID      *int64        `json:"director_id,omitempty"`
+	PosterURL       string        `json:"poster_url"`
+	TrailerURL      string        `json:"trailer_url"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Price           float64       `json:"price"`
+	PurchasePrice   *float64      `json:"purchase_price,omitempty"`
+	Visibility      string        `json:"visibility"`
+	Available       bool          `json:"available"`
+	InWatchlist     *bool         `json:"in_watchlist,omitempty"`
+	Genres          []string      `json:"genres,omitempty"`
+	Accessibility   Accessibility `json:"accessibility"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// Accessibility lists the accessibility features of a title
+type Accessibility struct {
+	ClosedCaptions         bool `json:"closed_captions"`
+	AudioDescription       bool `json:"audio_description"`
+	FlashingContentWarning bool `json:"flashing_content_warning"`
+}
+
+// Pagination describes the page of a list
+type Pagination struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	Limit       int   `json:"limit"`
+}
+
+// MovieQuery filters the catalog, zero values use the API defaults
+type MovieQuery struct {
+	Page          int
+	Limit         int
+	Genre         string   // genre name, e.g. action
+	Sort          string   // newest, title, price_asc, price_desc, duration or views
+	Accessibility []string // cc, ad, no_flashing
+}
+
+// ListMovies returns a page of the catalog, signed in users also get the in_watchlist flag
+// GET /api/v1/movies
+func (c *Client) ListMovies(ctx context.Context, query MovieQuery) ([]Movie, *Pagination, error) {
+	params := url.Values{}
+	if query.Page > 0 {
+		params.Set("page", strconv.Itoa(query.Page))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Genre != "" {
+		params.Set("genre", query.Genre)
+	}
+	if query.Sort != "" {
+		params.Set("sort", query.Sort)
+	}
+	if len(query.Accessibility) > 0 {
+		params.Set("accessibility", strings.Join(query.Accessibility, ","))
+	}
+
+	var movies []Movie
+	env, err := c.do(ctx, request{method: http.MethodGet, path: "/movies", query: params, auth: c.signedIn()}, &movies)
+	if err != nil {
+		return nil, nil, err
+	}
+	return movies, env.Pagination, nil
+}
+
+// GetMovie returns the detail of a published title
+// GET /api/v1/movies/:id
+func (c *Client) GetMovie(ctx context.Context, movieID int64) (*MovieDetail, error) {
+	var result MovieDetail
+	path := "/movies/" + strconv.FormatInt(movieID, 10)
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: path, auth: c.signedIn()}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// signedIn reports whether catalog calls should send and refresh the token
+func (c *Client) signedIn() bool {
+	_, refreshToken := c.Tokens()
+	return refreshToken != ""
+}
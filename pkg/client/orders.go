@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Order types
+const (
+	OrderRental   = "rental"
+	OrderPurchase = "purchase"
+)
+
+// CreateOrderRequest orders a title, Type defaults to a rental
+type CreateOrderRequest struct {
+	MovieID int64  `json:"movie_id"`
+	Type    string `json:"type,omitempty"`
+}
+
+// Order is a created order waiting for its payment at CheckoutURL
+type Order struct {
+	OrderID     int64     `json:"order_id"`
+	OrderType   string    `json:"order_type"`
+	CheckoutURL string    `json:"checkout_url"`
+	Amount      float64   `json:"amount"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ExpiresIn   int64     `json:"expires_in_seconds"`
+	Existing    bool      `json:"existing"` // the pending order of the title was returned instead of a new one
+	Message     string    `json:"message"`
+}
+
+// CreateOrder creates an order, the title can be streamed once it was paid.
+// A title the user already owns fails with 409 movie_already_owned.
+// POST /api/v1/orders
+func (c *Client) CreateOrder(ctx context.Context, payload CreateOrderRequest) (*Order, error) {
+	var result Order
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/orders", body: payload, auth: true}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeviceHints describe what the player can play, zero values send no hint
+type DeviceHints struct {
+	Codecs        []string // h264, hevc
+	MaxResolution int      // max frame height, e.g. 720
+	DRM           []string // widevine, fairplay
+}
+
+// Stream is the playback of a title the user has access to
+type Stream struct {
+	HLSURL          string          `json:"hls_url"`
+	ProxyURL        string          `json:"proxy_url"`
+	DASHURL         string          `json:"dash_manifest_url,omitempty"`
+	DASHProxyURL    string          `json:"dash_proxy_url,omitempty"`
+	AccessExpiresAt *time.Time      `json:"access_expires_at,omitempty"`
+	SessionID       string          `json:"session_id,omitempty"`
+	Subtitles       []SubtitleTrack `json:"subtitles,omitempty"`
+	AudioTracks     []AudioTrack    `json:"audio_tracks,omitempty"`
+	Message         string          `json:"message"`
+}
+
+// SubtitleTrack is a WebVTT subtitle of a stream
+type SubtitleTrack struct {
+	Language string `json:"language"`
+	Label    string `json:"label"`
+	Default  bool   `json:"default"`
+	URL      string `json:"url"`
+}
+
+// AudioTrack is an audio language of a stream
+type AudioTrack struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Default  bool   `json:"default"`
+	URL      string `json:"url,omitempty"`
+}
+
+// GetStreamURL starts a stream session of the device (see WithDeviceID) and returns the playlist URLs
+// GET /api/v1/movies/:id/stream
+func (c *Client) GetStreamURL(ctx context.Context, movieID int64, hints DeviceHints) (*Stream, error) {
+	query := url.Values{}
+	if len(hints.Codecs) > 0 {
+		query.Set("codecs", strings.Join(hints.Codecs, ","))
+	}
+	if hints.MaxResolution > 0 {
+		query.Set("max_resolution", strconv.Itoa(hints.MaxResolution))
+	}
+	if len(hints.DRM) > 0 {
+		query.Set("drm", strings.Join(hints.DRM, ","))
+	}
+
+	var result Stream
+	path := "/movies/" + strconv.FormatInt(movieID, 10) + "/stream"
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
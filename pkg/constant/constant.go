@@ -7,4 +7,10 @@ type ContextKey string
 const (
 	CtxKeyUserExtID ContextKey = "user_ext_id"
 	CtxKeyUserRole  ContextKey = "user_role"
+
+	// Set by the partner metering middleware for requests with an X-API-Key
+	CtxKeyPartnerKeyID ContextKey = "partner_key_id"
+
+	// Set by the region middleware, the client's country for licensing windows
+	CtxKeyRegion ContextKey = "region"
 )
@@ -0,0 +1,51 @@
+package constant
+
+// User roles stored in users.role
+const (
+	RoleUser           = "USER"
+	RoleAdmin          = "ADMIN"
+	RoleContentManager = "CONTENT_MANAGER"
+	RoleTester         = "TESTER"        // sees every soft-launched movie, no staff permissions
+	RoleRightsHolder   = "RIGHTS_HOLDER" // studio partner, only sees the analytics of its rights holder's titles
+)
+
+// Permission is an action a staff role may perform
+type Permission string
+
+// Permissions checked by the RequirePermission middleware
+const (
+	PermManageCatalog   Permission = "catalog:manage"   // movies, uploads, genres, people
+	PermManageEditorial Permission = "editorial:manage" // editorial reviews
+	PermModerateContent Permission = "content:moderate" // comments, reports, takedowns
+	PermManageOrders    Permission = "orders:manage"    // order list and refunds
+	PermManageSupport   Permission = "support:manage"   // help tickets
+	PermManageUsers     Permission = "users:manage"     // roles and account status
+	PermViewOps         Permission = "ops:view"         // security/ops summary
+	PermManageLicenses  Permission = "licenses:manage"  // offline license revocation
+	PermExportAudience  Permission = "audience:export"  // marketing audience with consent
+	PermManagePartners  Permission = "partners:manage"  // partner API keys, quotas and usage, rights holders
+	PermGrantAccess     Permission = "access:grant"     // complimentary access campaigns, screener links
+)
+
+// rolePermissions lists what each non-admin role may do, ADMIN may do everything
+var rolePermissions = map[string][]Permission{
+	RoleContentManager: {PermManageCatalog, PermManageEditorial},
+}
+
+// HasPermission reports whether the role grants the permission
+func HasPermission(role string, permission Permission) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin || role == RoleContentManager || role == RoleTester || role == RoleRightsHolder
+}
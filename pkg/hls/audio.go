@@ -0,0 +1,124 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	mediaTagPrefix = "#EXT-X-MEDIA:"
+	// audioGroup is the GROUP-ID used when the master playlist has no audio group yet
+	audioGroup = "audio"
+	// describesVideo marks audio descriptions, players pick them for viewers with the accessibility setting on
+	describesVideo = "public.accessibility.describes-video"
+)
+
+// AudioRendition is an #EXT-X-MEDIA audio entry
+type AudioRendition struct {
+	Language       string
+	Name           string
+	URI            string // audio media playlist relative to the master, empty for the audio muxed into the variants
+	Default        bool
+	DescribesVideo bool
+}
+
+// AddAudio adds alternate audio renditions to a master playlist.
+// A master without audio group gets one: main describes the audio muxed into the variants, nil when they
+// have none, and every variant references the group. NAME stays unique within the group.
+func AddAudio(master []byte, main *AudioRendition, tracks []AudioRendition) []byte {
+	if len(tracks) == 0 {
+		return master
+	}
+
+	group := ""
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(master))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, mediaTagPrefix) {
+			continue
+		}
+		attrs := parseAttributes(strings.TrimPrefix(line, mediaTagPrefix))
+		if attrs["TYPE"] != "AUDIO" {
+			continue
+		}
+		if group == "" {
+			group = attrs["GROUP-ID"]
+		}
+		if attrs["GROUP-ID"] == group {
+			names[attrs["NAME"]] = true
+		}
+	}
+
+	created := group == ""
+	var tags []string
+	if created {
+		group = audioGroup
+		if main != nil {
+			rendition := *main
+			rendition.URI = ""
+			rendition.Default = true
+			tags = append(tags, audioTag(group, rendition))
+			names[rendition.Name] = true
+		}
+	}
+	for i, track := range tracks {
+		// Without audio in the variants the first uploaded track plays by default
+		track.Default = created && main == nil && i == 0
+		name := track.Name
+		for n := 2; names[name]; n++ {
+			name = fmt.Sprintf("%s (%d)", track.Name, n)
+		}
+		track.Name = name
+		names[name] = true
+		tags = append(tags, audioTag(group, track))
+	}
+
+	var out bytes.Buffer
+	written := false
+
+	scanner = bufio.NewScanner(bytes.NewReader(master))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, streamInfTag) {
+			if !written {
+				for _, tag := range tags {
+					out.WriteString(tag + "\n")
+				}
+				written = true
+			}
+			if created && !strings.Contains(line, "AUDIO=") {
+				line += fmt.Sprintf(`,AUDIO="%s"`, group)
+			}
+		}
+
+		if line != "" {
+			out.WriteString(line + "\n")
+		}
+	}
+
+	return out.Bytes()
+}
+
+// audioTag renders the #EXT-X-MEDIA tag of an audio rendition
+func audioTag(group string, track AudioRendition) string {
+	attrs := fmt.Sprintf(`TYPE=AUDIO,GROUP-ID="%s",NAME="%s"`, group, strings.ReplaceAll(track.Name, `"`, "'"))
+	if track.Language != "" && track.Language != "und" {
+		attrs += fmt.Sprintf(`,LANGUAGE="%s"`, track.Language)
+	}
+	if track.Default {
+		attrs += ",DEFAULT=YES,AUTOSELECT=YES"
+	} else {
+		attrs += ",DEFAULT=NO,AUTOSELECT=YES"
+	}
+	if track.DescribesVideo {
+		attrs += fmt.Sprintf(`,CHARACTERISTICS="%s"`, describesVideo)
+	}
+	if track.URI != "" {
+		attrs += fmt.Sprintf(`,URI="%s"`, track.URI)
+	}
+	return mediaTagPrefix + attrs
+}
@@ -0,0 +1,109 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const streamInfTag = "#EXT-X-STREAM-INF:"
+
+// Variant is a single #EXT-X-STREAM-INF entry of a master playlist
+type Variant struct {
+	Bandwidth int
+	Width     int
+	Height    int
+	Codecs    string
+	URI       string
+}
+
+// VideoCodec returns the video codec family of the variant ("h264" or "hevc")
+// Playlists written before CODECS was emitted are always H.264
+func (v Variant) VideoCodec() string {
+	for _, codec := range strings.Split(v.Codecs, ",") {
+		codec = strings.TrimSpace(codec)
+		if strings.HasPrefix(codec, "hvc1") || strings.HasPrefix(codec, "hev1") {
+			return "hevc"
+		}
+	}
+	return "h264"
+}
+
+// FilterMaster rewrites a master playlist keeping only the variants accepted by keep
+// It returns the new playlist and the number of variants kept
+func FilterMaster(master []byte, keep func(Variant) bool) ([]byte, int) {
+	var out bytes.Buffer
+	kept := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(master))
+	var pendingInf string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, streamInfTag) {
+			pendingInf = line
+			continue
+		}
+
+		// The URI line following a stream info tag completes the variant
+		if pendingInf != "" && line != "" && !strings.HasPrefix(line, "#") {
+			variant := parseStreamInf(strings.TrimPrefix(pendingInf, streamInfTag))
+			variant.URI = line
+			if keep(variant) {
+				out.WriteString(pendingInf + "\n" + line + "\n")
+				kept++
+			}
+			pendingInf = ""
+			continue
+		}
+
+		if line != "" {
+			out.WriteString(line + "\n")
+		}
+	}
+
+	return out.Bytes(), kept
+}
+
+// parseStreamInf parses the attribute list of an #EXT-X-STREAM-INF tag
+func parseStreamInf(attrs string) Variant {
+	var variant Variant
+	for key, value := range parseAttributes(attrs) {
+		switch key {
+		case "BANDWIDTH":
+			variant.Bandwidth, _ = strconv.Atoi(value)
+		case "RESOLUTION":
+			if w, h, ok := strings.Cut(value, "x"); ok {
+				variant.Width, _ = strconv.Atoi(w)
+				variant.Height, _ = strconv.Atoi(h)
+			}
+		case "CODECS":
+			variant.Codecs = value
+		}
+	}
+	return variant
+}
+
+// parseAttributes splits KEY=VALUE pairs, honouring commas inside quoted values
+func parseAttributes(attrs string) map[string]string {
+	result := make(map[string]string)
+	inQuotes := false
+	start := 0
+	for i := 0; i <= len(attrs); i++ {
+		if i < len(attrs) {
+			if attrs[i] == '"' {
+				inQuotes = !inQuotes
+			}
+			if attrs[i] != ',' || inQuotes {
+				continue
+			}
+		}
+
+		if key, value, ok := strings.Cut(attrs[start:i], "="); ok {
+			result[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+		start = i + 1
+	}
+	return result
+}
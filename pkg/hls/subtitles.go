@@ -0,0 +1,83 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// subtitleGroup is the GROUP-ID of the subtitle renditions added to a master playlist
+const subtitleGroup = "subs"
+
+// SubtitleRendition is an #EXT-X-MEDIA subtitle entry
+type SubtitleRendition struct {
+	Language string
+	Name     string
+	URI      string // subtitle media playlist, relative to the master playlist
+	Default  bool
+}
+
+// AddSubtitles adds the subtitle renditions to a master playlist and references them from every variant
+func AddSubtitles(master []byte, tracks []SubtitleRendition) []byte {
+	if len(tracks) == 0 {
+		return master
+	}
+
+	var out bytes.Buffer
+	written := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(master))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, streamInfTag) {
+			if !written {
+				for _, track := range tracks {
+					out.WriteString(mediaTag(track) + "\n")
+				}
+				written = true
+			}
+			if !strings.Contains(line, "SUBTITLES=") {
+				line += fmt.Sprintf(`,SUBTITLES="%s"`, subtitleGroup)
+			}
+		}
+
+		if line != "" {
+			out.WriteString(line + "\n")
+		}
+	}
+
+	return out.Bytes()
+}
+
+// mediaTag renders the #EXT-X-MEDIA tag of a subtitle rendition
+func mediaTag(track SubtitleRendition) string {
+	isDefault := "NO"
+	if track.Default {
+		isDefault = "YES"
+	}
+	return fmt.Sprintf(`#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="%s",NAME="%s",LANGUAGE="%s",DEFAULT=%s,AUTOSELECT=YES,URI="%s"`,
+		subtitleGroup, strings.ReplaceAll(track.Name, `"`, "'"), track.Language, isDefault, track.URI)
+}
+
+// SubtitlePlaylist returns a media playlist serving a whole WebVTT file as a single segment
+func SubtitlePlaylist(vttURI string, duration time.Duration) []byte {
+	seconds := duration.Seconds()
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	var out bytes.Buffer
+	out.WriteString("#EXTM3U\n")
+	out.WriteString("#EXT-X-VERSION:3\n")
+	out.WriteString(fmt.Sprintf("#EXT-X-TARGETDURATION:%d\n", int(math.Ceil(seconds))))
+	out.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n")
+	out.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	out.WriteString(fmt.Sprintf("#EXTINF:%.3f,\n", seconds))
+	out.WriteString(vttURI + "\n")
+	out.WriteString("#EXT-X-ENDLIST\n")
+	return out.Bytes()
+}
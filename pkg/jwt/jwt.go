@@ -8,6 +8,7 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/middleware"
 	"github.com/martinmanurung/cinestream/pkg/response"
 )
 
@@ -75,7 +76,7 @@ func (j *JWTService) ValidateToken(tokenStr string) (*MyClaims, error) {
 }
 
 func (j *JWTService) JWTMiddleware() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
+	return middleware.Describe(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			token := c.Request().Header.Get(echo.HeaderAuthorization)
 			if token == "" {
@@ -91,7 +92,59 @@ func (j *JWTService) JWTMiddleware() echo.MiddlewareFunc {
 			c.Set(string(constant.CtxKeyUserRole), claims.Role)
 			return next(c)
 		}
-	}
+	}, requireToken)
+}
+
+// OptionalJWTMiddleware sets the user claims when a valid token is sent but lets anonymous requests through
+func (j *JWTService) OptionalJWTMiddleware() echo.MiddlewareFunc {
+	return middleware.Describe(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.Request().Header.Get(echo.HeaderAuthorization)
+			if token == "" {
+				return next(c)
+			}
+
+			if claims, err := j.ValidateToken(token); err == nil {
+				c.Set(string(constant.CtxKeyUserExtID), claims.UserExtID)
+				c.Set(string(constant.CtxKeyUserRole), claims.Role)
+			}
+			return next(c)
+		}
+	}, func(a *middleware.Access) {
+		if a.Auth == middleware.AuthNone {
+			a.Auth = middleware.AuthOptionalJWT
+		}
+	})
+}
+
+// WebSocketJWTMiddleware accepts the token from the Authorization header or the "token"
+// query param, since browsers cannot set headers on WebSocket handshakes
+func (j *JWTService) WebSocketJWTMiddleware() echo.MiddlewareFunc {
+	return middleware.Describe(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.Request().Header.Get(echo.HeaderAuthorization)
+			if token == "" {
+				token = c.QueryParam("token")
+			}
+			if token == "" {
+				return response.Error(c, 401, "unauthorized", "missing authorization token")
+			}
+
+			claims, err := j.ValidateToken(token)
+			if err != nil {
+				return response.Error(c, 401, "unauthorized", err.Error())
+			}
+
+			c.Set(string(constant.CtxKeyUserExtID), claims.UserExtID)
+			c.Set(string(constant.CtxKeyUserRole), claims.Role)
+			return next(c)
+		}
+	}, requireToken)
+}
+
+// requireToken describes the middleware that reject requests without a valid token
+func requireToken(a *middleware.Access) {
+	a.Auth = middleware.AuthJWT
 }
 
 // GetUserExtIDFromContext extracts user_ext_id from echo context
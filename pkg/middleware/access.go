@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+)
+
+// Authentication a route requires, see Access.Auth
+const (
+	AuthNone        = "none"
+	AuthJWT         = "jwt"          // Bearer token in the Authorization header
+	AuthOptionalJWT = "optional_jwt" // anonymous requests pass, a valid token adds the user
+)
+
+// ctxKeyDescribe holds the *Access a route is described into, set only by DescribeRoute
+const ctxKeyDescribe = "describe_route_access"
+
+// Access is what a route requires of the caller
+type Access struct {
+	Auth        string   `json:"auth"`
+	Roles       []string `json:"roles,omitempty"`       // one of them is required
+	Permissions []string `json:"permissions,omitempty"` // all of them are required
+}
+
+// Allows reports whether a caller with the role may call the route, an empty role is an anonymous caller
+func (a Access) Allows(role string) bool {
+	if a.Auth == AuthJWT && role == "" {
+		return false
+	}
+	if len(a.Roles) > 0 {
+		allowed := false
+		for _, r := range a.Roles {
+			allowed = allowed || r == role
+		}
+		if !allowed {
+			return false
+		}
+	}
+	for _, permission := range a.Permissions {
+		if !constant.HasPermission(role, constant.Permission(permission)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe marks a middleware with what it requires, so the route listing can show it.
+// While a route is described the middleware only records its requirement and runs nothing.
+func Describe(mw echo.MiddlewareFunc, describe func(*Access)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		h := mw(next)
+		return func(c echo.Context) error {
+			if access, ok := c.Get(ctxKeyDescribe).(*Access); ok {
+				describe(access)
+				return nil
+			}
+			return h(c)
+		}
+	}
+}
+
+// DescribeRoute collects the requirements of the middleware of a route, e.g. from Echo.OnAddRouteHandler.
+// Middleware without a description run against an empty GET request and are expected to let it through.
+func DescribeRoute(e *echo.Echo, middleware []echo.MiddlewareFunc) Access {
+	access := Access{Auth: AuthNone}
+	for _, mw := range middleware {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		c.Set(ctxKeyDescribe, &access)
+		_ = mw(func(echo.Context) error { return nil })(c)
+	}
+	sort.Strings(access.Permissions)
+	return access
+}
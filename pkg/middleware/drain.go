@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Drain lets a shutting down instance fail its readiness probe while in-flight requests,
+// such as admin uploads that take minutes, are allowed to finish
+type Drain struct {
+	draining atomic.Bool
+	inFlight atomic.Int64
+}
+
+// NewDrain returns a Drain in the ready state
+func NewDrain() *Drain {
+	return &Drain{}
+}
+
+// Middleware counts the requests that are being served
+func (d *Drain) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			d.inFlight.Add(1)
+			defer d.inFlight.Add(-1)
+			return next(c)
+		}
+	}
+}
+
+// Start marks the instance as draining, the readiness probe fails from then on
+func (d *Drain) Start() {
+	d.draining.Store(true)
+}
+
+// InFlight returns the number of requests being served
+func (d *Drain) InFlight() int64 {
+	return d.inFlight.Load()
+}
+
+// ReadyGate wraps the readiness probe, it returns 503 once draining started so the
+// load balancer stops routing new requests while the liveness probe (/healthz) keeps passing
+func (d *Drain) ReadyGate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if d.draining.Load() {
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{
+				"status": "draining",
+			})
+		}
+		return next(c)
+	}
+}
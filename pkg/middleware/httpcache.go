@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+)
+
+// HTTPCache lets browsers and CDNs revalidate public catalog responses instead of refetching them.
+// Successful GET responses get an ETag hashed from the body, so it changes with anything the response
+// shows, e.g. a movie's updated_at, and a request whose If-None-Match matches gets a 304 without body.
+// Anonymous responses may be shared for maxAge, responses of signed-in users carry per-user flags
+// such as in_watchlist, they are private and revalidated on every use.
+func HTTPCache(maxAge time.Duration) echo.MiddlewareFunc {
+	publicControl := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			res := c.Response()
+			writer := res.Writer
+			recorder := &bodyRecorder{ResponseWriter: writer, status: http.StatusOK}
+			res.Writer = recorder
+			err := next(c)
+			res.Writer = writer
+			if err != nil {
+				// Nothing was written, the error handler writes the response
+				return err
+			}
+			if !res.Committed {
+				return nil
+			}
+
+			if recorder.status != http.StatusOK {
+				return recorder.flush()
+			}
+
+			header := res.Header()
+			sum := sha256.Sum256(recorder.body.Bytes())
+			// Weak, the Gzip middleware encodes the body after it was hashed
+			etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+			header.Set("ETag", etag)
+			header.Add("Vary", echo.HeaderAuthorization)
+			if c.Get(string(constant.CtxKeyUserExtID)) != nil {
+				header.Set("Cache-Control", "private, no-cache")
+			} else {
+				header.Set("Cache-Control", publicControl)
+			}
+
+			if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+				header.Del(echo.HeaderContentType)
+				header.Del(echo.HeaderContentLength)
+				recorder.status = http.StatusNotModified
+				recorder.body.Reset()
+			}
+			return recorder.flush()
+		}
+	}
+}
+
+// etagMatches compares the If-None-Match list with etag the weak way, ignoring the W/ prefix
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyRecorder holds back the status and body until the ETag is known, headers go to the real writer
+type bodyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *bodyRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *bodyRecorder) flush() error {
+	r.ResponseWriter.WriteHeader(r.status)
+	if r.body.Len() == 0 {
+		return nil
+	}
+	_, err := r.ResponseWriter.Write(r.body.Bytes())
+	return err
+}
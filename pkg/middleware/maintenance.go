@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
+)
+
+// Maintenance answers requests with 503 while maintenance mode is on, e.g. during a database migration.
+// It can be switched at runtime, e.g. by a config reload.
+type Maintenance struct {
+	state atomic.Pointer[maintenanceState]
+}
+
+type maintenanceState struct {
+	enabled    bool
+	message    string
+	retryAfter time.Duration
+}
+
+// NewMaintenance returns a Maintenance that lets every request through
+func NewMaintenance() *Maintenance {
+	m := &Maintenance{}
+	m.Set(false, "", 0)
+	return m
+}
+
+// Set switches maintenance mode, message is shown to clients and retryAfter sent as Retry-After when positive
+func (m *Maintenance) Set(enabled bool, message string, retryAfter time.Duration) {
+	m.state.Store(&maintenanceState{enabled: enabled, message: message, retryAfter: retryAfter})
+}
+
+// Enabled reports whether maintenance mode is on
+func (m *Maintenance) Enabled() bool {
+	return m.state.Load().enabled
+}
+
+// Middleware rejects requests during maintenance, paths under allowPrefixes still pass,
+// so staff can sign in and use the admin API and payment gateways can deliver webhooks
+func (m *Maintenance) Middleware(allowPrefixes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			state := m.state.Load()
+			if !state.enabled || hasAnyPrefix(c.Request().URL.Path, allowPrefixes) {
+				return next(c)
+			}
+			if state.retryAfter > 0 {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(state.retryAfter.Seconds())))
+			}
+			message := strings.TrimSpace(state.message)
+			if message == "" {
+				message = "CineStream is down for maintenance, please try again later"
+			}
+			return apperr.Unavailable("maintenance", message)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorRecorder records error responses for the admin ops summary
+type ErrorRecorder interface {
+	RecordHTTPError(ctx context.Context, method, route string, status int)
+	RecordAuthFailure(ctx context.Context, ip string)
+}
+
+// ErrorMetrics counts 4xx/5xx responses per route and 401 responses per client IP.
+// Routes under skipAuthPrefixes (e.g. payment webhooks) record their own auth failures.
+func ErrorMetrics(recorder ErrorRecorder, skipAuthPrefixes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := next(c); err != nil {
+				// Let the error handler write the response so the final status is known
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status < http.StatusBadRequest {
+				return nil
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			ctx := context.Background()
+			recorder.RecordHTTPError(ctx, c.Request().Method, route, status)
+
+			if status == http.StatusUnauthorized && !hasAnyPrefix(route, skipAuthPrefixes) {
+				recorder.RecordAuthFailure(ctx, c.RealIP())
+			}
+
+			return nil
+		}
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
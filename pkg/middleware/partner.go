@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// HeaderAPIKey carries the key of partner integrations
+const HeaderAPIKey = "X-API-Key"
+
+// PartnerMeter authenticates partner API keys and meters their traffic
+type PartnerMeter interface {
+	// CheckPartnerKey returns the key id and whether its soft or hard monthly quota is used up
+	CheckPartnerKey(ctx context.Context, apiKey string) (keyID int64, softExceeded, hardExceeded bool, err error)
+	RecordPartnerUsage(ctx context.Context, keyID int64, bytes int64)
+}
+
+// PartnerMetering meters requests that carry an X-API-Key, other requests pass through untouched.
+// A used up hard quota answers 429, a soft quota only adds the X-Quota-Warning header.
+func PartnerMetering(meter PartnerMeter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey := c.Request().Header.Get(HeaderAPIKey)
+			if apiKey == "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			keyID, softExceeded, hardExceeded, err := meter.CheckPartnerKey(ctx, apiKey)
+			if err != nil {
+				return response.HandleError(c, err)
+			}
+			if hardExceeded {
+				return response.Error(c, http.StatusTooManyRequests, "partner_quota_exceeded", "monthly quota used up, contact us to raise it")
+			}
+			if softExceeded {
+				c.Response().Header().Set("X-Quota-Warning", "soft monthly quota exceeded")
+			}
+
+			c.Set(string(constant.CtxKeyPartnerKeyID), keyID)
+			if err := next(c); err != nil {
+				// Let the error handler write the response so its size is metered
+				c.Error(err)
+			}
+
+			// Streamed responses may outlive a cancelled request context
+			meter.RecordPartnerUsage(context.WithoutCancel(ctx), keyID, c.Response().Size)
+			return nil
+		}
+	}
+}
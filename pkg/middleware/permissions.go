@@ -8,9 +8,9 @@ import (
 	"github.com/martinmanurung/cinestream/pkg/response"
 )
 
-// AdminOnly middleware checks if the user has ADMIN role
-func AdminOnly() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
+// RequirePermission middleware checks if the user's role grants the permission
+func RequirePermission(permission constant.Permission) echo.MiddlewareFunc {
+	return Describe(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Get user role from context (set by JWT middleware)
 			role := c.Get(string(constant.CtxKeyUserRole))
@@ -19,12 +19,14 @@ func AdminOnly() echo.MiddlewareFunc {
 				return response.Error(c, http.StatusUnauthorized, "unauthorized", "missing role information")
 			}
 
-			userRole, ok := role.(string)
-			if !ok || userRole != "ADMIN" {
-				return response.Error(c, http.StatusForbidden, "forbidden", "admin access required")
+			userRole, _ := role.(string)
+			if !constant.HasPermission(userRole, permission) {
+				return response.Error(c, http.StatusForbidden, "forbidden", "missing permission "+string(permission))
 			}
 
 			return next(c)
 		}
-	}
+	}, func(a *Access) {
+		a.Permissions = append(a.Permissions, string(permission))
+	})
 }
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+)
+
+// Region stores the client's country (ISO 3166-1 alpha-2) read from header, set by the CDN in front of the API.
+// The header is only honored on connections from trustedProxies, clients reaching the API directly could pick
+// any country. Requests without a valid code get fallback, an empty fallback leaves the region unset.
+func Region(header, fallback string, trustedProxies []*net.IPNet) echo.MiddlewareFunc {
+	fallback = strings.ToUpper(fallback)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			region := fallback
+			if header != "" && fromTrustedProxy(c.Request().RemoteAddr, trustedProxies) {
+				if value := strings.ToUpper(strings.TrimSpace(c.Request().Header.Get(header))); isRegionCode(value) {
+					region = value
+				}
+			}
+			if region != "" {
+				c.Set(string(constant.CtxKeyRegion), region)
+			}
+			return next(c)
+		}
+	}
+}
+
+// fromTrustedProxy reports whether the peer of the connection is in one of the trusted ranges
+func fromTrustedProxy(remoteAddr string, trustedProxies []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipRange := range trustedProxies {
+		if ipRange.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRegionCode reports whether value is two letters, CDNs send XX or T1 for unknown countries and Tor
+func isRegionCode(value string) bool {
+	if len(value) != 2 || value == "XX" {
+		return false
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/tracing"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -19,9 +20,16 @@ func RequestID() echo.MiddlewareFunc {
 			// Set request ID to response header
 			c.Response().Header().Set("X-Request-Id", requestID)
 
+			// Continue the caller's W3C trace (or start one) and keep it in the request context
+			// so outbound calls and queued jobs can pass it on
+			trace := tracing.FromHeaders(c.Request().Header)
+			c.Response().Header().Set(tracing.HeaderTraceParent, trace.TraceParent)
+			c.SetRequest(c.Request().WithContext(tracing.NewContext(c.Request().Context(), trace)))
+
 			// Create logger with request_id and store in context
 			logger := log.With().
 				Str("request_id", requestID).
+				Str("trace_id", trace.TraceID()).
 				Logger()
 
 			// Store logger in context for handlers to use
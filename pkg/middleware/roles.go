@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/constant"
+	"github.com/martinmanurung/cinestream/pkg/response"
+)
+
+// RequireRoles middleware checks if the user has one of the given roles
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return Describe(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role := c.Get(string(constant.CtxKeyUserRole))
+
+			if role == nil {
+				return response.Error(c, http.StatusUnauthorized, "unauthorized", "missing role information")
+			}
+
+			userRole, _ := role.(string)
+			for _, allowed := range roles {
+				if userRole == allowed {
+					return next(c)
+				}
+			}
+
+			return response.Error(c, http.StatusForbidden, "forbidden", "insufficient role")
+		}
+	}, func(a *Access) {
+		a.Roles = append(a.Roles, roles...)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/martinmanurung/cinestream/pkg/apperr"
 )
 
 type SuccessResponse struct {
@@ -39,22 +40,36 @@ func Error(c echo.Context, code int, message string, errDetails interface{}) err
 	})
 }
 
-type APIError struct {
-	Code    int
-	Message string
-	Details interface{}
-}
-
-func (e *APIError) Error() string {
-	return e.Message
+// StatusCode maps an error kind to its HTTP status.
+// This is the only place where usecase errors become status codes.
+func StatusCode(kind apperr.Kind) int {
+	switch kind {
+	case apperr.KindValidation:
+		return http.StatusBadRequest
+	case apperr.KindNotFound:
+		return http.StatusNotFound
+	case apperr.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperr.KindForbidden:
+		return http.StatusForbidden
+	case apperr.KindConflict:
+		return http.StatusConflict
+	case apperr.KindRateLimited:
+		return http.StatusTooManyRequests
+	case apperr.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
-func NewError(code int, message string, details interface{}) *APIError {
-	return &APIError{
-		Code:    code,
-		Message: message,
-		Details: details,
+// HandleError writes the error response for an error returned by a usecase
+func HandleError(c echo.Context, err error) error {
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) && appErr.Kind != apperr.KindInternal {
+		return Error(c, StatusCode(appErr.Kind), appErr.Code, appErr.Details)
 	}
+	return Error(c, http.StatusInternalServerError, "internal_server_error", err.Error())
 }
 
 func CustomErrorHandler(err error, c echo.Context) {
@@ -62,9 +77,9 @@ func CustomErrorHandler(err error, c echo.Context) {
 		return
 	}
 
-	var apiErr *APIError
-	if errors.As(err, &apiErr) {
-		Error(c, apiErr.Code, apiErr.Message, apiErr.Details)
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		HandleError(c, err)
 		return
 	}
 
@@ -82,11 +97,3 @@ func CustomErrorHandler(err error, c echo.Context) {
 	c.Logger().Error(err)
 	Error(c, http.StatusInternalServerError, "Internal Server Error", nil)
 }
-
-func InternalServerError(err error) error {
-	return &APIError{
-		Code:    http.StatusInternalServerError,
-		Message: "internal_server_error",
-		Details: err,
-	}
-}
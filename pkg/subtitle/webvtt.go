@@ -0,0 +1,129 @@
+package subtitle
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported upload formats
+const (
+	FormatSRT    = "srt"
+	FormatWebVTT = "vtt"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported subtitle format, use .srt or .vtt")
+	ErrNoCues            = errors.New("subtitle file contains no cues")
+)
+
+const cueArrow = "-->"
+
+// FormatFromFileName returns the subtitle format from the file extension
+func FormatFromFileName(fileName string) (string, error) {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), ".")) {
+	case FormatSRT:
+		return FormatSRT, nil
+	case FormatWebVTT:
+		return FormatWebVTT, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ToWebVTT converts an SRT or WebVTT file to WebVTT and returns the end time of the last cue
+// SRT timestamps use a comma before the milliseconds, WebVTT a dot, everything else is kept
+func ToWebVTT(data []byte, format string) ([]byte, time.Duration, error) {
+	if format != FormatSRT && format != FormatWebVTT {
+		return nil, 0, ErrUnsupportedFormat
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	var out bytes.Buffer
+	var end time.Duration
+	cues := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if first {
+			first = false
+			if format == FormatWebVTT {
+				if !strings.HasPrefix(line, "WEBVTT") {
+					return nil, 0, fmt.Errorf("invalid WebVTT file: missing WEBVTT header")
+				}
+				out.WriteString(line + "\n")
+				continue
+			}
+			out.WriteString("WEBVTT\n\n")
+		}
+
+		if strings.Contains(line, cueArrow) {
+			if format == FormatSRT {
+				line = strings.ReplaceAll(line, ",", ".")
+			}
+			cueEnd, err := parseCueEnd(line)
+			if err != nil {
+				return nil, 0, err
+			}
+			if cueEnd > end {
+				end = cueEnd
+			}
+			cues++
+		}
+
+		out.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read subtitle file: %w", err)
+	}
+	if cues == 0 {
+		return nil, 0, ErrNoCues
+	}
+
+	return out.Bytes(), end, nil
+}
+
+// parseCueEnd returns the end timestamp of a "start --> end [settings]" line
+func parseCueEnd(line string) (time.Duration, error) {
+	_, after, _ := strings.Cut(line, cueArrow)
+	fields := strings.Fields(after)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid cue timing %q", line)
+	}
+	return parseTimestamp(fields[0])
+}
+
+// parseTimestamp parses "hh:mm:ss.ttt" or "mm:ss.ttt"
+func parseTimestamp(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+	total := time.Duration(seconds * float64(time.Second))
+
+	units := []time.Duration{time.Minute, time.Hour}
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid cue timestamp %q", value)
+		}
+		total += time.Duration(n) * units[len(parts)-2-i]
+	}
+	return total, nil
+}
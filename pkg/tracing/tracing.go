@@ -0,0 +1,169 @@
+// Package tracing carries W3C Trace Context (traceparent/tracestate) and X-Request-Id
+// across HTTP requests, outbound calls and queue jobs so CineStream joins the caller's trace.
+// It does not record spans itself, it only propagates the identifiers.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+	HeaderRequestID   = "X-Request-Id"
+)
+
+type ctxKey struct{}
+
+// Context is the propagated trace state of a request or job
+type Context struct {
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
+}
+
+// TraceID returns the trace-id part of the traceparent
+func (t Context) TraceID() string {
+	traceID, _, _, ok := parse(t.TraceParent)
+	if !ok {
+		return ""
+	}
+	return traceID
+}
+
+// Child returns the context for the next hop: same trace, new parent span
+func (t Context) Child() Context {
+	t.TraceParent = ChildOf(t.TraceParent)
+	return t
+}
+
+// String formats the identifiers for log lines
+func (t Context) String() string {
+	return fmt.Sprintf("trace_id=%s request_id=%s", t.TraceID(), t.RequestID)
+}
+
+// FromHeaders reads the trace state of an incoming request
+// A missing or malformed traceparent starts a new trace, a valid one is continued with a new span
+func FromHeaders(h http.Header) Context {
+	t := Context{
+		TraceParent: ChildOf(h.Get(HeaderTraceParent)),
+		RequestID:   h.Get(HeaderRequestID),
+	}
+	// tracestate is only meaningful together with the traceparent it was sent with
+	if _, _, _, ok := parse(h.Get(HeaderTraceParent)); ok {
+		t.TraceState = h.Get(HeaderTraceState)
+	}
+	return t
+}
+
+// Inject writes the trace state of ctx to outgoing headers, as a child of the current span
+func Inject(ctx context.Context, h http.Header) {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	child := t.Child()
+	h.Set(HeaderTraceParent, child.TraceParent)
+	if child.TraceState != "" {
+		h.Set(HeaderTraceState, child.TraceState)
+	}
+	if child.RequestID != "" {
+		h.Set(HeaderRequestID, child.RequestID)
+	}
+}
+
+// NewContext stores the trace state in ctx
+func NewContext(ctx context.Context, t Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+// FromContext returns the trace state stored in ctx
+func FromContext(ctx context.Context) (Context, bool) {
+	if ctx == nil {
+		return Context{}, false
+	}
+	t, ok := ctx.Value(ctxKey{}).(Context)
+	return t, ok
+}
+
+// WithRequest copies the trace state of an incoming request into ctx
+// Handlers work with a long-lived context rather than the request context, this keeps the trace
+func WithRequest(ctx context.Context, r *http.Request) context.Context {
+	if t, ok := FromContext(r.Context()); ok {
+		return NewContext(ctx, t)
+	}
+	return ctx
+}
+
+// ChildOf returns a traceparent continuing the given trace with a new span id
+// When traceparent is empty or invalid a new trace is started
+func ChildOf(traceparent string) string {
+	traceID, _, flags, ok := parse(traceparent)
+	if !ok {
+		return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+	}
+	return "00-" + traceID + "-" + randomHex(8) + "-" + flags
+}
+
+// NewTransport wraps base so every request carries the trace headers of ctx
+// It is used for clients that do not pass a request context through, like the Midtrans SDK
+func NewTransport(ctx context.Context, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{ctx: ctx, base: base}
+}
+
+type transport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	Inject(t.ctx, req.Header)
+	return t.base.RoundTrip(req)
+}
+
+// parse validates a version 00 traceparent: 00-<32 hex trace-id>-<16 hex parent-id>-<2 hex flags>
+func parse(traceparent string) (traceID, parentID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return "", "", "", false
+	}
+	traceID, parentID, flags = parts[1], parts[2], parts[3]
+
+	if !isLowerHex(traceID, 32) || !isLowerHex(parentID, 16) || !isLowerHex(flags, 2) {
+		return "", "", "", false
+	}
+	// All-zero ids are invalid per the spec
+	if strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return "", "", "", false
+	}
+	return traceID, parentID, flags, true
+}
+
+func isLowerHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}